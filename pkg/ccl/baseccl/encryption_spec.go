@@ -12,6 +12,7 @@ import (
 	"bytes"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -25,9 +26,31 @@ import (
 // DefaultRotationPeriod is the rotation period used if not specified.
 const DefaultRotationPeriod = time.Hour * 24 * 7 // 1 week, give or take time changes.
 
+// DefaultKMSTimeout is how long to keep retrying to reach the KMS if a store
+// key is given as a KMS URI and kms-timeout is not specified.
+const DefaultKMSTimeout = time.Minute
+
+// DefaultKMSCipher is the cipher of the store keys generated for KMS master
+// keys if cipher is not specified.
+const DefaultKMSCipher = "aes-256-ctr"
+
+// KMSCiphers are the valid values of the cipher field, i.e. the ciphers of
+// the store keys that can be generated for KMS master keys. The AES-CTR
+// ciphers use the current version of AES-CTR encryption, while the AES-GCM
+// ciphers provide authenticated encryption.
+var KMSCiphers = []string{
+	"aes-128-ctr", "aes-192-ctr", "aes-256-ctr", "aes-128-gcm", "aes-192-gcm", "aes-256-gcm",
+}
+
 // Special value of key paths to mean "no encryption". We do not accept empty fields.
 const plaintextFieldValue = "plain"
 
+// IsKMSURI returns true if a key specified in the --enterprise-encryption
+// flag is the URI of a KMS master key rather than the path to a key file.
+func IsKMSURI(key string) bool {
+	return strings.Contains(key, "://")
+}
+
 // StoreEncryptionSpec contains the details that can be specified in the cli via
 // the --enterprise-encryption flag.
 type StoreEncryptionSpec struct {
@@ -35,6 +58,12 @@ type StoreEncryptionSpec struct {
 	KeyPath        string
 	OldKeyPath     string
 	RotationPeriod time.Duration
+	// KMSTimeout is only used if KeyPath or OldKeyPath is a KMS URI. Zero means
+	// DefaultKMSTimeout.
+	KMSTimeout time.Duration
+	// KMSCipher is only used if KeyPath is a KMS URI, when a new store key is
+	// generated for it. Empty means DefaultKMSCipher.
+	KMSCipher string
 }
 
 // ToEncryptionOptions convert to a serialized EncryptionOptions protobuf.
@@ -46,6 +75,8 @@ func (es StoreEncryptionSpec) ToEncryptionOptions() ([]byte, error) {
 			OldKey:     es.OldKeyPath,
 		},
 		DataKeyRotationPeriod: int64(es.RotationPeriod / time.Second),
+		KMSTimeout:            int64(es.KMSTimeout / time.Second),
+		KMSCipher:             es.KMSCipher,
 	}
 
 	return protoutil.Marshal(&opts)
@@ -53,9 +84,16 @@ func (es StoreEncryptionSpec) ToEncryptionOptions() ([]byte, error) {
 
 // String returns a fully parsable version of the encryption spec.
 func (es StoreEncryptionSpec) String() string {
-	// All fields are set.
-	return fmt.Sprintf("path=%s,key=%s,old-key=%s,rotation-period=%s",
+	// All fields are set, except for the optional kms-timeout and cipher.
+	s := fmt.Sprintf("path=%s,key=%s,old-key=%s,rotation-period=%s",
 		es.Path, es.KeyPath, es.OldKeyPath, es.RotationPeriod)
+	if es.KMSTimeout != 0 {
+		s += fmt.Sprintf(",kms-timeout=%s", es.KMSTimeout)
+	}
+	if es.KMSCipher != "" {
+		s += fmt.Sprintf(",cipher=%s", es.KMSCipher)
+	}
+	return s
 }
 
 // PathMatches returns true if this StoreEncryptionSpec matches the given store path.
@@ -106,8 +144,8 @@ func NewStoreEncryptionSpec(value string) (StoreEncryptionSpec, error) {
 				}
 			}
 		case "key":
-			if value == plaintextFieldValue {
-				es.KeyPath = plaintextFieldValue
+			if value == plaintextFieldValue || IsKMSURI(value) {
+				es.KeyPath = value
 			} else {
 				var err error
 				es.KeyPath, err = base.GetAbsoluteFSPath("key", value)
@@ -116,8 +154,8 @@ func NewStoreEncryptionSpec(value string) (StoreEncryptionSpec, error) {
 				}
 			}
 		case "old-key":
-			if value == plaintextFieldValue {
-				es.OldKeyPath = plaintextFieldValue
+			if value == plaintextFieldValue || IsKMSURI(value) {
+				es.OldKeyPath = value
 			} else {
 				var err error
 				es.OldKeyPath, err = base.GetAbsoluteFSPath("old-key", value)
@@ -131,6 +169,21 @@ func NewStoreEncryptionSpec(value string) (StoreEncryptionSpec, error) {
 			if err != nil {
 				return StoreEncryptionSpec{}, errors.Wrapf(err, "could not parse rotation-duration value: %s", value)
 			}
+		case "kms-timeout":
+			var err error
+			es.KMSTimeout, err = time.ParseDuration(value)
+			if err != nil {
+				return StoreEncryptionSpec{}, errors.Wrapf(err, "could not parse kms-timeout value: %s", value)
+			}
+			if es.KMSTimeout < time.Second {
+				return StoreEncryptionSpec{}, fmt.Errorf("kms-timeout must be at least 1s: %s", value)
+			}
+		case "cipher":
+			es.KMSCipher = strings.ToLower(value)
+			if !slices.Contains(KMSCiphers, es.KMSCipher) {
+				return StoreEncryptionSpec{}, fmt.Errorf("cipher must be one of %s: %s",
+					strings.Join(KMSCiphers, ", "), value)
+			}
 		default:
 			return StoreEncryptionSpec{}, fmt.Errorf("%s is not a valid enterprise-encryption field", field)
 		}
@@ -146,6 +199,9 @@ func NewStoreEncryptionSpec(value string) (StoreEncryptionSpec, error) {
 	if es.OldKeyPath == "" {
 		return StoreEncryptionSpec{}, fmt.Errorf("no old-key specified")
 	}
+	if es.KMSCipher != "" && !IsKMSURI(es.KeyPath) {
+		return StoreEncryptionSpec{}, fmt.Errorf("cipher can only be specified if key is a KMS URI")
+	}
 
 	return es, nil
 }
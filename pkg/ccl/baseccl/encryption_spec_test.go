@@ -63,6 +63,15 @@ func TestNewStoreEncryptionSpec(t *testing.T) {
 
 		// Special path * is not absolutized.
 		{"path=*,key=/new.key,old-key=/old.key", "", StoreEncryptionSpec{Path: "*", KeyPath: "/new.key", OldKeyPath: "/old.key", RotationPeriod: DefaultRotationPeriod}},
+
+		// KMS URIs are not absolutized.
+		{"path=/data,key=aws-kms:///new?REGION=us-east-1,old-key=/old.key", "", StoreEncryptionSpec{Path: "/data", KeyPath: "aws-kms:///new?REGION=us-east-1", OldKeyPath: "/old.key", RotationPeriod: DefaultRotationPeriod}},
+		{"path=/data,key=gs:///new,old-key=gs:///old,kms-timeout=30s", "", StoreEncryptionSpec{Path: "/data", KeyPath: "gs:///new", OldKeyPath: "gs:///old", RotationPeriod: DefaultRotationPeriod, KMSTimeout: 30 * time.Second}},
+		{"path=/data,key=gs:///new,old-key=plain,kms-timeout=1", `could not parse kms-timeout value: 1: time: missing unit in duration "1"`, StoreEncryptionSpec{}},
+		{"path=/data,key=gs:///new,old-key=plain,kms-timeout=10ms", "kms-timeout must be at least 1s: 10ms", StoreEncryptionSpec{}},
+		{"path=/data,key=gs:///new,old-key=plain,cipher=AES-128-GCM", "", StoreEncryptionSpec{Path: "/data", KeyPath: "gs:///new", OldKeyPath: "plain", RotationPeriod: DefaultRotationPeriod, KMSCipher: "aes-128-gcm"}},
+		{"path=/data,key=gs:///new,old-key=plain,cipher=aes-512-gcm", "cipher must be one of aes-128-ctr, aes-192-ctr, aes-256-ctr, aes-128-gcm, aes-192-gcm, aes-256-gcm: aes-512-gcm", StoreEncryptionSpec{}},
+		{"path=/data,key=/new.key,old-key=plain,cipher=aes-128-gcm", "cipher can only be specified if key is a KMS URI", StoreEncryptionSpec{}},
 	}
 
 	for i, testCase := range testCases {
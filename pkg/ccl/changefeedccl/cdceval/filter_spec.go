@@ -0,0 +1,255 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package cdceval
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/valueside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/lib/pq/oid"
+)
+
+// RangeFeedFilterSpecForExpression returns the filter spec pushed down to the
+// rangefeed servers of a changefeed with the specified select clause, so that
+// they do not send the changes the expression is certain to filter out.
+// Select clause expression assumed to be normalized.
+//
+// If the target is a single column family, the spec restricts the rangefeed
+// to that family and includes the conjuncts of the WHERE clause that compare a
+// stored, non-key column of the family to a constant. If project is set, the
+// spec also restricts the emitted values to the columns referenced by the
+// expression, unless the expression references the whole row (e.g. "*" or
+// cdc_prev). The changefeed still evaluates the whole expression on the events
+// it receives.
+func RangeFeedFilterSpecForExpression(
+	ctx context.Context,
+	desc catalog.TableDescriptor,
+	target jobspb.ChangefeedTargetSpecification,
+	sc *tree.SelectClause,
+	project bool,
+) (*kvpb.RangeFeedFilterSpec, error) {
+	spec := &kvpb.RangeFeedFilterSpec{}
+
+	var family *descpb.ColumnFamilyDescriptor
+	if target.Type == jobspb.ChangefeedTargetSpecification_COLUMN_FAMILY || desc.NumFamilies() == 1 {
+		var err error
+		if family, err = getTargetFamilyDescriptor(desc, target); err != nil {
+			return nil, err
+		}
+		spec.Families = []uint32{uint32(family.ID)}
+	}
+
+	// Key columns are encoded in the key rather than in the value, so they are
+	// neither projected nor compared by the rangefeed servers.
+	keyCols := desc.GetPrimaryIndex().CollectKeyColumnIDs()
+
+	if project {
+		if refCols, ok := referencedColumns(desc, sc); ok {
+			refCols.Difference(keyCols).ForEach(func(id descpb.ColumnID) {
+				spec.ColumnIDs = append(spec.ColumnIDs, uint32(id))
+			})
+		}
+	}
+
+	// Predicates are only allowed on a single family since a column that is
+	// not in a value is considered to be NULL.
+	if family != nil && sc.Where != nil {
+		valueCols := catalog.MakeTableColSet(family.ColumnIDs...).Difference(keyCols)
+		for _, conjunct := range splitConjuncts(sc.Where.Expr, nil) {
+			if p, ok := makeRangeFeedPredicate(ctx, desc, valueCols, conjunct); ok {
+				spec.Predicates = append(spec.Predicates, p)
+			}
+		}
+	}
+	return spec, nil
+}
+
+// referencedColumns returns the set of columns referenced by the select
+// clause, or false if the select clause may reference columns it does not
+// name, in which case all columns must be retained.
+func referencedColumns(
+	desc catalog.TableDescriptor, sc *tree.SelectClause,
+) (_ catalog.TableColSet, ok bool) {
+	var cols catalog.TableColSet
+	ok = true
+	// addColumn adds the column with the given name, returning false if the
+	// name does not refer to a stored column of the table.
+	addColumn := func(name tree.Name) bool {
+		col := catalog.FindColumnByTreeName(desc, name)
+		if col == nil || col.IsVirtual() {
+			// This is either the table itself or cdc_prev used as a tuple, or a
+			// virtual column which is computed from other columns.
+			return false
+		}
+		cols.Add(col.GetID())
+		return true
+	}
+
+	_, _ = tree.SimpleStmtVisit(sc, func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		if !ok {
+			return false, expr, nil
+		}
+		switch e := expr.(type) {
+		case *tree.ColumnAccessExpr:
+			// Accessing a field of the previous row, e.g. (cdc_prev).x.
+			if n, isName := tree.StripParens(e.Expr).(*tree.UnresolvedName); isName &&
+				!e.ByIndex && n.NumParts == 1 && catalog.FindColumnByTreeName(desc, tree.Name(n.Parts[0])) == nil {
+				ok = addColumn(e.ColName)
+				return false, expr, nil
+			}
+			ok = false
+		case *tree.UnresolvedName:
+			vn, err := e.NormalizeVarName()
+			if err != nil {
+				ok = false
+				return false, expr, nil
+			}
+			switch v := vn.(type) {
+			case *tree.ColumnItem:
+				ok = addColumn(v.ColumnName)
+			default:
+				ok = false
+			}
+		case tree.UnqualifiedStar, *tree.AllColumnsSelector, *tree.TupleStar:
+			ok = false
+		}
+		return ok, expr, nil
+	})
+	return cols, ok
+}
+
+// splitConjuncts appends the conjuncts of the given expression to the given
+// slice.
+func splitConjuncts(expr tree.Expr, conjuncts []tree.Expr) []tree.Expr {
+	if and, ok := tree.StripParens(expr).(*tree.AndExpr); ok {
+		conjuncts = splitConjuncts(and.Left, conjuncts)
+		return splitConjuncts(and.Right, conjuncts)
+	}
+	return append(conjuncts, expr)
+}
+
+// makeRangeFeedPredicate returns the rangefeed predicate equivalent to the
+// given expression, or false if the expression is not a comparison of one of
+// the given columns to a constant that the rangefeed servers can evaluate.
+func makeRangeFeedPredicate(
+	ctx context.Context, desc catalog.TableDescriptor, valueCols catalog.TableColSet, expr tree.Expr,
+) (kvpb.RangeFeedPredicate, bool) {
+	cmp, ok := tree.StripParens(expr).(*tree.ComparisonExpr)
+	if !ok {
+		return kvpb.RangeFeedPredicate{}, false
+	}
+	op, ok := rangeFeedPredicateOps[cmp.Operator.Symbol]
+	if !ok {
+		return kvpb.RangeFeedPredicate{}, false
+	}
+	left, right := tree.StripParens(cmp.Left), tree.StripParens(cmp.Right)
+	if _, isName := right.(*tree.UnresolvedName); isName {
+		// The column is on the right hand side, e.g. 5 < x.
+		left, right = right, left
+		op = commutedRangeFeedPredicateOps[op]
+	}
+
+	n, ok := left.(*tree.UnresolvedName)
+	if !ok {
+		return kvpb.RangeFeedPredicate{}, false
+	}
+	vn, err := n.NormalizeVarName()
+	if err != nil {
+		return kvpb.RangeFeedPredicate{}, false
+	}
+	colItem, ok := vn.(*tree.ColumnItem)
+	if !ok {
+		return kvpb.RangeFeedPredicate{}, false
+	}
+	col := catalog.FindColumnByTreeName(desc, colItem.ColumnName)
+	if col == nil || col.IsVirtual() || !valueCols.Contains(col.GetID()) {
+		return kvpb.RangeFeedPredicate{}, false
+	}
+	typ, ok := rangeFeedPredicateType(col.GetType())
+	if !ok {
+		return kvpb.RangeFeedPredicate{}, false
+	}
+
+	// Only constants are pushed down.
+	switch right.(type) {
+	case *tree.NumVal, *tree.StrVal, tree.Datum:
+	default:
+		return kvpb.RangeFeedPredicate{}, false
+	}
+	typedExpr, err := tree.TypeCheckAndRequire(ctx, right, nil /* semaCtx */, typ, "changefeed predicate")
+	if err != nil {
+		return kvpb.RangeFeedPredicate{}, false
+	}
+	d, ok := typedExpr.(tree.Datum)
+	if !ok || d == tree.DNull {
+		return kvpb.RangeFeedPredicate{}, false
+	}
+	value, err := valueside.Encode(nil /* appendTo */, valueside.NoColumnID, d, nil /* scratch */)
+	if err != nil {
+		return kvpb.RangeFeedPredicate{}, false
+	}
+	return kvpb.RangeFeedPredicate{
+		ColumnID: uint32(col.GetID()),
+		Op:       op,
+		Value:    value,
+	}, true
+}
+
+// rangeFeedPredicateType returns the type to which constants compared to a
+// column of the given type are converted, or false if comparisons on the
+// column cannot be pushed down. The value encoding of these types orders
+// values the same way as SQL.
+func rangeFeedPredicateType(typ *types.T) (*types.T, bool) {
+	switch typ.Family() {
+	case types.IntFamily:
+		return types.Int, true
+	case types.FloatFamily:
+		// FLOAT4 values are rounded when stored, so comparisons with constants
+		// of higher precision are not pushed down.
+		if typ.Width() == 64 {
+			return types.Float, true
+		}
+	case types.BoolFamily:
+		return types.Bool, true
+	case types.BytesFamily:
+		return types.Bytes, true
+	case types.StringFamily:
+		// CHAR values are padded when compared, and collated strings, which are
+		// not in the string family, are compared by their collation.
+		if typ.Oid() == oid.T_text || typ.Oid() == oid.T_varchar {
+			return types.String, true
+		}
+	}
+	return nil, false
+}
+
+var rangeFeedPredicateOps = map[treecmp.ComparisonOperatorSymbol]kvpb.RangeFeedPredicate_Operator{
+	treecmp.EQ: kvpb.RangeFeedPredicate_EQ,
+	treecmp.NE: kvpb.RangeFeedPredicate_NE,
+	treecmp.LT: kvpb.RangeFeedPredicate_LT,
+	treecmp.LE: kvpb.RangeFeedPredicate_LE,
+	treecmp.GT: kvpb.RangeFeedPredicate_GT,
+	treecmp.GE: kvpb.RangeFeedPredicate_GE,
+}
+
+var commutedRangeFeedPredicateOps = map[kvpb.RangeFeedPredicate_Operator]kvpb.RangeFeedPredicate_Operator{
+	kvpb.RangeFeedPredicate_EQ: kvpb.RangeFeedPredicate_EQ,
+	kvpb.RangeFeedPredicate_NE: kvpb.RangeFeedPredicate_NE,
+	kvpb.RangeFeedPredicate_LT: kvpb.RangeFeedPredicate_GT,
+	kvpb.RangeFeedPredicate_LE: kvpb.RangeFeedPredicate_GE,
+	kvpb.RangeFeedPredicate_GT: kvpb.RangeFeedPredicate_LT,
+	kvpb.RangeFeedPredicate_GE: kvpb.RangeFeedPredicate_LE,
+}
@@ -0,0 +1,203 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package cdceval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/rangefeed"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRangeFeedFilterSpecForExpression(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(context.Background())
+	s := srv.ApplicationLayer()
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE TABLE foo (
+a INT PRIMARY KEY,
+b INT,
+c STRING,
+d FLOAT4,
+e STRING AS (upper(c)) VIRTUAL,
+f BYTES,
+FAMILY main (a, b, c, d),
+FAMILY extra (f)
+)`)
+	sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY, b INT)`)
+
+	fooDesc := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), "foo")
+	barDesc := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), "bar")
+	ctx := context.Background()
+
+	intValue := func(i int64) []byte {
+		return encoding.EncodeIntValue(nil, encoding.NoColumnID, i)
+	}
+	bytesValue := func(b string) []byte {
+		return encoding.EncodeBytesValue(nil, encoding.NoColumnID, []byte(b))
+	}
+
+	for _, tc := range []struct {
+		name         string
+		desc         catalog.TableDescriptor
+		stmt         string
+		targetFamily string
+		noProjection bool
+		expected     kvpb.RangeFeedFilterSpec
+	}{
+		{
+			name:         "projection and predicate",
+			stmt:         "SELECT a, c FROM foo WHERE b > 10",
+			targetFamily: "main",
+			expected: kvpb.RangeFeedFilterSpec{
+				Families:   []uint32{0},
+				ColumnIDs:  []uint32{2, 3},
+				Predicates: []kvpb.RangeFeedPredicate{{ColumnID: 2, Op: kvpb.RangeFeedPredicate_GT, Value: intValue(10)}},
+			},
+		},
+		{
+			name:         "projection disabled",
+			stmt:         "SELECT a, c FROM foo WHERE b > 10",
+			targetFamily: "main",
+			noProjection: true,
+			expected: kvpb.RangeFeedFilterSpec{
+				Families:   []uint32{0},
+				Predicates: []kvpb.RangeFeedPredicate{{ColumnID: 2, Op: kvpb.RangeFeedPredicate_GT, Value: intValue(10)}},
+			},
+		},
+		{
+			name:         "star and commuted conjuncts",
+			stmt:         "SELECT * FROM foo WHERE 10 <= b AND (c = 'x' AND b != 20)",
+			targetFamily: "main",
+			expected: kvpb.RangeFeedFilterSpec{
+				Families: []uint32{0},
+				Predicates: []kvpb.RangeFeedPredicate{
+					{ColumnID: 2, Op: kvpb.RangeFeedPredicate_GE, Value: intValue(10)},
+					{ColumnID: 3, Op: kvpb.RangeFeedPredicate_EQ, Value: bytesValue("x")},
+					{ColumnID: 2, Op: kvpb.RangeFeedPredicate_NE, Value: intValue(20)},
+				},
+			},
+		},
+		{
+			name:         "disjunction and key column",
+			stmt:         "SELECT a FROM foo WHERE a > 1 OR b > 1",
+			targetFamily: "main",
+			expected: kvpb.RangeFeedFilterSpec{
+				Families:  []uint32{0},
+				ColumnIDs: []uint32{2},
+			},
+		},
+		{
+			name:         "predicate on key column",
+			stmt:         "SELECT a, b FROM foo WHERE a > 1",
+			targetFamily: "main",
+			expected: kvpb.RangeFeedFilterSpec{
+				Families:  []uint32{0},
+				ColumnIDs: []uint32{2},
+			},
+		},
+		{
+			name:         "previous row column and float4",
+			stmt:         "SELECT a, (cdc_prev).c FROM foo WHERE d > 1.5",
+			targetFamily: "main",
+			expected: kvpb.RangeFeedFilterSpec{
+				Families:  []uint32{0},
+				ColumnIDs: []uint32{3, 4},
+			},
+		},
+		{
+			name:         "previous row tuple",
+			stmt:         "SELECT a, cdc_prev FROM foo WHERE b < 3",
+			targetFamily: "main",
+			expected: kvpb.RangeFeedFilterSpec{
+				Families:   []uint32{0},
+				Predicates: []kvpb.RangeFeedPredicate{{ColumnID: 2, Op: kvpb.RangeFeedPredicate_LT, Value: intValue(3)}},
+			},
+		},
+		{
+			name:         "virtual column",
+			stmt:         "SELECT a, e FROM foo",
+			targetFamily: "main",
+			expected: kvpb.RangeFeedFilterSpec{
+				Families: []uint32{0},
+			},
+		},
+		{
+			name:         "comparison with a non-constant",
+			stmt:         "SELECT c FROM foo WHERE b > c::INT AND b <= NULL",
+			targetFamily: "main",
+			expected: kvpb.RangeFeedFilterSpec{
+				Families:  []uint32{0},
+				ColumnIDs: []uint32{2, 3},
+			},
+		},
+		{
+			name:         "other family",
+			stmt:         "SELECT f FROM foo WHERE f = 'x' AND b = 1",
+			targetFamily: "extra",
+			expected: kvpb.RangeFeedFilterSpec{
+				Families:   []uint32{1},
+				ColumnIDs:  []uint32{2, 6},
+				Predicates: []kvpb.RangeFeedPredicate{{ColumnID: 6, Op: kvpb.RangeFeedPredicate_EQ, Value: bytesValue("x")}},
+			},
+		},
+		{
+			name: "multiple families without target family",
+			stmt: "SELECT a, b FROM foo WHERE b = 1",
+			expected: kvpb.RangeFeedFilterSpec{
+				ColumnIDs: []uint32{2},
+			},
+		},
+		{
+			name: "single family without target family",
+			desc: barDesc,
+			stmt: "SELECT a, b FROM bar WHERE b = 1",
+			expected: kvpb.RangeFeedFilterSpec{
+				Families:   []uint32{0},
+				ColumnIDs:  []uint32{2},
+				Predicates: []kvpb.RangeFeedPredicate{{ColumnID: 2, Op: kvpb.RangeFeedPredicate_EQ, Value: intValue(1)}},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sc, err := ParseChangefeedExpression(tc.stmt)
+			require.NoError(t, err)
+
+			desc := tc.desc
+			if desc == nil {
+				desc = fooDesc
+			}
+			target := jobspb.ChangefeedTargetSpecification{TableID: desc.GetID()}
+			if tc.targetFamily != "" {
+				target.Type = jobspb.ChangefeedTargetSpecification_COLUMN_FAMILY
+				target.FamilyName = tc.targetFamily
+			}
+
+			spec, err := RangeFeedFilterSpecForExpression(ctx, desc, target, sc, !tc.noProjection)
+			require.NoError(t, err)
+			require.NoError(t, rangefeed.ValidateFilterSpec(spec))
+			require.Equal(t, tc.expected, *spec)
+		})
+	}
+}
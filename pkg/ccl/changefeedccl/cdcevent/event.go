@@ -481,6 +481,31 @@ func NewEventDecoder(
 	targets changefeedbase.Targets,
 	includeVirtual bool,
 	keyOnly bool,
+) (Decoder, error) {
+	return newEventDecoder(ctx, cfg, targets, includeVirtual, keyOnly, false /* projected */)
+}
+
+// NewProjectedEventDecoder returns key value decoder for values whose columns
+// may have been projected by the rangefeed servers (see
+// kvpb.RangeFeedFilterSpec). Columns missing from such values are decoded as
+// NULL, even if they are not nullable.
+func NewProjectedEventDecoder(
+	ctx context.Context,
+	cfg *sql.ExecutorConfig,
+	targets changefeedbase.Targets,
+	includeVirtual bool,
+	keyOnly bool,
+) (Decoder, error) {
+	return newEventDecoder(ctx, cfg, targets, includeVirtual, keyOnly, true /* projected */)
+}
+
+func newEventDecoder(
+	ctx context.Context,
+	cfg *sql.ExecutorConfig,
+	targets changefeedbase.Targets,
+	includeVirtual bool,
+	keyOnly bool,
+	projected bool,
 ) (Decoder, error) {
 	rfCache, err := newRowFetcherCache(
 		ctx,
@@ -494,6 +519,7 @@ func NewEventDecoder(
 	if err != nil {
 		return nil, err
 	}
+	rfCache.rfArgs.ignoreUnexpectedNulls = projected
 
 	return NewEventDecoderWithCache(ctx, rfCache, includeVirtual, keyOnly), nil
 }
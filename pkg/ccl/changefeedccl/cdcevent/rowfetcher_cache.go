@@ -138,6 +138,10 @@ func refreshUDT(
 type rowFetcherArgs struct {
 	traceKV             bool
 	traceKVLogFrequency time.Duration
+	// ignoreUnexpectedNulls is set if the decoded values may be missing
+	// columns, including non-nullable ones, because they were projected by
+	// the rangefeed servers.
+	ignoreUnexpectedNulls bool
 }
 
 type cachedFetcher struct {
@@ -327,6 +331,7 @@ func (c *rowFetcherCache) RowFetcherForColumnFamily(
 	); err != nil {
 		return nil, nil, err
 	}
+	rf.IgnoreUnexpectedNulls = c.rfArgs.ignoreUnexpectedNulls
 
 	c.fetchers.Add(idVer, f)
 	return rf, familyDesc, nil
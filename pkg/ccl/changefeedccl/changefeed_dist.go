@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql"
@@ -223,6 +224,36 @@ func fetchSpansForTables(
 		sd, tableDescs[0], initialHighwater, target, sc)
 }
 
+// fetchFilterSpecForTables returns the filter spec that the change aggregators
+// push down to their rangefeeds, or nil if the changefeed has no select clause.
+// The select clause must have been validated by fetchSpansForTables.
+func fetchFilterSpecForTables(
+	ctx context.Context,
+	execCtx sql.JobExecContext,
+	tableDescs []catalog.TableDescriptor,
+	details jobspb.ChangefeedDetails,
+) (*kvpb.RangeFeedFilterSpec, error) {
+	if details.Select == "" ||
+		!changefeedbase.RangefeedFilterPushdownEnabled.Get(&execCtx.ExecCfg().Settings.SV) {
+		return nil, nil
+	}
+	sc, err := cdceval.ParseChangefeedExpression(details.Select)
+	if err != nil {
+		return nil, pgerror.Wrap(err, pgcode.InvalidParameterValue,
+			"could not parse changefeed expression")
+	}
+	encodingOpts, err := changefeedbase.MakeStatementOptions(details.Opts).GetEncodingOptions()
+	if err != nil {
+		return nil, err
+	}
+	// Other envelopes may encode columns of the row which the expression does
+	// not reference (e.g. the previous row with the wrapped envelope), so the
+	// columns are only projected with the bare envelope.
+	project := encodingOpts.Envelope == changefeedbase.OptEnvelopeBare
+	return cdceval.RangeFeedFilterSpecForExpression(
+		ctx, tableDescs[0], details.TargetSpecifications[0], sc, project)
+}
+
 // startDistChangefeed starts distributed changefeed execution.
 func startDistChangefeed(
 	ctx context.Context,
@@ -251,6 +282,10 @@ func startDistChangefeed(
 		log.Infof(ctx, "tracked spans: %s", trackedSpans)
 	}
 	localState.trackedSpans = trackedSpans
+	filterSpec, err := fetchFilterSpecForTables(ctx, execCtx, tableDescs, details)
+	if err != nil {
+		return err
+	}
 
 	// Changefeed flows handle transactional consistency themselves.
 	var noTxn *kv.Txn
@@ -263,7 +298,7 @@ func startDistChangefeed(
 		checkpoint = progress.Checkpoint
 	}
 	p, planCtx, err := makePlan(execCtx, jobID, details, initialHighWater,
-		trackedSpans, checkpoint, localState.drainingNodes)(ctx, dsp)
+		trackedSpans, filterSpec, checkpoint, localState.drainingNodes)(ctx, dsp)
 	if err != nil {
 		return err
 	}
@@ -378,6 +413,7 @@ func makePlan(
 	details jobspb.ChangefeedDetails,
 	initialHighWater hlc.Timestamp,
 	trackedSpans []roachpb.Span,
+	filterSpec *kvpb.RangeFeedFilterSpec,
 	checkpoint *jobspb.ChangefeedProgress_Checkpoint,
 	drainingNodes []roachpb.NodeID,
 ) func(context.Context, *sql.DistSQLPlanner) (*sql.PhysicalPlan, *sql.PlanningCtx, error) {
@@ -485,6 +521,7 @@ func makePlan(
 				UserProto:  execCtx.User().EncodeProto(),
 				JobID:      jobID,
 				Select:     execinfrapb.Expression{Expr: details.Select},
+				FilterSpec: filterSpec,
 			}
 		}
 
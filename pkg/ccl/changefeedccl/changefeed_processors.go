@@ -492,6 +492,7 @@ func (ca *changeAggregator) makeKVFeedCfg(
 		EndTime:             config.EndTime,
 		WithDiff:            filters.WithDiff,
 		WithFiltering:       filters.WithFiltering,
+		FilterSpec:          ca.spec.FilterSpec,
 		NeedsInitialScan:    needsInitialScan,
 		SchemaChangeEvents:  schemaChange.EventClass,
 		SchemaChangePolicy:  schemaChange.Policy,
@@ -1199,7 +1200,8 @@ func newChangeFrontierProcessor(
 
 	if cf.encoder, err = getEncoder(
 		ctx, encodingOpts, AllTargets(spec.Feed), spec.Feed.Select != "",
-		makeExternalConnectionProvider(ctx, flowCtx.Cfg.DB), sliMertics,
+		makeExternalConnectionProvider(ctx, flowCtx.Cfg.DB), flowCtx.Cfg.ExternalStorageFromURI,
+		spec.User(), sliMertics,
 	); err != nil {
 		return nil, err
 	}
@@ -592,7 +592,8 @@ func createChangefeedJobRecord(
 		return nil, err
 	}
 	if _, err := getEncoder(ctx, encodingOpts, AllTargets(details), details.Select != "",
-		makeExternalConnectionProvider(ctx, p.ExecCfg().InternalDB), nil); err != nil {
+		makeExternalConnectionProvider(ctx, p.ExecCfg().InternalDB),
+		p.ExecCfg().DistSQLSrv.ExternalStorageFromURI, p.User(), nil); err != nil {
 		return nil, err
 	}
 
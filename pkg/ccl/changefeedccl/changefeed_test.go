@@ -79,6 +79,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -7786,6 +7787,84 @@ INSERT INTO foo (a, b, e) VALUES (3, 'tres', 'closed'); -- should be emitted
 	})
 }
 
+// TestChangefeedPredicatePushdown verifies that the column family, columns and
+// predicates of a changefeed expression are pushed down to the rangefeed
+// servers, which then do not send the changes that the expression filters out.
+func TestChangefeedPredicatePushdown(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `
+CREATE TABLE foo (
+  a INT PRIMARY KEY,
+  b INT NOT NULL,
+  c STRING,
+  d STRING NOT NULL DEFAULT 'unused'
+)`)
+
+		knobs := s.TestingKnobs.
+			DistSQL.(*execinfra.TestingKnobs).
+			Changefeed.(*TestingKnobs)
+		var mu struct {
+			syncutil.Mutex
+			filterSpec *kvpb.RangeFeedFilterSpec
+		}
+		knobs.OnDistflowSpec = func(
+			aggregatorSpecs []*execinfrapb.ChangeAggregatorSpec, _ *execinfrapb.ChangeFrontierSpec,
+		) {
+			mu.Lock()
+			defer mu.Unlock()
+			mu.filterSpec = aggregatorSpecs[0].FilterSpec
+		}
+		var rangefeedValues int64
+		knobs.FeedKnobs.OnRangeFeedValue = func() error {
+			atomic.AddInt64(&rangefeedValues, 1)
+			return nil
+		}
+
+		feed := feed(t, f, `
+CREATE CHANGEFEED WITH initial_scan='no'
+AS SELECT a, c FROM foo WHERE b > 10`)
+		defer closeFeed(t, feed)
+
+		// None of these rows satisfy the predicate, so the rangefeed servers do
+		// not send them.
+		sqlDB.Exec(t, `INSERT INTO foo (a, b, c) SELECT i, 0, 'filtered' FROM generate_series(100, 199) AS g(i)`)
+		sqlDB.Exec(t, `INSERT INTO foo (a, b, c) VALUES (1, 5, 'one'), (2, 20, 'two')`)
+		sqlDB.Exec(t, `UPDATE foo SET b = 30 WHERE a = 1`)
+		sqlDB.Exec(t, `UPDATE foo SET b = 1 WHERE a = 2`)
+		sqlDB.Exec(t, `INSERT INTO foo (a, b, c) VALUES (3, 11, 'three')`)
+
+		// The projection removes the non-nullable column d, which must not
+		// prevent the events from being decoded.
+		assertPayloads(t, feed, []string{
+			`foo: [2]->{"a": 2, "c": "two"}`,
+			`foo: [1]->{"a": 1, "c": "one"}`,
+			`foo: [3]->{"a": 3, "c": "three"}`,
+		})
+
+		mu.Lock()
+		filterSpec := mu.filterSpec
+		mu.Unlock()
+		require.NotNil(t, filterSpec)
+		require.Equal(t, []uint32{0}, filterSpec.Families)
+		require.Equal(t, []uint32{2, 3}, filterSpec.ColumnIDs)
+		require.Equal(t, []kvpb.RangeFeedPredicate{{
+			ColumnID: 2,
+			Op:       kvpb.RangeFeedPredicate_GT,
+			Value:    encoding.EncodeIntValue(nil, encoding.NoColumnID, 10),
+		}}, filterSpec.Predicates)
+
+		// Only the three matching values are expected, but allow for some to be
+		// sent more than once if a rangefeed is restarted.
+		require.Less(t, atomic.LoadInt64(&rangefeedValues), int64(100))
+	}
+
+	cdcTest(t, testFn)
+}
+
 // Some predicates and projections can be verified when creating changefeed.
 // The types of errors that can be detected early on is restricted to simple checks
 // (such as type checking, non-existent columns, etc).  More complex errors detected
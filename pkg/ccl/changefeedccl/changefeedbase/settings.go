@@ -265,6 +265,19 @@ var PerEventElasticCPUControlEnabled = settings.RegisterBoolSetting(
 	true,
 )
 
+// RangefeedFilterPushdownEnabled determines whether the column family, columns
+// and predicates of a changefeed expression are pushed down to the rangefeed
+// servers.
+var RangefeedFilterPushdownEnabled = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"changefeed.rangefeed_filter_pushdown.enabled",
+	"if enabled, changefeeds with expressions push their column family, projected "+
+		"columns and simple predicates down to the rangefeed servers, which then do "+
+		"not send changes that the expression filters out; takes effect when the "+
+		"changefeed is restarted",
+	true,
+)
+
 // RequireExternalConnectionSink is used to restrict non-admins with the CHANGEFEED privilege
 // to create changefeeds to external connections only.
 var RequireExternalConnectionSink = settings.RegisterBoolSetting(
@@ -13,6 +13,8 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/errors"
 )
@@ -47,6 +49,8 @@ func getEncoder(
 	targets changefeedbase.Targets,
 	encodeForQuery bool,
 	p externalConnectionProvider,
+	makeExternalStorage cloud.ExternalStorageFromURIFactory,
+	user username.SQLUsername,
 	sliMetrics *sliMetrics,
 ) (Encoder, error) {
 	switch opts.Format {
@@ -56,6 +60,8 @@ func getEncoder(
 		return newConfluentAvroEncoder(opts, targets, p, sliMetrics)
 	case changefeedbase.OptFormatCSV:
 		return newCSVEncoder(opts), nil
+	case changefeedbase.OptFormatProtobuf:
+		return newProtobufEncoder(opts, targets, makeExternalStorage, user)
 	case changefeedbase.OptFormatParquet:
 		//We will return no encoder for parquet format because there is a separate
 		//sink implemented for parquet format for cloud storage, which does the job
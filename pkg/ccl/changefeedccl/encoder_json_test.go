@@ -16,6 +16,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -149,7 +150,7 @@ func TestJSONEncoderJSONNullAsObject(t *testing.T) {
 		// NOTE: This is no longer required in go 1.22+, but bazel still requires it. See https://github.com/bazelbuild/rules_go/issues/3924
 		c := c
 		t.Run(c.name, func(t *testing.T) {
-			e, err := getEncoder(ctx, opts, targets, false, nil, nil)
+			e, err := getEncoder(ctx, opts, targets, false, nil, nil, username.RootUserName(), nil)
 			require.NoError(t, err)
 
 			row := cdcevent.TestingMakeEventRow(tableDesc, 0, c.row, false)
@@ -193,7 +194,7 @@ func TestJSONEncoderJSONNullAsObjectEdgeCases(t *testing.T) {
 			rowenc.EncDatum{Datum: tree.DBoolTrue},
 			rowenc.EncDatum{Datum: tree.NewDJSON(json.NullJSONValue)},
 		}
-		e, err := getEncoder(ctx, opts, targets, false, nil, nil)
+		e, err := getEncoder(ctx, opts, targets, false, nil, nil, username.RootUserName(), nil)
 		require.NoError(t, err)
 
 		row := cdcevent.TestingMakeEventRow(tableDesc, 0, eRow, false)
@@ -216,7 +217,7 @@ func TestJSONEncoderJSONNullAsObjectEdgeCases(t *testing.T) {
 			rowenc.EncDatum{Datum: tree.NewDJSON(json.NullJSONValue)},
 			rowenc.EncDatum{Datum: tree.DNull},
 		}
-		e, err := getEncoder(ctx, opts, twoJSONsTargets, false, nil, nil)
+		e, err := getEncoder(ctx, opts, twoJSONsTargets, false, nil, nil, username.RootUserName(), nil)
 		require.NoError(t, err)
 
 		row := cdcevent.TestingMakeEventRow(twoJSONsTableDesc, 0, eRow, false)
@@ -233,7 +234,7 @@ func TestJSONEncoderJSONNullAsObjectEdgeCases(t *testing.T) {
 			rowenc.EncDatum{Datum: tree.NewDJSON(json.NullJSONValue)},
 			rowenc.EncDatum{Datum: tree.NewDJSON(json.NullJSONValue)},
 		}
-		e, err := getEncoder(ctx, opts, twoJSONsTargets, false, nil, nil)
+		e, err := getEncoder(ctx, opts, twoJSONsTargets, false, nil, nil, username.RootUserName(), nil)
 		require.NoError(t, err)
 
 		row := cdcevent.TestingMakeEventRow(twoJSONsTableDesc, 0, eRow, false)
@@ -254,7 +255,7 @@ func TestJSONEncoderJSONNullAsObjectEdgeCases(t *testing.T) {
 			rowenc.EncDatum{Datum: tree.NewDJSON(json.NullJSONValue)},
 			rowenc.EncDatum{Datum: tree.DNull},
 		}
-		e, err := getEncoder(ctx, disabledOpts, twoJSONsTargets, false, nil, nil)
+		e, err := getEncoder(ctx, disabledOpts, twoJSONsTargets, false, nil, nil, username.RootUserName(), nil)
 		require.NoError(t, err)
 
 		row := cdcevent.TestingMakeEventRow(twoJSONsTableDesc, 0, eRow, false)
@@ -275,7 +276,7 @@ func TestJSONEncoderJSONNullAsObjectEdgeCases(t *testing.T) {
 			rowenc.EncDatum{Datum: tree.NewDJSON(json.NullJSONValue)},
 			rowenc.EncDatum{Datum: tree.NewDJSON(obj)},
 		}
-		e, err := getEncoder(ctx, opts, twoJSONsTargets, false, nil, nil)
+		e, err := getEncoder(ctx, opts, twoJSONsTargets, false, nil, nil, username.RootUserName(), nil)
 		require.NoError(t, err)
 
 		row := cdcevent.TestingMakeEventRow(twoJSONsTableDesc, 0, eRow, false)
@@ -0,0 +1,566 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// protobufPackage is the package of every generated descriptor. Table
+	// descriptors append the escaped table name and version to it.
+	protobufPackage = `cockroachdb.changefeed`
+
+	protobufKeyMessage      = `Key`
+	protobufRowMessage      = `Row`
+	protobufEnvelopeMessage = `Envelope`
+	protobufResolvedMessage = `Resolved`
+
+	protobufResolvedDescriptorFile = `resolved.binpb`
+)
+
+// Field numbers of the Envelope message. These never change, so consumers
+// can rely on them regardless of the table version.
+const (
+	protobufEnvelopeAfter         protowire.Number = 1
+	protobufEnvelopeBefore        protowire.Number = 2
+	protobufEnvelopeUpdated       protowire.Number = 3
+	protobufEnvelopeMVCCTimestamp protowire.Number = 4
+	protobufEnvelopeKey           protowire.Number = 5
+	protobufEnvelopeTopic         protowire.Number = 6
+
+	protobufResolvedField protowire.Number = 1
+)
+
+// protobufEncoder encodes changefeed entries as protocol buffer messages.
+// Keys are Key messages containing the primary key columns of a row (or the
+// key_column). Values are Envelope messages wrapping Row messages with all
+// the columns of a row, or bare Row messages for the row and bare envelopes.
+//
+// The messages are described by a FileDescriptorSet that is generated for
+// every table version and written to the location given by the
+// protobuf_descriptor_uri option before the first message of that version is
+// emitted. Row fields are numbered by column ID, so a consumer holding the
+// descriptor of a newer table version can decode messages produced with an
+// older one.
+type protobufEncoder struct {
+	updatedField, mvccTimestampField, beforeField bool
+	keyInValue, topicInValue                      bool
+	envelopeType                                  changefeedbase.EnvelopeType
+	customKeyColumn                               string
+	targets                                       changefeedbase.Targets
+
+	descriptorURI       string
+	makeExternalStorage cloud.ExternalStorageFromURIFactory
+	user                username.SQLUsername
+
+	schemaCache *cache.UnorderedCache // [tableIDAndVersion]*protobufSchema
+	prevCache   *cache.UnorderedCache // [tableIDAndVersion][]protobufField
+	// wroteResolvedDescriptor is set once the descriptor of resolved timestamp
+	// messages has been written.
+	wroteResolvedDescriptor bool
+
+	formatter *tree.FmtCtx
+	// scratch is used to encode nested messages.
+	scratch []byte
+}
+
+var _ Encoder = &protobufEncoder{}
+
+// protobufKind is the protobuf type a column is encoded as.
+type protobufKind int8
+
+const (
+	protobufString protobufKind = iota
+	protobufBool
+	protobufInt64
+	protobufDouble
+	protobufBytes
+	protobufTimestamp
+)
+
+// protobufField describes how a column is encoded.
+type protobufField struct {
+	name   string
+	number protowire.Number
+	kind   protobufKind
+}
+
+// protobufSchema describes the messages generated for one version of a table
+// (and column family). Fields are in the same order as the columns returned
+// by the iterators of rows with this version.
+type protobufSchema struct {
+	key []protobufField
+	row []protobufField
+}
+
+func newProtobufEncoder(
+	opts changefeedbase.EncodingOptions,
+	targets changefeedbase.Targets,
+	makeExternalStorage cloud.ExternalStorageFromURIFactory,
+	user username.SQLUsername,
+) (*protobufEncoder, error) {
+	if opts.ProtobufDescriptorURI == `` {
+		return nil, errors.Errorf(`WITH option %s is required for %s=%s`,
+			changefeedbase.OptProtobufDescriptorURI, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+	// Validate the URI now rather than when the first descriptor is written.
+	if _, err := cloud.ExternalStorageConfFromURI(opts.ProtobufDescriptorURI, user); err != nil {
+		return nil, errors.Wrapf(err, `invalid %s`, changefeedbase.OptProtobufDescriptorURI)
+	}
+	return &protobufEncoder{
+		updatedField:        opts.UpdatedTimestamps,
+		mvccTimestampField:  opts.MVCCTimestamps,
+		beforeField:         opts.Diff,
+		keyInValue:          opts.KeyInValue,
+		topicInValue:        opts.TopicInValue,
+		envelopeType:        opts.Envelope,
+		customKeyColumn:     opts.CustomKeyColumn,
+		targets:             targets,
+		descriptorURI:       opts.ProtobufDescriptorURI,
+		makeExternalStorage: makeExternalStorage,
+		user:                user,
+		schemaCache:         cache.NewUnorderedCache(encoderCacheConfig),
+		prevCache:           cache.NewUnorderedCache(encoderCacheConfig),
+		formatter:           tree.NewFmtCtx(tree.FmtExport),
+	}, nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *protobufEncoder) EncodeKey(ctx context.Context, row cdcevent.Row) ([]byte, error) {
+	schema, err := e.schemaForRow(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	it, err := e.keyColumns(row)
+	if err != nil {
+		return nil, err
+	}
+	return e.appendMessage(nil, schema.key, it)
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *protobufEncoder) EncodeValue(
+	ctx context.Context, evCtx eventContext, updatedRow cdcevent.Row, prevRow cdcevent.Row,
+) ([]byte, error) {
+	if e.envelopeType == changefeedbase.OptEnvelopeKeyOnly {
+		return nil, nil
+	}
+	schema, err := e.schemaForRow(ctx, updatedRow)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.envelopeType != changefeedbase.OptEnvelopeWrapped {
+		if updatedRow.IsDeleted() {
+			return nil, nil
+		}
+		return e.appendMessage(nil, schema.row, updatedRow.ForEachColumn())
+	}
+
+	var b []byte
+	if !updatedRow.IsDeleted() {
+		if b, err = e.appendNestedMessage(b, protobufEnvelopeAfter, schema.row, updatedRow.ForEachColumn()); err != nil {
+			return nil, err
+		}
+	}
+	if e.beforeField && prevRow.IsInitialized() && !prevRow.IsDeleted() {
+		// The previous row may have a different version than the updated row, or
+		// different columns when encoding a CDC query. Encode it with its own
+		// fields: columns that are also in the updated row have the same field
+		// number, and the others are unknown fields of the Row message.
+		prevFields, err := e.prevRowFields(prevRow)
+		if err != nil {
+			return nil, err
+		}
+		if b, err = e.appendNestedMessage(b, protobufEnvelopeBefore, prevFields, prevRow.ForEachColumn()); err != nil {
+			return nil, err
+		}
+	}
+	if e.updatedField {
+		b = protowire.AppendTag(b, protobufEnvelopeUpdated, protowire.BytesType)
+		b = protowire.AppendString(b, evCtx.updated.AsOfSystemTime())
+	}
+	if e.mvccTimestampField {
+		b = protowire.AppendTag(b, protobufEnvelopeMVCCTimestamp, protowire.BytesType)
+		b = protowire.AppendString(b, evCtx.mvcc.AsOfSystemTime())
+	}
+	if e.keyInValue {
+		it, err := e.keyColumns(updatedRow)
+		if err != nil {
+			return nil, err
+		}
+		if b, err = e.appendNestedMessage(b, protobufEnvelopeKey, schema.key, it); err != nil {
+			return nil, err
+		}
+	}
+	if e.topicInValue {
+		b = protowire.AppendTag(b, protobufEnvelopeTopic, protowire.BytesType)
+		b = protowire.AppendString(b, evCtx.topic)
+	}
+	if b == nil {
+		// An envelope without any fields set is still a message.
+		b = []byte{}
+	}
+	return b, nil
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *protobufEncoder) EncodeResolvedTimestamp(
+	ctx context.Context, _ string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	if !e.wroteResolvedDescriptor {
+		fd := &descriptorpb.FileDescriptorProto{
+			Name:    proto.String(`cockroachdb/changefeed/resolved.proto`),
+			Package: proto.String(protobufPackage),
+			Syntax:  proto.String(`proto2`),
+			MessageType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String(protobufResolvedMessage),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					protobufScalarFieldDescriptor(`resolved`, protobufResolvedField, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+				},
+			}},
+		}
+		if err := e.writeDescriptor(ctx, protobufResolvedDescriptorFile, fd); err != nil {
+			return nil, err
+		}
+		e.wroteResolvedDescriptor = true
+	}
+	b := protowire.AppendTag(nil, protobufResolvedField, protowire.BytesType)
+	return protowire.AppendString(b, resolved.AsOfSystemTime()), nil
+}
+
+// keyColumns returns an iterator over the columns making up the key of row.
+func (e *protobufEncoder) keyColumns(row cdcevent.Row) (cdcevent.Iterator, error) {
+	if e.customKeyColumn == `` {
+		return row.ForEachKeyColumn(), nil
+	}
+	it, err := row.DatumNamed(e.customKeyColumn)
+	if err != nil {
+		return nil, changefeedbase.WithTerminalError(err)
+	}
+	return it, nil
+}
+
+// schemaForRow returns the schema of the version of the given row. When a
+// version is seen for the first time, its descriptor is generated and written
+// to the descriptor location.
+func (e *protobufEncoder) schemaForRow(
+	ctx context.Context, row cdcevent.Row,
+) (*protobufSchema, error) {
+	cacheKey := tableIDAndVersion{tableID: row.TableID, version: row.Version, familyID: row.FamilyID}
+	if v, ok := e.schemaCache.Get(cacheKey); ok {
+		return v.(*protobufSchema), nil
+	}
+
+	keyIt, err := e.keyColumns(row)
+	if err != nil {
+		return nil, err
+	}
+	schema := &protobufSchema{}
+	if schema.key, err = protobufFieldsForColumns(keyIt); err != nil {
+		return nil, err
+	}
+	if schema.row, err = protobufFieldsForColumns(row.ForEachColumn()); err != nil {
+		return nil, err
+	}
+
+	name, err := e.rawTableName(row.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	fd := makeProtobufFileDescriptor(name, row.Version, schema)
+	path := fmt.Sprintf("%s/v%d.binpb", SQLNameToKafkaName(name), row.Version)
+	if err := e.writeDescriptor(ctx, path, fd); err != nil {
+		return nil, err
+	}
+
+	e.schemaCache.Add(cacheKey, schema)
+	return schema, nil
+}
+
+// prevRowFields returns the fields used to encode the previous value of a row.
+func (e *protobufEncoder) prevRowFields(prevRow cdcevent.Row) ([]protobufField, error) {
+	cacheKey := tableIDAndVersion{tableID: prevRow.TableID, version: prevRow.Version, familyID: prevRow.FamilyID}
+	if v, ok := e.prevCache.Get(cacheKey); ok {
+		return v.([]protobufField), nil
+	}
+	fields, err := protobufFieldsForColumns(prevRow.ForEachColumn())
+	if err != nil {
+		return nil, err
+	}
+	e.prevCache.Add(cacheKey, fields)
+	return fields, nil
+}
+
+func (e *protobufEncoder) rawTableName(eventMeta cdcevent.Metadata) (string, error) {
+	return targetNameForEvent(e.targets, eventMeta, `` /* prefix */)
+}
+
+// writeDescriptor validates the given file descriptor and writes it, along
+// with its dependencies, as a serialized FileDescriptorSet to the file with
+// the given name in the descriptor location. Descriptors are written every
+// time an encoder sees a new version, so the same file may be written several
+// times, always with the same contents.
+func (e *protobufEncoder) writeDescriptor(
+	ctx context.Context, filename string, fd *descriptorpb.FileDescriptorProto,
+) error {
+	if _, err := protodesc.NewFile(fd, protoregistry.GlobalFiles); err != nil {
+		return errors.NewAssertionErrorWithWrappedErrf(err, "invalid protobuf descriptor for %s", filename)
+	}
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto),
+		fd,
+	}}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(set)
+	if err != nil {
+		return err
+	}
+
+	es, err := e.makeExternalStorage(ctx, e.descriptorURI, e.user)
+	if err != nil {
+		return err
+	}
+	defer es.Close()
+	log.VEventf(ctx, 2, "writing protobuf descriptor %s", filename)
+	return errors.Wrapf(cloud.WriteFile(ctx, es, filename, bytes.NewReader(data)),
+		"writing protobuf descriptor %s", filename)
+}
+
+// appendNestedMessage appends the given columns to b as a message in the field
+// with the given number.
+func (e *protobufEncoder) appendNestedMessage(
+	b []byte, num protowire.Number, fields []protobufField, it cdcevent.Iterator,
+) ([]byte, error) {
+	msg, err := e.appendMessage(e.scratch[:0], fields, it)
+	if err != nil {
+		return nil, err
+	}
+	e.scratch = msg
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg), nil
+}
+
+// appendMessage appends the given columns to b as the fields of a message.
+// NULL values are omitted.
+func (e *protobufEncoder) appendMessage(
+	b []byte, fields []protobufField, it cdcevent.Iterator,
+) ([]byte, error) {
+	i := 0
+	if err := it.Datum(func(d tree.Datum, col cdcevent.ResultColumn) error {
+		if i >= len(fields) {
+			return errors.AssertionFailedf("unexpected column %q", col.Name)
+		}
+		f := fields[i]
+		i++
+		if d == tree.DNull {
+			return nil
+		}
+		b = e.appendField(b, f, d)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if b == nil {
+		b = []byte{}
+	}
+	return b, nil
+}
+
+func (e *protobufEncoder) appendField(b []byte, f protobufField, d tree.Datum) []byte {
+	switch f.kind {
+	case protobufBool:
+		b = protowire.AppendTag(b, f.number, protowire.VarintType)
+		return protowire.AppendVarint(b, protowire.EncodeBool(bool(tree.MustBeDBool(d))))
+	case protobufInt64:
+		b = protowire.AppendTag(b, f.number, protowire.VarintType)
+		return protowire.AppendVarint(b, uint64(tree.MustBeDInt(d)))
+	case protobufDouble:
+		b = protowire.AppendTag(b, f.number, protowire.Fixed64Type)
+		return protowire.AppendFixed64(b, math.Float64bits(float64(tree.MustBeDFloat(d))))
+	case protobufBytes:
+		b = protowire.AppendTag(b, f.number, protowire.BytesType)
+		return protowire.AppendString(b, string(tree.MustBeDBytes(d)))
+	case protobufTimestamp:
+		var seconds int64
+		var nanos int32
+		switch t := tree.UnwrapDOidWrapper(d).(type) {
+		case *tree.DTimestamp:
+			seconds, nanos = t.Unix(), int32(t.Nanosecond())
+		case *tree.DTimestampTZ:
+			seconds, nanos = t.Unix(), int32(t.Nanosecond())
+		}
+		var ts []byte
+		if seconds != 0 {
+			ts = protowire.AppendTag(ts, 1, protowire.VarintType)
+			ts = protowire.AppendVarint(ts, uint64(seconds))
+		}
+		if nanos != 0 {
+			ts = protowire.AppendTag(ts, 2, protowire.VarintType)
+			ts = protowire.AppendVarint(ts, uint64(nanos))
+		}
+		b = protowire.AppendTag(b, f.number, protowire.BytesType)
+		return protowire.AppendBytes(b, ts)
+	default:
+		b = protowire.AppendTag(b, f.number, protowire.BytesType)
+		if cs, ok := d.(*tree.DCollatedString); ok {
+			return protowire.AppendString(b, cs.Contents)
+		}
+		e.formatter.Reset()
+		e.formatter.FormatNode(d)
+		return protowire.AppendString(b, e.formatter.String())
+	}
+}
+
+// protobufKindForType returns the protobuf type used to encode values of the
+// given SQL type. Types without a natural protobuf counterpart are encoded as
+// strings, using the same representation as the CSV format.
+func protobufKindForType(typ *types.T) protobufKind {
+	switch typ.Family() {
+	case types.BoolFamily:
+		return protobufBool
+	case types.IntFamily:
+		return protobufInt64
+	case types.FloatFamily:
+		return protobufDouble
+	case types.BytesFamily:
+		return protobufBytes
+	case types.TimestampFamily, types.TimestampTZFamily:
+		return protobufTimestamp
+	default:
+		return protobufString
+	}
+}
+
+// protobufFieldsForColumns returns the fields of a message encoding the given
+// columns. Fields are numbered by column ID so that numbers are stable across
+// table versions. When that is not possible, which can happen for CDC queries
+// that project expressions, fields are numbered by position instead.
+func protobufFieldsForColumns(it cdcevent.Iterator) ([]protobufField, error) {
+	var fields []protobufField
+	byColumnID := true
+	seen := make(map[protowire.Number]struct{})
+	if err := it.Col(func(col cdcevent.ResultColumn) error {
+		num := protowire.Number(col.PGAttributeNum)
+		if _, dup := seen[num]; dup || !num.IsValid() {
+			byColumnID = false
+		}
+		seen[num] = struct{}{}
+		fields = append(fields, protobufField{
+			name:   SQLNameToAvroName(col.Name),
+			number: num,
+			kind:   protobufKindForType(col.Typ),
+		})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if !byColumnID {
+		for i := range fields {
+			fields[i].number = protowire.Number(i + 1)
+		}
+	}
+	return fields, nil
+}
+
+// makeProtobufFileDescriptor returns the file descriptor describing the Key,
+// Row and Envelope messages of the given version of a table. Messages use
+// proto2 syntax: it gives every field explicit presence, which distinguishes
+// NULLs from zero values, and unlike proto3 it allows field names that only
+// differ by case, as SQL column names can.
+func makeProtobufFileDescriptor(
+	tableName string, version descpb.DescriptorVersion, schema *protobufSchema,
+) *descriptorpb.FileDescriptorProto {
+	escaped := SQLNameToAvroName(tableName)
+	pkg := fmt.Sprintf("%s.%s.v%d", protobufPackage, escaped, version)
+	messageDescriptor := func(name string, fields []protobufField) *descriptorpb.DescriptorProto {
+		m := &descriptorpb.DescriptorProto{Name: proto.String(name)}
+		for _, f := range fields {
+			var fdp *descriptorpb.FieldDescriptorProto
+			switch f.kind {
+			case protobufBool:
+				fdp = protobufScalarFieldDescriptor(f.name, f.number, descriptorpb.FieldDescriptorProto_TYPE_BOOL)
+			case protobufInt64:
+				fdp = protobufScalarFieldDescriptor(f.name, f.number, descriptorpb.FieldDescriptorProto_TYPE_INT64)
+			case protobufDouble:
+				fdp = protobufScalarFieldDescriptor(f.name, f.number, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE)
+			case protobufBytes:
+				fdp = protobufScalarFieldDescriptor(f.name, f.number, descriptorpb.FieldDescriptorProto_TYPE_BYTES)
+			case protobufTimestamp:
+				fdp = protobufMessageFieldDescriptor(f.name, f.number, `.google.protobuf.Timestamp`)
+			default:
+				fdp = protobufScalarFieldDescriptor(f.name, f.number, descriptorpb.FieldDescriptorProto_TYPE_STRING)
+			}
+			m.Field = append(m.Field, fdp)
+		}
+		return m
+	}
+	envelope := &descriptorpb.DescriptorProto{
+		Name: proto.String(protobufEnvelopeMessage),
+		Field: []*descriptorpb.FieldDescriptorProto{
+			protobufMessageFieldDescriptor(`after`, protobufEnvelopeAfter, `.`+pkg+`.`+protobufRowMessage),
+			protobufMessageFieldDescriptor(`before`, protobufEnvelopeBefore, `.`+pkg+`.`+protobufRowMessage),
+			protobufScalarFieldDescriptor(`updated`, protobufEnvelopeUpdated, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+			protobufScalarFieldDescriptor(`mvcc_timestamp`, protobufEnvelopeMVCCTimestamp, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+			protobufMessageFieldDescriptor(`key`, protobufEnvelopeKey, `.`+pkg+`.`+protobufKeyMessage),
+			protobufScalarFieldDescriptor(`topic`, protobufEnvelopeTopic, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+		},
+	}
+	return &descriptorpb.FileDescriptorProto{
+		Name:       proto.String(fmt.Sprintf("cockroachdb/changefeed/%s/v%d.proto", escaped, version)),
+		Package:    proto.String(pkg),
+		Syntax:     proto.String(`proto2`),
+		Dependency: []string{timestamppb.File_google_protobuf_timestamp_proto.Path()},
+		MessageType: []*descriptorpb.DescriptorProto{
+			messageDescriptor(protobufKeyMessage, schema.key),
+			messageDescriptor(protobufRowMessage, schema.row),
+			envelope,
+		},
+	}
+}
+
+func protobufScalarFieldDescriptor(
+	name string, num protowire.Number, typ descriptorpb.FieldDescriptorProto_Type,
+) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(int32(num)),
+		Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:   typ.Enum(),
+	}
+}
+
+func protobufMessageFieldDescriptor(
+	name string, num protowire.Number, typeName string,
+) *descriptorpb.FieldDescriptorProto {
+	fd := protobufScalarFieldDescriptor(name, num, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE)
+	fd.TypeName = proto.String(typeName)
+	return fd
+}
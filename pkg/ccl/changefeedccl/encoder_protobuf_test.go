@@ -0,0 +1,212 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/blobs"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestProtobufEncoder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	dir, dirCleanupFn := testutils.TempDir(t)
+	defer dirCleanupFn()
+
+	settings := cluster.MakeTestingClusterSettings()
+	settings.ExternalIODir = dir
+	clientFactory := blobs.TestBlobServiceClient(dir)
+	externalStorageFromURI := func(
+		ctx context.Context, uri string, user username.SQLUsername, opts ...cloud.ExternalStorageOption,
+	) (cloud.ExternalStorage, error) {
+		return cloud.ExternalStorageFromURI(ctx, uri, base.ExternalIODirConfig{}, settings,
+			clientFactory,
+			user,
+			nil, /* db */
+			nil, /* limiters */
+			cloud.NilMetrics,
+			opts...)
+	}
+
+	// Column names that only differ by case are valid in SQL, and must be
+	// valid in the generated messages.
+	tableDesc, err := parseTableDesc(
+		`CREATE TABLE foo (a INT PRIMARY KEY, b STRING, "B" BOOL, c FLOAT, d BYTES, e TIMESTAMPTZ, f DECIMAL)`)
+	require.NoError(t, err)
+	targets := mkTargets(tableDesc)
+
+	row := rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.NewDString(`bar`)},
+		rowenc.EncDatum{Datum: tree.DBoolTrue},
+		rowenc.EncDatum{Datum: tree.NewDFloat(1.5)},
+		rowenc.EncDatum{Datum: tree.NewDBytes(`baz`)},
+		rowenc.EncDatum{Datum: tree.MustMakeDTimestampTZ(timeutil.Unix(1700000000, 123000), 0)},
+		rowenc.EncDatum{Datum: tree.DNull},
+	}
+	prevRow := rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.DNull},
+		rowenc.EncDatum{Datum: tree.DBoolFalse},
+		rowenc.EncDatum{Datum: tree.DNull},
+		rowenc.EncDatum{Datum: tree.DNull},
+		rowenc.EncDatum{Datum: tree.DNull},
+		rowenc.EncDatum{Datum: &tree.DDecimal{}},
+	}
+	evCtx := eventContext{
+		updated: hlc.Timestamp{WallTime: 1, Logical: 2},
+		mvcc:    hlc.Timestamp{WallTime: 1},
+		topic:   `foo`,
+	}
+
+	// decode decodes msg as the named message using the descriptor set in the
+	// given file, and returns its JSON representation.
+	decode := func(t *testing.T, file string, name protoreflect.FullName, msg []byte) string {
+		data, err := os.ReadFile(filepath.Join(dir, `descriptors`, file))
+		require.NoError(t, err)
+		var set descriptorpb.FileDescriptorSet
+		require.NoError(t, proto.Unmarshal(data, &set))
+		files, err := protodesc.NewFiles(&set)
+		require.NoError(t, err)
+		desc, err := files.FindDescriptorByName(name)
+		require.NoError(t, err)
+		m := dynamicpb.NewMessage(desc.(protoreflect.MessageDescriptor))
+		require.NoError(t, proto.Unmarshal(msg, m))
+		j, err := protojson.Marshal(m)
+		require.NoError(t, err)
+		return string(normalizeJson(t, j))
+	}
+
+	const pkg = `cockroachdb.changefeed.foo.v1.`
+	makeEncoder := func(t *testing.T, opts changefeedbase.EncodingOptions) Encoder {
+		opts.Format = changefeedbase.OptFormatProtobuf
+		opts.ProtobufDescriptorURI = `nodelocal://1/descriptors`
+		require.NoError(t, opts.Validate())
+		e, err := getEncoder(ctx, opts, targets, false, nil, externalStorageFromURI, username.RootUserName(), nil)
+		require.NoError(t, err)
+		return e
+	}
+
+	t.Run("wrapped", func(t *testing.T) {
+		e := makeEncoder(t, changefeedbase.EncodingOptions{
+			Envelope:          changefeedbase.OptEnvelopeWrapped,
+			Diff:              true,
+			UpdatedTimestamps: true,
+			MVCCTimestamps:    true,
+			KeyInValue:        true,
+			TopicInValue:      true,
+		})
+		updated := cdcevent.TestingMakeEventRow(tableDesc, 0, row, false)
+		prev := cdcevent.TestingMakeEventRow(tableDesc, 0, prevRow, false)
+
+		key, err := e.EncodeKey(ctx, updated)
+		require.NoError(t, err)
+		require.Equal(t, `{"a":"1"}`, decode(t, `foo/v1.binpb`, pkg+`Key`, key))
+
+		value, err := e.EncodeValue(ctx, evCtx, updated, prev)
+		require.NoError(t, err)
+		require.Equal(t, string(normalizeJson(t, []byte(`{
+			"after": {"a": "1", "b": "bar", "B": true, "c": 1.5, "d": "YmF6", "e": "2023-11-14T22:13:20.000123Z"},
+			"before": {"a": "1", "B": false, "f": "0"},
+			"updated": "1.0000000002",
+			"mvccTimestamp": "1.0000000000",
+			"key": {"a": "1"},
+			"topic": "foo"
+		}`))), decode(t, `foo/v1.binpb`, pkg+`Envelope`, value))
+
+		deleted := cdcevent.TestingMakeEventRow(tableDesc, 0, row, true)
+		value, err = e.EncodeValue(ctx, evCtx, deleted, updated)
+		require.NoError(t, err)
+		require.Equal(t, string(normalizeJson(t, []byte(`{
+			"before": {"a": "1", "b": "bar", "B": true, "c": 1.5, "d": "YmF6", "e": "2023-11-14T22:13:20.000123Z"},
+			"updated": "1.0000000002",
+			"mvccTimestamp": "1.0000000000",
+			"key": {"a": "1"},
+			"topic": "foo"
+		}`))), decode(t, `foo/v1.binpb`, pkg+`Envelope`, value))
+
+		resolved, err := e.EncodeResolvedTimestamp(ctx, `foo`, hlc.Timestamp{WallTime: 3})
+		require.NoError(t, err)
+		require.Equal(t, `{"resolved":"3.0000000000"}`,
+			decode(t, `resolved.binpb`, `cockroachdb.changefeed.Resolved`, resolved))
+	})
+
+	t.Run("bare", func(t *testing.T) {
+		e := makeEncoder(t, changefeedbase.EncodingOptions{Envelope: changefeedbase.OptEnvelopeBare})
+		value, err := e.EncodeValue(ctx, evCtx, cdcevent.TestingMakeEventRow(tableDesc, 0, prevRow, false), cdcevent.Row{})
+		require.NoError(t, err)
+		require.Equal(t, `{"B":false,"a":"1","f":"0"}`, decode(t, `foo/v1.binpb`, pkg+`Row`, value))
+
+		// Deletes are encoded as tombstones.
+		value, err = e.EncodeValue(ctx, evCtx, cdcevent.TestingMakeEventRow(tableDesc, 0, row, true), cdcevent.Row{})
+		require.NoError(t, err)
+		require.Nil(t, value)
+	})
+
+	t.Run("schema change", func(t *testing.T) {
+		e := makeEncoder(t, changefeedbase.EncodingOptions{Envelope: changefeedbase.OptEnvelopeBare})
+		newDesc, err := parseTableDesc(
+			`CREATE TABLE foo (a INT PRIMARY KEY, b STRING, "B" BOOL, c FLOAT, d BYTES, e TIMESTAMPTZ, f DECIMAL, g INT)`)
+		require.NoError(t, err)
+		newDesc.(*tabledesc.Mutable).Version = 2
+		newRow := append(rowenc.EncDatumRow{}, prevRow...)
+		newRow = append(newRow, rowenc.EncDatum{Datum: tree.NewDInt(7)})
+
+		oldValue, err := e.EncodeValue(ctx, evCtx, cdcevent.TestingMakeEventRow(tableDesc, 0, prevRow, false), cdcevent.Row{})
+		require.NoError(t, err)
+		newValue, err := e.EncodeValue(ctx, evCtx, cdcevent.TestingMakeEventRow(newDesc, 0, newRow, false), cdcevent.Row{})
+		require.NoError(t, err)
+
+		// Each version has its own descriptor, and the descriptor of the new
+		// version can decode messages encoded with the old one.
+		const newPkg = `cockroachdb.changefeed.foo.v2.`
+		require.Equal(t, `{"B":false,"a":"1","f":"0","g":"7"}`, decode(t, `foo/v2.binpb`, newPkg+`Row`, newValue))
+		require.Equal(t, `{"B":false,"a":"1","f":"0"}`, decode(t, `foo/v2.binpb`, newPkg+`Row`, oldValue))
+	})
+
+	t.Run("options", func(t *testing.T) {
+		opts := changefeedbase.EncodingOptions{
+			Format:   changefeedbase.OptFormatProtobuf,
+			Envelope: changefeedbase.OptEnvelopeWrapped,
+		}
+		_, err := getEncoder(ctx, opts, targets, false, nil, externalStorageFromURI, username.RootUserName(), nil)
+		require.ErrorContains(t, err, `WITH option protobuf_descriptor_uri is required for format=protobuf`)
+
+		opts.Format = changefeedbase.OptFormatJSON
+		opts.ProtobufDescriptorURI = `nodelocal://1/descriptors`
+		require.ErrorContains(t, opts.Validate(), `protobuf_descriptor_uri is only usable with format=protobuf`)
+	})
+}
@@ -104,7 +104,7 @@ func newEventConsumer(
 	makeConsumer := func(s EventSink, frontier frontier) (eventConsumer, error) {
 		var err error
 		encoder, err := getEncoder(ctx, encodingOpts, feed.Targets, spec.Select.Expr != "",
-			makeExternalConnectionProvider(ctx, cfg.DB), sliMetrics)
+			makeExternalConnectionProvider(ctx, cfg.DB), cfg.ExternalStorageFromURI, spec.User(), sliMetrics)
 		if err != nil {
 			return nil, err
 		}
@@ -223,7 +223,13 @@ func newKVEventToRowConsumer(
 ) (_ *kvEventToRowConsumer, err error) {
 	includeVirtual := details.Opts.IncludeVirtual()
 	keyOnly := details.Opts.KeyOnly()
-	decoder, err := cdcevent.NewEventDecoder(ctx, cfg, details.Targets, includeVirtual, keyOnly)
+	newDecoder := cdcevent.NewEventDecoder
+	if spec.FilterSpec != nil && len(spec.FilterSpec.ColumnIDs) > 0 {
+		// The rangefeed servers only send the columns referenced by the select
+		// clause.
+		newDecoder = cdcevent.NewProjectedEventDecoder
+	}
+	decoder, err := newDecoder(ctx, cfg, details.Targets, includeVirtual, keyOnly)
 	if err != nil {
 		return nil, err
 	}
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
@@ -91,6 +92,11 @@ type Config struct {
 	// enables filtering out any transactional writes with that flag set to true.
 	WithFiltering bool
 
+	// FilterSpec, if set, is propagated via the RangefeedRequest to the
+	// rangefeed server, which does not emit the values that do not satisfy it.
+	// It is not applied to scans.
+	FilterSpec *kvpb.RangeFeedFilterSpec
+
 	// Knobs are kvfeed testing knobs.
 	Knobs TestingKnobs
 }
@@ -128,6 +134,7 @@ func Run(ctx context.Context, cfg Config) error {
 		cfg.SchemaFeed,
 		sc, pff, bf, cfg.Targets, cfg.Knobs)
 	f.onBackfillCallback = cfg.MonitoringCfg.OnBackfillCallback
+	f.filterSpec = cfg.FilterSpec
 	f.rangeObserver = startLaggingRangesObserver(g, cfg.MonitoringCfg.LaggingRangesCallback,
 		cfg.MonitoringCfg.LaggingRangesPollingInterval, cfg.MonitoringCfg.LaggingRangesThreshold)
 
@@ -244,6 +251,7 @@ type kvFeed struct {
 	checkpointTimestamp hlc.Timestamp
 	withDiff            bool
 	withFiltering       bool
+	filterSpec          *kvpb.RangeFeedFilterSpec
 	withInitialBackfill bool
 	initialHighWater    hlc.Timestamp
 	endTime             hlc.Timestamp
@@ -563,6 +571,7 @@ func (f *kvFeed) runUntilTableEvent(ctx context.Context, resumeFrontier span.Fro
 		Frontier:      resumeFrontier.Frontier(),
 		WithDiff:      f.withDiff,
 		WithFiltering: f.withFiltering,
+		FilterSpec:    f.filterSpec,
 		Knobs:         f.knobs,
 		RangeObserver: f.rangeObserver,
 	}
@@ -33,6 +33,7 @@ type rangeFeedConfig struct {
 	Spans         []kvcoord.SpanTimePair
 	WithDiff      bool
 	WithFiltering bool
+	FilterSpec    *kvpb.RangeFeedFilterSpec
 	RangeObserver func(fn kvcoord.ForEachRangeFn)
 	Knobs         TestingKnobs
 }
@@ -92,6 +93,9 @@ func (p rangefeedFactory) Run(ctx context.Context, sink kvevent.Writer, cfg rang
 	if cfg.WithFiltering {
 		rfOpts = append(rfOpts, kvcoord.WithFiltering())
 	}
+	if cfg.FilterSpec != nil {
+		rfOpts = append(rfOpts, kvcoord.WithFilterSpec(cfg.FilterSpec))
+	}
 	if cfg.RangeObserver != nil {
 		rfOpts = append(rfOpts, kvcoord.WithRangeObserver(cfg.RangeObserver))
 	}
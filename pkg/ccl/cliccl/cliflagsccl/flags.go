@@ -20,20 +20,32 @@ stores exist, the flag must be specified for each store.
 
 A valid enterprise license is required to use this functionality.
 
-Key files should be generated by "cockroach gen encryption-key".
+Key files should be generated by "cockroach gen encryption-key". Instead of a
+key file, a key can be given as the URI of a KMS master key (using the same
+URI formats as encrypted backups), in which case the store generates its own
+key and only keeps it on disk encrypted by the KMS. The KMS must be reachable
+whenever the node starts: the key is never stored unencrypted, so a node that
+cannot reach the KMS within kms-timeout fails to start. Once started, the node
+does not need the KMS until its next restart.
 
 Valid fields:
 
 * path    (required): must match the path of one of the stores, or the special
                       value "*" to match all stores
-* key     (required): path to the current key file, or "plain"
-* old-key (required): path to the previous key file, or "plain"
+* key     (required): path to the current key file, KMS URI, or "plain"
+* old-key (required): path to the previous key file, KMS URI, or "plain"
 * rotation-period   : amount of time after which data keys should be rotated
+* kms-timeout       : how long to keep retrying to reach the KMS on startup
+                      before giving up (default 1m)
+* cipher            : the cipher of the key generated for a KMS master key, one
+                      of aes-128-ctr, aes-192-ctr, aes-256-ctr, aes-128-gcm,
+                      aes-192-gcm, or aes-256-gcm (default aes-256-ctr)
 
 </PRE>
 example:
 <PRE>
-  --enterprise-encryption=path=cockroach-data,key=/keys/aes-128.key,old-key=plain</PRE>
+  --enterprise-encryption=path=cockroach-data,key=/keys/aes-128.key,old-key=plain
+  --enterprise-encryption=path=cockroach-data,key=aws-kms:///arn:aws:kms:us-east-1:123456789012:key/my-key?AUTH=implicit&REGION=us-east-1,old-key=/keys/aes-128.key</PRE>
 `,
 	}
 )
@@ -38,6 +38,10 @@ func setProxyContextDefaults() {
 	proxyContext.ThrottleBaseDelay = time.Second
 	proxyContext.DisableConnectionRebalancing = false
 	proxyContext.RequireProxyProtocol = false
+	proxyContext.TransactionPooling = false
+	proxyContext.ConnPoolMaxIdleConns = 100
+	proxyContext.ConnPoolIdleTimeout = 5 * time.Minute
+	proxyContext.ConnPoolReleaseDelay = 10 * time.Millisecond
 }
 
 var testDirectorySvrContext struct {
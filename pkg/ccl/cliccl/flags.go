@@ -82,6 +82,10 @@ func init() {
 		cliflagcfg.DurationFlag(f, &proxyContext.ThrottleBaseDelay, cliflags.ThrottleBaseDelay)
 		cliflagcfg.BoolFlag(f, &proxyContext.DisableConnectionRebalancing, cliflags.DisableConnectionRebalancing)
 		cliflagcfg.BoolFlag(f, &proxyContext.RequireProxyProtocol, cliflags.RequireProxyProtocol)
+		cliflagcfg.BoolFlag(f, &proxyContext.TransactionPooling, cliflags.TransactionPooling)
+		cliflagcfg.IntFlag(f, &proxyContext.ConnPoolMaxIdleConns, cliflags.ConnPoolMaxIdleConns)
+		cliflagcfg.DurationFlag(f, &proxyContext.ConnPoolIdleTimeout, cliflags.ConnPoolIdleTimeout)
+		cliflagcfg.DurationFlag(f, &proxyContext.ConnPoolReleaseDelay, cliflags.ConnPoolReleaseDelay)
 	}
 
 	// Multi-tenancy test directory command flags.
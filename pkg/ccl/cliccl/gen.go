@@ -48,15 +48,24 @@ func genEncryptionKey(
 	case 1:
 		b = append(b, keyID...)
 		b = append(b, key...)
-	case 2:
+	case 2, 3:
 		var et enginepbccl.EncryptionType
 		switch aesSize {
 		case 128:
 			et = enginepbccl.EncryptionType_AES_128_CTR_V2
+			if keyVersion == 3 {
+				et = enginepbccl.EncryptionType_AES_128_GCM
+			}
 		case 192:
 			et = enginepbccl.EncryptionType_AES_192_CTR_V2
+			if keyVersion == 3 {
+				et = enginepbccl.EncryptionType_AES_192_GCM
+			}
 		case 256:
 			et = enginepbccl.EncryptionType_AES_256_CTR_V2
+			if keyVersion == 3 {
+				et = enginepbccl.EncryptionType_AES_256_GCM
+			}
 		default:
 			// Redundant since we checked this at the start of the function too.
 			return fmt.Errorf("store key size should be 128, 192, or 256 bits, got %d", aesSize)
@@ -114,6 +123,9 @@ var genEncryptionKeyCmd = &cobra.Command{
 
 Generates a key suitable for use as a store key for Encryption At Rest.
 The resulting key file will be 32 bytes (random key ID) + key_size in bytes.
+
+With --version=2 or 3, the key is written in JWK format instead. Version 3 keys
+use AES-GCM, which detects tampering with or corruption of the encrypted data.
 `,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -138,5 +150,5 @@ func init() {
 	genEncryptionKeyCmd.PersistentFlags().BoolVar(&overwriteKeyFlag, "overwrite", false,
 		"Overwrite key if it exists")
 	genEncryptionKeyCmd.PersistentFlags().IntVar(&keyVersionFlag, "version", 1,
-		"Encryption format version (1 or 2)")
+		"Encryption format version (1, 2, or 3)")
 }
@@ -28,7 +28,7 @@ func TestGenEncryptionKey(t *testing.T) {
 
 	dir := t.TempDir()
 
-	for _, keyVersion := range []int{1, 2} {
+	for _, keyVersion := range []int{1, 2, 3} {
 		for _, keySize := range []int{128, 192, 256} {
 			t.Run(fmt.Sprintf("version=%d/size=%d", keyVersion, keySize), func(t *testing.T) {
 				keyName := fmt.Sprintf("aes-%d-v%d.key", keySize, keyVersion)
@@ -49,6 +49,7 @@ func TestGenEncryptionKey(t *testing.T) {
 				assert.EqualValues(t, keySize/8, len(key.Key))
 				// Key ID is hex encoded on load so it's 64 bytes here but 32 in the file size.
 				assert.EqualValues(t, 64, len(key.Info.KeyId))
+				assert.Equal(t, keyVersion == 3, key.Info.EncryptionType.IsAuthenticated())
 
 				err = genEncryptionKey(keyPath, keySize, false, keyVersion)
 				require.ErrorContains(t, err, fmt.Sprintf("%s: file exists", keyName))
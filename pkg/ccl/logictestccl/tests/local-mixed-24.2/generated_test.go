@@ -99,6 +99,13 @@ func TestCCLLogic_hash_sharded_index_read_committed(
 	runCCLLogicTest(t, "hash_sharded_index_read_committed")
 }
 
+func TestCCLLogic_mixed_version_triggers(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runCCLLogicTest(t, "mixed_version_triggers")
+}
+
 func TestCCLLogic_nested_routines(
 	t *testing.T,
 ) {
@@ -239,13 +246,6 @@ func TestCCLLogic_subject(
 	runCCLLogicTest(t, "subject")
 }
 
-func TestCCLLogic_triggers(
-	t *testing.T,
-) {
-	defer leaktest.AfterTest(t)()
-	runCCLLogicTest(t, "triggers")
-}
-
 func TestCCLLogic_udf_params(
 	t *testing.T,
 ) {
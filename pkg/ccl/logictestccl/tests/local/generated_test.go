@@ -379,6 +379,13 @@ func TestCCLLogic_udf_plpgsql(
 	runCCLLogicTest(t, "udf_plpgsql")
 }
 
+func TestCCLLogic_udf_plpgsql_variadic(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runCCLLogicTest(t, "udf_plpgsql_variadic")
+}
+
 func TestCCLLogic_udf_rewrite(
 	t *testing.T,
 ) {
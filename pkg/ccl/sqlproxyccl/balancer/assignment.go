@@ -12,12 +12,16 @@ import (
 	"sync"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 )
 
 // ServerAssignment represents an assignment to a SQL pod.
 type ServerAssignment struct {
-	owner   ConnectionHandle
-	addr    string
+	addr string
+	mu   struct {
+		syncutil.Mutex
+		owner ConnectionHandle
+	}
 	onClose struct {
 		sync.Once
 		closerFn func()
@@ -29,7 +33,8 @@ type ServerAssignment struct {
 func NewServerAssignment(
 	tenantID roachpb.TenantID, tracker *ConnTracker, owner ConnectionHandle, addr string,
 ) *ServerAssignment {
-	sa := &ServerAssignment{owner: owner, addr: addr}
+	sa := &ServerAssignment{addr: addr}
+	sa.mu.owner = owner
 	sa.onClose.closerFn = func() {
 		// Since closerFn is used within Close, operations in closerFn should
 		// not invoke Close on the server assignment, or else a cyclic call may
@@ -44,7 +49,20 @@ func NewServerAssignment(
 // The connection handle may not be initialized yet, so callers will need to
 // check for that where necessary.
 func (sa *ServerAssignment) Owner() ConnectionHandle {
-	return sa.owner
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+	return sa.mu.owner
+}
+
+// SetOwner updates the connection handle associated with the server
+// assignment. This is used when the server connection is handed over to a
+// different client connection (e.g. through transaction pooling). A nil owner
+// indicates that the server connection is not used by any client connection,
+// and the assignment will not be selected for rebalancing.
+func (sa *ServerAssignment) SetOwner(owner ConnectionHandle) {
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+	sa.mu.owner = owner
 }
 
 // Addr returns the address of the server assignment.
@@ -37,6 +37,18 @@ func TestServerAssignment(t *testing.T) {
 		sa.Addr(): {handle},
 	}, tracker.GetConnsMap(tenantID))
 
+	// Assignments without an owner are not returned.
+	sa.SetOwner(nil)
+	require.Nil(t, sa.Owner())
+	require.Empty(t, tracker.GetConnsMap(tenantID))
+
+	otherHandle := &testConnHandle{}
+	sa.SetOwner(otherHandle)
+	require.Equal(t, otherHandle, sa.Owner())
+	require.Equal(t, map[string][]ConnectionHandle{
+		sa.Addr(): {otherHandle},
+	}, tracker.GetConnsMap(tenantID))
+
 	// Once Close gets invoked, assignments should be empty.
 	sa.Close()
 	require.Empty(t, tracker.GetConnsMap(tenantID))
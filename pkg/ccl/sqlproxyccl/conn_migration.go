@@ -87,6 +87,12 @@ func (f *forwarder) tryBeginTransfer() (started bool, cleanupFn func()) {
 		return false, nil
 	}
 
+	// The forwarder has released its server connection to the pool, so there
+	// is nothing to transfer.
+	if f.mu.detached != nil {
+		return false, nil
+	}
+
 	request, response := f.mu.request, f.mu.response
 	request.mu.Lock()
 	response.mu.Lock()
@@ -81,6 +81,16 @@ func TestForwarder_tryBeginTransfer(t *testing.T) {
 		require.Nil(t, cleanupFn)
 	})
 
+	t.Run("detached", func(t *testing.T) {
+		f := &forwarder{}
+		f.mu.isInitialized = true
+		f.mu.detached = &detachedSession{}
+
+		started, cleanupFn := f.tryBeginTransfer()
+		require.False(t, started)
+		require.Nil(t, cleanupFn)
+	})
+
 	t.Run("isSafeTransferPointLocked=false", func(t *testing.T) {
 		defer testutils.TestingHook(&isSafeTransferPointLocked,
 			func(req *processor, res *processor) bool {
@@ -0,0 +1,612 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package sqlproxyccl
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/sqlproxyccl/balancer"
+	"github.com/cockroachdb/cockroach/pkg/ccl/sqlproxyccl/interceptor"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
+	pgproto3 "github.com/jackc/pgproto3/v2"
+)
+
+// Transaction pooling
+//
+// With transaction pooling, a client connection only holds on to a server
+// connection for the duration of a transaction. Once a transaction ends and
+// the client connection has been idle for the release delay, the forwarder
+// serializes the session through SHOW TRANSFER STATE (as done for connection
+// migration), resets the session on the server connection through DISCARD
+// ALL, and releases the server connection to a pool that is shared by all
+// client connections of the same tenant and user. The forwarder is then
+// "detached". When the client sends its next message, the forwarder acquires
+// an idle server connection from the pool (or opens a new one through the
+// session revival token if there are none), deserializes the session into it,
+// and resumes forwarding messages.
+//
+// The balancer assignment of a server connection follows the connection: it
+// has no owner while the connection is idle in the pool, so the rebalancer
+// will not attempt to transfer it, and is owned by the forwarder that
+// acquired it otherwise.
+//
+// Limitations:
+//   - Sessions which cannot be serialized (e.g. sessions with temporary
+//     tables) are pinned to their server connection until they are closed.
+//   - Session revival tokens expire, so a client connection that has been
+//     detached for longer than the token lifetime will be closed if the pool
+//     has no idle server connections for it.
+
+// txnStatusIdle is the transaction status in ReadyForQuery messages which
+// indicates that the session is not in a transaction block.
+const txnStatusIdle = 'I'
+
+// poolKey identifies the server connections that can be shared by client
+// connections. Sessions can only be deserialized into server connections that
+// were authenticated as the same user.
+type poolKey struct {
+	tenantID roachpb.TenantID
+	user     string
+}
+
+// pooledConn is an idle server connection in the pool.
+type pooledConn struct {
+	conn *interceptor.PGConn
+	// backendKeyData is the cancel key of the server connection.
+	backendKeyData *pgproto3.BackendKeyData
+	// releasedAt is the time at which the connection was released to the pool.
+	releasedAt time.Time
+}
+
+// connPool holds idle server connections which can be used by the forwarders
+// of the same tenant and user. All methods on connPool are thread-safe.
+type connPool struct {
+	// maxIdleConns is the maximum number of idle server connections per key.
+	// Once reached, the oldest idle connection will be closed on release.
+	maxIdleConns int
+	// idleTimeout is the duration after which idle server connections will be
+	// closed.
+	idleTimeout time.Duration
+	// releaseDelay is the duration that forwarders will wait for after the end
+	// of a transaction before releasing their server connection.
+	releaseDelay time.Duration
+
+	metrics    *metrics
+	timeSource timeutil.TimeSource
+
+	mu struct {
+		syncutil.Mutex
+		// idle contains the idle server connections of each key, ordered by the
+		// time they were released.
+		idle map[poolKey][]*pooledConn
+	}
+}
+
+// newConnPool returns a new instance of connPool. If timeSource is nil,
+// timeutil.DefaultTimeSource will be used.
+func newConnPool(
+	metrics *metrics,
+	timeSource timeutil.TimeSource,
+	maxIdleConns int,
+	idleTimeout time.Duration,
+	releaseDelay time.Duration,
+) *connPool {
+	if timeSource == nil {
+		timeSource = timeutil.DefaultTimeSource{}
+	}
+	p := &connPool{
+		maxIdleConns: maxIdleConns,
+		idleTimeout:  idleTimeout,
+		releaseDelay: releaseDelay,
+		metrics:      metrics,
+		timeSource:   timeSource,
+	}
+	p.mu.idle = make(map[poolKey][]*pooledConn)
+	return p
+}
+
+// start starts a background task that closes idle server connections which
+// have exceeded the idle timeout. All idle server connections will be closed
+// once the stopper quiesces.
+func (p *connPool) start(ctx context.Context, stopper *stop.Stopper) error {
+	return stopper.RunAsyncTask(ctx, "conn-pool-evict", func(ctx context.Context) {
+		ctx, cancel := stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+		defer p.closeAll()
+
+		timer := p.timeSource.NewTimer()
+		defer timer.Stop()
+		for {
+			// Check a few times per idle timeout so that connections do not
+			// linger for too long past their expiration.
+			timer.Reset(p.idleTimeout / 4)
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.Ch():
+				timer.MarkRead()
+				p.closeExpired()
+			}
+		}
+	})
+}
+
+// acquire returns the most recently released idle server connection for key,
+// or nil if there are none. The caller takes ownership of the connection.
+func (p *connPool) acquire(key poolKey) *pooledConn {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	conns := p.mu.idle[key]
+	if len(conns) == 0 {
+		return nil
+	}
+	// Use the most recently released connection, which is the least likely to
+	// have been closed by the server, and lets older connections expire when
+	// there are fewer client connections.
+	pc := conns[len(conns)-1]
+	conns[len(conns)-1] = nil
+	if len(conns) == 1 {
+		delete(p.mu.idle, key)
+	} else {
+		p.mu.idle[key] = conns[:len(conns)-1]
+	}
+	p.metrics.ConnPoolIdleConns.Dec(1)
+	return pc
+}
+
+// release adds the idle server connection to the pool for key. The pool takes
+// ownership of the connection.
+func (p *connPool) release(key poolKey, pc *pooledConn) {
+	pc.releasedAt = p.timeSource.Now()
+	var evicted *pooledConn
+	func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		conns := append(p.mu.idle[key], pc)
+		if len(conns) > p.maxIdleConns {
+			evicted = conns[0]
+			conns[0] = nil
+			conns = conns[1:]
+		} else {
+			p.metrics.ConnPoolIdleConns.Inc(1)
+		}
+		p.mu.idle[key] = conns
+	}()
+	if evicted != nil {
+		p.metrics.ConnPoolEvictedConnsCount.Inc(1)
+		_ = evicted.conn.Close()
+	}
+}
+
+// closeExpired closes all idle server connections that have been in the pool
+// for longer than the idle timeout.
+func (p *connPool) closeExpired() {
+	now := p.timeSource.Now()
+	var expired []*pooledConn
+	func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		for key, conns := range p.mu.idle {
+			// Connections are ordered by release time, so the expired ones are
+			// at the front.
+			i := 0
+			for i < len(conns) && now.Sub(conns[i].releasedAt) >= p.idleTimeout {
+				i++
+			}
+			if i == 0 {
+				continue
+			}
+			expired = append(expired, conns[:i]...)
+			if i == len(conns) {
+				delete(p.mu.idle, key)
+			} else {
+				p.mu.idle[key] = append([]*pooledConn(nil), conns[i:]...)
+			}
+		}
+		p.metrics.ConnPoolIdleConns.Dec(int64(len(expired)))
+	}()
+	p.metrics.ConnPoolEvictedConnsCount.Inc(int64(len(expired)))
+	for _, pc := range expired {
+		_ = pc.conn.Close()
+	}
+}
+
+// closeAll closes all idle server connections in the pool.
+func (p *connPool) closeAll() {
+	var all []*pooledConn
+	func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		for _, conns := range p.mu.idle {
+			all = append(all, conns...)
+		}
+		p.mu.idle = make(map[poolKey][]*pooledConn)
+		p.metrics.ConnPoolIdleConns.Dec(int64(len(all)))
+	}()
+	for _, pc := range all {
+		_ = pc.conn.Close()
+	}
+}
+
+// detachedSession is the state of a session whose server connection has been
+// released to the pool.
+type detachedSession struct {
+	// state is the serialized session state, as returned by SHOW TRANSFER
+	// STATE.
+	state string
+	// revivalToken is the session revival token, which is used to open a new
+	// server connection for the session if the pool has no idle connections.
+	revivalToken string
+}
+
+// onReadyForQuery is invoked by the response processor whenever a
+// ReadyForQuery message has been forwarded to the client when transaction
+// pooling is enabled.
+func (f *forwarder) onReadyForQuery(txnStatus byte) {
+	if txnStatus != txnStatusIdle {
+		return
+	}
+	select {
+	case f.txnEndedCh <- struct{}{}:
+	default: /* a signal is already pending */
+	}
+}
+
+// runPooling releases the server connection to the pool at the end of
+// transactions, and reattaches the forwarder to a server connection once the
+// client sends its next message. This runs until the forwarder is closed, or
+// the session gets pinned to its server connection.
+func (f *forwarder) runPooling() {
+	timer := f.timeSource.NewTimer()
+	defer timer.Stop()
+	for {
+		select {
+		case <-f.ctx.Done():
+			return
+		case <-f.txnEndedCh:
+		}
+
+		// Clients usually issue their next statement right after the previous
+		// transaction ends, so wait a little before releasing to avoid
+		// detaching and reattaching in between every statement. If the client
+		// started a new transaction in the meantime, the release will not be
+		// attempted until it ends.
+		if f.pool.releaseDelay > 0 {
+			timer.Reset(f.pool.releaseDelay)
+			select {
+			case <-f.ctx.Done():
+				return
+			case <-timer.Ch():
+				timer.MarkRead()
+			}
+		}
+
+		released, err := f.releaseServerConn()
+		if err != nil {
+			// The forwarder has already been closed.
+			return
+		}
+		if !released {
+			if f.isPinned() {
+				return
+			}
+			continue
+		}
+		if err := f.reattachServerConn(); err != nil {
+			f.tryReportError(err)
+			return
+		}
+	}
+}
+
+// isPinned returns true if the session has been pinned to its server
+// connection.
+func (f *forwarder) isPinned() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.mu.isPinned
+}
+
+// tryBeginRelease returns true if the server connection can be released to the
+// pool, and false otherwise. This is similar to tryBeginTransfer, but in
+// addition requires the session to be outside of a transaction block.
+func (f *forwarder) tryBeginRelease() (started bool, cleanupFn func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.mu.isInitialized || f.mu.isTransferring || f.mu.isPinned || f.mu.detached != nil {
+		return false, nil
+	}
+
+	request, response := f.mu.request, f.mu.response
+	request.mu.Lock()
+	response.mu.Lock()
+	defer request.mu.Unlock()
+	defer response.mu.Unlock()
+
+	if !isSafeTransferPointLocked(request, response) ||
+		response.mu.lastTxnStatus != txnStatusIdle {
+		return false, nil
+	}
+
+	f.mu.isTransferring = true
+	request.mu.suspendReq = true
+	response.mu.suspendReq = true
+
+	return true, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.mu.isTransferring = false
+	}
+}
+
+// releaseServerConn attempts to release the server connection to the pool,
+// and detaches the forwarder from it. released is false if the forwarder was
+// not at a point where the server connection could be released, or if the
+// session could not be serialized, in which case the processors are resumed.
+// If an error is returned, the forwarder has been closed.
+func (f *forwarder) releaseServerConn() (released bool, retErr error) {
+	if f.ctx.Err() != nil {
+		return false, f.ctx.Err()
+	}
+
+	started, cleanupFn := f.tryBeginRelease()
+	if !started {
+		return false, nil
+	}
+	defer cleanupFn()
+
+	// As with TransferConnection, the only way to unblock I/O once the timeout
+	// expires is to close the forwarder.
+	ctx, cancel := newTransferContext(f.ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		if !ctx.isRecoverable() {
+			f.Close()
+		}
+	}()
+
+	logCtx := logtags.WithTags(context.Background(), logtags.FromContext(f.ctx))
+	defer func() {
+		switch {
+		case !ctx.isRecoverable():
+			log.Infof(logCtx, "releasing server connection failed: connection closed, err=%v", retErr)
+			f.metrics.ConnPoolReleaseErrorCount.Inc(1)
+			f.Close()
+		case released:
+			f.metrics.ConnPoolReleaseCount.Inc(1)
+		default:
+			if err := f.resumeProcessors(); err != nil {
+				log.Infof(logCtx, "unable to resume processors: %v", err)
+				f.Close()
+				retErr = err
+			}
+		}
+	}()
+
+	request, response := f.getProcessors()
+	if err := request.suspend(ctx); err != nil {
+		return false, errors.Wrap(err, "suspending request processor")
+	}
+	if err := response.suspend(ctx); err != nil {
+		return false, errors.Wrap(err, "suspending response processor")
+	}
+
+	clientConn, serverConn := f.getConns()
+	transferKey := uuid.MakeV4().String()
+
+	// Once SHOW TRANSFER STATE has been sent, the connection is
+	// non-recoverable until the response has been consumed.
+	ctx.markRecoverable(false)
+	if err := runShowTransferState(serverConn, transferKey); err != nil {
+		return false, errors.Wrap(err, "sending transfer request")
+	}
+	transferErr, state, revivalToken, err := waitForShowTransferState(
+		ctx, serverConn.ToFrontendConn(), clientConn, transferKey, nil /* metrics */)
+	if err != nil {
+		return false, errors.Wrap(err, "waiting for transfer state")
+	}
+	ctx.markRecoverable(true)
+
+	if transferErr != "" {
+		// Since the session was idle, this is due to state that cannot be
+		// serialized (e.g. temporary tables), and will not go away until the
+		// session ends.
+		log.Infof(logCtx, "pinning session to server connection: %s", transferErr)
+		f.metrics.ConnPoolPinnedCount.Inc(1)
+		f.mu.Lock()
+		f.mu.isPinned = true
+		f.mu.Unlock()
+		return false, nil
+	}
+
+	// The session state has been saved, so detach the forwarder from the
+	// server connection. Query cancellation requests will be ignored until
+	// the forwarder has been reattached.
+	backendKeyData := f.connector.CancelInfo.backendKeyData()
+	f.connector.CancelInfo.clearBackend()
+	f.mu.Lock()
+	f.mu.serverConn = nil
+	f.mu.detached = &detachedSession{state: state, revivalToken: revivalToken}
+	f.mu.Unlock()
+	f.metrics.ConnPoolDetachedConns.Inc(1)
+
+	// Reset the session so that the server connection can be used by other
+	// client connections. If that fails, the server connection is closed
+	// instead of being released to the pool.
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = serverConn.SetDeadline(deadline)
+	}
+	if err := runAndWaitForDiscardAll(ctx, serverConn.ToFrontendConn()); err != nil {
+		log.Infof(logCtx, "closing server connection: resetting session: %v", err)
+		_ = serverConn.Close()
+		return true, nil
+	}
+	_ = serverConn.SetDeadline(time.Time{})
+	setServerConnOwner(serverConn, nil)
+	f.pool.release(f.poolKey, &pooledConn{conn: serverConn, backendKeyData: backendKeyData})
+	return true, nil
+}
+
+// reattachServerConn waits for the client to send its next message, and then
+// attaches the forwarder to an idle server connection from the pool, or to a
+// new server connection if there are none. The processors are resumed once the
+// session has been deserialized. If an error is returned, the forwarder must
+// be closed.
+func (f *forwarder) reattachServerConn() (retErr error) {
+	defer f.metrics.ConnPoolDetachedConns.Dec(1)
+
+	// Only the request processor reads from clientConn, and it is suspended,
+	// so it is safe to peek here. Closing the forwarder unblocks this.
+	clientConn, _ := f.getConns()
+	if _, _, err := clientConn.PeekMsg(); err != nil {
+		return wrapClientToServerError(err)
+	}
+
+	f.mu.Lock()
+	detached := f.mu.detached
+	f.mu.Unlock()
+
+	tBegin := timeutil.Now()
+	defer func() {
+		if retErr != nil {
+			f.metrics.ConnPoolAcquireErrorCount.Inc(1)
+			return
+		}
+		f.metrics.ConnPoolAcquireLatency.RecordValue(timeutil.Since(tBegin).Nanoseconds())
+	}()
+
+	ctx, cancel := context.WithTimeout(f.ctx, defaultTransferTimeout) // nolint:context
+	defer cancel()
+
+	serverConn, err := f.acquireServerConn(ctx, detached)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	if f.ctx.Err() != nil {
+		// The forwarder was closed concurrently, so serverConn would leak.
+		f.mu.Unlock()
+		serverConn.Close()
+		return f.ctx.Err()
+	}
+	f.mu.serverConn = serverConn
+	f.mu.detached = nil
+	f.newProcessorsLocked()
+	f.mu.Unlock()
+
+	return errors.Wrap(f.resumeProcessors(), "resuming processors")
+}
+
+// acquireServerConn returns a server connection into which the detached
+// session has been deserialized. Idle server connections from the pool are
+// preferred, and are closed if the session cannot be deserialized into them.
+func (f *forwarder) acquireServerConn(
+	ctx context.Context, detached *detachedSession,
+) (*interceptor.PGConn, error) {
+	for pc := f.pool.acquire(f.poolKey); pc != nil; pc = f.pool.acquire(f.poolKey) {
+		if deadline, ok := ctx.Deadline(); ok {
+			_ = pc.conn.SetDeadline(deadline)
+		}
+		err := runAndWaitForDeserializeSession(ctx, pc.conn.ToFrontendConn(), detached.state)
+		if err == nil {
+			_ = pc.conn.SetDeadline(time.Time{})
+			setServerConnOwner(pc.conn, f)
+			f.connector.CancelInfo.setNewBackend(
+				pc.backendKeyData, pc.conn.RemoteAddr().(*net.TCPAddr))
+			f.metrics.ConnPoolAcquireHitCount.Inc(1)
+			return pc.conn, nil
+		}
+		// The server connection was likely closed by the server while it was
+		// idle, so try the next one.
+		log.Infof(ctx, "closing pooled server connection: deserializing session: %v", err)
+		f.metrics.ConnPoolEvictedConnsCount.Inc(1)
+		_ = pc.conn.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+
+	f.metrics.ConnPoolAcquireMissCount.Inc(1)
+	netConn, err := f.connector.OpenTenantConnWithToken(ctx, f, detached.revivalToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening connection")
+	}
+	serverConn := interceptor.NewPGConn(netConn)
+	if err := runAndWaitForDeserializeSession(
+		ctx, serverConn.ToFrontendConn(), detached.state,
+	); err != nil {
+		serverConn.Close()
+		return nil, errors.Wrap(err, "deserializing session")
+	}
+	return serverConn, nil
+}
+
+// setServerConnOwner updates the owner of the balancer assignment of
+// serverConn, so that connection rebalancing transfers the client connection
+// which is currently using it. This is a no-op if serverConn was not opened
+// through the balancer (e.g. in tests).
+func setServerConnOwner(serverConn *interceptor.PGConn, owner balancer.ConnectionHandle) {
+	if cc, ok := serverConn.Conn.(*onConnectionClose); ok && cc.serverAssignment != nil {
+		cc.serverAssignment.SetOwner(owner)
+	}
+}
+
+// runAndWaitForDiscardAll resets the session on serverConn through DISCARD ALL
+// so that it can be used by another client. It is assumed that the last
+// message from the server was ReadyForQuery.
+//
+// WARNING: When using this, we assume that no other goroutines are using
+// serverConn.
+var runAndWaitForDiscardAll = func(
+	ctx context.Context, serverConn *interceptor.FrontendConn,
+) error {
+	if err := writeQuery(serverConn, "DISCARD ALL"); err != nil {
+		return err
+	}
+
+	// Resetting session variables may send ParameterStatus messages, which
+	// were meant for the client that released the server connection, so skip
+	// them.
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		typ, _, err := serverConn.PeekMsg()
+		if err != nil {
+			return errors.Wrap(err, "peeking message")
+		}
+		if typ != pgwirebase.ServerMsgParameterStatus && typ != pgwirebase.ServerMsgNoticeResponse {
+			break
+		}
+		if _, err := serverConn.ReadMsg(); err != nil {
+			return errors.Wrap(err, "reading message")
+		}
+	}
+
+	if err := expectCommandComplete(ctx, serverConn, "DISCARD ALL"); err != nil {
+		return errors.Wrap(err, "expecting CommandComplete")
+	}
+	if err := expectReadyForQuery(ctx, serverConn); err != nil {
+		return errors.Wrap(err, "expecting ReadyForQuery")
+	}
+	return nil
+}
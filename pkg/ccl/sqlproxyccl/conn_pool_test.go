@@ -0,0 +1,322 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package sqlproxyccl
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/sqlproxyccl/balancer"
+	"github.com/cockroachdb/cockroach/pkg/ccl/sqlproxyccl/interceptor"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// closeTrackingConn is a net.Conn that records whether it has been closed.
+type closeTrackingConn struct {
+	net.Conn
+	closed bool
+}
+
+func (c *closeTrackingConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestConnPool(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	t0 := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	timeSource := timeutil.NewManualTime(t0)
+	m := makeProxyMetrics()
+	pool := newConnPool(&m, timeSource, 2 /* maxIdleConns */, time.Minute, 0 /* releaseDelay */)
+
+	keyA := poolKey{tenantID: roachpb.MustMakeTenantID(10), user: "alice"}
+	keyB := poolKey{tenantID: roachpb.MustMakeTenantID(10), user: "bob"}
+	makeConn := func() (*pooledConn, *closeTrackingConn) {
+		c := &closeTrackingConn{}
+		return &pooledConn{conn: interceptor.NewPGConn(c)}, c
+	}
+
+	// Empty pool.
+	require.Nil(t, pool.acquire(keyA))
+
+	// Connections are only handed out for the same key, starting with the most
+	// recently released one.
+	pc1, c1 := makeConn()
+	pc2, c2 := makeConn()
+	pool.release(keyA, pc1)
+	timeSource.Advance(time.Second)
+	pool.release(keyA, pc2)
+	require.Equal(t, int64(2), m.ConnPoolIdleConns.Value())
+	require.Nil(t, pool.acquire(keyB))
+	require.Equal(t, pc2, pool.acquire(keyA))
+	require.Equal(t, int64(1), m.ConnPoolIdleConns.Value())
+	pool.release(keyA, pc2)
+
+	// Releasing past the maximum number of idle connections closes the oldest
+	// one.
+	pc3, c3 := makeConn()
+	pool.release(keyA, pc3)
+	require.True(t, c1.closed)
+	require.False(t, c2.closed)
+	require.False(t, c3.closed)
+	require.Equal(t, int64(2), m.ConnPoolIdleConns.Value())
+	require.Equal(t, int64(1), m.ConnPoolEvictedConnsCount.Count())
+
+	// Idle connections are closed once they exceed the idle timeout.
+	pc4, c4 := makeConn()
+	pool.release(keyB, pc4)
+	timeSource.Advance(30 * time.Second)
+	pc5, c5 := makeConn()
+	pool.release(keyB, pc5)
+	timeSource.Advance(30 * time.Second)
+	pool.closeExpired()
+	require.True(t, c2.closed)
+	require.True(t, c3.closed)
+	require.True(t, c4.closed)
+	require.False(t, c5.closed)
+	require.Equal(t, int64(1), m.ConnPoolIdleConns.Value())
+	require.Equal(t, int64(4), m.ConnPoolEvictedConnsCount.Count())
+	require.Nil(t, pool.acquire(keyA))
+
+	// All idle connections are closed once the stopper quiesces.
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	require.NoError(t, pool.start(ctx, stopper))
+	stopper.Stop(ctx)
+	require.True(t, c5.closed)
+	require.Equal(t, int64(0), m.ConnPoolIdleConns.Value())
+	require.Nil(t, pool.acquire(keyB))
+}
+
+func TestForwarder_tryBeginRelease(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer testutils.TestingHook(&isSafeTransferPointLocked,
+		func(req *processor, res *processor) bool {
+			return true
+		},
+	)()
+
+	makeForwarder := func(txnStatus byte) *forwarder {
+		f := &forwarder{}
+		f.mu.request = &processor{}
+		f.mu.response = &processor{}
+		f.mu.response.mu.lastTxnStatus = txnStatus
+		f.mu.isInitialized = true
+		return f
+	}
+
+	t.Run("in_transaction", func(t *testing.T) {
+		f := makeForwarder('T')
+		started, cleanupFn := f.tryBeginRelease()
+		require.False(t, started)
+		require.Nil(t, cleanupFn)
+	})
+
+	t.Run("pinned", func(t *testing.T) {
+		f := makeForwarder(txnStatusIdle)
+		f.mu.isPinned = true
+		started, cleanupFn := f.tryBeginRelease()
+		require.False(t, started)
+		require.Nil(t, cleanupFn)
+	})
+
+	t.Run("detached", func(t *testing.T) {
+		f := makeForwarder(txnStatusIdle)
+		f.mu.detached = &detachedSession{}
+		started, cleanupFn := f.tryBeginRelease()
+		require.False(t, started)
+		require.Nil(t, cleanupFn)
+	})
+
+	t.Run("successful", func(t *testing.T) {
+		f := makeForwarder(txnStatusIdle)
+		started, cleanupFn := f.tryBeginRelease()
+		require.True(t, started)
+		require.True(t, f.mu.isTransferring)
+		require.True(t, f.mu.request.mu.suspendReq)
+		require.True(t, f.mu.response.mu.suspendReq)
+		cleanupFn()
+		require.False(t, f.mu.isTransferring)
+	})
+}
+
+func TestSetServerConnOwner(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	tracker, err := balancer.NewConnTracker(ctx, stopper, nil /* timeSource */)
+	require.NoError(t, err)
+
+	tenantID := roachpb.MustMakeTenantID(10)
+	f1, f2 := &forwarder{}, &forwarder{}
+	sa := balancer.NewServerAssignment(tenantID, tracker, f1, "127.0.0.10:26257")
+	defer sa.Close()
+
+	conn, _ := net.Pipe()
+	defer conn.Close()
+	serverConn := interceptor.NewPGConn(&onConnectionClose{
+		Conn:             conn,
+		closerFn:         sa.Close,
+		serverAssignment: sa,
+	})
+
+	// Idle server connections in the pool have no owner, so they are not
+	// rebalanced.
+	setServerConnOwner(serverConn, nil)
+	require.Nil(t, sa.Owner())
+	require.Empty(t, tracker.GetConnsMap(tenantID))
+
+	// The forwarder which acquires the server connection becomes its owner.
+	setServerConnOwner(serverConn, f2)
+	require.Equal(t, balancer.ConnectionHandle(f2), sa.Owner())
+	require.Equal(t, map[string][]balancer.ConnectionHandle{
+		sa.Addr(): {f2},
+	}, tracker.GetConnsMap(tenantID))
+
+	// Server connections that were not opened through the balancer are
+	// ignored.
+	setServerConnOwner(interceptor.NewPGConn(conn), f1)
+	require.Equal(t, balancer.ConnectionHandle(f2), sa.Owner())
+}
+
+func TestProcessor_onReadyForQuery(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	serverProxy, server := net.Pipe()
+	clientProxy, client := net.Pipe()
+	defer serverProxy.Close()
+	defer clientProxy.Close()
+
+	p := newProcessor(
+		makeLogicalClockFn(),
+		interceptor.NewPGConn(serverProxy),
+		interceptor.NewPGConn(clientProxy),
+	)
+	statusCh := make(chan byte, 2)
+	p.onReadyForQuery = func(txnStatus byte) {
+		statusCh <- txnStatus
+	}
+	go func() { _ = p.resume(ctx) }()
+	require.NoError(t, p.waitResumed(ctx))
+
+	go func() {
+		writeServerMsg(server, &pgproto3.CommandComplete{CommandTag: []byte("BEGIN")})
+		writeServerMsg(server, &pgproto3.ReadyForQuery{TxStatus: 'T'})
+		writeServerMsg(server, &pgproto3.CommandComplete{CommandTag: []byte("COMMIT")})
+		writeServerMsg(server, &pgproto3.ReadyForQuery{TxStatus: txnStatusIdle})
+	}()
+
+	// The client receives all messages, and the transaction status is recorded
+	// for ReadyForQuery messages.
+	frontend := interceptor.NewFrontendConn(client)
+	expectTxnStatus := func(tag string, txnStatus byte) {
+		msg, err := frontend.ReadMsg()
+		require.NoError(t, err)
+		require.Equal(t, tag, string(msg.(*pgproto3.CommandComplete).CommandTag))
+		p.mu.Lock()
+		require.Equal(t, byte(0), p.mu.lastTxnStatus)
+		p.mu.Unlock()
+
+		msg, err = frontend.ReadMsg()
+		require.NoError(t, err)
+		require.Equal(t, txnStatus, msg.(*pgproto3.ReadyForQuery).TxStatus)
+		require.Equal(t, txnStatus, <-statusCh)
+		p.mu.Lock()
+		require.Equal(t, txnStatus, p.mu.lastTxnStatus)
+		p.mu.Unlock()
+	}
+	expectTxnStatus("BEGIN", 'T')
+	expectTxnStatus("COMMIT", txnStatusIdle)
+
+	require.NoError(t, p.suspend(ctx))
+}
+
+func TestRunAndWaitForDiscardAll(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name         string
+		sendSequence []pgproto3.BackendMessage
+		err          string
+	}{
+		{
+			name: "error",
+			sendSequence: []pgproto3.BackendMessage{
+				&pgproto3.ErrorResponse{Message: "foo"},
+			},
+			err: "CommandComplete: unexpected message.*ErrorResponse",
+		},
+		{
+			name: "CommandComplete/tag_mismatch",
+			sendSequence: []pgproto3.BackendMessage{
+				&pgproto3.CommandComplete{CommandTag: []byte("SELECT 1")},
+			},
+			err: "CommandComplete: unexpected message.*CommandComplete.*CommandTag.*SELECT 1",
+		},
+		{
+			name: "ReadyForQuery/type_mismatch",
+			sendSequence: []pgproto3.BackendMessage{
+				&pgproto3.CommandComplete{CommandTag: []byte("DISCARD ALL")},
+				&pgproto3.CommandComplete{CommandTag: []byte("DISCARD ALL")},
+			},
+			err: "ReadyForQuery: unexpected message.*CommandComplete",
+		},
+		{
+			name: "successful",
+			sendSequence: []pgproto3.BackendMessage{
+				&pgproto3.ParameterStatus{Name: "application_name", Value: ""},
+				&pgproto3.NoticeResponse{Message: "bar"},
+				&pgproto3.CommandComplete{CommandTag: []byte("DISCARD ALL")},
+				&pgproto3.ReadyForQuery{TxStatus: txnStatusIdle},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			serverProxy, server := net.Pipe()
+			defer serverProxy.Close()
+			defer server.Close()
+
+			msgCh := make(chan pgproto3.FrontendMessage, 1)
+			go func(sequence []pgproto3.BackendMessage) {
+				backend := interceptor.NewBackendConn(server)
+				msg, _ := backend.ReadMsg()
+				msgCh <- msg
+				for _, m := range sequence {
+					writeServerMsg(server, m)
+				}
+			}(tc.sendSequence)
+
+			err := runAndWaitForDiscardAll(ctx, interceptor.NewFrontendConn(serverProxy))
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Regexp(t, tc.err, err)
+			}
+
+			msg := <-msgCh
+			m, ok := msg.(*pgproto3.Query)
+			require.True(t, ok)
+			require.Equal(t, "DISCARD ALL", m.String)
+		})
+	}
+}
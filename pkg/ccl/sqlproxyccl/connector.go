@@ -391,8 +391,9 @@ func (c *connector) dialSQLServer(
 	// Add a connection wrapper that lets the balancer know the connection is
 	// closed.
 	conn = &onConnectionClose{
-		Conn:     conn,
-		closerFn: serverAssignment.Close,
+		Conn:             conn,
+		closerFn:         serverAssignment.Close,
+		serverAssignment: serverAssignment,
 	}
 
 	return conn, nil
@@ -403,6 +404,9 @@ func (c *connector) dialSQLServer(
 type onConnectionClose struct {
 	net.Conn
 	closerFn func()
+	// serverAssignment is the balancer assignment of the connection, which
+	// is needed to update its owner when the connection gets pooled.
+	serverAssignment *balancer.ServerAssignment
 }
 
 // Close invokes our custom closer function before closing the underlying
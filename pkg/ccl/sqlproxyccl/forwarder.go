@@ -17,6 +17,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/sqlproxyccl/balancer"
 	"github.com/cockroachdb/cockroach/pkg/ccl/sqlproxyccl/interceptor"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
@@ -61,6 +62,20 @@ type forwarder struct {
 	// by default. This is often replaced in tests.
 	timeSource timeutil.TimeSource
 
+	// pool is the pool that the server connection gets released to between
+	// transactions when transaction pooling is enabled, and nil otherwise. See
+	// conn_pool.go for more details. This must be set before run is called.
+	pool *connPool
+
+	// poolKey identifies the server connections in the pool that can be used
+	// by this forwarder. This is only used if pool is set.
+	poolKey poolKey
+
+	// txnEndedCh is signaled by the response processor whenever a transaction
+	// ends (i.e. a ReadyForQuery message with the idle transaction status has
+	// been forwarded to the client). This is only used if pool is set.
+	txnEndedCh chan struct{}
+
 	// While not all of these fields may need to be guarded by a mutex, we do
 	// so for consistency. Fields like clientConn and serverConn need them
 	// because Close can be invoked anytime from a different goroutine while
@@ -83,8 +98,22 @@ type forwarder struct {
 		isInitialized bool
 
 		// isTransferring indicates that a connection migration is in progress.
+		// This is also set while the server connection is being released to the
+		// pool.
 		isTransferring bool
 
+		// detached holds the serialized session state while the forwarder does
+		// not have a server connection (i.e. serverConn is nil) because it has
+		// been released to the pool. This is only used with transaction
+		// pooling.
+		detached *detachedSession
+
+		// isPinned indicates that the session could not be serialized when the
+		// server connection was about to be released to the pool, so the
+		// forwarder holds on to the server connection until it is closed. This
+		// is only used with transaction pooling.
+		isPinned bool
+
 		// clientConn and serverConn provide a convenient way to read and forward
 		// Postgres messages, while minimizing IO reads and memory allocations.
 		//
@@ -95,7 +124,8 @@ type forwarder struct {
 		// authentication phase, and will be replaced if a connection migration
 		// occurs. During a connection migration, serverConn is only replaced once
 		// the session has successfully been deserialized, and the old connection
-		// will be closed.
+		// will be closed. With transaction pooling, serverConn is nil while the
+		// forwarder is detached.
 		//
 		// All reads from these connections must go through the PG interceptors.
 		// It is not safe to call Read directly as the interceptors may have
@@ -153,6 +183,7 @@ func newForwarder(
 		connector:  connector,
 		metrics:    metrics,
 		timeSource: timeSource,
+		txnEndedCh: make(chan struct{}, 1),
 	}
 }
 
@@ -187,9 +218,7 @@ func (f *forwarder) run(clientConn net.Conn, serverConn net.Conn) error {
 
 		// Note that we don't obtain the f.mu lock here since the processors have
 		// not been resumed yet.
-		clockFn := makeLogicalClockFn()
-		f.mu.request = newProcessor(clockFn, f.mu.clientConn, f.mu.serverConn)  // client -> server
-		f.mu.response = newProcessor(clockFn, f.mu.serverConn, f.mu.clientConn) // server -> client
+		f.newProcessorsLocked()
 
 		// Forwarder is considered active initially.
 		f.mu.activity.lastRequestTransferredAt = f.mu.request.lastMessageTransferredAt()
@@ -213,6 +242,10 @@ func (f *forwarder) run(clientConn net.Conn, serverConn net.Conn) error {
 
 	// Mark the forwarder as initialized, and connection is ready for a transfer.
 	markInitialized()
+
+	if f.pool != nil {
+		go f.runPooling()
+	}
 	return nil
 }
 
@@ -356,11 +389,22 @@ func (f *forwarder) getConns() (client, server *interceptor.PGConn) {
 func (f *forwarder) replaceServerConn(newServerConn *interceptor.PGConn) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
-	clockFn := makeLogicalClockFn()
 	f.mu.serverConn.Close()
 	f.mu.serverConn = newServerConn
-	f.mu.request = newProcessor(clockFn, f.mu.clientConn, f.mu.serverConn)
-	f.mu.response = newProcessor(clockFn, f.mu.serverConn, f.mu.clientConn)
+	f.newProcessorsLocked()
+}
+
+// newProcessorsLocked creates new processors for clientConn and serverConn.
+//
+// REQUIRES: f.mu is held, and the previous processors (if any) have been
+// suspended.
+func (f *forwarder) newProcessorsLocked() {
+	clockFn := makeLogicalClockFn()
+	f.mu.request = newProcessor(clockFn, f.mu.clientConn, f.mu.serverConn)  // client -> server
+	f.mu.response = newProcessor(clockFn, f.mu.serverConn, f.mu.clientConn) // server -> client
+	if f.pool != nil {
+		f.mu.response.onReadyForQuery = f.onReadyForQuery
+	}
 }
 
 // wrapClientToServerError overrides client to server errors for external
@@ -433,6 +477,11 @@ type processor struct {
 	src *interceptor.PGConn
 	dst *interceptor.PGConn
 
+	// onReadyForQuery, if set, is called with the transaction status of the
+	// session whenever a ReadyForQuery message has been forwarded. This is only
+	// set on response processors when transaction pooling is enabled.
+	onReadyForQuery func(txnStatus byte)
+
 	mu struct {
 		syncutil.Mutex
 		cond       *sync.Cond
@@ -443,6 +492,11 @@ type processor struct {
 
 		lastMessageTransferredAt uint64 // Updated through logicalClockFn
 		lastMessageType          byte
+		// lastTxnStatus is the transaction status of the last ReadyForQuery
+		// message that was forwarded, and is reset to 0 whenever another message
+		// is about to be forwarded. This is only tracked if onReadyForQuery is
+		// set.
+		lastTxnStatus byte
 	}
 	logicalClockFn func() uint64
 
@@ -532,6 +586,7 @@ func (p *processor) resume(ctx context.Context) (retErr error) {
 		// forward that message.
 		p.mu.lastMessageType = typ
 		p.mu.lastMessageTransferredAt = p.logicalClockFn()
+		p.mu.lastTxnStatus = 0
 		return false, nil
 	}
 
@@ -550,13 +605,44 @@ func (p *processor) resume(ctx context.Context) (retErr error) {
 		if p.testingKnobs.beforeForwardMsg != nil {
 			p.testingKnobs.beforeForwardMsg()
 		}
-		if _, err := p.src.ForwardMsg(p.dst); err != nil {
+		if err := p.forwardMsg(); err != nil {
 			return errors.Wrap(err, "forwarding message")
 		}
 	}
 	return ctx.Err()
 }
 
+// forwardMsg forwards the message that was prepared in resume from src to
+// dst. If onReadyForQuery is set and the message is a ReadyForQuery, the
+// transaction status is recorded, and onReadyForQuery is called once the
+// message has been forwarded.
+func (p *processor) forwardMsg() error {
+	if p.onReadyForQuery == nil ||
+		pgwirebase.ServerMessageType(p.lastMessageType()) != pgwirebase.ServerMsgReady {
+		_, err := p.src.ForwardMsg(p.dst)
+		return err
+	}
+
+	// ReadyForQuery messages are tiny (i.e. type, length, and the transaction
+	// status), so this does not allocate.
+	msg, err := p.src.ReadMsg()
+	if err != nil {
+		return err
+	}
+	if len(msg) != 6 {
+		return errors.Newf("unexpected ReadyForQuery message length: %d", len(msg))
+	}
+	txnStatus := msg[5]
+	if _, err := p.dst.Write(msg); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.mu.lastTxnStatus = txnStatus
+	p.mu.Unlock()
+	p.onReadyForQuery(txnStatus)
+	return nil
+}
+
 // waitResumed waits until the processor has been resumed. This can be used to
 // ensure that suspend actually suspends the running processor, and there won't
 // be a race where the goroutines have not started running, and suspend returns.
@@ -610,6 +696,13 @@ func (p *processor) suspend(ctx context.Context) error {
 	return nil
 }
 
+// lastMessageType returns the type of the message that was last transferred.
+func (p *processor) lastMessageType() byte {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.mu.lastMessageType
+}
+
 // lastMessageTransferredAt returns the logical clock's value that the message
 // was last transferred at.
 func (p *processor) lastMessageTransferredAt() uint64 {
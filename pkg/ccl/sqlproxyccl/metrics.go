@@ -45,6 +45,17 @@ type metrics struct {
 	QueryCancelSuccessful     *metric.Counter
 
 	AccessControlFileErrorCount *metric.Gauge
+
+	ConnPoolIdleConns         *metric.Gauge
+	ConnPoolDetachedConns     *metric.Gauge
+	ConnPoolReleaseCount      *metric.Counter
+	ConnPoolReleaseErrorCount *metric.Counter
+	ConnPoolPinnedCount       *metric.Counter
+	ConnPoolAcquireHitCount   *metric.Counter
+	ConnPoolAcquireMissCount  *metric.Counter
+	ConnPoolAcquireErrorCount *metric.Counter
+	ConnPoolAcquireLatency    metric.IHistogram
+	ConnPoolEvictedConnsCount *metric.Counter
 }
 
 // MetricStruct implements the metrics.Struct interface.
@@ -218,6 +229,72 @@ var (
 		Measurement: "Access Control File Errors",
 		Unit:        metric.Unit_COUNT,
 	}
+	// Transaction pooling metrics.
+	//
+	// acquire.hit + acquire.miss + acquire.error = number of attempts to
+	// reattach a detached client connection to a server connection.
+	metaConnPoolIdleConns = metric.Metadata{
+		Name:        "proxy.conn_pool.idle",
+		Help:        "Number of idle server connections in the connection pool",
+		Measurement: "Connections",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaConnPoolDetachedConns = metric.Metadata{
+		Name:        "proxy.conn_pool.detached",
+		Help:        "Number of client connections that are not attached to a server connection",
+		Measurement: "Connections",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaConnPoolReleaseCount = metric.Metadata{
+		Name:        "proxy.conn_pool.release.success",
+		Help:        "Number of server connections released by client connections between transactions",
+		Measurement: "Releases",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaConnPoolReleaseErrorCount = metric.Metadata{
+		// When releasing errored out, client connections will be closed.
+		Name:        "proxy.conn_pool.release.error_fatal",
+		Help:        "Number of failed server connection releases which resulted in terminations",
+		Measurement: "Releases",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaConnPoolPinnedCount = metric.Metadata{
+		Name:        "proxy.conn_pool.pinned",
+		Help:        "Number of client connections pinned to their server connection because their session could not be serialized",
+		Measurement: "Connections",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaConnPoolAcquireHitCount = metric.Metadata{
+		Name:        "proxy.conn_pool.acquire.hit",
+		Help:        "Number of detached client connections that were attached to an idle pooled server connection",
+		Measurement: "Acquisitions",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaConnPoolAcquireMissCount = metric.Metadata{
+		Name:        "proxy.conn_pool.acquire.miss",
+		Help:        "Number of detached client connections that were attached to a new server connection",
+		Measurement: "Acquisitions",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaConnPoolAcquireErrorCount = metric.Metadata{
+		// When acquiring errored out, client connections will be closed.
+		Name:        "proxy.conn_pool.acquire.error_fatal",
+		Help:        "Number of detached client connections that could not be attached to a server connection",
+		Measurement: "Acquisitions",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaConnPoolAcquireLatency = metric.Metadata{
+		Name:        "proxy.conn_pool.acquire.latency",
+		Help:        "Latency histogram for attaching detached client connections to a server connection",
+		Measurement: "Latency",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaConnPoolEvictedConnsCount = metric.Metadata{
+		Name:        "proxy.conn_pool.evicted",
+		Help:        "Number of idle server connections closed by the connection pool",
+		Measurement: "Connections",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 // makeProxyMetrics instantiates the metrics holder for proxy monitoring.
@@ -274,6 +351,22 @@ func makeProxyMetrics() metrics {
 		QueryCancelSuccessful:     metric.NewCounter(metaQueryCancelSuccessful),
 
 		AccessControlFileErrorCount: metric.NewGauge(accessControlFileErrorCount),
+		// Transaction pooling metrics.
+		ConnPoolIdleConns:         metric.NewGauge(metaConnPoolIdleConns),
+		ConnPoolDetachedConns:     metric.NewGauge(metaConnPoolDetachedConns),
+		ConnPoolReleaseCount:      metric.NewCounter(metaConnPoolReleaseCount),
+		ConnPoolReleaseErrorCount: metric.NewCounter(metaConnPoolReleaseErrorCount),
+		ConnPoolPinnedCount:       metric.NewCounter(metaConnPoolPinnedCount),
+		ConnPoolAcquireHitCount:   metric.NewCounter(metaConnPoolAcquireHitCount),
+		ConnPoolAcquireMissCount:  metric.NewCounter(metaConnPoolAcquireMissCount),
+		ConnPoolAcquireErrorCount: metric.NewCounter(metaConnPoolAcquireErrorCount),
+		ConnPoolAcquireLatency: metric.NewHistogram(metric.HistogramOptions{
+			Mode:         metric.HistogramModePreferHdrLatency,
+			Metadata:     metaConnPoolAcquireLatency,
+			Duration:     base.DefaultHistogramWindowInterval(),
+			BucketConfig: metric.IOLatencyBuckets,
+		}),
+		ConnPoolEvictedConnsCount: metric.NewCounter(metaConnPoolEvictedConnsCount),
 	}
 }
 
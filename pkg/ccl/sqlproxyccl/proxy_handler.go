@@ -122,6 +122,19 @@ type ProxyOptions struct {
 	// port, if specified, will require the proxy protocol regardless of
 	// RequireProxyProtocol.
 	RequireProxyProtocol bool
+	// TransactionPooling enables transaction pooling, where client connections
+	// only hold on to a server connection for the duration of a transaction.
+	TransactionPooling bool
+	// ConnPoolMaxIdleConns is the maximum number of idle server connections
+	// per tenant and user when transaction pooling is enabled.
+	ConnPoolMaxIdleConns int
+	// ConnPoolIdleTimeout is the duration after which idle server connections
+	// are closed when transaction pooling is enabled.
+	ConnPoolIdleTimeout time.Duration
+	// ConnPoolReleaseDelay is the duration that client connections must be
+	// idle for after a transaction before their server connection is released
+	// when transaction pooling is enabled.
+	ConnPoolReleaseDelay time.Duration
 
 	// testingKnobs are knobs used for testing.
 	testingKnobs struct {
@@ -175,6 +188,10 @@ type proxyHandler struct {
 
 	// cancelInfoMap keeps track of all the cancel request keys for this proxy.
 	cancelInfoMap *cancelInfoMap
+
+	// connPool holds the idle server connections when transaction pooling is
+	// enabled, and is nil otherwise.
+	connPool *connPool
 }
 
 const throttledErrorHint string = `Connection throttling is triggered by repeated authentication failure. Make
@@ -312,6 +329,23 @@ func newProxyHandler(
 		return nil, err
 	}
 
+	if handler.TransactionPooling {
+		if handler.ConnPoolMaxIdleConns <= 0 || handler.ConnPoolIdleTimeout <= 0 {
+			return nil, errors.New(
+				"transaction pooling requires a positive max idle conns and idle timeout")
+		}
+		handler.connPool = newConnPool(
+			proxyMetrics,
+			nil, /* timeSource */
+			handler.ConnPoolMaxIdleConns,
+			handler.ConnPoolIdleTimeout,
+			handler.ConnPoolReleaseDelay,
+		)
+		if err := handler.connPool.start(ctx, stopper); err != nil {
+			return nil, err
+		}
+	}
+
 	// Only start the pod watcher once everything has been initialized. This
 	// will depend on the balancer eventually.
 	go handler.startPodWatcher(ctx, podWatcher)
@@ -468,6 +502,10 @@ func (handler *proxyHandler) handle(
 
 	f := newForwarder(ctx, connector, handler.metrics, nil /* timeSource */)
 	defer f.Close()
+	if handler.connPool != nil {
+		f.pool = handler.connPool
+		f.poolKey = poolKey{tenantID: tenID, user: backendStartupMsg.Parameters["user"]}
+	}
 
 	crdbConn, sentToClient, err := connector.OpenTenantConnWithAuth(ctx, f, fe.Conn,
 		func(status throttler.AttemptStatus) error {
@@ -489,7 +527,6 @@ func (handler *proxyHandler) handle(
 		}
 		return err
 	}
-	defer func() { _ = crdbConn.Close() }()
 
 	// Update the cancel info.
 	handler.cancelInfoMap.addCancelInfo(connector.CancelInfo.proxySecretID(), connector.CancelInfo)
@@ -515,8 +552,11 @@ func (handler *proxyHandler) handle(
 		writeErrMarker: errClientWrite,
 	}
 
-	// Pass ownership of conn and crdbConn to the forwarder.
+	// Pass ownership of conn and crdbConn to the forwarder. With transaction
+	// pooling, crdbConn may outlive the forwarder, so it must only be closed
+	// here if the forwarder could not take ownership of it.
 	if err := f.run(clientConn, crdbConn); err != nil {
+		_ = crdbConn.Close()
 		// Don't send to the client here for the same reason below.
 		handler.metrics.updateForError(err)
 		return errors.Wrap(err, "running forwarder")
@@ -90,6 +90,23 @@ func (c *cancelInfo) setNewBackend(
 	c.mu.crdbAddr = newCrdbAddr
 }
 
+// backendKeyData returns the cancel key of the current backend.
+func (c *cancelInfo) backendKeyData() *pgproto3.BackendKeyData {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.mu.origBackendKeyData
+}
+
+// clearBackend removes the backend cancel key and address. This is used when
+// the server connection is released to the connection pool, since the backend
+// may then be used by other clients.
+func (c *cancelInfo) clearBackend() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.origBackendKeyData = nil
+	c.mu.crdbAddr = nil
+}
+
 // sendCancelToBackend sends a cancel request to the backend after checking that
 // the given client IP is allowed to send this request.
 func (c *cancelInfo) sendCancelToBackend(requestClientIP net.IP) error {
@@ -107,6 +124,11 @@ func (c *cancelInfo) sendCancelToBackend(requestClientIP net.IP) error {
 		crdbAddr = c.mu.crdbAddr
 		origBackendKeyData = c.mu.origBackendKeyData
 	}()
+	if crdbAddr == nil || origBackendKeyData == nil {
+		// The client connection is not attached to a server connection, so
+		// there is no query to cancel.
+		return errors.Errorf("no backend for cancel request")
+	}
 	cancelConn, err := net.DialTimeout("tcp", crdbAddr.String(), timeout)
 	if err != nil {
 		return err
@@ -44,6 +44,22 @@ const (
 func (c *FileCipherStreamCreator) CreateNew(
 	ctx context.Context,
 ) (*enginepbccl.EncryptionSettings, FileStream, error) {
+	settings, key, err := c.newSettings(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	fcs, err := createFileCipherStream(settings, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return settings, fcs, nil
+}
+
+// newSettings returns the settings for a new file using the currently active
+// key, along with that key.
+func (c *FileCipherStreamCreator) newSettings(
+	ctx context.Context,
+) (*enginepbccl.EncryptionSettings, *enginepbccl.SecretKey, error) {
 	key, err := c.keyManager.ActiveKeyForWriter(ctx)
 	if err != nil {
 		return nil, nil, err
@@ -51,6 +67,13 @@ func (c *FileCipherStreamCreator) CreateNew(
 	settings := &enginepbccl.EncryptionSettings{}
 	if key == nil || key.Info.EncryptionType == enginepbccl.EncryptionType_Plaintext {
 		settings.EncryptionType = enginepbccl.EncryptionType_Plaintext
+	} else if key.Info.EncryptionType.IsAuthenticated() {
+		settings.EncryptionType = key.Info.EncryptionType
+		settings.KeyId = key.Info.KeyId
+		settings.Nonce = make([]byte, gcmSaltSize)
+		if _, err = rand.Read(settings.Nonce); err != nil {
+			return nil, nil, err
+		}
 	} else {
 		settings.EncryptionType = key.Info.EncryptionType
 		settings.KeyId = key.Info.KeyId
@@ -66,12 +89,7 @@ func (c *FileCipherStreamCreator) CreateNew(
 		// Does not matter how we convert 4 random bytes into uint32
 		settings.Counter = binary.LittleEndian.Uint32(counterBytes)
 	}
-
-	fcs, err := createFileCipherStream(settings, key)
-	if err != nil {
-		return nil, nil, err
-	}
-	return settings, fcs, nil
+	return settings, key, nil
 }
 
 func createFileCipherStream(
@@ -97,6 +115,11 @@ func createFileCipherStream(
 			return nil, err
 		}
 		return fcs, nil
+
+	case enginepbccl.EncryptionType_AES_128_GCM, enginepbccl.EncryptionType_AES_192_GCM, enginepbccl.EncryptionType_AES_256_GCM:
+		// Authenticated encryption changes the size of the data, so it cannot
+		// be done in place; see gcmFile.
+		return nil, fmt.Errorf("encryption type %s cannot be used as a stream", settings.EncryptionType)
 	}
 	return nil, fmt.Errorf("unknown encryption type %s", settings.EncryptionType)
 }
@@ -11,6 +11,7 @@ package engineccl
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/baseccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl/enginepbccl"
@@ -41,6 +42,8 @@ import (
 // - The StoreKeyManager uses the base-FS to read the user-specified store keys at startup.
 //   These are in two key files: the active key file and the old key file, which contain the
 //   key id and the key.
+//   Either key can instead be a KMS URI, in which case the key is kept in the base-FS
+//   wrapped by the KMS and is unwrapped at startup (see kms_store_key.go).
 // - The store-FS is used only for storing the key file for the generated keys. It is used by
 //   the DataKeyManager. These keys are rotated periodically in a simple manner -- a new
 //   active key is generated for future file writes. Existing files are not affected.
@@ -54,6 +57,11 @@ import (
 // Both data-FS and store-FS can be operating in plaintext mode if the user-specified store
 // keys are "plain".
 //
+// Files encrypted with AES-CTR have the same size as their plaintext and are wrapped by
+// encryptedFile. AES-GCM provides authenticated encryption, which seals the file in records
+// that each add a header and a tag, so files encrypted with it are wrapped by gcmFile
+// instead (see gcm_file.go).
+//
 // For query execution spilling to disk: we want to use encryption, but the registries do not need
 // to be on disk since on restart the query path will wipe all the existing files it has written.
 // The setup would include a memFS and there would logically be four FSs: base-FS (unencrypted
@@ -118,7 +126,7 @@ func (fs *encryptedFS) Create(name string, category vfs.DiskWriteCategory) (vfs.
 		return f, err
 	}
 	// NB: f.Close() must be called except in the case of a successful return.
-	settings, stream, err := fs.streamCreator.CreateNew(context.TODO())
+	settings, ef, err := fs.streamCreator.createNewFile(context.TODO(), f, name)
 	if err != nil {
 		_ = f.Close()
 		return nil, err
@@ -143,7 +151,7 @@ func (fs *encryptedFS) Create(name string, category vfs.DiskWriteCategory) (vfs.
 			return nil, err
 		}
 	}
-	return &encryptedFile{File: f, stream: stream}, nil
+	return ef, nil
 }
 
 // Link implements vfs.FS.Link.
@@ -174,12 +182,50 @@ func (fs *encryptedFS) Open(name string, opts ...vfs.OpenOption) (vfs.File, erro
 			return nil, err
 		}
 	}
-	stream, err := fs.streamCreator.CreateExisting(settings)
+	ef, err := fs.streamCreator.openExistingFile(f, name, settings)
 	if err != nil {
 		f.Close()
 		return nil, err
 	}
-	return &encryptedFile{File: f, stream: stream}, nil
+	return ef, nil
+}
+
+// Stat implements vfs.FS.Stat. The size of a file encrypted with AES-GCM is
+// reported without the overhead of its records, consistent with the size of
+// the file as seen through Read, which requires reading the layout of its
+// records from the file.
+func (fs *encryptedFS) Stat(name string) (vfs.FileInfo, error) {
+	info, err := fs.FS.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	fileEntry := fs.fileRegistry.GetFileEntry(name)
+	if fileEntry == nil || fileEntry.EnvType != fs.streamCreator.envType {
+		return info, nil
+	}
+	settings := &enginepbccl.EncryptionSettings{}
+	if err := protoutil.Unmarshal(fileEntry.EncryptionSettings, settings); err != nil {
+		return nil, err
+	}
+	if !settings.EncryptionType.IsAuthenticated() {
+		return info, nil
+	}
+	key, err := fs.streamCreator.keyManager.GetKey(settings.KeyId)
+	if err != nil {
+		return nil, err
+	}
+	f, err := fs.FS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	// The file may still be being written, so it is not required to have a
+	// final record.
+	gf, err := openGCMFile(f, name, key.Key, settings.Nonce, true /* allowUnfinished */)
+	if err != nil {
+		return nil, err
+	}
+	return gcmFileInfo{FileInfo: info, size: gf.layout.size}, nil
 }
 
 // Remove implements vfs.FS.Remove.
@@ -241,6 +287,53 @@ func (fs *encryptedFS) ReuseForWrite(
 	return fs.Create(newname, category)
 }
 
+// createNewFile wraps f, a file newly created in the underlying FS, so that it
+// is encrypted using the currently active key. It returns the settings used,
+// so that the caller can record these in the file registry.
+func (c *FileCipherStreamCreator) createNewFile(
+	ctx context.Context, f vfs.File, name string,
+) (*enginepbccl.EncryptionSettings, vfs.File, error) {
+	settings, key, err := c.newSettings(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if settings.EncryptionType.IsAuthenticated() {
+		gf, err := newGCMFile(f, name, key.Key, settings.Nonce)
+		if err != nil {
+			return nil, nil, err
+		}
+		return settings, gf, nil
+	}
+	stream, err := createFileCipherStream(settings, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return settings, &encryptedFile{File: f, stream: stream}, nil
+}
+
+// openExistingFile wraps f, an existing file in the underlying FS, so that it
+// is decrypted according to settings, which are nil for unencrypted files.
+func (c *FileCipherStreamCreator) openExistingFile(
+	f vfs.File, name string, settings *enginepbccl.EncryptionSettings,
+) (vfs.File, error) {
+	if settings != nil && settings.EncryptionType.IsAuthenticated() {
+		key, err := c.keyManager.GetKey(settings.KeyId)
+		if err != nil {
+			return nil, err
+		}
+		gf, err := openGCMFile(f, name, key.Key, settings.Nonce, gcmAllowsUnfinished(name))
+		if err != nil {
+			return nil, err
+		}
+		return gf, nil
+	}
+	stream, err := c.CreateExisting(settings)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedFile{File: f, stream: stream}, nil
+}
+
 type encryptionStatsHandler struct {
 	storeKM *StoreKeyManager
 	dataKM  *DataKeyManager
@@ -309,6 +402,10 @@ func newEncryptedEnv(
 		fs:                unencryptedFS,
 		activeKeyFilename: options.KeyFiles.CurrentKey,
 		oldKeyFilename:    options.KeyFiles.OldKey,
+		dbDir:             dbDir,
+		readOnly:          readOnly,
+		kmsTimeout:        time.Duration(options.KMSTimeout) * time.Second,
+		kmsCipher:         options.KMSCipher,
 	}
 	if err := storeKeyManager.Load(context.TODO()); err != nil {
 		return nil, err
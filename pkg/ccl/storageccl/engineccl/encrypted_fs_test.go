@@ -432,6 +432,116 @@ func TestPebbleEncryption2(t *testing.T) {
 	addKeyAndValidate("d", "d", "plain", "16v2.key")
 }
 
+// TestPebbleEncryptionMigrationToGCM tests that a store can move from AES-CTR
+// to AES-GCM by switching to an AES-GCM store key: data written before the
+// switch remains readable, while new files use authenticated encryption.
+func TestPebbleEncryptionMigrationToGCM(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const stickyVFSID = `foo`
+	ctx := context.Background()
+	stickyRegistry := fs.NewStickyRegistry()
+	memFS := stickyRegistry.Get(stickyVFSID)
+	writeToFile(t, memFS, "ctr.key", []byte(keyFile128))
+	writeToFile(t, memFS, "gcm.key", []byte(`{"keys":[{"kty":"oct",`+
+		`"kid":"8f4e4b1a1c0e4c2ba9e1f0d7a6b5c4d38f4e4b1a1c0e4c2ba9e1f0d7a6b5c4d3",`+
+		`"alg":"cockroach-aes-128-gcm-v1","k":"MDEyMzQ1Njc4OWFiY2RlZg"}]}`))
+
+	// sstableEncryptionTypes returns the encryption types of the sstables in the
+	// file registry.
+	sstableEncryptionTypes := func(db storage.Engine) map[enginepbccl.EncryptionType]int {
+		r, err := db.GetEncryptionRegistries()
+		require.NoError(t, err)
+		var fileRegistry enginepb.FileRegistry
+		require.NoError(t, protoutil.Unmarshal(r.FileRegistry, &fileRegistry))
+		types := make(map[enginepbccl.EncryptionType]int)
+		for name, entry := range fileRegistry.Files {
+			if !strings.HasSuffix(name, ".sst") {
+				continue
+			}
+			var settings enginepbccl.EncryptionSettings
+			require.NoError(t, protoutil.Unmarshal(entry.EncryptionSettings, &settings))
+			types[settings.EncryptionType]++
+		}
+		return types
+	}
+
+	openDB := func(currentKey, oldKey string) storage.Engine {
+		encOptionsBytes, err := protoutil.Marshal(&baseccl.EncryptionOptions{
+			KeySource: baseccl.EncryptionKeySource_KeyFiles,
+			KeyFiles: &baseccl.EncryptionKeyFiles{
+				CurrentKey: currentKey,
+				OldKey:     oldKey,
+			},
+			DataKeyRotationPeriod: 1000, // arbitrary seconds
+		})
+		require.NoError(t, err)
+		env, err := fs.InitEnvFromStoreSpec(
+			ctx,
+			base.StoreSpec{
+				InMemory:          true,
+				Attributes:        roachpb.Attributes{},
+				Size:              base.SizeSpec{InBytes: 512 << 20},
+				EncryptionOptions: encOptionsBytes,
+				StickyVFSID:       stickyVFSID,
+			},
+			fs.ReadWrite,
+			stickyRegistry, /* sticky registry */
+			nil,            /* statsCollector */
+		)
+		require.NoError(t, err)
+		db, err := storage.Open(ctx, env, cluster.MakeTestingClusterSettings())
+		require.NoError(t, err)
+		return db
+	}
+
+	func() {
+		db := openDB("ctr.key", "plain")
+		defer db.Close()
+		require.NoError(t, db.PutUnversioned(roachpb.Key("a"), []byte("a")))
+		require.NoError(t, db.Flush())
+		require.Equal(t, map[enginepbccl.EncryptionType]int{
+			enginepbccl.EncryptionType_AES128_CTR: 1,
+		}, sstableEncryptionTypes(db))
+	}()
+
+	func() {
+		// Switching to the AES-GCM store key rotates the data key.
+		db := openDB("gcm.key", "ctr.key")
+		defer db.Close()
+		stats, err := db.GetEnvStats()
+		require.NoError(t, err)
+		require.Equal(t, int32(enginepbccl.EncryptionType_AES_128_GCM), stats.EncryptionType)
+		var s enginepbccl.EncryptionStatus
+		require.NoError(t, protoutil.Unmarshal(stats.EncryptionStatus, &s))
+		require.Equal(t, enginepbccl.EncryptionType_AES_128_GCM, s.ActiveDataKey.EncryptionType)
+
+		require.Equal(t, []byte("a"), storageutils.MVCCGetRaw(t, db, storageutils.PointKey("a", 0)))
+		require.NoError(t, db.PutUnversioned(roachpb.Key("b"), []byte("b")))
+		require.NoError(t, db.Flush())
+		require.Equal(t, map[enginepbccl.EncryptionType]int{
+			enginepbccl.EncryptionType_AES128_CTR:  1,
+			enginepbccl.EncryptionType_AES_128_GCM: 1,
+		}, sstableEncryptionTypes(db))
+	}()
+
+	func() {
+		// The AES-CTR store key is no longer needed, since the data keys are now
+		// stored using the AES-GCM store key. Compacting rewrites the remaining
+		// AES-CTR sstable with authenticated encryption.
+		db := openDB("gcm.key", "plain")
+		defer db.Close()
+		require.Equal(t, []byte("a"), storageutils.MVCCGetRaw(t, db, storageutils.PointKey("a", 0)))
+		require.Equal(t, []byte("b"), storageutils.MVCCGetRaw(t, db, storageutils.PointKey("b", 0)))
+		require.NoError(t, db.Compact())
+		for encType := range sstableEncryptionTypes(db) {
+			require.Equal(t, enginepbccl.EncryptionType_AES_128_GCM, encType)
+		}
+		require.Equal(t, []byte("a"), storageutils.MVCCGetRaw(t, db, storageutils.PointKey("a", 0)))
+		require.Equal(t, []byte("b"), storageutils.MVCCGetRaw(t, db, storageutils.PointKey("b", 0)))
+	}()
+}
+
 func TestCanRegistryElide(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -0,0 +1,632 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package engineccl
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+)
+
+const (
+	// gcmBlockSize is the maximum number of plaintext bytes sealed in a record
+	// of a file encrypted with AES-GCM.
+	gcmBlockSize = 4096
+	// gcmHeaderSize is the size of the record header, which holds the length
+	// of the plaintext of the record and its flags.
+	gcmHeaderSize = 5
+	gcmTagSize    = 16
+	gcmNonceSize  = 12
+	// gcmRecordOverhead is the number of bytes added to each record by the
+	// encryption.
+	gcmRecordOverhead = gcmHeaderSize + gcmTagSize
+	// gcmTrailerSize is the size of the trailer that follows the final record
+	// of a file, which holds the size of that record.
+	gcmTrailerSize = 4
+	// gcmSaltSize is the size of the random per-file salt stored in the
+	// EncryptionSettings of the file.
+	gcmSaltSize = 16
+
+	// gcmFinalRecordFlag is set in the header of the final record of a file.
+	gcmFinalRecordFlag = 1
+	// gcmFinalRecordSeq is used in place of the sequence number of the final
+	// record of a file to form its nonce, so that it cannot collide with the
+	// nonce of a data record.
+	gcmFinalRecordSeq = math.MaxUint64
+)
+
+// gcmKeyDerivationLabel is mixed into the derivation of per-file keys to
+// separate them from any other use of the data key.
+var gcmKeyDerivationLabel = []byte("cockroach-ear-aes-gcm-file-key")
+
+// newFileGCM returns the AEAD used to seal the records of a file, using a key
+// derived from the data key and the salt of the file. Nonces only encode the
+// sequence number of a record within its file, so deriving a distinct key for every
+// file is what prevents nonce reuse across the files sharing a data key.
+func newFileGCM(key, salt []byte) (cipher.AEAD, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, errors.Newf("invalid AES-GCM key length %d", len(key))
+	}
+	if len(salt) != gcmSaltSize {
+		return nil, errors.Newf("invalid AES-GCM file salt length %d", len(salt))
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(gcmKeyDerivationLabel)
+	mac.Write(salt)
+	fileKey := mac.Sum(nil)[:len(key)]
+	block, err := aes.NewCipher(fileKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCMWithNonceSize(block, gcmNonceSize)
+}
+
+// gcmFile implements vfs.File for files encrypted with AES-GCM.
+//
+// The file is written as a sequence of records which are only ever appended,
+// so that ciphertext that was written out, and possibly synced, is never
+// overwritten. Each record is stored as:
+//
+//	[length (4 bytes)][flags (1 byte)][ciphertext (length bytes)][tag (16 bytes)]
+//
+// The plaintext is sealed in data records of gcmBlockSize bytes, except when
+// a partial block has to be written out because the file is synced or
+// closed, in which case it is sealed in a shorter data record and the
+// following data starts a new record. The nonce of a data record is its
+// sequence number within the file, and its header is authenticated as
+// additional data, so a record that is modified, or moved to another
+// position or another file, fails authentication when it is read.
+//
+// When the file is closed, a final record, flagged as such in its header, is
+// appended with an index of the lengths of the data records, followed by a
+// trailer holding the size of the final record. The index lets the records
+// be located without reading all of their headers, and since it records the
+// number of data records and their total length, a file that was truncated
+// or rolled back to an earlier state, which is necessarily a prefix of the
+// file, is detected by the absence of a valid final record. Write-ahead logs
+// and manifests are the only files that are read by Pebble after a crash
+// without having been closed, so only they can be read without a final
+// record: their records are located by reading their headers, up to the
+// first incomplete record. Like for the other encryption types, their
+// truncation at a record boundary is left to be detected by Pebble.
+type gcmFile struct {
+	vfs.File
+	name string
+	aead cipher.AEAD
+	// writable is set for files created by newGCMFile, in which case the
+	// layout, as well as the rest of the state below, is protected by mu. The
+	// layout of a file opened by openGCMFile is immutable.
+	writable bool
+	layout   gcmLayout
+
+	mu struct {
+		syncutil.Mutex
+		rOffset int64
+
+		// pending holds the plaintext of the last, partial block of a file
+		// being written, which is sealed once it is full or the file is synced
+		// or closed.
+		pending []byte
+		// buf is scratch space for the sealed records of a write.
+		buf []byte
+		// synced is set once the file is synced, after which the final record
+		// is synced when the file is closed.
+		synced bool
+		// err is set when a write to the wrapped file fails, after which the
+		// state above may no longer match the contents of the file.
+		err error
+	}
+}
+
+var _ vfs.File = (*gcmFile)(nil)
+
+// gcmLayout describes the data records of a file encrypted with AES-GCM.
+// All of the records hold gcmBlockSize bytes of plaintext, except for the
+// short records, which are listed in order. Only short records are listed
+// so that the layout of a file that is mostly written in full blocks, like
+// an sstable, takes little memory.
+type gcmLayout struct {
+	numRecords int64
+	// size is the total length of the plaintext of the records.
+	size  int64
+	short []gcmShortRecord
+}
+
+type gcmShortRecord struct {
+	// index is the sequence number of the record, and start is the offset in
+	// the plaintext of the file at which it starts.
+	index, start int64
+	length       int64
+}
+
+// add appends a record with the given length of plaintext.
+func (l *gcmLayout) add(length int64) {
+	if length < gcmBlockSize {
+		l.short = append(l.short, gcmShortRecord{index: l.numRecords, start: l.size, length: length})
+	}
+	l.numRecords++
+	l.size += length
+}
+
+// record returns the index of the record holding the plaintext at the given
+// offset, which must be less than the size of the plaintext, and the offset
+// at which the plaintext of the record starts.
+func (l *gcmLayout) record(off int64) (index, start int64) {
+	// Find the last short record which starts at or before the offset. The
+	// records between it and the offset are full.
+	i := sort.Search(len(l.short), func(i int) bool { return l.short[i].start > off }) - 1
+	if i < 0 {
+		return off / gcmBlockSize, off / gcmBlockSize * gcmBlockSize
+	}
+	s := l.short[i]
+	if off < s.start+s.length {
+		return s.index, s.start
+	}
+	full := (off - s.start - s.length) / gcmBlockSize
+	return s.index + 1 + full, s.start + s.length + full*gcmBlockSize
+}
+
+// recordLength returns the length of the plaintext of the given record.
+func (l *gcmLayout) recordLength(index int64) int64 {
+	i := sort.Search(len(l.short), func(i int) bool { return l.short[i].index >= index })
+	if i < len(l.short) && l.short[i].index == index {
+		return l.short[i].length
+	}
+	return gcmBlockSize
+}
+
+// sealedSize returns the size on disk of the data records.
+func (l *gcmLayout) sealedSize() int64 {
+	return l.size + l.numRecords*gcmRecordOverhead
+}
+
+// encode returns the index stored in the final record of a file: the number
+// of data records, followed by the sequence number and length of each short
+// record.
+func (l *gcmLayout) encode() []byte {
+	b := make([]byte, 0, 8+len(l.short)*10)
+	b = binary.BigEndian.AppendUint64(b, uint64(l.numRecords))
+	for _, s := range l.short {
+		b = binary.BigEndian.AppendUint64(b, uint64(s.index))
+		b = binary.BigEndian.AppendUint16(b, uint16(s.length))
+	}
+	return b
+}
+
+// decodeGCMLayout decodes the index stored in the final record of a file.
+func decodeGCMLayout(b []byte) (gcmLayout, error) {
+	var l gcmLayout
+	if len(b) < 8 || (len(b)-8)%10 != 0 {
+		return gcmLayout{}, errors.Newf("invalid index length %d", len(b))
+	}
+	numRecords := int64(binary.BigEndian.Uint64(b))
+	for b = b[8:]; len(b) > 0; b = b[10:] {
+		index := int64(binary.BigEndian.Uint64(b))
+		length := int64(binary.BigEndian.Uint16(b[8:]))
+		if index < l.numRecords || index >= numRecords || length == 0 || length >= gcmBlockSize {
+			return gcmLayout{}, errors.Newf("invalid short record %d of length %d", index, length)
+		}
+		// The records before this one are full.
+		l.size += (index - l.numRecords) * gcmBlockSize
+		l.numRecords = index
+		l.add(length)
+	}
+	l.size += (numRecords - l.numRecords) * gcmBlockSize
+	l.numRecords = numRecords
+	return l, nil
+}
+
+func gcmNonce(seq uint64) []byte {
+	nonce := make([]byte, gcmNonceSize)
+	binary.BigEndian.PutUint64(nonce, seq)
+	return nonce
+}
+
+func gcmCorruptionf(format string, args ...interface{}) error {
+	return errors.Mark(errors.Newf(format, args...), pebble.ErrCorruption)
+}
+
+// gcmAllowsUnfinished returns true if the file with the given name can be
+// read without a final record, i.e. if it is a write-ahead log or a manifest.
+func gcmAllowsUnfinished(name string) bool {
+	base := filepath.Base(name)
+	return strings.HasSuffix(base, ".log") || strings.HasPrefix(base, "MANIFEST-")
+}
+
+// newGCMFile wraps f, a newly created file, so that it is written encrypted
+// with AES-GCM.
+func newGCMFile(f vfs.File, name string, key, salt []byte) (*gcmFile, error) {
+	aead, err := newFileGCM(key, salt)
+	if err != nil {
+		return nil, err
+	}
+	gf := &gcmFile{File: f, name: name, aead: aead, writable: true}
+	gf.mu.pending = make([]byte, 0, gcmBlockSize)
+	return gf, nil
+}
+
+// openGCMFile wraps f, an existing file encrypted with AES-GCM, so that it is
+// decrypted when read. Unless allowUnfinished is set, the file must have a
+// valid final record.
+func openGCMFile(
+	f vfs.File, name string, key, salt []byte, allowUnfinished bool,
+) (*gcmFile, error) {
+	aead, err := newFileGCM(key, salt)
+	if err != nil {
+		return nil, err
+	}
+	gf := &gcmFile{File: f, name: name, aead: aead}
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	ok, err := gf.loadFinalRecord(info.Size())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if !allowUnfinished {
+			return nil, gcmCorruptionf(
+				"%s: the final record is missing or invalid, the file may have been truncated", name)
+		}
+		if err := gf.scanRecords(info.Size()); err != nil {
+			return nil, err
+		}
+	}
+	return gf, nil
+}
+
+// loadFinalRecord loads the layout of the file from its final record,
+// returning false if the file does not end with a valid final record.
+func (f *gcmFile) loadFinalRecord(size int64) (bool, error) {
+	if size < gcmTrailerSize+gcmRecordOverhead {
+		return false, nil
+	}
+	var trailer [gcmTrailerSize]byte
+	if _, err := f.File.ReadAt(trailer[:], size-gcmTrailerSize); err != nil {
+		return false, err
+	}
+	finalSize := int64(binary.BigEndian.Uint32(trailer[:]))
+	finalStart := size - gcmTrailerSize - finalSize
+	if finalSize < gcmRecordOverhead || finalStart < 0 {
+		return false, nil
+	}
+	record := make([]byte, finalSize)
+	if _, err := f.File.ReadAt(record, finalStart); err != nil {
+		return false, err
+	}
+	index, err := f.open(gcmFinalRecordSeq, gcmFinalRecordFlag, record)
+	if err != nil {
+		// The trailer is followed by data records rather than a final record,
+		// or the final record is corrupt.
+		return false, nil //nolint:returnerrcheck
+	}
+	layout, err := decodeGCMLayout(index)
+	if err != nil {
+		return false, errors.Mark(errors.Wrapf(err, "%s: final record", f.name), pebble.ErrCorruption)
+	}
+	if layout.sealedSize() != finalStart {
+		return false, gcmCorruptionf("%s: the final record describes %d bytes of records, found %d",
+			f.name, layout.sealedSize(), finalStart)
+	}
+	f.layout = layout
+	return true, nil
+}
+
+// scanRecords loads the layout of a file without a final record from the
+// headers of its records, up to the first incomplete one.
+func (f *gcmFile) scanRecords(size int64) error {
+	var header [gcmHeaderSize]byte
+	for off := int64(0); off+gcmRecordOverhead <= size; {
+		if _, err := f.File.ReadAt(header[:], off); err != nil {
+			return err
+		}
+		if header[4] != 0 {
+			// An invalid final record.
+			break
+		}
+		length := int64(binary.BigEndian.Uint32(header[:]))
+		if length == 0 || length > gcmBlockSize {
+			return gcmCorruptionf("%s: record %d at offset %d has invalid length %d",
+				f.name, f.layout.numRecords, off, length)
+		}
+		if off+length+gcmRecordOverhead > size {
+			break
+		}
+		f.layout.add(length)
+		off += length + gcmRecordOverhead
+	}
+	return nil
+}
+
+// sealGCMRecord appends the sealed record with the given sequence number,
+// flags and plaintext to buf.
+func sealGCMRecord(aead cipher.AEAD, buf []byte, seq uint64, flags byte, plaintext []byte) []byte {
+	var header [gcmHeaderSize]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(plaintext)))
+	header[4] = flags
+	buf = append(buf, header[:]...)
+	return aead.Seal(buf, gcmNonce(seq), plaintext, header[:])
+}
+
+// open authenticates and decrypts a record in place, returning its
+// plaintext.
+func (f *gcmFile) open(seq uint64, flags byte, record []byte) ([]byte, error) {
+	if len(record) < gcmRecordOverhead {
+		return nil, gcmCorruptionf("%s: record %d is truncated to %d bytes", f.name, seq, len(record))
+	}
+	var header [gcmHeaderSize]byte
+	copy(header[:], record)
+	if length := binary.BigEndian.Uint32(header[:]); header[4] != flags ||
+		int64(length) != int64(len(record)-gcmRecordOverhead) {
+		return nil, gcmCorruptionf("%s: record %d has an unexpected header", f.name, seq)
+	}
+	ciphertext := record[gcmHeaderSize:]
+	plaintext, err := f.aead.Open(ciphertext[:0], gcmNonce(seq), ciphertext, header[:])
+	if err != nil {
+		return nil, errors.Mark(errors.Wrapf(err, "%s: record %d", f.name, seq), pebble.ErrCorruption)
+	}
+	return plaintext, nil
+}
+
+// sealLocked appends the data record sealing the given plaintext to buf.
+func (f *gcmFile) sealLocked(buf []byte, plaintext []byte) []byte {
+	buf = sealGCMRecord(f.aead, buf, uint64(f.layout.numRecords), 0 /* flags */, plaintext)
+	f.layout.add(int64(len(plaintext)))
+	return buf
+}
+
+// writeLocked appends the given records to the wrapped file.
+func (f *gcmFile) writeLocked(buf []byte) error {
+	f.mu.buf = buf
+	if _, err := f.File.Write(buf); err != nil {
+		f.mu.err = err
+		return err
+	}
+	return nil
+}
+
+// flushLocked seals and writes out the pending partial block, if any.
+func (f *gcmFile) flushLocked() error {
+	if f.mu.err != nil {
+		return f.mu.err
+	}
+	if len(f.mu.pending) == 0 {
+		return nil
+	}
+	buf := f.sealLocked(f.mu.buf[:0], f.mu.pending)
+	f.mu.pending = f.mu.pending[:0]
+	return f.writeLocked(buf)
+}
+
+// Write implements io.Writer. Full blocks are sealed and written out
+// immediately, while the remaining partial block is held until it is full or
+// the file is synced or closed.
+func (f *gcmFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.writable {
+		return 0, errors.Newf("%s: file is not open for writing", f.name)
+	}
+	if f.mu.err != nil {
+		return 0, f.mu.err
+	}
+	buf := f.mu.buf[:0]
+	for data := p; len(data) > 0; {
+		pending := f.mu.pending
+		n := copy(pending[len(pending):gcmBlockSize], data)
+		data = data[n:]
+		f.mu.pending = pending[:len(pending)+n]
+		if len(f.mu.pending) == gcmBlockSize {
+			buf = f.sealLocked(buf, f.mu.pending)
+			f.mu.pending = f.mu.pending[:0]
+		}
+	}
+	if len(buf) > 0 {
+		if err := f.writeLocked(buf); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// WriteAt implements io.WriterAt. It is not supported, since records are
+// only ever appended.
+func (f *gcmFile) WriteAt(p []byte, off int64) (int, error) {
+	return 0, errors.Newf("%s: WriteAt is not supported with authenticated encryption", f.name)
+}
+
+// Read implements io.Reader.
+func (f *gcmFile) Read(p []byte) (n int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n, err = f.readAt(p, f.mu.rOffset)
+	f.mu.rOffset += int64(n)
+	return n, err
+}
+
+// ReadAt implements io.ReaderAt. A record that fails authentication is
+// reported as a pebble.ErrCorruption error.
+func (f *gcmFile) ReadAt(p []byte, off int64) (int, error) {
+	if f.writable {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+	}
+	return f.readAt(p, off)
+}
+
+// readAt implements ReadAt. For a file being written, mu must be held; this
+// includes the pending partial block.
+func (f *gcmFile) readAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	var n int
+	if size := f.layout.size; off < size {
+		var err error
+		if n, err = f.readRecords(p[:min(int64(len(p)), size-off)], off); err != nil {
+			return n, err
+		}
+	}
+	if pos := off + int64(n) - f.layout.size; pos >= 0 && pos < int64(len(f.mu.pending)) {
+		n += copy(p[n:], f.mu.pending[pos:])
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// readRecords reads the plaintext at the given offset, which must be entirely
+// held by the data records.
+func (f *gcmFile) readRecords(p []byte, off int64) (int, error) {
+	first, start := f.layout.record(off)
+	last, lastStart := f.layout.record(off + int64(len(p)) - 1)
+	sealedStart := start + first*gcmRecordOverhead
+	sealedEnd := lastStart + f.layout.recordLength(last) + (last+1)*gcmRecordOverhead
+	sealed := make([]byte, sealedEnd-sealedStart)
+	m, err := f.File.ReadAt(sealed, sealedStart)
+	if m < len(sealed) {
+		if err == nil || err == io.EOF {
+			err = gcmCorruptionf("%s: record %d at offset %d is truncated", f.name, first, sealedStart)
+		}
+		return 0, err
+	}
+
+	var n int
+	skip := off - start
+	for index := first; index <= last; index++ {
+		record := sealed[:f.layout.recordLength(index)+gcmRecordOverhead]
+		sealed = sealed[len(record):]
+		plaintext, err := f.open(uint64(index), 0 /* flags */, record)
+		if err != nil {
+			return n, err
+		}
+		n += copy(p[n:], plaintext[skip:])
+		skip = 0
+	}
+	return n, nil
+}
+
+// Close implements io.Closer. Closing a file being written seals the pending
+// partial block and appends the final record, which is synced if the file
+// was synced before, so that a durable file remains readable.
+func (f *gcmFile) Close() error {
+	if !f.writable {
+		return f.File.Close()
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	err := f.flushLocked()
+	if err == nil {
+		buf := sealGCMRecord(f.aead, f.mu.buf[:0], gcmFinalRecordSeq, gcmFinalRecordFlag, f.layout.encode())
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(buf)))
+		err = f.writeLocked(buf)
+	}
+	if err == nil && f.mu.synced {
+		err = f.File.Sync()
+	}
+	return errors.CombineErrors(err, f.File.Close())
+}
+
+// Sync implements vfs.File.Sync.
+func (f *gcmFile) Sync() error {
+	return f.sync(f.File.Sync)
+}
+
+// SyncData implements vfs.File.SyncData.
+func (f *gcmFile) SyncData() error {
+	return f.sync(f.File.SyncData)
+}
+
+func (f *gcmFile) sync(sync func() error) error {
+	if !f.writable {
+		return sync()
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.flushLocked(); err != nil {
+		return err
+	}
+	if err := sync(); err != nil {
+		return err
+	}
+	f.mu.synced = true
+	return nil
+}
+
+// SyncTo implements vfs.File.SyncTo. The pending partial block is not written
+// out, since SyncTo is only a hint, so the file is not fully synced while
+// there is one.
+func (f *gcmFile) SyncTo(length int64) (fullSync bool, err error) {
+	if !f.writable {
+		return f.File.SyncTo(gcmSealedSizeEstimate(length))
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fullSync, err = f.File.SyncTo(f.layout.sealedSize())
+	return fullSync && len(f.mu.pending) == 0, err
+}
+
+// gcmSealedSizeEstimate returns the size of the records holding the given
+// length of plaintext, assuming that all of them are full.
+func gcmSealedSizeEstimate(length int64) int64 {
+	return length + (length+gcmBlockSize-1)/gcmBlockSize*gcmRecordOverhead
+}
+
+// Preallocate implements vfs.File.Preallocate.
+func (f *gcmFile) Preallocate(offset, length int64) error {
+	start := gcmSealedSizeEstimate(offset)
+	return f.File.Preallocate(start, gcmSealedSizeEstimate(offset+length)-start)
+}
+
+// Prefetch implements vfs.File.Prefetch.
+func (f *gcmFile) Prefetch(offset, length int64) error {
+	start := offset / gcmBlockSize * (gcmBlockSize + gcmRecordOverhead)
+	return f.File.Prefetch(start, gcmSealedSizeEstimate(offset+length)-start)
+}
+
+// Stat implements vfs.File.Stat.
+func (f *gcmFile) Stat() (vfs.FileInfo, error) {
+	info, err := f.File.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !f.writable {
+		return gcmFileInfo{FileInfo: info, size: f.layout.size}, nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return gcmFileInfo{FileInfo: info, size: f.layout.size + int64(len(f.mu.pending))}, nil
+}
+
+// gcmFileInfo reports the plaintext size of a file encrypted with AES-GCM.
+type gcmFileInfo struct {
+	vfs.FileInfo
+	size int64
+}
+
+// Size implements os.FileInfo.
+func (i gcmFileInfo) Size() int64 {
+	return i.size
+}
@@ -0,0 +1,332 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package engineccl
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/storage/fs"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/stretchr/testify/require"
+)
+
+func makeGCMTestKey(t *testing.T, keyLength int) (key, salt []byte) {
+	key = make([]byte, keyLength)
+	salt = make([]byte, gcmSaltSize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	_, err = rand.Read(salt)
+	require.NoError(t, err)
+	return key, salt
+}
+
+func createGCMTestFile(t *testing.T, memFS vfs.FS, name string, key, salt []byte) *gcmFile {
+	f, err := memFS.Create(name, fs.UnspecifiedWriteCategory)
+	require.NoError(t, err)
+	gf, err := newGCMFile(f, name, key, salt)
+	require.NoError(t, err)
+	return gf
+}
+
+func openGCMTestFile(t *testing.T, memFS vfs.FS, name string, key, salt []byte) *gcmFile {
+	gf, err := tryOpenGCMTestFile(t, memFS, name, key, salt)
+	require.NoError(t, err)
+	return gf
+}
+
+func tryOpenGCMTestFile(
+	t *testing.T, memFS vfs.FS, name string, key, salt []byte,
+) (*gcmFile, error) {
+	f, err := memFS.Open(name)
+	require.NoError(t, err)
+	gf, err := openGCMFile(f, name, key, salt, gcmAllowsUnfinished(name))
+	if err != nil {
+		require.NoError(t, f.Close())
+	}
+	return gf, err
+}
+
+func readRawFile(t *testing.T, memFS vfs.FS, name string) []byte {
+	f, err := memFS.Open(name)
+	require.NoError(t, err)
+	defer f.Close()
+	b, err := io.ReadAll(f)
+	require.NoError(t, err)
+	return b
+}
+
+func TestGCMFile(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rng, _ := randutil.NewTestRand()
+	for _, keyLength := range []int{16, 24, 32} {
+		for _, size := range []int{0, 1, gcmBlockSize - 1, gcmBlockSize, gcmBlockSize + 1, 5*gcmBlockSize + 123} {
+			t.Run(fmt.Sprintf("key=%d/size=%d", keyLength, size), func(t *testing.T) {
+				memFS := vfs.NewMem()
+				key, salt := makeGCMTestKey(t, keyLength)
+				data := make([]byte, size)
+				_, err := rng.Read(data)
+				require.NoError(t, err)
+
+				// Write the data in chunks of random sizes, sometimes syncing in
+				// between, to exercise the sealing of partial blocks.
+				gf := createGCMTestFile(t, memFS, "foo", key, salt)
+				for rest := data; len(rest) > 0; {
+					n := 1 + rng.Intn(min(len(rest), 2*gcmBlockSize))
+					written, err := gf.Write(rest[:n])
+					require.NoError(t, err)
+					require.Equal(t, n, written)
+					if rng.Intn(2) == 0 {
+						require.NoError(t, gf.Sync())
+					}
+					rest = rest[n:]
+				}
+				// The data can be read back before the file is closed, including
+				// the pending partial block.
+				written := make([]byte, size)
+				n, err := gf.ReadAt(written, 0)
+				require.NoError(t, err)
+				require.Equal(t, size, n)
+				require.True(t, bytes.Equal(data, written))
+				require.NoError(t, gf.Close())
+
+				raw := readRawFile(t, memFS, "foo")
+				require.Less(t, int64(size), int64(len(raw)))
+				if size >= 64 {
+					require.NotContains(t, string(raw), string(data[:64]))
+				}
+
+				gf = openGCMTestFile(t, memFS, "foo", key, salt)
+				defer gf.Close()
+				info, err := gf.Stat()
+				require.NoError(t, err)
+				require.Equal(t, int64(size), info.Size())
+
+				read, err := io.ReadAll(gf)
+				require.NoError(t, err)
+				require.True(t, bytes.Equal(data, read))
+
+				for i := 0; i < 100; i++ {
+					off := rng.Intn(size + 1)
+					buf := make([]byte, rng.Intn(2*gcmBlockSize))
+					n, err := gf.ReadAt(buf, int64(off))
+					if off+len(buf) > size {
+						require.Equal(t, io.EOF, err)
+					} else {
+						require.NoError(t, err)
+					}
+					require.Equal(t, data[off:min(size, off+len(buf))], buf[:n])
+				}
+			})
+		}
+	}
+}
+
+func TestGCMFileAppendOnly(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	memFS := vfs.NewMem()
+	key, salt := makeGCMTestKey(t, 16)
+	gf := createGCMTestFile(t, memFS, "foo", key, salt)
+	var prev []byte
+	for i := 0; i < 3; i++ {
+		_, err := gf.Write([]byte("abc"))
+		require.NoError(t, err)
+		require.NoError(t, gf.Sync())
+		// The partial block is sealed in a new record on every sync, which is
+		// appended to the file without modifying what was written before.
+		raw := readRawFile(t, memFS, "foo")
+		require.Equal(t, (i+1)*(3+gcmRecordOverhead), len(raw))
+		require.Equal(t, prev, raw[:len(prev)])
+		require.Equal(t, uint32(3), binary.BigEndian.Uint32(raw[len(prev):]))
+		prev = raw
+	}
+
+	// A full block is sealed in a single record.
+	_, err := gf.Write(make([]byte, gcmBlockSize))
+	require.NoError(t, err)
+	raw := readRawFile(t, memFS, "foo")
+	require.Equal(t, prev, raw[:len(prev)])
+	require.Equal(t, uint32(gcmBlockSize), binary.BigEndian.Uint32(raw[len(prev):]))
+
+	// Closing the file appends the final record and the trailer.
+	require.NoError(t, gf.Close())
+	prev = raw
+	raw = readRawFile(t, memFS, "foo")
+	require.Equal(t, prev, raw[:len(prev)])
+	trailer := int(binary.BigEndian.Uint32(raw[len(raw)-gcmTrailerSize:]))
+	final := raw[len(raw)-gcmTrailerSize-trailer:]
+	require.Equal(t, byte(gcmFinalRecordFlag), final[4])
+
+	gf = openGCMTestFile(t, memFS, "foo", key, salt)
+	defer gf.Close()
+	require.Equal(t, int64(4), gf.layout.numRecords)
+	require.Equal(t, int64(9+gcmBlockSize), gf.layout.size)
+	read, err := io.ReadAll(gf)
+	require.NoError(t, err)
+	require.Equal(t, append([]byte("abcabcabc"), make([]byte, gcmBlockSize)...), read)
+
+	_, err = gf.WriteAt([]byte("abc"), 0)
+	require.Error(t, err)
+	_, err = gf.Write([]byte("abc"))
+	require.Error(t, err)
+}
+
+func TestGCMLayout(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rng, _ := randutil.NewTestRand()
+	for i := 0; i < 100; i++ {
+		var l gcmLayout
+		var starts []int64
+		for n := rng.Intn(20); n > 0; n-- {
+			length := int64(gcmBlockSize)
+			if rng.Intn(3) == 0 {
+				length = 1 + rng.Int63n(gcmBlockSize-1)
+			}
+			starts = append(starts, l.size)
+			l.add(length)
+		}
+		decoded, err := decodeGCMLayout(l.encode())
+		require.NoError(t, err)
+		require.Equal(t, l, decoded)
+
+		for index, start := range starts {
+			length := l.recordLength(int64(index))
+			for _, off := range []int64{start, start + rng.Int63n(length), start + length - 1} {
+				idx, s := l.record(off)
+				require.Equal(t, int64(index), idx)
+				require.Equal(t, start, s)
+			}
+		}
+	}
+}
+
+func TestGCMFileDetectsTampering(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const recordSize = gcmBlockSize + gcmRecordOverhead
+	memFS := vfs.NewMem()
+	key, salt := makeGCMTestKey(t, 32)
+	data := bytes.Repeat([]byte("0123456789abcdef"), 3*gcmBlockSize/16)
+	for _, name := range []string{"foo", "000001.log"} {
+		gf := createGCMTestFile(t, memFS, name, key, salt)
+		_, err := gf.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, gf.Close())
+	}
+	raw := readRawFile(t, memFS, "foo")
+
+	overwrite := func(t *testing.T, name string, b []byte) {
+		f, err := memFS.Create(name, fs.UnspecifiedWriteCategory)
+		require.NoError(t, err)
+		_, err = f.Write(b)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+	}
+	// requireCorrupt checks that the first block of the file can be read, but
+	// the second one cannot.
+	requireCorrupt := func(t *testing.T, gf *gcmFile) {
+		defer gf.Close()
+		buf := make([]byte, gcmBlockSize)
+		_, err := gf.ReadAt(buf, 0)
+		require.NoError(t, err)
+		_, err = gf.ReadAt(buf, gcmBlockSize)
+		require.True(t, errors.Is(err, pebble.ErrCorruption), "%+v", err)
+		_, err = io.ReadAll(gf)
+		require.True(t, errors.Is(err, pebble.ErrCorruption), "%+v", err)
+	}
+	// requireOpenCorrupt checks that the file cannot be opened.
+	requireOpenCorrupt := func(t *testing.T, name string, key, salt []byte) {
+		_, err := tryOpenGCMTestFile(t, memFS, name, key, salt)
+		require.True(t, errors.Is(err, pebble.ErrCorruption), "%+v", err)
+	}
+
+	t.Run("modified", func(t *testing.T) {
+		b := append([]byte(nil), raw...)
+		b[recordSize+gcmHeaderSize+10] ^= 1
+		overwrite(t, "foo", b)
+		requireCorrupt(t, openGCMTestFile(t, memFS, "foo", key, salt))
+	})
+
+	t.Run("header", func(t *testing.T) {
+		b := append([]byte(nil), raw...)
+		b[recordSize+gcmHeaderSize-1] ^= 1
+		overwrite(t, "foo", b)
+		requireCorrupt(t, openGCMTestFile(t, memFS, "foo", key, salt))
+	})
+
+	t.Run("reordered", func(t *testing.T) {
+		var b []byte
+		b = append(b, raw[:recordSize]...)
+		b = append(b, raw[2*recordSize:3*recordSize]...)
+		b = append(b, raw[recordSize:2*recordSize]...)
+		b = append(b, raw[3*recordSize:]...)
+		overwrite(t, "foo", b)
+		requireCorrupt(t, openGCMTestFile(t, memFS, "foo", key, salt))
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		var b []byte
+		b = append(b, raw[:recordSize]...)
+		b = append(b, raw[2*recordSize:]...)
+		overwrite(t, "foo", b)
+		requireOpenCorrupt(t, "foo", key, salt)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		// Truncating the file at a record boundary, or anywhere else, leaves it
+		// without its final record.
+		for _, size := range []int{0, recordSize, 2*recordSize + 1, len(raw) - 1} {
+			overwrite(t, "foo", raw[:size])
+			requireOpenCorrupt(t, "foo", key, salt)
+		}
+	})
+
+	t.Run("final record", func(t *testing.T) {
+		b := append([]byte(nil), raw...)
+		b[len(b)-gcmTrailerSize-1] ^= 1
+		overwrite(t, "foo", b)
+		requireOpenCorrupt(t, "foo", key, salt)
+	})
+
+	t.Run("other file", func(t *testing.T) {
+		// The same data key with a different salt, as used by another file.
+		overwrite(t, "foo", raw)
+		_, otherSalt := makeGCMTestKey(t, 32)
+		requireOpenCorrupt(t, "foo", key, otherSalt)
+	})
+
+	t.Run("unfinished log", func(t *testing.T) {
+		// A log can be read without its final record, up to its last complete
+		// record, as it is after a crash.
+		logRaw := readRawFile(t, memFS, "000001.log")
+		overwrite(t, "000001.log", logRaw[:2*recordSize+10])
+		gf := openGCMTestFile(t, memFS, "000001.log", key, salt)
+		defer gf.Close()
+		read, err := io.ReadAll(gf)
+		require.NoError(t, err)
+		require.Equal(t, data[:2*gcmBlockSize], read)
+
+		// Its records are still authenticated.
+		b := append([]byte(nil), logRaw[:2*recordSize]...)
+		b[recordSize+gcmHeaderSize+10] ^= 1
+		overwrite(t, "000001.log", b)
+		requireCorrupt(t, openGCMTestFile(t, memFS, "000001.log", key, salt))
+	})
+}
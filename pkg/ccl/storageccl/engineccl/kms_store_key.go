@@ -0,0 +1,279 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package engineccl
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/baseccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl/enginepbccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/storage/fs"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/errors/oserror"
+)
+
+// Store keys given as KMS URIs are not provided by the user. Instead, the
+// store generates a key the first time a KMS master key is used, and writes it
+// to the store directory encrypted ("wrapped") by the KMS, as a KMSWrappedKey
+// in a file whose name is derived from the ID of the master key. On every
+// start, the key is read back and unwrapped by the KMS, after which it is
+// cached by the StoreKeyManager like any other store key. The KMS is thus only
+// needed when a node starts: reading and rotating the data keys, which are
+// encrypted with the store key, never requires access to it.
+//
+// Rotating the KMS master key works like rotating key files: the new master
+// key URI is passed as the current key and the previous URI as the old key,
+// which generates a new store key wrapped by the new master key and
+// re-encrypts the data keys registry with it.
+//
+// The cipher of a generated store key is given by the cipher field of the
+// --enterprise-encryption flag, and defaults to AES-256 in counter mode. Since
+// data keys use the encryption type of the active store key, this is also the
+// cipher of the data. The cipher of an existing store key is recorded with
+// it, so changing the cipher only takes effect for the next master key.
+//
+// If the KMS cannot be reached on startup, the calls to it are retried until
+// the KMS timeout expires, after which the store fails to open. The unwrapped
+// store key is deliberately not cached anywhere but in memory, so that
+// revoking access to the master key prevents the store from being opened
+// again; a node that is running when the KMS becomes unavailable is not
+// affected until it restarts.
+
+// kmsStoreKeyFilenamePrefix is the prefix of the files holding the store keys
+// wrapped by a KMS master key.
+const kmsStoreKeyFilenamePrefix = "COCKROACHDB_KMS_STORE_KEY_"
+
+// kmsCipherEncryptionType returns the encryption type of the store keys
+// generated for KMS master keys with the given cipher, one of
+// baseccl.KMSCiphers, or the default cipher if empty.
+func kmsCipherEncryptionType(cipher string) (enginepbccl.EncryptionType, error) {
+	if cipher == "" {
+		cipher = baseccl.DefaultKMSCipher
+	}
+	switch cipher {
+	case "aes-128-ctr":
+		return enginepbccl.EncryptionType_AES_128_CTR_V2, nil
+	case "aes-192-ctr":
+		return enginepbccl.EncryptionType_AES_192_CTR_V2, nil
+	case "aes-256-ctr":
+		return enginepbccl.EncryptionType_AES_256_CTR_V2, nil
+	case "aes-128-gcm":
+		return enginepbccl.EncryptionType_AES_128_GCM, nil
+	case "aes-192-gcm":
+		return enginepbccl.EncryptionType_AES_192_GCM, nil
+	case "aes-256-gcm":
+		return enginepbccl.EncryptionType_AES_256_GCM, nil
+	}
+	return 0, errors.Newf("unknown cipher %q for KMS store keys", cipher)
+}
+
+// kmsRetryOptions are used to retry the calls to a KMS until the KMS timeout.
+var kmsRetryOptions = retry.Options{
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Multiplier:     2,
+}
+
+// storeKMSEnv is the cloud.KMSEnv used for the KMS master keys of stores.
+// Stores are opened before the node joins the cluster, so the KMS has to be
+// usable without the cluster settings or a SQL connection.
+type storeKMSEnv struct {
+	settings *cluster.Settings
+}
+
+var _ cloud.KMSEnv = storeKMSEnv{}
+
+// ClusterSettings implements cloud.KMSEnv.
+func (e storeKMSEnv) ClusterSettings() *cluster.Settings {
+	return e.settings
+}
+
+// KMSConfig implements cloud.KMSEnv.
+func (e storeKMSEnv) KMSConfig() *base.ExternalIODirConfig {
+	return &base.ExternalIODirConfig{}
+}
+
+// DBHandle implements cloud.KMSEnv.
+func (e storeKMSEnv) DBHandle() isql.DB {
+	return nil
+}
+
+// User implements cloud.KMSEnv.
+func (e storeKMSEnv) User() username.SQLUsername {
+	return username.RootUserName()
+}
+
+// kmsStoreKeyFilename returns the name of the file holding the store key
+// wrapped by the given KMS master key. Master key IDs can contain characters
+// that are not valid in filenames, so they are hashed.
+func kmsStoreKeyFilename(masterKeyID string) string {
+	h := sha256.Sum256([]byte(masterKeyID))
+	return kmsStoreKeyFilenamePrefix + hex.EncodeToString(h[:8])
+}
+
+// openStoreKMS returns the KMS for a store key URI.
+func openStoreKMS(ctx context.Context, uri string) (cloud.KMS, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		// The error contains the URI, which may contain credentials.
+		return nil, errors.New("could not parse KMS URI for store key")
+	}
+	if u.Scheme == "external" {
+		return nil, errors.Newf("external connections cannot be used for store keys")
+	}
+	return cloud.KMSFromURI(ctx, uri, storeKMSEnv{settings: cluster.MakeClusterSettings()})
+}
+
+// loadKMSKey returns the store key wrapped by the KMS master key with the
+// given URI. If create is true and the store has no key for the master key
+// yet, a new one is generated, wrapped and written to the store directory.
+func (m *StoreKeyManager) loadKMSKey(
+	ctx context.Context, uri string, create bool,
+) (*enginepbccl.SecretKey, error) {
+	kms, err := openStoreKMS(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := kms.Close(); err != nil {
+			log.Warningf(ctx, "error closing KMS: %v", err)
+		}
+	}()
+	masterKeyID := kms.MasterKeyID()
+	filename := m.fs.PathJoin(m.dbDir, kmsStoreKeyFilename(masterKeyID))
+
+	f, err := m.fs.Open(filename)
+	if oserror.IsNotExist(err) {
+		if !create {
+			return nil, errors.Newf("no store key found for KMS master key %s", masterKeyID)
+		}
+		return m.createKMSKey(ctx, kms, filename)
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := &enginepbccl.KMSWrappedKey{}
+	if err := protoutil.Unmarshal(b, wrapped); err != nil {
+		return nil, errors.Wrapf(err, "could not parse %s", filename)
+	}
+	if wrapped.MasterKeyId != masterKeyID || wrapped.Info == nil {
+		return nil, errors.Newf("%s does not hold a store key for KMS master key %s",
+			filename, masterKeyID)
+	}
+
+	var rawKey []byte
+	if err := m.withKMSRetry(ctx, masterKeyID, func(ctx context.Context) (err error) {
+		rawKey, err = kms.Decrypt(ctx, wrapped.WrappedKey)
+		return err
+	}); err != nil {
+		return nil, errors.Wrapf(err, "unwrapping store key with KMS master key %s", masterKeyID)
+	}
+	if keyLength, err := encryptionKeyLength(wrapped.Info.EncryptionType); err != nil {
+		return nil, err
+	} else if len(rawKey) != keyLength {
+		return nil, errors.Newf("store key unwrapped with KMS master key %s has length %d, expected %d",
+			masterKeyID, len(rawKey), keyLength)
+	}
+	log.Infof(ctx, "unwrapped store key %s with KMS master key %s", wrapped.Info.KeyId, masterKeyID)
+	return &enginepbccl.SecretKey{Info: wrapped.Info, Key: rawKey}, nil
+}
+
+// createKMSKey generates a new store key, and writes it to the given file
+// wrapped by the KMS.
+func (m *StoreKeyManager) createKMSKey(
+	ctx context.Context, kms cloud.KMS, filename string,
+) (*enginepbccl.SecretKey, error) {
+	masterKeyID := kms.MasterKeyID()
+	encryptionType, err := kmsCipherEncryptionType(m.kmsCipher)
+	if err != nil {
+		return nil, err
+	}
+	keyLength, err := encryptionKeyLength(encryptionType)
+	if err != nil {
+		return nil, err
+	}
+	key := &enginepbccl.SecretKey{
+		Info: &enginepbccl.KeyInfo{
+			EncryptionType: encryptionType,
+			CreationTime:   kmTimeNow().Unix(),
+			Source:         "KMS master key " + masterKeyID,
+		},
+		Key: make([]byte, keyLength),
+	}
+	if _, err := rand.Read(key.Key); err != nil {
+		return nil, err
+	}
+	keyID := make([]byte, keyIDLength)
+	if _, err := rand.Read(keyID); err != nil {
+		return nil, err
+	}
+	key.Info.KeyId = hex.EncodeToString(keyID)
+
+	wrapped := &enginepbccl.KMSWrappedKey{MasterKeyId: masterKeyID, Info: key.Info}
+	if err := m.withKMSRetry(ctx, masterKeyID, func(ctx context.Context) (err error) {
+		wrapped.WrappedKey, err = kms.Encrypt(ctx, key.Key)
+		return err
+	}); err != nil {
+		return nil, errors.Wrapf(err, "wrapping store key with KMS master key %s", masterKeyID)
+	}
+	b, err := protoutil.Marshal(wrapped)
+	if err != nil {
+		return nil, err
+	}
+	if err := fs.SafeWriteToFile(
+		m.fs, m.dbDir, filename, b, fs.EncryptionRegistryWriteCategory,
+	); err != nil {
+		return nil, err
+	}
+	log.Infof(ctx, "generated store key %s wrapped with KMS master key %s", key.Info.KeyId, masterKeyID)
+	return key, nil
+}
+
+// withKMSRetry runs fn, which calls the KMS, retrying errors until the KMS
+// timeout expires. The last error is then returned marked as
+// cloud.KMSInaccessible.
+func (m *StoreKeyManager) withKMSRetry(
+	ctx context.Context, masterKeyID string, fn func(ctx context.Context) error,
+) error {
+	timeout := m.kmsTimeout
+	if timeout <= 0 {
+		timeout = baseccl.DefaultKMSTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout) // nolint:context
+	defer cancel()
+	var err error
+	for r := retry.StartWithCtx(ctx, kmsRetryOptions); r.Next(); {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+		log.Warningf(ctx, "KMS master key %s: attempt %d failed: %v", masterKeyID, r.CurrentAttempt()+1, err)
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	return cloud.KMSInaccessible(errors.Wrapf(err, "KMS unavailable after %s", timeout))
+}
@@ -0,0 +1,245 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package engineccl
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/baseccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl/enginepbccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudtestutils"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/fs"
+	"github.com/cockroachdb/cockroach/pkg/testutils/storageutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreKeyManagerKMS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	memFS := vfs.NewMem()
+	const dbDir = "store"
+	require.NoError(t, memFS.MkdirAll(dbDir, 0755))
+	// Master keys of the local KMS live for the lifetime of the process, so use
+	// names that are unique to the test.
+	uriA := cloudtestutils.LocalKMSScheme + "://" + t.Name() + "-a"
+	uriB := cloudtestutils.LocalKMSScheme + "://" + t.Name() + "-b"
+
+	loadWithCipher := func(activeKey, oldKey, cipher string) (*StoreKeyManager, error) {
+		skm := &StoreKeyManager{
+			fs:                memFS,
+			activeKeyFilename: activeKey,
+			oldKeyFilename:    oldKey,
+			dbDir:             dbDir,
+			kmsTimeout:        10 * time.Second,
+			kmsCipher:         cipher,
+		}
+		return skm, skm.Load(ctx)
+	}
+	load := func(activeKey, oldKey string) (*StoreKeyManager, error) {
+		return loadWithCipher(activeKey, oldKey, "" /* cipher */)
+	}
+
+	// The first use of a master key generates a store key, which is written to
+	// the store directory wrapped by the KMS.
+	skm, err := load(uriA, "plain")
+	require.NoError(t, err)
+	keyA, err := skm.ActiveKeyForWriter(ctx)
+	require.NoError(t, err)
+	// The store key uses AES-256 in counter mode by default.
+	require.Equal(t, enginepbccl.EncryptionType_AES_256_CTR_V2, keyA.Info.EncryptionType)
+	require.Len(t, keyA.Key, 32)
+	require.NotContains(t, keyA.Info.Source, cloudtestutils.LocalKMSScheme)
+	filenameA := memFS.PathJoin(dbDir, kmsStoreKeyFilename(t.Name()+"-a"))
+	raw := readRawFile(t, memFS, filenameA)
+	require.NotContains(t, string(raw), string(keyA.Key))
+	var wrapped enginepbccl.KMSWrappedKey
+	require.NoError(t, protoutil.Unmarshal(raw, &wrapped))
+	require.Equal(t, t.Name()+"-a", wrapped.MasterKeyId)
+	require.Equal(t, keyA.Info.KeyId, wrapped.Info.KeyId)
+
+	// Loading the key again unwraps the same key.
+	skm, err = load(uriA, "plain")
+	require.NoError(t, err)
+	key, err := skm.ActiveKeyForWriter(ctx)
+	require.NoError(t, err)
+	require.Equal(t, keyA.String(), key.String())
+
+	// A master key that was never used cannot be the old key.
+	_, err = load(uriA, uriB)
+	require.ErrorContains(t, err, "no store key found for KMS master key")
+
+	// Rotating the master key generates a new store key, while the previous one
+	// remains available as the old key.
+	skm, err = load(uriB, uriA)
+	require.NoError(t, err)
+	keyB, err := skm.ActiveKeyForWriter(ctx)
+	require.NoError(t, err)
+	require.NotEqual(t, keyA.Info.KeyId, keyB.Info.KeyId)
+	key, err = skm.GetKey(keyA.Info.KeyId)
+	require.NoError(t, err)
+	require.Equal(t, keyA.String(), key.String())
+
+	// Key files and master keys can be mixed, to migrate between them.
+	writeToFile(t, memFS, "16.key", []byte(keyFile128))
+	skm, err = load(uriB, "16.key")
+	require.NoError(t, err)
+	_, err = skm.GetKey(keyID128)
+	require.NoError(t, err)
+
+	// The cipher of a new store key can be chosen, while existing store keys
+	// keep their cipher.
+	uriD := cloudtestutils.LocalKMSScheme + "://" + t.Name() + "-d"
+	skm, err = loadWithCipher(uriD, uriB, "aes-128-gcm")
+	require.NoError(t, err)
+	keyD, err := skm.ActiveKeyForWriter(ctx)
+	require.NoError(t, err)
+	require.Equal(t, enginepbccl.EncryptionType_AES_128_GCM, keyD.Info.EncryptionType)
+	require.Len(t, keyD.Key, 16)
+	skm, err = loadWithCipher(uriD, uriB, "aes-256-ctr")
+	require.NoError(t, err)
+	key, err = skm.ActiveKeyForWriter(ctx)
+	require.NoError(t, err)
+	require.Equal(t, keyD.String(), key.String())
+
+	// A read-only store does not generate store keys.
+	uriC := cloudtestutils.LocalKMSScheme + "://" + t.Name() + "-c"
+	skm = &StoreKeyManager{
+		fs:                memFS,
+		activeKeyFilename: uriC,
+		oldKeyFilename:    "plain",
+		dbDir:             dbDir,
+		readOnly:          true,
+	}
+	require.ErrorContains(t, skm.Load(ctx), "no store key found for KMS master key")
+}
+
+func TestStoreKeyManagerKMSUnavailable(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	memFS := vfs.NewMem()
+	const dbDir = "store"
+	require.NoError(t, memFS.MkdirAll(dbDir, 0755))
+	uri := cloudtestutils.LocalKMSScheme + "://" + t.Name()
+	load := func(timeout time.Duration) error {
+		skm := &StoreKeyManager{
+			fs:                memFS,
+			activeKeyFilename: uri,
+			oldKeyFilename:    "plain",
+			dbDir:             dbDir,
+			kmsTimeout:        timeout,
+		}
+		return skm.Load(ctx)
+	}
+	require.NoError(t, load(10*time.Second))
+
+	// The store fails to open once the KMS timeout expires.
+	restore := cloudtestutils.SetLocalKMSAvailable(false)
+	defer restore()
+	err := load(50 * time.Millisecond)
+	require.True(t, cloud.IsKMSInaccessible(err), "%+v", err)
+
+	// Calls to the KMS are retried until the timeout expires.
+	timer := time.AfterFunc(200*time.Millisecond, func() { restore() })
+	defer timer.Stop()
+	require.NoError(t, load(time.Minute))
+}
+
+func TestPebbleEncryptionKMS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const stickyVFSID = `foo`
+	ctx := context.Background()
+	stickyRegistry := fs.NewStickyRegistry()
+	uriA := cloudtestutils.LocalKMSScheme + "://" + t.Name() + "-a"
+	uriB := cloudtestutils.LocalKMSScheme + "://" + t.Name() + "-b"
+
+	openDB := func(currentKey, oldKey string) (storage.Engine, error) {
+		encOptionsBytes, err := protoutil.Marshal(&baseccl.EncryptionOptions{
+			KeySource: baseccl.EncryptionKeySource_KeyFiles,
+			KeyFiles: &baseccl.EncryptionKeyFiles{
+				CurrentKey: currentKey,
+				OldKey:     oldKey,
+			},
+			DataKeyRotationPeriod: 1000, // arbitrary seconds
+			KMSTimeout:            1,
+		})
+		require.NoError(t, err)
+		env, err := fs.InitEnvFromStoreSpec(
+			ctx,
+			base.StoreSpec{
+				InMemory:          true,
+				Attributes:        roachpb.Attributes{},
+				Size:              base.SizeSpec{InBytes: 512 << 20},
+				EncryptionOptions: encOptionsBytes,
+				StickyVFSID:       stickyVFSID,
+			},
+			fs.ReadWrite,
+			stickyRegistry, /* sticky registry */
+			nil,            /* statsCollector */
+		)
+		if err != nil {
+			return nil, err
+		}
+		return storage.Open(ctx, env, cluster.MakeTestingClusterSettings())
+	}
+	activeStoreKeySource := func(db storage.Engine) string {
+		stats, err := db.GetEnvStats()
+		require.NoError(t, err)
+		var s enginepbccl.EncryptionStatus
+		require.NoError(t, protoutil.Unmarshal(stats.EncryptionStatus, &s))
+		return s.ActiveStoreKey.Source
+	}
+
+	func() {
+		db, err := openDB(uriA, "plain")
+		require.NoError(t, err)
+		defer db.Close()
+		require.True(t, strings.HasSuffix(activeStoreKeySource(db), t.Name()+"-a"))
+		require.NoError(t, db.PutUnversioned(roachpb.Key("a"), []byte("a")))
+		require.NoError(t, db.Flush())
+	}()
+
+	func() {
+		// The KMS is needed to open the store.
+		defer cloudtestutils.SetLocalKMSAvailable(false)()
+		_, err := openDB(uriA, "plain")
+		require.True(t, cloud.IsKMSInaccessible(err), "%+v", err)
+	}()
+
+	func() {
+		// Rotate to a new master key.
+		db, err := openDB(uriB, uriA)
+		require.NoError(t, err)
+		defer db.Close()
+		require.True(t, strings.HasSuffix(activeStoreKeySource(db), t.Name()+"-b"))
+		require.Equal(t, []byte("a"), storageutils.MVCCGetRaw(t, db, storageutils.PointKey("a", 0)))
+	}()
+
+	func() {
+		// The data keys are now encrypted with the store key of the new master
+		// key, so the previous one is no longer needed.
+		db, err := openDB(uriB, "plain")
+		require.NoError(t, err)
+		defer db.Close()
+		require.Equal(t, []byte("a"), storageutils.MVCCGetRaw(t, db, storageutils.PointKey("a", 0)))
+	}()
+}
@@ -16,6 +16,7 @@ import (
 	"io"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/baseccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl/enginepbccl"
 	"github.com/cockroachdb/cockroach/pkg/storage/fs"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -80,11 +81,19 @@ var _ PebbleKeyManager = &DataKeyManager{}
 var kmTimeNow = time.Now
 
 // StoreKeyManager manages the user-provided keys. Implements PebbleKeyManager.
+// The keys are either read from key files, or are KMS URIs, see
+// kms_store_key.go.
 type StoreKeyManager struct {
 	// Initialize the following before calling Load().
 	fs                vfs.FS
 	activeKeyFilename string
 	oldKeyFilename    string
+	// Only used for keys that are KMS URIs. Wrapped keys are stored in dbDir,
+	// and are not created if readOnly is set. New keys use kmsCipher.
+	dbDir      string
+	readOnly   bool
+	kmsTimeout time.Duration
+	kmsCipher  string
 
 	// Implementation. Both are not nil after a successful call to Load().
 	activeKey *enginepbccl.SecretKey
@@ -94,11 +103,11 @@ type StoreKeyManager struct {
 // Load must be called before calling other functions.
 func (m *StoreKeyManager) Load(ctx context.Context) error {
 	var err error
-	m.activeKey, err = LoadKeyFromFile(m.fs, m.activeKeyFilename)
+	m.activeKey, err = m.loadKey(ctx, m.activeKeyFilename, !m.readOnly /* create */)
 	if err != nil {
 		return err
 	}
-	m.oldKey, err = LoadKeyFromFile(m.fs, m.oldKeyFilename)
+	m.oldKey, err = m.loadKey(ctx, m.oldKeyFilename, false /* create */)
 	if err != nil {
 		return err
 	}
@@ -107,6 +116,18 @@ func (m *StoreKeyManager) Load(ctx context.Context) error {
 	return nil
 }
 
+// loadKey loads the key from a key file or, if the key is a KMS URI, unwraps
+// it with the KMS. Only the active key is created for a KMS master key that
+// has not been used by the store before.
+func (m *StoreKeyManager) loadKey(
+	ctx context.Context, key string, create bool,
+) (*enginepbccl.SecretKey, error) {
+	if baseccl.IsKMSURI(key) {
+		return m.loadKMSKey(ctx, key, create)
+	}
+	return LoadKeyFromFile(m.fs, key)
+}
+
 // ActiveKeyForWriter implements PebbleKeyManager.
 func (m *StoreKeyManager) ActiveKeyForWriter(ctx context.Context) (*enginepbccl.SecretKey, error) {
 	return m.activeKey, nil
@@ -376,6 +397,12 @@ func (m *DataKeyManager) GetKey(id string) (*enginepbccl.SecretKey, error) {
 // - there is no active data key.
 // - the active store key has changed.
 //
+// Data keys use the encryption type of the active store key. This is also how a
+// store migrates from AES-CTR to AES-GCM online: once an AES-GCM store key
+// becomes active, the data key is rotated to an AES-GCM key and new files are
+// written with authenticated encryption, while existing files remain readable
+// using their AES-CTR data keys until they are rewritten by compactions.
+//
 // This function should not be called for a read only store.
 func (m *DataKeyManager) SetActiveStoreKeyInfo(
 	ctx context.Context, storeKeyInfo *enginepbccl.KeyInfo,
@@ -447,6 +474,23 @@ func validateRegistry(keyRegistry *enginepbccl.DataKeysRegistry) error {
 	return nil
 }
 
+// encryptionKeyLength returns the length in bytes of the keys of the given
+// encryption type.
+func encryptionKeyLength(encryptionType enginepbccl.EncryptionType) (int, error) {
+	switch encryptionType {
+	case enginepbccl.EncryptionType_AES128_CTR, enginepbccl.EncryptionType_AES_128_CTR_V2,
+		enginepbccl.EncryptionType_AES_128_GCM:
+		return 16, nil
+	case enginepbccl.EncryptionType_AES192_CTR, enginepbccl.EncryptionType_AES_192_CTR_V2,
+		enginepbccl.EncryptionType_AES_192_GCM:
+		return 24, nil
+	case enginepbccl.EncryptionType_AES256_CTR, enginepbccl.EncryptionType_AES_256_CTR_V2,
+		enginepbccl.EncryptionType_AES_256_GCM:
+		return 32, nil
+	}
+	return 0, fmt.Errorf("unknown encryption type %d", encryptionType)
+}
+
 // Generates a new data key and adds it to the keyRegistry proto and sets it as the active key.
 func generateAndSetNewDataKey(
 	ctx context.Context, keyRegistry *enginepbccl.DataKeysRegistry,
@@ -466,17 +510,9 @@ func generateAndSetNewDataKey(
 		key.Info.KeyId = plainKeyID
 		key.Info.WasExposed = true
 	} else {
-		var keyLength int
-		switch activeStoreKey.EncryptionType {
-		case enginepbccl.EncryptionType_AES128_CTR, enginepbccl.EncryptionType_AES_128_CTR_V2:
-			keyLength = 16
-		case enginepbccl.EncryptionType_AES192_CTR, enginepbccl.EncryptionType_AES_192_CTR_V2:
-			keyLength = 24
-		case enginepbccl.EncryptionType_AES256_CTR, enginepbccl.EncryptionType_AES_256_CTR_V2:
-			keyLength = 32
-		default:
-			return nil, fmt.Errorf("unknown encryption type %d for key ID %s",
-				activeStoreKey.EncryptionType, activeStoreKey.KeyId)
+		keyLength, err := encryptionKeyLength(activeStoreKey.EncryptionType)
+		if err != nil {
+			return nil, errors.Wrapf(err, "key ID %s", activeStoreKey.KeyId)
 		}
 		key.Key = make([]byte, keyLength)
 		n, err := rand.Read(key.Key)
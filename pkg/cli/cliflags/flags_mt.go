@@ -99,6 +99,28 @@ wait for the tenant id to be fully written to the file (with a newline character
 		Description: "If true, proxy will not attempt to rebalance connections.",
 	}
 
+	TransactionPooling = FlagInfo{
+		Name: "transaction-pooling",
+		Description: `If true, client connections only hold on to a connection to
+the SQL pod for the duration of a transaction, and share a pool of idle
+connections with the other client connections of the same tenant and user.`,
+	}
+
+	ConnPoolMaxIdleConns = FlagInfo{
+		Name:        "conn-pool-max-idle-conns",
+		Description: "Maximum number of idle connections to SQL pods per tenant and user with transaction pooling.",
+	}
+
+	ConnPoolIdleTimeout = FlagInfo{
+		Name:        "conn-pool-idle-timeout",
+		Description: "Duration after which idle connections to SQL pods are closed with transaction pooling.",
+	}
+
+	ConnPoolReleaseDelay = FlagInfo{
+		Name:        "conn-pool-release-delay",
+		Description: "Duration that client connections must be idle for after a transaction before their connection to the SQL pod is released with transaction pooling.",
+	}
+
 	// TODO(joel): Remove this flag, and use --listen-addr for a non-proxy
 	// protocol listener, and use --proxy-protocol-listen-addr for a proxy
 	// protocol listener.
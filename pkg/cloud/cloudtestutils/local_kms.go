@@ -0,0 +1,132 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cloudtestutils
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"net/url"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// LocalKMSScheme is the URI scheme of the local KMS, a stand-in for a real KMS
+// in tests. The host and path of a URI such as local-kms://my-key name a master
+// key, which is generated in memory the first time it is used. Master keys
+// live for the lifetime of the process.
+const LocalKMSScheme = "local-kms"
+
+var localKMSState struct {
+	syncutil.Mutex
+	masterKeys  map[string][]byte
+	unavailable bool
+}
+
+func init() {
+	cloud.RegisterKMSFromURIFactory(makeLocalKMS, LocalKMSScheme)
+}
+
+// SetLocalKMSAvailable controls whether calls to the local KMS succeed, to
+// simulate a KMS that cannot be reached. It returns a function that restores
+// the previous state.
+func SetLocalKMSAvailable(available bool) (restore func()) {
+	localKMSState.Lock()
+	defer localKMSState.Unlock()
+	prev := !localKMSState.unavailable
+	localKMSState.unavailable = !available
+	return func() {
+		localKMSState.Lock()
+		defer localKMSState.Unlock()
+		localKMSState.unavailable = !prev
+	}
+}
+
+type localKMS struct {
+	masterKeyID string
+}
+
+var _ cloud.KMS = &localKMS{}
+
+func makeLocalKMS(_ context.Context, uri string, _ cloud.KMSEnv) (cloud.KMS, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	masterKeyID := u.Host + u.Path
+	if masterKeyID == "" {
+		return nil, errors.Newf("%s URI must name a master key", LocalKMSScheme)
+	}
+	return &localKMS{masterKeyID: masterKeyID}, nil
+}
+
+// MasterKeyID implements the KMS interface.
+func (k *localKMS) MasterKeyID() string {
+	return k.masterKeyID
+}
+
+func (k *localKMS) aead() (cipher.AEAD, error) {
+	localKMSState.Lock()
+	defer localKMSState.Unlock()
+	if localKMSState.unavailable {
+		return nil, cloud.KMSInaccessible(errors.New("local KMS is unavailable"))
+	}
+	key, ok := localKMSState.masterKeys[k.masterKeyID]
+	if !ok {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		if localKMSState.masterKeys == nil {
+			localKMSState.masterKeys = make(map[string][]byte)
+		}
+		localKMSState.masterKeys[k.masterKeyID] = key
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt implements the KMS interface.
+func (k *localKMS) Encrypt(_ context.Context, data []byte) ([]byte, error) {
+	aead, err := k.aead()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, []byte(k.masterKeyID)), nil
+}
+
+// Decrypt implements the KMS interface.
+func (k *localKMS) Decrypt(_ context.Context, data []byte) ([]byte, error) {
+	aead, err := k.aead()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, []byte(k.masterKeyID))
+}
+
+// Close implements the KMS interface.
+func (k *localKMS) Close() error {
+	return nil
+}
@@ -224,6 +224,31 @@ const (
 	// config for the timeseries range if one does not exist currently.
 	V24_3_AddTimeseriesZoneConfig
 
+	// V24_3_NotificationsTable is the version that adds the
+	// system.notifications table, which is used to deliver LISTEN/NOTIFY
+	// notifications across nodes.
+	V24_3_NotificationsTable
+
+	// V24_3_RangeTypes is the version that allows columns of range and
+	// multirange types, whose values use a new key and value encoding.
+	V24_3_RangeTypes
+
+	// V24_3_Domains is the version that allows domain types to be created, whose
+	// constraints are enforced by the nodes that write their values.
+	V24_3_Domains
+
+	// V24_3_VariadicRoutines is the version that allows routines with VARIADIC
+	// parameters, which older nodes would resolve as non-variadic.
+	V24_3_VariadicRoutines
+
+	// V24_3_Triggers is the version that allows triggers to be created, which
+	// older nodes would not fire.
+	V24_3_Triggers
+
+	// V24_3_ScheduledSQL is the version that allows CREATE SCHEDULE FOR SQL,
+	// whose schedules and jobs older nodes cannot run.
+	V24_3_ScheduledSQL
+
 	// *************************************************
 	// Step (1) Add new versions above this comment.
 	// Do not add new versions to a patch release.
@@ -276,6 +301,18 @@ var versionTable = [numKeys]roachpb.Version{
 
 	V24_3_AddTimeseriesZoneConfig: {Major: 24, Minor: 2, Internal: 6},
 
+	V24_3_NotificationsTable: {Major: 24, Minor: 2, Internal: 8},
+
+	V24_3_RangeTypes: {Major: 24, Minor: 2, Internal: 10},
+
+	V24_3_Domains: {Major: 24, Minor: 2, Internal: 12},
+
+	V24_3_VariadicRoutines: {Major: 24, Minor: 2, Internal: 14},
+
+	V24_3_Triggers: {Major: 24, Minor: 2, Internal: 16},
+
+	V24_3_ScheduledSQL: {Major: 24, Minor: 2, Internal: 18},
+
 	// *************************************************
 	// Step (2): Add new versions above this comment.
 	// Do not add new versions to a patch release.
@@ -803,6 +803,16 @@ var specs = []stmtSpec{
 			"opt_with_options":      "( | 'WITH' changefeed_option ( ',' changefeed_option )* )"},
 		unlink: []string{"schedule_label", "schedule_option", "changefeed_sink", "changefeed_option", "crontab"},
 	},
+	{
+		name:   "create_schedule_for_sql_stmt",
+		inline: []string{"opt_with_schedule_options", "cron_expr"},
+		replace: map[string]string{
+			"'RECURRING' sconst_or_placeholder": "'RECURRING' crontab",
+			"'SQL' sconst_or_placeholder":       "'SQL' statements",
+			"schedule_label_spec":               "( 'IF NOT EXISTS' | )  schedule_label",
+			"kv_option_list":                    "schedule_option"},
+		unlink: []string{"schedule_label", "schedule_option", "statements", "crontab"},
+	},
 	{
 		name:    "create_schema_stmt",
 		inline:  []string{"qualifiable_schema_name", "opt_schema_name", "opt_name"},
@@ -106,6 +106,7 @@ DIAGRAMS_SRCS = [
     "//docs/generated/sql/bnf:create_schedule.html",
     "//docs/generated/sql/bnf:create_schedule_for_backup.html",
     "//docs/generated/sql/bnf:create_schedule_for_changefeed.html",
+    "//docs/generated/sql/bnf:create_schedule_for_sql.html",
     "//docs/generated/sql/bnf:create_schema.html",
     "//docs/generated/sql/bnf:create_sequence.html",
     "//docs/generated/sql/bnf:create_stats.html",
@@ -46,8 +46,10 @@ PROTOBUF_SRCS = [
     "//pkg/multitenant/mtinfopb:mtinfopb_go_proto",
     "//pkg/multitenant/tenantcapabilities/tenantcapabilitiespb:tenantcapabilitiespb_go_proto",
     "//pkg/obsservice/obspb/opentelemetry-proto/collector/logs/v1:v1_go_proto",
+    "//pkg/obsservice/obspb/opentelemetry-proto/collector/metrics/v1:v1_go_proto",
     "//pkg/obsservice/obspb/opentelemetry-proto/common/v1:v1_go_proto",
     "//pkg/obsservice/obspb/opentelemetry-proto/logs/v1:v1_go_proto",
+    "//pkg/obsservice/obspb/opentelemetry-proto/metrics/v1:v1_go_proto",
     "//pkg/obsservice/obspb/opentelemetry-proto/resource/v1:v1_go_proto",
     "//pkg/obsservice/obspb:obspb_go_proto",
     "//pkg/raft/raftpb:raftpb_go_proto",
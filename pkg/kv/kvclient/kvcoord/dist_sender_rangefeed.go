@@ -68,6 +68,7 @@ type rangeFeedConfig struct {
 	withFiltering         bool
 	withMetadata          bool
 	withMatchingOriginIDs []uint32
+	filterSpec            *kvpb.RangeFeedFilterSpec
 	rangeObserver         func(ForEachRangeFn)
 
 	knobs struct {
@@ -126,6 +127,16 @@ func WithMatchingOriginIDs(originIDs ...uint32) RangeFeedOption {
 	})
 }
 
+// WithFilterSpec pushes the given column family, column and predicate filters
+// down to the rangefeed servers, which use it to avoid sending values that the
+// client would discard. The client must still apply its own filtering since
+// the servers may emit values that do not satisfy the spec.
+func WithFilterSpec(spec *kvpb.RangeFeedFilterSpec) RangeFeedOption {
+	return optionFunc(func(c *rangeFeedConfig) {
+		c.filterSpec = spec
+	})
+}
+
 // WithRangeObserver is called when the rangefeed starts with a function that
 // can be used to iterate over all the ranges.
 func WithRangeObserver(observer func(ForEachRangeFn)) RangeFeedOption {
@@ -663,6 +674,7 @@ func makeRangeFeedRequest(
 	withDiff bool,
 	withFiltering bool,
 	withMatchingOriginIDs []uint32,
+	filterSpec *kvpb.RangeFeedFilterSpec,
 ) kvpb.RangeFeedRequest {
 	admissionPri := admissionpb.BulkNormalPri
 	if isSystemRange {
@@ -677,6 +689,7 @@ func makeRangeFeedRequest(
 		WithDiff:              withDiff,
 		WithFiltering:         withFiltering,
 		WithMatchingOriginIDs: withMatchingOriginIDs,
+		FilterSpec:            filterSpec,
 		AdmissionHeader: kvpb.AdmissionHeader{
 			// NB: AdmissionHeader is used only at the start of the range feed
 			// stream since the initial catch-up scan is expensive.
@@ -44,6 +44,7 @@ type config struct {
 	withDiff              bool
 	withFiltering         bool
 	withMatchingOriginIDs []uint32
+	filterSpec            *kvpb.RangeFeedFilterSpec
 	onUnrecoverableError  OnUnrecoverableError
 	onCheckpoint          OnCheckpoint
 	frontierQuantize      time.Duration
@@ -164,6 +165,16 @@ func WithOriginIDsMatching(originIDs ...uint32) Option {
 	})
 }
 
+// WithFilterSpec makes an option to push the given filter spec down to the
+// rangefeed servers. The spec is not applied to the initial scan, and the
+// servers may emit values that do not satisfy it, so the OnValue callback must
+// still filter the values it receives.
+func WithFilterSpec(spec *kvpb.RangeFeedFilterSpec) Option {
+	return optionFunc(func(c *config) {
+		c.filterSpec = spec
+	})
+}
+
 // WithInvoker makes an option to invoke the rangefeed tasks such as running the
 // the client and processing events emitted by the client with a caller-supplied
 // function, which can make it easier to introspect into work done by a given
@@ -110,6 +110,7 @@ func runBenchmarkRangefeed(b *testing.B, opts benchmarkRangefeedOpts) {
 		streams[i] = &noopStream{ctx: ctx, done: make(chan *kvpb.Error, 1)}
 		ok, _ := p.Register(span, hlc.MinTimestamp, nil,
 			withDiff, withFiltering, false, /* withOmitRemote */
+			nil, /* filterSpec */
 			streams[i], nil)
 		require.True(b, ok)
 	}
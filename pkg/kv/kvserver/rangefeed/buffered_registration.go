@@ -81,6 +81,7 @@ func newBufferedRegistration(
 	withDiff bool,
 	withFiltering bool,
 	withOmitRemote bool,
+	filterSpec *kvpb.RangeFeedFilterSpec,
 	bufferSz int,
 	blockWhenFull bool,
 	metrics *Metrics,
@@ -94,6 +95,7 @@ func newBufferedRegistration(
 			withDiff:         withDiff,
 			withFiltering:    withFiltering,
 			withOmitRemote:   withOmitRemote,
+			filter:           newValueFilter(filterSpec),
 			unreg:            unregisterFn,
 		},
 		metrics:       metrics,
@@ -116,7 +118,11 @@ func (br *bufferedRegistration) publish(
 	ctx context.Context, event *kvpb.RangeFeedEvent, alloc *SharedBudgetAllocation,
 ) {
 	br.assertEvent(ctx, event)
-	e := getPooledSharedEvent(sharedEvent{event: br.maybeStripEvent(ctx, event), alloc: alloc})
+	strippedEvent := br.maybeStripEvent(ctx, event)
+	if strippedEvent == nil {
+		return
+	}
+	e := getPooledSharedEvent(sharedEvent{event: strippedEvent, alloc: alloc})
 
 	br.mu.Lock()
 	defer br.mu.Unlock()
@@ -271,7 +277,18 @@ func (br *bufferedRegistration) maybeRunCatchUpScan(ctx context.Context) error {
 		br.metrics.RangeFeedCatchUpScanNanos.Inc(timeutil.Since(start).Nanoseconds())
 	}()
 
-	return catchUpIter.CatchUpScan(ctx, br.stream.Send, br.withDiff, br.withFiltering, br.withOmitRemote)
+	send := br.stream.Send
+	if br.filter != nil {
+		// Apply the filter spec to the catch-up scan's events in the same way
+		// as it is applied to live events.
+		send = func(event *kvpb.RangeFeedEvent) error {
+			if event = br.maybeStripEvent(ctx, event); event == nil {
+				return nil
+			}
+			return br.stream.Send(event)
+		}
+	}
+	return catchUpIter.CatchUpScan(ctx, send, br.withDiff, br.withFiltering, br.withOmitRemote)
 }
 
 // Wait for this registration to completely process its internal buffer.
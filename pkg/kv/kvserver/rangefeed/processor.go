@@ -194,6 +194,9 @@ type Processor interface {
 	// subsequently close it. If method fails, iterator must be kept intact and
 	// would be closed by caller.
 	//
+	// The optional filter spec is applied to the values emitted to the
+	// registration, including those emitted by the catch-up scan.
+	//
 	// If the method returns false, the processor will have been stopped, so calling
 	// Stop is not necessary. If the method returns true, it will also return an
 	// updated operation filter that includes the operations required by the new
@@ -207,6 +210,7 @@ type Processor interface {
 		withDiff bool,
 		withFiltering bool,
 		withOmitRemote bool,
+		filterSpec *kvpb.RangeFeedFilterSpec,
 		stream Stream,
 		disconnectFn func(),
 	) (bool, *Filter)
@@ -591,6 +595,7 @@ func (p *LegacyProcessor) Register(
 	withDiff bool,
 	withFiltering bool,
 	withOmitRemote bool,
+	filterSpec *kvpb.RangeFeedFilterSpec,
 	stream Stream,
 	disconnectFn func(),
 ) (bool, *Filter) {
@@ -602,7 +607,7 @@ func (p *LegacyProcessor) Register(
 	blockWhenFull := p.Config.EventChanTimeout == 0 // for testing
 	r := newBufferedRegistration(
 		span.AsRawSpanWithNoLocals(), startTS, catchUpIter, withDiff, withFiltering, withOmitRemote,
-		p.Config.EventChanCap, blockWhenFull, p.Metrics, stream, disconnectFn,
+		filterSpec, p.Config.EventChanCap, blockWhenFull, p.Metrics, stream, disconnectFn,
 	)
 	select {
 	case p.regC <- r:
@@ -497,6 +497,7 @@ func TestProcessorBasic(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			nil,   /* filterSpec */
 			r1Stream,
 			func() {},
 		)
@@ -631,6 +632,7 @@ func TestProcessorBasic(t *testing.T) {
 			true,  /* withDiff */
 			true,  /* withFiltering */
 			false, /* withOmitRemote */
+			nil,   /* filterSpec */
 			r2Stream,
 			func() {},
 		)
@@ -745,6 +747,7 @@ func TestProcessorBasic(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			nil,   /* filterSpec */
 			r3Stream,
 			func() {},
 		)
@@ -770,6 +773,7 @@ func TestProcessorOmitRemote(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			nil,   /* filterSpec */
 			r1Stream,
 			func() {},
 		)
@@ -795,6 +799,7 @@ func TestProcessorOmitRemote(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			true,  /* withOmitRemote */
+			nil,   /* filterSpec */
 			r2Stream,
 			func() {},
 		)
@@ -848,6 +853,7 @@ func TestProcessorSlowConsumer(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			nil,   /* filterSpec */
 			r1Stream,
 			func() {},
 		)
@@ -859,6 +865,7 @@ func TestProcessorSlowConsumer(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			nil,   /* filterSpec */
 			r2Stream,
 			func() {},
 		)
@@ -955,6 +962,7 @@ func TestProcessorMemoryBudgetExceeded(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			nil,   /* filterSpec */
 			r1Stream,
 			func() {},
 		)
@@ -1010,6 +1018,7 @@ func TestProcessorMemoryBudgetReleased(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			nil,   /* filterSpec */
 			r1Stream,
 			func() {},
 		)
@@ -1091,6 +1100,7 @@ func TestProcessorInitializeResolvedTimestamp(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			nil,   /* filterSpec */
 			r1Stream,
 			func() {},
 		)
@@ -1399,7 +1409,7 @@ func TestProcessorConcurrentStop(t *testing.T) {
 				runtime.Gosched()
 				s := newTestStream()
 				p.Register(h.span, hlc.Timestamp{}, nil, /* catchUpIter */
-					false /* withDiff */, false /* withFiltering */, false /* withOmitRemote */, s, func() {})
+					false /* withDiff */, false /* withFiltering */, false /* withOmitRemote */, nil /* filterSpec */, s, func() {})
 			}()
 			go func() {
 				defer wg.Done()
@@ -1471,7 +1481,7 @@ func TestProcessorRegistrationObservesOnlyNewEvents(t *testing.T) {
 				s := newTestStream()
 				regs[s] = firstIdx
 				p.Register(h.span, hlc.Timestamp{}, nil, /* catchUpIter */
-					false /* withDiff */, false /* withFiltering */, false /* withOmitRemote */, s, func() {})
+					false /* withDiff */, false /* withFiltering */, false /* withOmitRemote */, nil /* filterSpec */, s, func() {})
 				regDone <- struct{}{}
 			}
 		}()
@@ -1530,6 +1540,7 @@ func TestBudgetReleaseOnProcessorStop(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			nil,   /* filterSpec */
 			rStream,
 			func() {},
 		)
@@ -1610,6 +1621,7 @@ func TestBudgetReleaseOnLastStreamError(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			nil,   /* filterSpec */
 			rStream,
 			func() {},
 		)
@@ -1680,6 +1692,7 @@ func TestBudgetReleaseOnOneStreamError(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			nil,   /* filterSpec */
 			r1Stream,
 			func() {},
 		)
@@ -1693,6 +1706,7 @@ func TestBudgetReleaseOnOneStreamError(t *testing.T) {
 			false, /* withDiff */
 			false, /* withFiltering */
 			false, /* withOmitRemote */
+			nil,   /* filterSpec */
 			r2Stream,
 			func() {},
 		)
@@ -1865,7 +1879,7 @@ func TestProcessorBackpressure(t *testing.T) {
 	// Add a registration.
 	stream := newTestStream()
 	ok, _ := p.Register(span, hlc.MinTimestamp, nil, /* catchUpIter */
-		false /* withDiff */, false /* withFiltering */, false /* withOmitRemote */, stream, nil)
+		false /* withDiff */, false /* withFiltering */, false /* withOmitRemote */, nil /* filterSpec */, stream, nil)
 	require.True(t, ok)
 
 	// Wait for the initial checkpoint.
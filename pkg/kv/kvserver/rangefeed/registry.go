@@ -71,6 +71,7 @@ type baseRegistration struct {
 	withDiff         bool
 	withFiltering    bool
 	withOmitRemote   bool
+	filter           *valueFilter // nil if the registration does not filter values
 	unreg            func()
 	catchUpTimestamp hlc.Timestamp // exclusive
 	id               int64         // internal
@@ -165,7 +166,8 @@ func (r *baseRegistration) assertEvent(ctx context.Context, event *kvpb.RangeFee
 // maybeStripEvent determines whether the event contains excess information not
 // applicable to the current registration. If so, it makes a copy of the event
 // and strips the incompatible information to match only what the registration
-// requested.
+// requested. It returns nil if the registration's filter spec excludes the
+// event altogether.
 func (r *baseRegistration) maybeStripEvent(
 	ctx context.Context, event *kvpb.RangeFeedEvent,
 ) *kvpb.RangeFeedEvent {
@@ -189,6 +191,16 @@ func (r *baseRegistration) maybeStripEvent(
 			t = copyOnWrite().(*kvpb.RangeFeedValue)
 			t.PrevValue = roachpb.Value{}
 		}
+		if r.filter != nil {
+			value, prevValue, ok := r.filter.apply(t.Key, t.Value, t.PrevValue, r.withDiff)
+			if !ok {
+				return nil
+			}
+			if len(r.filter.columnIDs) > 0 {
+				t = copyOnWrite().(*kvpb.RangeFeedValue)
+				t.Value, t.PrevValue = value, prevValue
+			}
+		}
 	case *kvpb.RangeFeedCheckpoint:
 		if !t.Span.EqualValue(r.span) {
 			// Checkpoint events are always created spanning the entire Range.
@@ -164,6 +164,7 @@ func newTestRegistration(
 		withDiff,
 		withFiltering,
 		withOmitRemote,
+		nil, /* filterSpec */
 		5,
 		false, /* blockWhenFull */
 		NewMetrics(),
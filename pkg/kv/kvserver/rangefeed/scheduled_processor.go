@@ -307,6 +307,7 @@ func (p *ScheduledProcessor) Register(
 	withDiff bool,
 	withFiltering bool,
 	withOmitRemote bool,
+	filterSpec *kvpb.RangeFeedFilterSpec,
 	stream Stream,
 	disconnectFn func(),
 ) (bool, *Filter) {
@@ -318,7 +319,7 @@ func (p *ScheduledProcessor) Register(
 	blockWhenFull := p.Config.EventChanTimeout == 0 // for testing
 	r := newBufferedRegistration(
 		span.AsRawSpanWithNoLocals(), startTS, catchUpIter, withDiff, withFiltering, withOmitRemote,
-		p.Config.EventChanCap, blockWhenFull, p.Metrics, stream, disconnectFn,
+		filterSpec, p.Config.EventChanCap, blockWhenFull, p.Metrics, stream, disconnectFn,
 	)
 
 	filter := runRequest(p, func(ctx context.Context, p *ScheduledProcessor) *Filter {
@@ -0,0 +1,262 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rangefeed
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// ValidateFilterSpec returns an error if the given filter spec of a
+// RangeFeedRequest is malformed. A nil spec is valid.
+func ValidateFilterSpec(spec *kvpb.RangeFeedFilterSpec) error {
+	if spec == nil || len(spec.Predicates) == 0 {
+		return nil
+	}
+	if len(spec.Families) != 1 {
+		return errors.Errorf(
+			"rangefeed predicates require exactly one column family, found %d", len(spec.Families))
+	}
+	for _, p := range spec.Predicates {
+		if _, ok := kvpb.RangeFeedPredicate_Operator_name[int32(p.Op)]; !ok {
+			return errors.Errorf("unknown rangefeed predicate operator %d", p.Op)
+		}
+		if _, _, _, _, err := encoding.DecodeValueTag(p.Value); err != nil {
+			return errors.Wrapf(err, "invalid value for rangefeed predicate on column %d", p.ColumnID)
+		}
+	}
+	return nil
+}
+
+// valueFilter evaluates a kvpb.RangeFeedFilterSpec against the values
+// published to a registration. All of its methods err on the side of emitting
+// a value, so that a value which cannot be interpreted reaches the client,
+// which applies its own filtering.
+type valueFilter struct {
+	families   []uint32
+	columnIDs  []uint32
+	predicates []kvpb.RangeFeedPredicate
+}
+
+// newValueFilter returns the valueFilter for the given spec, or nil if the
+// spec does not filter or project anything.
+func newValueFilter(spec *kvpb.RangeFeedFilterSpec) *valueFilter {
+	if spec == nil ||
+		(len(spec.Families) == 0 && len(spec.ColumnIDs) == 0 && len(spec.Predicates) == 0) {
+		return nil
+	}
+	f := &valueFilter{
+		families:   spec.Families,
+		columnIDs:  slices.Clone(spec.ColumnIDs),
+		predicates: spec.Predicates,
+	}
+	slices.Sort(f.columnIDs)
+	return f
+}
+
+// apply returns the (possibly projected) value and previous value to emit
+// for the given key, or false if the value should not be emitted. The
+// previous value is only considered by the predicates if withDiff is set.
+func (f *valueFilter) apply(
+	key roachpb.Key, value, prevValue roachpb.Value, withDiff bool,
+) (_, _ roachpb.Value, ok bool) {
+	if len(f.families) > 0 {
+		// Keys that are not SQL row keys fail to decode and are emitted.
+		if family, err := keys.DecodeFamilyKey(key); err == nil && !slices.Contains(f.families, family) {
+			return roachpb.Value{}, roachpb.Value{}, false
+		}
+	}
+	// Deletion tombstones are always emitted since they do not contain any
+	// columns to filter on.
+	if !value.IsPresent() {
+		return value, f.project(prevValue), true
+	}
+	if len(f.predicates) > 0 && !f.matches(value) {
+		// The row may be leaving the set of rows that satisfy the predicates,
+		// which the client can only observe through the previous value.
+		if !withDiff || !prevValue.IsPresent() || !f.matches(prevValue) {
+			return roachpb.Value{}, roachpb.Value{}, false
+		}
+	}
+	return f.project(value), f.project(prevValue), true
+}
+
+// matches returns whether the value satisfies all of the predicates.
+func (f *valueFilter) matches(value roachpb.Value) bool {
+	tuple, err := value.GetTuple()
+	if err != nil {
+		// Families with a single column may be stored without a column tuple,
+		// in which case the column ID is not encoded in the value.
+		return true
+	}
+	for i := range f.predicates {
+		p := &f.predicates[i]
+		col, found, err := findColumn(tuple, p.ColumnID)
+		if err != nil {
+			return true
+		}
+		if !found {
+			// The column is NULL.
+			return false
+		}
+		c, ok := compareEncodedValues(col, p.Value)
+		if !ok {
+			continue
+		}
+		var satisfied bool
+		switch p.Op {
+		case kvpb.RangeFeedPredicate_EQ:
+			satisfied = c == 0
+		case kvpb.RangeFeedPredicate_NE:
+			satisfied = c != 0
+		case kvpb.RangeFeedPredicate_LT:
+			satisfied = c < 0
+		case kvpb.RangeFeedPredicate_LE:
+			satisfied = c <= 0
+		case kvpb.RangeFeedPredicate_GT:
+			satisfied = c > 0
+		case kvpb.RangeFeedPredicate_GE:
+			satisfied = c >= 0
+		default:
+			satisfied = true
+		}
+		if !satisfied {
+			return false
+		}
+	}
+	return true
+}
+
+// project returns the value with only the columns in columnIDs retained. The
+// value is returned unchanged if it is not a column tuple.
+func (f *valueFilter) project(value roachpb.Value) roachpb.Value {
+	if len(f.columnIDs) == 0 {
+		return value
+	}
+	tuple, err := value.GetTuple()
+	if err != nil {
+		return value
+	}
+	var buf []byte
+	var colID, lastColID uint32
+	for b := tuple; len(b) > 0; {
+		_, dataOffset, colIDDelta, typ, err := encoding.DecodeValueTag(b)
+		if err != nil {
+			return value
+		}
+		length, err := encoding.PeekValueLengthWithOffsetsAndType(b, dataOffset, typ)
+		if err != nil {
+			return value
+		}
+		colID += colIDDelta
+		if _, ok := slices.BinarySearch(f.columnIDs, colID); ok {
+			buf = encoding.EncodeValueTag(buf, colID-lastColID, typ)
+			buf = append(buf, b[dataOffset:length]...)
+			lastColID = colID
+		}
+		b = b[length:]
+	}
+	ret := roachpb.Value{Timestamp: value.Timestamp}
+	ret.SetTuple(buf)
+	return ret
+}
+
+// findColumn returns the encoded column with the given ID in a column tuple,
+// or false if the tuple does not contain the column.
+func findColumn(tuple []byte, id uint32) (_ []byte, found bool, _ error) {
+	var colID uint32
+	for b := tuple; len(b) > 0; {
+		_, dataOffset, colIDDelta, typ, err := encoding.DecodeValueTag(b)
+		if err != nil {
+			return nil, false, err
+		}
+		length, err := encoding.PeekValueLengthWithOffsetsAndType(b, dataOffset, typ)
+		if err != nil {
+			return nil, false, err
+		}
+		colID += colIDDelta
+		if colID == id {
+			return b[:length], true, nil
+		} else if colID > id {
+			break
+		}
+		b = b[length:]
+	}
+	return nil, false, nil
+}
+
+// compareEncodedValues compares two values encoded with the value encoding,
+// returning false if they cannot be compared.
+func compareEncodedValues(a, b []byte) (int, bool) {
+	_, aOffset, _, aTyp, err := encoding.DecodeValueTag(a)
+	if err != nil {
+		return 0, false
+	}
+	_, bOffset, _, bTyp, err := encoding.DecodeValueTag(b)
+	if err != nil {
+		return 0, false
+	}
+	a, b = a[aOffset:], b[bOffset:]
+	switch {
+	case aTyp == encoding.Int && bTyp == encoding.Int:
+		_, x, err := encoding.DecodeUntaggedIntValue(a)
+		if err != nil {
+			return 0, false
+		}
+		_, y, err := encoding.DecodeUntaggedIntValue(b)
+		if err != nil {
+			return 0, false
+		}
+		return cmp.Compare(x, y), true
+	case aTyp == encoding.Float && bTyp == encoding.Float:
+		_, x, err := encoding.DecodeUntaggedFloatValue(a)
+		if err != nil {
+			return 0, false
+		}
+		_, y, err := encoding.DecodeUntaggedFloatValue(b)
+		if err != nil {
+			return 0, false
+		}
+		return cmp.Compare(x, y), true
+	case aTyp == encoding.Bytes && bTyp == encoding.Bytes:
+		_, x, err := encoding.DecodeUntaggedBytesValue(a)
+		if err != nil {
+			return 0, false
+		}
+		_, y, err := encoding.DecodeUntaggedBytesValue(b)
+		if err != nil {
+			return 0, false
+		}
+		return bytes.Compare(x, y), true
+	case isBoolType(aTyp) && isBoolType(bTyp):
+		// False sorts before true.
+		return cmp.Compare(boolToInt(aTyp == encoding.True), boolToInt(bTyp == encoding.True)), true
+	}
+	return 0, false
+}
+
+func isBoolType(typ encoding.Type) bool {
+	return typ == encoding.True || typ == encoding.False
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
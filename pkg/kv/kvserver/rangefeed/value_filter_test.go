@@ -0,0 +1,155 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rangefeed
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rowKey := func(family uint32) roachpb.Key {
+		key := keys.SystemSQLCodec.IndexPrefix(104, 1)
+		key = encoding.EncodeVarintAscending(key, 1)
+		return keys.MakeFamilyKey(key, family)
+	}
+	// row returns a column tuple with a = 1, b = <b> and c = 'foo'. Column b
+	// is omitted (NULL) if b is negative.
+	row := func(b int64) roachpb.Value {
+		var buf []byte
+		buf = encoding.EncodeIntValue(buf, 1, 1)
+		lastColID := uint32(1)
+		if b >= 0 {
+			buf = encoding.EncodeIntValue(buf, 2-lastColID, b)
+			lastColID = 2
+		}
+		buf = encoding.EncodeBytesValue(buf, 3-lastColID, []byte("foo"))
+		var v roachpb.Value
+		v.SetTuple(buf)
+		return v
+	}
+	intPredicate := func(colID uint32, op kvpb.RangeFeedPredicate_Operator, i int64) kvpb.RangeFeedPredicate {
+		return kvpb.RangeFeedPredicate{
+			ColumnID: colID,
+			Op:       op,
+			Value:    encoding.EncodeIntValue(nil, encoding.NoColumnID, i),
+		}
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		require.Nil(t, newValueFilter(nil))
+		require.Nil(t, newValueFilter(&kvpb.RangeFeedFilterSpec{}))
+	})
+
+	t.Run("families", func(t *testing.T) {
+		f := newValueFilter(&kvpb.RangeFeedFilterSpec{Families: []uint32{1}})
+		_, _, ok := f.apply(rowKey(0), row(5), roachpb.Value{}, false /* withDiff */)
+		require.False(t, ok)
+		v, _, ok := f.apply(rowKey(1), row(5), roachpb.Value{}, false /* withDiff */)
+		require.True(t, ok)
+		require.Equal(t, row(5), v)
+		// Keys that are not row keys are always emitted.
+		_, _, ok = f.apply(roachpb.Key("a"), row(5), roachpb.Value{}, false /* withDiff */)
+		require.True(t, ok)
+	})
+
+	t.Run("predicates", func(t *testing.T) {
+		for _, tc := range []struct {
+			op       kvpb.RangeFeedPredicate_Operator
+			b        int64
+			expected bool
+		}{
+			{op: kvpb.RangeFeedPredicate_EQ, b: 10, expected: true},
+			{op: kvpb.RangeFeedPredicate_EQ, b: 5, expected: false},
+			{op: kvpb.RangeFeedPredicate_NE, b: 5, expected: true},
+			{op: kvpb.RangeFeedPredicate_LT, b: 5, expected: true},
+			{op: kvpb.RangeFeedPredicate_LT, b: 10, expected: false},
+			{op: kvpb.RangeFeedPredicate_LE, b: 10, expected: true},
+			{op: kvpb.RangeFeedPredicate_GT, b: 10, expected: false},
+			{op: kvpb.RangeFeedPredicate_GE, b: 10, expected: true},
+			// A NULL column does not satisfy any comparison.
+			{op: kvpb.RangeFeedPredicate_NE, b: -1, expected: false},
+		} {
+			f := newValueFilter(&kvpb.RangeFeedFilterSpec{
+				Families:   []uint32{0},
+				Predicates: []kvpb.RangeFeedPredicate{intPredicate(2, tc.op, 10)},
+			})
+			_, _, ok := f.apply(rowKey(0), row(tc.b), roachpb.Value{}, false /* withDiff */)
+			require.Equal(t, tc.expected, ok, "b=%d %s 10", tc.b, tc.op)
+		}
+	})
+
+	t.Run("predicates with diff", func(t *testing.T) {
+		f := newValueFilter(&kvpb.RangeFeedFilterSpec{
+			Families:   []uint32{0},
+			Predicates: []kvpb.RangeFeedPredicate{intPredicate(2, kvpb.RangeFeedPredicate_EQ, 10)},
+		})
+		// The row no longer matches, but the previous value did.
+		_, _, ok := f.apply(rowKey(0), row(5), row(10), true /* withDiff */)
+		require.True(t, ok)
+		// Neither the row nor the previous value match.
+		_, _, ok = f.apply(rowKey(0), row(5), row(6), true /* withDiff */)
+		require.False(t, ok)
+		// The previous value is ignored without diff.
+		_, _, ok = f.apply(rowKey(0), row(5), row(10), false /* withDiff */)
+		require.False(t, ok)
+		// Deletions are always emitted.
+		_, _, ok = f.apply(rowKey(0), roachpb.Value{}, row(5), true /* withDiff */)
+		require.True(t, ok)
+	})
+
+	t.Run("projection", func(t *testing.T) {
+		f := newValueFilter(&kvpb.RangeFeedFilterSpec{ColumnIDs: []uint32{3, 1}})
+		v, prev, ok := f.apply(rowKey(0), row(5), row(6), true /* withDiff */)
+		require.True(t, ok)
+
+		var expected []byte
+		expected = encoding.EncodeIntValue(expected, 1, 1)
+		expected = encoding.EncodeBytesValue(expected, 2, []byte("foo"))
+		for _, projected := range []roachpb.Value{v, prev} {
+			tuple, err := projected.GetTuple()
+			require.NoError(t, err)
+			require.Equal(t, expected, tuple)
+		}
+	})
+}
+
+func TestValidateFilterSpec(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	pred := kvpb.RangeFeedPredicate{
+		ColumnID: 1,
+		Op:       kvpb.RangeFeedPredicate_EQ,
+		Value:    encoding.EncodeIntValue(nil, encoding.NoColumnID, 1),
+	}
+	require.NoError(t, ValidateFilterSpec(nil))
+	require.NoError(t, ValidateFilterSpec(&kvpb.RangeFeedFilterSpec{Families: []uint32{0, 1}}))
+	require.NoError(t, ValidateFilterSpec(&kvpb.RangeFeedFilterSpec{
+		Families: []uint32{0}, Predicates: []kvpb.RangeFeedPredicate{pred},
+	}))
+	require.ErrorContains(t, ValidateFilterSpec(&kvpb.RangeFeedFilterSpec{
+		Predicates: []kvpb.RangeFeedPredicate{pred},
+	}), "exactly one column family")
+
+	badOp := pred
+	badOp.Op = 100
+	require.ErrorContains(t, ValidateFilterSpec(&kvpb.RangeFeedFilterSpec{
+		Families: []uint32{0}, Predicates: []kvpb.RangeFeedPredicate{badOp},
+	}), "unknown rangefeed predicate operator")
+}
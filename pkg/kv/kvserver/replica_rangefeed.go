@@ -245,6 +245,9 @@ func (r *Replica) RangeFeed(
 	} else if len(args.WithMatchingOriginIDs) > 0 {
 		return errors.Errorf("multiple origin IDs and OriginID != 0 not supported yet")
 	}
+	if err := rangefeed.ValidateFilterSpec(args.FilterSpec); err != nil {
+		return err
+	}
 
 	// If the RangeFeed is performing a catch-up scan then it will observe all
 	// values above args.Timestamp. If the RangeFeed is requesting previous
@@ -316,7 +319,8 @@ func (r *Replica) RangeFeed(
 	}
 
 	p, err := r.registerWithRangefeedRaftMuLocked(
-		ctx, rSpan, args.Timestamp, catchUpIter, args.WithDiff, args.WithFiltering, omitRemote, stream,
+		ctx, rSpan, args.Timestamp, catchUpIter, args.WithDiff, args.WithFiltering, omitRemote,
+		args.FilterSpec, stream,
 	)
 	r.raftMu.Unlock()
 
@@ -413,6 +417,7 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	withDiff bool,
 	withFiltering bool,
 	withOmitRemote bool,
+	filterSpec *kvpb.RangeFeedFilterSpec,
 	stream rangefeed.Stream,
 ) (rangefeed.Processor, error) {
 	defer logSlowRangefeedRegistration(ctx)()
@@ -434,7 +439,7 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 
 	if p != nil {
 		reg, filter := p.Register(span, startTS, catchUpIter, withDiff, withFiltering, withOmitRemote,
-			stream, func() { r.maybeDisconnectEmptyRangefeed(p) })
+			filterSpec, stream, func() { r.maybeDisconnectEmptyRangefeed(p) })
 		if reg {
 			// Registered successfully with an existing processor.
 			// Update the rangefeed filter to avoid filtering ops
@@ -521,7 +526,7 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	// this ensures that the only time the registration fails is during
 	// server shutdown.
 	reg, filter := p.Register(span, startTS, catchUpIter, withDiff,
-		withFiltering, withOmitRemote, stream, func() { r.maybeDisconnectEmptyRangefeed(p) })
+		withFiltering, withOmitRemote, filterSpec, stream, func() { r.maybeDisconnectEmptyRangefeed(p) })
 	if !reg {
 		select {
 		case <-r.store.Stopper().ShouldQuiesce():
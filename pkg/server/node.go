@@ -15,6 +15,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"os"
 	"sort"
 	"strings"
 	"sync/atomic"
@@ -42,6 +43,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/multitenant"
 	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities"
 	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities/tenantcapabilitieswatcher"
+	otel_metrics_collector_pb "github.com/cockroachdb/cockroach/pkg/obsservice/obspb/opentelemetry-proto/collector/metrics/v1"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/server/status"
@@ -92,6 +94,9 @@ const (
 
 	graphiteIntervalKey = "external.graphite.interval"
 	maxGraphiteInterval = 15 * time.Minute
+
+	otlpMetricsIntervalKey = "external.otlp.metrics.interval"
+	maxOTLPMetricsInterval = 15 * time.Minute
 )
 
 // Metric names.
@@ -227,6 +232,49 @@ var (
 		settings.NonNegativeDurationWithMaximum(maxGraphiteInterval),
 		settings.WithPublic)
 
+	// otlpMetricsEndpoint is the endpoint, if any, of the OpenTelemetry
+	// collector to which metrics are pushed.
+	otlpMetricsEndpoint = settings.RegisterStringSetting(
+		settings.ApplicationLevel,
+		"external.otlp.metrics.endpoint",
+		"if nonempty, push server metrics to the OpenTelemetry collector at the specified endpoint; "+
+			"host:port for gRPC, or a URL for HTTP; TLS is used unless the endpoint has the http:// scheme",
+		"",
+		settings.WithPublic)
+
+	// otlpMetricsCustomCA is the custom root CA, if any, used to verify the
+	// certificate of the OpenTelemetry collector.
+	otlpMetricsCustomCA = settings.RegisterStringSetting(
+		settings.ApplicationLevel,
+		"external.otlp.metrics.custom_ca",
+		"custom root CA (appended to system's default CAs) for verifying the certificate of the "+
+			"OpenTelemetry collector to which metrics are pushed",
+		"",
+		settings.WithPublic)
+
+	// otlpMetricsProtocol is the transport used to push metrics to the
+	// OpenTelemetry collector.
+	otlpMetricsProtocol = settings.RegisterEnumSetting(
+		settings.ApplicationLevel,
+		"external.otlp.metrics.protocol",
+		"the OTLP transport used to push metrics to the OpenTelemetry collector",
+		"grpc",
+		map[metric.OTLPProtocol]string{
+			metric.OTLPProtocolGRPC: "grpc",
+			metric.OTLPProtocolHTTP: "http",
+		},
+		settings.WithPublic)
+
+	// otlpMetricsInterval is how often metrics are pushed to the
+	// OpenTelemetry collector, if enabled.
+	otlpMetricsInterval = settings.RegisterDurationSetting(
+		settings.ApplicationLevel,
+		otlpMetricsIntervalKey,
+		"the interval at which metrics are pushed to the OpenTelemetry collector (if enabled)",
+		10*time.Second,
+		settings.NonNegativeDurationWithMaximum(maxOTLPMetricsInterval),
+		settings.WithPublic)
+
 	RedactServerTracesForSecondaryTenants = settings.RegisterBoolSetting(
 		settings.SystemOnly,
 		"server.secondary_tenants.redact_trace.enabled",
@@ -1330,6 +1378,84 @@ func startGraphiteStatsExporter(
 	})
 }
 
+// startOTLPMetricsExporter starts pushing the metrics of the node to the
+// OpenTelemetry collector configured by external.otlp.metrics.endpoint.
+//
+// Metrics are collected on every interval by one task and pushed by
+// another, so that a slow or unavailable collector does not delay the
+// collection. Only the latest collected metrics are pushed: if the previous
+// request is still being pushed when new metrics are collected, the pending
+// request is replaced by the new one.
+func startOTLPMetricsExporter(
+	ctx context.Context,
+	stopper *stop.Stopper,
+	recorder *status.MetricsRecorder,
+	st *cluster.Settings,
+	nodeID roachpb.NodeID,
+) {
+	ctx = logtags.AddTag(ctx, "otlp metrics exporter", nil)
+	pm := metric.MakePrometheusExporter()
+	resourceAttrs := map[string]string{
+		"service.name":        "cockroachdb",
+		"service.instance.id": nodeID.String(),
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		resourceAttrs["host.name"] = hostname
+	}
+	exporter := metric.NewOTLPExporter(&pm, resourceAttrs)
+	pending := make(chan *otel_metrics_collector_pb.ExportMetricsServiceRequest, 1)
+
+	_ = stopper.RunAsyncTask(ctx, "otlp-metrics-collector", func(ctx context.Context) {
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			timer.Reset(otlpMetricsInterval.Get(&st.SV))
+			select {
+			case <-stopper.ShouldQuiesce():
+				return
+			case <-timer.C:
+				timer.Read = true
+				if otlpMetricsEndpoint.Get(&st.SV) == "" {
+					continue
+				}
+				recorder.ScrapeIntoPrometheus(&pm)
+				req := exporter.Collect()
+				// Drop the pending request, if any, in favor of the new one.
+				select {
+				case <-pending:
+				default:
+				}
+				pending <- req
+			}
+		}
+	})
+
+	_ = stopper.RunAsyncTask(ctx, "otlp-metrics-exporter", func(ctx context.Context) {
+		defer exporter.Close()
+		ctx, cancel := stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+		every := log.Every(time.Minute)
+		for {
+			select {
+			case <-stopper.ShouldQuiesce():
+				return
+			case req := <-pending:
+				endpoint := otlpMetricsEndpoint.Get(&st.SV)
+				protocol := otlpMetricsProtocol.Get(&st.SV)
+				customCA := otlpMetricsCustomCA.Get(&st.SV)
+				// Give up on the request once the next one is due.
+				err := timeutil.RunWithTimeout(ctx, "push metrics", otlpMetricsInterval.Get(&st.SV),
+					func(ctx context.Context) error {
+						return exporter.Push(ctx, protocol, endpoint, customCA, req)
+					})
+				if err != nil && every.ShouldLog() {
+					log.Warningf(ctx, "error pushing metrics to OpenTelemetry collector: %v", err)
+				}
+			}
+		}
+	})
+}
+
 // startWriteNodeStatus begins periodically persisting status summaries for the
 // node and its stores.
 func (n *Node) startWriteNodeStatus(frequency time.Duration) error {
@@ -0,0 +1,82 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	otel_metrics_collector_pb "github.com/cockroachdb/cockroach/pkg/obsservice/obspb/opentelemetry-proto/collector/metrics/v1"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOTLPMetricsExporter tests that a server pushes metrics data to an
+// OpenTelemetry collector, if configured.
+func TestOTLPMetricsExporter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	requests := make(chan *otel_metrics_collector_pb.ExportMetricsServiceRequest, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		var req otel_metrics_collector_pb.ExportMetricsServiceRequest
+		if err := req.Unmarshal(body); err != nil {
+			t.Error(err)
+			return
+		}
+		select {
+		case requests <- &req:
+		default:
+		}
+	}))
+	defer collector.Close()
+
+	s, rawDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.Background())
+
+	const setQ = `SET CLUSTER SETTING "%s" = "%s"`
+	db := sqlutils.MakeSQLRunner(rawDB)
+	db.Exec(t, fmt.Sprintf(setQ, otlpMetricsIntervalKey, 10*time.Millisecond))
+	db.Exec(t, fmt.Sprintf(setQ, "external.otlp.metrics.protocol", "http"))
+	db.Exec(t, fmt.Sprintf(setQ, "external.otlp.metrics.endpoint", collector.URL))
+
+	var req *otel_metrics_collector_pb.ExportMetricsServiceRequest
+	select {
+	case req = <-requests:
+	case <-time.After(45 * time.Second):
+		t.Fatal("timed out waiting for metrics")
+	}
+	require.Len(t, req.ResourceMetrics, 1)
+	rm := req.ResourceMetrics[0]
+	var instanceID string
+	for _, kv := range rm.Resource.Attributes {
+		if kv.Key == "service.instance.id" {
+			instanceID = kv.Value.GetStringValue()
+		}
+	}
+	require.Equal(t, s.NodeID().String(), instanceID)
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.NotEmpty(t, rm.ScopeMetrics[0].Metrics)
+}
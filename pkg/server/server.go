@@ -101,6 +101,7 @@ import (
 	_ "github.com/cockroachdb/cockroach/pkg/sql/importer" // register jobs/planHooks declared outside of pkg/sql
 	"github.com/cockroachdb/cockroach/pkg/sql/optionalnodeliveness"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
+	_ "github.com/cockroachdb/cockroach/pkg/sql/scheduledsql"        // register jobs/planHooks declared outside of pkg/sql
 	_ "github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scjob" // register jobs declared outside of pkg/sql
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
@@ -1929,6 +1930,17 @@ func (s *topLevelServer) PreStart(ctx context.Context) error {
 		}
 	})
 
+	// Export statistics to an OpenTelemetry collector, if enabled by
+	// configuration.
+	var otlpMetricsOnce sync.Once
+	otlpMetricsEndpoint.SetOnChange(&s.st.SV, func(context.Context) {
+		if otlpMetricsEndpoint.Get(&s.st.SV) != "" {
+			otlpMetricsOnce.Do(func() {
+				startOTLPMetricsExporter(workersCtx, s.stopper, s.recorder, s.st, s.NodeID())
+			})
+		}
+	})
+
 	// Start the protected timestamp subsystem. Note that this needs to happen
 	// before the modeOperational switch below, as the protected timestamps
 	// subsystem will crash if accessed before being Started (and serving general
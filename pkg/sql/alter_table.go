@@ -209,6 +209,12 @@ func (n *alterTableNode) startExec(params runParams) error {
 				return err
 			}
 		case *tree.AlterTableAddConstraint:
+			if _, ok := t.ConstraintDef.(*tree.ExclusionConstraintTableDef); ok {
+				// EXCLUDE constraints are added by the declarative schema changer,
+				// which validates the existing rows against each other.
+				return unimplemented.NewWithIssueDetail(46657, "add constraint exclude",
+					"adding an EXCLUDE constraint requires the declarative schema changer")
+			}
 			if skip, err := validateConstraintNameIsNotUsed(n.tableDesc, t); err != nil {
 				return err
 			} else if skip {
@@ -232,6 +238,9 @@ func (n *alterTableNode) startExec(params runParams) error {
 					}
 					continue
 				}
+				if d.Deferrable.IsDeferrable() {
+					return sqlerrors.NewDeferrableUniqueIndexNotSupportedError()
+				}
 
 				if d.PrimaryKey {
 					if t.ValidationBehavior == tree.ValidationSkip {
@@ -536,6 +545,16 @@ func (n *alterTableNode) startExec(params runParams) error {
 			droppedViews = append(droppedViews, colDroppedViews...)
 		case *tree.AlterTableDropConstraint:
 			name := string(t.Constraint)
+			// An exclusion constraint is dropped along with its backing index.
+			if idx := findExclusionConstraintIndex(n.tableDesc, name); idx != nil {
+				if err := params.p.dropIndexByName(
+					params.ctx, tn, tree.UnrestrictedName(name), n.tableDesc, false, /* ifExists */
+					t.DropBehavior, ignoreIdxConstraint, tree.AsStringWithFQNames(n.n, params.Ann()),
+				); err != nil {
+					return err
+				}
+				continue
+			}
 			c := catalog.FindConstraintByName(n.tableDesc, name)
 			if c == nil {
 				if t.IfExists {
@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/decodeusername"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -39,10 +40,14 @@ type alterTypeNode struct {
 var _ planNode = &alterTypeNode{n: nil}
 
 func (p *planner) AlterType(ctx context.Context, n *tree.AlterType) (planNode, error) {
+	stmtName := "ALTER TYPE"
+	if n.IsDomain {
+		stmtName = "ALTER DOMAIN"
+	}
 	if err := checkSchemaChangeEnabled(
 		ctx,
 		p.ExecCfg(),
-		"ALTER TYPE",
+		stmtName,
 	); err != nil {
 		return nil, err
 	}
@@ -86,6 +91,21 @@ func (p *planner) AlterType(ctx context.Context, n *tree.AlterType) (planNode, e
 		)
 	}
 
+	// ALTER DOMAIN only applies to domains, and the domain specific commands
+	// can't be used through ALTER TYPE.
+	isDomain := desc.Kind == descpb.TypeDescriptor_DOMAIN
+	if n.IsDomain && !isDomain {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"%q is not a domain", tree.AsStringWithFQNames(n.Type, &p.semaCtx.Annotations))
+	}
+	switch n.Cmd.(type) {
+	case *tree.AlterDomainSetDefault, *tree.AlterDomainSetNotNull, *tree.AlterDomainAddConstraint,
+		*tree.AlterDomainDropConstraint, *tree.AlterDomainValidateConstraint:
+		if !n.IsDomain {
+			return nil, errors.AssertionFailedf("unexpected domain command in ALTER TYPE")
+		}
+	}
+
 	return &alterTypeNode{
 		n:      n,
 		prefix: prefix,
@@ -130,6 +150,16 @@ func (n *alterTypeNode) startExec(params runParams) error {
 		eventLogDone = true // done inside alterTypeOwner().
 	case *tree.AlterTypeDropValue:
 		err = params.p.dropEnumValue(params.ctx, n.desc, t.Val)
+	case *tree.AlterDomainSetDefault:
+		err = params.p.alterDomainSetDefault(params.ctx, n.desc, t, tree.AsStringWithFQNames(n.n, params.p.Ann()))
+	case *tree.AlterDomainSetNotNull:
+		err = params.p.alterDomainSetNotNull(params.ctx, n.desc, t, tree.AsStringWithFQNames(n.n, params.p.Ann()))
+	case *tree.AlterDomainAddConstraint:
+		err = params.p.alterDomainAddConstraint(params.ctx, n.desc, t, tree.AsStringWithFQNames(n.n, params.p.Ann()))
+	case *tree.AlterDomainDropConstraint:
+		err = params.p.alterDomainDropConstraint(params.ctx, n.desc, t, tree.AsStringWithFQNames(n.n, params.p.Ann()))
+	case *tree.AlterDomainValidateConstraint:
+		err = params.p.alterDomainValidateConstraint(params.ctx, n.desc, t, tree.AsStringWithFQNames(n.n, params.p.Ann()))
 	default:
 		err = errors.AssertionFailedf("unknown alter type cmd %s", t)
 	}
@@ -217,6 +247,108 @@ func (p *planner) dropEnumValue(
 	return p.writeTypeSchemaChange(ctx, desc, desc.Name)
 }
 
+func (p *planner) alterDomainSetDefault(
+	ctx context.Context, desc *typedesc.Mutable, node *tree.AlterDomainSetDefault, jobDesc string,
+) error {
+	if node.Default == nil {
+		desc.Domain.DefaultExpr = nil
+	} else {
+		defaultExpr, err := schemaexpr.ValidateDomainDefaultExpr(
+			ctx, node.Default, desc.Domain.BaseType, p.SemaCtx(),
+		)
+		if err != nil {
+			return err
+		}
+		desc.Domain.DefaultExpr = &defaultExpr
+	}
+	return p.writeTypeSchemaChange(ctx, desc, jobDesc)
+}
+
+func (p *planner) alterDomainSetNotNull(
+	ctx context.Context, desc *typedesc.Mutable, node *tree.AlterDomainSetNotNull, jobDesc string,
+) error {
+	if node.NotNull == desc.Domain.NotNull {
+		return nil
+	}
+	desc.Domain.NotNull = node.NotNull
+	if node.NotNull {
+		// The existing values of the domain are validated by the type schema
+		// changer.
+		desc.Domain.NotNullValidity = descpb.ConstraintValidity_Validating
+	} else {
+		desc.Domain.NotNullValidity = descpb.ConstraintValidity_Validated
+	}
+	return p.writeTypeSchemaChange(ctx, desc, jobDesc)
+}
+
+func (p *planner) alterDomainAddConstraint(
+	ctx context.Context, desc *typedesc.Mutable, node *tree.AlterDomainAddConstraint, jobDesc string,
+) error {
+	c := &node.Constraint
+	switch {
+	case c.Null:
+		return pgerror.New(pgcode.Syntax, "NULL is not a valid constraint to add to a domain")
+	case c.NotNull && node.NotValid:
+		return pgerror.New(pgcode.FeatureNotSupported, "NOT NULL constraints cannot be marked NOT VALID")
+	case c.NotNull && desc.Domain.NotNull:
+		return nil
+	}
+	validity := descpb.ConstraintValidity_Validating
+	if node.NotValid {
+		validity = descpb.ConstraintValidity_Unvalidated
+	}
+	if err := addDomainConstraint(ctx, p.SemaCtx(), desc.Name, desc.Domain, c, validity); err != nil {
+		return err
+	}
+	return p.writeTypeSchemaChange(ctx, desc, jobDesc)
+}
+
+func (p *planner) alterDomainDropConstraint(
+	ctx context.Context, desc *typedesc.Mutable, node *tree.AlterDomainDropConstraint, jobDesc string,
+) error {
+	idx := findDomainConstraint(desc.Domain, string(node.Constraint))
+	if idx == -1 {
+		if node.IfExists {
+			p.BufferClientNotice(
+				ctx,
+				pgnotice.Newf("constraint %q of domain %q does not exist, skipping", node.Constraint, desc.Name),
+			)
+			return nil
+		}
+		return pgerror.Newf(pgcode.UndefinedObject,
+			"constraint %q of domain %q does not exist", node.Constraint, desc.Name)
+	}
+	desc.Domain.Constraints = append(desc.Domain.Constraints[:idx], desc.Domain.Constraints[idx+1:]...)
+	return p.writeTypeSchemaChange(ctx, desc, jobDesc)
+}
+
+func (p *planner) alterDomainValidateConstraint(
+	ctx context.Context,
+	desc *typedesc.Mutable,
+	node *tree.AlterDomainValidateConstraint,
+	jobDesc string,
+) error {
+	idx := findDomainConstraint(desc.Domain, string(node.Constraint))
+	if idx == -1 {
+		return pgerror.Newf(pgcode.UndefinedObject,
+			"constraint %q of domain %q does not exist", node.Constraint, desc.Name)
+	}
+	c := &desc.Domain.Constraints[idx]
+	if c.Validity != descpb.ConstraintValidity_Unvalidated {
+		return nil
+	}
+	// The constraint is already enforced on new values, so the existing values
+	// can be validated in the current transaction. If they aren't valid, the
+	// constraint is left NOT VALID.
+	if err := validateDomainValues(
+		ctx, p.InternalSQLTxn(), desc, false /* validateNotNull */, desc.Domain.Constraints[idx:idx+1],
+	); err != nil {
+		return err
+	}
+	c.Validity = descpb.ConstraintValidity_Validated
+	return p.writeTypeSchemaChange(ctx, desc, jobDesc)
+}
+
 func (p *planner) renameType(ctx context.Context, n *alterTypeNode, newName string) error {
 	err := descs.CheckObjectNameCollision(
 		ctx,
@@ -1624,6 +1624,35 @@ func ValidateConstraint(
 	})
 }
 
+// ValidateExclusionConstraint validates the exclusion constraint backed by the
+// index with the given ID against all rows in `tbl`.
+func ValidateExclusionConstraint(
+	ctx context.Context,
+	tableDesc catalog.TableDescriptor,
+	indexID descpb.IndexID,
+	sessionData *sessiondata.SessionData,
+	runHistoricalTxn descs.HistoricalInternalExecTxnRunner,
+	execOverride sessiondata.InternalExecutorOverride,
+) (err error) {
+	tableDesc, err = tableDesc.MakeFirstMutationPublic(catalog.IgnoreConstraints)
+	if err != nil {
+		return err
+	}
+
+	// The check operates at the historical timestamp.
+	return runHistoricalTxn.Exec(ctx, func(
+		ctx context.Context, txn descs.Txn,
+	) error {
+		defer func() { txn.Descriptors().ReleaseAll(ctx) }()
+		return txn.WithSyntheticDescriptors(
+			[]catalog.Descriptor{tableDesc},
+			func() error {
+				return validateExclusionConstraint(ctx, tableDesc, indexID, txn, sessionData.User())
+			},
+		)
+	})
+}
+
 // canSkipCheckValidation returns true if
 //  1. ck is from a hash-sharded column (because the shard column's computed
 //     expression is a modulo operation and thus the check constraint is
@@ -1808,12 +1837,17 @@ func countExpectedRowsForInvertedIndex(
 	) error {
 		var stmt string
 		geoConfig := idx.GetGeoConfig()
-		if geoConfig.IsEmpty() {
+		vecConfig := idx.GetVecConfig()
+		switch {
+		case !vecConfig.IsEmpty():
+			// Vector indexes contain exactly one entry per row.
+			stmt = fmt.Sprintf(`SELECT count(*) FROM [%d AS t]`, desc.GetID())
+		case geoConfig.IsEmpty():
 			stmt = fmt.Sprintf(
 				`SELECT coalesce(sum_int(crdb_internal.num_inverted_index_entries(%s, %d)), 0) FROM [%d AS t]`,
 				colNameOrExpr, idx.GetVersion(), desc.GetID(),
 			)
-		} else {
+		default:
 			stmt = fmt.Sprintf(
 				`SELECT coalesce(sum_int(crdb_internal.num_geo_inverted_index_entries(%d, %d, %s)), 0) FROM [%d AS t]`,
 				desc.GetID(), idx.GetID(), colNameOrExpr, desc.GetID(),
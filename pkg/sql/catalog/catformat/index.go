@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/errors"
 )
 
@@ -107,7 +108,11 @@ func indexForDisplay(
 		f.WriteString("UNIQUE ")
 	}
 	if !f.HasFlags(tree.FmtPGCatalog) && index.Type == descpb.IndexDescriptor_INVERTED {
-		f.WriteString("INVERTED ")
+		if !index.VecConfig.IsEmpty() {
+			f.WriteString("VECTOR ")
+		} else {
+			f.WriteString("INVERTED ")
+		}
 	}
 	f.WriteString("INDEX ")
 	f.FormatNameP(&index.Name)
@@ -118,7 +123,9 @@ func indexForDisplay(
 
 	if f.HasFlags(tree.FmtPGCatalog) {
 		f.WriteString(" USING")
-		if index.Type == descpb.IndexDescriptor_INVERTED {
+		if !index.VecConfig.IsEmpty() {
+			f.WriteString(" ivfflat")
+		} else if index.Type == descpb.IndexDescriptor_INVERTED {
 			f.WriteString(" gin")
 		} else {
 			f.WriteString(" btree")
@@ -249,6 +256,15 @@ func FormatIndexElements(
 			switch index.InvertedColumnKinds[0] {
 			case catpb.InvertedIndexColumnKind_TRIGRAM:
 				f.WriteString(" gin_trgm_ops")
+			case catpb.InvertedIndexColumnKind_VECTOR:
+				switch index.VecConfig.Metric {
+				case catpb.VectorDistanceMetric_L2:
+					f.WriteString(" vector_l2_ops")
+				case catpb.VectorDistanceMetric_COSINE:
+					f.WriteString(" vector_cosine_ops")
+				case catpb.VectorDistanceMetric_INNER_PRODUCT:
+					f.WriteString(" vector_ip_ops")
+				}
 			}
 		}
 		// The last column of an inverted index cannot have a DESC direction.
@@ -339,6 +355,18 @@ func formatStorageConfigs(
 		}
 	}
 
+	if cfg := index.VecConfig; !cfg.IsEmpty() &&
+		cfg.PartitionBits != vecindex.PartitionBitsForLists(vecindex.DefaultLists) {
+		if numCustomSettings > 0 {
+			f.WriteString(", ")
+		} else {
+			f.WriteString(" WITH (")
+		}
+		f.WriteString(`lists=`)
+		f.WriteString(strconv.FormatInt(int64(1)<<uint(cfg.PartitionBits), 10))
+		numCustomSettings++
+	}
+
 	if index.IsSharded() {
 		if numCustomSettings > 0 {
 			f.WriteString(", ")
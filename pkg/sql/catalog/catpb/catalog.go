@@ -131,3 +131,8 @@ func (rowLevelTTL *RowLevelTTL) GetTTLExpr() Expression {
 	}
 	return DefaultTTLExpirationExpr
 }
+
+// IsEmpty returns whether the config contains a vector index configuration.
+func (cfg VectorIndexConfig) IsEmpty() bool {
+	return cfg.Dims == 0
+}
@@ -113,6 +113,9 @@ func ValidateColumnDefType(ctx context.Context, st *cluster.Settings, t *types.T
 		types.TSQueryFamily, types.TSVectorFamily, types.PGLSNFamily, types.RefCursorFamily:
 	// These types are OK.
 
+	case types.RangeFamily, types.MultirangeFamily:
+		return checkRangeTypesActive(ctx, st)
+
 	case types.TupleFamily:
 		if !t.UserDefined() {
 			return pgerror.New(pgcode.InvalidTableDefinition, "cannot use anonymous record type as table column")
@@ -120,6 +123,13 @@ func ValidateColumnDefType(ctx context.Context, st *cluster.Settings, t *types.T
 		if t.TypeMeta.ImplicitRecordType {
 			return unimplemented.NewWithIssue(70099, "cannot use table record type as table column")
 		}
+		// Composite types may contain ranges, which are encoded as part of the
+		// tuple.
+		for _, typ := range t.TupleContents() {
+			if typ.Family() == types.RangeFamily || typ.Family() == types.MultirangeFamily {
+				return checkRangeTypesActive(ctx, st)
+			}
+		}
 
 	case types.PGVectorFamily:
 		if !st.Version.IsActive(ctx, clusterversion.V24_2) {
@@ -140,6 +150,18 @@ func ValidateColumnDefType(ctx context.Context, st *cluster.Settings, t *types.T
 	return nil
 }
 
+// checkRangeTypesActive returns an error if columns of range and multirange
+// types cannot be created yet. Older nodes cannot decode their encoding.
+func checkRangeTypesActive(ctx context.Context, st *cluster.Settings) error {
+	if !st.Version.IsActive(ctx, clusterversion.V24_3_RangeTypes) {
+		return pgerror.New(
+			pgcode.FeatureNotSupported,
+			"range and multirange types not supported until upgrade to version 24.3 is finalized",
+		)
+	}
+	return nil
+}
+
 // ColumnTypeIsIndexable returns whether the type t is valid as an indexed column.
 func ColumnTypeIsIndexable(t *types.T) bool {
 	// NB: .IsAmbiguous checks the content type of array types.
@@ -167,6 +189,14 @@ func ColumnTypeIsInvertedIndexable(t *types.T) bool {
 		return t.ArrayContents().Family() != types.RefCursorFamily
 	case types.JsonFamily, types.StringFamily:
 		return true
+	case types.RangeFamily:
+		// Ranges can be indexed for overlap queries, except for ranges of
+		// decimals, whose values can't be mapped onto a fixed-size space.
+		return t.RangeContents().Family() != types.DecimalFamily
+	case types.PGVectorFamily:
+		// Vectors can only be indexed by vector indexes, which are a kind of
+		// inverted index.
+		return true
 	}
 	return ColumnTypeIsOnlyInvertedIndexable(t)
 }
@@ -64,6 +64,8 @@ func CanHaveCompositeKeyEncoding(typ *types.T) bool {
 			}
 		}
 		return false
+	case types.RangeFamily, types.MultirangeFamily:
+		return CanHaveCompositeKeyEncoding(typ.RangeContents())
 	case types.BoolFamily,
 		types.IntFamily,
 		types.DateFamily,
@@ -352,6 +352,11 @@ type TableDescriptor interface {
 	// created at, for materialized views and CREATE TABLE AS. Only valid if
 	// IsAs or MaterializedView returns true.
 	GetCreateAsOfTime() hlc.Timestamp
+	// GetMaterializedViewRefreshTime returns the timestamp at which the query of
+	// a materialized view was last evaluated to populate the view, or an empty
+	// timestamp if the view holds no data. Only valid if MaterializedView
+	// returns true.
+	GetMaterializedViewRefreshTime() hlc.Timestamp
 
 	// GetViewQuery returns this view's CREATE VIEW declaration. Only valid if
 	// IsView is true.
@@ -771,6 +776,15 @@ type TableDescriptor interface {
 	// ExternalRowData indicates where the row data for this object is stored if
 	// it is stored outside the span of the object.
 	ExternalRowData() *descpb.ExternalRowData
+	// GetTriggers returns the triggers defined on this table, in the order in
+	// which they were created.
+	GetTriggers() []descpb.TriggerDescriptor
+	// GetNextTriggerID returns the next unused trigger ID for this table.
+	// Trigger IDs are unique per table, but not unique globally.
+	GetNextTriggerID() descpb.TriggerID
+	// GetExclusionConstraints returns the EXCLUDE constraints defined on this
+	// table.
+	GetExclusionConstraints() []descpb.ExclusionConstraint
 }
 
 // MutableTableDescriptor is both a MutableDescriptor and a TableDescriptor.
@@ -828,6 +842,10 @@ type TypeDescriptor interface {
 	// nil otherwise.
 	AsCompositeTypeDescriptor() CompositeTypeDescriptor
 
+	// AsDomainTypeDescriptor returns this instance cast to DomainTypeDescriptor
+	// if this type is a domain, nil otherwise.
+	AsDomainTypeDescriptor() DomainTypeDescriptor
+
 	// AsTableImplicitRecordTypeDescriptor returns this instance cast to
 	// TableImplicitRecordTypeDescriptor if this type is an implicit table record
 	// type, nil otherwise.
@@ -915,6 +933,34 @@ type CompositeTypeDescriptor interface {
 	GetElementType(ordinal int) *types.T
 }
 
+// DomainTypeDescriptor is the TypeDescriptor subtype for domains, which are
+// base types with constraints and a default.
+type DomainTypeDescriptor interface {
+	NonAliasTypeDescriptor
+
+	// GetBaseType returns the type which the domain is defined over.
+	GetBaseType() *types.T
+
+	// GetDomainDefaultExpr returns the serialized default expression of the
+	// domain, and whether it has one.
+	GetDomainDefaultExpr() (string, bool)
+
+	// IsDomainNotNull returns true if the domain does not allow NULL values.
+	IsDomainNotNull() bool
+
+	// GetDomainNotNullValidity returns the validity of the NOT NULL constraint
+	// of the domain.
+	GetDomainNotNullValidity() descpb.ConstraintValidity
+
+	// NumDomainConstraints returns the number of CHECK constraints of the
+	// domain.
+	NumDomainConstraints() int
+
+	// GetDomainConstraint returns the CHECK constraint of the domain at the
+	// given ordinal.
+	GetDomainConstraint(ordinal int) *descpb.TypeDescriptor_Domain_DomainConstraint
+}
+
 // TableImplicitRecordTypeDescriptor is the TypeDescriptor subtype for the
 // record type implicitly defined by a table.
 type TableImplicitRecordTypeDescriptor interface {
@@ -999,6 +1045,10 @@ type FunctionDescriptor interface {
 	// IsProcedure returns true if the descriptor represents a procedure. It
 	// returns false if the descriptor represents a user-defined function.
 	IsProcedure() bool
+
+	// IsAggregate returns true if the descriptor represents a user-defined
+	// aggregate function.
+	IsAggregate() bool
 }
 
 // FilterDroppedDescriptor returns an error if the descriptor state is DROP.
@@ -210,12 +210,26 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 	for i, param := range desc.Params {
 		if param.Type == nil {
 			vea.Report(errors.AssertionFailedf("type not set for arg %d", i))
+		} else if param.Class == catpb.Function_Param_VARIADIC && param.Type.Family() != types.ArrayFamily {
+			vea.Report(errors.AssertionFailedf("variadic arg %d is not an array", i))
 		}
 	}
 
 	vp := funcinfo.MakeVolatilityProperties(desc.Volatility, desc.LeakProof)
 	vea.Report(vp.Validate())
 
+	if agg := desc.Aggregate; agg != nil {
+		if agg.StateType == nil {
+			vea.Report(errors.AssertionFailedf("aggregate state type not set"))
+		}
+		if agg.StateTransitionFunc == 0 {
+			vea.Report(errors.AssertionFailedf("aggregate state transition function not set"))
+		}
+		if desc.IsProcedure() || desc.ReturnType.ReturnSet {
+			vea.Report(errors.AssertionFailedf("aggregate cannot be a procedure or return a set"))
+		}
+	}
+
 	for i, dep := range desc.DependedOnBy {
 		if dep.ID == descpb.InvalidID {
 			vea.Report(errors.AssertionFailedf("invalid relation id %d in depended-on-by references #%d", dep.ID, i))
@@ -330,9 +344,9 @@ func (desc *immutable) validateInboundFunctionRef(
 			backrefFunctionDesc.GetName(), backrefFunctionDesc.GetID())
 	}
 	// Validate all other references are unset.
-	if ref.ColumnIDs != nil || ref.IndexIDs != nil || ref.ConstraintIDs != nil {
-		return errors.AssertionFailedf("function reference has invalid references (%v, %v %v)",
-			ref.ColumnIDs, ref.IndexIDs, ref.ConstraintIDs)
+	if ref.ColumnIDs != nil || ref.IndexIDs != nil || ref.ConstraintIDs != nil || ref.TriggerIDs != nil {
+		return errors.AssertionFailedf("function reference has invalid references (%v, %v, %v, %v)",
+			ref.ColumnIDs, ref.IndexIDs, ref.ConstraintIDs, ref.TriggerIDs)
 	}
 	// Validate a reference exists to this function.
 	for _, refID := range backrefFunctionDesc.GetDependsOnFunctions() {
@@ -409,6 +423,29 @@ func (desc *immutable) validateInboundTableRef(
 			cstID, backRefTbl.GetName(), backRefTbl.GetID(), desc.GetName(), desc.GetID(),
 		)
 	}
+
+	for _, triggerID := range by.TriggerIDs {
+		var trigger *descpb.TriggerDescriptor
+		triggers := backRefTbl.GetTriggers()
+		for i := range triggers {
+			if triggers[i].ID == triggerID {
+				trigger = &triggers[i]
+				break
+			}
+		}
+		if trigger == nil {
+			return errors.AssertionFailedf("depended-on-by relation %q (%d) does not have a trigger with ID %d",
+				backRefTbl.GetName(), by.ID, triggerID)
+		}
+		if trigger.FuncID == desc.GetID() {
+			foundInTable = true
+			continue
+		}
+		return errors.AssertionFailedf(
+			"trigger %d in depended-on-by relation %q (%d) does not have reference to function %q (%d)",
+			triggerID, backRefTbl.GetName(), backRefTbl.GetID(), desc.GetName(), desc.GetID(),
+		)
+	}
 	if foundInTable {
 		return nil
 	}
@@ -704,14 +741,71 @@ func (desc *Mutable) RemoveColumnReference(id descpb.ID, colID descpb.ColumnID)
 	}
 }
 
+// AddTriggerReference adds back reference to a trigger to the function.
+func (desc *Mutable) AddTriggerReference(id descpb.ID, triggerID descpb.TriggerID) error {
+	for _, dep := range desc.DependsOn {
+		if dep == id {
+			return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"cannot add dependency from descriptor %d to function %s (%d) because there will be a dependency cycle", id, desc.GetName(), desc.GetID(),
+			)
+		}
+	}
+	for i := range desc.DependedOnBy {
+		if desc.DependedOnBy[i].ID == id {
+			for _, existing := range desc.DependedOnBy[i].TriggerIDs {
+				if existing == triggerID {
+					return nil
+				}
+			}
+			ids := append(desc.DependedOnBy[i].TriggerIDs, triggerID)
+			sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+			desc.DependedOnBy[i].TriggerIDs = ids
+			return nil
+		}
+	}
+	desc.DependedOnBy = append(
+		desc.DependedOnBy,
+		descpb.FunctionDescriptor_Reference{
+			ID:         id,
+			TriggerIDs: []descpb.TriggerID{triggerID},
+		},
+	)
+	sort.Slice(desc.DependedOnBy, func(i, j int) bool {
+		return desc.DependedOnBy[i].ID < desc.DependedOnBy[j].ID
+	})
+	return nil
+}
+
+// RemoveTriggerReference removes back reference to a trigger from the
+// function.
+func (desc *Mutable) RemoveTriggerReference(id descpb.ID, triggerID descpb.TriggerID) {
+	for i := range desc.DependedOnBy {
+		if desc.DependedOnBy[i].ID == id {
+			ids := desc.DependedOnBy[i].TriggerIDs[:0]
+			for _, existing := range desc.DependedOnBy[i].TriggerIDs {
+				if existing != triggerID {
+					ids = append(ids, existing)
+				}
+			}
+			if len(ids) == 0 {
+				ids = nil
+			}
+			desc.DependedOnBy[i].TriggerIDs = ids
+			desc.maybeRemoveTableReference(id)
+			return
+		}
+	}
+}
+
 // maybeRemoveTableReference removes a table's references from the function if
-// the column, index and constraint references are all empty. This function is
-// only used internally when removing an individual column, index or constraint
-// reference.
+// the column, index, constraint and trigger references are all empty. This
+// function is only used internally when removing an individual column, index,
+// constraint or trigger reference.
 func (desc *Mutable) maybeRemoveTableReference(id descpb.ID) {
 	var ret []descpb.FunctionDescriptor_Reference
 	for _, ref := range desc.DependedOnBy {
-		if ref.ID == id && len(ref.ColumnIDs) == 0 && len(ref.IndexIDs) == 0 && len(ref.ConstraintIDs) == 0 {
+		if ref.ID == id && len(ref.ColumnIDs) == 0 && len(ref.IndexIDs) == 0 &&
+			len(ref.ConstraintIDs) == 0 && len(ref.TriggerIDs) == 0 {
 			continue
 		}
 		ret = append(ret, ref)
@@ -795,6 +889,9 @@ func (desc *immutable) ToOverload() (ret *tree.Overload, err error) {
 		if tree.IsInParamClass(class) {
 			signatureTypes = append(signatureTypes, tree.ParamType{Name: param.Name, Typ: param.Type})
 		}
+		if class == tree.RoutineParamVariadic {
+			ret.Variadic = true
+		}
 		routineParam := tree.RoutineParam{
 			Name:  tree.Name(param.Name),
 			Type:  param.Type,
@@ -822,6 +919,16 @@ func (desc *immutable) ToOverload() (ret *tree.Overload, err error) {
 	if desc.ReturnType.ReturnSet {
 		ret.Class = tree.GeneratorClass
 	}
+	if agg := desc.Aggregate; agg != nil {
+		ret.Class = tree.AggregateClass
+		ret.UDFAggregate = &tree.UDFAggregate{
+			StateTransitionFunc: agg.StateTransitionFunc,
+			StateType:           agg.StateType,
+			InitialCondition:    agg.InitialCondition,
+			FinalFunc:           agg.FinalFunc,
+			CombineFunc:         agg.CombineFunc,
+		}
+	}
 
 	return ret, nil
 }
@@ -901,6 +1008,11 @@ func (desc *immutable) IsProcedure() bool {
 	return desc.FunctionDescriptor.IsProcedure
 }
 
+// IsAggregate implements the FunctionDescriptor interface.
+func (desc *immutable) IsAggregate() bool {
+	return desc.Aggregate != nil
+}
+
 func (desc *immutable) getCreateExprLang() tree.RoutineLanguage {
 	switch desc.Lang {
 	case catpb.Function_SQL:
@@ -54,6 +54,10 @@ func TableDescs(
 		// Reset the version and modification time on this new descriptor.
 		table.Version = 1
 		table.ModificationTime = hlc.Timestamp{}
+		// The MVCC history of the base tables of a restored materialized view
+		// does not go back to its last refresh, so the view must be fully
+		// refreshed before it can be refreshed incrementally again.
+		table.MaterializedViewRefreshTime = hlc.Timestamp{}
 
 		if table.IsView() && overrideDB != "" {
 			// restore checks that all dependencies are also being restored, but if
@@ -657,7 +661,8 @@ func TypeDescs(types []*typedesc.Mutable, descriptorRewrites jobspb.DescRewriteM
 			}
 		}
 		switch t := typ.Kind; t {
-		case descpb.TypeDescriptor_ENUM, descpb.TypeDescriptor_COMPOSITE, descpb.TypeDescriptor_MULTIREGION_ENUM:
+		case descpb.TypeDescriptor_ENUM, descpb.TypeDescriptor_COMPOSITE, descpb.TypeDescriptor_MULTIREGION_ENUM,
+			descpb.TypeDescriptor_DOMAIN:
 			if rw, ok := descriptorRewrites[typ.ArrayTypeID]; ok {
 				typ.ArrayTypeID = rw.ID
 			}
@@ -1079,6 +1084,26 @@ func FunctionDescs(
 			}
 		}
 
+		// Rewrite the support functions and state type of aggregates.
+		if agg := fnDesc.Aggregate; agg != nil {
+			if err := rewriteIDsInTypesT(agg.StateType, descriptorRewrites); err != nil {
+				return err
+			}
+			for _, fnOID := range []*oid.Oid{&agg.StateTransitionFunc, &agg.FinalFunc, &agg.CombineFunc} {
+				if !funcdesc.IsOIDUserDefinedFunc(*fnOID) {
+					continue
+				}
+				funcID := funcdesc.UserDefinedFunctionOIDToID(*fnOID)
+				funcRewrite, ok := descriptorRewrites[funcID]
+				if !ok {
+					return errors.AssertionFailedf(
+						"cannot restore aggregate %q because support function %d was not found",
+						fnDesc.Name, funcID)
+				}
+				*fnOID = catid.FuncIDToOID(funcRewrite.ID)
+			}
+		}
+
 		// Rewrite back reference IDs.
 		for i, dep := range fnDesc.DependedOnBy {
 			if depRewrite, ok := descriptorRewrites[dep.ID]; ok {
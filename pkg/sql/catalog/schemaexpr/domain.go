@@ -0,0 +1,86 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemaexpr
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// DomainValueName is the name by which the CHECK constraints of a domain refer
+// to the value being checked.
+const DomainValueName = tree.Name("value")
+
+// ValidateDomainCheckExpr validates a CHECK constraint expression of a domain
+// over the given base type, and returns the serialized expression if it is
+// valid.
+//
+// A domain check constraint expression is valid if the following are true:
+//
+//   - It results in a boolean.
+//   - It refers to no columns other than VALUE.
+//   - It does not include subqueries.
+//   - It does not include aggregate, window, or set returning functions.
+func ValidateDomainCheckExpr(
+	ctx context.Context, expr tree.Expr, baseType *types.T, semaCtx *tree.SemaContext,
+) (string, error) {
+	replacedExpr, _, err := ReplaceColumnVars(
+		expr,
+		func(columnName tree.Name) (exists bool, accessible bool, id catid.ColumnID, typ *types.T) {
+			if columnName != DomainValueName {
+				return false, false, 0, nil
+			}
+			return true, true, 0, baseType
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	// Subqueries are rejected along with other variable expressions.
+	typedExpr, err := SanitizeVarFreeExpr(
+		ctx,
+		replacedExpr,
+		types.Bool,
+		tree.DomainCheckExpr,
+		semaCtx,
+		volatility.Volatile,
+		false, /* allowAssignmentCast */
+	)
+	if err != nil {
+		return "", err
+	}
+	return tree.Serialize(typedExpr), nil
+}
+
+// ValidateDomainDefaultExpr validates the default expression of a domain over
+// the given base type, and returns the serialized expression if it is valid.
+func ValidateDomainDefaultExpr(
+	ctx context.Context, expr tree.Expr, baseType *types.T, semaCtx *tree.SemaContext,
+) (string, error) {
+	typedExpr, err := SanitizeVarFreeExpr(
+		ctx,
+		expr,
+		baseType,
+		tree.DomainDefaultExpr,
+		semaCtx,
+		volatility.Volatile,
+		true, /* allowAssignmentCast */
+	)
+	if err != nil {
+		return "", err
+	}
+	return tree.Serialize(typedExpr), nil
+}
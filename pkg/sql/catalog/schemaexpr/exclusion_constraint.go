@@ -0,0 +1,39 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemaexpr
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// ValidateExclusionConstraintOperator verifies that op can be used to compare
+// values of type typ in an EXCLUDE constraint.
+//
+// Rows are compared in both directions, so the operator must be commutative,
+// and it must be defined for the column type.
+func ValidateExclusionConstraintOperator(op treecmp.ComparisonOperator, typ *types.T) error {
+	switch op.Symbol {
+	case treecmp.EQ, treecmp.NE, treecmp.Overlaps:
+	default:
+		return pgerror.Newf(pgcode.WrongObjectType,
+			"operator %s is not commutative", op)
+	}
+	folded, _, _, _, _ := tree.FoldComparisonExpr(op, nil /* left */, nil /* right */)
+	if _, ok := tree.CmpOps[folded.Symbol].LookupImpl(typ, typ); !ok {
+		return pgerror.Newf(pgcode.UndefinedFunction,
+			"operator does not exist: %s %s %s", typ.SQLString(), op, typ.SQLString())
+	}
+	return nil
+}
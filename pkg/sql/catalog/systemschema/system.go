@@ -1175,6 +1175,22 @@ CREATE TABLE system.mvcc_statistics (
 			created
 		)
 	);`
+
+	// NotificationsTableSchema stores the notifications generated by NOTIFY
+	// and pg_notify. Each row holds the notifications of a single committed
+	// transaction, in the order in which they were generated; channels and
+	// payloads have the same length. Rows are read by a rangefeed on every node
+	// with listening sessions and are deleted after a retention period.
+	NotificationsTableSchema = `
+CREATE TABLE system.notifications (
+	id       INT8 NOT NULL DEFAULT unique_rowid(),
+	created  TIMESTAMPTZ NOT NULL DEFAULT now():::TIMESTAMPTZ,
+	pid      INT8 NOT NULL,
+	channels STRING[] NOT NULL,
+	payloads STRING[] NOT NULL,
+	CONSTRAINT "primary" PRIMARY KEY (id),
+	FAMILY "primary" (id, created, pid, channels, payloads)
+);`
 )
 
 func pk(name string) descpb.IndexDescriptor {
@@ -1227,7 +1243,7 @@ const SystemDatabaseName = catconstants.SystemDatabaseName
 // release version).
 //
 // NB: Don't set this to clusterversion.Latest; use a specific version instead.
-var SystemDatabaseSchemaBootstrapVersion = clusterversion.V24_3_AddTimeseriesZoneConfig.Version()
+var SystemDatabaseSchemaBootstrapVersion = clusterversion.V24_3_NotificationsTable.Version()
 
 // MakeSystemDatabaseDesc constructs a copy of the system database
 // descriptor.
@@ -1418,6 +1434,7 @@ func MakeSystemTables() []SystemTable {
 		SystemMVCCStatisticsTable,
 		StatementExecInsightsTable,
 		TransactionExecInsightsTable,
+		NotificationsTable,
 	}
 }
 
@@ -4738,6 +4755,28 @@ var (
 			tbl.NextConstraintID++
 		},
 	)
+
+	NotificationsTable = makeSystemTable(
+		NotificationsTableSchema,
+		systemTable(
+			catconstants.NotificationsTableName,
+			descpb.InvalidID, // dynamically assigned table ID
+			[]descpb.ColumnDescriptor{
+				{Name: "id", ID: 1, Type: types.Int, DefaultExpr: &uniqueRowIDString},
+				{Name: "created", ID: 2, Type: types.TimestampTZ, DefaultExpr: &nowTZString},
+				{Name: "pid", ID: 3, Type: types.Int},
+				{Name: "channels", ID: 4, Type: types.StringArray},
+				{Name: "payloads", ID: 5, Type: types.StringArray},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name:        "primary",
+					ColumnNames: []string{"id", "created", "pid", "channels", "payloads"},
+					ColumnIDs:   []descpb.ColumnID{1, 2, 3, 4, 5},
+				},
+			},
+			pk("id"),
+		))
 )
 
 // SpanConfigurationsTableName represents system.span_configurations.
@@ -178,6 +178,7 @@ type Index interface {
 	GetPredicate() string
 	GetType() descpb.IndexDescriptor_Type
 	GetGeoConfig() geopb.Config
+	GetVecConfig() catpb.VectorIndexConfig
 	GetVersion() descpb.IndexDescriptorVersion
 	GetEncodingType() catenumpb.IndexDescriptorEncodingType
 
@@ -652,6 +653,10 @@ type MaterializedViewRefresh interface {
 	// AsOf returns the timestamp at which the query should be run.
 	AsOf() hlc.Timestamp
 
+	// Incremental returns true iff the refresh applies the changes to the base
+	// tables of the view to its existing indexes.
+	Incremental() bool
+
 	// ForEachIndexID iterates through each of the index IDs.
 	// iterutil.StopIteration is supported.
 	ForEachIndexID(func(id descpb.IndexID) error) error
@@ -269,6 +269,11 @@ func (w index) GetGeoConfig() geopb.Config {
 	return w.desc.GeoConfig
 }
 
+// GetVecConfig returns the vector index config in the index descriptor.
+func (w index) GetVecConfig() catpb.VectorIndexConfig {
+	return w.desc.VecConfig
+}
+
 // GetSharded returns the ShardedDescriptor in the index descriptor
 func (w index) GetSharded() catpb.ShardedDescriptor {
 	return w.desc.Sharded
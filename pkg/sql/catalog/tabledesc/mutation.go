@@ -192,6 +192,12 @@ func (c materializedViewRefresh) AsOf() hlc.Timestamp {
 	return c.desc.AsOf
 }
 
+// Incremental returns true iff the refresh applies the changes to the base
+// tables of the view to its existing indexes.
+func (c materializedViewRefresh) Incremental() bool {
+	return c.desc.Incremental
+}
+
 // ForEachIndexID iterates through each of the index IDs.
 // iterutil.StopIteration is supported.
 func (c materializedViewRefresh) ForEachIndexID(fn func(id descpb.IndexID) error) error {
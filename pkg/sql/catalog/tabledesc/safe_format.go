@@ -328,6 +328,9 @@ func formatSafeMutation(w *redact.StringBuilder, m *descpb.DescriptorMutation) {
 		w.Printf("]")
 		w.Printf(", AsOf: %s, ShouldBackfill: %b",
 			md.MaterializedViewRefresh.AsOf, md.MaterializedViewRefresh.ShouldBackfill)
+		if md.MaterializedViewRefresh.Incremental {
+			w.Printf(", Incremental: true")
+		}
 		w.Printf("}")
 	}
 	w.Printf("}")
@@ -382,6 +382,9 @@ func (desc *wrapper) GetAllReferencedFunctionIDs() (catalog.DescriptorIDSet, err
 			ret.Add(id)
 		}
 	}
+	for i := range desc.Triggers {
+		ret.Add(desc.Triggers[i].FuncID)
+	}
 	// TODO(chengxiong): add logic to extract references from indexes when UDFs
 	// are allowed in them.
 	return ret.Union(catalog.MakeDescriptorIDSet(desc.DependsOnFunctions...)), nil
@@ -1644,10 +1647,20 @@ func (desc *Mutable) MakeMutationComplete(m descpb.DescriptorMutation) error {
 			}
 
 		case *descpb.DescriptorMutation_MaterializedViewRefresh:
+			// An incremental refresh wrote to the existing indexes and advanced
+			// the refresh time in the same transaction, so there is nothing left
+			// to do.
+			if t.MaterializedViewRefresh.Incremental {
+				break
+			}
 			// Completing a refresh mutation just means overwriting the table's
 			// indexes with the new indexes that have been backfilled already.
 			desc.SetPrimaryIndex(t.MaterializedViewRefresh.NewPrimaryIndex)
 			desc.SetPublicNonPrimaryIndexes(t.MaterializedViewRefresh.NewIndexes)
+			desc.MaterializedViewRefreshTime = hlc.Timestamp{}
+			if t.MaterializedViewRefresh.ShouldBackfill {
+				desc.MaterializedViewRefreshTime = t.MaterializedViewRefresh.AsOf
+			}
 		}
 
 	case descpb.DescriptorMutation_DROP:
@@ -199,6 +199,11 @@ func (desc *wrapper) ValidateForwardReferences(
 		}
 	}
 
+	// Check all trigger functions exist.
+	for i := range desc.Triggers {
+		vea.Report(desc.validateOutboundFuncRef(desc.Triggers[i].FuncID, vdg))
+	}
+
 	// Check enforced outbound foreign keys.
 	for _, fk := range desc.EnforcedOutboundForeignKeys() {
 		vea.Report(desc.validateOutboundFK(fk.ForeignKeyDesc(), vdg))
@@ -263,6 +268,17 @@ func (desc *wrapper) ValidateBackReferences(
 		}
 	}
 
+	// Check back-references in trigger functions.
+	for i := range desc.Triggers {
+		trigger := &desc.Triggers[i]
+		fn, err := vdg.GetFunctionDescriptor(trigger.FuncID)
+		if err != nil {
+			vea.Report(err)
+			continue
+		}
+		vea.Report(desc.validateOutboundFuncRefBackReferenceForTrigger(fn, trigger.ID))
+	}
+
 	// For views, check dependent relations.
 	if desc.IsView() {
 		for _, id := range desc.DependsOnTypes {
@@ -376,6 +392,23 @@ func (desc *wrapper) validateOutboundFuncRefBackReferenceForColumn(
 		ref.GetName(), ref.GetID())
 }
 
+func (desc *wrapper) validateOutboundFuncRefBackReferenceForTrigger(
+	ref catalog.FunctionDescriptor, triggerID descpb.TriggerID,
+) error {
+	for _, dep := range ref.GetDependedOnBy() {
+		if dep.ID != desc.GetID() {
+			continue
+		}
+		for _, id := range dep.TriggerIDs {
+			if id == triggerID {
+				return nil
+			}
+		}
+	}
+	return errors.AssertionFailedf("depends-on function %q (%d) has no corresponding depended-on-by back reference",
+		ref.GetName(), ref.GetID())
+}
+
 func (desc *wrapper) validateInboundFunctionRef(
 	by descpb.TableDescriptor_Reference, vdg catalog.ValidationDescGetter,
 ) error {
@@ -871,6 +904,8 @@ func (desc *wrapper) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 			desc.validateUniqueWithoutIndexConstraints(columnsByID),
 			desc.validateTableIndexes(columnsByID, vea.IsActive),
 			desc.validatePartitioning(),
+			desc.validateTriggers(),
+			desc.validateExclusionConstraints(columnsByID),
 		}
 		hasErrs := false
 		for _, err := range newErrs {
@@ -1101,6 +1136,66 @@ func (desc *wrapper) validateConstraintNamesAndIDs(vea catalog.ValidationErrorAc
 
 }
 
+// validateTriggers validates that the table's triggers have valid, unique IDs
+// and names.
+func (desc *wrapper) validateTriggers() error {
+	ids := make(map[descpb.TriggerID]struct{}, len(desc.Triggers))
+	names := make(map[string]struct{}, len(desc.Triggers))
+	for i := range desc.Triggers {
+		trigger := &desc.Triggers[i]
+		if trigger.Name == "" {
+			return pgerror.Newf(pgcode.Syntax, "empty trigger name")
+		}
+		if trigger.ID == 0 {
+			return errors.AssertionFailedf("invalid trigger ID %d", errors.Safe(trigger.ID))
+		}
+		if trigger.ID >= desc.NextTriggerID {
+			return errors.AssertionFailedf("trigger %q invalid ID (%d) >= next trigger ID (%d)",
+				trigger.Name, errors.Safe(trigger.ID), errors.Safe(desc.NextTriggerID))
+		}
+		if _, ok := ids[trigger.ID]; ok {
+			return errors.AssertionFailedf("trigger %q duplicate ID: %d", trigger.Name, trigger.ID)
+		}
+		ids[trigger.ID] = struct{}{}
+		if _, ok := names[trigger.Name]; ok {
+			return pgerror.Newf(pgcode.DuplicateObject, "duplicate trigger name: %q", trigger.Name)
+		}
+		names[trigger.Name] = struct{}{}
+		if trigger.FuncID == descpb.InvalidID {
+			return errors.AssertionFailedf("trigger %q has invalid function ID", trigger.Name)
+		}
+		if len(trigger.Events) == 0 {
+			return errors.AssertionFailedf("trigger %q has no events", trigger.Name)
+		}
+	}
+	return nil
+}
+
+// validateExclusionConstraints validates that the table's exclusion
+// constraints refer to existing columns and indexes.
+func (desc *wrapper) validateExclusionConstraints(
+	columnsByID map[descpb.ColumnID]catalog.Column,
+) error {
+	for i := range desc.ExclusionConstraints {
+		ec := &desc.ExclusionConstraints[i]
+		idx := catalog.FindIndexByID(desc, ec.IndexID)
+		if idx == nil {
+			return errors.AssertionFailedf("exclusion constraint refers to missing index %d",
+				errors.Safe(ec.IndexID))
+		}
+		if len(ec.Elements) == 0 {
+			return errors.AssertionFailedf("exclusion constraint %q has no elements", idx.GetName())
+		}
+		for _, elem := range ec.Elements {
+			if _, ok := columnsByID[elem.ColumnID]; !ok {
+				return errors.AssertionFailedf("exclusion constraint %q refers to missing column %d",
+					idx.GetName(), errors.Safe(elem.ColumnID))
+			}
+		}
+	}
+	return nil
+}
+
 func (desc *wrapper) validateColumns() error {
 	columnIDs := make(map[descpb.ColumnID]*descpb.ColumnDescriptor, len(desc.Columns))
 	columnNames := make(map[string]descpb.ColumnID, len(desc.Columns))
@@ -136,6 +136,18 @@ func ensureTypeMetadataIsHydrated(
 		tm.ImplicitRecordType = true
 		return
 	}
+	if d := maybeDesc.AsDomainTypeDescriptor(); d != nil {
+		tm.DomainData = &types.DomainMetadata{NotNull: d.IsDomainNotNull()}
+		tm.DomainData.DefaultExpr, _ = d.GetDomainDefaultExpr()
+		for i := 0; i < d.NumDomainConstraints(); i++ {
+			c := d.GetDomainConstraint(i)
+			tm.DomainData.Checks = append(tm.DomainData.Checks, types.DomainCheck{
+				Name: c.Name,
+				Expr: c.Expr,
+			})
+		}
+		return
+	}
 	if e := maybeDesc.AsEnumTypeDescriptor(); e != nil {
 		if imm, ok := e.(*immutable); ok {
 			// Fast-path for immutable enum descriptors. We can use a pointer into the
@@ -43,6 +43,7 @@ var _ catalog.EnumTypeDescriptor = (*immutable)(nil)
 var _ catalog.RegionEnumTypeDescriptor = (*immutable)(nil)
 var _ catalog.AliasTypeDescriptor = (*immutable)(nil)
 var _ catalog.CompositeTypeDescriptor = (*immutable)(nil)
+var _ catalog.DomainTypeDescriptor = (*immutable)(nil)
 var _ catalog.TypeDescriptor = (*Mutable)(nil)
 var _ catalog.MutableDescriptor = (*Mutable)(nil)
 
@@ -498,6 +499,22 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 		if desc.Composite == nil {
 			vea.Report(errors.AssertionFailedf("COMPOSITE type desc has nil composite type"))
 		}
+	case descpb.TypeDescriptor_DOMAIN:
+		if desc.Domain == nil || desc.Domain.BaseType == nil {
+			vea.Report(errors.AssertionFailedf("DOMAIN type desc has nil base type"))
+			break
+		}
+		if desc.Domain.BaseType.UserDefined() {
+			vea.Report(errors.AssertionFailedf("DOMAIN type desc has user-defined base type %d",
+				desc.Domain.BaseType.Oid()))
+		}
+		names := make(map[string]struct{}, len(desc.Domain.Constraints))
+		for _, c := range desc.Domain.Constraints {
+			if _, ok := names[c.Name]; ok {
+				vea.Report(errors.AssertionFailedf("duplicate domain constraint name %q", c.Name))
+			}
+			names[c.Name] = struct{}{}
+		}
 	case descpb.TypeDescriptor_TABLE_IMPLICIT_RECORD_TYPE:
 		vea.Report(errors.AssertionFailedf("invalid type descriptor: kind %s should never be serialized or validated", desc.Kind.String()))
 	default:
@@ -765,6 +782,12 @@ func (desc *immutable) AsTypesT() *types.T {
 			contents,
 			labels,
 		)
+	case descpb.TypeDescriptor_DOMAIN:
+		return types.MakeDomain(
+			catid.TypeIDToOID(desc.GetID()),
+			catid.TypeIDToOID(desc.ArrayTypeID),
+			desc.Domain.BaseType,
+		)
 	}
 	panic(errors.AssertionFailedf("unsupported descriptor kind %s", desc.Kind.String()))
 }
@@ -847,6 +870,18 @@ func (desc *immutable) HasPendingSchemaChanges() bool {
 			}
 		}
 		return false
+	case descpb.TypeDescriptor_DOMAIN:
+		// Newly added domain constraints must be validated against the existing
+		// values of the domain.
+		if desc.Domain.NotNull && desc.Domain.NotNullValidity == descpb.ConstraintValidity_Validating {
+			return true
+		}
+		for i := range desc.Domain.Constraints {
+			if desc.Domain.Constraints[i].Validity == descpb.ConstraintValidity_Validating {
+				return true
+			}
+		}
+		return false
 	default:
 		return false
 	}
@@ -1003,6 +1038,14 @@ func (desc *immutable) AsCompositeTypeDescriptor() catalog.CompositeTypeDescript
 	return nil
 }
 
+// AsDomainTypeDescriptor implements the catalog.TypeDescriptor interface.
+func (desc *immutable) AsDomainTypeDescriptor() catalog.DomainTypeDescriptor {
+	if desc.Kind == descpb.TypeDescriptor_DOMAIN {
+		return desc
+	}
+	return nil
+}
+
 // AsTableImplicitRecordTypeDescriptor implements the catalog.TypeDescriptor
 // interface.
 func (desc *immutable) AsTableImplicitRecordTypeDescriptor() catalog.TableImplicitRecordTypeDescriptor {
@@ -1029,6 +1072,42 @@ func (desc *immutable) GetElementType(ordinal int) *types.T {
 	return desc.Composite.Elements[ordinal].ElementType
 }
 
+// GetBaseType implements the catalog.DomainTypeDescriptor interface.
+func (desc *immutable) GetBaseType() *types.T {
+	return desc.Domain.BaseType
+}
+
+// GetDomainDefaultExpr implements the catalog.DomainTypeDescriptor interface.
+func (desc *immutable) GetDomainDefaultExpr() (string, bool) {
+	if desc.Domain.DefaultExpr == nil {
+		return "", false
+	}
+	return *desc.Domain.DefaultExpr, true
+}
+
+// IsDomainNotNull implements the catalog.DomainTypeDescriptor interface.
+func (desc *immutable) IsDomainNotNull() bool {
+	return desc.Domain.NotNull
+}
+
+// GetDomainNotNullValidity implements the catalog.DomainTypeDescriptor
+// interface.
+func (desc *immutable) GetDomainNotNullValidity() descpb.ConstraintValidity {
+	return desc.Domain.NotNullValidity
+}
+
+// NumDomainConstraints implements the catalog.DomainTypeDescriptor interface.
+func (desc *immutable) NumDomainConstraints() int {
+	return len(desc.Domain.Constraints)
+}
+
+// GetDomainConstraint implements the catalog.DomainTypeDescriptor interface.
+func (desc *immutable) GetDomainConstraint(
+	ordinal int,
+) *descpb.TypeDescriptor_Domain_DomainConstraint {
+	return &desc.Domain.Constraints[ordinal]
+}
+
 // ForEachRegionInSuperRegion implements the catalog.RegionEnumTypeDescriptor
 // interface.
 func (desc *immutable) ForEachRegionInSuperRegion(
@@ -339,13 +339,25 @@ func validateForeignKey(
 		return err
 	}
 	if values.Len() > 0 {
-		return pgerror.WithConstraintName(pgerror.Newf(pgcode.ForeignKeyViolation,
-			"foreign key violation: %q row %s has no match in %q",
-			srcTable.Name, formatValues(colNames, values), targetTable.GetName()), fk.Name)
+		return newFKValidationErr(srcTable, targetTable, fk.Name, colNames, values)
 	}
 	return nil
 }
 
+// newFKValidationErr returns the error reported when the validation of a
+// foreign key constraint finds a row in srcTable that has no match in
+// targetTable.
+func newFKValidationErr(
+	srcTable, targetTable catalog.TableDescriptor,
+	constraintName string,
+	colNames []string,
+	values tree.Datums,
+) error {
+	return pgerror.WithConstraintName(pgerror.Newf(pgcode.ForeignKeyViolation,
+		"foreign key violation: %q row %s has no match in %q",
+		srcTable.GetName(), formatValues(colNames, values), targetTable.GetName()), constraintName)
+}
+
 // duplicateRowQuery generates and returns a query for column values that
 // violate the specified unique constraint. Rows in the table with any null
 // values in the key are excluded from matching.
@@ -677,31 +689,189 @@ func validateUniqueConstraint(
 		return err
 	}
 	if values.Len() > 0 {
-		valuesStr := make([]string, len(values))
-		for i := range values {
-			valuesStr[i] = values[i].String()
+		return newUniqueValidationErr(constraintName, colNames, values, preExisting)
+	}
+	return nil
+}
+
+// newUniqueValidationErr returns the error reported when the validation of a
+// unique constraint finds duplicated values.
+func newUniqueValidationErr(
+	constraintName string, colNames []string, values tree.Datums, preExisting bool,
+) error {
+	valuesStr := make([]string, len(values))
+	for i := range values {
+		valuesStr[i] = values[i].String()
+	}
+	// Note: this error message mirrors the message produced by Postgres
+	// when it fails to add a unique index due to duplicated keys.
+	errMsg := "could not create unique constraint"
+	if preExisting {
+		errMsg = "failed to validate unique constraint"
+	}
+	return errors.WithDetail(
+		pgerror.WithConstraintName(
+			pgerror.Newf(
+				pgcode.UniqueViolation, "%s %q", errMsg, constraintName,
+			),
+			constraintName,
+		),
+		fmt.Sprintf(
+			"Key (%s)=(%s) is duplicated.", strings.Join(colNames, ","), strings.Join(valuesStr, ","),
+		),
+	)
+}
+
+// exclusionViolationQuery generates and returns a query for a pair of distinct
+// rows of srcTbl that conflict according to the given exclusion constraint.
+// The query mirrors the check that the optimizer plans for mutations of the
+// table: a self-join on the operators of the constraint, which excludes a row
+// from matching itself by comparing the primary keys of the two rows. For
+// example, for the constraint:
+//
+//	EXCLUDE USING GIST (room WITH =, during WITH &&) WHERE (NOT canceled)
+//
+// on a table with primary key k, the query is:
+//
+//	SELECT a.room, a.during, b.room, b.during
+//	FROM (SELECT k, room, during FROM [53 AS t] WHERE (NOT canceled)) AS a
+//	JOIN (SELECT k, room, during FROM [53 AS t] WHERE (NOT canceled)) AS b
+//	ON (a.k != b.k) AND a.room = b.room AND a.during && b.during
+//	LIMIT 1
+//
+// The first half of the returned row holds the values of the constraint
+// columns of one of the rows, and the second half those of the other row.
+func exclusionViolationQuery(
+	srcTbl catalog.TableDescriptor, ec *descpb.ExclusionConstraint, pred string,
+) (sql string, colNames []string, _ error) {
+	colIDs := make([]descpb.ColumnID, len(ec.Elements))
+	for i := range ec.Elements {
+		colIDs[i] = ec.Elements[i].ColumnID
+	}
+	colNames, err := catalog.ColumnNamesForIDs(srcTbl, colIDs)
+	if err != nil {
+		return "", nil, err
+	}
+	pkColNames, err := catalog.ColumnNamesForIDs(srcTbl, srcTbl.GetPrimaryIndex().IndexDesc().KeyColumnIDs)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Project the primary key and constraint columns of the rows that are
+	// subject to the constraint.
+	var srcCols []string
+	seen := make(map[string]struct{}, len(pkColNames)+len(colNames))
+	for _, n := range append(pkColNames, colNames...) {
+		if _, ok := seen[n]; !ok {
+			seen[n] = struct{}{}
+			srcCols = append(srcCols, tree.NameString(n))
 		}
-		// Note: this error message mirrors the message produced by Postgres
-		// when it fails to add a unique index due to duplicated keys.
-		errMsg := "could not create unique constraint"
-		if preExisting {
-			errMsg = "failed to validate unique constraint"
+	}
+	where := ""
+	if pred != "" {
+		where = fmt.Sprintf(" WHERE (%s)", pred)
+	}
+	src := fmt.Sprintf(`SELECT %s FROM [%d AS t]%s`, strings.Join(srcCols, ", "), srcTbl.GetID(), where)
+
+	pkFilters := make([]string, len(pkColNames))
+	for i, n := range pkColNames {
+		pkFilters[i] = fmt.Sprintf("a.%[1]s != b.%[1]s", tree.NameString(n))
+	}
+	onFilters := []string{fmt.Sprintf("(%s)", strings.Join(pkFilters, " OR "))}
+	aCols := make([]string, len(colNames))
+	bCols := make([]string, len(colNames))
+	for i, n := range colNames {
+		aCols[i] = "a." + tree.NameString(n)
+		bCols[i] = "b." + tree.NameString(n)
+		onFilters = append(onFilters, fmt.Sprintf("%s %s %s", aCols[i], ec.Elements[i].Operator, bCols[i]))
+	}
+
+	query := fmt.Sprintf(
+		`SELECT %[1]s, %[2]s FROM (%[3]s) AS a JOIN (%[3]s) AS b ON %[4]s LIMIT 1`,
+		strings.Join(aCols, ", "),        // 1
+		strings.Join(bCols, ", "),        // 2
+		src,                              // 3
+		strings.Join(onFilters, " AND "), // 4
+	)
+	return query, colNames, nil
+}
+
+// validateExclusionConstraint verifies that no two rows of the table conflict
+// according to the exclusion constraint backed by the index with the given ID.
+func validateExclusionConstraint(
+	ctx context.Context,
+	srcTable catalog.TableDescriptor,
+	indexID descpb.IndexID,
+	txn isql.Txn,
+	user username.SQLUsername,
+) error {
+	idx, err := catalog.MustFindIndexByID(srcTable, indexID)
+	if err != nil {
+		return err
+	}
+	var ec *descpb.ExclusionConstraint
+	for i := range srcTable.GetExclusionConstraints() {
+		if c := &srcTable.GetExclusionConstraints()[i]; c.IndexID == indexID {
+			ec = c
+			break
 		}
-		return errors.WithDetail(
-			pgerror.WithConstraintName(
-				pgerror.Newf(
-					pgcode.UniqueViolation, "%s %q", errMsg, constraintName,
-				),
-				constraintName,
-			),
-			fmt.Sprintf(
-				"Key (%s)=(%s) is duplicated.", strings.Join(colNames, ","), strings.Join(valuesStr, ","),
-			),
-		)
+	}
+	if ec == nil {
+		return errors.AssertionFailedf("exclusion constraint on index %d not found in table %q (%d)",
+			indexID, srcTable.GetName(), srcTable.GetID())
+	}
+	query, colNames, err := exclusionViolationQuery(srcTable, ec, idx.GetPredicate())
+	if err != nil {
+		return err
+	}
+
+	log.Infof(ctx, "validating exclusion constraint %q (%q [%v]) with query %q",
+		idx.GetName(),
+		srcTable.GetName(),
+		colNames,
+		query,
+	)
+
+	sessionDataOverride := sessiondata.NoSessionDataOverride
+	sessionDataOverride.User = user
+	values, err := txn.QueryRowEx(ctx, "validate exclusion constraint", txn.KV(), sessionDataOverride, query)
+	if err != nil {
+		return err
+	}
+	if values.Len() > 0 {
+		return newExclusionValidationErr(idx.GetName(), colNames, values)
 	}
 	return nil
 }
 
+// newExclusionValidationErr returns the error reported when the validation of
+// an exclusion constraint finds two conflicting rows, whose constraint column
+// values are the two halves of values.
+func newExclusionValidationErr(
+	constraintName string, colNames []string, values tree.Datums,
+) error {
+	valuesStr := make([]string, len(values))
+	for i := range values {
+		valuesStr[i] = values[i].String()
+	}
+	n := len(colNames)
+	cols := strings.Join(colNames, ", ")
+	// Note: this error message mirrors the message produced by Postgres when it
+	// fails to add an exclusion constraint due to conflicting rows.
+	return errors.WithDetail(
+		pgerror.WithConstraintName(
+			pgerror.Newf(
+				pgcode.ExclusionViolation, "could not create exclusion constraint %q", constraintName,
+			),
+			constraintName,
+		),
+		fmt.Sprintf(
+			"Key (%s)=(%s) conflicts with key (%s)=(%s).",
+			cols, strings.Join(valuesStr[:n], ", "), cols, strings.Join(valuesStr[n:], ", "),
+		),
+	)
+}
+
 // ValidateTTLScheduledJobsInCurrentDB is part of the EvalPlanner interface.
 func (p *planner) ValidateTTLScheduledJobsInCurrentDB(ctx context.Context) error {
 	dbName := p.CurrentDatabase()
@@ -1633,8 +1633,9 @@ func NewColOperator(
 							colIdx[i] = uint32(i)
 						}
 						aggregations := []execinfrapb.AggregatorSpec_Aggregation{{
-							Func:   aggType,
-							ColIdx: colIdx,
+							Func:        aggType,
+							ColIdx:      colIdx,
+							UserDefined: wf.UserDefined,
 						}}
 						aggArgs.Constructors, aggArgs.ConstArguments, aggArgs.OutputTypes, err =
 							colexecagg.ProcessAggregations(ctx, flowCtx.EvalCtx, args.SemaCtx, aggregations, argTypes)
@@ -1300,6 +1300,9 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 	}
 
 	ex.resetExtraTxnState(ctx, txnEvent{eventType: txnEvType}, payloadErr)
+	if ex.notificationListener != nil {
+		ex.server.cfg.NotificationRegistry.unregister(ex.notificationListener)
+	}
 	if ex.hasCreatedTemporarySchema && !ex.server.cfg.TestingKnobs.DisableTempObjectsCleanupOnSessionExit {
 		err := cleanupSessionTempObjects(
 			ctx,
@@ -1468,6 +1471,14 @@ type connExecutor struct {
 		// validateDbZoneConfig should the DB zone config on commit.
 		validateDbZoneConfig bool
 
+		// deferredConstraints tracks the SET CONSTRAINTS modes and the
+		// DEFERRABLE constraints that need to be validated on commit.
+		deferredConstraints deferredConstraintState
+
+		// notifications tracks the notifications sent by NOTIFY and the LISTEN
+		// and UNLISTEN operations, which take effect on commit.
+		notifications txnNotificationState
+
 		// txnCounter keeps track of how many SQL txns have been open since
 		// the start of the session. This is used for logging, to
 		// distinguish statements that belong to separate SQL transactions.
@@ -1741,6 +1752,11 @@ type connExecutor struct {
 	// pgwire cancellation protocol.
 	queryCancelKey pgwirecancel.BackendKeyData
 
+	// notificationListener receives the notifications for the channels the
+	// session listens on. It is created when the session first commits a
+	// LISTEN.
+	notificationListener *notificationListener
+
 	// activated determines whether activate() was called already.
 	// When this is set, close() must be called to release resources.
 	activated bool
@@ -2069,6 +2085,11 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent, pay
 	ex.extraTxnState.upgradedToSerializable = false
 	ex.extraTxnState.hasAdminRoleCache = HasAdminRoleCache{}
 	ex.extraTxnState.createdSequences = nil
+	ex.extraTxnState.deferredConstraints.reset()
+	if ev.eventType == txnCommit {
+		ex.applyListenOps(ev.commitTimestamp)
+	}
+	ex.extraTxnState.notifications.reset()
 
 	if ex.extraTxnState.skipResettingSchemaObjects {
 		if ex.extraTxnState.shouldResetSyntheticDescriptors {
@@ -2478,6 +2499,13 @@ func (ex *connExecutor) execCmd() (retErr error) {
 		}
 		// Note that the Sync result will flush results to the network connection.
 		res = ex.clientComm.CreateSyncResult(pos)
+		if ex.notificationListener != nil {
+			defer func() {
+				if ex.idleConn() {
+					ex.notificationListener.rewake()
+				}
+			}()
+		}
 		if ex.draining {
 			// If we're draining, then after handing the Sync connExecutor state
 			// transition, check whether this is a good time to finish the
@@ -2536,6 +2564,17 @@ func (ex *connExecutor) execCmd() (retErr error) {
 	case Flush:
 		// Closing the res will flush the connection's buffer.
 		res = ex.clientComm.CreateFlushResult(pos)
+	case SendNotifications:
+		notificationRes := ex.clientComm.CreateNotificationResult(pos)
+		res = notificationRes
+		// Like Postgres, notifications are only sent between transactions. The
+		// notifications received during a transaction are sent after the Sync
+		// that follows it.
+		if ex.notificationListener != nil && ex.idleConn() {
+			for _, n := range ex.notificationListener.take() {
+				notificationRes.BufferNotification(n)
+			}
+		}
 	default:
 		panic(errors.AssertionFailedf("unsupported command type: %T", cmd))
 	}
@@ -2805,6 +2844,8 @@ func (ex *connExecutor) updateTxnRewindPosMaybe(
 				canAdvance = true
 			case Flush:
 				canAdvance = true
+			case SendNotifications:
+				canAdvance = true
 			default:
 				panic(errors.AssertionFailedf("unsupported cmd: %T", cmd))
 			}
@@ -3753,6 +3794,12 @@ func (ex *connExecutor) initEvalCtx(ctx context.Context, evalCtx *extendedEvalCo
 		indexUsageStats:      ex.indexUsageStats,
 		statementPreparer:    ex,
 	}
+	// Internal executors running under an outer transaction don't track
+	// deferred constraints; violations they cause are reported immediately.
+	if !ex.extraTxnState.underOuterTxn {
+		evalCtx.deferredConstraints = &ex.extraTxnState.deferredConstraints
+		evalCtx.notifications = &ex.extraTxnState.notifications
+	}
 	evalCtx.copyFromExecCfg(ex.server.cfg)
 }
 
@@ -1582,10 +1582,30 @@ func (ex *connExecutor) commitSQLTransactionInternal(ctx context.Context) (retEr
 		ex.state.mu.txn.ConfigureStepping(ctx, prevSteppingMode)
 	}
 
+	if ex.extraTxnState.deferredConstraints.hasPending() {
+		if err := validateDeferredConstraints(
+			ctx,
+			ex.planner.InternalSQLTxn(),
+			ex.sessionData().User(),
+			&ex.extraTxnState.deferredConstraints,
+			false, /* onlyImmediate */
+		); err != nil {
+			return err
+		}
+	}
+
 	if err := ex.createJobs(ctx); err != nil {
 		return err
 	}
 
+	if ex.extraTxnState.notifications.hasPending() {
+		if err := ex.extraTxnState.notifications.write(
+			ctx, ex.planner.InternalSQLTxn(), ex.queryCancelKey.GetPGBackendPID(),
+		); err != nil {
+			return err
+		}
+	}
+
 	if ex.extraTxnState.schemaChangerState.mode != sessiondatapb.UseNewSchemaChangerOff {
 		if err := ex.runPreCommitStages(ctx); err != nil {
 			return err
@@ -3096,6 +3116,13 @@ func (ex *connExecutor) handleAutoCommit(
 	txn := ex.state.mu.txn
 	if txn.IsCommitted() {
 		log.Event(ctx, "statement execution committed the txn")
+		if ex.extraTxnState.notifications.hasPending() {
+			// Statements don't commit their transaction while it has
+			// notifications to send. See tableWriterBase.finalize.
+			log.Errorf(ctx, "%v", errors.AssertionFailedf(
+				"statement committed the txn with unsent notifications",
+			))
+		}
 		return eventTxnFinishCommitted{}, nil
 	}
 
@@ -105,6 +105,7 @@ func (ex *connExecutor) execSavepointInOpenState(
 		commitOnRelease: commitOnRelease,
 		kvToken:         token,
 		numDDL:          ex.extraTxnState.numDDL,
+		notifications:   ex.extraTxnState.notifications.mark(),
 	}
 	savepoints.push(sp)
 	ex.sessionDataStack.PushTopClone()
@@ -210,6 +211,7 @@ func (ex *connExecutor) execRollbackToSavepointInOpenState(
 		return ev, payload
 	}
 
+	ex.extraTxnState.notifications.rollbackTo(entry.notifications)
 	if err := ex.popSavepointsToIdx(s, idx); err != nil {
 		return ex.makeErrEvent(err, s)
 	}
@@ -286,6 +288,7 @@ func (ex *connExecutor) execRollbackToSavepointInAbortedState(
 		return ev, payload
 	}
 
+	ex.extraTxnState.notifications.rollbackTo(entry.notifications)
 	if err := ex.popSavepointsToIdx(s, idx); err != nil {
 		return ex.makeErrEvent(err, s)
 	}
@@ -357,6 +360,11 @@ type savepoint struct {
 	// more DDL statements were executed since the savepoint's creation.
 	// TODO(knz): support partial DDL cancellation in pending txns.
 	numDDL int
+
+	// notifications records the NOTIFY, LISTEN and UNLISTEN statements that
+	// had been executed in the transaction when the savepoint was created,
+	// so that the later ones are discarded on rollback.
+	notifications notificationMark
 }
 
 type savepointStack []savepoint
@@ -452,6 +452,24 @@ func (DrainRequest) String() string {
 
 var _ Command = DrainRequest{}
 
+// SendNotifications is pushed by the session's notification listener when
+// asynchronous notifications are waiting to be sent to the client. The
+// notifications are sent if the session is not in a transaction; otherwise,
+// they stay queued until the end of the transaction.
+type SendNotifications struct{}
+
+// command implements the Command interface.
+func (SendNotifications) command() string { return "send notifications" }
+
+// isExtendedProtocolCmd implements the Command interface.
+func (SendNotifications) isExtendedProtocolCmd() bool { return false }
+
+func (SendNotifications) String() string {
+	return "SendNotifications"
+}
+
+var _ Command = SendNotifications{}
+
 // SendError is a command that, upon execution, send a specific error to the
 // client. This is used by pgwire to schedule errors to be sent at an
 // appropriate time.
@@ -762,6 +780,9 @@ type ClientComm interface {
 	CreateCopyOutResult(cmd CopyOut, pos CmdPos) CopyOutResult
 	// CreateDrainResult creates a result for a Drain command.
 	CreateDrainResult(pos CmdPos) DrainResult
+	// CreateNotificationResult creates a result for a SendNotifications
+	// command.
+	CreateNotificationResult(pos CmdPos) NotificationResult
 
 	// LockCommunication ensures that no further results are delivered to the
 	// client. The returned ClientLock can be queried to see what results have
@@ -972,6 +993,15 @@ type DrainResult interface {
 	ResultBase
 }
 
+// NotificationResult represents the result of a SendNotifications command.
+// Closing this result sends the buffered notifications to the client.
+type NotificationResult interface {
+	ResultBase
+
+	// BufferNotification buffers a NotificationResponse message.
+	BufferNotification(n Notification)
+}
+
 // EmptyQueryResult represents the result of an empty query (a query
 // representing a blank string).
 type EmptyQueryResult interface {
@@ -13,6 +13,7 @@ package copy
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"strings"
 	"testing"
@@ -197,4 +198,58 @@ func TestCopyOutRandom(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("binary", func(t *testing.T) {
+		// Select all rows again with the values in the binary format.
+		binaryFormats := make([]int16, len(colNames))
+		for i := range binaryFormats {
+			binaryFormats[i] = 1
+		}
+		res := conn.PgConn().ExecParams(
+			ctx, "SELECT * FROM t", nil /* paramValues */, nil /* paramOIDs */, nil, /* paramFormats */
+			binaryFormats,
+		).Read()
+		require.NoError(t, res.Err)
+
+		var buf bytes.Buffer
+		_, err = conn.PgConn().CopyTo(ctx, &buf, "COPY t TO STDOUT BINARY")
+		require.NoError(t, err)
+
+		data := buf.Bytes()
+		require.Equal(t, "PGCOPY\n\377\r\n\000", string(data[:11]))
+		// Skip the signature, the flags and the header extension length.
+		data = data[19:]
+		readInt := func(n int) int {
+			var v int
+			switch n {
+			case 2:
+				v = int(int16(binary.BigEndian.Uint16(data)))
+			case 4:
+				v = int(int32(binary.BigEndian.Uint32(data)))
+			}
+			data = data[n:]
+			return v
+		}
+		for lineNum, row := range res.Rows {
+			require.Equal(t, len(colNames), readInt(2))
+			for fieldNum, expected := range row {
+				var field []byte
+				if l := readInt(4); l >= 0 {
+					field, data = data[:l], data[l:]
+				}
+				require.Equalf(
+					t,
+					expected,
+					field,
+					"error line %d, field %d (%s)",
+					lineNum,
+					fieldNum,
+					colTypes[fieldNum].SQLString(),
+				)
+			}
+		}
+		// The trailer is a field count of -1.
+		require.Equal(t, -1, readInt(2))
+		require.Empty(t, data)
+	})
 }
@@ -13,7 +13,9 @@ package sql
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
 	"io"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
@@ -21,7 +23,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding/csv"
-	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
 
@@ -31,10 +32,14 @@ type copyToTranslater interface {
 	// headerRow returns the header row bytes, if applicable, and a bool to
 	// determine whether one needs to be written.
 	headerRow(rcs colinfo.ResultColumns) ([]byte, bool, error)
+	// trailerRow returns the bytes written after all rows, if applicable, and
+	// a bool to determine whether they need to be written.
+	trailerRow() ([]byte, bool)
 }
 
 var _ copyToTranslater = (*textCopyToTranslater)(nil)
 var _ copyToTranslater = (*csvCopyToTranslater)(nil)
+var _ copyToTranslater = (*binaryCopyToTranslater)(nil)
 
 // textCopyToTranslater is the default text representation of COPY TO from postgres.
 type textCopyToTranslater struct {
@@ -70,6 +75,10 @@ func (t *textCopyToTranslater) headerRow(rcs colinfo.ResultColumns) ([]byte, boo
 	return nil, false, nil
 }
 
+func (t *textCopyToTranslater) trailerRow() ([]byte, bool) {
+	return nil, false
+}
+
 // csvCopyToTranslater is the CSV representation of COPY TO from postgres.
 type csvCopyToTranslater struct {
 	copyOptions
@@ -128,6 +137,47 @@ func (c *csvCopyToTranslater) headerRow(rcs colinfo.ResultColumns) ([]byte, bool
 	return c.b.Bytes(), true, nil
 }
 
+func (c *csvCopyToTranslater) trailerRow() ([]byte, bool) {
+	return nil, false
+}
+
+// binaryCopyToTranslater is the binary representation of COPY TO from
+// postgres. Each row starts with the number of fields, followed by the
+// length-prefixed binary encoding of each field.
+//
+// See: https://www.postgresql.org/docs/current/sql-copy.html#id-1.9.3.55.9.4
+type binaryCopyToTranslater struct {
+	b          bytes.Buffer
+	sessionLoc *time.Location
+}
+
+func (t *binaryCopyToTranslater) translateRow(
+	datums tree.Datums, rcs colinfo.ResultColumns,
+) ([]byte, error) {
+	t.b.Reset()
+	var fieldCount [2]byte
+	binary.BigEndian.PutUint16(fieldCount[:], uint16(len(datums)))
+	t.b.Write(fieldCount[:])
+	for i, d := range datums {
+		if err := pgwirebase.WriteBinaryDatum(&t.b, d, t.sessionLoc, rcs[i].Typ); err != nil {
+			return nil, err
+		}
+	}
+	return t.b.Bytes(), nil
+}
+
+func (t *binaryCopyToTranslater) headerRow(rcs colinfo.ResultColumns) ([]byte, bool, error) {
+	return copyBinarySignature[:], true, nil
+}
+
+// copyBinaryTrailer is the 16-bit field count of -1 which ends the binary
+// COPY format.
+var copyBinaryTrailer = []byte{0xff, 0xff}
+
+func (t *binaryCopyToTranslater) trailerRow() ([]byte, bool) {
+	return copyBinaryTrailer, true
+}
+
 func runCopyTo(
 	ctx context.Context, p *planner, txn *kv.Txn, cmd CopyOut, res CopyOutResult,
 ) (numOutputRows int, retErr error) {
@@ -140,11 +190,10 @@ func runCopyTo(
 	var t copyToTranslater
 	switch cmd.Stmt.Options.CopyFormat {
 	case tree.CopyFormatBinary:
-		// wireFormat = pgwirebase.FormatBinary
-		return 0, unimplemented.NewWithIssue(
-			97180,
-			"binary format for COPY TO not implemented",
-		)
+		wireFormat = pgwirebase.FormatBinary
+		t = &binaryCopyToTranslater{
+			sessionLoc: p.SessionData().GetLocation(),
+		}
 	case tree.CopyFormatCSV:
 		csvTranslater := &csvCopyToTranslater{
 			copyOptions: copyOptions,
@@ -235,6 +284,11 @@ func runCopyTo(
 				return err
 			}
 		}
+		if row, ok := t.trailerRow(); ok {
+			if err := res.SendCopyData(ctx, row, true /* isHeader */); err != nil {
+				return err
+			}
+		}
 		return nil
 	}(); err != nil {
 		return 0, err
@@ -3455,6 +3455,9 @@ func writeCreateTypeDescRow(
 	var typeVariety tree.CreateTypeVariety
 	var typeList []tree.CompositeTypeElem
 	var enumLabels tree.EnumValueList
+	var domainType *types.T
+	var domainDefault tree.Expr
+	var domainConstraints []tree.DomainConstraint
 	enumLabelsDatum := tree.NewDArray(types.String)
 	resolver := p.semaCtx.TypeResolver
 	descriptors := p.descCollection
@@ -3492,6 +3495,28 @@ func writeCreateTypeDescRow(
 			typeList[i].Label = tree.Name(c.GetElementLabel(i))
 		}
 		typeVariety = tree.Composite
+	} else if d := typeDesc.AsDomainTypeDescriptor(); d != nil {
+		typeVariety = tree.Domain
+		domainType = d.GetBaseType()
+		if defaultExpr, ok := d.GetDomainDefaultExpr(); ok {
+			if domainDefault, err = parser.ParseExpr(defaultExpr); err != nil {
+				return false, err
+			}
+		}
+		if d.IsDomainNotNull() {
+			domainConstraints = append(domainConstraints, tree.DomainConstraint{NotNull: true})
+		}
+		for i := 0; i < d.NumDomainConstraints(); i++ {
+			c := d.GetDomainConstraint(i)
+			check, err := parser.ParseExpr(c.Expr)
+			if err != nil {
+				return false, err
+			}
+			domainConstraints = append(domainConstraints, tree.DomainConstraint{
+				Name:  tree.Name(c.Name),
+				Check: check,
+			})
+		}
 	} else {
 		return false, errors.AssertionFailedf("unknown type descriptor kind %s", typeDesc.GetKind())
 	}
@@ -3505,6 +3530,11 @@ func writeCreateTypeDescRow(
 		TypeName:          name,
 		CompositeTypeList: typeList,
 		EnumLabels:        enumLabels,
+		DomainDefault:     domainDefault,
+		DomainConstraints: domainConstraints,
+	}
+	if domainType != nil {
+		node.DomainType = domainType
 	}
 
 	createStatement := tree.AsString(node)
@@ -3522,7 +3552,7 @@ func writeCreateTypeDescRow(
 		tree.NewDInt(tree.DInt(typeDesc.GetID())), // descriptor_id
 		tree.NewDString(typeDesc.GetName()),       // descriptor_name
 		tree.NewDString(createStatement),          // create_statement
-		enumLabelsDatum,                           // empty for composite types
+		enumLabelsDatum,                           // empty for composite types and domains
 	)
 }
 
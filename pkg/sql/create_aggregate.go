@@ -0,0 +1,533 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+type createAggregateNode struct {
+	n      *tree.CreateAggregate
+	dbDesc catalog.DatabaseDescriptor
+	scDesc catalog.SchemaDescriptor
+}
+
+// aggregateDefinition is the result of resolving the options of a CREATE
+// AGGREGATE statement.
+type aggregateDefinition struct {
+	params     []descpb.FunctionDescriptor_Parameter
+	argTypes   []*types.T
+	returnType *types.T
+	volatility volatility.V
+	agg        descpb.FunctionDescriptor_Aggregate
+}
+
+// CreateAggregate creates a user-defined aggregate function.
+// Privileges: CREATE on the schema.
+func (p *planner) CreateAggregate(ctx context.Context, n *tree.CreateAggregate) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE AGGREGATE",
+	); err != nil {
+		return nil, err
+	}
+
+	db, sc, prefix, err := p.ResolveTargetObject(ctx, n.Name.ToUnresolvedObjectName())
+	if err != nil {
+		return nil, err
+	}
+	if err := p.canCreateOnSchema(
+		ctx, sc.GetID(), db.GetID(), p.User(), skipCheckPublicSchema,
+	); err != nil {
+		return nil, err
+	}
+	if sc.SchemaKind() == catalog.SchemaTemporary {
+		return nil, unimplemented.NewWithIssue(104687, "cannot create UDFs under a temporary schema")
+	}
+	n.Name.ObjectNamePrefix = prefix
+	return &createAggregateNode{n: n, dbDesc: db, scDesc: sc}, nil
+}
+
+func (n *createAggregateNode) ReadingOwnWrites() {}
+
+func (n *createAggregateNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("aggregate"))
+
+	// Calls to the aggregate would be ambiguous with calls to a builtin
+	// function of the same name.
+	path := params.p.CurrentSearchPath()
+	unqualifiedName := tree.MakeRoutineNameFromPrefix(tree.ObjectNamePrefix{}, n.n.Name.ObjectName)
+	if def, err := tree.GetBuiltinFuncDefinition(unqualifiedName, &path); err != nil {
+		return err
+	} else if def != nil {
+		return pgerror.Newf(pgcode.DuplicateFunction,
+			"cannot create aggregate %q because a builtin function with the same name exists",
+			n.n.Name.Object())
+	}
+
+	// Look for an existing routine with the same signature.
+	routineObj := tree.RoutineObj{
+		FuncName: n.n.Name,
+		Params:   n.n.Params,
+	}
+	existing, err := params.p.matchRoutine(
+		params.ctx, &routineObj, false, /* required */
+		tree.UDFRoutine|tree.ProcedureRoutine, false, /* inDropContext */
+	)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		if err := n.checkNoNonAggregateOverloads(params); err != nil {
+			return err
+		}
+	} else if !n.n.Replace {
+		return pgerror.Newf(
+			pgcode.DuplicateFunction,
+			"function %q already exists with same argument types",
+			n.n.Name.Object(),
+		)
+	} else if existing.Class != tree.AggregateClass {
+		return errors.WithDetailf(
+			pgerror.Newf(pgcode.WrongObjectType, "cannot change routine kind"),
+			"%q is a function", n.n.Name.Object(),
+		)
+	}
+
+	def, err := n.resolveDefinition(params)
+	if err != nil {
+		return err
+	}
+
+	var fnDesc *funcdesc.Mutable
+	if existing == nil {
+		fnDesc, err = n.createAggregateDesc(params, def)
+	} else {
+		fnDesc, err = n.replaceAggregateDesc(params, def, existing)
+	}
+	if err != nil {
+		return err
+	}
+
+	fnName := tree.MakeQualifiedRoutineName(n.dbDesc.GetName(), n.scDesc.GetName(), n.n.Name.Object())
+	return params.p.logEvent(params.ctx, fnDesc.GetID(), &eventpb.CreateFunction{
+		FunctionName: fnName.FQString(),
+		IsReplace:    existing != nil,
+	})
+}
+
+func (*createAggregateNode) Next(params runParams) (bool, error) { return false, nil }
+func (*createAggregateNode) Values() tree.Datums                 { return tree.Datums{} }
+func (*createAggregateNode) Close(ctx context.Context)           {}
+
+// resolveDefinition resolves the arguments and the options of the aggregate.
+func (n *createAggregateNode) resolveDefinition(params runParams) (*aggregateDefinition, error) {
+	ctx, p := params.ctx, params.p
+	def := &aggregateDefinition{
+		params:   make([]descpb.FunctionDescriptor_Parameter, len(n.n.Params)),
+		argTypes: make([]*types.T, len(n.n.Params)),
+	}
+	for i, param := range n.n.Params {
+		if param.Class != tree.RoutineParamDefault && param.Class != tree.RoutineParamIn {
+			return nil, pgerror.New(pgcode.InvalidFunctionDefinition,
+				"aggregate arguments must be IN parameters")
+		}
+		pbParam, err := makeFunctionParam(ctx, p.SemaCtx(), param, p)
+		if err != nil {
+			return nil, err
+		}
+		if pbParam.Type.IsPolymorphicType() {
+			return nil, unimplemented.New("polymorphic aggregate",
+				"aggregates with polymorphic arguments are not supported")
+		}
+		def.params[i] = pbParam
+		def.argTypes[i] = pbParam.Type
+	}
+
+	if len(def.argTypes) == 0 {
+		return nil, unimplemented.New("aggregate without arguments",
+			"aggregates without arguments are not supported")
+	}
+
+	var sfunc, stype, finalFunc, combineFunc, initCond *tree.AggregateOption
+	for i := range n.n.Options {
+		opt := &n.n.Options[i]
+		var target **tree.AggregateOption
+		switch opt.Name {
+		case "sfunc":
+			target = &sfunc
+		case "stype":
+			target = &stype
+		case "finalfunc":
+			target = &finalFunc
+		case "combinefunc":
+			target = &combineFunc
+		case "initcond":
+			target = &initCond
+		default:
+			return nil, pgerror.Newf(pgcode.Syntax, "aggregate attribute %q not recognized", opt.Name)
+		}
+		if *target != nil {
+			return nil, tree.ErrConflictingRoutineOption
+		}
+		*target = opt
+	}
+	if sfunc == nil {
+		return nil, pgerror.New(pgcode.InvalidFunctionDefinition, "aggregate sfunc must be specified")
+	}
+	if stype == nil {
+		return nil, pgerror.New(pgcode.InvalidFunctionDefinition, "aggregate stype must be specified")
+	}
+	if stype.Type == nil {
+		return nil, pgerror.New(pgcode.Syntax, "aggregate stype must be a type name")
+	}
+	stateType, err := tree.ResolveType(ctx, stype.Type, p)
+	if err != nil {
+		return nil, err
+	}
+	if stateType.IsPolymorphicType() {
+		return nil, unimplemented.New("polymorphic aggregate",
+			"aggregates with a polymorphic state type are not supported")
+	}
+	def.agg.StateType = stateType
+
+	if initCond != nil {
+		var s string
+		switch v := initCond.Value.(type) {
+		case *tree.StrVal:
+			s = v.RawString()
+		case *tree.NumVal:
+			s = v.OrigString()
+		default:
+			return nil, pgerror.New(pgcode.Syntax, "aggregate initcond must be a string or numeric literal")
+		}
+		if _, err := eval.PerformCast(ctx, params.EvalContext(), tree.NewDString(s), stateType); err != nil {
+			return nil, errors.Wrapf(err, "invalid initial value for aggregate")
+		}
+		def.agg.InitialCondition = &s
+	}
+
+	transitionTypes := append([]*types.T{stateType}, def.argTypes...)
+	sf, err := n.resolveSupportFunc(params, sfunc, transitionTypes)
+	if err != nil {
+		return nil, err
+	}
+	if !sf.InferReturnTypeFromInputArgTypes(transitionTypes).Equivalent(stateType) {
+		return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+			"return type of transition function %s is not %s",
+			tree.ErrString(sfunc.Type), stateType.SQLStringForError())
+	}
+	if !sf.CalledOnNullInput && initCond == nil && !def.argTypes[0].Equivalent(stateType) {
+		return nil, pgerror.New(pgcode.InvalidFunctionDefinition,
+			"must not omit initial value when transition function is strict and "+
+				"transition type is not compatible with input type")
+	}
+	def.agg.StateTransitionFunc = sf.Oid
+	def.agg.StrictStateTransition = !sf.CalledOnNullInput
+	def.volatility = sf.Volatility
+
+	def.returnType = stateType
+	if finalFunc != nil {
+		finalTypes := []*types.T{stateType}
+		ff, err := n.resolveSupportFunc(params, finalFunc, finalTypes)
+		if err != nil {
+			return nil, err
+		}
+		def.returnType = ff.InferReturnTypeFromInputArgTypes(finalTypes)
+		def.agg.FinalFunc = ff.Oid
+		if ff.Volatility > def.volatility {
+			def.volatility = ff.Volatility
+		}
+	}
+
+	if combineFunc != nil {
+		combineTypes := []*types.T{stateType, stateType}
+		cf, err := n.resolveSupportFunc(params, combineFunc, combineTypes)
+		if err != nil {
+			return nil, err
+		}
+		if !cf.InferReturnTypeFromInputArgTypes(combineTypes).Equivalent(stateType) {
+			return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+				"return type of combine function %s is not %s",
+				tree.ErrString(combineFunc.Type), stateType.SQLStringForError())
+		}
+		def.agg.CombineFunc = cf.Oid
+		if cf.Volatility > def.volatility {
+			def.volatility = cf.Volatility
+		}
+	}
+	return def, nil
+}
+
+// resolveSupportFunc resolves the function named by the given option of the
+// aggregate which accepts arguments of the given types.
+func (n *createAggregateNode) resolveSupportFunc(
+	params runParams, opt *tree.AggregateOption, argTypes []*types.T,
+) (*tree.Overload, error) {
+	name, ok := opt.Type.(*tree.UnresolvedObjectName)
+	if !ok {
+		return nil, pgerror.Newf(pgcode.Syntax,
+			"aggregate %s must be a function name", opt.Name)
+	}
+	path := params.p.CurrentSearchPath()
+	fnDef, err := params.p.ResolveFunction(
+		params.ctx, tree.MakeUnresolvedFunctionName(name.ToUnresolvedName()), &path,
+	)
+	if err != nil {
+		return nil, err
+	}
+	routineObj := tree.RoutineObj{
+		FuncName: name.ToRoutineName(),
+		Params:   make(tree.RoutineParams, len(argTypes)),
+	}
+	for i, typ := range argTypes {
+		routineObj.Params[i] = tree.RoutineParam{Type: typ}
+	}
+	ol, err := fnDef.MatchOverload(
+		params.ctx, params.p, &routineObj, &path, tree.BuiltinRoutine|tree.UDFRoutine,
+		false /* inDropContext */, false, /* tryDefaultExprs */
+	)
+	if err != nil {
+		return nil, err
+	}
+	if ol.Class != tree.NormalClass {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"function %s%s cannot be used as aggregate %s",
+			fnDef.Name, ol.Signature(true /* simplify */), opt.Name)
+	}
+	if ol.Type == tree.BuiltinRoutine {
+		return ol.Overload, nil
+	}
+	// The overloads of user-defined functions in the resolved definition only
+	// contain the signature.
+	_, fn, err := params.p.ResolveFunctionByOID(params.ctx, ol.Oid)
+	if err != nil {
+		return nil, err
+	}
+	fnDesc, err := params.p.Descriptors().ByIDWithLeased(params.p.Txn()).Get().Function(
+		params.ctx, funcdesc.UserDefinedFunctionOIDToID(ol.Oid),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if dbID := fnDesc.GetParentID(); dbID != n.dbDesc.GetID() && dbID != keys.SystemDatabaseID {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"dependent function %s cannot be from another database", fnDesc.GetName())
+	}
+	return fn, nil
+}
+
+// checkNoNonAggregateOverloads returns an error if the schema already contains
+// a function or procedure with the name of the aggregate, since calls to the
+// aggregate would be ambiguous with calls to it.
+func (n *createAggregateNode) checkNoNonAggregateOverloads(params runParams) error {
+	fn, found := n.scDesc.GetFunction(n.n.Name.Object())
+	if !found {
+		return nil
+	}
+	for i := range fn.Signatures {
+		if !fn.Signatures[i].IsAggregate {
+			return pgerror.Newf(pgcode.DuplicateFunction,
+				"cannot create aggregate %q because a function with the same name exists",
+				n.n.Name.Object())
+		}
+	}
+	return nil
+}
+
+func (n *createAggregateNode) createAggregateDesc(
+	params runParams, def *aggregateDefinition,
+) (*funcdesc.Mutable, error) {
+	ctx, p := params.ctx, params.p
+	id, err := params.EvalContext().DescIDGenerator.GenerateUniqueDescID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	privileges, err := catprivilege.CreatePrivilegesFromDefaultPrivileges(
+		n.dbDesc.GetDefaultPrivilegeDescriptor(),
+		n.scDesc.GetDefaultPrivilegeDescriptor(),
+		n.dbDesc.GetID(),
+		params.SessionData().User(),
+		privilege.Routines,
+	)
+	if err != nil {
+		return nil, err
+	}
+	fnDesc := funcdesc.NewMutableFunctionDescriptor(
+		id,
+		n.dbDesc.GetID(),
+		n.scDesc.GetID(),
+		n.n.Name.Object(),
+		def.params,
+		def.returnType,
+		false, /* returnSet */
+		false, /* isProcedure */
+		privileges,
+	)
+	n.setAggregate(&fnDesc, def)
+	if err := n.addAggregateReferences(params, &fnDesc); err != nil {
+		return nil, err
+	}
+	if err := p.createDescriptor(
+		ctx, &fnDesc, tree.AsStringWithFQNames(&n.n.Name, params.Ann()),
+	); err != nil {
+		return nil, err
+	}
+
+	scDesc, err := p.descCollection.MutableByName(p.Txn()).Schema(ctx, n.dbDesc, n.scDesc.GetName())
+	if err != nil {
+		return nil, err
+	}
+	scDesc.AddFunction(
+		fnDesc.GetName(),
+		descpb.SchemaDescriptor_FunctionSignature{
+			ID:          fnDesc.GetID(),
+			ArgTypes:    def.argTypes,
+			ReturnType:  def.returnType,
+			IsAggregate: true,
+		},
+	)
+	if err := p.writeSchemaDescChange(ctx, scDesc, "Create Aggregate"); err != nil {
+		return nil, err
+	}
+	return &fnDesc, nil
+}
+
+func (n *createAggregateNode) replaceAggregateDesc(
+	params runParams, def *aggregateDefinition, existing *tree.QualifiedOverload,
+) (*funcdesc.Mutable, error) {
+	ctx, p := params.ctx, params.p
+	fnDesc, err := p.checkPrivilegesForDropFunction(ctx, funcdesc.UserDefinedFunctionOIDToID(existing.Oid))
+	if err != nil {
+		return nil, err
+	}
+	if !def.returnType.Equivalent(fnDesc.ReturnType.Type) {
+		return nil, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"cannot change return type of existing function")
+	}
+
+	// Remove the existing references before adding the new ones.
+	for _, id := range fnDesc.DependsOnFunctions {
+		backRefDesc, err := p.Descriptors().MutableByID(p.Txn()).Function(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := backRefDesc.RemoveFunctionReference(fnDesc.ID); err != nil {
+			return nil, err
+		}
+		if err := p.writeFuncSchemaChange(ctx, backRefDesc); err != nil {
+			return nil, err
+		}
+	}
+	jobDesc := fmt.Sprintf("updating type back reference %d for aggregate %d", fnDesc.DependsOnTypes, fnDesc.ID)
+	if err := p.removeTypeBackReferences(ctx, fnDesc.DependsOnTypes, fnDesc.ID, jobDesc); err != nil {
+		return nil, err
+	}
+
+	fnDesc.Params = def.params
+	n.setAggregate(fnDesc, def)
+	if err := n.addAggregateReferences(params, fnDesc); err != nil {
+		return nil, err
+	}
+	return fnDesc, p.writeFuncSchemaChange(ctx, fnDesc)
+}
+
+// setAggregate sets the properties of the function descriptor that are derived
+// from the aggregate definition.
+func (n *createAggregateNode) setAggregate(fnDesc *funcdesc.Mutable, def *aggregateDefinition) {
+	agg := def.agg
+	fnDesc.Aggregate = &agg
+	fnDesc.SetLeakProof(false)
+	switch def.volatility {
+	case volatility.Leakproof, volatility.Immutable:
+		fnDesc.SetVolatility(catpb.Function_IMMUTABLE)
+	case volatility.Stable:
+		fnDesc.SetVolatility(catpb.Function_STABLE)
+	default:
+		fnDesc.SetVolatility(catpb.Function_VOLATILE)
+	}
+}
+
+// addAggregateReferences adds references from the aggregate to the
+// user-defined support functions and types it uses, and the corresponding
+// back references.
+func (n *createAggregateNode) addAggregateReferences(
+	params runParams, fnDesc *funcdesc.Mutable,
+) error {
+	ctx, p := params.ctx, params.p
+	var funcIDs catalog.DescriptorIDSet
+	agg := fnDesc.Aggregate
+	for _, fnOID := range [...]oid.Oid{agg.StateTransitionFunc, agg.FinalFunc, agg.CombineFunc} {
+		if funcdesc.IsOIDUserDefinedFunc(fnOID) {
+			funcIDs.Add(funcdesc.UserDefinedFunctionOIDToID(fnOID))
+		}
+	}
+	fnDesc.DependsOnFunctions = funcIDs.Ordered()
+	for _, id := range fnDesc.DependsOnFunctions {
+		backRefDesc, err := p.Descriptors().MutableByID(p.Txn()).Function(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := backRefDesc.AddFunctionReference(fnDesc.ID); err != nil {
+			return err
+		}
+		if err := p.writeFuncSchemaChange(ctx, backRefDesc); err != nil {
+			return err
+		}
+	}
+
+	var typeIDs catalog.DescriptorIDSet
+	addTypeIDs := func(typ *types.T) {
+		typedesc.GetTypeDescriptorClosure(typ).ForEach(typeIDs.Add)
+	}
+	for _, param := range fnDesc.Params {
+		addTypeIDs(param.Type)
+	}
+	addTypeIDs(agg.StateType)
+	addTypeIDs(fnDesc.ReturnType.Type)
+	fnDesc.DependsOnTypes = typeIDs.Ordered()
+	for _, id := range fnDesc.DependsOnTypes {
+		if isTable, err := p.descIsTable(ctx, id); err != nil {
+			return err
+		} else if isTable {
+			return unimplemented.New("aggregate table type",
+				"aggregates using the row types of tables are not supported")
+		}
+		jobDesc := fmt.Sprintf("updating type back reference %d for aggregate %d", id, fnDesc.ID)
+		if err := p.addTypeBackReference(ctx, id, fnDesc.ID, jobDesc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
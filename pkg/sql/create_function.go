@@ -160,11 +160,15 @@ func (n *createFunctionNode) createNewFunction(
 	var outParamOrdinals []int32
 	var outParamTypes []*types.T
 	var defaultExprs []string
+	var isVariadic bool
 	for paramIdx, param := range udfDesc.Params {
 		class := funcdesc.ToTreeRoutineParamClass(param.Class)
 		if tree.IsInParamClass(class) {
 			signatureTypes = append(signatureTypes, param.Type)
 		}
+		if class == tree.RoutineParamVariadic {
+			isVariadic = true
+		}
 		if class == tree.RoutineParamOut {
 			outParamOrdinals = append(outParamOrdinals, int32(paramIdx))
 			outParamTypes = append(outParamTypes, param.Type)
@@ -184,6 +188,7 @@ func (n *createFunctionNode) createNewFunction(
 			OutParamOrdinals: outParamOrdinals,
 			OutParamTypes:    outParamTypes,
 			DefaultExprs:     defaultExprs,
+			IsVariadic:       isVariadic,
 		},
 	)
 	if err := params.p.writeSchemaDescChange(params.ctx, scDesc, "Create Function"); err != nil {
@@ -200,10 +205,12 @@ func (n *createFunctionNode) replaceFunction(
 	existing *tree.QualifiedOverload,
 ) error {
 
-	if n.cf.IsProcedure != udfDesc.IsProcedure() {
+	if n.cf.IsProcedure != udfDesc.IsProcedure() || udfDesc.IsAggregate() {
 		formatStr := "%q is a function"
 		if udfDesc.IsProcedure() {
 			formatStr = "%q is a procedure"
+		} else if udfDesc.IsAggregate() {
+			formatStr = "%q is an aggregate function"
 		}
 		return errors.WithDetailf(
 			pgerror.Newf(pgcode.WrongObjectType, "cannot change routine kind"),
@@ -247,11 +254,15 @@ func (n *createFunctionNode) replaceFunction(
 	var outParamOrdinals []int32
 	var outParamTypes []*types.T
 	var defaultExprs []string
+	var isVariadic bool
 	for i, p := range n.cf.Params {
 		udfDesc.Params[i], err = makeFunctionParam(params.ctx, params.p.SemaCtx(), p, params.p)
 		if err != nil {
 			return err
 		}
+		if p.Class == tree.RoutineParamVariadic {
+			isVariadic = true
+		}
 		if p.Class == tree.RoutineParamOut {
 			outParamOrdinals = append(outParamOrdinals, int32(i))
 			outParamTypes = append(outParamTypes, udfDesc.Params[i].Type)
@@ -310,7 +321,8 @@ func (n *createFunctionNode) replaceFunction(
 		return err
 	}
 
-	signatureChanged := len(existing.OutParamOrdinals) != len(outParamOrdinals) ||
+	signatureChanged := existing.Variadic != isVariadic ||
+		len(existing.OutParamOrdinals) != len(outParamOrdinals) ||
 		len(existing.DefaultExprs) != len(defaultExprs)
 	for i := 0; !signatureChanged && i < len(outParamOrdinals); i++ {
 		signatureChanged = existing.OutParamOrdinals[i] != outParamOrdinals[i] ||
@@ -339,6 +351,7 @@ func (n *createFunctionNode) replaceFunction(
 				OutParamOrdinals: outParamOrdinals,
 				OutParamTypes:    outParamTypes,
 				DefaultExprs:     defaultExprs,
+				IsVariadic:       isVariadic,
 			},
 		); err != nil {
 			return err
@@ -35,8 +35,10 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/storageparam"
 	"github.com/cockroachdb/cockroach/pkg/sql/storageparam/indexstorageparam"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 	"github.com/cockroachdb/errors"
 )
 
@@ -229,9 +231,15 @@ func makeIndexDescriptor(
 			return nil, err
 		}
 		if err := populateInvertedIndexDescriptor(
-			params.ctx, params.ExecCfg().Settings, column, &indexDesc, invCol); err != nil {
+			params.ctx, params.ExecCfg().Settings, column, &indexDesc, invCol, n.Vector,
+		); err != nil {
 			return nil, err
 		}
+		if n.Vector {
+			if err := checkVectorIndexColumns(columns); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if n.Sharded != nil {
@@ -297,6 +305,9 @@ func makeIndexDescriptor(
 		if len(indexDesc.InvertedColumnKinds) > 0 && indexDesc.InvertedColumnKinds[0] == catpb.InvertedIndexColumnKind_TRIGRAM {
 			telemetry.Inc(sqltelemetry.TrigramInvertedIndexCounter)
 		}
+		if !indexDesc.VecConfig.IsEmpty() {
+			telemetry.Inc(sqltelemetry.VectorInvertedIndexCounter)
+		}
 		if indexDesc.IsPartial() {
 			telemetry.Inc(sqltelemetry.PartialInvertedIndexCounter)
 		}
@@ -361,15 +372,20 @@ func checkIndexColumns(
 // populateInvertedIndexDescriptor adds information to the input index descriptor
 // for the inverted index given by the input column and invCol, which should
 // match (column is the catalog column, and invCol is the grammar node of
-// the column in the index creation statement).
+// the column in the index creation statement). vector is true if the index is
+// a vector index.
 func populateInvertedIndexDescriptor(
 	ctx context.Context,
 	cs *cluster.Settings,
 	column catalog.Column,
 	indexDesc *descpb.IndexDescriptor,
 	invCol tree.IndexElem,
+	vector bool,
 ) error {
 	indexDesc.InvertedColumnKinds = []catpb.InvertedIndexColumnKind{catpb.InvertedIndexColumnKind_DEFAULT}
+	if vector {
+		return populateVectorIndexDescriptor(column, indexDesc, invCol)
+	}
 	switch column.GetType().Family() {
 	case types.ArrayFamily:
 		switch invCol.OpClass {
@@ -418,12 +434,73 @@ func populateInvertedIndexDescriptor(
 		default:
 			return newUndefinedOpclassError(invCol.OpClass)
 		}
+	case types.RangeFamily:
+		switch invCol.OpClass {
+		case "range_ops", "":
+		default:
+			return newUndefinedOpclassError(invCol.OpClass)
+		}
+	case types.PGVectorFamily:
+		return errors.WithHint(
+			tabledesc.NewInvalidInvertedColumnError(column.GetName(), column.GetType().Name()),
+			"use CREATE VECTOR INDEX to index vectors for nearest neighbor search",
+		)
 	default:
 		return tabledesc.NewInvalidInvertedColumnError(column.GetName(), column.GetType().Name())
 	}
 	return nil
 }
 
+// populateVectorIndexDescriptor populates the index descriptor of a vector
+// index on the given column. The partitioning of the index can be adjusted
+// afterwards with the "lists" storage parameter.
+func populateVectorIndexDescriptor(
+	column catalog.Column, indexDesc *descpb.IndexDescriptor, invCol tree.IndexElem,
+) error {
+	typ := column.GetType()
+	if typ.Family() != types.PGVectorFamily {
+		return pgerror.Newf(pgcode.UndefinedObject,
+			"data type %s has no default operator class for access method \"ivfflat\"", typ.Name())
+	}
+	var metric catpb.VectorDistanceMetric
+	switch invCol.OpClass {
+	case "vector_l2_ops", "":
+		metric = catpb.VectorDistanceMetric_L2
+	case "vector_cosine_ops":
+		metric = catpb.VectorDistanceMetric_COSINE
+	case "vector_ip_ops":
+		metric = catpb.VectorDistanceMetric_INNER_PRODUCT
+	default:
+		return newUndefinedOpclassError(invCol.OpClass)
+	}
+	if invCol.Direction == tree.Descending {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"the column in a vector index cannot have the DESC option")
+	}
+	if typ.Width() == 0 {
+		return errors.WithHint(
+			pgerror.Newf(pgcode.FeatureNotSupported, "column %s does not have dimensions", column.GetName()),
+			"vector indexes require a fixed number of dimensions, for example VECTOR(3)",
+		)
+	}
+	indexDesc.InvertedColumnKinds[0] = catpb.InvertedIndexColumnKind_VECTOR
+	indexDesc.VecConfig = vecindex.MakeConfig(
+		typ.Width(), vecindex.DefaultLists, metric, randutil.NewPseudoSeed(),
+	)
+	return nil
+}
+
+// checkVectorIndexColumns verifies that a vector index indexes a single
+// column. Vector indexes cannot have prefix columns, since nearest neighbor
+// searches do not constrain them.
+func checkVectorIndexColumns(columns tree.IndexElemList) error {
+	if len(columns) != 1 {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"vector indexes can only be created on a single column")
+	}
+	return nil
+}
+
 func newUndefinedOpclassError(opclass tree.Name) error {
 	return pgerror.Newf(pgcode.UndefinedObject, "operator class %q does not exist", opclass)
 }
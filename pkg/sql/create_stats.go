@@ -230,6 +230,19 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 		)
 	}
 
+	if len(n.Options.Kinds) > 0 {
+		if len(n.ColumnNames) == 0 {
+			return nil, pgerror.New(pgcode.InvalidParameterValue,
+				"statistics kinds can only be specified for an explicit list of columns",
+			)
+		}
+		if n.Options.UsingExtremes {
+			return nil, pgerror.New(pgcode.FeatureNotSupported,
+				"cannot specify statistics kinds for partial statistics at extremes",
+			)
+		}
+	}
+
 	if err := n.p.CheckPrivilege(ctx, tableDesc, privilege.SELECT); err != nil {
 		return nil, err
 	}
@@ -293,11 +306,43 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 		_ = stats.MakeSortedColStatKey(columnIDs)
 		isInvIndex := colinfo.ColumnTypeIsOnlyInvertedIndexable(col.GetType())
 		defaultHistogramBuckets := stats.GetDefaultHistogramBuckets(n.p.ExecCfg().SV(), tableDesc)
+		hasHistogram := len(columnIDs) == 1 && !isInvIndex
+		if len(columnIDs) > 1 && stats.MultiColumnHistogramClusterMode.Get(n.p.ExecCfg().SV()) {
+			hasHistogram = canHaveMultiColumnHistogram(columns)
+		}
+		var hasDependencies, hasMostCommonValues bool
+		if len(n.Options.Kinds) > 0 {
+			// The requested kinds override the defaults, including
+			// sql.stats.multi_column_histograms.enabled.
+			hasDependencies = n.Options.HasKind(tree.CreateStatsKindDependencies)
+			hasMostCommonValues = n.Options.HasKind(tree.CreateStatsKindMostCommonValues)
+			if !n.Options.HasKind(tree.CreateStatsKindHistogram) {
+				hasHistogram = false
+				isInvIndex = false
+			} else if len(columnIDs) > 1 {
+				hasHistogram = true
+			}
+			if len(columnIDs) == 1 && (hasDependencies || hasMostCommonValues) {
+				return nil, pgerror.New(pgcode.InvalidParameterValue,
+					"dependencies and most common values statistics require at least two columns",
+				)
+			}
+			if len(columnIDs) > 1 && !canHaveMultiColumnHistogram(columns) {
+				return nil, pgerror.New(pgcode.FeatureNotSupported,
+					"cannot create multi-column statistics kinds on inverted or user-defined type columns",
+				)
+			}
+		}
 		colStats = []jobspb.CreateStatsDetails_ColStat{{
 			ColumnIDs: columnIDs,
 			// By default, create histograms on all explicitly requested column stats
-			// with a single column that doesn't use an inverted index.
-			HasHistogram:        len(columnIDs) == 1 && !isInvIndex,
+			// with a single column that doesn't use an inverted index. Histograms on
+			// explicitly requested multi-column stats are controlled by
+			// sql.stats.multi_column_histograms.enabled, unless the statistics kinds
+			// are specified.
+			HasHistogram:        hasHistogram,
+			HasDependencies:     hasDependencies,
+			HasMostCommonValues: hasMostCommonValues,
 			HistogramMaxBuckets: defaultHistogramBuckets,
 		}}
 		// Make histograms for inverted index column types.
@@ -357,6 +402,21 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 // when choosing a default set of column statistics.
 const maxNonIndexCols = 100
 
+// canHaveMultiColumnHistogram returns whether a histogram can be collected
+// over tuples of the values of the given columns. Histograms over inverted
+// columns are built from inverted index keys, and the types of user-defined
+// columns nested in a tuple are not hydrated when the histogram is decoded,
+// so neither is supported.
+func canHaveMultiColumnHistogram(columns []catalog.Column) bool {
+	for _, col := range columns {
+		typ := col.GetType()
+		if colinfo.ColumnTypeIsOnlyInvertedIndexable(typ) || typ.UserDefined() {
+			return false
+		}
+	}
+	return true
+}
+
 // createStatsDefaultColumns creates column statistics on a default set of
 // column lists when no columns were specified by the caller.
 //
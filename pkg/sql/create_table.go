@@ -50,6 +50,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/semenumpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treebin"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
@@ -672,6 +673,7 @@ func addUniqueWithoutIndexColumnTableDef(
 		string(d.Unique.ConstraintName),
 		[]string{string(d.Name)},
 		"", /* predicate */
+		tree.ConstraintNotDeferrable,
 		ts,
 		validationBehavior,
 	); err != nil {
@@ -735,7 +737,7 @@ func addUniqueWithoutIndexTableDef(
 		colNames[i] = string(d.Columns[i].Column)
 	}
 	if err := ResolveUniqueWithoutIndexConstraint(
-		ctx, desc, string(d.Name), colNames, predicate, ts, validationBehavior,
+		ctx, desc, string(d.Name), colNames, predicate, d.Deferrable, ts, validationBehavior,
 	); err != nil {
 		return err
 	}
@@ -755,6 +757,7 @@ func ResolveUniqueWithoutIndexConstraint(
 	constraintName string,
 	colNames []string,
 	predicate string,
+	deferrability tree.ConstraintDeferrability,
 	ts TableState,
 	validationBehavior tree.ValidationBehavior,
 ) error {
@@ -803,12 +806,13 @@ func ResolveUniqueWithoutIndexConstraint(
 	}
 
 	uc := descpb.UniqueWithoutIndexConstraint{
-		Name:         constraintName,
-		TableID:      tbl.ID,
-		ColumnIDs:    columnIDs,
-		Predicate:    predicate,
-		Validity:     validity,
-		ConstraintID: tbl.NextConstraintID,
+		Name:          constraintName,
+		TableID:       tbl.ID,
+		ColumnIDs:     columnIDs,
+		Predicate:     predicate,
+		Validity:      validity,
+		ConstraintID:  tbl.NextConstraintID,
+		Deferrability: semenumpb.ConstraintDeferrability(deferrability),
 	}
 	tbl.NextConstraintID++
 	if ts == NewTable {
@@ -1063,6 +1067,7 @@ func ResolveFK(
 		OnUpdate:            tree.ForeignKeyReferenceActionValue[d.Actions.Update],
 		Match:               tree.CompositeKeyMatchMethodValue[d.Match],
 		ConstraintID:        tbl.NextConstraintID,
+		Deferrability:       semenumpb.ConstraintDeferrability(d.Deferrable),
 	}
 	tbl.NextConstraintID++
 	if ts == NewTable {
@@ -1806,6 +1811,16 @@ func NewTableDesc(
 	}
 
 	for _, def := range n.Defs {
+		// The index backing an EXCLUDE constraint is built like any other
+		// secondary index. The constraint itself is added below, once the index
+		// has an ID.
+		if d, ok := def.(*tree.ExclusionConstraintTableDef); ok {
+			defer func(name tree.Name) { d.Name = name }(d.Name)
+			if err := prepareExclusionConstraint(&desc, d); err != nil {
+				return nil, err
+			}
+			def = &d.IndexTableDef
+		}
 		switch d := def.(type) {
 		case *tree.ColumnTableDef, *tree.LikeTableDef:
 			// pass, handled above.
@@ -1869,9 +1884,15 @@ func NewTableDesc(
 					return nil, err
 				}
 				if err := populateInvertedIndexDescriptor(
-					ctx, evalCtx.Settings, column, &idx, columns[len(columns)-1]); err != nil {
+					ctx, evalCtx.Settings, column, &idx, columns[len(columns)-1], d.Vector,
+				); err != nil {
 					return nil, err
 				}
+				if d.Vector {
+					if err := checkVectorIndexColumns(columns); err != nil {
+						return nil, err
+					}
+				}
 			}
 
 			var idxPartitionBy *tree.PartitionBy
@@ -1931,6 +1952,9 @@ func NewTableDesc(
 				// We will add the unique constraint below.
 				break
 			}
+			if d.Deferrable.IsDeferrable() {
+				return nil, sqlerrors.NewDeferrableUniqueIndexNotSupportedError()
+			}
 			// If the index is named, ensure that the name is unique. Unnamed
 			// indexes will be given a unique auto-generated name later on when
 			// AllocateIDs is called.
@@ -2221,6 +2245,11 @@ func NewTableDesc(
 		case *tree.IndexTableDef, *tree.FamilyTableDef, *tree.LikeTableDef:
 			// Pass, handled above.
 
+		case *tree.ExclusionConstraintTableDef:
+			if err := addExclusionConstraint(&desc, d); err != nil {
+				return nil, err
+			}
+
 		case *tree.CheckConstraintTableDef:
 			ck, err := ckBuilder.Build(d, version)
 			if err != nil {
@@ -2291,6 +2320,9 @@ func NewTableDesc(
 			if idx.InvertedColumnKind() == catpb.InvertedIndexColumnKind_TRIGRAM {
 				telemetry.Inc(sqltelemetry.TrigramInvertedIndexCounter)
 			}
+			if idx.InvertedColumnKind() == catpb.InvertedIndexColumnKind_VECTOR {
+				telemetry.Inc(sqltelemetry.VectorInvertedIndexCounter)
+			}
 			if idx.IsPartial() {
 				telemetry.Inc(sqltelemetry.PartialInvertedIndexCounter)
 			}
@@ -2850,6 +2882,72 @@ func setSequenceOwner(
 	return nil
 }
 
+// prepareExclusionConstraint validates the elements of an EXCLUDE constraint
+// and names the constraint if it is unnamed, so that its backing index can be
+// created from d.IndexTableDef.
+func prepareExclusionConstraint(desc *tabledesc.Mutable, d *tree.ExclusionConstraintTableDef) error {
+	colNames := make([]string, len(d.Columns))
+	for i := range d.Columns {
+		col, err := catalog.MustFindColumnByTreeName(desc, d.Columns[i].Column)
+		if err != nil {
+			return err
+		}
+		colNames[i] = col.GetName()
+		if err := schemaexpr.ValidateExclusionConstraintOperator(d.Operators[i], col.GetType()); err != nil {
+			return err
+		}
+	}
+	if d.Name == "" {
+		d.Name = tree.Name(tabledesc.GenerateUniqueName(
+			fmt.Sprintf("%s_%s_excl", desc.GetName(), strings.Join(colNames, "_")),
+			func(name string) bool {
+				return catalog.FindIndexByName(desc, name) != nil
+			},
+		))
+	}
+	return nil
+}
+
+// addExclusionConstraint adds an EXCLUDE constraint to desc. The backing index
+// of the constraint must already have been added to desc and assigned an ID.
+func addExclusionConstraint(desc *tabledesc.Mutable, d *tree.ExclusionConstraintTableDef) error {
+	idx, err := catalog.MustFindIndexByName(desc, string(d.Name))
+	if err != nil {
+		return err
+	}
+	ec := descpb.ExclusionConstraint{
+		IndexID:  idx.GetID(),
+		Elements: make([]descpb.ExclusionConstraint_Element, len(d.Columns)),
+	}
+	for i := range d.Columns {
+		col, err := catalog.MustFindColumnByTreeName(desc, d.Columns[i].Column)
+		if err != nil {
+			return err
+		}
+		ec.Elements[i] = descpb.ExclusionConstraint_Element{
+			ColumnID: col.GetID(),
+			Operator: treecmp.ComparisonOpName(d.Operators[i].Symbol),
+		}
+	}
+	desc.ExclusionConstraints = append(desc.ExclusionConstraints, ec)
+	return nil
+}
+
+// findExclusionConstraintIndex returns the index backing the exclusion
+// constraint with the given name, or nil if there is no such constraint.
+func findExclusionConstraintIndex(desc catalog.TableDescriptor, name string) catalog.Index {
+	idx := catalog.FindIndexByName(desc, name)
+	if idx == nil || idx.Dropped() {
+		return nil
+	}
+	for _, ec := range desc.GetExclusionConstraints() {
+		if ec.IndexID == idx.GetID() {
+			return idx
+		}
+	}
+	return nil
+}
+
 // validateUniqueConstraintParamsForCreateTable validate storage params of
 // unique constraints passed in through `CREATE TABLE` statement.
 func validateUniqueConstraintParamsForCreateTable(n *tree.CreateTable) error {
@@ -0,0 +1,147 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/errors"
+)
+
+type createTriggerNode struct {
+	ct *tree.CreateTrigger
+
+	planDeps     planDependencies
+	typeDeps     typeDependencies
+	functionDeps functionDependencies
+}
+
+func (n *createTriggerNode) ReadingOwnWrites() {}
+
+func (n *createTriggerNode) startExec(params runParams) error {
+	if !params.p.IsActive(params.ctx, clusterversion.V24_3_Triggers) {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"CREATE TRIGGER not supported until upgrade to version 24.3 is finalized")
+	}
+	// The optimizer records the table on which the trigger is defined and the
+	// trigger function as the only dependencies.
+	if len(n.planDeps) != 1 || len(n.functionDeps) != 1 {
+		return errors.AssertionFailedf(
+			"expected one table and one function dependency for CREATE TRIGGER, found %d and %d",
+			len(n.planDeps), len(n.functionDeps),
+		)
+	}
+	var tableID, funcID descpb.ID
+	for id := range n.planDeps {
+		tableID = id
+	}
+	for id := range n.functionDeps {
+		funcID = id
+	}
+
+	tableDesc, err := params.p.Descriptors().MutableByID(params.p.txn).Table(params.ctx, tableID)
+	if err != nil {
+		return err
+	}
+	fnDesc, err := params.p.Descriptors().MutableByID(params.p.txn).Function(params.ctx, funcID)
+	if err != nil {
+		return err
+	}
+	if fnDesc.GetParentID() != tableDesc.GetParentID() {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"trigger function %s cannot be from another database", fnDesc.GetName(),
+		)
+	}
+
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("trigger"))
+
+	trigger := descpb.TriggerDescriptor{
+		Name:       string(n.ct.Name),
+		ActionTime: tree.TriggerActionTimeToProto[n.ct.ActionTime],
+		ForEachRow: n.ct.ForEach == tree.TriggerForEachRow,
+		FuncID:     funcID,
+		FuncArgs:   n.ct.FuncArgs,
+		Enabled:    true,
+	}
+	for _, event := range n.ct.Events {
+		descEvent := &descpb.TriggerDescriptor_Event{
+			Type: tree.TriggerEventTypeToProto[event.EventType],
+		}
+		for _, colName := range event.Columns {
+			descEvent.ColumnNames = append(descEvent.ColumnNames, string(colName))
+		}
+		trigger.Events = append(trigger.Events, descEvent)
+	}
+	if n.ct.When != nil {
+		trigger.WhenExpr = tree.Serialize(n.ct.When)
+	}
+
+	if existing := findTrigger(tableDesc, trigger.Name); existing != nil {
+		if !n.ct.Replace {
+			return pgerror.Newf(pgcode.DuplicateObject,
+				"trigger %q for relation %q already exists", trigger.Name, tableDesc.GetName(),
+			)
+		}
+		// Replace the existing trigger, keeping its ID. The back-reference from
+		// the old trigger function must be removed first, since the function
+		// may have changed.
+		oldFnDesc, err := params.p.Descriptors().MutableByID(params.p.txn).Function(params.ctx, existing.FuncID)
+		if err != nil {
+			return err
+		}
+		oldFnDesc.RemoveTriggerReference(tableDesc.GetID(), existing.ID)
+		if err := params.p.writeFuncSchemaChange(params.ctx, oldFnDesc); err != nil {
+			return err
+		}
+		trigger.ID = existing.ID
+		*existing = trigger
+	} else {
+		if tableDesc.NextTriggerID == 0 {
+			tableDesc.NextTriggerID = 1
+		}
+		trigger.ID = tableDesc.NextTriggerID
+		tableDesc.NextTriggerID++
+		tableDesc.Triggers = append(tableDesc.Triggers, trigger)
+	}
+
+	if err := fnDesc.AddTriggerReference(tableDesc.GetID(), trigger.ID); err != nil {
+		return err
+	}
+	if err := params.p.writeFuncSchemaChange(params.ctx, fnDesc); err != nil {
+		return err
+	}
+	return params.p.writeSchemaChange(
+		params.ctx, tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.ct, params.Ann()),
+	)
+}
+
+func (*createTriggerNode) Next(params runParams) (bool, error) { return false, nil }
+func (*createTriggerNode) Values() tree.Datums                 { return tree.Datums{} }
+func (*createTriggerNode) Close(ctx context.Context)           {}
+
+// findTrigger returns the trigger with the given name on the given table, or
+// nil if there is no such trigger.
+func findTrigger(tableDesc *tabledesc.Mutable, name string) *descpb.TriggerDescriptor {
+	for i := range tableDesc.Triggers {
+		if tableDesc.Triggers[i].Name == name {
+			return &tableDesc.Triggers[i]
+		}
+	}
+	return nil
+}
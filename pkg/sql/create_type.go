@@ -14,6 +14,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
@@ -21,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/enum"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -67,6 +69,10 @@ func (p *planner) CreateType(ctx context.Context, n *tree.CreateType) (planNode,
 	); err != nil {
 		return nil, err
 	}
+	if n.Variety == tree.Domain && !p.IsActive(ctx, clusterversion.V24_3_Domains) {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"CREATE DOMAIN not supported until upgrade to version 24.3 is finalized")
+	}
 
 	// Resolve the desired new type name.
 	typeName, db, err := resolveNewTypeName(ctx, p, n.TypeName)
@@ -237,6 +243,8 @@ func CreateUserDefinedArrayTypeDesc(
 			labels[i] = e.ElementLabel
 		}
 		elemTyp = types.NewCompositeType(catid.TypeIDToOID(typDesc.GetID()), catid.TypeIDToOID(id), contents, labels)
+	case descpb.TypeDescriptor_DOMAIN:
+		elemTyp = types.MakeDomain(catid.TypeIDToOID(typDesc.GetID()), catid.TypeIDToOID(id), typDesc.Domain.BaseType)
 	default:
 		return nil, errors.AssertionFailedf("cannot make array type for kind %s", t.String())
 	}
@@ -318,6 +326,8 @@ func (p *planner) createUserDefinedType(params runParams, n *createTypeNode) err
 		return params.p.createCompositeWithID(
 			params, id, n.n.CompositeTypeList, n.dbDesc, n.typeName,
 		)
+	case tree.Domain:
+		return params.p.createDomainWithID(params, id, n.n, n.dbDesc, n.typeName)
 	}
 	return unimplemented.NewWithIssue(25123, "CREATE TYPE")
 }
@@ -461,6 +471,141 @@ func createCompositeTypeDesc(
 	}).BuildCreatedMutableType(), nil
 }
 
+// createDomainTypeDesc creates a new domain type descriptor.
+func createDomainTypeDesc(
+	params runParams,
+	id descpb.ID,
+	n *tree.CreateType,
+	dbDesc catalog.DatabaseDescriptor,
+	schema catalog.SchemaDescriptor,
+	typeName *tree.TypeName,
+) (*typedesc.Mutable, error) {
+	baseType, err := tree.ResolveType(params.ctx, n.DomainType, params.p.semaCtx.TypeResolver)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateDomainBaseType(baseType); err != nil {
+		return nil, err
+	}
+	if err = tree.CheckUnsupportedType(params.ctx, &params.p.semaCtx, baseType); err != nil {
+		return nil, err
+	}
+
+	domain := &descpb.TypeDescriptor_Domain{BaseType: baseType}
+	if n.DomainDefault != nil {
+		defaultExpr, err := schemaexpr.ValidateDomainDefaultExpr(
+			params.ctx, n.DomainDefault, baseType, params.p.SemaCtx(),
+		)
+		if err != nil {
+			return nil, err
+		}
+		domain.DefaultExpr = &defaultExpr
+	}
+	var sawNull, sawNotNull bool
+	for i := range n.DomainConstraints {
+		c := &n.DomainConstraints[i]
+		sawNull = sawNull || c.Null
+		sawNotNull = sawNotNull || c.NotNull
+		if sawNull && sawNotNull {
+			return nil, pgerror.New(pgcode.Syntax, "conflicting NULL/NOT NULL constraints")
+		}
+		if err := addDomainConstraint(
+			params.ctx, params.p.SemaCtx(), typeName.Type(), domain, c, descpb.ConstraintValidity_Validated,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	privs, err := catprivilege.CreatePrivilegesFromDefaultPrivileges(
+		dbDesc.GetDefaultPrivilegeDescriptor(),
+		schema.GetDefaultPrivilegeDescriptor(),
+		dbDesc.GetID(),
+		params.SessionData().User(),
+		privilege.Types,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return typedesc.NewBuilder(&descpb.TypeDescriptor{
+		Name:           typeName.Type(),
+		ID:             id,
+		ParentID:       dbDesc.GetID(),
+		ParentSchemaID: schema.GetID(),
+		Kind:           descpb.TypeDescriptor_DOMAIN,
+		Domain:         domain,
+		Version:        1,
+		Privileges:     privs,
+	}).BuildCreatedMutableType(), nil
+}
+
+// validateDomainBaseType returns an error if a domain cannot be defined over
+// the given type.
+func validateDomainBaseType(typ *types.T) error {
+	switch typ.Family() {
+	case types.AnyFamily, types.VoidFamily, types.TriggerFamily, types.UnknownFamily:
+		return pgerror.Newf(pgcode.DatatypeMismatch,
+			"%q is not a valid base type for a domain", typ.SQLString())
+	case types.ArrayFamily, types.TupleFamily:
+		return unimplemented.NewWithIssue(27796, "domains over array and composite types")
+	}
+	if typ.UserDefined() || typ.IsWildcardType() {
+		return unimplemented.NewWithIssue(27796, "domains over user-defined types")
+	}
+	return nil
+}
+
+// addDomainConstraint validates the given constraint and adds it to the
+// domain. A CHECK constraint without a name is named after the domain.
+func addDomainConstraint(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	domainName string,
+	domain *descpb.TypeDescriptor_Domain,
+	c *tree.DomainConstraint,
+	validity descpb.ConstraintValidity,
+) error {
+	switch {
+	case c.NotNull:
+		domain.NotNull = true
+		domain.NotNullValidity = validity
+		return nil
+	case c.Null:
+		return nil
+	}
+	name := string(c.Name)
+	if name == "" {
+		name = domainName + "_check"
+		for i := 1; findDomainConstraint(domain, name) != -1; i++ {
+			name = fmt.Sprintf("%s_check%d", domainName, i)
+		}
+	} else if findDomainConstraint(domain, name) != -1 {
+		return pgerror.Newf(pgcode.DuplicateObject,
+			"constraint %q for domain %q already exists", name, domainName)
+	}
+	expr, err := schemaexpr.ValidateDomainCheckExpr(ctx, c.Check, domain.BaseType, semaCtx)
+	if err != nil {
+		return err
+	}
+	domain.Constraints = append(domain.Constraints, descpb.TypeDescriptor_Domain_DomainConstraint{
+		Name:     name,
+		Expr:     expr,
+		Validity: validity,
+	})
+	return nil
+}
+
+// findDomainConstraint returns the ordinal of the CHECK constraint of the
+// domain with the given name, or -1 if there is none.
+func findDomainConstraint(domain *descpb.TypeDescriptor_Domain, name string) int {
+	for i := range domain.Constraints {
+		if domain.Constraints[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
 func (p *planner) createEnumWithID(
 	ctx context.Context,
 	evalCtx *eval.Context,
@@ -514,6 +659,27 @@ func (p *planner) createCompositeWithID(
 	return nil
 }
 
+func (p *planner) createDomainWithID(
+	params runParams,
+	id descpb.ID,
+	n *tree.CreateType,
+	dbDesc catalog.DatabaseDescriptor,
+	typeName *tree.TypeName,
+) error {
+	// Generate a key in the namespace table and a new id for this type.
+	schema, err := getCreateTypeParams(params.ctx, p, typeName, dbDesc)
+	if err != nil {
+		return err
+	}
+
+	typeDesc, err := createDomainTypeDesc(params, id, n, dbDesc, schema, typeName)
+	if err != nil {
+		return err
+	}
+
+	return p.finishCreateType(params.ctx, params.EvalContext(), typeName, typeDesc, dbDesc, schema)
+}
+
 func (p *planner) finishCreateType(
 	ctx context.Context,
 	evalCtx *eval.Context,
@@ -251,6 +251,11 @@ func (n *createViewNode) startExec(params runParams) error {
 					// should only be accessed after a REFRESH VIEW operation has been called
 					// on it.
 					desc.RefreshViewRequired = !createView.WithData
+					if createView.WithData {
+						// The view is populated with the results of its query as of the
+						// creation time.
+						desc.MaterializedViewRefreshTime = desc.CreateAsOfTime
+					}
 					desc.State = descpb.DescriptorState_ADD
 					version := params.ExecCfg().Settings.Version.ActiveVersion(params.ctx)
 					if err := desc.AllocateIDs(params.ctx, version); err != nil {
@@ -0,0 +1,390 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// constraintsMode is the mode established by SET CONSTRAINTS for a set of
+// constraints.
+type constraintsMode int8
+
+const (
+	// constraintsModeUnset means that the initial mode of each constraint, as
+	// recorded in its descriptor, is in effect.
+	constraintsModeUnset constraintsMode = iota
+	constraintsModeDeferred
+	constraintsModeImmediate
+)
+
+// maxDeferredConstraintKeys is the maximum number of violating keys that are
+// tracked for a deferred constraint. Once a constraint has more violating keys,
+// they are discarded and the whole constraint is revalidated instead.
+const maxDeferredConstraintKeys = 1000
+
+// deferredConstraintBatchSize is the number of violating keys that are
+// rechecked by a single validation query.
+const deferredConstraintBatchSize = 100
+
+// deferredConstraintKey identifies a constraint on a table.
+type deferredConstraintKey struct {
+	tableID descpb.ID
+	name    string
+}
+
+// deferredConstraintState is the per-transaction state backing DEFERRABLE
+// constraints. It tracks the modes set with SET CONSTRAINTS as well as the
+// violations of deferred constraints found by post-query checks.
+//
+// When a post-query check for a deferred constraint fails, the keys of the
+// violating rows are queued. When the transaction commits (or when the
+// constraint becomes IMMEDIATE), only the queued keys are rechecked: every
+// write that violates a constraint is caught by some statement-level check for
+// that constraint, so a key that was never reported as violating cannot be
+// violating at commit time.
+type deferredConstraintState struct {
+	// allMode is the mode set by SET CONSTRAINTS ALL.
+	allMode constraintsMode
+	// named maps constraints to the mode set by SET CONSTRAINTS <names>.
+	// Entries are cleared by SET CONSTRAINTS ALL.
+	named map[deferredConstraintKey]constraintsMode
+	// pending contains the constraints that must be validated before the
+	// transaction commits.
+	pending map[deferredConstraintKey]*pendingConstraint
+}
+
+// pendingConstraint describes the violations of a deferred constraint.
+type pendingConstraint struct {
+	deferrability tree.ConstraintDeferrability
+	// keys contains the values of the constraint columns of the violating rows.
+	// It is only used if allRows is false.
+	keys []tree.Datums
+	// allRows is set if the violating keys are unknown, or if there are too
+	// many of them, in which case the whole constraint is revalidated.
+	allRows bool
+}
+
+// isDeferred returns whether violations of the given constraint with the
+// given deferrability should currently be deferred.
+func (s *deferredConstraintState) isDeferred(
+	k deferredConstraintKey, deferrability tree.ConstraintDeferrability,
+) bool {
+	if !deferrability.IsDeferrable() {
+		return false
+	}
+	mode := s.named[k]
+	if mode == constraintsModeUnset {
+		mode = s.allMode
+	}
+	switch mode {
+	case constraintsModeDeferred:
+		return true
+	case constraintsModeImmediate:
+		return false
+	default:
+		return deferrability == tree.DeferrableInitiallyDeferred
+	}
+}
+
+// setMode implements SET CONSTRAINTS. An empty list of constraints corresponds
+// to SET CONSTRAINTS ALL.
+func (s *deferredConstraintState) setMode(constraints []deferredConstraintKey, deferred bool) {
+	mode := constraintsModeImmediate
+	if deferred {
+		mode = constraintsModeDeferred
+	}
+	if len(constraints) == 0 {
+		s.allMode = mode
+		s.named = nil
+		return
+	}
+	if s.named == nil {
+		s.named = make(map[deferredConstraintKey]constraintsMode, len(constraints))
+	}
+	for _, k := range constraints {
+		s.named[k] = mode
+	}
+}
+
+// enqueue records the violating keys described by v, which need to be
+// rechecked before the transaction commits.
+func (s *deferredConstraintState) enqueue(v *sqlerrors.DeferrableConstraintViolation) {
+	if s.pending == nil {
+		s.pending = make(map[deferredConstraintKey]*pendingConstraint)
+	}
+	k := deferredConstraintKey{tableID: v.TableID, name: v.ConstraintName}
+	pc := s.pending[k]
+	if pc == nil {
+		pc = &pendingConstraint{deferrability: v.Deferrability}
+		s.pending[k] = pc
+	}
+	if pc.allRows {
+		return
+	}
+	if v.Keys == nil || len(pc.keys)+len(v.Keys) > maxDeferredConstraintKeys {
+		pc.keys = nil
+		pc.allRows = true
+		return
+	}
+	pc.keys = append(pc.keys, v.Keys...)
+}
+
+// hasPending returns whether any constraint is pending validation.
+func (s *deferredConstraintState) hasPending() bool {
+	return len(s.pending) > 0
+}
+
+// reset clears the state at the end of a transaction.
+func (s *deferredConstraintState) reset() {
+	*s = deferredConstraintState{}
+}
+
+// maybeDeferConstraintViolation is called with the error returned by a
+// post-query check. If the error is a violation of a constraint that is
+// currently deferred, the violating keys are queued for validation at commit
+// time and nil is returned. Otherwise, the error is returned unchanged.
+func (p *planner) maybeDeferConstraintViolation(ctx context.Context, err error) error {
+	s := p.extendedEvalCtx.deferredConstraints
+	if err == nil || s == nil {
+		return err
+	}
+	v := sqlerrors.GetDeferrableConstraintViolation(err)
+	if v == nil {
+		return err
+	}
+	k := deferredConstraintKey{tableID: v.TableID, name: v.ConstraintName}
+	if !s.isDeferred(k, v.Deferrability) {
+		return err
+	}
+	log.VEventf(ctx, 2, "deferring validation of constraint %q until commit", v.ConstraintName)
+	s.enqueue(v)
+	return nil
+}
+
+// validateDeferredConstraints validates the constraints pending in s. If
+// onlyImmediate is set, constraints that are still deferred are skipped and
+// remain pending.
+func validateDeferredConstraints(
+	ctx context.Context,
+	txn descs.Txn,
+	user username.SQLUsername,
+	s *deferredConstraintState,
+	onlyImmediate bool,
+) error {
+	if s == nil || !s.hasPending() {
+		return nil
+	}
+	// Validate the constraints in a deterministic order so that the reported
+	// error does not depend on map iteration order.
+	keys := make([]deferredConstraintKey, 0, len(s.pending))
+	for k, pc := range s.pending {
+		if onlyImmediate && s.isDeferred(k, pc.deferrability) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].tableID != keys[j].tableID {
+			return keys[i].tableID < keys[j].tableID
+		}
+		return keys[i].name < keys[j].name
+	})
+	for _, k := range keys {
+		if err := validateDeferredConstraint(ctx, txn, user, k, s.pending[k]); err != nil {
+			return err
+		}
+		delete(s.pending, k)
+	}
+	return nil
+}
+
+// validateDeferredConstraint validates a single constraint. Constraints that
+// were dropped, along with their table, after being queued are skipped.
+func validateDeferredConstraint(
+	ctx context.Context,
+	txn descs.Txn,
+	user username.SQLUsername,
+	k deferredConstraintKey,
+	pc *pendingConstraint,
+) error {
+	desc, err := txn.Descriptors().ByIDWithLeased(txn.KV()).Get().Table(ctx, k.tableID)
+	if err != nil {
+		return err
+	}
+	if desc.Dropped() {
+		return nil
+	}
+	c := catalog.FindConstraintByName(desc, k.name)
+	if c == nil {
+		return nil
+	}
+	allRows := pc.allRows
+	for _, key := range pc.keys {
+		for _, d := range key {
+			if d == tree.DNull {
+				// NULLs in the key indicate a MATCH FULL violation, which is not
+				// detected by the keyed validation queries below.
+				allRows = true
+			}
+		}
+	}
+	if fk := c.AsForeignKey(); fk != nil {
+		if allRows {
+			log.VEventf(ctx, 2, "validating deferred constraint %q on table %q", k.name, desc.GetName())
+			srcTable := tabledesc.NewBuilder(desc.TableDesc()).BuildExistingMutableTable()
+			return validateFkInTxn(ctx, txn, srcTable, k.name)
+		}
+		return validateDeferredForeignKey(ctx, txn, desc, fk.ForeignKeyDesc(), pc.keys)
+	}
+	if uwi := c.AsUniqueWithoutIndex(); uwi != nil {
+		uc := uwi.UniqueWithoutIndexDesc()
+		if allRows {
+			log.VEventf(ctx, 2, "validating deferred constraint %q on table %q", k.name, desc.GetName())
+			return validateUniqueConstraint(
+				ctx,
+				desc,
+				uc.Name,
+				uc.ColumnIDs,
+				uc.Predicate,
+				0, /* indexIDForValidation */
+				txn,
+				user,
+				true, /* preExisting */
+			)
+		}
+		return validateDeferredUniqueConstraint(ctx, txn, user, desc, uc, pc.keys)
+	}
+	return nil
+}
+
+// validateDeferredForeignKey verifies that the rows of srcTable with the given
+// foreign key values have a matching row in the referenced table.
+func validateDeferredForeignKey(
+	ctx context.Context,
+	txn descs.Txn,
+	srcTable catalog.TableDescriptor,
+	fk *descpb.ForeignKeyConstraint,
+	keys []tree.Datums,
+) error {
+	targetTable, err := txn.Descriptors().ByIDWithLeased(txn.KV()).Get().Table(ctx, fk.ReferencedTableID)
+	if err != nil {
+		return err
+	}
+	query, colNames, err := nonMatchingRowQuery(
+		srcTable, fk, targetTable, 0 /* indexIDForValidation */, false, /* limitResults */
+	)
+	if err != nil {
+		return err
+	}
+	return validateDeferredKeys(
+		ctx, txn, sessiondata.NodeUserSessionDataOverride, srcTable, fk.Name,
+		query, colNames[:len(fk.OriginColumnIDs)], keys,
+		func(values tree.Datums) error {
+			return newFKValidationErr(srcTable, targetTable, fk.Name, colNames, values)
+		},
+	)
+}
+
+// validateDeferredUniqueConstraint verifies that the given values of the
+// unique constraint columns are not duplicated in srcTable.
+func validateDeferredUniqueConstraint(
+	ctx context.Context,
+	txn descs.Txn,
+	user username.SQLUsername,
+	srcTable catalog.TableDescriptor,
+	uc *descpb.UniqueWithoutIndexConstraint,
+	keys []tree.Datums,
+) error {
+	query, colNames, err := duplicateRowQuery(
+		srcTable, uc.ColumnIDs, uc.Predicate, 0 /* indexIDForValidation */, false, /* limitResults */
+	)
+	if err != nil {
+		return err
+	}
+	sessionDataOverride := sessiondata.NoSessionDataOverride
+	sessionDataOverride.User = user
+	return validateDeferredKeys(
+		ctx, txn, sessionDataOverride, srcTable, uc.Name, query, colNames, keys,
+		func(values tree.Datums) error {
+			return newUniqueValidationErr(uc.Name, colNames, values, true /* preExisting */)
+		},
+	)
+}
+
+// validateDeferredKeys runs the given validation query, restricted to the rows
+// whose keyCols match one of the given keys. The keys are checked in batches,
+// and mkErr is called with the first row returned by the query, if any.
+func validateDeferredKeys(
+	ctx context.Context,
+	txn descs.Txn,
+	sessionDataOverride sessiondata.InternalExecutorOverride,
+	srcTable catalog.TableDescriptor,
+	constraintName string,
+	query string,
+	keyCols []string,
+	keys []tree.Datums,
+	mkErr func(values tree.Datums) error,
+) error {
+	log.VEventf(ctx, 2, "validating %d keys of deferred constraint %q on table %q",
+		len(keys), constraintName, srcTable.GetName())
+	for len(keys) > 0 {
+		batch := keys
+		if len(batch) > deferredConstraintBatchSize {
+			batch = batch[:deferredConstraintBatchSize]
+		}
+		keys = keys[len(batch):]
+
+		// Build a filter of the form:
+		//   (a = $1 AND b = $2) OR (a = $3 AND b = $4) OR ...
+		var filter strings.Builder
+		args := make([]interface{}, 0, len(batch)*len(keyCols))
+		for i, key := range batch {
+			if i > 0 {
+				filter.WriteString(" OR ")
+			}
+			filter.WriteByte('(')
+			for j, col := range keyCols {
+				if j > 0 {
+					filter.WriteString(" AND ")
+				}
+				args = append(args, key[j])
+				fmt.Fprintf(&filter, "%s = $%d", tree.NameString(col), len(args))
+			}
+			filter.WriteByte(')')
+		}
+		values, err := txn.QueryRowEx(
+			ctx, "validate deferred constraint", txn.KV(), sessionDataOverride,
+			fmt.Sprintf(`SELECT * FROM (%s) AS v WHERE %s LIMIT 1`, query, filter.String()),
+			args...,
+		)
+		if err != nil {
+			return err
+		}
+		if values.Len() > 0 {
+			return mkErr(values)
+		}
+	}
+	return nil
+}
@@ -67,6 +67,13 @@ WHERE status='%s' AND created_by_type='%s' AND created_by_id=schedule_id
 			"executor_type = '%s'", tree.ScheduledChangefeedExecutor.InternalName()))
 		columnExprs = append(columnExprs, fmt.Sprintf(
 			"%s->>'changefeed_statement' AS command", commandColumn))
+	case tree.ScheduledSQLExecutor:
+		whereExprs = append(whereExprs, fmt.Sprintf(
+			"executor_type = '%s'", tree.ScheduledSQLExecutor.InternalName()))
+		columnExprs = append(columnExprs, fmt.Sprintf(
+			"%s->>'statement' AS command", commandColumn))
+		columnExprs = append(columnExprs, fmt.Sprintf(
+			"%s->'history' AS history", commandColumn))
 	default:
 		// Strip out '@type' tag from the ExecutionArgs.args, and display what's left.
 		columnExprs = append(columnExprs, fmt.Sprintf("%s #-'{@type}' AS command", commandColumn))
@@ -112,7 +112,9 @@ func (d *deleteRangeNode) startExec(params runParams) error {
 	log.VEvent(ctx, 2, "fast delete: skipping scan")
 	spans := make([]roachpb.Span, len(d.spans))
 	copy(spans, d.spans)
-	if !d.autoCommitEnabled {
+	// Notifications are written by the transaction when it commits, so the
+	// batch cannot commit it if there are any.
+	if !d.autoCommitEnabled || params.p.HasPendingNotifications() {
 		// Without autocommit, we're going to run each batch one by one, respecting
 		// a max span request keys size. We use spans as a queue of spans to delete.
 		// It'll be edited if there are any resume spans encountered (if any request
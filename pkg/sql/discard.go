@@ -61,6 +61,11 @@ func (n *discardNode) startExec(params runParams) error {
 			m.initSequenceCache()
 		})
 
+		// UNLISTEN *
+		if s := params.p.extendedEvalCtx.notifications; s != nil {
+			s.listenOps = append(s.listenOps, listenOp{})
+		}
+
 		// DISCARD TEMP
 		err := deleteTempTables(params.ctx, params.p)
 		if err != nil {
@@ -109,6 +109,8 @@ var aggregateFuncToNumArguments = map[execinfrapb.AggregatorSpec_Func]int{
 	execinfrapb.MergeStatementStats:         1,
 	execinfrapb.MergeTransactionStats:       1,
 	execinfrapb.MergeAggregatedStmtMetadata: 1,
+	execinfrapb.RangeAgg:                    1,
+	execinfrapb.RangeIntersectAgg:           1,
 }
 
 // TestAggregateFuncToNumArguments ensures that all aggregate functions are
@@ -134,6 +136,10 @@ func TestAggregateFuncToNumArguments(t *testing.T) {
 	fns := make([]execinfrapb.AggregatorSpec_Func, 0,
 		len(execinfrapb.AggregatorSpec_Func_name))
 	for fn := range execinfrapb.AggregatorSpec_Func_name {
+		if execinfrapb.AggregatorSpec_Func(fn) == execinfrapb.UserDefined {
+			// User-defined aggregates are not builtins.
+			continue
+		}
 		fns = append(fns, execinfrapb.AggregatorSpec_Func(fn))
 	}
 	sort.Slice(fns, func(i, j int) bool { return fns[i] < fns[j] })
@@ -393,6 +393,22 @@ func checkExprForDistSQL(expr tree.Expr, distSQLVisitor *distSQLExprCheckVisitor
 	return distSQLVisitor.err
 }
 
+// checkUserDefinedAggForDistSQL checks the support expressions of the given
+// user-defined aggregate, if any, for distSQL support.
+func checkUserDefinedAggForDistSQL(
+	agg *exec.UserDefinedAggInfo, distSQLVisitor *distSQLExprCheckVisitor,
+) error {
+	if agg == nil {
+		return nil
+	}
+	for _, expr := range []tree.TypedExpr{agg.InitialState, agg.Transition, agg.Final, agg.Combine} {
+		if err := checkExprForDistSQL(expr, distSQLVisitor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type distRecommendation int
 
 const (
@@ -547,6 +563,9 @@ func checkSupportForPlanNode(
 			if agg.distsqlBlocklist {
 				return cannotDistribute, newQueryNotSupportedErrorf("aggregate %q cannot be executed with distsql", agg.funcName)
 			}
+			if err := checkUserDefinedAggForDistSQL(agg.userDefined, distSQLVisitor); err != nil {
+				return cannotDistribute, err
+			}
 		}
 		// Distribute aggregations if possible.
 		return rec.compose(shouldDistribute), nil
@@ -739,6 +758,11 @@ func checkSupportForPlanNode(
 		if err != nil {
 			return cannotDistribute, err
 		}
+		for _, f := range n.funcs {
+			if err := checkUserDefinedAggForDistSQL(f.userDefined, distSQLVisitor); err != nil {
+				return cannotDistribute, err
+			}
+		}
 		for _, f := range n.funcs {
 			if len(f.partitionIdxs) > 0 {
 				// If at least one function has PARTITION BY clause, then we
@@ -2392,11 +2416,15 @@ func (dsp *DistSQLPlanner) addAggregators(
 	aggregations := make([]execinfrapb.AggregatorSpec_Aggregation, len(n.funcs))
 	argumentsColumnTypes := make([][]*types.T, len(n.funcs))
 	for i, fholder := range n.funcs {
-		funcIdx, err := execinfrapb.GetAggregateFuncIdx(fholder.funcName)
-		if err != nil {
-			return err
+		if fholder.userDefined != nil {
+			aggregations[i].Func = execinfrapb.UserDefined
+		} else {
+			funcIdx, err := execinfrapb.GetAggregateFuncIdx(fholder.funcName)
+			if err != nil {
+				return err
+			}
+			aggregations[i].Func = execinfrapb.AggregatorSpec_Func(funcIdx)
 		}
-		aggregations[i].Func = execinfrapb.AggregatorSpec_Func(funcIdx)
 		aggregations[i].Distinct = fholder.isDistinct
 		for _, renderIdx := range fholder.argRenderIdxs {
 			aggregations[i].ColIdx = append(aggregations[i].ColIdx, uint32(p.PlanToStreamColMap[renderIdx]))
@@ -2417,6 +2445,13 @@ func (dsp *DistSQLPlanner) addAggregators(
 			}
 			argumentsColumnTypes[i][j] = argument.ResolvedType()
 		}
+		if fholder.userDefined != nil {
+			var err error
+			aggregations[i].UserDefined, err = makeUserDefinedAggregation(&ef, fholder.userDefined)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	return dsp.planAggregators(ctx, planCtx, p, &aggregatorPlanningInfo{
@@ -2431,6 +2466,56 @@ func (dsp *DistSQLPlanner) addAggregators(
 	})
 }
 
+// makeUserDefinedAggregation creates the specification of the COMPLETE stage
+// of a user-defined aggregate. ef must be initialized without an indexVarMap,
+// since the support expressions reference the state and arguments of the
+// aggregate rather than the columns of the input.
+func makeUserDefinedAggregation(
+	ef *physicalplan.ExprFactory, agg *exec.UserDefinedAggInfo,
+) (_ *execinfrapb.UserDefinedAggregation, err error) {
+	u := &execinfrapb.UserDefinedAggregation{
+		ResultType:       agg.ResultType,
+		StateType:        agg.StateType,
+		ArgTypes:         agg.ArgTypes,
+		StrictTransition: agg.StrictTransition,
+		StrictCombine:    agg.StrictCombine,
+	}
+	if u.InitialState, err = ef.Make(agg.InitialState); err != nil {
+		return nil, err
+	}
+	if u.Transition, err = ef.Make(agg.Transition); err != nil {
+		return nil, err
+	}
+	if u.Final, err = ef.Make(agg.Final); err != nil {
+		return nil, err
+	}
+	if u.Combine, err = ef.Make(agg.Combine); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// userDefinedAggregationStage returns a copy of the given user-defined
+// aggregate for the given stage.
+func userDefinedAggregationStage(
+	u *execinfrapb.UserDefinedAggregation, stage execinfrapb.UserDefinedAggregation_Stage,
+) *execinfrapb.UserDefinedAggregation {
+	res := *u
+	res.Stage = stage
+	return &res
+}
+
+// getAggregateOutputType returns the output type of the given aggregation
+// when applied on the given types.
+func getAggregateOutputType(
+	agg *execinfrapb.AggregatorSpec_Aggregation, argTypes []*types.T,
+) (*types.T, error) {
+	if agg.UserDefined != nil {
+		return agg.UserDefined.OutputType(), nil
+	}
+	return execagg.GetAggregateOutputType(agg.Func, argTypes)
+}
+
 // planAggregators plans the aggregator processors. An evaluator stage is added
 // if necessary.
 // Invariants assumed:
@@ -2631,7 +2716,7 @@ func (dsp *DistSQLPlanner) planAggregators(
 				break
 			}
 			// Check that the function supports a local stage.
-			if _, ok := physicalplan.DistAggregationTable[e.Func]; !ok {
+			if _, ok := physicalplan.GetDistAggregationInfo(&e); !ok {
 				multiStage = false
 				break
 			}
@@ -2671,7 +2756,7 @@ func (dsp *DistSQLPlanner) planAggregators(
 		nFinalAgg := 0
 		needRender := false
 		for _, e := range info.aggregations {
-			info := physicalplan.DistAggregationTable[e.Func]
+			info, _ := physicalplan.GetDistAggregationInfo(&e)
 			nLocalAgg += len(info.LocalStage)
 			nFinalAgg += len(info.FinalStage)
 			if info.FinalRendering != nil {
@@ -2706,7 +2791,7 @@ func (dsp *DistSQLPlanner) planAggregators(
 		// to all final aggregations.
 		finalIdx := 0
 		for _, e := range info.aggregations {
-			info := physicalplan.DistAggregationTable[e.Func]
+			info, _ := physicalplan.GetDistAggregationInfo(&e)
 
 			// relToAbsLocalIdx maps each local stage for the given
 			// aggregation e to its final index in localAggs.  This
@@ -2728,6 +2813,11 @@ func (dsp *DistSQLPlanner) planAggregators(
 					ColIdx:       e.ColIdx,
 					FilterColIdx: e.FilterColIdx,
 				}
+				if e.UserDefined != nil {
+					localAgg.UserDefined = userDefinedAggregationStage(
+						e.UserDefined, execinfrapb.UserDefinedAggregation_LOCAL,
+					)
+				}
 
 				isNewAgg := true
 				for j, prevLocalAgg := range localAggs {
@@ -2754,7 +2844,7 @@ func (dsp *DistSQLPlanner) planAggregators(
 					for _, c := range e.ColIdx {
 						argTypes = append(argTypes, inputTypes[c])
 					}
-					outputType, err := execagg.GetAggregateOutputType(localFunc, argTypes)
+					outputType, err := getAggregateOutputType(&localAgg, argTypes)
 					if err != nil {
 						return err
 					}
@@ -2778,6 +2868,11 @@ func (dsp *DistSQLPlanner) planAggregators(
 					Func:   finalInfo.Fn,
 					ColIdx: argIdxs,
 				}
+				if e.UserDefined != nil {
+					finalAgg.UserDefined = userDefinedAggregationStage(
+						e.UserDefined, execinfrapb.UserDefinedAggregation_FINAL,
+					)
+				}
 
 				isNewAgg := true
 				for i, prevFinalAgg := range finalAggs {
@@ -2805,7 +2900,7 @@ func (dsp *DistSQLPlanner) planAggregators(
 							// types for the current aggregation e.
 							argTypes = append(argTypes, intermediateTypes[argIdxs[i]])
 						}
-						outputType, err := execagg.GetAggregateOutputType(finalInfo.Fn, argTypes)
+						outputType, err := getAggregateOutputType(&finalAgg, argTypes)
 						if err != nil {
 							return err
 						}
@@ -2924,7 +3019,7 @@ func (dsp *DistSQLPlanner) planAggregators(
 			var ef physicalplan.ExprFactory
 			ef.Init(ctx, planCtx, nil /* indexVarMap */)
 			for i, e := range info.aggregations {
-				info := physicalplan.DistAggregationTable[e.Func]
+				info, _ := physicalplan.GetDistAggregationInfo(&e)
 				if info.FinalRendering == nil {
 					// mappedIdx corresponds to the index
 					// location of the result for this
@@ -2971,13 +3066,14 @@ func (dsp *DistSQLPlanner) planAggregators(
 	// Set up the final stage.
 
 	finalOutTypes := make([]*types.T, len(info.aggregations))
-	for i, agg := range info.aggregations {
+	for i := range info.aggregations {
+		agg := &info.aggregations[i]
 		argTypes = argTypes[:0]
 		for _, c := range agg.ColIdx {
 			argTypes = append(argTypes, inputTypes[c])
 		}
 		argTypes = append(argTypes, info.argumentsColumnTypes[i]...)
-		returnTyp, err := execagg.GetAggregateOutputType(agg.Func, argTypes)
+		returnTyp, err := getAggregateOutputType(agg, argTypes)
 		if err != nil {
 			return err
 		}
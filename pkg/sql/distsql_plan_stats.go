@@ -47,6 +47,11 @@ type requestedStat struct {
 	histogramMaxBuckets uint32
 	name                string
 	inverted            bool
+	// dependencies and mostCommonValues are set if the functional dependencies
+	// between the columns and their most common values, respectively, are
+	// collected from the samples of a multi-column statistic.
+	dependencies     bool
+	mostCommonValues bool
 }
 
 // histogramSamples is the number of sample rows to be collected for histogram
@@ -196,7 +201,7 @@ func (dsp *DistSQLPlanner) createAndAttachSamplers(
 	// For partial statistics this loop should only iterate once
 	// since we only support one reqStat at a time.
 	for _, s := range reqStats {
-		if s.histogram {
+		if s.histogram || s.dependencies || s.mostCommonValues {
 			var histogramSamplesCount uint32
 			if tableSampleCount, ok := desc.HistogramSamplesCount(); ok {
 				histogramSamplesCount = tableSampleCount
@@ -430,7 +435,7 @@ func (dsp *DistSQLPlanner) createPartialStatsPlan(
 		// currently have a way of using more than one or deciding which one
 		// is better.
 		//
-		// We do not generate multi-column stats with histograms, so there
+		// We do not generate multi-column stats with inverted histograms, so there
 		// is no need to find an index for multi-column stats here.
 		//
 		// TODO(mjibson): allow multiple inverted indexes on the same column
@@ -640,11 +645,13 @@ func (dsp *DistSQLPlanner) createStatsPlan(
 	sampledColumnIDs := make([]descpb.ColumnID, len(requestedCols))
 	for _, s := range reqStats {
 		spec := execinfrapb.SketchSpec{
-			SketchType:          execinfrapb.SketchType_HLL_PLUS_PLUS_V1,
-			GenerateHistogram:   s.histogram,
-			HistogramMaxBuckets: s.histogramMaxBuckets,
-			Columns:             make([]uint32, len(s.columns)),
-			StatName:            s.name,
+			SketchType:               execinfrapb.SketchType_HLL_PLUS_PLUS_V1,
+			GenerateHistogram:        s.histogram,
+			HistogramMaxBuckets:      s.histogramMaxBuckets,
+			Columns:                  make([]uint32, len(s.columns)),
+			StatName:                 s.name,
+			GenerateDependencies:     s.dependencies,
+			GenerateMostCommonValues: s.mostCommonValues,
 		}
 		for i, colID := range s.columns {
 			colIdx, ok := colIdxMap.Get(colID)
@@ -661,7 +668,7 @@ func (dsp *DistSQLPlanner) createStatsPlan(
 			// currently have a way of using more than one or deciding which one
 			// is better.
 			//
-			// We do not generate multi-column stats with histograms, so there
+			// We do not generate multi-column stats with inverted histograms, so there
 			// is no need to find an index for multi-column stats here.
 			//
 			// TODO(mjibson): allow multiple inverted indexes on the same column
@@ -731,6 +738,8 @@ func (dsp *DistSQLPlanner) createPlanForCreateStats(
 			histogramMaxBuckets: histogramMaxBuckets,
 			name:                details.Name,
 			inverted:            details.ColumnStats[i].Inverted,
+			dependencies:        details.ColumnStats[i].HasDependencies,
+			mostCommonValues:    details.ColumnStats[i].HasMostCommonValues,
 		}
 	}
 
@@ -2110,7 +2110,11 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 			}
 		}
 
-		log.VEventf(ctx, 2, "executing cascade for constraint %s", plan.cascades[i].FKConstraint.Name())
+		if trigger := plan.cascades[i].Trigger; trigger != nil {
+			log.VEventf(ctx, 2, "executing cascade for trigger %s", trigger.Name())
+		} else {
+			log.VEventf(ctx, 2, "executing cascade for constraint %s", plan.cascades[i].FKConstraint.Name())
+		}
 
 		// We place a sequence point before every cascade, so that each subsequent
 		// cascade can observe the writes by the previous step. However, The
@@ -2244,8 +2248,10 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 				planner.instrumentation.getAssociateNodeWithComponentsFn(),
 				recv.stats.add,
 			); err != nil {
-				recv.SetError(err)
-				return false
+				if err = planner.maybeDeferConstraintViolation(ctx, err); err != nil {
+					recv.SetError(err)
+					return false
+				}
 			}
 		}
 	}
@@ -2484,10 +2490,11 @@ func (dsp *DistSQLPlanner) planAndRunChecksInParallel(
 		runCheck(ctx, checkPlanIdx)
 	}
 	// Wait for all concurrent checks to complete and return the error from the
-	// earliest check (if there were any errors).
+	// earliest check (if there were any errors). Violations of deferred
+	// constraints are queued for validation at commit time instead.
 	wg.Wait()
 	for _, err := range errs {
-		if err != nil {
+		if err = planner.maybeDeferConstraintViolation(ctx, err); err != nil {
 			return err
 		}
 	}
@@ -462,14 +462,24 @@ func populateAggFuncSpec(
 	argCols []exec.NodeColumnOrdinal,
 	constArgs []tree.Datum,
 	filter exec.NodeColumnOrdinal,
+	userDefined *exec.UserDefinedAggInfo,
 	planCtx *PlanningCtx,
 	physPlan *PhysicalPlan,
 ) (argumentsColumnTypes []*types.T, err error) {
-	funcIdx, err := execinfrapb.GetAggregateFuncIdx(funcName)
-	if err != nil {
-		return nil, err
+	if userDefined != nil {
+		var ef physicalplan.ExprFactory
+		ef.Init(ctx, planCtx, nil /* indexVarMap */)
+		spec.Func = execinfrapb.UserDefined
+		if spec.UserDefined, err = makeUserDefinedAggregation(&ef, userDefined); err != nil {
+			return nil, err
+		}
+	} else {
+		funcIdx, err := execinfrapb.GetAggregateFuncIdx(funcName)
+		if err != nil {
+			return nil, err
+		}
+		spec.Func = execinfrapb.AggregatorSpec_Func(funcIdx)
 	}
-	spec.Func = execinfrapb.AggregatorSpec_Func(funcIdx)
 	spec.Distinct = distinct
 	spec.ColIdx = make([]uint32, len(argCols))
 	for i, col := range argCols {
@@ -519,7 +529,7 @@ func (e *distSQLSpecExecFactory) constructAggregators(
 			argColsScratch[0] = col
 			_, err = populateAggFuncSpec(
 				e.ctx, spec, builtins.AnyNotNull, false /* distinct*/, argColsScratch,
-				nil /* constArgs */, noFilter, planCtx, physPlan,
+				nil /* constArgs */, noFilter, nil /* userDefined */, planCtx, physPlan,
 			)
 			if err != nil {
 				return nil, err
@@ -532,7 +542,7 @@ func (e *distSQLSpecExecFactory) constructAggregators(
 		agg := &aggregations[j]
 		argumentsColumnTypes[i], err = populateAggFuncSpec(
 			e.ctx, spec, agg.FuncName, agg.Distinct, agg.ArgCols,
-			agg.ConstArgs, agg.Filter, planCtx, physPlan,
+			agg.ConstArgs, agg.Filter, agg.UserDefined, planCtx, physPlan,
 		)
 		if err != nil {
 			return nil, err
@@ -1074,6 +1084,15 @@ func (e *distSQLSpecExecFactory) ConstructCreateFunction(
 	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: create function")
 }
 
+func (e *distSQLSpecExecFactory) ConstructCreateTrigger(
+	ct *tree.CreateTrigger,
+	deps opt.SchemaDeps,
+	typeDeps opt.SchemaTypeDeps,
+	functionDeps opt.SchemaFunctionDeps,
+) (exec.Node, error) {
+	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: create trigger")
+}
+
 func (e *distSQLSpecExecFactory) ConstructSequenceSelect(sequence cat.Sequence) (exec.Node, error) {
 	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: sequence select")
 }
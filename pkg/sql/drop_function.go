@@ -39,10 +39,14 @@ type dropFunctionNode struct {
 
 // DropFunction drops a function.
 func (p *planner) DropFunction(ctx context.Context, n *tree.DropRoutine) (ret planNode, err error) {
+	stmtName := "DROP FUNCTION"
+	if n.Aggregate {
+		stmtName = "DROP AGGREGATE"
+	}
 	if err := checkSchemaChangeEnabled(
 		ctx,
 		p.ExecCfg(),
-		"DROP FUNCTION",
+		stmtName,
 	); err != nil {
 		return nil, err
 	}
@@ -77,6 +81,9 @@ func (p *planner) DropFunction(ctx context.Context, n *tree.DropRoutine) (ret pl
 		if err != nil {
 			return nil, err
 		}
+		if err := checkRoutineIsAggregate(mut, n.Aggregate); err != nil {
+			return nil, err
+		}
 		if n.DropBehavior != tree.DropCascade && len(mut.DependedOnBy) > 0 {
 			dependedOnByIDs := make([]descpb.ID, 0, len(mut.DependedOnBy))
 			for _, ref := range mut.DependedOnBy {
@@ -117,6 +124,21 @@ func (n *dropFunctionNode) Next(params runParams) (bool, error) { return false,
 func (n *dropFunctionNode) Values() tree.Datums                 { return tree.Datums{} }
 func (n *dropFunctionNode) Close(ctx context.Context)           {}
 
+// checkRoutineIsAggregate returns an error if the function is an aggregate
+// but the statement does not refer to an aggregate, or vice versa.
+func checkRoutineIsAggregate(fnDesc catalog.FunctionDescriptor, aggregate bool) error {
+	if fnDesc.IsAggregate() && !aggregate {
+		return errors.WithHint(
+			pgerror.Newf(pgcode.WrongObjectType, "%q is an aggregate function", fnDesc.GetName()),
+			"Use DROP AGGREGATE or ALTER AGGREGATE to modify aggregate functions.",
+		)
+	}
+	if !fnDesc.IsAggregate() && aggregate {
+		return pgerror.Newf(pgcode.WrongObjectType, "function %s is not an aggregate", fnDesc.GetName())
+	}
+	return nil
+}
+
 // matchRoutine tries to resolve a user-defined function or procedure with the
 // given signature from the current search path, only overloads with exactly the
 // same argument types are considered a match. If required is true, an error is
@@ -350,6 +350,24 @@ func (p *planner) dropIndexByName(
 		)
 	}
 
+	// Drop the exclusion constraint that is backed by the index, if any.
+	for i := range tableDesc.ExclusionConstraints {
+		if tableDesc.ExclusionConstraints[i].IndexID != idx.GetID() {
+			continue
+		}
+		if behavior != tree.DropCascade && constraintBehavior != ignoreIdxConstraint {
+			return errors.WithHint(
+				pgerror.Newf(pgcode.DependentObjectsStillExist,
+					"index %q is in use as exclusion constraint", idx.GetName()),
+				"use CASCADE if you really want to drop it.",
+			)
+		}
+		tableDesc.ExclusionConstraints = append(
+			tableDesc.ExclusionConstraints[:i], tableDesc.ExclusionConstraints[i+1:]...,
+		)
+		break
+	}
+
 	// Check if requires CCL binary for eventual zone config removal.
 	_, zone, _, err := GetZoneConfigInTxn(
 		ctx, p.txn, p.Descriptors(), tableDesc.ID, nil /* index */, "", false,
@@ -13,11 +13,81 @@ package sql
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
-	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 )
 
+type dropTriggerNode struct {
+	n         *tree.DropTrigger
+	tableDesc *tabledesc.Mutable
+}
+
 // DropTrigger drops a trigger.
 func (p *planner) DropTrigger(ctx context.Context, n *tree.DropTrigger) (ret planNode, err error) {
-	return nil, unimplemented.NewWithIssue(126359, "DROP TRIGGER")
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP TRIGGER",
+	); err != nil {
+		return nil, err
+	}
+	// Nothing can depend on a trigger, so the drop behavior can be ignored.
+	_, tableDesc, err := p.ResolveMutableTableDescriptorEx(
+		ctx, n.Table, !n.IfExists, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc == nil {
+		return newZeroNode(nil /* columns */), nil
+	}
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	if findTrigger(tableDesc, string(n.Trigger)) == nil {
+		if n.IfExists {
+			return newZeroNode(nil /* columns */), nil
+		}
+		return nil, pgerror.Newf(pgcode.UndefinedObject,
+			"trigger %q for table %q does not exist", n.Trigger, tableDesc.GetName(),
+		)
+	}
+	return &dropTriggerNode{n: n, tableDesc: tableDesc}, nil
 }
+
+func (n *dropTriggerNode) ReadingOwnWrites() {}
+
+func (n *dropTriggerNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("trigger"))
+
+	tableDesc := n.tableDesc
+	for i := range tableDesc.Triggers {
+		trigger := &tableDesc.Triggers[i]
+		if trigger.Name != string(n.n.Trigger) {
+			continue
+		}
+		fnDesc, err := params.p.Descriptors().MutableByID(params.p.txn).Function(params.ctx, trigger.FuncID)
+		if err != nil {
+			return err
+		}
+		fnDesc.RemoveTriggerReference(tableDesc.GetID(), trigger.ID)
+		if err := params.p.writeFuncSchemaChange(params.ctx, fnDesc); err != nil {
+			return err
+		}
+		tableDesc.Triggers = append(tableDesc.Triggers[:i], tableDesc.Triggers[i+1:]...)
+		break
+	}
+	return params.p.writeSchemaChange(
+		params.ctx, tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (*dropTriggerNode) Next(params runParams) (bool, error) { return false, nil }
+func (*dropTriggerNode) Values() tree.Datums                 { return tree.Datums{} }
+func (*dropTriggerNode) Close(ctx context.Context)           {}
@@ -38,10 +38,14 @@ type dropTypeNode struct {
 var _ planNode = &dropTypeNode{n: nil}
 
 func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, error) {
+	stmtName := "DROP TYPE"
+	if n.IsDomain {
+		stmtName = "DROP DOMAIN"
+	}
 	if err := checkSchemaChangeEnabled(
 		ctx,
 		p.ExecCfg(),
-		"DROP TYPE",
+		stmtName,
 	); err != nil {
 		return nil, err
 	}
@@ -91,6 +95,16 @@ func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, err
 				"cannot drop type %q because table %q requires it",
 				name, name,
 			)
+		case descpb.TypeDescriptor_DOMAIN:
+			if !n.IsDomain {
+				return nil, errors.WithHint(
+					pgerror.Newf(pgcode.WrongObjectType, "%q is not a type", name),
+					"Use DROP DOMAIN to remove a domain.",
+				)
+			}
+		}
+		if n.IsDomain && typeDesc.Kind != descpb.TypeDescriptor_DOMAIN {
+			return nil, pgerror.Newf(pgcode.WrongObjectType, "%q is not a domain", name)
 		}
 
 		// Check if we can drop the type.
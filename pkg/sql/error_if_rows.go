@@ -15,6 +15,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 )
 
 // errorIfRowsNode wraps another planNode and returns an error if the wrapped
@@ -44,11 +45,42 @@ func (n *errorIfRowsNode) Next(params runParams) (bool, error) {
 		return false, err
 	}
 	if ok {
-		return false, n.mkErr(n.plan.Values())
+		err := n.mkErr(n.plan.Values())
+		if v := sqlerrors.GetDeferrableConstraintViolation(err); v != nil {
+			// The violation might be deferred until the end of the transaction,
+			// at which point only the violating keys are rechecked. Collect the
+			// keys of the remaining rows as well.
+			if err := n.collectDeferredKeys(params, v); err != nil {
+				return false, err
+			}
+		}
+		return false, err
 	}
 	return false, nil
 }
 
+// collectDeferredKeys adds the keys of the remaining rows of the wrapped node
+// to the given violation. If there are too many violating rows to keep track
+// of, the keys are dropped so that the whole constraint is revalidated.
+func (n *errorIfRowsNode) collectDeferredKeys(
+	params runParams, v *sqlerrors.DeferrableConstraintViolation,
+) error {
+	for {
+		ok, err := n.plan.Next(params)
+		if err != nil || !ok {
+			return err
+		}
+		if len(v.Keys) >= maxDeferredConstraintKeys {
+			v.Keys = nil
+			return nil
+		}
+		rowErr := n.mkErr(n.plan.Values())
+		if rowV := sqlerrors.GetDeferrableConstraintViolation(rowErr); rowV != nil {
+			v.Keys = append(v.Keys, rowV.Keys...)
+		}
+	}
+}
+
 func (n *errorIfRowsNode) Values() tree.Datums {
 	return nil
 }
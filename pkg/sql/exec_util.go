@@ -1467,6 +1467,10 @@ type ExecutorConfig struct {
 
 	// CidrLookup is used to look up the tag name for a given IP address.
 	CidrLookup *cidr.Lookup
+
+	// NotificationRegistry routes the notifications sent by NOTIFY to the
+	// sessions of this node that use LISTEN.
+	NotificationRegistry *NotificationRegistry
 }
 
 // UpdateVersionSystemSettingHook provides a callback that allows us
@@ -2064,6 +2068,8 @@ func checkResultType(typ *types.T, fmtCode pgwirebase.FormatCode) error {
 	case types.PGLSNFamily:
 	case types.PGVectorFamily:
 	case types.RefCursorFamily:
+	case types.RangeFamily:
+	case types.MultirangeFamily:
 	case types.TupleFamily:
 	case types.EnumFamily:
 	case types.VoidFamily:
@@ -3862,6 +3868,10 @@ func (m *sessionDataMutator) SetOptimizerUseConditionalHoistFix(val bool) {
 	m.data.OptimizerUseConditionalHoistFix = val
 }
 
+func (m *sessionDataMutator) SetVectorSearchProbes(val int64) {
+	m.data.VectorSearchProbes = val
+}
+
 // Utility functions related to scrubbing sensitive information on SQL Stats.
 
 // quantizeCounts ensures that the Count field in the
@@ -109,6 +109,14 @@ func GetAggregateConstructor(
 		argTypes[len(aggInfo.ColIdx)+j] = d.ResolvedType()
 		arguments[j] = d
 	}
+	if aggInfo.Func == execinfrapb.UserDefined {
+		constructor, err = newUserDefinedAggregateConstructor(ctx, evalCtx, semaCtx, aggInfo.UserDefined)
+		if err != nil {
+			return
+		}
+		outputType = aggInfo.UserDefined.OutputType()
+		return
+	}
 	constructor, outputType, err = getAggregateInfo(aggInfo.Func, argTypes)
 	return
 }
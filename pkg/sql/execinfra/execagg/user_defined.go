@@ -0,0 +1,225 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package execagg
+
+import (
+	"context"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// userDefinedAggregateExprs holds the support expressions of a user-defined
+// aggregate. It is shared by all of the aggregate functions created for the
+// same aggregation, which are never evaluated concurrently.
+type userDefinedAggregateExprs struct {
+	spec *execinfrapb.UserDefinedAggregation
+
+	initialState tree.Datum
+	transition   execinfrapb.ExprHelper
+	final        execinfrapb.ExprHelper
+	combine      execinfrapb.ExprHelper
+	hasFinal     bool
+
+	// ctx is the context used to evaluate the final expression, since
+	// eval.AggregateFunc.Result does not take one. It is updated on each call
+	// to Add and Reset.
+	ctx context.Context
+	// row is the scratch row in which the state and the arguments of the
+	// aggregate are passed to the support expressions.
+	row rowenc.EncDatumRow
+}
+
+// newUserDefinedAggregateConstructor prepares the support expressions of the
+// given user-defined aggregate and returns a constructor of aggregate
+// functions that evaluate them.
+func newUserDefinedAggregateConstructor(
+	ctx context.Context,
+	evalCtx *eval.Context,
+	semaCtx *tree.SemaContext,
+	spec *execinfrapb.UserDefinedAggregation,
+) (AggregateConstructor, error) {
+	if spec == nil {
+		return nil, errors.AssertionFailedf("missing user-defined aggregate")
+	}
+	e := &userDefinedAggregateExprs{spec: spec, ctx: ctx}
+	var initial execinfrapb.ExprHelper
+	// Pass nil types and row - there are no variables in the initial state.
+	if err := initial.Init(ctx, spec.InitialState, nil /* types */, semaCtx, evalCtx); err != nil {
+		return nil, err
+	}
+	var err error
+	if e.initialState, err = initial.Eval(ctx, nil /* row */); err != nil {
+		return nil, err
+	}
+	transitionTypes := make([]*types.T, 1+len(spec.ArgTypes))
+	transitionTypes[0] = spec.StateType
+	copy(transitionTypes[1:], spec.ArgTypes)
+	if err := e.transition.Init(ctx, spec.Transition, transitionTypes, semaCtx, evalCtx); err != nil {
+		return nil, err
+	}
+	stateTypes := []*types.T{spec.StateType, spec.StateType}
+	if spec.Stage != execinfrapb.UserDefinedAggregation_LOCAL && !spec.Final.Empty() {
+		if err := e.final.Init(ctx, spec.Final, stateTypes[:1], semaCtx, evalCtx); err != nil {
+			return nil, err
+		}
+		e.hasFinal = true
+	}
+	if spec.Stage == execinfrapb.UserDefinedAggregation_FINAL {
+		if spec.Combine.Empty() {
+			return nil, errors.AssertionFailedf("final stage of aggregate without combine function")
+		}
+		if err := e.combine.Init(ctx, spec.Combine, stateTypes, semaCtx, evalCtx); err != nil {
+			return nil, err
+		}
+	}
+	e.row = make(rowenc.EncDatumRow, len(transitionTypes))
+	return func(*eval.Context, tree.Datums) eval.AggregateFunc {
+		a := &userDefinedAggregate{exprs: e}
+		a.reset()
+		return a
+	}, nil
+}
+
+// GetUserDefinedWindowFunctionInfo returns the windowFunc constructor and the
+// return type of the given user-defined aggregate used as a window function.
+func GetUserDefinedWindowFunctionInfo(
+	ctx context.Context,
+	evalCtx *eval.Context,
+	semaCtx *tree.SemaContext,
+	spec *execinfrapb.UserDefinedAggregation,
+) (windowConstructor func(*eval.Context) eval.WindowFunc, returnType *types.T, err error) {
+	constructor, err := newUserDefinedAggregateConstructor(ctx, evalCtx, semaCtx, spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	return builtins.NewAggregateWindowFunc(constructor), spec.OutputType(), nil
+}
+
+// userDefinedAggregate computes an aggregate created with CREATE AGGREGATE by
+// evaluating its support functions, following the semantics of Postgres.
+type userDefinedAggregate struct {
+	exprs *userDefinedAggregateExprs
+	state tree.Datum
+	// noState is set if the state is still the NULL initial state. The first
+	// non-NULL input then becomes the state if the support function that would
+	// consume it is strict.
+	noState bool
+}
+
+var _ eval.AggregateFunc = &userDefinedAggregate{}
+
+const sizeOfUserDefinedAggregate = int64(unsafe.Sizeof(userDefinedAggregate{}))
+
+func (a *userDefinedAggregate) reset() {
+	a.state = a.exprs.initialState
+	a.noState = a.state == tree.DNull
+}
+
+// Add implements the eval.AggregateFunc interface.
+func (a *userDefinedAggregate) Add(
+	ctx context.Context, firstArg tree.Datum, otherArgs ...tree.Datum,
+) error {
+	e := a.exprs
+	e.ctx = ctx
+	if e.spec.Stage == execinfrapb.UserDefinedAggregation_FINAL {
+		return a.combine(ctx, firstArg)
+	}
+	args := tree.Datums{firstArg}
+	if len(e.spec.ArgTypes) > 1 {
+		t, ok := tree.AsDTuple(firstArg)
+		if !ok || len(t.D) != len(e.spec.ArgTypes) {
+			return errors.AssertionFailedf("expected a tuple of %d arguments, got %s",
+				len(e.spec.ArgTypes), firstArg)
+		}
+		args = t.D
+	}
+	if e.spec.StrictTransition {
+		for _, arg := range args {
+			if arg == tree.DNull {
+				return nil
+			}
+		}
+		if a.noState {
+			a.state, a.noState = args[0], false
+			return nil
+		}
+		if a.state == tree.DNull {
+			return nil
+		}
+	}
+	e.row[0] = rowenc.DatumToEncDatum(e.spec.StateType, a.state)
+	for i, arg := range args {
+		e.row[i+1] = rowenc.DatumToEncDatum(e.spec.ArgTypes[i], arg)
+	}
+	state, err := e.transition.Eval(ctx, e.row)
+	if err != nil {
+		return err
+	}
+	a.state, a.noState = state, false
+	return nil
+}
+
+// combine combines the given partial state, produced by the LOCAL stage of
+// the aggregate, into the state.
+func (a *userDefinedAggregate) combine(ctx context.Context, partial tree.Datum) error {
+	e := a.exprs
+	if e.spec.StrictCombine {
+		if partial == tree.DNull {
+			return nil
+		}
+		if a.noState {
+			a.state, a.noState = partial, false
+			return nil
+		}
+		if a.state == tree.DNull {
+			return nil
+		}
+	}
+	e.row[0] = rowenc.DatumToEncDatum(e.spec.StateType, a.state)
+	e.row[1] = rowenc.DatumToEncDatum(e.spec.StateType, partial)
+	state, err := e.combine.Eval(ctx, e.row[:2])
+	if err != nil {
+		return err
+	}
+	a.state, a.noState = state, false
+	return nil
+}
+
+// Result implements the eval.AggregateFunc interface.
+func (a *userDefinedAggregate) Result() (tree.Datum, error) {
+	e := a.exprs
+	if !e.hasFinal {
+		return a.state, nil
+	}
+	e.row[0] = rowenc.DatumToEncDatum(e.spec.StateType, a.state)
+	return e.final.Eval(e.ctx, e.row[:1])
+}
+
+// Reset implements the eval.AggregateFunc interface.
+func (a *userDefinedAggregate) Reset(ctx context.Context) {
+	a.exprs.ctx = ctx
+	a.reset()
+}
+
+// Close implements the eval.AggregateFunc interface.
+func (a *userDefinedAggregate) Close(context.Context) {}
+
+// Size implements the eval.AggregateFunc interface.
+func (a *userDefinedAggregate) Size() int64 {
+	return sizeOfUserDefinedAggregate
+}
@@ -78,4 +78,7 @@ const (
 	MergeStatementStats         = AggregatorSpec_MERGE_STATEMENT_STATS
 	MergeTransactionStats       = AggregatorSpec_MERGE_TRANSACTION_STATS
 	MergeAggregatedStmtMetadata = AggregatorSpec_MERGE_AGGREGATED_STMT_METADATA
+	RangeAgg                    = AggregatorSpec_RANGE_AGG
+	RangeIntersectAgg           = AggregatorSpec_RANGE_INTERSECT_AGG
+	UserDefined                 = AggregatorSpec_USER_DEFINED
 )
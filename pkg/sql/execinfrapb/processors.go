@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treewindow"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/errors"
 )
@@ -47,6 +48,10 @@ func (a AggregatorSpec_Aggregation) Equals(b AggregatorSpec_Aggregation) bool {
 	if a.Func != b.Func || a.Distinct != b.Distinct {
 		return false
 	}
+	if a.UserDefined != nil || b.UserDefined != nil {
+		// The support expressions of user-defined aggregates are not compared.
+		return false
+	}
 	if a.FilterColIdx == nil {
 		if b.FilterColIdx != nil {
 			return false
@@ -67,6 +72,15 @@ func (a AggregatorSpec_Aggregation) Equals(b AggregatorSpec_Aggregation) bool {
 	return true
 }
 
+// OutputType returns the type of the values produced by the user-defined
+// aggregate, which is the type of its state in the LOCAL stage.
+func (u *UserDefinedAggregation) OutputType() *types.T {
+	if u.Stage == UserDefinedAggregation_LOCAL {
+		return u.StateType
+	}
+	return u.ResultType
+}
+
 // IsScalar returns whether the aggregate function is in scalar context.
 func (spec *AggregatorSpec) IsScalar() bool {
 	switch spec.Type {
@@ -380,6 +380,13 @@ func (ep *DummyEvalPlanner) FingerprintSpan(
 	return 0, errors.AssertionFailedf("FingerprintSpan unimplemented")
 }
 
+// ScanTableRevisions is part of the eval.Planner interface.
+func (ep *DummyEvalPlanner) ScanTableRevisions(
+	_ context.Context, _ descpb.ID, _, _ hlc.Timestamp,
+) (eval.InternalRows, error) {
+	return nil, errors.WithStack(errEvalPlanner)
+}
+
 // ResetMultiRegionZoneConfigsForTable is part of the eval.RegionOperator
 // interface.
 func (ep *DummyEvalPlanner) ResetMultiRegionZoneConfigsForTable(_ context.Context, _ int64) error {
@@ -576,6 +583,16 @@ func (ep *DummyEvalPlanner) ClearQueryPlanCache() {}
 // ClearTableStatsCache is part of the eval.Planner interface.
 func (ep *DummyEvalPlanner) ClearTableStatsCache() {}
 
+// Notify is part of the eval.Planner interface.
+func (ep *DummyEvalPlanner) Notify(context.Context, string, string) error {
+	return errors.WithStack(errEvalPlanner)
+}
+
+// HasPendingNotifications is part of the eval.Planner interface.
+func (ep *DummyEvalPlanner) HasPendingNotifications() bool {
+	return false
+}
+
 // DummyPrivilegedAccessor implements the tree.PrivilegedAccessor interface by returning errors.
 type DummyPrivilegedAccessor struct{}
 
@@ -38,6 +38,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -150,7 +151,8 @@ func newReadImportDataProcessor(
 		spec:   spec,
 		progCh: make(chan execinfrapb.RemoteProducerMetadata_BulkProcessorProgress),
 	}
-	if err := idp.Init(ctx, idp, post, csvOutputTypes, flowCtx, processorID, nil, /* memMonitor */
+	memMonitor := execinfra.NewMonitor(ctx, flowCtx.Mon, "read-import-data-mem")
+	if err := idp.Init(ctx, idp, post, csvOutputTypes, flowCtx, processorID, memMonitor,
 		execinfra.ProcStateOpts{
 			// This processor doesn't have any inputs to drain.
 			InputsToDrain: nil,
@@ -187,7 +189,7 @@ func (idp *readImportDataProcessor) Start(ctx context.Context) {
 	idp.wg.GoCtx(func(ctx context.Context) error {
 		defer close(idp.progCh)
 		idp.summary, idp.importErr = runImport(ctx, idp.FlowCtx, &idp.spec, idp.progCh,
-			idp.seqChunkProvider)
+			idp.seqChunkProvider, idp.MemMonitor)
 		return nil
 	})
 }
@@ -243,7 +245,9 @@ func (idp *readImportDataProcessor) close() {
 	}
 	_ = idp.wg.Wait()
 
-	idp.InternalClose()
+	if idp.InternalClose() {
+		idp.MemMonitor.Stop(idp.Ctx())
+	}
 }
 
 func injectTimeIntoEvalCtx(evalCtx *eval.Context, walltime int64) {
@@ -262,6 +266,7 @@ func makeInputConverter(
 	kvCh chan row.KVBatch,
 	seqChunkProvider *row.SeqChunkProvider,
 	db *kv.DB,
+	memMonitor *mon.BytesMonitor,
 ) (inputConverter, error) {
 	injectTimeIntoEvalCtx(evalCtx, spec.WalltimeNanos)
 	var singleTable catalog.TableDescriptor
@@ -338,6 +343,10 @@ func makeInputConverter(
 		return newAvroInputReader(
 			semaCtx, kvCh, singleTable, spec.Format.Avro, spec.WalltimeNanos,
 			readerParallelism, evalCtx, db)
+	case roachpb.IOFileFormat_Parquet:
+		return newParquetInputReader(
+			semaCtx, kvCh, singleTable, spec.Format.Parquet, spec.WalltimeNanos,
+			readerParallelism, evalCtx, db, memMonitor), nil
 	default:
 		return nil, errors.Errorf(
 			"Requested IMPORT format (%d) not supported by this node", spec.Format.Format)
@@ -127,7 +127,7 @@ func TestConverterFlushesBatches(t *testing.T) {
 				kvCh := make(chan row.KVBatch, batchSize)
 				semaCtx := tree.MakeSemaContext(nil /* resolver */)
 				conv, err := makeInputConverter(ctx, &semaCtx, converterSpec, &evalCtx, kvCh,
-					nil /* seqChunkProvider */, db)
+					nil /* seqChunkProvider */, db, evalCtx.TestingMon)
 				if err != nil {
 					t.Fatalf("makeInputConverter() error = %v", err)
 				}
@@ -459,7 +459,7 @@ func TestImportHonorsResumePosition(t *testing.T) {
 					}
 				}()
 
-				_, err := runImport(ctx, flowCtx, spec, progCh, nil /* seqChunkProvider */)
+				_, err := runImport(ctx, flowCtx, spec, progCh, nil /* seqChunkProvider */, flowCtx.Mon)
 				if err != nil {
 					t.Fatal(err)
 				}
@@ -538,7 +538,7 @@ func TestImportHandlesDuplicateKVs(t *testing.T) {
 				}
 			}()
 
-			_, err := runImport(ctx, flowCtx, spec, progCh, nil /* seqChunkProvider */)
+			_, err := runImport(ctx, flowCtx, spec, progCh, nil /* seqChunkProvider */, flowCtx.Mon)
 			require.True(t, errors.HasType(err, &kvserverbase.DuplicateKeyError{}))
 		})
 	}
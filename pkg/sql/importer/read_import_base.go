@@ -43,6 +43,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/encoding/csv"
 	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/errors"
@@ -54,6 +55,7 @@ func runImport(
 	spec *execinfrapb.ReadImportDataSpec,
 	progCh chan execinfrapb.RemoteProducerMetadata_BulkProcessorProgress,
 	seqChunkProvider *row.SeqChunkProvider,
+	memMonitor *mon.BytesMonitor,
 ) (*kvpb.BulkOpSummary, error) {
 	// Used to send ingested import rows to the KV layer.
 	kvCh := make(chan row.KVBatch, 10)
@@ -72,7 +74,9 @@ func runImport(
 	evalCtx := flowCtx.NewEvalCtx()
 	evalCtx.Regions = makeImportRegionOperator(spec.DatabasePrimaryRegion)
 	semaCtx := tree.MakeSemaContext(importResolver)
-	conv, err := makeInputConverter(ctx, &semaCtx, spec, evalCtx, kvCh, seqChunkProvider, flowCtx.Cfg.DB.KV())
+	conv, err := makeInputConverter(
+		ctx, &semaCtx, spec, evalCtx, kvCh, seqChunkProvider, flowCtx.Cfg.DB.KV(), memMonitor,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -239,6 +243,9 @@ func readInputFiles(
 			}
 			defer decompressed.Close()
 			src.Reader = decompressed
+			if src.total > 0 && guessCompressionFromName(dataFile, format.Compression) == roachpb.IOFileFormat_None {
+				src.ranged = &externalStorageReaderAt{ctx: ctx, es: es}
+			}
 
 			var rejected chan string
 			if (format.Format == roachpb.IOFileFormat_CSV && format.SaveRejected) ||
@@ -367,6 +374,38 @@ type fileReader struct {
 	io.Reader
 	total   int64
 	counter byteCounter
+	// ranged, if set, reads the file at arbitrary offsets. It is only set for
+	// uncompressed files of known size (total).
+	ranged io.ReaderAt
+}
+
+// externalStorageReaderAt implements io.ReaderAt on top of a file in
+// cloud.ExternalStorage. Each call issues a ranged read.
+type externalStorageReaderAt struct {
+	ctx context.Context
+	es  cloud.ExternalStorage
+}
+
+var _ io.ReaderAt = (*externalStorageReaderAt)(nil)
+
+// ReadAt implements the io.ReaderAt interface.
+func (r *externalStorageReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	reader, _, err := r.es.ReadFile(r.ctx, "", cloud.ReadOptions{
+		Offset:     off,
+		LengthHint: int64(len(p)),
+		NoFileSize: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close(r.ctx)
+	n, err := io.ReadFull(ioctx.ReaderCtxAdapter(r.ctx, reader), p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		// The io.ReaderAt interface requires io.EOF when fewer than len(p)
+		// bytes are read because the end of the file was reached.
+		err = io.EOF
+	}
+	return n, err
 }
 
 func (f fileReader) ReadFraction() float32 {
@@ -387,6 +426,7 @@ type inputConverter interface {
 func formatHasNamedColumns(format roachpb.IOFileFormat_FileFormat) bool {
 	switch format {
 	case roachpb.IOFileFormat_Avro,
+		roachpb.IOFileFormat_Parquet,
 		roachpb.IOFileFormat_Mysqldump,
 		roachpb.IOFileFormat_PgDump:
 		return true
@@ -0,0 +1,836 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package importer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow/go/v11/parquet"
+	"github.com/apache/arrow/go/v11/parquet/file"
+	"github.com/apache/arrow/go/v11/parquet/metadata"
+	"github.com/apache/arrow/go/v11/parquet/schema"
+	"github.com/cockroachdb/apd/v3"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/cast"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+// parquetMaxFileSize limits the size of a single compressed parquet file that
+// can be imported. Parquet files are read from their footer and row groups are
+// read at arbitrary offsets. Uncompressed files are read with ranged reads, but
+// compressed files can only be read sequentially, so they are buffered in
+// memory while they are being imported.
+var parquetMaxFileSize = settings.RegisterByteSizeSetting(
+	settings.ApplicationLevel,
+	"bulkio.import.parquet.max_file_size",
+	"maximum size of a compressed parquet file that can be imported; such files are buffered in memory",
+	1<<30, // 1 GiB
+)
+
+// parquetReadBatchSize is the number of levels read from a column chunk at a
+// time.
+const parquetReadBatchSize = 1024
+
+// crdbParquetWriter is the created_by value written by pkg/util/parquet. That
+// writer encodes DECIMAL columns as text rather than as an unscaled integer,
+// so the reader must know which convention a file follows.
+const crdbParquetWriter = "cockroachdb"
+
+// parquetColumn describes a leaf column of a parquet file which is imported
+// into a column of the target table.
+type parquetColumn struct {
+	// name is the name of the top-level field of the column.
+	name string
+	// leafIdx is the index of the leaf column in the file schema.
+	leafIdx int
+	// targetIdx is the index of the target column in the visible columns of
+	// the table.
+	targetIdx int
+	physical  parquet.Type
+	logical   schema.LogicalType
+	// isList is set if the column is a LIST of primitive values.
+	isList bool
+	// listDef and elemDef are the definition levels at which a list is
+	// non-null and at which a list has an element, respectively. A level of
+	// maxDef indicates a non-null value. They are only set for list columns.
+	listDef, elemDef, maxDef int16
+	// textDecimals is set if DECIMAL values are stored as text.
+	textDecimals bool
+}
+
+// makeParquetColumns maps the top-level fields of the parquet schema to the
+// visible columns of the table. Only the leaf columns which are imported are
+// returned. Flat primitive columns and lists of primitive values are
+// supported; other nested fields result in an error if they are imported.
+func makeParquetColumns(
+	reader *file.Reader, fieldNameToIdx map[string]int, strict bool,
+) ([]parquetColumn, error) {
+	sch := reader.MetaData().Schema
+	textDecimals := strings.HasPrefix(reader.MetaData().GetCreatedBy(), crdbParquetWriter)
+	var cols []parquetColumn
+	seen := make(map[int]string)
+	for i := 0; i < sch.NumColumns(); i++ {
+		leaf := sch.Column(i)
+		root := sch.ColumnRoot(i)
+		name := lexbase.NormalizeName(root.Name())
+		idx, ok := fieldNameToIdx[name]
+		if !ok {
+			if strict {
+				return nil, errors.Newf("could not find column for parquet field %s", name)
+			}
+			continue
+		}
+		col := parquetColumn{
+			name:         name,
+			leafIdx:      i,
+			targetIdx:    idx,
+			physical:     leaf.PhysicalType(),
+			logical:      leaf.LogicalType(),
+			maxDef:       leaf.MaxDefinitionLevel(),
+			textDecimals: textDecimals,
+		}
+		switch {
+		case root.Type() == schema.Primitive && leaf.MaxRepetitionLevel() == 0:
+		case isParquetList(root) && leaf.MaxRepetitionLevel() == 1:
+			col.isList = true
+			if root.RepetitionType() != parquet.Repetitions.Required {
+				col.listDef = 1
+			}
+			col.elemDef = col.listDef + 1
+		default:
+			return nil, errors.Newf("parquet field %s has an unsupported nested type", name)
+		}
+		if other, ok := seen[idx]; ok {
+			return nil, errors.Newf("parquet fields %s and %s map to the same column", other, root.Name())
+		}
+		seen[idx] = root.Name()
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+// isParquetList returns whether n is a LIST annotated group.
+func isParquetList(n schema.Node) bool {
+	_, ok := n.LogicalType().(schema.ListLogicalType)
+	return ok && n.Type() == schema.Group
+}
+
+type parquetBatchReader[T any] interface {
+	ReadBatch(
+		batchSize int64, values []T, defLvls []int16, repLvls []int16,
+	) (total int64, valuesRead int, err error)
+}
+
+// readParquetColumnChunk reads all values of the column chunk into the colIdx
+// entry of rows. The values are stored as go native types; see
+// parquetValueToDatum.
+func readParquetColumnChunk(
+	cr file.ColumnChunkReader, col *parquetColumn, rows [][]interface{}, colIdx int,
+) error {
+	switch col.physical {
+	case parquet.Types.Boolean:
+		return readParquetValues(cr, make([]bool, parquetReadBatchSize), col, rows, colIdx)
+	case parquet.Types.Int32:
+		return readParquetValues(cr, make([]int32, parquetReadBatchSize), col, rows, colIdx)
+	case parquet.Types.Int64:
+		return readParquetValues(cr, make([]int64, parquetReadBatchSize), col, rows, colIdx)
+	case parquet.Types.Int96:
+		return readParquetValues(cr, make([]parquet.Int96, parquetReadBatchSize), col, rows, colIdx)
+	case parquet.Types.Float:
+		return readParquetValues(cr, make([]float32, parquetReadBatchSize), col, rows, colIdx)
+	case parquet.Types.Double:
+		return readParquetValues(cr, make([]float64, parquetReadBatchSize), col, rows, colIdx)
+	case parquet.Types.ByteArray:
+		return readParquetValues(cr, make([]parquet.ByteArray, parquetReadBatchSize), col, rows, colIdx)
+	case parquet.Types.FixedLenByteArray:
+		return readParquetValues(
+			cr, make([]parquet.FixedLenByteArray, parquetReadBatchSize), col, rows, colIdx)
+	default:
+		return errors.Newf("parquet field %s has unsupported physical type %s", col.name, col.physical)
+	}
+}
+
+func readParquetValues[T any](
+	cr file.ColumnChunkReader, values []T, col *parquetColumn, rows [][]interface{}, colIdx int,
+) error {
+	br, ok := cr.(parquetBatchReader[T])
+	if !ok {
+		return errors.AssertionFailedf("expected batch reader for %T, found %T", values, cr)
+	}
+	defLvls := make([]int16, len(values))
+	repLvls := make([]int16, len(values))
+	rowIdx := -1
+	for {
+		total, _, err := br.ReadBatch(int64(len(values)), values, defLvls, repLvls)
+		if err != nil {
+			return err
+		}
+		if total == 0 {
+			break
+		}
+		// Values are packed densely: only levels for non-null values have an
+		// entry in values.
+		valIdx := 0
+		for i := 0; i < int(total); i++ {
+			// Required columns do not have definition levels.
+			def := col.maxDef
+			if col.maxDef > 0 {
+				def = defLvls[i]
+			}
+			var v interface{}
+			if def == col.maxDef {
+				v = copyParquetValue(values[valIdx])
+				valIdx++
+			}
+			if !col.isList {
+				rowIdx++
+				if rowIdx >= len(rows) {
+					return errors.Newf("parquet field %s has more values than rows", col.name)
+				}
+				rows[rowIdx][colIdx] = v
+				continue
+			}
+			// Repetition level 0 starts a new list.
+			if repLvls[i] == 0 {
+				rowIdx++
+				if rowIdx >= len(rows) {
+					return errors.Newf("parquet field %s has more values than rows", col.name)
+				}
+				if def < col.listDef {
+					// NULL list.
+					continue
+				}
+				rows[rowIdx][colIdx] = []interface{}{}
+				if def < col.elemDef {
+					// Empty list.
+					continue
+				}
+			}
+			rows[rowIdx][colIdx] = append(rows[rowIdx][colIdx].([]interface{}), v)
+		}
+	}
+	if rowIdx != len(rows)-1 {
+		return errors.Newf("expected %d rows for parquet field %s, found %d", len(rows), col.name, rowIdx+1)
+	}
+	return nil
+}
+
+// copyParquetValue converts the values returned by the column readers into
+// values that can outlive the next read; byte arrays reference the reader's
+// buffers.
+func copyParquetValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case parquet.ByteArray:
+		return append([]byte(nil), t...)
+	case parquet.FixedLenByteArray:
+		return append([]byte(nil), t...)
+	default:
+		return v
+	}
+}
+
+// parquetValueToDatum converts a value read from a parquet column into a
+// datum of the target type. The parquet logical type of the column
+// determines how the physical value is interpreted (e.g. an INT32 may be a
+// DATE); the resulting datum is then converted to the target type using an
+// assignment cast.
+func parquetValueToDatum(
+	ctx context.Context,
+	v interface{},
+	col *parquetColumn,
+	targetT *types.T,
+	evalCtx *eval.Context,
+	semaCtx *tree.SemaContext,
+) (tree.Datum, error) {
+	if v == nil {
+		return tree.DNull, nil
+	}
+	if list, ok := v.([]interface{}); ok {
+		if targetT.Family() != types.ArrayFamily {
+			return nil, errors.Newf("cannot convert parquet list %s to non-array type %s", col.name, targetT)
+		}
+		arr := tree.NewDArray(targetT.ArrayContents())
+		for _, elt := range list {
+			d, err := parquetValueToDatum(ctx, elt, col, targetT.ArrayContents(), evalCtx, semaCtx)
+			if err == nil {
+				err = arr.Append(d)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	}
+
+	d, err := parquetNativeToDatum(v, col, targetT)
+	if err != nil {
+		return nil, err
+	}
+	if d.ResolvedType().Identical(targetT) {
+		return d, nil
+	}
+	// Strings and byte arrays without a logical type may hold the text
+	// representation of any type, just like a CSV field.
+	if s, ok := d.(*tree.DString); ok && targetT.Family() != types.StringFamily {
+		return rowenc.ParseDatumStringAs(ctx, targetT, string(*s), evalCtx, semaCtx)
+	}
+	if !cast.ValidCast(d.ResolvedType(), targetT, cast.ContextAssignment) {
+		return nil, errors.Newf(
+			"cannot convert parquet field %s of type %s to %s", col.name, d.ResolvedType(), targetT)
+	}
+	return eval.PerformAssignmentCast(ctx, evalCtx, d, targetT)
+}
+
+// parquetNativeToDatum interprets a primitive parquet value according to the
+// logical type of its column.
+func parquetNativeToDatum(v interface{}, col *parquetColumn, targetT *types.T) (tree.Datum, error) {
+	switch x := v.(type) {
+	case bool:
+		return tree.MakeDBool(tree.DBool(x)), nil
+	case int32:
+		switch lt := col.logical.(type) {
+		case schema.DateLogicalType:
+			date, err := pgdate.MakeDateFromUnixEpoch(int64(x))
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDDate(date), nil
+		case *schema.TimeLogicalType:
+			return parquetTimeToDatum(int64(x), lt.TimeUnit()), nil
+		case *schema.DecimalLogicalType:
+			return &tree.DDecimal{Decimal: *apd.New(int64(x), -lt.Scale())}, nil
+		case *schema.IntLogicalType:
+			if !lt.IsSigned() {
+				return tree.NewDInt(tree.DInt(uint32(x))), nil
+			}
+		}
+		return tree.NewDInt(tree.DInt(x)), nil
+	case int64:
+		switch lt := col.logical.(type) {
+		case *schema.TimestampLogicalType:
+			return parquetTimestampToDatum(x, lt)
+		case *schema.TimeLogicalType:
+			return parquetTimeToDatum(x, lt.TimeUnit()), nil
+		case *schema.DecimalLogicalType:
+			return &tree.DDecimal{Decimal: *apd.New(x, -lt.Scale())}, nil
+		case *schema.IntLogicalType:
+			if !lt.IsSigned() && x < 0 {
+				return nil, pgerror.Newf(pgcode.NumericValueOutOfRange,
+					"value %d of parquet field %s is out of range", uint64(x), col.name)
+			}
+		}
+		return tree.NewDInt(tree.DInt(x)), nil
+	case parquet.Int96:
+		// INT96 is a deprecated encoding of nanosecond timestamps that is still
+		// produced by some writers.
+		return tree.MakeDTimestampTZ(x.ToTime(), time.Microsecond)
+	case float32:
+		return tree.NewDFloat(tree.DFloat(x)), nil
+	case float64:
+		return tree.NewDFloat(tree.DFloat(x)), nil
+	case []byte:
+		switch lt := col.logical.(type) {
+		case schema.StringLogicalType, schema.EnumLogicalType:
+			return tree.NewDString(string(x)), nil
+		case schema.JSONLogicalType:
+			return tree.ParseDJSON(string(x))
+		case schema.UUIDLogicalType:
+			u, err := uuid.FromBytes(x)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDUuid(tree.DUuid{UUID: u}), nil
+		case *schema.DecimalLogicalType:
+			if col.textDecimals {
+				return tree.ParseDDecimal(string(x))
+			}
+			return parquetDecimalToDatum(x, lt.Scale()), nil
+		}
+		if targetT.Family() == types.BytesFamily {
+			return tree.NewDBytes(tree.DBytes(x)), nil
+		}
+		return tree.NewDString(string(x)), nil
+	default:
+		return nil, errors.AssertionFailedf("unexpected parquet value %T", v)
+	}
+}
+
+// parquetTimeUnitDuration returns the duration of a unit of a parquet TIME or
+// TIMESTAMP value.
+func parquetTimeUnitDuration(unit schema.TimeUnitType) time.Duration {
+	switch unit {
+	case schema.TimeUnitMillis:
+		return time.Millisecond
+	case schema.TimeUnitNanos:
+		return time.Nanosecond
+	default:
+		return time.Microsecond
+	}
+}
+
+func parquetTimestampToDatum(v int64, lt *schema.TimestampLogicalType) (tree.Datum, error) {
+	var t time.Time
+	switch lt.TimeUnit() {
+	case schema.TimeUnitMillis:
+		t = time.UnixMilli(v)
+	case schema.TimeUnitNanos:
+		t = time.Unix(0, v)
+	default:
+		t = time.UnixMicro(v)
+	}
+	if lt.IsAdjustedToUTC() {
+		return tree.MakeDTimestampTZ(t.UTC(), time.Microsecond)
+	}
+	return tree.MakeDTimestamp(t.UTC(), time.Microsecond)
+}
+
+func parquetTimeToDatum(v int64, unit schema.TimeUnitType) tree.Datum {
+	d := time.Duration(v) * parquetTimeUnitDuration(unit)
+	return tree.MakeDTime(timeofday.TimeOfDay(d / time.Microsecond))
+}
+
+// parquetDecimalToDatum decodes a DECIMAL stored as a big-endian two's
+// complement unscaled integer.
+func parquetDecimalToDatum(b []byte, scale int32) tree.Datum {
+	var coeff big.Int
+	coeff.SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		var bound big.Int
+		bound.Lsh(big.NewInt(1), uint(len(b)*8))
+		coeff.Sub(&coeff, &bound)
+	}
+	d := &tree.DDecimal{}
+	d.Negative = coeff.Sign() < 0
+	d.Coeff.SetMathBigInt(coeff.Abs(&coeff))
+	d.Exponent = -scale
+	return d
+}
+
+// parquetConsumer implements importRowConsumer interface.
+type parquetConsumer struct {
+	importCtx *parallelImportContext
+	columns   []parquetColumn
+	strict    bool
+}
+
+var _ importRowConsumer = &parquetConsumer{}
+
+// FillDatums implements importRowConsumer interface.
+func (p *parquetConsumer) FillDatums(
+	ctx context.Context, native interface{}, rowIndex int64, conv *row.DatumRowConverter,
+) error {
+	values, ok := native.([]interface{})
+	if !ok {
+		return errors.AssertionFailedf("unexpected native type; expected []interface{} found %T", native)
+	}
+	for i := range p.columns {
+		col := &p.columns[i]
+		d, err := parquetValueToDatum(
+			ctx, values[i], col, conv.VisibleColTypes[col.targetIdx], conv.EvalCtx, conv.SemaCtx,
+		)
+		if err != nil {
+			return err
+		}
+		conv.Datums[col.targetIdx] = d
+	}
+
+	// Set any nil datums to DNull (in case the file did not have a field for
+	// the column).
+	for i := range conv.Datums {
+		if conv.TargetColOrds.Contains(i) && conv.Datums[i] == nil {
+			if p.strict {
+				return errors.Newf("field %s was not set in the parquet import", conv.VisibleCols[i].GetName())
+			}
+			conv.Datums[i] = tree.DNull
+		}
+	}
+	return nil
+}
+
+// parquetRowGroup is a decoded parquet row group.
+type parquetRowGroup struct {
+	rows [][]interface{}
+	// acc accounts for the memory used by the row group. It is closed once the
+	// row group is consumed.
+	acc mon.BoundAccount
+	err error
+}
+
+// parquetRowStream is an importRowProducer which decodes the row groups of a
+// parquet file in parallel. Row groups are returned in file order, so that the
+// row numbers used to resume an import are stable.
+type parquetRowStream struct {
+	// src is the parquet file and size is its size in bytes.
+	src  io.ReaderAt
+	size int64
+	// metadata is the metadata read from the footer of the file. It is shared
+	// by the readers used to decode row groups.
+	metadata *metadata.FileMetaData
+	columns  []parquetColumn
+	// memMonitor is used to account for the memory used by decoded row groups.
+	memMonitor *mon.BytesMonitor
+
+	ctx   context.Context
+	group ctxgroup.Group
+	done  <-chan struct{}
+	// sem limits the number of row groups which are decoded but not yet
+	// consumed.
+	sem chan struct{}
+	// results has a channel for each row group, which receives the row group
+	// once it is decoded.
+	results []chan parquetRowGroup
+
+	nextGroup int
+	rows      [][]interface{}
+	// acc accounts for the memory used by rows.
+	acc mon.BoundAccount
+	pos int
+	row []interface{}
+	err error
+
+	totalRows int64
+	rowsRead  int64
+}
+
+var _ importRowProducer = &parquetRowStream{}
+
+// start begins decoding the row groups of the file.
+func (s *parquetRowStream) start(ctx context.Context, parallelism int) {
+	if parallelism <= 0 {
+		parallelism = 1
+	}
+	s.sem = make(chan struct{}, parallelism)
+	s.ctx = ctx
+	s.group = ctxgroup.WithContext(ctx)
+	s.done = ctx.Done()
+	s.group.GoCtx(func(ctx context.Context) error {
+		// Decoding is scheduled in file order, which ensures that the next row
+		// group to be consumed is never starved by later ones.
+		for rg := range s.results {
+			select {
+			case s.sem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			rg := rg
+			s.group.GoCtx(func(ctx context.Context) error {
+				res := parquetRowGroup{acc: s.memMonitor.MakeBoundAccount()}
+				res.rows, res.err = s.decodeRowGroup(ctx, rg, &res.acc)
+				// The results channels are buffered.
+				s.results[rg] <- res
+				return nil
+			})
+		}
+		return nil
+	})
+}
+
+// wait waits for the decoding goroutines to exit. The context passed to start
+// must be canceled first if the stream was not fully consumed.
+func (s *parquetRowStream) wait() {
+	// Decoding errors are returned from Scan, so only context cancellation
+	// errors are returned by the group.
+	_ = s.group.Wait()
+	// Release the memory of the row groups that were not consumed.
+	s.acc.Close(s.ctx)
+	for _, ch := range s.results[s.nextGroup:] {
+		select {
+		case res := <-ch:
+			res.acc.Close(s.ctx)
+		default:
+		}
+	}
+}
+
+// decodeRowGroup reads the imported columns of a row group. The memory used by
+// the row group is accounted for in acc.
+func (s *parquetRowStream) decodeRowGroup(
+	ctx context.Context, rg int, acc *mon.BoundAccount,
+) ([][]interface{}, error) {
+	// Each row group is decoded with its own reader, as readers are not safe
+	// for concurrent use.
+	reader, err := file.NewParquetReader(
+		io.NewSectionReader(s.src, 0, s.size), file.WithMetadata(s.metadata),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	rgr := reader.RowGroup(rg)
+	// The column chunks are read into memory, and the size of the decoded
+	// values is approximated by the uncompressed size of the column chunks.
+	var size int64
+	for i := range s.columns {
+		chunk, err := rgr.MetaData().ColumnChunk(s.columns[i].leafIdx)
+		if err != nil {
+			return nil, err
+		}
+		size += chunk.TotalCompressedSize() + chunk.TotalUncompressedSize()
+	}
+	if err := acc.Grow(ctx, size); err != nil {
+		return nil, errors.Wrapf(err, "decoding row group %d", rg)
+	}
+	rows := make([][]interface{}, rgr.NumRows())
+	for i := range rows {
+		rows[i] = make([]interface{}, len(s.columns))
+	}
+	for i := range s.columns {
+		cr, err := rgr.Column(s.columns[i].leafIdx)
+		if err != nil {
+			return nil, err
+		}
+		if err := readParquetColumnChunk(cr, &s.columns[i], rows, i); err != nil {
+			return nil, errors.Wrapf(err, "reading row group %d", rg)
+		}
+	}
+	return rows, nil
+}
+
+// Scan implements importRowProducer interface.
+func (s *parquetRowStream) Scan() bool {
+	for s.pos >= len(s.rows) {
+		if s.err != nil || s.nextGroup >= len(s.results) {
+			return false
+		}
+		var res parquetRowGroup
+		select {
+		case res = <-s.results[s.nextGroup]:
+		case <-s.done:
+			s.err = errors.New("parquet import canceled")
+			return false
+		}
+		<-s.sem
+		s.nextGroup++
+		s.acc.Close(s.ctx)
+		s.acc = res.acc
+		if res.err != nil {
+			s.err = res.err
+			return false
+		}
+		s.rows, s.pos = res.rows, 0
+	}
+	s.row = s.rows[s.pos]
+	s.rows[s.pos] = nil
+	s.pos++
+	s.rowsRead++
+	return true
+}
+
+// Err implements importRowProducer interface.
+func (s *parquetRowStream) Err() error {
+	return s.err
+}
+
+// Skip implements importRowProducer interface.
+func (s *parquetRowStream) Skip() error {
+	s.row = nil
+	return nil
+}
+
+// Row implements importRowProducer interface.
+func (s *parquetRowStream) Row() (interface{}, error) {
+	res := s.row
+	s.row = nil
+	return res, nil
+}
+
+// Progress implements importRowProducer interface.
+func (s *parquetRowStream) Progress() float32 {
+	if s.totalRows == 0 {
+		return 0
+	}
+	return float32(s.rowsRead) / float32(s.totalRows)
+}
+
+// bufferParquetFile reads a parquet file which cannot be read with ranged
+// reads into memory. The memory is accounted for in acc.
+func bufferParquetFile(
+	ctx context.Context, p *parquetInputReader, input *fileReader, acc *mon.BoundAccount,
+) ([]byte, error) {
+	maxSize := parquetMaxFileSize.Get(&p.importContext.evalCtx.Settings.SV)
+	var buf bytes.Buffer
+	chunk := make([]byte, 64<<10)
+	for {
+		n, err := input.Read(chunk)
+		if n > 0 {
+			if int64(buf.Len()+n) > maxSize {
+				return nil, errors.WithHintf(
+					errors.Newf("compressed parquet file is larger than %s", humanizeutil.IBytes(maxSize)),
+					"increase the %s cluster setting, split the file or import it uncompressed",
+					parquetMaxFileSize.Name(),
+				)
+			}
+			if err := acc.Grow(ctx, int64(n)); err != nil {
+				return nil, err
+			}
+			buf.Write(chunk[:n])
+		}
+		if err == io.EOF {
+			return buf.Bytes(), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// newImportParquetPipeline sets up the decoding of a parquet file. The
+// returned account holds the memory of the file if it had to be buffered, and
+// must be closed once the file is imported.
+func newImportParquetPipeline(
+	ctx context.Context, p *parquetInputReader, input *fileReader,
+) (*parquetRowStream, importRowConsumer, *mon.BoundAccount, error) {
+	fileAcc := p.memMonitor.MakeBoundAccount()
+	src, size := input.ranged, input.total
+	if src == nil {
+		data, err := bufferParquetFile(ctx, p, input, &fileAcc)
+		if err != nil {
+			fileAcc.Close(ctx)
+			return nil, nil, nil, err
+		}
+		src, size = bytes.NewReader(data), int64(len(data))
+	}
+	reader, err := file.NewParquetReader(io.NewSectionReader(src, 0, size))
+	if err != nil {
+		fileAcc.Close(ctx)
+		return nil, nil, nil, err
+	}
+	defer func() { _ = reader.Close() }()
+
+	fieldIdxByName := make(map[string]int)
+	for idx, col := range p.importContext.tableDesc.VisibleColumns() {
+		fieldIdxByName[col.GetName()] = idx
+	}
+	columns, err := makeParquetColumns(reader, fieldIdxByName, p.opts.StrictMode)
+	if err != nil {
+		fileAcc.Close(ctx)
+		return nil, nil, nil, err
+	}
+
+	producer := &parquetRowStream{
+		src:        src,
+		size:       size,
+		metadata:   reader.MetaData(),
+		columns:    columns,
+		memMonitor: p.memMonitor,
+		results:    make([]chan parquetRowGroup, reader.NumRowGroups()),
+		totalRows:  reader.NumRows(),
+	}
+	for i := range producer.results {
+		producer.results[i] = make(chan parquetRowGroup, 1)
+	}
+	producer.start(ctx, p.importContext.numWorkers)
+
+	consumer := &parquetConsumer{
+		importCtx: p.importContext,
+		columns:   columns,
+		strict:    p.opts.StrictMode,
+	}
+	return producer, consumer, &fileAcc, nil
+}
+
+type parquetInputReader struct {
+	importContext *parallelImportContext
+	opts          roachpb.ParquetOptions
+	// memMonitor is used to account for the memory used by buffered files and
+	// decoded row groups.
+	memMonitor *mon.BytesMonitor
+}
+
+var _ inputConverter = &parquetInputReader{}
+
+func newParquetInputReader(
+	semaCtx *tree.SemaContext,
+	kvCh chan row.KVBatch,
+	tableDesc catalog.TableDescriptor,
+	parquetOpts roachpb.ParquetOptions,
+	walltime int64,
+	parallelism int,
+	evalCtx *eval.Context,
+	db *kv.DB,
+	memMonitor *mon.BytesMonitor,
+) *parquetInputReader {
+	return &parquetInputReader{
+		importContext: &parallelImportContext{
+			semaCtx:    semaCtx,
+			walltime:   walltime,
+			numWorkers: parallelism,
+			evalCtx:    evalCtx,
+			tableDesc:  tableDesc,
+			kvCh:       kvCh,
+			db:         db,
+		},
+		opts:       parquetOpts,
+		memMonitor: memMonitor,
+	}
+}
+
+func (p *parquetInputReader) start(group ctxgroup.Group) {}
+
+func (p *parquetInputReader) readFiles(
+	ctx context.Context,
+	dataFiles map[int32]string,
+	resumePos map[int32]int64,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	user username.SQLUsername,
+) error {
+	return readInputFiles(ctx, dataFiles, resumePos, format, p.readFile, makeExternalStorage, user)
+}
+
+func (p *parquetInputReader) readFile(
+	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected chan string,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	producer, consumer, fileAcc, err := newImportParquetPipeline(ctx, p, input)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// The producer may not be fully consumed, e.g. if a row limit is set.
+		cancel()
+		producer.wait()
+		fileAcc.Close(ctx)
+	}()
+
+	fileCtx := &importFileContext{
+		source:   inputIdx,
+		skip:     resumePos,
+		rejected: rejected,
+		rowLimit: p.opts.RowLimit,
+	}
+	return runParallelImport(ctx, p.importContext, fileCtx, producer, consumer)
+}
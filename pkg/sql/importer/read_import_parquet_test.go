@@ -0,0 +1,322 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package importer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/arrow/go/v11/parquet"
+	"github.com/apache/arrow/go/v11/parquet/schema"
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParquetValueToDatum(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := eval.MakeTestingEvalContext(st)
+	semaCtx := tree.MakeSemaContext(nil /* resolver */)
+
+	for _, tc := range []struct {
+		name     string
+		physical parquet.Type
+		logical  schema.LogicalType
+		value    interface{}
+		typ      *types.T
+		expected string
+	}{
+		{
+			name:     "int32",
+			physical: parquet.Types.Int32,
+			logical:  schema.NoLogicalType{},
+			value:    int32(-7),
+			typ:      types.Int,
+			expected: "-7",
+		},
+		{
+			name:     "int64-to-decimal",
+			physical: parquet.Types.Int64,
+			logical:  schema.NoLogicalType{},
+			value:    int64(12),
+			typ:      types.Decimal,
+			expected: "12",
+		},
+		{
+			name:     "uint32",
+			physical: parquet.Types.Int32,
+			logical:  schema.NewIntLogicalType(32, false /* signed */),
+			value:    int32(-1),
+			typ:      types.Int,
+			expected: "4294967295",
+		},
+		{
+			name:     "date",
+			physical: parquet.Types.Int32,
+			logical:  schema.DateLogicalType{},
+			value:    int32(19000),
+			typ:      types.Date,
+			expected: "'2022-01-08'",
+		},
+		{
+			name:     "timestamp-millis",
+			physical: parquet.Types.Int64,
+			logical:  schema.NewTimestampLogicalType(false /* isAdjustedToUTC */, schema.TimeUnitMillis),
+			value:    int64(1641600000123),
+			typ:      types.Timestamp,
+			expected: "'2022-01-08 00:00:00.123'",
+		},
+		{
+			name:     "timestamptz-micros",
+			physical: parquet.Types.Int64,
+			logical:  schema.NewTimestampLogicalType(true /* isAdjustedToUTC */, schema.TimeUnitMicros),
+			value:    int64(1641600000000001),
+			typ:      types.TimestampTZ,
+			expected: "'2022-01-08 00:00:00.000001+00'",
+		},
+		{
+			name:     "time-micros",
+			physical: parquet.Types.Int64,
+			logical:  schema.NewTimeLogicalType(false /* isAdjustedToUTC */, schema.TimeUnitMicros),
+			value:    int64(3723000004),
+			typ:      types.Time,
+			expected: "'01:02:03.000004'",
+		},
+		{
+			name:     "int32-decimal",
+			physical: parquet.Types.Int32,
+			logical:  schema.NewDecimalLogicalType(9 /* precision */, 2 /* scale */),
+			value:    int32(-12345),
+			typ:      types.Decimal,
+			expected: "-123.45",
+		},
+		{
+			name:     "byte-array-decimal",
+			physical: parquet.Types.ByteArray,
+			logical:  schema.NewDecimalLogicalType(20 /* precision */, 3 /* scale */),
+			value:    []byte{0xff, 0x85},
+			typ:      types.Decimal,
+			expected: "-0.123",
+		},
+		{
+			name:     "string",
+			physical: parquet.Types.ByteArray,
+			logical:  schema.StringLogicalType{},
+			value:    []byte("hello"),
+			typ:      types.String,
+			expected: "'hello'",
+		},
+		{
+			name:     "string-to-inet",
+			physical: parquet.Types.ByteArray,
+			logical:  schema.StringLogicalType{},
+			value:    []byte("192.168.0.1"),
+			typ:      types.INet,
+			expected: "'192.168.0.1'",
+		},
+		{
+			name:     "bytes",
+			physical: parquet.Types.ByteArray,
+			logical:  schema.NoLogicalType{},
+			value:    []byte("abc"),
+			typ:      types.Bytes,
+			expected: `'\x616263'`,
+		},
+		{
+			name:     "json",
+			physical: parquet.Types.ByteArray,
+			logical:  schema.JSONLogicalType{},
+			value:    []byte(`{"a": 1}`),
+			typ:      types.Jsonb,
+			expected: `'{"a": 1}'`,
+		},
+		{
+			name:     "uuid",
+			physical: parquet.Types.FixedLenByteArray,
+			logical:  schema.UUIDLogicalType{},
+			value:    []byte{0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0, 0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0},
+			typ:      types.Uuid,
+			expected: "'12345678-9abc-def0-1234-56789abcdef0'",
+		},
+		{
+			name:     "list",
+			physical: parquet.Types.Int64,
+			logical:  schema.NoLogicalType{},
+			value:    []interface{}{int64(1), nil, int64(3)},
+			typ:      types.IntArray,
+			expected: "ARRAY[1,NULL,3]",
+		},
+		{
+			name:     "null",
+			physical: parquet.Types.Int64,
+			logical:  schema.NoLogicalType{},
+			value:    nil,
+			typ:      types.Int,
+			expected: "NULL",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			col := &parquetColumn{name: tc.name, physical: tc.physical, logical: tc.logical}
+			d, err := parquetValueToDatum(ctx, tc.value, col, tc.typ, &evalCtx, &semaCtx)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, tree.AsString(d))
+		})
+	}
+
+	t.Run("out-of-range", func(t *testing.T) {
+		col := &parquetColumn{name: "x", physical: parquet.Types.Int64, logical: schema.NoLogicalType{}}
+		_, err := parquetValueToDatum(ctx, int64(1<<40), col, types.Int2, &evalCtx, &semaCtx)
+		require.ErrorContains(t, err, "out of range")
+	})
+
+	t.Run("list-to-scalar", func(t *testing.T) {
+		col := &parquetColumn{name: "x", physical: parquet.Types.Int64, logical: schema.NoLogicalType{}}
+		_, err := parquetValueToDatum(ctx, []interface{}{int64(1)}, col, types.Int, &evalCtx, &semaCtx)
+		require.ErrorContains(t, err, "cannot convert parquet list x to non-array type")
+	})
+}
+
+// TestImportParquet exports tables using EXPORT PARQUET and imports the
+// resulting files back with IMPORT INTO.
+func TestImportParquet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	dir, dirCleanupFn := testutils.TempDir(t)
+	defer dirCleanupFn()
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	const cols = `(
+		i INT PRIMARY KEY, s STRING, b BOOL, f FLOAT, d DECIMAL(10, 2), ts TIMESTAMP,
+		tz TIMESTAMPTZ, dt DATE, u UUID, j JSONB, bs BYTES, a INT[], ip INET
+	)`
+	sqlDB.Exec(t, `CREATE TABLE src `+cols)
+	sqlDB.Exec(t, `INSERT INTO src
+		SELECT
+			g, 'row ' || g::STRING, g % 2 = 0, g::FLOAT / 3, g::DECIMAL / 7,
+			'2024-01-01'::TIMESTAMP + g * '1 hour'::INTERVAL,
+			'2024-01-01 10:00:00+00'::TIMESTAMPTZ + g * '1 second'::INTERVAL,
+			'2024-01-01'::DATE + g, gen_random_uuid(), json_build_object('g', g),
+			g::STRING::BYTES, ARRAY[g, NULL, g + 1], ('10.0.0.' || (g % 255)::STRING)::INET
+		FROM generate_series(1, 1000) AS g`)
+	sqlDB.Exec(t, `INSERT INTO src (i) VALUES (0)`)
+	sqlDB.Exec(t, `EXPORT INTO PARQUET 'nodelocal://1/src' FROM TABLE src`)
+
+	const except = `SELECT count(*) FROM ((TABLE src EXCEPT ALL TABLE dst) UNION ALL (TABLE dst EXCEPT ALL TABLE src))`
+
+	t.Run("round-trip", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE dst `+cols)
+		defer sqlDB.Exec(t, `DROP TABLE dst`)
+		sqlDB.Exec(t, `IMPORT INTO dst PARQUET DATA ('nodelocal://1/src/*.parquet')`)
+		sqlDB.CheckQueryResults(t, except, [][]string{{"0"}})
+	})
+
+	// Compressed files cannot be read with ranged reads, so they are buffered
+	// in memory.
+	t.Run("compressed", func(t *testing.T) {
+		files, err := filepath.Glob(filepath.Join(dir, "src", "*.parquet"))
+		require.NoError(t, err)
+		require.NotEmpty(t, files)
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "gz"), 0755))
+		for _, f := range files {
+			data, err := os.ReadFile(f)
+			require.NoError(t, err)
+			var buf bytes.Buffer
+			gw := gzip.NewWriter(&buf)
+			_, err = gw.Write(data)
+			require.NoError(t, err)
+			require.NoError(t, gw.Close())
+			require.NoError(t, os.WriteFile(
+				filepath.Join(dir, "gz", filepath.Base(f)+".gz"), buf.Bytes(), 0644,
+			))
+		}
+
+		sqlDB.Exec(t, `CREATE TABLE dst `+cols)
+		defer sqlDB.Exec(t, `DROP TABLE dst`)
+		sqlDB.Exec(t, `IMPORT INTO dst PARQUET DATA ('nodelocal://1/gz/*.parquet.gz')`)
+		sqlDB.CheckQueryResults(t, except, [][]string{{"0"}})
+
+		sqlDB.Exec(t, `SET CLUSTER SETTING bulkio.import.parquet.max_file_size = '10B'`)
+		defer sqlDB.Exec(t, `RESET CLUSTER SETTING bulkio.import.parquet.max_file_size`)
+		sqlDB.ExpectErr(t, "compressed parquet file is larger than",
+			`IMPORT INTO dst PARQUET DATA ('nodelocal://1/gz/*.parquet.gz')`)
+		// Uncompressed files are not subject to the limit.
+		sqlDB.Exec(t, `TRUNCATE dst`)
+		sqlDB.Exec(t, `IMPORT INTO dst PARQUET DATA ('nodelocal://1/src/*.parquet')`)
+		sqlDB.CheckQueryResults(t, except, [][]string{{"0"}})
+	})
+
+	t.Run("relaxed-import-sets-missing-fields", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE dst (i INT PRIMARY KEY, s STRING, z INT)`)
+		defer sqlDB.Exec(t, `DROP TABLE dst`)
+		sqlDB.Exec(t, `IMPORT INTO dst PARQUET DATA ('nodelocal://1/src/*.parquet')`)
+		sqlDB.CheckQueryResults(t,
+			`SELECT count(*), count(s), count(z) FROM dst`, [][]string{{"1001", "1000", "0"}})
+	})
+
+	t.Run("strict-import-errors-extra-fields", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE dst (i INT PRIMARY KEY, s STRING)`)
+		defer sqlDB.Exec(t, `DROP TABLE dst`)
+		sqlDB.ExpectErr(t, "could not find column for parquet field",
+			`IMPORT INTO dst PARQUET DATA ('nodelocal://1/src/*.parquet') WITH strict_validation`)
+	})
+
+	t.Run("strict-import-errors-missing-fields", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE dst `+cols)
+		sqlDB.Exec(t, `ALTER TABLE dst ADD COLUMN z INT`)
+		defer sqlDB.Exec(t, `DROP TABLE dst`)
+		sqlDB.ExpectErr(t, "field z was not set in the parquet import",
+			`IMPORT INTO dst PARQUET DATA ('nodelocal://1/src/*.parquet') WITH strict_validation`)
+	})
+
+	t.Run("row-limit", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE dst `+cols)
+		defer sqlDB.Exec(t, `DROP TABLE dst`)
+		sqlDB.Exec(t, `IMPORT INTO dst PARQUET DATA ('nodelocal://1/src/*.parquet') WITH row_limit = '10'`)
+		var n int
+		sqlDB.QueryRow(t, `SELECT count(*) FROM dst`).Scan(&n)
+		require.LessOrEqual(t, n, 10)
+		require.Greater(t, n, 0)
+	})
+
+	t.Run("incompatible-type", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE dst (i INT PRIMARY KEY, b INT)`)
+		defer sqlDB.Exec(t, `DROP TABLE dst`)
+		sqlDB.ExpectErr(t, "cannot convert parquet field b of type bool to int",
+			`IMPORT INTO dst PARQUET DATA ('nodelocal://1/src/*.parquet')`)
+	})
+
+	t.Run("unsupported-option", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE dst (i INT PRIMARY KEY)`)
+		defer sqlDB.Exec(t, `DROP TABLE dst`)
+		sqlDB.ExpectErr(t, "invalid option \"delimiter\" specified for PARQUET import format",
+			`IMPORT INTO dst PARQUET DATA ('nodelocal://1/src/*.parquet') WITH delimiter = '|'`)
+	})
+}
@@ -1472,13 +1472,19 @@ https://www.postgresql.org/docs/9.5/infoschema-table-constraints.html`,
 
 				for _, c := range table.AllConstraints() {
 					kind := catconstants.ConstraintTypeUnique
+					deferrability := tree.ConstraintNotDeferrable
 					if c.AsCheck() != nil {
 						kind = catconstants.ConstraintTypeCheck
-					} else if c.AsForeignKey() != nil {
+					} else if fk := c.AsForeignKey(); fk != nil {
 						kind = catconstants.ConstraintTypeFK
+						deferrability = tree.ConstraintDeferrability(fk.ForeignKeyDesc().Deferrability)
 					} else if u := c.AsUniqueWithIndex(); u != nil && u.Primary() {
 						kind = catconstants.ConstraintTypePK
+					} else if uwoi := c.AsUniqueWithoutIndex(); uwoi != nil {
+						deferrability = tree.ConstraintDeferrability(uwoi.UniqueWithoutIndexDesc().Deferrability)
 					}
+					isDeferrable := yesOrNoDatum(deferrability.IsDeferrable())
+					initiallyDeferred := yesOrNoDatum(deferrability == tree.DeferrableInitiallyDeferred)
 					if err := addRow(
 						dbNameStr,                     // constraint_catalog
 						scNameStr,                     // constraint_schema
@@ -1487,8 +1493,8 @@ https://www.postgresql.org/docs/9.5/infoschema-table-constraints.html`,
 						scNameStr,                     // table_schema
 						tbNameStr,                     // table_name
 						tree.NewDString(string(kind)), // constraint_type
-						yesOrNoDatum(false),           // is_deferrable
-						yesOrNoDatum(false),           // initially_deferred
+						isDeferrable,                  // is_deferrable
+						initiallyDeferred,             // initially_deferred
 					); err != nil {
 						return err
 					}
@@ -2130,12 +2136,61 @@ var informationSchemaUsagePrivilegesTable = virtualSchemaTable{
 }
 
 var informationSchemaDomainsTable = virtualSchemaTable{
-	comment: "domains was created for compatibility and is currently unimplemented",
-	schema:  vtable.InformationSchemaDomains,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `domains defined in the current database
+https://www.postgresql.org/docs/9.5/infoschema-domains.html`,
+	schema: vtable.InformationSchemaDomains,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return forEachDatabaseDesc(ctx, p, dbContext, true, /* requiresPrivileges */
+			func(ctx context.Context, db catalog.DatabaseDescriptor) error {
+				dbNameStr := tree.NewDString(db.GetName())
+				return forEachTypeDesc(ctx, p, db, func(ctx context.Context, db catalog.DatabaseDescriptor, sc catalog.SchemaDescriptor, typeDesc catalog.TypeDescriptor) error {
+					domainDesc := typeDesc.AsDomainTypeDescriptor()
+					if domainDesc == nil {
+						return nil
+					}
+					baseType := domainDesc.GetBaseType()
+					domainDefault := tree.DNull
+					if defaultExpr, ok := domainDesc.GetDomainDefaultExpr(); ok {
+						domainDefault = tree.NewDString(defaultExpr)
+					}
+					collationCatalog, collationSchema, collationName := tree.DNull, tree.DNull, tree.DNull
+					if locale := baseType.Locale(); locale != "" {
+						collationCatalog = dbNameStr
+						collationSchema = pgCatalogNameDString
+						collationName = tree.NewDString(locale)
+					}
+					return addRow(
+						dbNameStr,                                         // domain_catalog
+						tree.NewDString(sc.GetName()),                     // domain_schema
+						tree.NewDString(typeDesc.GetName()),               // domain_name
+						tree.NewDString(baseType.InformationSchemaName()), // data_type
+						characterMaximumLength(baseType),                  // character_maximum_length
+						characterOctetLength(baseType),                    // character_octet_length
+						tree.DNull,                                        // character_set_catalog
+						tree.DNull,                                        // character_set_schema
+						tree.DNull,                                        // character_set_name
+						collationCatalog,                                  // collation_catalog
+						collationSchema,                                   // collation_schema
+						collationName,                                     // collation_name
+						numericPrecision(baseType),                        // numeric_precision
+						numericPrecisionRadix(baseType),                   // numeric_precision_radix
+						numericScale(baseType),                            // numeric_scale
+						datetimePrecision(baseType),                       // datetime_precision
+						tree.DNull,                                        // interval_type
+						tree.DNull,                                        // interval_precision
+						domainDefault,                                     // domain_default
+						dbNameStr,                                         // udt_catalog
+						pgCatalogNameDString,                              // udt_schema
+						tree.NewDString(baseType.PGName()),                // udt_name
+						tree.DNull,                                        // scope_catalog
+						tree.DNull,                                        // scope_schema
+						tree.DNull,                                        // scope_name
+						tree.DNull,                                        // maximum_cardinality
+						tree.DNull,                                        // dtd_identifier
+					)
+				})
+			})
 	},
-	unimplemented: true,
 }
 
 var informationSchemaSQLImplementationInfoTable = virtualSchemaTable{
@@ -0,0 +1,616 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangefeed"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/valueside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// Notifications generated by NOTIFY and pg_notify are written to
+// system.notifications by the transaction that generates them, so they are
+// only visible once it commits. Statements that generate notifications never
+// commit their transaction in one phase, so the notifications are always
+// written before the commit. Every node that has listening sessions runs a
+// rangefeed over the table and routes the notifications to its listeners,
+// which deliver them to their clients the next time the session is idle. A
+// session receives the notifications committed after its LISTEN transaction.
+
+const (
+	// notificationChannelMaxLength is the maximum length of a channel name, in
+	// bytes. It matches the maximum identifier length of Postgres.
+	notificationChannelMaxLength = 63
+	// notificationPayloadMaxLength is the maximum length of a notification
+	// payload, in bytes. It matches the limit of Postgres.
+	notificationPayloadMaxLength = 8000
+	// notificationCleanupInterval is the interval at which expired
+	// notifications are deleted.
+	notificationCleanupInterval = time.Minute
+	// notificationCleanupBatchSize is the maximum number of notifications
+	// deleted by a single statement.
+	notificationCleanupBatchSize = 1000
+)
+
+var notificationRetention = settings.RegisterDurationSetting(
+	settings.ApplicationLevel,
+	"sql.notifications.retention",
+	"the amount of time for which the notifications sent by NOTIFY are kept in "+
+		"system.notifications; notifications that are not delivered to a listening "+
+		"session within this time may be lost",
+	10*time.Minute,
+	settings.PositiveDuration,
+)
+
+// Notification is an asynchronous notification sent by NOTIFY or pg_notify.
+type Notification struct {
+	// PID is the backend PID of the session that sent the notification.
+	PID     uint32
+	Channel string
+	Payload string
+}
+
+// notificationKey identifies a notification sent by a transaction. A
+// transaction sends each distinct notification only once.
+type notificationKey struct {
+	channel string
+	payload string
+}
+
+// listenOp is a LISTEN or UNLISTEN operation. UNLISTEN * is represented by an
+// UNLISTEN of the empty channel.
+type listenOp struct {
+	channel string
+	listen  bool
+}
+
+// txnNotificationState is the per-transaction state backing LISTEN, UNLISTEN
+// and NOTIFY, none of which take effect until the transaction commits.
+type txnNotificationState struct {
+	// pending contains the notifications to send when the transaction commits,
+	// in the order in which they were generated.
+	pending []notificationKey
+	// listenOps contains the LISTEN and UNLISTEN operations to apply to the
+	// session's listener when the transaction commits.
+	listenOps []listenOp
+}
+
+// notificationMark records the length of the pending notifications and
+// operations when a savepoint is created.
+type notificationMark struct {
+	numPending   int
+	numListenOps int
+}
+
+// notify queues a notification, unless an identical one is already queued.
+func (s *txnNotificationState) notify(channel, payload string) {
+	k := notificationKey{channel: channel, payload: payload}
+	for _, p := range s.pending {
+		if p == k {
+			return
+		}
+	}
+	s.pending = append(s.pending, k)
+}
+
+// hasPending returns whether there are notifications to send.
+func (s *txnNotificationState) hasPending() bool {
+	return len(s.pending) > 0
+}
+
+// mark returns the current position in the state, for use by savepoints.
+func (s *txnNotificationState) mark() notificationMark {
+	return notificationMark{numPending: len(s.pending), numListenOps: len(s.listenOps)}
+}
+
+// rollbackTo discards the notifications and operations queued since m was
+// taken.
+func (s *txnNotificationState) rollbackTo(m notificationMark) {
+	if m.numPending < len(s.pending) {
+		s.pending = s.pending[:m.numPending]
+	}
+	if m.numListenOps < len(s.listenOps) {
+		s.listenOps = s.listenOps[:m.numListenOps]
+	}
+}
+
+// reset clears the state at the end of a transaction.
+func (s *txnNotificationState) reset() {
+	*s = txnNotificationState{}
+}
+
+// write inserts the pending notifications into system.notifications using the
+// given transaction.
+func (s *txnNotificationState) write(ctx context.Context, txn isql.Txn, pid uint32) error {
+	channels := make([]string, len(s.pending))
+	payloads := make([]string, len(s.pending))
+	for i, n := range s.pending {
+		channels[i] = n.channel
+		payloads[i] = n.payload
+	}
+	_, err := txn.ExecEx(
+		ctx, "insert-notifications", txn.KV(), sessiondata.NodeUserSessionDataOverride,
+		`INSERT INTO system.notifications (pid, channels, payloads) VALUES ($1, $2, $3)`,
+		pid, channels, payloads,
+	)
+	return err
+}
+
+// validateNotificationChannel returns an error if the given channel name is
+// not valid for LISTEN or NOTIFY.
+func validateNotificationChannel(channel string) error {
+	if channel == "" {
+		return pgerror.New(pgcode.InvalidParameterValue, "channel name cannot be empty")
+	}
+	if len(channel) > notificationChannelMaxLength {
+		return pgerror.New(pgcode.InvalidParameterValue, "channel name too long")
+	}
+	return nil
+}
+
+// notificationState returns the notification state of the current
+// transaction, or an error if the statement cannot use it.
+func (p *planner) notificationState(ctx context.Context, op string) (*txnNotificationState, error) {
+	s := p.extendedEvalCtx.notifications
+	if s == nil {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported, "%s is not supported in this context", op)
+	}
+	if !p.execCfg.Settings.Version.IsActive(ctx, clusterversion.V24_3_NotificationsTable) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s is not supported until the cluster version is finalized", op)
+	}
+	return s, nil
+}
+
+// Listen implements the LISTEN statement.
+func (p *planner) Listen(ctx context.Context, n *tree.Listen) (planNode, error) {
+	channel := string(n.ChannelName)
+	if err := validateNotificationChannel(channel); err != nil {
+		return nil, err
+	}
+	if p.SessionData().Internal {
+		return nil, pgerror.New(pgcode.FeatureNotSupported, "LISTEN is not supported in this context")
+	}
+	s, err := p.notificationState(ctx, "LISTEN")
+	if err != nil {
+		return nil, err
+	}
+	// Start watching for notifications right away, so that errors are reported
+	// to the client and the session doesn't miss notifications committed right
+	// after its transaction.
+	if err := p.execCfg.NotificationRegistry.start(ctx, p.txn.ReadTimestamp()); err != nil {
+		return nil, err
+	}
+	s.listenOps = append(s.listenOps, listenOp{channel: channel, listen: true})
+	return newZeroNode(nil /* columns */), nil
+}
+
+// Notify implements the NOTIFY statement.
+func (p *planner) Notify(ctx context.Context, channel, payload string) error {
+	if err := validateNotificationChannel(channel); err != nil {
+		return err
+	}
+	if len(payload) >= notificationPayloadMaxLength {
+		return pgerror.New(pgcode.InvalidParameterValue, "payload string too long")
+	}
+	s, err := p.notificationState(ctx, "NOTIFY")
+	if err != nil {
+		return err
+	}
+	p.execCfg.NotificationRegistry.startCleanup(ctx)
+	s.notify(channel, payload)
+	return nil
+}
+
+// HasPendingNotifications is part of the eval.Planner interface.
+func (p *planner) HasPendingNotifications() bool {
+	s := p.extendedEvalCtx.notifications
+	return s != nil && s.hasPending()
+}
+
+// NotifyStmt implements the NOTIFY statement.
+func (p *planner) NotifyStmt(ctx context.Context, n *tree.Notify) (planNode, error) {
+	if err := p.Notify(ctx, string(n.ChannelName), n.Payload); err != nil {
+		return nil, err
+	}
+	return newZeroNode(nil /* columns */), nil
+}
+
+// NotificationRegistry routes the notifications committed to
+// system.notifications to the listening sessions of this node.
+//
+// The registry doesn't do any work until the node has a session that uses
+// LISTEN or NOTIFY.
+type NotificationRegistry struct {
+	cfg *ExecutorConfig
+
+	// decoder decodes the rows of system.notifications.
+	decoder valueside.Decoder
+
+	mu struct {
+		syncutil.Mutex
+		// started is set once the rangefeed has been started at startTS.
+		started bool
+		startTS hlc.Timestamp
+		// cleanupStarted is set once the task that deletes expired
+		// notifications has been started.
+		cleanupStarted bool
+		listeners      map[*notificationListener]struct{}
+		// frontier is the resolved timestamp of the rangefeed. delivered
+		// contains the keys and timestamps of the rows above the frontier that
+		// have been routed to listeners, so that rows that are emitted again
+		// after the rangefeed restarts are ignored.
+		frontier  hlc.Timestamp
+		delivered map[string]hlc.Timestamp
+	}
+}
+
+// NewNotificationRegistry creates a NotificationRegistry.
+func NewNotificationRegistry(cfg *ExecutorConfig) *NotificationRegistry {
+	r := &NotificationRegistry{
+		cfg:     cfg,
+		decoder: valueside.MakeDecoder(systemschema.NotificationsTable.PublicColumns()),
+	}
+	r.mu.listeners = make(map[*notificationListener]struct{})
+	r.mu.delivered = make(map[string]hlc.Timestamp)
+	return r
+}
+
+// start starts the rangefeed on system.notifications, if it isn't running
+// already. readTS is the read timestamp of the transaction that runs LISTEN,
+// which is a lower bound of its commit timestamp.
+//
+// The rangefeed starts at or below readTS, so that it emits the notifications
+// committed after the transaction. It also starts below the closed timestamp
+// target, so that the rangefeed also covers the LISTEN transactions of other
+// sessions that are already running. A listener whose transaction committed
+// below the start of the rangefeed anyway only receives the notifications
+// committed after that start. See notificationListener.apply.
+func (r *NotificationRegistry) start(ctx context.Context, readTS hlc.Timestamp) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mu.started {
+		return nil
+	}
+	tableID, err := r.cfg.SystemTableIDResolver.LookupSystemTableID(
+		ctx, string(catconstants.NotificationsTableName),
+	)
+	if err != nil {
+		return err
+	}
+	prefix := r.cfg.Codec.TablePrefix(uint32(tableID))
+	span := roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()}
+	// The rangefeed outlives the statement that starts it, and it stops
+	// automatically when the server shuts down.
+	bgCtx := r.cfg.AmbientCtx.AnnotateCtx(context.Background())
+	startTS := r.cfg.Clock.Now().AddDuration(-closedts.TargetDuration.Get(&r.cfg.Settings.SV))
+	startTS.Backward(readTS)
+	if _, err := r.cfg.RangeFeedFactory.RangeFeed(
+		bgCtx,
+		"notifications",
+		[]roachpb.Span{span},
+		startTS,
+		r.onValue,
+		rangefeed.WithSystemTablePriority(),
+		rangefeed.WithOnFrontierAdvance(r.onFrontierAdvance),
+	); err != nil {
+		return err
+	}
+	r.mu.started = true
+	r.mu.startTS = startTS
+	// Rows at or below the start timestamp are never emitted.
+	r.mu.frontier.Forward(startTS)
+	return nil
+}
+
+// startTimestamp returns the timestamp at which the rangefeed started.
+func (r *NotificationRegistry) startTimestamp() hlc.Timestamp {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.mu.startTS
+}
+
+// startCleanup starts the task that deletes expired notifications, if it
+// isn't running already.
+func (r *NotificationRegistry) startCleanup(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mu.cleanupStarted {
+		return
+	}
+	bgCtx := r.cfg.AmbientCtx.AnnotateCtx(context.Background())
+	if err := r.cfg.Stopper.RunAsyncTask(bgCtx, "notifications-cleanup", r.runCleanup); err != nil {
+		// The server is shutting down.
+		log.VEventf(ctx, 2, "not starting notifications cleanup: %v", err)
+		return
+	}
+	r.mu.cleanupStarted = true
+}
+
+// runCleanup periodically deletes the notifications that are older than
+// sql.notifications.retention.
+func (r *NotificationRegistry) runCleanup(ctx context.Context) {
+	ctx, cancel := r.cfg.Stopper.WithCancelOnQuiesce(ctx)
+	defer cancel()
+
+	var t timeutil.Timer
+	defer t.Stop()
+	for {
+		t.Reset(notificationCleanupInterval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			t.Read = true
+		}
+		cutoff := timeutil.Now().Add(-notificationRetention.Get(&r.cfg.Settings.SV))
+		for {
+			n, err := r.cfg.InternalDB.Executor().ExecEx(
+				ctx, "delete-expired-notifications", nil /* txn */, sessiondata.NodeUserSessionDataOverride,
+				`DELETE FROM system.notifications WHERE created < $1 LIMIT $2`,
+				cutoff, notificationCleanupBatchSize,
+			)
+			if err != nil {
+				log.Warningf(ctx, "failed to delete expired notifications: %v", err)
+				break
+			}
+			if n < notificationCleanupBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// onValue is called by the rangefeed for every write to system.notifications.
+func (r *NotificationRegistry) onValue(ctx context.Context, kv *kvpb.RangeFeedValue) {
+	// Deletions of expired notifications have no value.
+	if !kv.Value.IsPresent() {
+		return
+	}
+	ts := kv.Value.Timestamp
+	r.mu.Lock()
+	if ts.LessEq(r.mu.frontier) {
+		r.mu.Unlock()
+		return
+	}
+	if _, ok := r.mu.delivered[string(kv.Key)]; ok {
+		r.mu.Unlock()
+		return
+	}
+	r.mu.delivered[string(kv.Key)] = ts
+	listeners := make([]*notificationListener, 0, len(r.mu.listeners))
+	for l := range r.mu.listeners {
+		listeners = append(listeners, l)
+	}
+	r.mu.Unlock()
+
+	if len(listeners) == 0 {
+		return
+	}
+	notifications, err := r.decodeNotifications(kv.Value)
+	if err != nil {
+		log.Warningf(ctx, "failed to decode notifications %v: %v", kv.Key, err)
+		return
+	}
+	for _, l := range listeners {
+		l.deliver(ts, notifications)
+	}
+}
+
+// onFrontierAdvance forgets the delivered rows that can no longer be emitted
+// by the rangefeed.
+func (r *NotificationRegistry) onFrontierAdvance(_ context.Context, frontier hlc.Timestamp) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.frontier.Forward(frontier)
+	for k, ts := range r.mu.delivered {
+		if ts.LessEq(r.mu.frontier) {
+			delete(r.mu.delivered, k)
+		}
+	}
+}
+
+// Ordinals of the columns of system.notifications.
+const (
+	notificationsPIDColumnOrdinal      = 2
+	notificationsChannelsColumnOrdinal = 3
+	notificationsPayloadsColumnOrdinal = 4
+)
+
+// decodeNotifications decodes the notifications stored in a row of
+// system.notifications.
+func (r *NotificationRegistry) decodeNotifications(value roachpb.Value) ([]Notification, error) {
+	bytes, err := value.GetTuple()
+	if err != nil {
+		return nil, err
+	}
+	datums, err := r.decoder.Decode(&tree.DatumAlloc{}, bytes)
+	if err != nil {
+		return nil, err
+	}
+	pid, ok := datums[notificationsPIDColumnOrdinal].(*tree.DInt)
+	if !ok {
+		return nil, errors.AssertionFailedf("unexpected pid %v", datums[notificationsPIDColumnOrdinal])
+	}
+	channels, ok := datums[notificationsChannelsColumnOrdinal].(*tree.DArray)
+	if !ok {
+		return nil, errors.AssertionFailedf("unexpected channels %v", datums[notificationsChannelsColumnOrdinal])
+	}
+	payloads, ok := datums[notificationsPayloadsColumnOrdinal].(*tree.DArray)
+	if !ok || payloads.Len() != channels.Len() {
+		return nil, errors.AssertionFailedf("unexpected payloads %v", datums[notificationsPayloadsColumnOrdinal])
+	}
+	notifications := make([]Notification, channels.Len())
+	for i := range notifications {
+		notifications[i] = Notification{
+			PID:     uint32(*pid),
+			Channel: string(tree.MustBeDString(channels.Array[i])),
+			Payload: string(tree.MustBeDString(payloads.Array[i])),
+		}
+	}
+	return notifications, nil
+}
+
+// register adds a listener to the registry.
+func (r *NotificationRegistry) register(l *notificationListener) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.listeners[l] = struct{}{}
+}
+
+// unregister removes a listener from the registry.
+func (r *NotificationRegistry) unregister(l *notificationListener) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.mu.listeners, l)
+}
+
+// notificationListener receives the notifications for the channels that a
+// session listens on. Notifications are queued until the session is idle,
+// at which point the connExecutor sends them to the client.
+type notificationListener struct {
+	registry *NotificationRegistry
+	// wake is called when notifications are queued and the connExecutor
+	// hasn't been woken up already. It must not block.
+	wake func()
+
+	mu struct {
+		syncutil.Mutex
+		// channels maps the channels the session listens on to the commit
+		// timestamp of the transaction that started listening. Notifications
+		// committed at or before that timestamp are not delivered.
+		channels map[string]hlc.Timestamp
+		queue    []Notification
+		// wakePending is set when wake has been called and the connExecutor
+		// has not taken the queued notifications since.
+		wakePending bool
+	}
+}
+
+func newNotificationListener(registry *NotificationRegistry, wake func()) *notificationListener {
+	l := &notificationListener{registry: registry, wake: wake}
+	l.mu.channels = make(map[string]hlc.Timestamp)
+	return l
+}
+
+// apply applies the LISTEN and UNLISTEN operations of a transaction that
+// committed at the given timestamp.
+func (l *notificationListener) apply(ops []listenOp, commitTS hlc.Timestamp) {
+	// Notifications committed before the rangefeed started were never seen by
+	// the registry.
+	since := commitTS
+	since.Forward(l.registry.startTimestamp())
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, op := range ops {
+		switch {
+		case op.listen:
+			if _, ok := l.mu.channels[op.channel]; !ok {
+				l.mu.channels[op.channel] = since
+			}
+		case op.channel == "":
+			l.mu.channels = make(map[string]hlc.Timestamp)
+		default:
+			delete(l.mu.channels, op.channel)
+		}
+	}
+}
+
+// deliver queues the notifications, committed at the given timestamp, that
+// are sent on a channel the session listens on.
+func (l *notificationListener) deliver(ts hlc.Timestamp, notifications []Notification) {
+	var wake bool
+	func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for _, n := range notifications {
+			if since, ok := l.mu.channels[n.Channel]; ok && since.Less(ts) {
+				l.mu.queue = append(l.mu.queue, n)
+			}
+		}
+		if len(l.mu.queue) > 0 && !l.mu.wakePending {
+			l.mu.wakePending = true
+			wake = true
+		}
+	}()
+	if wake {
+		l.wake()
+	}
+}
+
+// take returns and clears the queued notifications.
+func (l *notificationListener) take() []Notification {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.mu.wakePending = false
+	q := l.mu.queue
+	l.mu.queue = nil
+	return q
+}
+
+// rewake calls wake if there are queued notifications. It is used when the
+// session becomes idle, since the notifications that arrived while it was
+// busy were left in the queue.
+func (l *notificationListener) rewake() {
+	l.mu.Lock()
+	wake := len(l.mu.queue) > 0
+	l.mu.wakePending = l.mu.wakePending || wake
+	l.mu.Unlock()
+	if wake {
+		l.wake()
+	}
+}
+
+// applyListenOps applies the LISTEN and UNLISTEN operations of the transaction
+// that just committed at the given timestamp, creating the session's listener
+// if needed.
+func (ex *connExecutor) applyListenOps(commitTS hlc.Timestamp) {
+	ops := ex.extraTxnState.notifications.listenOps
+	if len(ops) == 0 {
+		return
+	}
+	if ex.notificationListener == nil {
+		hasListen := false
+		for _, op := range ops {
+			hasListen = hasListen || op.listen
+		}
+		if !hasListen {
+			return
+		}
+		registry := ex.server.cfg.NotificationRegistry
+		connCtx, stmtBuf := ex.ctxHolder.connCtx, ex.stmtBuf
+		ex.notificationListener = newNotificationListener(registry, func() {
+			// The error is only returned if the session is closing.
+			_ = stmtBuf.Push(connCtx, SendNotifications{})
+		})
+		registry.register(ex.notificationListener)
+	}
+	ex.notificationListener.apply(ops, commitTS)
+}
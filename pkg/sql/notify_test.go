@@ -0,0 +1,116 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// TestListenNotify checks that notifications sent on one node are delivered
+// to the sessions listening on another node once the sending transaction
+// commits.
+func TestListenNotify(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := testcluster.StartTestCluster(t, 2 /* nodes */, base.TestClusterArgs{})
+	defer tc.Stopper().Stop(ctx)
+
+	pgURL, cleanup := sqlutils.PGUrl(t, tc.Server(0).AdvSQLAddr(), t.Name(), url.User("root"))
+	defer cleanup()
+	listener, err := pgx.Connect(ctx, pgURL.String())
+	require.NoError(t, err)
+	defer func() { _ = listener.Close(ctx) }()
+
+	sender := sqlutils.MakeSQLRunner(tc.ServerConn(1))
+	var senderPID int64
+	sender.QueryRow(t, `SELECT pg_backend_pid()`).Scan(&senderPID)
+
+	_, err = listener.Exec(ctx, `LISTEN foo`)
+	require.NoError(t, err)
+
+	expect := func(t *testing.T, channel, payload string) {
+		t.Helper()
+		waitCtx, cancel := context.WithTimeout(ctx, 45*time.Second)
+		defer cancel()
+		n, err := listener.WaitForNotification(waitCtx)
+		require.NoError(t, err)
+		require.Equal(t, channel, n.Channel)
+		require.Equal(t, payload, n.Payload)
+	}
+
+	t.Run("commit", func(t *testing.T) {
+		sender.Exec(t, `NOTIFY foo, 'a'`)
+		sender.Exec(t, `BEGIN; NOTIFY foo, 'b'; ROLLBACK`)
+		sender.Exec(t, `NOTIFY bar, 'c'`)
+		sender.Exec(t, `SELECT pg_notify('foo', 'd')`)
+
+		waitCtx, cancel := context.WithTimeout(ctx, 45*time.Second)
+		defer cancel()
+		n, err := listener.WaitForNotification(waitCtx)
+		require.NoError(t, err)
+		require.Equal(t, uint32(senderPID), n.PID)
+		require.Equal(t, "foo", n.Channel)
+		require.Equal(t, "a", n.Payload)
+		expect(t, "foo", "d")
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		// Duplicate notifications are sent once, and the notifications of a
+		// transaction are delivered in order.
+		sender.Exec(t, `BEGIN; NOTIFY foo, 'e'; NOTIFY foo, 'f'; NOTIFY foo, 'e'; COMMIT`)
+		expect(t, "foo", "e")
+		expect(t, "foo", "f")
+
+		// Notifications sent after a savepoint are discarded when rolling back
+		// to it.
+		sender.Exec(t, `BEGIN; SAVEPOINT s; NOTIFY foo, 'g'; ROLLBACK TO SAVEPOINT s; NOTIFY foo, 'h'; COMMIT`)
+		expect(t, "foo", "h")
+	})
+
+	t.Run("auto commit", func(t *testing.T) {
+		// Mutations that would otherwise commit their implicit transaction in
+		// one phase write their notifications in the same transaction.
+		sender.Exec(t, `CREATE TABLE notify_t (k INT PRIMARY KEY, v STRING)`)
+		sender.Exec(t, `INSERT INTO notify_t SELECT 1, 'x' WHERE pg_notify('foo', 'm') IS NOT NULL`)
+		expect(t, "foo", "m")
+		sender.Exec(t, `SELECT pg_notify('foo', 'n'); DELETE FROM notify_t WHERE k = 1`)
+		expect(t, "foo", "n")
+		sender.CheckQueryResults(t, `SELECT count(*) FROM notify_t`, [][]string{{"0"}})
+	})
+
+	t.Run("unlisten", func(t *testing.T) {
+		_, err := listener.Exec(ctx, `UNLISTEN foo; LISTEN bar`)
+		require.NoError(t, err)
+		sender.Exec(t, `NOTIFY foo, 'i'`)
+		sender.Exec(t, `NOTIFY bar, 'j'`)
+		expect(t, "bar", "j")
+
+		// LISTEN only takes effect when the transaction commits.
+		_, err = listener.Exec(ctx, `BEGIN; LISTEN foo; ROLLBACK`)
+		require.NoError(t, err)
+		sender.Exec(t, `NOTIFY foo, 'k'`)
+		sender.Exec(t, `NOTIFY bar, 'l'`)
+		expect(t, "bar", "l")
+	})
+}
@@ -13,6 +13,7 @@ package cat
 import (
 	"github.com/cockroachdb/cockroach/pkg/geo/geopb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 )
@@ -194,6 +195,10 @@ type Index interface {
 	// describes the configuration for this geospatial inverted index.
 	GeoConfig() geopb.Config
 
+	// VecConfig returns a vector index configuration. If not empty, it
+	// describes the configuration for this vector inverted index.
+	VecConfig() catpb.VectorIndexConfig
+
 	// Version returns the IndexDescriptorVersion of the index.
 	Version() descpb.IndexDescriptorVersion
 
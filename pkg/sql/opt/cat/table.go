@@ -15,6 +15,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
 
@@ -139,6 +140,14 @@ type Table interface {
 	// i < UniqueCount.
 	Unique(i UniqueOrdinal) UniqueConstraint
 
+	// ExclusionConstraintCount returns the number of EXCLUDE constraints defined
+	// on this table.
+	ExclusionConstraintCount() int
+
+	// ExclusionConstraint returns the ith EXCLUDE constraint defined on this
+	// table, where i < ExclusionConstraintCount.
+	ExclusionConstraint(i int) ExclusionConstraint
+
 	// Zone returns a table's zone.
 	Zone() Zone
 
@@ -180,6 +189,14 @@ type Table interface {
 	// IsHypothetical returns true if this is a hypothetical table (used when
 	// searching for index recommendations).
 	IsHypothetical() bool
+
+	// TriggerCount returns the number of triggers present on the table.
+	TriggerCount() int
+
+	// Trigger returns the ith trigger, where i < TriggerCount. Triggers are
+	// returned in the order in which they should fire, which is alphabetical
+	// order by name, as in Postgres.
+	Trigger(i int) Trigger
 }
 
 // CheckConstraint represents a check constraint on a table. Check constraints
@@ -233,9 +250,9 @@ type TableStatistic interface {
 	AvgSize() uint64
 
 	// Histogram returns a slice of histogram buckets, sorted by UpperBound.
-	// It is only used for single-column stats (i.e., when ColumnCount() = 1),
-	// and it represents the distribution of values for that column.
-	// See HistogramBucket for more details.
+	// It represents the distribution of values for the column of a
+	// single-column statistic, or of tuples of the values of the columns of a
+	// multi-column statistic. See HistogramBucket for more details.
 	Histogram() []HistogramBucket
 
 	// HistogramType returns the type that the histogram was created on. For
@@ -257,6 +274,37 @@ type TableStatistic interface {
 
 	// IsAuto returns true if this statistic was collected automatically.
 	IsAuto() bool
+
+	// MostCommonValues returns the most common tuples of the values of the
+	// columns of a multi-column statistic, sorted by Value. Like the histogram,
+	// they exclude the rows in which all of the columns are NULL.
+	MostCommonValues() []MostCommonValue
+
+	// Dependencies returns the functional dependencies between the columns of
+	// a multi-column statistic.
+	Dependencies() []ColumnDependency
+}
+
+// MostCommonValue is one of the most common values of the columns of a
+// statistic.
+type MostCommonValue struct {
+	// NumEq is the estimated number of rows equal to Value.
+	NumEq float64
+
+	// Value is a tuple with the values of the columns of the statistic.
+	Value tree.Datum
+}
+
+// ColumnDependency is a functional dependency between two columns of a
+// statistic, which holds for a fraction of the rows.
+type ColumnDependency struct {
+	// From and To are the indexes of the determining and of the dependent
+	// column in the columns of the statistic (see TableStatistic.ColumnOrdinal).
+	From, To int
+
+	// Degree is the estimated fraction of rows, between 0 and 1, in which the
+	// value of column From determines the value of column To.
+	Degree float64
 }
 
 // HistogramBucket contains the data for a single histogram bucket. Note
@@ -328,6 +376,12 @@ type ForeignKeyConstraint interface {
 	// UpdateReferenceAction returns the action to be performed if the foreign key
 	// constraint would be violated by an update.
 	UpdateReferenceAction() tree.ReferenceAction
+
+	// Deferrability returns whether violations of the constraint can be
+	// tolerated until the end of the transaction. A deferrable constraint is
+	// never reported as Validated, since its invariant may not hold mid
+	// transaction.
+	Deferrability() tree.ConstraintDeferrability
 }
 
 // UniqueConstraint represents a uniqueness constraint. UniqueConstraints may
@@ -375,6 +429,43 @@ type UniqueConstraint interface {
 	// satisfied when building functional dependencies for the table. This enables
 	// additional optimizations, such as omission of uniqueness checks.
 	UniquenessGuaranteedByAnotherIndex() bool
+
+	// Deferrability returns whether violations of the constraint can be
+	// tolerated until the end of the transaction. Only constraints that are
+	// enforced with uniqueness checks (WithoutIndex is true) can be deferrable,
+	// and a deferrable constraint is never reported as Validated.
+	Deferrability() tree.ConstraintDeferrability
+}
+
+// ExclusionConstraint represents an EXCLUDE constraint, which guarantees that
+// no two rows of a table compare true on every one of the constraint's
+// elements. For example, the following constraint ensures that no two
+// bookings of the same room have overlapping areas:
+//
+//	CREATE TABLE b (room INT, area GEOMETRY, EXCLUDE USING GIST (room WITH =, area WITH &&))
+//
+// Like unique constraints without an index, exclusion constraints are enforced
+// by the optimizer with a check added as a postquery to any query that inserts
+// into or updates the constrained columns.
+type ExclusionConstraint interface {
+	// Name of the exclusion constraint.
+	Name() string
+
+	// ColumnCount returns the number of elements in this constraint.
+	ColumnCount() int
+
+	// ColumnOrdinal returns the table column ordinal of the column of the ith
+	// element in this constraint.
+	ColumnOrdinal(tab Table, i int) int
+
+	// Operator returns the commutative comparison operator of the ith element
+	// in this constraint.
+	Operator(i int) treecmp.ComparisonOperator
+
+	// Predicate returns the partial predicate expression and true if the
+	// constraint only applies to the rows that satisfy the predicate. If it
+	// does not, the empty string and false are returned.
+	Predicate() (string, bool)
 }
 
 // UniqueOrdinal identifies a unique constraint (in the context of a Table).
@@ -0,0 +1,66 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cat
+
+import "github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+
+// Trigger is an interface to a trigger on a table or view. A trigger executes
+// a PL/pgSQL function when a mutation event occurs on the table. For example:
+//
+//	CREATE TRIGGER tr BEFORE INSERT ON xy FOR EACH ROW EXECUTE FUNCTION f();
+type Trigger interface {
+	// Name is the name of the trigger. It is unique within a given table, and
+	// cannot be qualified.
+	Name() tree.Name
+
+	// ActionTime defines whether the trigger should be fired before, after, or
+	// instead of the triggering event.
+	ActionTime() tree.TriggerActionTime
+
+	// EventCount returns the number of events that the trigger is configured
+	// to fire on.
+	EventCount() int
+
+	// Event returns the ith event that the trigger is configured to fire on,
+	// where i < EventCount.
+	Event(i int) tree.TriggerEvent
+
+	// ForEachRow indicates whether the trigger should be fired for each row
+	// affected by the triggering event. If false, the trigger is fired once per
+	// statement.
+	ForEachRow() bool
+
+	// WhenExpr is the optional filter expression that determines whether the
+	// trigger should be fired. If empty, the trigger is always fired.
+	WhenExpr() string
+
+	// FuncID is the ID of the function that will be called when the trigger
+	// fires.
+	FuncID() StableID
+
+	// FuncArgs is a list of constant string arguments for the trigger function.
+	// They are made available to the function through TG_ARGV.
+	FuncArgs() []string
+
+	// Enabled is true if the trigger is currently enabled.
+	Enabled() bool
+}
+
+// HasTriggerEvent returns true if the given trigger fires on the given event
+// type.
+func HasTriggerEvent(trigger Trigger, eventType tree.TriggerEventType) bool {
+	for i := 0; i < trigger.EventCount(); i++ {
+		if trigger.Event(i).EventType == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -15,6 +15,7 @@ import (
 	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
@@ -24,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/util/intsets"
 	"github.com/cockroachdb/errors"
 )
@@ -135,6 +137,12 @@ func (b *Builder) buildInsert(ins *memo.InsertExpr) (_ execPlan, outputCols colO
 		return execPlan{}, colOrdMap{}, err
 	}
 
+	// Inserts do not cause foreign key cascades, but they can cause AFTER
+	// triggers to be fired, which are planned in the same way.
+	if err := b.buildFKCascades(ins.WithID, ins.FKCascades); err != nil {
+		return execPlan{}, colOrdMap{}, err
+	}
+
 	return ep, outputCols, nil
 }
 
@@ -153,6 +161,10 @@ func (b *Builder) tryBuildFastPathInsert(
 	if !b.allowInsertFastPath {
 		return execPlan{}, colOrdMap{}, false, nil
 	}
+	// AFTER triggers require the buffered input of the insert.
+	if len(ins.FKCascades) > 0 {
+		return execPlan{}, colOrdMap{}, false, nil
+	}
 	// If there are unique checks required, there must be the same number of fast
 	// path unique checks.
 	if len(ins.UniqueChecks) != len(ins.FastPathUniqueChecks) {
@@ -173,6 +185,11 @@ func (b *Builder) tryBuildFastPathInsert(
 	uniqChecks := make([]exec.InsertFastPathCheck, len(ins.UniqueChecks))
 	for i := range ins.FastPathUniqueChecks {
 		c := &ins.FastPathUniqueChecks[i]
+		if tab.Unique(ins.UniqueChecks[i].CheckOrdinal).Deferrability().IsDeferrable() {
+			// Violations of deferrable constraints must be detected by postquery
+			// checks, which can tolerate them until the end of the transaction.
+			return execPlan{}, colOrdMap{}, false, nil
+		}
 		if len(c.DatumsFromConstraint) == 0 {
 			// We need at least one DatumsFromConstraint in order to perform
 			// uniqueness checks during fast-path insert. Even if DatumsFromConstraint
@@ -243,6 +260,10 @@ func (b *Builder) tryBuildFastPathInsert(
 			return execPlan{}, colOrdMap{}, false, nil
 		}
 		fk := tab.OutboundForeignKey(c.FKOrdinal)
+		if fk.Deferrability().IsDeferrable() {
+			// See the comment for deferrable unique constraints above.
+			return execPlan{}, colOrdMap{}, false, nil
+		}
 		lookupJoin, isLookupJoin := c.Check.(*memo.LookupJoinExpr)
 		if !isLookupJoin || lookupJoin.JoinType != opt.AntiJoinOp {
 			// Not a lookup anti-join.
@@ -816,6 +837,9 @@ func (b *Builder) buildUniqueChecks(checks memo.UniqueChecksExpr) error {
 				}
 				keyVals[i] = row[ord]
 			}
+			if c.Exclusion {
+				return mkExclusionCheckErr(md, c, keyVals)
+			}
 			return mkUniqueCheckErr(md, c, keyVals)
 		}
 		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
@@ -892,13 +916,60 @@ func mkUniqueCheckErr(md *opt.Metadata, c *memo.UniqueChecksItem, keyVals tree.D
 
 	details.WriteString(") already exists.")
 
-	return errors.WithDetail(
+	err := errors.WithDetail(
 		pgerror.WithConstraintName(
 			pgerror.Newf(pgcode.UniqueViolation, "%s", msg.String()),
 			constraintName,
 		),
 		details.String(),
 	)
+	if d := uc.Deferrability(); d.IsDeferrable() {
+		err = sqlerrors.WithDeferrableConstraintViolation(
+			err, descpb.ID(uc.TableID()), constraintName, d, keyVals,
+		)
+	}
+	return err
+}
+
+// mkExclusionCheckErr generates a user-friendly error describing an exclusion
+// constraint violation. The keyVals are the values that correspond to the
+// cat.ExclusionConstraint columns.
+func mkExclusionCheckErr(md *opt.Metadata, c *memo.UniqueChecksItem, keyVals tree.Datums) error {
+	tabMeta := md.TableMeta(c.Table)
+	ec := tabMeta.Table.ExclusionConstraint(c.CheckOrdinal)
+	constraintName := ec.Name()
+	var msg, details bytes.Buffer
+
+	// Generate an error of the form:
+	//   ERROR:  conflicting key value violates exclusion constraint "foo"
+	//   DETAIL: Key (k, g)=(2, 'POINT (1 1)') conflicts with existing key.
+	msg.WriteString("conflicting key value violates exclusion constraint ")
+	lexbase.EncodeEscapedSQLIdent(&msg, constraintName)
+
+	details.WriteString("Key (")
+	for i := 0; i < ec.ColumnCount(); i++ {
+		if i > 0 {
+			details.WriteString(", ")
+		}
+		col := tabMeta.Table.Column(ec.ColumnOrdinal(tabMeta.Table, i))
+		details.WriteString(string(col.ColName()))
+	}
+	details.WriteString(")=(")
+	for i, d := range keyVals {
+		if i > 0 {
+			details.WriteString(", ")
+		}
+		details.WriteString(d.String())
+	}
+	details.WriteString(") conflicts with existing key.")
+
+	return errors.WithDetail(
+		pgerror.WithConstraintName(
+			pgerror.Newf(pgcode.ExclusionViolation, "%s", msg.String()),
+			constraintName,
+		),
+		details.String(),
+	)
 }
 
 // mkUniqueCheckErrWithoutColNames is a simpler version of mkUniqueCheckErr that
@@ -982,11 +1053,12 @@ func mkFKCheckErr(md *opt.Metadata, c *memo.FKChecksItem, keyVals tree.Datums) e
 
 	var msg, details bytes.Buffer
 	var constraintName string
+	var fk cat.ForeignKeyConstraint
 	if c.FKOutbound {
 		// Generate an error of the form:
 		//   ERROR:  insert on table "child" violates foreign key constraint "foo"
 		//   DETAIL: Key (child_p)=(2) is not present in table "parent".
-		fk := origin.Table.OutboundForeignKey(c.FKOrdinal)
+		fk = origin.Table.OutboundForeignKey(c.FKOrdinal)
 		constraintName = fk.Name()
 		fmt.Fprintf(&msg, "%s on table ", c.OpName)
 		lexbase.EncodeEscapedSQLIdent(&msg, string(origin.Alias.ObjectName))
@@ -1028,7 +1100,7 @@ func mkFKCheckErr(md *opt.Metadata, c *memo.FKChecksItem, keyVals tree.Datums) e
 		//   ERROR:  delete on table "parent" violates foreign key constraint
 		//           "child_child_p_fkey" on table "child"
 		//   DETAIL: Key (p)=(1) is still referenced from table "child".
-		fk := referenced.Table.InboundForeignKey(c.FKOrdinal)
+		fk = referenced.Table.InboundForeignKey(c.FKOrdinal)
 		constraintName = fk.Name()
 		fmt.Fprintf(&msg, "%s on table ", c.OpName)
 		lexbase.EncodeEscapedSQLIdent(&msg, string(referenced.Alias.ObjectName))
@@ -1057,13 +1129,19 @@ func mkFKCheckErr(md *opt.Metadata, c *memo.FKChecksItem, keyVals tree.Datums) e
 		details.WriteByte('.')
 	}
 
-	return errors.WithDetail(
+	err := errors.WithDetail(
 		pgerror.WithConstraintName(
 			pgerror.Newf(pgcode.ForeignKeyViolation, "%s", msg.String()),
 			constraintName,
 		),
 		details.String(),
 	)
+	if d := fk.Deferrability(); d.IsDeferrable() {
+		err = sqlerrors.WithDeferrableConstraintViolation(
+			err, descpb.ID(fk.OriginTableID()), constraintName, d, keyVals,
+		)
+	}
+	return err
 }
 
 func (b *Builder) buildFKCascades(withID opt.WithID, cascades memo.FKCascades) error {
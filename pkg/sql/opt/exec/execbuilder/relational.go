@@ -285,6 +285,9 @@ func (b *Builder) buildRelational(e memo.RelExpr) (_ execPlan, outputCols colOrd
 	case *memo.CreateFunctionExpr:
 		ep, outputCols, err = b.buildCreateFunction(t)
 
+	case *memo.CreateTriggerExpr:
+		ep, outputCols, err = b.buildCreateTrigger(t)
+
 	case *memo.WithExpr:
 		ep, outputCols, err = b.buildWith(t)
 
@@ -1660,7 +1663,25 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (_ execPlan, outputCols col
 			agg = aggDistinct.Input
 		}
 
-		name, overload := memo.FindAggregateOverload(agg)
+		var name string
+		var distsqlBlocklist bool
+		var userDefined *exec.UserDefinedAggInfo
+		if uda, ok := agg.(*memo.UserDefinedAggExpr); ok {
+			name = uda.Def.Name
+			if userDefined, err = b.buildUserDefinedAgg(uda); err != nil {
+				return execPlan{}, colOrdMap{}, err
+			}
+			private := groupBy.Private().(*memo.GroupingPrivate)
+			if !private.Ordering.ColSet().SubsetOf(private.GroupingCols) {
+				// The aggregate depends on the order of the rows in each group,
+				// which is not preserved when partial states are combined.
+				userDefined.Combine = nil
+			}
+		} else {
+			var overload *tree.Overload
+			name, overload = memo.FindAggregateOverload(agg)
+			distsqlBlocklist = overload.DistsqlBlocklist
+		}
 
 		// Accumulate variable arguments in argCols and constant arguments in
 		// constArgs. Constant arguments must follow variable arguments.
@@ -1694,7 +1715,8 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (_ execPlan, outputCols col
 			ArgCols:          argCols[:len(argCols):len(argCols)],
 			ConstArgs:        constArgs[:len(constArgs):len(constArgs)],
 			Filter:           filterOrd,
-			DistsqlBlocklist: overload.DistsqlBlocklist,
+			UserDefined:      userDefined,
+			DistsqlBlocklist: distsqlBlocklist,
 		}
 		outputCols.Set(item.Col, len(groupingColIdx)+i)
 		// Slice argCols and constArgs so the rest of their capacity can be
@@ -1735,6 +1757,48 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (_ execPlan, outputCols col
 	return ep, outputCols, nil
 }
 
+// buildUserDefinedAgg builds the support expressions of a user-defined
+// aggregate. They reference the state of the aggregate as @1 and either its
+// arguments or the partial state to combine with as @2, @3, etc.
+func (b *Builder) buildUserDefinedAgg(
+	agg *memo.UserDefinedAggExpr,
+) (_ *exec.UserDefinedAggInfo, err error) {
+	def := agg.Def
+	md := b.mem.Metadata()
+	info := &exec.UserDefinedAggInfo{
+		ResultType:       def.Typ,
+		StateType:        def.StateType,
+		ArgTypes:         make([]*types.T, len(def.ArgCols)),
+		StrictTransition: def.StrictTransition,
+		StrictCombine:    def.StrictCombine,
+	}
+	colMap := b.colOrdsAlloc.Alloc()
+	defer b.colOrdsAlloc.Free(colMap)
+	if info.InitialState, err = b.buildScalarWithMap(colMap, def.InitialState); err != nil {
+		return nil, err
+	}
+	colMap.Set(def.StateCol, 0)
+	if def.Final != nil {
+		if info.Final, err = b.buildScalarWithMap(colMap, def.Final); err != nil {
+			return nil, err
+		}
+	}
+	if def.Combine != nil {
+		colMap.Set(def.OtherStateCol, 1)
+		if info.Combine, err = b.buildScalarWithMap(colMap, def.Combine); err != nil {
+			return nil, err
+		}
+	}
+	for i, col := range def.ArgCols {
+		info.ArgTypes[i] = md.ColumnMeta(col).Type
+		colMap.Set(col, i+1)
+	}
+	if info.Transition, err = b.buildScalarWithMap(colMap, def.Transition); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
 func (b *Builder) buildDistinct(
 	distinct memo.RelExpr,
 ) (_ execPlan, outputCols colOrdMap, err error) {
@@ -3609,15 +3673,36 @@ func (b *Builder) buildWindow(w *memo.WindowExpr) (_ execPlan, outputCols colOrd
 	filterIdxs := make([]int, len(w.Windows))
 	exprs := make([]*tree.FuncExpr, len(w.Windows))
 	windowVals := make([]tree.WindowDef, len(w.Windows))
+	userDefinedAggs := make([]*exec.UserDefinedAggInfo, len(w.Windows))
 
 	for i := range w.Windows {
 		item := &w.Windows[i]
 		fn := b.extractWindowFunction(item.Function)
-		name, overload := memo.FindWindowOverload(fn)
-		if !b.disableTelemetry {
-			telemetry.Inc(sqltelemetry.WindowFunctionCounter(name))
+		var wrappedFn tree.ResolvableFunctionReference
+		var typ *types.T
+		var props *tree.FunctionProperties
+		var overload *tree.Overload
+		if uda, ok := fn.(*memo.UserDefinedAggExpr); ok {
+			if userDefinedAggs[i], err = b.buildUserDefinedAgg(uda); err != nil {
+				return execPlan{}, colOrdMap{}, err
+			}
+			wrappedFn = tree.ResolvableFunctionReference{
+				FunctionReference: &tree.ResolvedFunctionDefinition{Name: uda.Def.Name},
+			}
+			typ = uda.Def.Typ
+			props = &tree.FunctionProperties{}
+		} else {
+			var name string
+			name, overload = memo.FindWindowOverload(fn)
+			if !b.disableTelemetry {
+				telemetry.Inc(sqltelemetry.WindowFunctionCounter(name))
+			}
+			props, _ = builtinsregistry.GetBuiltinProperties(name)
+			if wrappedFn, err = b.wrapBuiltinFunction(name); err != nil {
+				return execPlan{}, colOrdMap{}, err
+			}
+			typ = overload.FixedReturnType()
 		}
-		props, _ := builtinsregistry.GetBuiltinProperties(name)
 
 		args := make([]tree.TypedExpr, fn.ChildCount())
 		argIdxs[i] = make([]exec.NodeColumnOrdinal, fn.ChildCount())
@@ -3660,17 +3745,13 @@ func (b *Builder) buildWindow(w *memo.WindowExpr) (_ execPlan, outputCols colOrd
 			OrderBy:    orderingExprs,
 			Frame:      frame,
 		}
-		wrappedFn, err := b.wrapBuiltinFunction(name)
-		if err != nil {
-			return execPlan{}, colOrdMap{}, err
-		}
 		exprs[i] = tree.NewTypedFuncExpr(
 			wrappedFn,
 			0,
 			args,
 			builtFilter,
 			&windowVals[i],
-			overload.FixedReturnType(),
+			typ,
 			props,
 			overload,
 		)
@@ -3709,13 +3790,14 @@ func (b *Builder) buildWindow(w *memo.WindowExpr) (_ execPlan, outputCols colOrd
 	}
 	var ep execPlan
 	ep.root, err = b.factory.ConstructWindow(input.root, exec.WindowInfo{
-		Cols:       resultCols,
-		Exprs:      exprs,
-		OutputIdxs: outputIdxs,
-		ArgIdxs:    argIdxs,
-		FilterIdxs: filterIdxs,
-		Partition:  partitionIdxs,
-		Ordering:   sqlOrdering,
+		Cols:            resultCols,
+		Exprs:           exprs,
+		OutputIdxs:      outputIdxs,
+		ArgIdxs:         argIdxs,
+		FilterIdxs:      filterIdxs,
+		UserDefinedAggs: userDefinedAggs,
+		Partition:       partitionIdxs,
+		Ordering:        sqlOrdering,
 	})
 	if err != nil {
 		return execPlan{}, colOrdMap{}, err
@@ -89,6 +89,18 @@ func (b *Builder) buildCreateFunction(
 	return execPlan{root: root}, colOrdMap{}, err
 }
 
+func (b *Builder) buildCreateTrigger(
+	ct *memo.CreateTriggerExpr,
+) (_ execPlan, outputCols colOrdMap, err error) {
+	root, err := b.factory.ConstructCreateTrigger(
+		ct.Syntax,
+		ct.Deps,
+		ct.TypeDeps,
+		ct.FuncDeps,
+	)
+	return execPlan{root: root}, colOrdMap{}, err
+}
+
 func (b *Builder) buildExplainOpt(
 	explain *memo.ExplainExpr,
 ) (_ execPlan, outputCols colOrdMap, err error) {
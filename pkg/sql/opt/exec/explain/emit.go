@@ -121,8 +121,20 @@ func emitInternal(
 	}
 
 	for _, cascade := range plan.Cascades {
-		ob.EnterMetaNode("fk-cascade")
-		ob.Attr("fk", cascade.FKConstraint.Name())
+		var fkID string
+		if trigger := cascade.Trigger; trigger != nil {
+			ob.EnterMetaNode("after-trigger")
+			ob.Attr("trigger", string(trigger.Name()))
+			// A trigger can cause itself to fire again, so we use its name as
+			// the "id" to prevent infinite recursion.
+			fkID = fmt.Sprintf("trigger %s", trigger.Name())
+		} else {
+			ob.EnterMetaNode("fk-cascade")
+			ob.Attr("fk", cascade.FKConstraint.Name())
+			fk := cascade.FKConstraint
+			// Come up with a custom "id" for this FK.
+			fkID = fmt.Sprintf("%d%s", fk.OriginTableID(), fk.Name())
+		}
 		// Here we do want to allow creation of the plans for the cascades to be
 		// able to include them into the EXPLAIN output.
 		const createPlanIfMissing = true
@@ -132,9 +144,6 @@ func emitInternal(
 			if visitedFKsByCascades == nil {
 				visitedFKsByCascades = make(map[string]struct{})
 			}
-			fk := cascade.FKConstraint
-			// Come up with a custom "id" for this FK.
-			fkID := fmt.Sprintf("%d%s", fk.OriginTableID(), fk.Name())
 			if _, visited := visitedFKsByCascades[fkID]; visited {
 				// If we have already visited this particular FK cascade, we
 				// don't recurse into it again to prevent infinite recursion.
@@ -340,6 +349,7 @@ var nodeNames = [...]string{
 	createFunctionOp:       "create function",
 	createTableOp:          "create table",
 	createTableAsOp:        "create table as",
+	createTriggerOp:        "create trigger",
 	createViewOp:           "create view",
 	deleteOp:               "delete",
 	deleteRangeOp:          "delete range",
@@ -1040,6 +1050,7 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 		createFunctionOp,
 		createTableOp,
 		createTableAsOp,
+		createTriggerOp,
 		createViewOp,
 		sequenceSelectOp,
 		saveTableOp,
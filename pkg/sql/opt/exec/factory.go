@@ -213,11 +213,42 @@ type AggInfo struct {
 	// FILTER condition for the aggregate. If there is no filter, Filter is -1.
 	Filter NodeColumnOrdinal
 
+	// UserDefined is set if this is an aggregate created with CREATE
+	// AGGREGATE.
+	UserDefined *UserDefinedAggInfo
+
 	// DistsqlBlocklist is set to true when this aggregate function cannot be
 	// evaluated in distributed fashion.
 	DistsqlBlocklist bool
 }
 
+// UserDefinedAggInfo describes how to compute an aggregate created with CREATE
+// AGGREGATE using its support functions. The expressions reference the state
+// of the aggregate as @1. The transition expression references the arguments
+// of the aggregate as @2, @3, etc, and the combine expression references the
+// partial state to combine with as @2.
+type UserDefinedAggInfo struct {
+	ResultType *types.T
+	StateType  *types.T
+
+	// ArgTypes are the types of the arguments of the aggregate. If there is
+	// more than one argument, the aggregate is passed a single tuple column.
+	ArgTypes []*types.T
+
+	// InitialState is the initial state of the aggregate.
+	InitialState tree.TypedExpr
+
+	Transition       tree.TypedExpr
+	StrictTransition bool
+
+	// Final is nil if the state is the result of the aggregate.
+	Final tree.TypedExpr
+
+	// Combine is nil if the aggregate cannot be computed in multiple stages.
+	Combine       tree.TypedExpr
+	StrictCombine bool
+}
+
 // WindowInfo represents the information about a window function that must be
 // passed through to the execution engine.
 type WindowInfo struct {
@@ -240,6 +271,11 @@ type WindowInfo struct {
 	// FilterIdxs is the list of column indices to use as filters.
 	FilterIdxs []int
 
+	// UserDefinedAggs is the list of user-defined aggregates, in the same order
+	// as Exprs. It is nil for the functions that are not user-defined
+	// aggregates.
+	UserDefinedAggs []*UserDefinedAggInfo
+
 	// Partition is the set of input columns to partition on.
 	Partition []NodeColumnOrdinal
 
@@ -277,8 +313,14 @@ type ApplyJoinPlanRightSideFn func(ctx context.Context, ef Factory, leftRow tree
 // ConstructBuffer as an input; it should only be triggered if this buffer is
 // not empty.
 type Cascade struct {
+	// FKConstraint is the foreign key constraint that caused the cascade, or nil
+	// if the cascade was planned for an AFTER trigger.
 	FKConstraint cat.ForeignKeyConstraint
 
+	// Trigger is the AFTER trigger that is run by the cascade, or nil if the
+	// cascade was planned for a foreign key action.
+	Trigger cat.Trigger
+
 	// Buffer is the Node returned by ConstructBuffer which stores the input to
 	// the mutation. It is nil if the cascade does not require a buffer.
 	Buffer Node
@@ -146,6 +146,12 @@ func TryFilterInvertedIndex(
 				index:           index,
 				computedColumns: computedColumns,
 			}
+		case types.RangeFamily:
+			filterPlanner = &rangeFilterPlanner{
+				tabID:           tabID,
+				index:           index,
+				computedColumns: computedColumns,
+			}
 		default:
 			return nil, nil, nil, nil, false
 		}
@@ -454,7 +460,8 @@ func TryJoinInvertedIndex(
 	index cat.Index,
 	inputCols opt.ColSet,
 ) opt.ScalarExpr {
-	if !index.IsInverted() {
+	if !index.IsInverted() || !index.VecConfig().IsEmpty() {
+		// Vector indexes cannot be used in inverted joins.
 		return nil
 	}
 
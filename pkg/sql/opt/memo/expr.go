@@ -762,6 +762,59 @@ type UDFDefinition struct {
 	CursorDeclaration *tree.RoutineOpenCursor
 }
 
+// UserDefinedAggregate stores details about a call to an aggregate created
+// with CREATE AGGREGATE. The calls to its support functions are built as
+// scalar expressions over columns that are not produced by any relational
+// expression: StateCol holds the current state of the aggregate, ArgCols hold
+// the arguments of an aggregated row, and OtherStateCol holds a partial state
+// that is combined with the current state.
+type UserDefinedAggregate struct {
+	// Name is the name of the aggregate.
+	Name string
+
+	// Typ is the return type of the aggregate.
+	Typ *types.T
+
+	// StateType is the type of the state of the aggregate.
+	StateType *types.T
+
+	// Volatility is the volatility of the aggregate, which is the volatility
+	// of its least restrictive support function.
+	Volatility volatility.V
+
+	// StateCol, ArgCols and OtherStateCol are the columns referenced by the
+	// calls to the support functions. OtherStateCol is only set if Combine is
+	// set.
+	StateCol      opt.ColumnID
+	ArgCols       opt.ColList
+	OtherStateCol opt.ColumnID
+
+	// InitialState is the initial state of the aggregate. It is a NULL
+	// constant if the aggregate has no initial condition.
+	InitialState opt.ScalarExpr
+
+	// Transition computes the next state from StateCol and ArgCols.
+	Transition opt.ScalarExpr
+
+	// StrictTransition is true if the state transition function is not called
+	// on NULL input. In that case rows with a NULL argument are skipped, and
+	// the first non-NULL argument becomes the state if the state is NULL.
+	StrictTransition bool
+
+	// Final computes the result of the aggregate from StateCol. If it is nil,
+	// the state is the result.
+	Final opt.ScalarExpr
+
+	// Combine computes the combination of the partial states in StateCol and
+	// OtherStateCol. If it is nil, the aggregate cannot be computed in
+	// multiple stages.
+	Combine opt.ScalarExpr
+
+	// StrictCombine is true if the combine function is not called on NULL
+	// input, in which case a NULL partial state is ignored.
+	StrictCombine bool
+}
+
 // ExceptionBlock contains the information needed to match and handle errors in
 // the EXCEPTION block of a routine defined with PLpgSQL.
 type ExceptionBlock struct {
@@ -1338,8 +1391,15 @@ type FKCascades []FKCascade
 // FKCascade stores metadata necessary for building a cascading query.
 // Cascading queries are built as needed, after the original query is executed.
 type FKCascade struct {
+	// FKConstraint is the foreign key constraint that caused the cascade. It
+	// is nil if the cascade executes an AFTER trigger rather than a foreign key
+	// action.
 	FKConstraint cat.ForeignKeyConstraint
 
+	// Trigger is the AFTER trigger executed by the cascade, if any. Exactly one
+	// of FKConstraint and Trigger is non-nil.
+	Trigger cat.Trigger
+
 	// Builder is an object that can be used as the "optbuilder" for the cascading
 	// query.
 	Builder CascadeBuilder
@@ -757,6 +757,14 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 		tp.Child(tree.AsStringWithFlags(t.Syntax, fmtFlags))
 		f.formatDependencies(tp, t.Deps, t.TypeDeps)
 
+	case *CreateTriggerExpr:
+		fmtFlags := tree.FmtSimple
+		if f.RedactableValues {
+			fmtFlags = tree.FmtMarkRedactionNode | tree.FmtOmitNameRedaction
+		}
+		tp.Child(tree.AsStringWithFlags(t.Syntax, fmtFlags))
+		f.formatDependencies(tp, t.Deps, t.TypeDeps)
+
 	case *CreateStatisticsExpr:
 		tp.Child(t.Syntax.String())
 
@@ -1303,6 +1311,19 @@ func (f *ExprFmtCtx) formatScalarPrivate(scalar opt.ScalarExpr) {
 
 	case *UniqueChecksItem:
 		tab := f.Memo.metadata.TableMeta(t.Table)
+		if t.Exclusion {
+			constraint := tab.Table.ExclusionConstraint(t.CheckOrdinal)
+			fmt.Fprintf(f.Buffer, ": %s EXCLUDE (", tab.Alias.ObjectName)
+			for i := 0; i < constraint.ColumnCount(); i++ {
+				if i > 0 {
+					f.Buffer.WriteString(", ")
+				}
+				col := tab.Table.Column(constraint.ColumnOrdinal(tab.Table, i))
+				fmt.Fprintf(f.Buffer, "%s WITH %s", col.ColName(), constraint.Operator(i))
+			}
+			f.Buffer.WriteByte(')')
+			break
+		}
 		constraint := tab.Table.Unique(t.CheckOrdinal)
 		fmt.Fprintf(f.Buffer, ": %s(", tab.Alias.ObjectName)
 		for i := 0; i < constraint.ColumnCount(); i++ {
@@ -1362,6 +1383,9 @@ func (f *ExprFmtCtx) formatScalarPrivate(scalar opt.ScalarExpr) {
 	case *UDFCallExpr:
 		private = nil
 
+	case *UserDefinedAggExpr:
+		fmt.Fprintf(f.Buffer, " %s", t.Def.Name)
+
 	default:
 		private = scalar.Private()
 	}
@@ -1555,7 +1579,11 @@ func (f *ExprFmtCtx) formatMutationCommon(tp treeprinter.Node, p *MutationPrivat
 	if len(p.FKCascades) > 0 {
 		c := tp.Childf("cascades")
 		for i := range p.FKCascades {
-			c.Child(p.FKCascades[i].FKConstraint.Name())
+			if trigger := p.FKCascades[i].Trigger; trigger != nil {
+				c.Childf("trigger %s", trigger.Name())
+			} else {
+				c.Child(p.FKCascades[i].FKConstraint.Name())
+			}
 		}
 	}
 }
@@ -201,6 +201,7 @@ type Memo struct {
 	pushOffsetIntoIndexJoin                    bool
 	usePolymorphicParameterFix                 bool
 	useConditionalHoistFix                     bool
+	vectorSearchProbes                         int64
 
 	// txnIsoLevel is the isolation level under which the plan was created. This
 	// affects the planning of some locking operations, so it must be included in
@@ -288,6 +289,7 @@ func (m *Memo) Init(ctx context.Context, evalCtx *eval.Context) {
 		pushOffsetIntoIndexJoin:                    evalCtx.SessionData().OptimizerPushOffsetIntoIndexJoin,
 		usePolymorphicParameterFix:                 evalCtx.SessionData().OptimizerUsePolymorphicParameterFix,
 		useConditionalHoistFix:                     evalCtx.SessionData().OptimizerUseConditionalHoistFix,
+		vectorSearchProbes:                         evalCtx.SessionData().VectorSearchProbes,
 		txnIsoLevel:                                evalCtx.TxnIsoLevel,
 	}
 	m.metadata.Init()
@@ -453,6 +455,7 @@ func (m *Memo) IsStale(
 		m.pushOffsetIntoIndexJoin != evalCtx.SessionData().OptimizerPushOffsetIntoIndexJoin ||
 		m.usePolymorphicParameterFix != evalCtx.SessionData().OptimizerUsePolymorphicParameterFix ||
 		m.useConditionalHoistFix != evalCtx.SessionData().OptimizerUseConditionalHoistFix ||
+		m.vectorSearchProbes != evalCtx.SessionData().VectorSearchProbes ||
 		m.txnIsoLevel != evalCtx.TxnIsoLevel {
 		return true, nil
 	}
@@ -14,10 +14,13 @@ import (
 	"context"
 	"math"
 	"reflect"
+	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/geo/geoindex"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
@@ -762,6 +765,19 @@ func (sb *statisticsBuilder) makeTableStatistics(tabID opt.TableID) *props.Stati
 				// were added at different times (and therefore have a different row
 				// count).
 				sb.finalizeFromRowCountAndDistinctCounts(colStat, stats)
+
+				// Keep the histogram of a multi-column statistic, which is built over
+				// tuples of the column values, as well as its most common values and
+				// functional dependencies.
+				if cols.Len() > 1 {
+					useHistogram := len(stat.Histogram()) > 0 &&
+						sb.evalCtx.SessionData().OptimizerUseHistograms
+					if useHistogram || len(stat.MostCommonValues()) > 0 || len(stat.Dependencies()) > 0 {
+						stats.ExtendedStats = append(
+							stats.ExtendedStats, makeExtendedStatistic(tabID, stat, useHistogram),
+						)
+					}
+				}
 			}
 
 			// Add inverted histograms if necessary.
@@ -798,6 +814,35 @@ func (sb *statisticsBuilder) makeTableStatistics(tabID opt.TableID) *props.Stati
 	return stats
 }
 
+// makeExtendedStatistic returns the extended statistic of the given
+// multi-column statistic. The histogram, which is only included if
+// useHistogram is true, does not include the bucket for rows in which all of
+// the columns are NULL.
+func makeExtendedStatistic(
+	tabID opt.TableID, stat cat.TableStatistic, useHistogram bool,
+) props.ExtendedStatistic {
+	e := props.ExtendedStatistic{
+		Cols:                 make(opt.ColList, stat.ColumnCount()),
+		NonNullRowCount:      float64(stat.RowCount() - stat.NullCount()),
+		NonNullDistinctCount: float64(stat.DistinctCount()),
+		MostCommonValues:     stat.MostCommonValues(),
+		Dependencies:         stat.Dependencies(),
+	}
+	for i := range e.Cols {
+		e.Cols[i] = tabID.ColumnID(stat.ColumnOrdinal(i))
+	}
+	if stat.NullCount() > 0 && e.NonNullDistinctCount > 0 {
+		e.NonNullDistinctCount--
+	}
+	if useHistogram {
+		e.Histogram = stat.Histogram()
+		if len(e.Histogram) > 0 && e.Histogram[0].UpperBound == tree.DNull {
+			e.Histogram = e.Histogram[1:]
+		}
+	}
+	return e
+}
+
 // invertedIndexColInfo is used to store information about an inverted column.
 type invertedIndexColInfo struct {
 	// invIdxColOrds is the list of inverted index column ordinals for a given
@@ -970,6 +1015,7 @@ func (sb *statisticsBuilder) constrainScan(
 ) {
 	var unapplied filterCount
 	var constrainedCols, histCols opt.ColSet
+	vecSelectivity := props.OneSelectivity
 	idx := sb.md.Table(scan.Table).Index(scan.Index)
 
 	// Calculate distinct counts and histograms for inverted constrained columns
@@ -1001,12 +1047,16 @@ func (sb *statisticsBuilder) constrainScan(
 					histCols.Add(invertedConstrainedCol)
 					sb.updateDistinctCountFromHistogram(colStat, inputStat.DistinctCount)
 				}
+			} else if vecConfig := idx.VecConfig(); !vecConfig.IsEmpty() {
+				vecSelectivity = vectorScanSelectivity(vecConfig, scan.InvertedConstraint)
 			} else {
 				// Just assume a single closed span such as ["\xfd", "\xfe").
 				// This corresponds to two "conjuncts" as defined in
 				// numConjunctsInConstraint.
 				unapplied.unknown += 2
 			}
+		} else if vecConfig := idx.VecConfig(); !vecConfig.IsEmpty() {
+			vecSelectivity = vectorScanSelectivity(vecConfig, scan.InvertedConstraint)
 		} else {
 			// Assume a single closed span.
 			unapplied.unknown += 2
@@ -1048,12 +1098,50 @@ func (sb *statisticsBuilder) constrainScan(
 
 	// Calculate row count and selectivity
 	// -----------------------------------
-	corr := sb.correlationFromMultiColDistinctCounts(constrainedCols, scan, s)
-	s.ApplySelectivity(sb.selectivityFromConstrainedCols(constrainedCols, histCols, scan, s, corr))
+	// Columns constrained to constants by the exact prefix of the constraint or
+	// by equalities in the partial index predicate may be accounted for by the
+	// extended statistics of multi-column statistics.
+	var extCols opt.ColSet
+	if scan.InvertedConstraint == nil {
+		var eqVals map[opt.ColumnID]tree.Datum
+		if constraint != nil && constraint.Spans.Count() > 0 {
+			sp := constraint.Spans.Get(0)
+			for i, n := 0, constraint.ExactPrefix(sb.ctx, sb.evalCtx); i < n; i++ {
+				if d := sp.StartKey().Value(i); d != tree.DNull {
+					if eqVals == nil {
+						eqVals = make(map[opt.ColumnID]tree.Datum)
+					}
+					eqVals[constraint.Columns.Get(i).ID()] = d
+				}
+			}
+		}
+		eqVals = eqConstantsFromFilters(pred, eqVals)
+		var extSel props.Selectivity
+		extSel, extCols = sb.selectivityFromExtendedStats(
+			scan.Table, eqVals, constrainedCols, histCols, scan, s,
+		)
+		s.ApplySelectivity(extSel)
+	}
+	remainingCols := constrainedCols.Difference(extCols)
+	corr := sb.correlationFromMultiColDistinctCounts(remainingCols, scan, s)
+	s.ApplySelectivity(sb.selectivityFromConstrainedCols(
+		remainingCols, histCols.Difference(extCols), scan, s, corr,
+	))
 	s.ApplySelectivity(sb.selectivityFromUnappliedConjuncts(unapplied))
+	s.ApplySelectivity(vecSelectivity)
 	s.ApplySelectivity(sb.selectivityFromNullsRemoved(scan, notNullCols, constrainedCols))
 }
 
+// vectorScanSelectivity estimates the selectivity of a scan over the given
+// partitions of a vector index. Without a histogram, vectors are assumed to be
+// spread evenly across the partitions.
+func vectorScanSelectivity(
+	cfg catpb.VectorIndexConfig, spans inverted.Spans,
+) props.Selectivity {
+	partitions := float64(uint64(1) << uint(cfg.PartitionBits))
+	return props.MakeSelectivity(float64(len(spans)) / partitions)
+}
+
 func (sb *statisticsBuilder) colStatScan(colSet opt.ColSet, scan *ScanExpr) *props.ColumnStatistic {
 	relProps := scan.Relational()
 	s := relProps.Statistics()
@@ -3303,8 +3391,23 @@ func (sb *statisticsBuilder) filterRelExpr(
 
 	// Calculate row count and selectivity
 	// -----------------------------------
-	corr := sb.correlationFromMultiColDistinctCounts(constrainedCols, e, s)
-	s.ApplySelectivity(sb.selectivityFromConstrainedCols(constrainedCols, histCols, e, s, corr))
+	// Columns constrained to constants by equality predicates that are
+	// accounted for by the extended statistics of multi-column statistics are
+	// excluded from the selectivity calculation based on single-column
+	// statistics.
+	var extSel props.Selectivity
+	var extCols opt.ColSet
+	if tabID, ok := sb.extendedStatsTable(e); ok {
+		extSel, extCols = sb.selectivityFromExtendedStats(
+			tabID, eqConstantsFromFilters(filters, nil /* eqVals */), constrainedCols, histCols, e, s,
+		)
+		s.ApplySelectivity(extSel)
+	}
+	remainingCols := constrainedCols.Difference(extCols)
+	corr := sb.correlationFromMultiColDistinctCounts(remainingCols, e, s)
+	s.ApplySelectivity(sb.selectivityFromConstrainedCols(
+		remainingCols, histCols.Difference(extCols), e, s, corr,
+	))
 	s.ApplySelectivity(sb.selectivityFromEquivalencies(equivReps, &relProps.FuncDeps, e, s))
 	s.ApplySelectivity(sb.selectivityFromUnappliedConjuncts(unapplied))
 	s.ApplySelectivity(sb.selectivityFromNullsRemoved(e, notNullCols, constrainedCols))
@@ -4475,6 +4578,163 @@ func (sb *statisticsBuilder) selectivityFromConstrainedCols(
 	return selectivity
 }
 
+// extendedStatsTable returns the table whose statistics are the input
+// statistics of the given expression, if its filters are estimated from them.
+// This is the case for a Select directly on top of an unfiltered scan of a
+// table, and for an unconstrained scan of a partial index.
+func (sb *statisticsBuilder) extendedStatsTable(e RelExpr) (opt.TableID, bool) {
+	switch t := e.(type) {
+	case *SelectExpr:
+		if scan, ok := t.Input.(*ScanExpr); ok && scan.IsUnfiltered(sb.md) {
+			return scan.Table, true
+		}
+	case *ScanExpr:
+		if t.Constraint == nil && t.InvertedConstraint == nil {
+			return t.Table, true
+		}
+	}
+	return 0, false
+}
+
+// eqConstantsFromFilters adds the columns that are constrained to a non-NULL
+// constant by an equality in the given filters to eqVals, which is allocated
+// if nil, and returns it.
+func eqConstantsFromFilters(
+	filters FiltersExpr, eqVals map[opt.ColumnID]tree.Datum,
+) map[opt.ColumnID]tree.Datum {
+	for i := range filters {
+		eq, ok := filters[i].Condition.(*EqExpr)
+		if !ok {
+			continue
+		}
+		v, ok := eq.Left.(*VariableExpr)
+		if !ok || !CanExtractConstDatum(eq.Right) {
+			continue
+		}
+		d := ExtractConstDatum(eq.Right)
+		if d == tree.DNull {
+			continue
+		}
+		if eqVals == nil {
+			eqVals = make(map[opt.ColumnID]tree.Datum)
+		}
+		eqVals[v.Col] = d
+	}
+	return eqVals
+}
+
+// selectivityFromExtendedStats calculates the selectivity of the equality
+// predicates that constrain columns of the given table to the constant values
+// in eqVals, using the extended statistics of its multi-column statistics. It
+// returns the selectivity and the set of columns it accounts for, which must
+// be excluded from the selectivity calculation based on single-column
+// statistics.
+//
+// First, the predicates that constrain all of the columns of a multi-column
+// statistic are estimated from the number of rows matching the tuple of
+// values, according to its most common values or histogram. For example,
+// given a statistic on (city, zip), the selectivity of:
+//
+//	city = 'X' AND zip = 'Y'
+//
+// is estimated from the number of rows matching the tuple ('X', 'Y'), rather
+// than from the product of the selectivities of the individual predicates,
+// which underestimates the row count when the columns are correlated.
+//
+// Then, like Postgres, the functional dependencies between the remaining
+// constrained columns are applied in descending order of degree. If column a
+// determines column b with degree f, the selectivity of:
+//
+//	a = 'X' AND b = 'Y'
+//
+// is estimated as P(a = 'X') * (f + (1-f) * P(b = 'Y')), so b is accounted for
+// here and a is left to the single-column statistics. A column is only
+// implied by a single dependency, and a column that determines another one is
+// not implied by a later dependency, to avoid circular estimates.
+func (sb *statisticsBuilder) selectivityFromExtendedStats(
+	tabID opt.TableID,
+	eqVals map[opt.ColumnID]tree.Datum,
+	constrainedCols, histCols opt.ColSet,
+	e RelExpr,
+	s *props.Statistics,
+) (selectivity props.Selectivity, cols opt.ColSet) {
+	selectivity = props.OneSelectivity
+	if !sb.evalCtx.SessionData().OptimizerUseMultiColStats || len(eqVals) < 2 {
+		return selectivity, cols
+	}
+	tableStats := sb.makeTableStatistics(tabID)
+	if !tableStats.Available || len(tableStats.ExtendedStats) == 0 {
+		return selectivity, cols
+	}
+
+	for i := range tableStats.ExtendedStats {
+		ext := &tableStats.ExtendedStats[i]
+		var typ *types.T
+		if len(ext.MostCommonValues) > 0 {
+			typ = ext.MostCommonValues[0].Value.ResolvedType()
+		} else if len(ext.Histogram) > 0 {
+			typ = ext.Histogram[0].UpperBound.ResolvedType()
+		} else {
+			continue
+		}
+		extCols := ext.Cols.ToSet()
+		if extCols.Intersects(cols) {
+			// The columns are already accounted for by another statistic.
+			continue
+		}
+		vals := make(tree.Datums, 0, len(ext.Cols))
+		for _, col := range ext.Cols {
+			if d, ok := eqVals[col]; ok {
+				vals = append(vals, d)
+			}
+		}
+		if len(vals) != len(ext.Cols) {
+			continue
+		}
+		rowCount, ok := ext.EqualityRowCount(sb.ctx, sb.evalCtx, tree.NewDTuple(typ, vals...))
+		if !ok {
+			continue
+		}
+		selectivity.Multiply(props.MakeSelectivityFromFraction(rowCount, tableStats.RowCount))
+		cols.UnionWith(extCols)
+	}
+
+	type dependency struct {
+		from, to opt.ColumnID
+		degree   float64
+	}
+	var deps []dependency
+	for i := range tableStats.ExtendedStats {
+		ext := &tableStats.ExtendedStats[i]
+		for _, dep := range ext.Dependencies {
+			from, to := ext.Cols[dep.From], ext.Cols[dep.To]
+			if _, ok := eqVals[from]; !ok || !constrainedCols.Contains(to) {
+				continue
+			}
+			if _, ok := eqVals[to]; ok && !cols.Contains(to) {
+				deps = append(deps, dependency{from: from, to: to, degree: dep.Degree})
+			}
+		}
+	}
+	sort.SliceStable(deps, func(i, j int) bool {
+		return deps[i].degree > deps[j].degree
+	})
+	var determinants opt.ColSet
+	for _, dep := range deps {
+		if cols.Contains(dep.to) || determinants.Contains(dep.to) {
+			continue
+		}
+		toCols := opt.MakeColSet(dep.to)
+		toSel := sb.selectivityFromConstrainedCols(
+			toCols, histCols.Intersection(toCols), e, s, 0, /* correlation */
+		)
+		selectivity.Multiply(props.MakeSelectivity(dep.degree + (1-dep.degree)*toSel.AsFloat()))
+		cols.Add(dep.to)
+		determinants.Add(dep.from)
+	}
+	return selectivity, cols
+}
+
 // selectivityFromNullsRemoved calculates the selectivity from null-rejecting
 // filters that were not already accounted for in selectivityFromMultiColDistinctCounts
 // or selectivityFromHistograms. The columns for filters already accounted for
@@ -193,6 +193,7 @@ func init() {
 	typingFuncMap[opt.IfErrOp] = typeIfErr
 	typingFuncMap[opt.UDFCallOp] = typeUDFCall
 	typingFuncMap[opt.TxnControlOp] = typeTxnControl
+	typingFuncMap[opt.UserDefinedAggOp] = typeUserDefinedAgg
 
 	// Override default typeAsAggregate behavior for aggregate functions with
 	// a large number of possible overloads or where ReturnType depends on
@@ -205,6 +206,7 @@ func init() {
 	typingFuncMap[opt.ConstNotNullAggOp] = typeAsFirstArg
 	typingFuncMap[opt.AnyNotNullAggOp] = typeAsFirstArg
 	typingFuncMap[opt.FirstAggOp] = typeAsFirstArg
+	typingFuncMap[opt.RangeIntersectAggOp] = typeAsFirstArg
 
 	typingFuncMap[opt.LagOp] = typeAsFirstArg
 	typingFuncMap[opt.LeadOp] = typeAsFirstArg
@@ -392,6 +394,11 @@ func typeUDFCall(e opt.ScalarExpr) *types.T {
 	return e.(*UDFCallExpr).Def.Typ
 }
 
+// typeUserDefinedAgg returns the type of a UserDefinedAggExpr operator.
+func typeUserDefinedAgg(e opt.ScalarExpr) *types.T {
+	return e.(*UserDefinedAggExpr).Def.Typ
+}
+
 // typeTxnControl returns the type of a TxnControlExpr operator
 func typeTxnControl(e opt.ScalarExpr) *types.T {
 	return e.(*TxnControlExpr).Def.Typ
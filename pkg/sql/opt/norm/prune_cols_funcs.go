@@ -79,6 +79,15 @@ func (c *CustomFuncs) neededMutationCols(
 		cols.Add(private.CanaryCol)
 	}
 
+	// AFTER triggers are passed the entire old and new rows, which are read from
+	// the buffered mutation input.
+	for i := range private.FKCascades {
+		if private.FKCascades[i].Trigger != nil {
+			cols.UnionWith(private.FKCascades[i].OldValues.ToSet())
+			cols.UnionWith(private.FKCascades[i].NewValues.ToSet())
+		}
+	}
+
 	if private.WithID != 0 {
 		for i := range uniqueChecks {
 			withUses := memo.WithUses(uniqueChecks[i].Check)
@@ -138,6 +138,12 @@ type Builder struct {
 	// are disabled and certain statements (like mutations) are disallowed.
 	insideViewDef bool
 
+	// mergeActions are the mutations that implement the actions of the MERGE
+	// statement being built, if any. The MERGE statement registers a single
+	// mutation of its target table, so its actions are not checked against
+	// multiple mutations of the same table. See buildMerge.
+	mergeActions []tree.Statement
+
 	// If set, we are processing a function definition; in this case catalog caches
 	// are disabled and only statements whitelisted are allowed.
 	insideFuncDef bool
@@ -157,6 +163,20 @@ type Builder struct {
 	// CALL statement.
 	insideNestedPLpgSQLCall bool
 
+	// insideTriggerWhen is true when we are processing the WHEN condition of a
+	// trigger, which may reference fields of the NEW and OLD rows.
+	insideTriggerWhen bool
+
+	// insideTriggerFunction is true when we are processing the body of a
+	// trigger function, in which TG_ARGV is indexed starting from 0.
+	insideTriggerFunction bool
+
+	// triggerArgvCols is the set of columns that hold the value of the TG_ARGV
+	// parameter of a trigger function, including the columns that PL/pgSQL
+	// variables are rebound to. It is used to identify references to TG_ARGV,
+	// which may be shadowed by other columns with the same name.
+	triggerArgvCols opt.ColSet
+
 	// If set, we are collecting view dependencies in schemaDeps. This can only
 	// happen inside view/function definitions.
 	//
@@ -326,7 +346,7 @@ func (b *Builder) buildStmt(
 	if b.insideViewDef {
 		// A blocklist of statements that can't be used from inside a view.
 		switch stmt := stmt.(type) {
-		case *tree.Delete, *tree.Insert, *tree.Update, *tree.CreateTable, *tree.CreateView,
+		case *tree.Delete, *tree.Insert, *tree.Update, *tree.Merge, *tree.CreateTable, *tree.CreateView,
 			*tree.Split, *tree.Unsplit, *tree.Relocate, *tree.RelocateRange,
 			*tree.ControlJobs, *tree.ControlSchedules, *tree.CancelQueries, *tree.CancelSessions,
 			*tree.CreateRoutine:
@@ -373,6 +393,11 @@ func (b *Builder) buildStmt(
 			return b.buildUpdate(stmt, inScope)
 		})
 
+	case *tree.Merge:
+		// The WITH clause is handled by buildMerge, since the MERGE actions must
+		// be built at the top level.
+		return b.buildMerge(stmt, inScope)
+
 	case *tree.CreateTable:
 		return b.buildCreateTable(stmt, inScope)
 
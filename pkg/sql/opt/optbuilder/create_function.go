@@ -14,6 +14,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
@@ -165,7 +166,7 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 	// When multiple OUT parameters are present, parameter names become the
 	// labels in the output RECORD type.
 	var outParamNames []string
-	var sawDefaultExpr, sawPolymorphicInParam, sawPolymorphicOutParam bool
+	var sawDefaultExpr, sawPolymorphicInParam, sawPolymorphicOutParam, sawVariadic bool
 	for i := range cf.Params {
 		param := &cf.Params[i]
 		typ, err := tree.ResolveType(b.ctx, param.Type, b.semaCtx.TypeResolver)
@@ -192,6 +193,35 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 		if param.Class == tree.RoutineParamInOut && param.Name == "" {
 			panic(unimplemented.NewWithIssue(121251, "unnamed INOUT parameters are not yet supported"))
 		}
+		if sawVariadic {
+			if param.IsInParam() {
+				panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+					"VARIADIC parameter must be the last input parameter"))
+			}
+			if cf.IsProcedure && param.Class == tree.RoutineParamOut {
+				panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+					"VARIADIC parameter must be the last parameter"))
+			}
+		}
+		if param.Class == tree.RoutineParamVariadic {
+			if !b.evalCtx.Settings.Version.IsActive(b.ctx, clusterversion.V24_3_VariadicRoutines) {
+				panic(pgerror.New(pgcode.FeatureNotSupported,
+					"VARIADIC parameters not supported until upgrade to version 24.3 is finalized"))
+			}
+			if typ.IsPolymorphicType() {
+				panic(unimplemented.NewWithIssue(88947,
+					"VARIADIC parameters of polymorphic types are not yet supported"))
+			}
+			if typ.Family() != types.ArrayFamily {
+				panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+					"VARIADIC parameter must be an array"))
+			}
+			if param.DefaultVal != nil {
+				panic(unimplemented.NewWithIssue(88947,
+					"VARIADIC parameters with default values are not yet supported"))
+			}
+			sawVariadic = true
+		}
 		if param.IsInParam() {
 			if typ.Family() == types.VoidFamily {
 				panic(pgerror.Newf(pgcode.InvalidFunctionDefinition, "SQL functions cannot have arguments of type VOID"))
@@ -293,6 +323,10 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 	} else if len(outParamTypes) == 1 {
 		outParamType = outParamTypes[0]
 	}
+	if sawVariadic && sawPolymorphicInParam {
+		panic(unimplemented.NewWithIssue(88947,
+			"routines with both VARIADIC and polymorphic parameters are not yet supported"))
+	}
 
 	var funcReturnType *types.T
 	var err error
@@ -417,10 +451,6 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 				"set-returning PL/pgSQL functions are not yet supported",
 			))
 		}
-		if funcReturnType.Identical(types.Trigger) {
-			panic(unimplemented.NewWithIssue(126356, "trigger functions are not yet supported"))
-		}
-
 		// Parse the function body.
 		stmt, err := plpgsqlparser.Parse(funcBodyStr)
 		if err != nil {
@@ -439,6 +469,16 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 			}
 		}
 
+		if funcReturnType.Identical(types.Trigger) {
+			// The body of a trigger function is not built until a trigger invokes
+			// it, since the types of NEW and OLD depend on the table on which the
+			// trigger is defined. As in Postgres, the body is only checked for
+			// syntax errors at creation time.
+			formatFuncBodyStmt(fmtCtx, stmt.AST, language, false /* newLine */)
+			afterBuildStmt()
+			break
+		}
+
 		// We need to disable stable function folding because we want to catch the
 		// volatility of stable functions. If folded, we only get a scalar and lose
 		// the volatility.
@@ -11,10 +11,141 @@
 package optbuilder
 
 import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 )
 
-func (b *Builder) buildCreateTrigger(cf *tree.CreateTrigger, inScope *scope) (outScope *scope) {
-	panic(unimplemented.NewWithIssue(126359, "CREATE TRIGGER"))
+func (b *Builder) buildCreateTrigger(ct *tree.CreateTrigger, inScope *scope) (outScope *scope) {
+	b.DisableMemoReuse = true
+
+	if ct.ActionTime == tree.TriggerActionTimeInsteadOf {
+		panic(unimplemented.NewWithIssue(126359, "INSTEAD OF triggers"))
+	}
+	if len(ct.Transitions) > 0 {
+		panic(unimplemented.NewWithIssue(126359, "trigger transition tables"))
+	}
+	for _, event := range ct.Events {
+		if event.EventType == tree.TriggerEventTruncate {
+			panic(unimplemented.NewWithIssue(126359, "TRUNCATE triggers"))
+		}
+	}
+
+	// Resolve the table on which the trigger is defined. Creating a trigger
+	// requires the same privilege as altering the table.
+	tn := ct.TableName.ToTableName()
+	tab, resName := b.resolveTable(&tn, privilege.CREATE)
+	if tab.IsVirtualTable() {
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"cannot create trigger on virtual table %q", tab.Name(),
+		))
+	}
+	if tab.IsMaterializedView() {
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"cannot create trigger on materialized view %q", tab.Name(),
+		))
+	}
+	ct.TableName = resName.ToUnresolvedObjectName()
+
+	// Check that the columns of any UPDATE OF events exist.
+	for _, event := range ct.Events {
+		for _, name := range event.Columns {
+			var found bool
+			for i, n := 0, tab.ColumnCount(); i < n; i++ {
+				if tab.Column(i).ColName() == name && tab.Column(i).Visibility() == cat.Visible {
+					found = true
+					break
+				}
+			}
+			if !found {
+				panic(pgerror.Newf(pgcode.UndefinedColumn,
+					"column %q of relation %q does not exist", name, tab.Name(),
+				))
+			}
+		}
+	}
+
+	// Resolve the trigger function. It must take no arguments and return
+	// TRIGGER; the arguments listed in the CREATE TRIGGER statement are passed
+	// to the function through TG_ARGV.
+	funcDef, err := ct.FuncName.Resolve(b.ctx, b.semaCtx.SearchPath, b.semaCtx.FunctionResolver)
+	if err != nil {
+		panic(err)
+	}
+	var overload *tree.Overload
+	for i := range funcDef.Overloads {
+		if o := funcDef.Overloads[i].Overload; o.Type == tree.UDFRoutine && o.Types.Length() == 0 {
+			overload = o
+			break
+		}
+	}
+	if overload == nil {
+		panic(pgerror.Newf(pgcode.UndefinedFunction, "function %s() does not exist", funcDef.Name))
+	}
+	if !overload.ReturnType(nil /* args */).Identical(types.Trigger) {
+		panic(pgerror.Newf(pgcode.InvalidObjectDefinition,
+			"function %s must return type trigger", funcDef.Name,
+		))
+	}
+	var funcDeps opt.SchemaFunctionDeps
+	funcDeps.Add(int(overload.Oid))
+
+	if ct.When != nil {
+		b.validateTriggerWhen(ct, tab)
+	}
+
+	outScope = b.allocScope()
+	outScope.expr = b.factory.ConstructCreateTrigger(
+		&memo.CreateTriggerPrivate{
+			Syntax:   ct,
+			Deps:     opt.SchemaDeps{{DataSource: tab}},
+			FuncDeps: funcDeps,
+		},
+	)
+	return outScope
+}
+
+// validateTriggerWhen checks that the WHEN condition of the given trigger is a
+// valid boolean expression, and that it only references the NEW and OLD rows
+// when they are available to the trigger.
+func (b *Builder) validateTriggerWhen(ct *tree.CreateTrigger, tab cat.Table) {
+	md := b.factory.Metadata()
+	rowType, _ := triggerRowType(tab)
+	newColID := md.AddColumn("new", rowType)
+	oldColID := md.AddColumn("old", rowType)
+	whenScope := b.triggerWhenScope(rowType, newColID, oldColID)
+	texpr := b.resolveTriggerWhen(ct.When, whenScope)
+	var colRefs opt.ColSet
+	b.buildScalar(texpr, whenScope, nil /* outScope */, nil /* outCol */, &colRefs)
+
+	if ct.ForEach == tree.TriggerForEachStatement {
+		if !colRefs.Empty() {
+			panic(pgerror.New(pgcode.InvalidObjectDefinition,
+				"statement trigger's WHEN condition cannot reference column values",
+			))
+		}
+		return
+	}
+	for _, event := range ct.Events {
+		switch event.EventType {
+		case tree.TriggerEventInsert:
+			if colRefs.Contains(oldColID) {
+				panic(pgerror.New(pgcode.InvalidObjectDefinition,
+					"INSERT trigger's WHEN condition cannot reference OLD values",
+				))
+			}
+		case tree.TriggerEventDelete:
+			if colRefs.Contains(newColID) {
+				panic(pgerror.New(pgcode.InvalidObjectDefinition,
+					"DELETE trigger's WHEN condition cannot reference NEW values",
+				))
+			}
+		}
+	}
 }
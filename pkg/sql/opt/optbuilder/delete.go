@@ -78,7 +78,9 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 	b.checkPrivilege(depName, tab, privilege.SELECT)
 
 	// Check if this table has already been mutated in another subquery.
-	b.checkMultipleMutations(tab, generalMutation)
+	if !b.isMergeAction(del) {
+		b.checkMultipleMutations(tab, generalMutation)
+	}
 
 	var mb mutationBuilder
 	mb.init(b, "delete", tab, alias)
@@ -99,14 +101,23 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 		mb.buildDelete(nil /* returning */)
 	}
 
+	// Statement-level BEFORE triggers must be executed before the delete.
+	mb.buildStatementLevelBeforeTriggers(tree.TriggerEventDelete)
+
 	return mb.outScope
 }
 
 // buildDelete constructs a Delete operator, possibly wrapped by a Project
 // operator that corresponds to the given RETURNING clause.
 func (mb *mutationBuilder) buildDelete(returning *tree.ReturningExprs) {
+	// Invoke any row-level BEFORE triggers, which may skip the deletion of some
+	// rows.
+	mb.buildRowLevelBeforeTriggers(tree.TriggerEventDelete)
+
 	mb.buildFKChecksAndCascadesForDelete()
 
+	mb.buildAfterTriggers(tree.TriggerEventDelete)
+
 	// Project partial index DEL boolean columns.
 	mb.projectPartialIndexDelCols()
 
@@ -0,0 +1,118 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinsregistry"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// buildDomainChecks wraps the given value of a domain type in calls to the
+// crdb_internal.check_domain builtin function, which raise an error if the
+// value violates the NOT NULL or CHECK constraints of the domain. The value is
+// returned unchanged if typ is not a domain or if the domain has no
+// constraints.
+func (b *Builder) buildDomainChecks(value opt.ScalarExpr, typ *types.T) opt.ScalarExpr {
+	if !typ.IsDomain() || typ.TypeMeta.DomainData == nil {
+		return value
+	}
+	domain := typ.TypeMeta.DomainData
+	if !domain.NotNull && len(domain.Checks) == 0 {
+		return value
+	}
+
+	// The value is referenced by each of the constraints, so it must not be
+	// evaluated more than once.
+	var p props.Shared
+	memo.BuildSharedProps(value, &p, b.evalCtx)
+	if p.VolatilitySet.HasVolatile() {
+		panic(unimplemented.NewWithIssue(27796,
+			"casting a volatile expression to a domain with constraints"))
+	}
+
+	domainName := typ.Name()
+	out := value
+	if domain.NotNull {
+		notNull := b.factory.ConstructIsNot(value, memo.NullSingleton)
+		out = b.constructDomainCheck(out, notNull, domainName, "" /* constraint */)
+	}
+	if len(domain.Checks) == 0 {
+		return out
+	}
+
+	// The CHECK constraints refer to the value as VALUE. It is resolved to a
+	// column of the base type in a scope of its own, and references to that
+	// column are then replaced with the value.
+	baseType := typ.DomainBaseType()
+	valueColID := b.factory.Metadata().AddColumn(string(schemaexpr.DomainValueName), baseType)
+	checkScope := b.allocScope()
+	checkScope.context = exprKindDomainCheck
+	checkScope.cols = append(checkScope.cols, scopeColumn{
+		name: scopeColName(schemaexpr.DomainValueName), typ: baseType, id: valueColID,
+	})
+	var replace norm.ReplaceFunc
+	replace = func(e opt.Expr) opt.Expr {
+		if v, ok := e.(*memo.VariableExpr); ok && v.Col == valueColID {
+			return value
+		}
+		return b.factory.Replace(e, replace)
+	}
+	for i := range domain.Checks {
+		check := &domain.Checks[i]
+		expr, err := parser.ParseExpr(check.Expr)
+		if err != nil {
+			panic(err)
+		}
+		passed := b.resolveAndBuildScalar(
+			expr, types.Bool, exprKindDomainCheck, tree.RejectSpecial|tree.RejectSubqueries, checkScope,
+		)
+		passed = replace(passed).(opt.ScalarExpr)
+		out = b.constructDomainCheck(out, passed, domainName, check.Name)
+	}
+	return out
+}
+
+// constructDomainCheck builds a call to the crdb_internal.check_domain builtin
+// function, which returns value if passed is not false, and raises an error for
+// the given constraint of the domain otherwise. An empty constraint name refers
+// to the NOT NULL constraint of the domain.
+func (b *Builder) constructDomainCheck(
+	value, passed opt.ScalarExpr, domainName, constraint string,
+) opt.ScalarExpr {
+	const checkDomainFnName = "crdb_internal.check_domain"
+	fnProps, overloads := builtinsregistry.GetBuiltinProperties(checkDomainFnName)
+	if len(overloads) != 1 {
+		panic(errors.AssertionFailedf("expected one overload for %s", checkDomainFnName))
+	}
+	return b.factory.ConstructFunction(
+		memo.ScalarListExpr{
+			value,
+			passed,
+			b.factory.ConstructConstVal(tree.NewDString(domainName), types.String),
+			b.factory.ConstructConstVal(tree.NewDString(constraint), types.String),
+		},
+		&memo.FunctionPrivate{
+			Name:       checkDomainFnName,
+			Typ:        value.DataType(),
+			Properties: fnProps,
+			Overload:   &overloads[0],
+		},
+	)
+}
@@ -37,6 +37,7 @@ package optbuilder
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
@@ -46,6 +47,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
 )
 
 // groupby information stored in scopes.
@@ -100,6 +102,10 @@ type groupby struct {
 	// It is used to ensure that the builder does not throw a grouping error
 	// prematurely.
 	buildingGroupingCols bool
+
+	// groupingSets is set if the GROUP BY clause contains ROLLUP, CUBE or
+	// GROUPING SETS elements. See grouping_sets.go.
+	groupingSets *groupingSetsInfo
 }
 
 // groupByStrSet is a set of stringified GROUP BY expressions that map to the
@@ -250,6 +256,11 @@ func (a aggregateInfo) isOrderingSensitive() bool {
 	if a.isOrderedSetAggregate() {
 		return true
 	}
+	if a.def.Overload.UDFAggregate != nil {
+		// The state transition function of a user-defined aggregate may
+		// depend on the order of the rows.
+		return true
+	}
 	switch a.def.Name {
 	case "array_agg", "array_cat_agg", "concat_agg", "string_agg", "json_agg",
 		"jsonb_agg", "json_object_agg", "jsonb_object_agg", "st_makeline",
@@ -385,7 +396,7 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 
 		// Construct the aggregate function from its name and arguments and store
 		// it in the corresponding scope column.
-		aggCols[i].scalar = b.constructAggregate(agg.def.Name, args)
+		aggCols[i].scalar = b.constructAggregate(&agg.def, agg.FuncExpr, args)
 
 		// Wrap the aggregate function with an AggDistinct operator if DISTINCT
 		// was specified in the query.
@@ -426,16 +437,20 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 		g.aggInScope.copyOrdering(fromScope)
 	}
 
-	// Construct the pre-projection, which renders the grouping columns and the
-	// aggregate arguments, as well as any additional order by columns.
-	b.constructProjectForScope(fromScope, g.aggInScope)
-
-	g.aggOutScope.expr = b.constructGroupBy(
-		g.aggInScope.expr,
-		groupingColSet,
-		aggCols,
-		g.aggInScope.ordering,
-	)
+	if g.groupingSets != nil {
+		g.aggOutScope.expr = b.constructGroupingSets(fromScope, aggCols)
+	} else {
+		// Construct the pre-projection, which renders the grouping columns and the
+		// aggregate arguments, as well as any additional order by columns.
+		b.constructProjectForScope(fromScope, g.aggInScope)
+
+		g.aggOutScope.expr = b.constructGroupBy(
+			g.aggInScope.expr,
+			groupingColSet,
+			aggCols,
+			g.aggInScope.ordering,
+		)
+	}
 
 	// Wrap with having filter if it exists.
 	if having != nil {
@@ -510,8 +525,12 @@ func (b *Builder) buildGroupingList(
 	// used in an aggregate function`. The builder cannot know whether there is
 	// a grouping error until the grouping columns are fully built.
 	g.buildingGroupingCols = true
-	for _, e := range groupBy {
-		b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+	if hasGroupingSets(groupBy) {
+		b.buildGroupingSets(groupBy, selects, projectionsScope, fromScope)
+	} else {
+		for _, e := range groupBy {
+			b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+		}
 	}
 	g.buildingGroupingCols = false
 }
@@ -536,9 +555,12 @@ func (b *Builder) buildGroupingList(
 // aggInScope       The scope that will contain the grouping expressions as well
 //
 //	as the aggregate function arguments.
+//
+// Returns the symbolic representation (see groupStrs) of each of the grouping
+// expressions built.
 func (b *Builder) buildGrouping(
 	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope, aggInScope *scope,
-) {
+) (exprStrs []string) {
 	// Unwrap parenthesized expressions like "((a))" to "a".
 	groupBy = tree.StripParens(groupBy)
 	alias := ""
@@ -636,6 +658,7 @@ func (b *Builder) buildGrouping(
 		// If a grouping column has already been added, don't add it again.
 		// GROUP BY a, a is semantically equivalent to GROUP BY a.
 		exprStr := symbolicExprStr(e)
+		exprStrs = append(exprStrs, exprStr)
 		if _, ok := fromScope.groupby.groupStrs[exprStr]; ok {
 			continue
 		}
@@ -647,6 +670,7 @@ func (b *Builder) buildGrouping(
 		b.buildScalar(e, fromScope, aggInScope, col, nil)
 		fromScope.groupby.groupStrs[exprStr] = col
 	}
+	return exprStrs
 }
 
 // buildAggArg builds a scalar expression which is used as an input in some form
@@ -699,11 +723,12 @@ func (b *Builder) buildAggregateFunction(
 ) *aggregateInfo {
 	tempScopeColsBefore := len(tempScope.cols)
 
+	argExprs := userDefinedAggArgs(def, getTypedExprs(f.Exprs))
 	info := aggregateInfo{
 		FuncExpr: f,
 		def:      *def,
 		distinct: (f.Type == tree.DistinctFuncType),
-		args:     make(memo.ScalarListExpr, len(f.Exprs)),
+		args:     make(memo.ScalarListExpr, len(argExprs)),
 	}
 
 	// Temporarily set b.subquery to nil so we don't add outer columns to the
@@ -712,8 +737,8 @@ func (b *Builder) buildAggregateFunction(
 	b.subquery = nil
 	defer func() { b.subquery = subq }()
 
-	for i, pexpr := range f.Exprs {
-		info.args[i] = b.buildAggArg(pexpr.(tree.TypedExpr), &info, tempScope, fromScope)
+	for i, pexpr := range argExprs {
+		info.args[i] = b.buildAggArg(pexpr, &info, tempScope, fromScope)
 	}
 
 	// If we have a filter, add it to tempScope after all the arguments. We'll
@@ -775,8 +800,10 @@ func (b *Builder) buildAggregateFunction(
 	return &info
 }
 
-func (b *Builder) constructWindowFn(name string, args []opt.ScalarExpr) opt.ScalarExpr {
-	switch name {
+func (b *Builder) constructWindowFn(
+	def *memo.FunctionPrivate, f *tree.FuncExpr, args []opt.ScalarExpr,
+) opt.ScalarExpr {
+	switch def.Name {
 	case "rank":
 		return b.factory.ConstructRank()
 	case "row_number":
@@ -800,11 +827,19 @@ func (b *Builder) constructWindowFn(name string, args []opt.ScalarExpr) opt.Scal
 	case "nth_value":
 		return b.factory.ConstructNthValue(args[0], args[1])
 	default:
-		return b.constructAggregate(name, args)
+		return b.constructAggregate(def, f, args)
 	}
 }
 
-func (b *Builder) constructAggregate(name string, args []opt.ScalarExpr) opt.ScalarExpr {
+func (b *Builder) constructAggregate(
+	def *memo.FunctionPrivate, f *tree.FuncExpr, args []opt.ScalarExpr,
+) opt.ScalarExpr {
+	if def.Overload.UDFAggregate != nil {
+		return b.factory.ConstructUserDefinedAgg(
+			args[0], &memo.UserDefinedAggPrivate{Def: b.buildUserDefinedAggregate(def, f)},
+		)
+	}
+	name := def.Name
 	switch name {
 	case "array_agg":
 		return b.factory.ConstructArrayAgg(args[0])
@@ -876,6 +911,10 @@ func (b *Builder) constructAggregate(name string, args []opt.ScalarExpr) opt.Sca
 		return b.factory.ConstructSTExtent(args[0])
 	case "st_union", "st_memunion":
 		return b.factory.ConstructSTUnion(args[0])
+	case "range_agg":
+		return b.factory.ConstructRangeAgg(args[0])
+	case "range_intersect_agg":
+		return b.factory.ConstructRangeIntersectAgg(args[0])
 	case "xor_agg":
 		return b.factory.ConstructXorAgg(args[0])
 	case "json_agg":
@@ -905,6 +944,131 @@ func (b *Builder) constructAggregate(name string, args []opt.ScalarExpr) opt.Sca
 	panic(errors.AssertionFailedf("unhandled aggregate: %s", name))
 }
 
+// userDefinedAggArgs returns the arguments with which an aggregate created with
+// CREATE AGGREGATE is built, given the arguments of the call. The arguments are
+// cast to the parameter types of the aggregate, and packed into a single tuple
+// if there is more than one, since UserDefinedAgg has a single input. The
+// arguments of any other aggregate are returned unchanged.
+func userDefinedAggArgs(def *memo.FunctionPrivate, args []tree.TypedExpr) []tree.TypedExpr {
+	if def.Overload.UDFAggregate == nil {
+		return args
+	}
+	paramTypes := def.Overload.Types.Types()
+	if len(paramTypes) != len(args) {
+		panic(errors.AssertionFailedf(
+			"different number of parameters %d and arguments %d", len(paramTypes), len(args),
+		))
+	}
+	for i, arg := range args {
+		if !arg.ResolvedType().Identical(paramTypes[i]) {
+			args[i] = tree.NewTypedCastExpr(arg, paramTypes[i])
+		}
+	}
+	if len(args) == 1 {
+		return args
+	}
+	exprs := make(tree.Exprs, len(args))
+	for i := range args {
+		exprs[i] = args[i]
+	}
+	return []tree.TypedExpr{tree.NewTypedTuple(types.MakeTuple(paramTypes), exprs)}
+}
+
+// buildUserDefinedAggregate builds the definition of a call to an aggregate
+// created with CREATE AGGREGATE. The calls to its support functions are built
+// over columns of a scope that is not part of the query; they are mapped to the
+// state and the arguments of the aggregate when it is executed.
+func (b *Builder) buildUserDefinedAggregate(
+	def *memo.FunctionPrivate, f *tree.FuncExpr,
+) *memo.UserDefinedAggregate {
+	o := def.Overload
+	agg := o.UDFAggregate
+	if f.AggType == tree.OrderedSetAgg {
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"%s is not an ordered-set aggregate, so it cannot have WITHIN GROUP", def.Name))
+	}
+	if err := b.catalog.CheckExecutionPrivilege(b.ctx, o.Oid); err != nil {
+		panic(err)
+	}
+	paramTypes := o.Types.Types()
+	b.factory.Metadata().AddUserDefinedFunction(o, paramTypes, f.Func.ReferenceByName)
+	if b.trackSchemaDeps {
+		b.schemaFunctionDeps.Add(int(o.Oid))
+	}
+
+	// The support functions cannot reference outer columns.
+	subq := b.subquery
+	b.subquery = nil
+	defer func() { b.subquery = subq }()
+
+	supportScope := b.allocScope()
+	uda := &memo.UserDefinedAggregate{
+		Name:       def.Name,
+		Typ:        f.ResolvedType(),
+		StateType:  agg.StateType,
+		Volatility: o.Volatility,
+		ArgCols:    make(opt.ColList, len(paramTypes)),
+	}
+	stateCol := b.synthesizeColumn(
+		supportScope, scopeColName("state"), agg.StateType, nil /* expr */, nil, /* scalar */
+	)
+	uda.StateCol = stateCol.id
+	transitionArgs := make(tree.Exprs, 0, len(paramTypes)+1)
+	transitionArgs = append(transitionArgs, stateCol)
+	for i, typ := range paramTypes {
+		argCol := b.synthesizeColumn(
+			supportScope, scopeColName(tree.Name(fmt.Sprintf("arg%d", i+1))), typ,
+			nil /* expr */, nil, /* scalar */
+		)
+		uda.ArgCols[i] = argCol.id
+		transitionArgs = append(transitionArgs, argCol)
+	}
+	uda.Transition, uda.StrictTransition = b.buildAggregateSupportCall(
+		agg.StateTransitionFunc, transitionArgs, supportScope,
+	)
+	if agg.FinalFunc != 0 {
+		uda.Final, _ = b.buildAggregateSupportCall(agg.FinalFunc, tree.Exprs{stateCol}, supportScope)
+	}
+	if agg.CombineFunc != 0 {
+		otherStateCol := b.synthesizeColumn(
+			supportScope, scopeColName("other_state"), agg.StateType, nil /* expr */, nil, /* scalar */
+		)
+		uda.OtherStateCol = otherStateCol.id
+		uda.Combine, uda.StrictCombine = b.buildAggregateSupportCall(
+			agg.CombineFunc, tree.Exprs{stateCol, otherStateCol}, supportScope,
+		)
+	}
+	if agg.InitialCondition != nil {
+		initCond := tree.NewTypedCastExpr(tree.NewDString(*agg.InitialCondition), agg.StateType)
+		uda.InitialState = b.buildScalar(initCond, supportScope, nil, nil, nil)
+	} else {
+		uda.InitialState = b.factory.ConstructNull(agg.StateType)
+	}
+	return uda
+}
+
+// buildAggregateSupportCall builds a call to the support function of a
+// user-defined aggregate with the given OID. It also returns true if the
+// function is strict, i.e. not called on NULL input.
+func (b *Builder) buildAggregateSupportCall(
+	fnOID oid.Oid, args tree.Exprs, inScope *scope,
+) (_ opt.ScalarExpr, strict bool) {
+	call := &tree.FuncExpr{
+		Func:  tree.ResolvableFunctionReference{FunctionReference: &tree.FunctionOID{OID: fnOID}},
+		Exprs: args,
+	}
+	texpr, err := tree.TypeCheck(b.ctx, call, b.semaCtx, types.Any)
+	if err != nil {
+		panic(err)
+	}
+	typedCall, ok := texpr.(*tree.FuncExpr)
+	if !ok {
+		panic(errors.AssertionFailedf("expected a function call, got %T", texpr))
+	}
+	strict = !typedCall.ResolvedOverload().CalledOnNullInput
+	return b.buildScalar(typedCall, inScope, nil, nil, nil), strict
+}
+
 func isAggregate(def *tree.ResolvedFunctionDefinition) bool {
 	return isClass(def, tree.AggregateClass)
 }
@@ -940,6 +1104,11 @@ func newGroupingError(name tree.Name) error {
 // In the unique index or unique without index cases, all key columns must be
 // marked as NOT NULL to allow the implicit grouping.
 func (b *Builder) allowImplicitGroupingColumn(colID opt.ColumnID, g *groupby) bool {
+	if g.groupingSets != nil {
+		// The column would need to be NULL for grouping sets that do not include
+		// the key columns.
+		return false
+	}
 	md := b.factory.Metadata()
 	colMeta := md.ColumnMeta(colID)
 	if colMeta.Table == 0 {
@@ -0,0 +1,531 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+// This file has builder code specific to GROUP BY clauses with ROLLUP, CUBE or
+// GROUPING SETS elements.
+//
+// Each element of such a GROUP BY clause is expanded into a list of grouping
+// sets, and the grouping sets of the clause are the cross product of the
+// grouping sets of its elements. For example:
+//
+//   GROUP BY a, ROLLUP (b, c)
+//   =>
+//   GROUPING SETS ((a, b, c), (a, b), (a))
+//
+// The aggregation is built as follows:
+//
+//  - the pre-projection renders the grouping expressions and the arguments to
+//    aggregate functions.
+//
+//  - the pre-projection is cross-joined with a Values expression that contains
+//    the index of each grouping set, which expands each input row into one row
+//    per grouping set. A projection replaces the grouping expressions that are
+//    not part of the grouping set with NULL. This way, the input is only read
+//    once, regardless of the number of grouping sets.
+//
+//  - a single GroupBy aggregates the expanded rows, grouping them by the
+//    projected grouping columns and the grouping set index. The index is also
+//    used to compute the GROUPING function.
+//
+//  - like a scalar aggregation, an empty grouping set produces a row even if
+//    the input is empty. To handle this case, the aggregation is full-joined
+//    with the indexes of the empty grouping sets, and the aggregates that have
+//    a non-NULL default value are projected with that value.
+//
+// For example:
+//   SELECT a, b, sum(c), count(*) FROM t GROUP BY ROLLUP (a, b)
+//
+//   project (set=COALESCE(set_idx, empty_set), count=COALESCE(count_rows, 0))
+//    └── full-join
+//         ├── group-by (a', b', set_idx)
+//         │    ├── project
+//         │    │    ├── inner-join (cross)
+//         │    │    │    ├── project: a, b, c
+//         │    │    │    │    └── scan t
+//         │    │    │    └── values: set_idx=(0), (1), (2)
+//         │    │    └── projections
+//         │    │         ├── CASE set_idx WHEN 0 THEN a WHEN 1 THEN a END [as=a']
+//         │    │         └── CASE set_idx WHEN 0 THEN b END [as=b']
+//         │    └── aggregations: sum(c), count_rows
+//         ├── values: empty_set=(2)
+//         └── filters: set_idx = empty_set
+//
+// Since the grouping columns produced by the aggregation can be NULL even when
+// the grouping expressions are not, they are assigned new column IDs that are
+// distinct from the columns computed by the pre-projection. References to the
+// grouping expressions in the SELECT list, HAVING and ORDER BY clauses resolve
+// to the new columns, while references within aggregate functions continue to
+// use the columns computed by the pre-projection.
+//
+// Ordering-sensitive aggregates are built as window functions over the
+// expanded rows, partitioned by the same grouping columns. See
+// buildAggregationAsWindow.
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/intsets"
+)
+
+const (
+	// maxCubeElements is the maximum number of elements in a CUBE, which
+	// expands to 2^n grouping sets. This matches the limit in Postgres.
+	maxCubeElements = 12
+
+	// maxGroupingSets is the maximum number of grouping sets that a GROUP BY
+	// clause can expand to. This matches the limit in Postgres.
+	maxGroupingSets = 4096
+
+	// maxGroupingArgs is the maximum number of arguments to the GROUPING
+	// function, so that its result fits in an INT4 as it does in Postgres.
+	maxGroupingArgs = 31
+)
+
+// groupingSetsInfo contains information about the grouping sets of a GROUP BY
+// clause with ROLLUP, CUBE or GROUPING SETS elements.
+type groupingSetsInfo struct {
+	// sets contains, for each grouping set, the ordinals in groupingCols() of
+	// the grouping columns that are part of the set.
+	sets []intsets.Fast
+
+	// inputCols contains the columns of the pre-projection that compute the
+	// grouping expressions. They correspond one-to-one with groupingCols(),
+	// which are instead produced by the aggregation and are NULL for rows of
+	// grouping sets they are not part of.
+	inputCols []scopeColumn
+
+	// outCols contains the IDs of the columns in groupingCols(), in the same
+	// order.
+	outCols opt.ColList
+
+	// setCol is produced by the aggregation and contains the index in sets of
+	// the grouping set that each row belongs to.
+	setCol opt.ColumnID
+
+	// setIdxCol is the column of the expanded input that contains the index of
+	// the grouping set. It is the same as setCol, unless there are empty
+	// grouping sets; see constructEmptyGroupingSets.
+	setIdxCol opt.ColumnID
+
+	// defaults contains, for each aggregate that has a non-NULL default value,
+	// the column that the aggregation produces instead of the aggregate column
+	// when there are empty grouping sets. The aggregate column is projected on
+	// top of the aggregation; see constructEmptyGroupingSets.
+	defaults map[opt.ColumnID]groupingSetDefault
+}
+
+// groupingSetDefault is an aggregate with a non-NULL default value, which is
+// returned for the empty grouping sets of an input with no rows.
+type groupingSetDefault struct {
+	// col is the column produced by the aggregation.
+	col opt.ColumnID
+	// val is the default value of the aggregate.
+	val opt.ScalarExpr
+}
+
+// aggOutCol returns the column that the aggregation produces for the given
+// aggregate column.
+func (info *groupingSetsInfo) aggOutCol(id opt.ColumnID) opt.ColumnID {
+	if info != nil {
+		if d, ok := info.defaults[id]; ok {
+			return d.col
+		}
+	}
+	return id
+}
+
+// hasEmptySet returns true if any of the grouping sets is empty.
+func (info *groupingSetsInfo) hasEmptySet() bool {
+	for _, set := range info.sets {
+		if set.Empty() {
+			return true
+		}
+	}
+	return false
+}
+
+// hasGroupingSets returns true if the given GROUP BY clause contains a ROLLUP,
+// CUBE or GROUPING SETS element.
+func hasGroupingSets(groupBy tree.GroupBy) bool {
+	for _, e := range groupBy {
+		switch e.(type) {
+		case *tree.RollupExpr, *tree.CubeExpr, *tree.GroupingSetsExpr:
+			return true
+		}
+	}
+	return false
+}
+
+// buildGroupingSets builds the grouping columns for a GROUP BY clause that
+// contains ROLLUP, CUBE or GROUPING SETS elements, and expands the clause into
+// its list of grouping sets. See buildGroupingList for a description of the
+// parameters.
+func (b *Builder) buildGroupingSets(
+	groupBy tree.GroupBy, selects tree.SelectExprs, projectionsScope *scope, fromScope *scope,
+) {
+	if b.insideFuncDef {
+		panic(unimplemented.New("user-defined functions", "GROUPING SETS usage inside a function definition"))
+	}
+	g := fromScope.groupby
+	firstGroupingCol := len(g.aggInScope.cols)
+
+	// ords maps each grouping expression to the ordinal of its grouping column.
+	// buildGrouping adds a column only the first time an expression is seen, so
+	// ordinals are assigned in the same order as the columns.
+	ords := make(map[string]int)
+	buildElem := func(e tree.Expr) (set intsets.Fast) {
+		for _, exprStr := range b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope) {
+			ord, ok := ords[exprStr]
+			if !ok {
+				ord = len(ords)
+				ords[exprStr] = ord
+			}
+			set.Add(ord)
+		}
+		return set
+	}
+
+	var expand func(e tree.Expr) []intsets.Fast
+	expand = func(e tree.Expr) []intsets.Fast {
+		switch t := e.(type) {
+		case *tree.RollupExpr:
+			// ROLLUP (a, b, c) => GROUPING SETS ((a, b, c), (a, b), (a), ()).
+			sets := make([]intsets.Fast, len(t.Exprs)+1)
+			for i := range t.Exprs {
+				sets[i+1] = sets[i].Union(buildElem(t.Exprs[i]))
+			}
+			for i, j := 0, len(sets)-1; i < j; i, j = i+1, j-1 {
+				sets[i], sets[j] = sets[j], sets[i]
+			}
+			return sets
+
+		case *tree.CubeExpr:
+			// CUBE (a, b) => GROUPING SETS ((a, b), (a), (b), ()).
+			if len(t.Exprs) > maxCubeElements {
+				panic(pgerror.Newf(pgcode.ProgramLimitExceeded,
+					"CUBE is limited to %d elements", maxCubeElements,
+				))
+			}
+			elems := make([]intsets.Fast, len(t.Exprs))
+			for i := range t.Exprs {
+				elems[i] = buildElem(t.Exprs[i])
+			}
+			n := len(elems)
+			sets := make([]intsets.Fast, 0, 1<<n)
+			for mask := (1 << n) - 1; mask >= 0; mask-- {
+				var set intsets.Fast
+				for i := range elems {
+					if mask&(1<<(n-1-i)) != 0 {
+						set.UnionWith(elems[i])
+					}
+				}
+				sets = append(sets, set)
+			}
+			return sets
+
+		case *tree.GroupingSetsExpr:
+			var sets []intsets.Fast
+			for _, elem := range t.Sets {
+				sets = append(sets, expand(elem)...)
+			}
+			return sets
+
+		default:
+			return []intsets.Fast{buildElem(e)}
+		}
+	}
+
+	// The grouping sets of the GROUP BY clause are the cross product of the
+	// grouping sets of each of its elements.
+	sets := []intsets.Fast{{}}
+	for _, e := range groupBy {
+		elemSets := expand(e)
+		product := make([]intsets.Fast, 0, len(sets)*len(elemSets))
+		for _, s := range sets {
+			for _, t := range elemSets {
+				product = append(product, s.Union(t))
+			}
+		}
+		sets = product
+		if len(sets) > maxGroupingSets {
+			panic(pgerror.Newf(pgcode.ProgramLimitExceeded,
+				"too many grouping sets present (maximum %d)", maxGroupingSets,
+			))
+		}
+	}
+
+	// Save the pre-projection columns for the grouping expressions, and give
+	// the grouping columns produced by the aggregation new IDs. groupStrs is
+	// updated to point at the new columns, since the slice backing aggInScope
+	// may have been reallocated while the grouping columns were built.
+	md := b.factory.Metadata()
+	groupingCols := g.aggInScope.cols[firstGroupingCol:]
+	info := &groupingSetsInfo{
+		sets:      sets,
+		inputCols: make([]scopeColumn, len(groupingCols)),
+		outCols:   make(opt.ColList, len(groupingCols)),
+	}
+	copy(info.inputCols, groupingCols)
+	for i := range groupingCols {
+		col := &groupingCols[i]
+		col.id = md.AddColumn(md.ColumnMeta(col.id).Alias, col.typ)
+		col.scalar = nil
+		info.outCols[i] = col.id
+	}
+	for exprStr, ord := range ords {
+		g.groupStrs[exprStr] = &groupingCols[ord]
+	}
+	info.setCol = md.AddColumn("grouping_set", types.Int)
+	g.groupingSets = info
+}
+
+// constructGroupingSets constructs the aggregation for a GROUP BY clause with
+// grouping sets, as described at the top of this file. aggCols are the
+// aggregate columns of the aggOutScope, with their aggregate functions already
+// built. The output columns of the returned expression are the aggregate
+// columns, the grouping columns and the setCol of the groupingSetsInfo.
+func (b *Builder) constructGroupingSets(fromScope *scope, aggCols []scopeColumn) memo.RelExpr {
+	g := fromScope.groupby
+	groupingColSet := b.constructGroupingSetsInput(fromScope)
+	outCols := make([]scopeColumn, len(aggCols))
+	for i := range aggCols {
+		outCols[i] = aggCols[i]
+		outCols[i].id = g.groupingSets.aggOutCol(aggCols[i].id)
+	}
+	agg := b.constructGroupBy(g.aggInScope.expr, groupingColSet, outCols, g.aggInScope.ordering)
+	return b.constructEmptyGroupingSets(g, agg)
+}
+
+// constructGroupingSetsInput constructs the pre-projection for a GROUP BY
+// clause with grouping sets, and expands each of its rows into one row for
+// each grouping set. The expanded input is stored in the aggInScope, and the
+// returned set contains the columns by which it must be grouped: the grouping
+// columns and the setIdxCol of the groupingSetsInfo.
+func (b *Builder) constructGroupingSetsInput(fromScope *scope) opt.ColSet {
+	g := fromScope.groupby
+	info := g.groupingSets
+	f := b.factory
+	md := f.Metadata()
+
+	// Construct the pre-projection, which renders the grouping expressions in
+	// place of the grouping columns, as well as the aggregate arguments and any
+	// additional order by columns.
+	var outColOrds opt.ColMap
+	for i, id := range info.outCols {
+		outColOrds.Set(int(id), i)
+	}
+	preScope := g.aggInScope.replace()
+	for i := range g.aggInScope.cols {
+		if ord, ok := outColOrds.Get(int(g.aggInScope.cols[i].id)); ok {
+			preScope.cols = append(preScope.cols, info.inputCols[ord])
+		} else {
+			preScope.cols = append(preScope.cols, g.aggInScope.cols[i])
+		}
+	}
+	preScope.extraCols = g.aggInScope.extraCols
+	preScope.ordering = g.aggInScope.ordering
+	b.constructProjectForScope(fromScope, preScope)
+
+	// Cross-join the pre-projection with the index of each grouping set.
+	info.setIdxCol = info.setCol
+	if info.hasEmptySet() {
+		info.setIdxCol = md.AddColumn("grouping_set_idx", types.Int)
+
+		// The aggregates that have a non-NULL default value are produced into
+		// new columns by the aggregation, so that the default can be projected
+		// into the aggregate columns after the empty grouping sets are added.
+		// These columns are allocated before the aggregation is constructed,
+		// since the factory may normalize it into a different operator (e.g. a
+		// DistinctOn if there are no aggregates).
+		info.defaults = make(map[opt.ColumnID]groupingSetDefault)
+		for i := range g.aggs {
+			if val, ok := b.overrideDefaultNullValue(g.aggs[i]); ok {
+				id := g.aggs[i].col.id
+				colMeta := md.ColumnMeta(id)
+				info.defaults[id] = groupingSetDefault{
+					col: md.AddColumn(colMeta.Alias, colMeta.Type),
+					val: val,
+				}
+			}
+		}
+	}
+	rowType := types.MakeTuple([]*types.T{types.Int})
+	rows := make(memo.ScalarListExpr, len(info.sets))
+	for i := range info.sets {
+		rows[i] = f.ConstructTuple(memo.ScalarListExpr{
+			f.ConstructConstVal(tree.NewDInt(tree.DInt(i)), types.Int),
+		}, rowType)
+	}
+	sets := f.ConstructValues(rows, &memo.ValuesPrivate{
+		Cols: opt.ColList{info.setIdxCol},
+		ID:   md.NextUniqueID(),
+	})
+	input := f.ConstructInnerJoin(preScope.expr, sets, memo.TrueFilter, memo.EmptyJoinPrivate)
+
+	// Project the grouping columns, which are NULL for the grouping sets they
+	// are not part of.
+	groupingColSet := opt.MakeColSet(info.setIdxCol)
+	projections := make(memo.ProjectionsExpr, len(info.outCols))
+	for i, id := range info.outCols {
+		var whens memo.ScalarListExpr
+		for setIdx, set := range info.sets {
+			if set.Contains(i) {
+				whens = append(whens, f.ConstructWhen(
+					f.ConstructConstVal(tree.NewDInt(tree.DInt(setIdx)), types.Int),
+					f.ConstructVariable(info.inputCols[i].id),
+				))
+			}
+		}
+		typ := md.ColumnMeta(id).Type
+		projections[i] = f.ConstructProjectionsItem(
+			f.ConstructCase(f.ConstructVariable(info.setIdxCol), whens, f.ConstructNull(typ)), id,
+		)
+		groupingColSet.Add(id)
+	}
+	passthrough := input.Relational().OutputCols
+	g.aggInScope.expr = f.ConstructProject(input, projections, passthrough)
+	return groupingColSet
+}
+
+// constructEmptyGroupingSets ensures that the given aggregation, which groups
+// the expanded input of a GROUP BY clause with grouping sets, produces a row
+// for each empty grouping set even if the input has no rows. The aggregation
+// is full-joined with the indexes of the empty grouping sets, so that a row of
+// NULLs is produced for each empty grouping set that has no row. The setCol
+// and the aggregates that have a non-NULL default value are then projected on
+// top of the join.
+//
+// The aggregation must produce the aggregates that have a default value into
+// the columns of the defaults of the groupingSetsInfo. It may be any
+// expression, since the factory can normalize the aggregation.
+func (b *Builder) constructEmptyGroupingSets(g *groupby, agg memo.RelExpr) memo.RelExpr {
+	info := g.groupingSets
+	if info.setIdxCol == info.setCol {
+		return agg
+	}
+	f := b.factory
+	md := f.Metadata()
+
+	// Join with the indexes of the empty grouping sets.
+	emptySetCol := md.AddColumn("empty_grouping_set", types.Int)
+	rowType := types.MakeTuple([]*types.T{types.Int})
+	var rows memo.ScalarListExpr
+	for i, set := range info.sets {
+		if set.Empty() {
+			rows = append(rows, f.ConstructTuple(memo.ScalarListExpr{
+				f.ConstructConstVal(tree.NewDInt(tree.DInt(i)), types.Int),
+			}, rowType))
+		}
+	}
+	emptySets := f.ConstructValues(rows, &memo.ValuesPrivate{
+		Cols: opt.ColList{emptySetCol},
+		ID:   md.NextUniqueID(),
+	})
+	join := f.ConstructFullJoin(
+		agg,
+		emptySets,
+		memo.FiltersExpr{f.ConstructFiltersItem(
+			f.ConstructEq(f.ConstructVariable(info.setIdxCol), f.ConstructVariable(emptySetCol)),
+		)},
+		memo.EmptyJoinPrivate,
+	)
+
+	// Project the default values into the aggregate columns, and the setCol.
+	passthrough := agg.Relational().OutputCols.Copy()
+	passthrough.Remove(info.setIdxCol)
+	projections := make(memo.ProjectionsExpr, 0, len(info.defaults)+1)
+	for i := range g.aggs {
+		id := g.aggs[i].col.id
+		d, ok := info.defaults[id]
+		if !ok || !passthrough.Contains(d.col) {
+			// The aggregate was deduplicated.
+			continue
+		}
+		passthrough.Remove(d.col)
+		projections = append(projections, f.ConstructProjectionsItem(
+			b.replaceDefaultReturn(f.ConstructVariable(d.col), memo.NullSingleton, d.val), id,
+		))
+	}
+	projections = append(projections, f.ConstructProjectionsItem(
+		f.ConstructCoalesce(memo.ScalarListExpr{
+			f.ConstructVariable(info.setIdxCol), f.ConstructVariable(emptySetCol),
+		}),
+		info.setCol,
+	))
+	return f.ConstructProject(join, projections, passthrough)
+}
+
+// buildGroupingFunc builds a call to the GROUPING function, which returns a bit
+// mask indicating which of its arguments are not part of the grouping set of
+// the current row. The leftmost argument corresponds to the most significant
+// bit.
+func (b *Builder) buildGroupingFunc(f *tree.FuncExpr, inScope *scope) opt.ScalarExpr {
+	if !inScope.inGroupingContext() || inScope.groupby.buildingGroupingCols {
+		panic(pgerror.New(pgcode.Grouping,
+			"arguments to GROUPING must be grouping expressions of the associated query level",
+		))
+	}
+	if inScope.inAgg {
+		panic(pgerror.New(pgcode.Grouping, "aggregate function calls cannot contain GROUPING"))
+	}
+	if len(f.Exprs) > maxGroupingArgs {
+		panic(pgerror.Newf(pgcode.TooManyArguments,
+			"GROUPING must have fewer than %d arguments", maxGroupingArgs+1,
+		))
+	}
+	g := inScope.groupby
+	groupingCols := g.groupingCols()
+	ords := make([]int, len(f.Exprs))
+	for i, e := range f.Exprs {
+		col, ok := g.groupStrs[symbolicExprStr(tree.StripParens(e))]
+		if !ok {
+			panic(pgerror.New(pgcode.Grouping,
+				"arguments to GROUPING must be grouping expressions of the associated query level",
+			))
+		}
+		for j := range groupingCols {
+			if groupingCols[j].id == col.id {
+				ords[i] = j
+				break
+			}
+		}
+	}
+
+	// Without grouping sets, every grouping expression is part of the only
+	// grouping set.
+	info := g.groupingSets
+	if info == nil {
+		return b.factory.ConstructConstVal(tree.NewDInt(0), types.Int)
+	}
+	whens := make(memo.ScalarListExpr, len(info.sets))
+	for i, set := range info.sets {
+		var mask tree.DInt
+		for _, ord := range ords {
+			mask <<= 1
+			if !set.Contains(ord) {
+				mask |= 1
+			}
+		}
+		whens[i] = b.factory.ConstructWhen(
+			b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(i)), types.Int),
+			b.factory.ConstructConstVal(tree.NewDInt(mask), types.Int),
+		)
+	}
+	return b.factory.ConstructCase(
+		b.factory.ConstructVariable(info.setCol), whens, b.factory.ConstructNull(types.Int),
+	)
+}
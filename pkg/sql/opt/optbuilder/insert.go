@@ -224,7 +224,9 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 	if ins.OnConflict == nil {
 		mutType = simpleInsert
 	}
-	b.checkMultipleMutations(tab, mutType)
+	if !b.isMergeAction(ins) {
+		b.checkMultipleMutations(tab, mutType)
+	}
 
 	var mb mutationBuilder
 	if ins.OnConflict != nil && ins.OnConflict.IsUpsertAlias() {
@@ -326,6 +328,10 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 			// derived from the primary index as the join condition.
 			mb.buildInputForUpsert(inScope, ins.Table, nil /* onConflict */, nil /* whereClause */)
 
+			// Invoke any row-level BEFORE UPDATE triggers for the rows that
+			// conflict with an existing row.
+			mb.buildRowLevelBeforeTriggers(tree.TriggerEventUpdate)
+
 			// Add additional columns for computed expressions that may depend on any
 			// updated columns, as well as mutation columns with default values.
 			mb.addSynthesizedColsForUpdate()
@@ -361,6 +367,15 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 		b.schemaDeps = append(b.schemaDeps, dep)
 	}
 
+	// Statement-level BEFORE triggers must be executed before the insert. As in
+	// Postgres, both the INSERT and UPDATE triggers are executed for an UPSERT or
+	// INSERT ... ON CONFLICT DO UPDATE, in that order. Each call wraps the
+	// mutation, so the UPDATE triggers are built first.
+	if ins.OnConflict != nil && !ins.OnConflict.DoNothing {
+		mb.buildStatementLevelBeforeTriggers(tree.TriggerEventUpdate)
+	}
+	mb.buildStatementLevelBeforeTriggers(tree.TriggerEventInsert)
+
 	return mb.outScope
 }
 
@@ -376,6 +391,9 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 //     values specified for them.
 //  4. Each update value is the same as the corresponding insert value.
 //  5. There are no inbound foreign keys containing non-key columns.
+//  6. There are no INSERT or UPDATE triggers. Triggers are passed the existing
+//     row, and must only be fired for the rows that are inserted or updated,
+//     respectively.
 //
 // TODO(andyk): The fast path is currently only enabled when the UPSERT alias
 // is explicitly selected by the user. It's possible to fast path some queries
@@ -430,6 +448,11 @@ func (mb *mutationBuilder) needExistingRows() bool {
 		}
 	}
 
+	// #6: Triggers need the existing values.
+	if hasUpsertTriggers(mb.tab) {
+		return true
+	}
+
 	return false
 }
 
@@ -680,6 +703,11 @@ func (mb *mutationBuilder) addSynthesizedColsForInsert() {
 	// Add assignment casts for default column values.
 	mb.addAssignmentCasts(mb.insertColIDs)
 
+	// Invoke any row-level BEFORE triggers, which may modify the non-computed
+	// column values. Computed columns must be added afterward, since they may
+	// depend on the modified values.
+	mb.buildRowLevelBeforeTriggers(tree.TriggerEventInsert)
+
 	// Now add all computed columns.
 	mb.addSynthesizedComputedCols(mb.insertColIDs, false /* restrict */)
 
@@ -702,8 +730,12 @@ func (mb *mutationBuilder) buildInsert(returning *tree.ReturningExprs) {
 
 	mb.buildUniqueChecksForInsert()
 
+	mb.buildExclusionChecksForInsert()
+
 	mb.buildFKChecksForInsert()
 
+	mb.buildAfterTriggers(tree.TriggerEventInsert)
+
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructInsert(
 		mb.outScope.expr, mb.uniqueChecks, mb.fastPathUniqueChecks, mb.fkChecks, private,
@@ -933,8 +965,15 @@ func (mb *mutationBuilder) buildUpsert(returning *tree.ReturningExprs) {
 
 	mb.buildUniqueChecksForUpsert()
 
+	mb.buildExclusionChecksForUpsert()
+
 	mb.buildFKChecksForUpsert()
 
+	// Rows that conflict with an existing row fire the AFTER UPDATE triggers,
+	// and the remaining rows fire the AFTER INSERT triggers.
+	mb.buildAfterTriggers(tree.TriggerEventInsert)
+	mb.buildAfterTriggers(tree.TriggerEventUpdate)
+
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructUpsert(
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
@@ -0,0 +1,745 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// mergeSourceName is the name of the CTE that holds the joined source rows
+	// of a MERGE statement. It is also used as the alias of an anonymous source.
+	mergeSourceName = "crdb_internal_merge_source"
+
+	// mergeActionColName is the name of the source column that holds the
+	// ordinal of the WHEN clause that applies to each source row.
+	mergeActionColName = "crdb_internal_merge_action"
+
+	// mergeKeyColPrefix and mergeDupColPrefix are the prefixes of the source
+	// columns that hold the primary key of the matched target row.
+	mergeKeyColPrefix = "crdb_internal_merge_key_"
+	mergeDupColPrefix = "crdb_internal_merge_dup_"
+
+	// mergeSrcColPrefix is the prefix of the source columns that hold the
+	// columns of the relations joined by a join source.
+	mergeSrcColPrefix = "crdb_internal_merge_src_"
+
+	// duplicateMergeErrText is the error text used when a target row is matched
+	// by more than one source row whose action updates or deletes it.
+	duplicateMergeErrText = "MERGE command cannot affect row a second time"
+)
+
+// buildMerge builds a MERGE statement on top of the existing mutation
+// operators. The statement
+//
+//	MERGE INTO t USING s ON <cond>
+//	WHEN MATCHED AND <c1> THEN UPDATE SET ...
+//	WHEN NOT MATCHED THEN INSERT ...
+//
+// is built as if it had been written as:
+//
+//	WITH crdb_internal_merge_source AS MATERIALIZED (
+//	  SELECT s.*, t.<pk>, CASE
+//	    WHEN t.<pk> IS NOT NULL AND <c1> THEN 1
+//	    WHEN t.<pk> IS NULL THEN 2
+//	  END AS crdb_internal_merge_action
+//	  FROM s LEFT JOIN t ON <cond>
+//	),
+//	crdb_internal_merge_1 AS (
+//	  UPDATE t SET ... FROM crdb_internal_merge_source AS s
+//	  WHERE t.<pk> = s.<pk> AND s.crdb_internal_merge_action = 1
+//	  RETURNING ...
+//	),
+//	crdb_internal_merge_2 AS (
+//	  INSERT INTO t SELECT ... FROM crdb_internal_merge_source AS s
+//	  WHERE s.crdb_internal_merge_action = 2
+//	  RETURNING ...
+//	)
+//	SELECT ... FROM crdb_internal_merge_1 UNION ALL SELECT ... FROM crdb_internal_merge_2
+//
+// Each source row takes the action of the first WHEN clause that applies to
+// it, so every target row is touched by at most one of the mutations. If a
+// target row is matched by more than one source row with an UPDATE or DELETE
+// action, an EnsureUpsertDistinctOn on the target primary key raises an error.
+// Without a RETURNING clause, the statement produces the number of affected
+// rows.
+//
+// If the source is a join, the CTE holds the columns of each joined relation
+// under generated names, and the actions expose them again under the name of
+// their relation through LATERAL subqueries. See sourceTables.
+//
+// The MERGE statement counts as a single mutation of its target table when
+// checking for multiple mutations of the same table, since its actions mutate
+// disjoint sets of target rows. However, the actions can still write the same
+// index keys if an UPDATE action changes an indexed column, so such statements
+// are rejected if there are other actions that mutate the table; see
+// checkMergeUpdateKeys.
+func (b *Builder) buildMerge(merge *tree.Merge, inScope *scope) (outScope *scope) {
+	if !inScope.atRoot {
+		panic(pgerror.Newf(pgcode.FeatureNotSupported,
+			"MERGE is only supported as a top-level statement"))
+	}
+	checkMergeWhens(merge.Whens)
+
+	inScope, correlatedCTEs := b.buildCTEs(merge.With, inScope)
+
+	// Find which table we're working on. The privileges required by each action
+	// are checked when the action is built.
+	tab, _, alias, refColumns := b.resolveTableForMutation(merge.Table, privilege.SELECT)
+	if tab.IsVirtualTable() {
+		panic(pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"cannot merge into view \"%s\"", tab.Name(),
+		))
+	}
+	if refColumns != nil {
+		panic(pgerror.Newf(pgcode.Syntax,
+			"cannot specify a list of column IDs with MERGE"))
+	}
+	b.checkMultipleMutations(tab, generalMutation)
+	b.checkMergeUpdateKeys(tab, merge.Whens)
+
+	mb := mergeBuilder{
+		merge:      merge,
+		tab:        tab,
+		targetName: alias.ObjectName,
+	}
+	mb.source, mb.sourceName = mergeSource(merge.Source)
+	if _, ok := mb.source.(*tree.JoinTableExpr); ok {
+		mb.sourceRels = b.mergeJoinSourceRels(mb.source, inScope)
+	}
+	pk := tab.Index(cat.PrimaryIndex)
+	mb.keyCols = make(tree.NameList, pk.KeyColumnCount())
+	for i := range mb.keyCols {
+		mb.keyCols[i] = pk.Column(i).ColName()
+	}
+
+	// Build the joined source rows as a CTE that each action reads from.
+	var srcScope *scope
+	if codes := mb.actionCodes(true /* matched */); len(codes) == 0 {
+		srcScope = b.buildStmt(mb.sourceSelect(), nil /* desiredTypes */, inScope)
+	} else {
+		srcScope = b.buildStmt(mb.dupSelect(codes), nil /* desiredTypes */, inScope)
+		var dupCols opt.ColSet
+		for i := len(srcScope.cols) - len(mb.keyCols); i < len(srcScope.cols); i++ {
+			dupCols.Add(srcScope.cols[i].id)
+		}
+		srcScope = b.buildDistinctOn(
+			dupCols, srcScope, true /* nullsAreDistinct */, duplicateMergeErrText,
+		)
+	}
+	srcScope.removeHiddenCols()
+	b.dropOrderingAndExtraCols(srcScope)
+
+	name := tree.AliasClause{Alias: mergeSourceName}
+	id := b.factory.Memo().NextWithID()
+	b.factory.Metadata().AddWithBinding(id, srcScope.expr)
+	cte := &cteSource{
+		id:           id,
+		name:         name,
+		cols:         b.getCTECols(srcScope, name),
+		originalExpr: merge,
+		expr:         srcScope.expr,
+		mtr:          tree.CTEMaterializeAlways,
+	}
+	b.addCTE(cte)
+
+	cteScope := inScope.push()
+	cteScope.ctes = map[string]*cteSource{name.Alias.String(): cte}
+	cteScope.atRoot = true
+
+	// The mutation of the target table was checked above on behalf of all of
+	// the actions.
+	actions := mb.actionsSelect()
+	b.mergeActions = mb.actions
+	defer func() { b.mergeActions = nil }()
+	outScope = b.buildStmt(actions, nil /* desiredTypes */, cteScope)
+	outScope.expr = b.buildWiths(outScope.expr, correlatedCTEs)
+	return outScope
+}
+
+// checkMergeWhens raises an error if a WHEN clause can never apply because it
+// follows an unconditional clause of the same kind.
+func checkMergeWhens(whens tree.MergeWhens) {
+	var unconditional [2]bool
+	for _, when := range whens {
+		idx := 0
+		if when.Matched {
+			idx = 1
+		}
+		if unconditional[idx] {
+			panic(pgerror.Newf(pgcode.Syntax,
+				"unreachable WHEN clause specified after unconditional WHEN clause"))
+		}
+		unconditional[idx] = when.Cond == nil
+	}
+}
+
+// checkMergeUpdateKeys raises an error if an UPDATE action of a MERGE statement
+// can change the index keys of the rows it updates, and other actions also
+// mutate the target table. The actions read the target table in the same step,
+// so their writes to the same key could silently overwrite each other instead
+// of raising a uniqueness violation (see #70731). For example, an UPDATE action
+// could change the primary key of a row to the key of a row inserted by a NOT
+// MATCHED action.
+//
+// As with checkMultipleMutations, the check can be disabled through
+// sql.multiple_modifications_of_table.enabled.
+func (b *Builder) checkMergeUpdateKeys(tab cat.Table, whens tree.MergeWhens) {
+	if multipleModificationsOfTableEnabled.Get(&b.evalCtx.Settings.SV) ||
+		b.evalCtx.SessionData().MultipleModificationsOfTable {
+		return
+	}
+	var numMutations int
+	updated := make(map[tree.Name]struct{})
+	for _, when := range whens {
+		if when.Action == tree.MergeDoNothing {
+			continue
+		}
+		numMutations++
+		if when.Action == tree.MergeUpdate {
+			for _, expr := range when.Exprs {
+				for _, name := range expr.Names {
+					updated[name] = struct{}{}
+				}
+			}
+		}
+	}
+	if numMutations < 2 || len(updated) == 0 {
+		return
+	}
+
+	// Computed columns that depend on the updated columns are updated as well.
+	for changed := true; changed; {
+		changed = false
+		for i, n := 0, tab.ColumnCount(); i < n; i++ {
+			col := tab.Column(i)
+			if _, ok := updated[col.ColName()]; ok || !col.IsComputed() {
+				continue
+			}
+			if referencesMergeCols(col.ComputedExprStr(), updated) {
+				updated[col.ColName()] = struct{}{}
+				changed = true
+			}
+		}
+	}
+
+	for i, n := 0, tab.DeletableIndexCount(); i < n; i++ {
+		index := tab.Index(i)
+		touched := false
+		for j, m := 0, index.KeyColumnCount(); j < m && !touched; j++ {
+			_, touched = updated[index.Column(j).ColName()]
+		}
+		if pred, isPartial := index.Predicate(); isPartial && !touched {
+			// Changing a column of the predicate adds or removes index entries.
+			touched = referencesMergeCols(pred, updated)
+		}
+		if touched {
+			panic(pgerror.Newf(pgcode.FeatureNotSupported,
+				"MERGE statements with an UPDATE action that modifies columns of index %q "+
+					"and other actions that mutate table %q are not supported; this is to "+
+					"prevent data corruption, see documentation of "+
+					"sql.multiple_modifications_of_table.enabled", index.Name(), tab.Name(),
+			))
+		}
+	}
+}
+
+// referencesMergeCols returns true if the given expression references any of
+// the given columns.
+func referencesMergeCols(exprStr string, cols map[tree.Name]struct{}) bool {
+	expr, err := parser.ParseExpr(exprStr)
+	if err != nil {
+		panic(err)
+	}
+	found := false
+	_, _ = tree.SimpleVisit(expr, func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		if n, ok := expr.(*tree.UnresolvedName); ok && !found {
+			_, found = cols[tree.Name(n.Parts[0])]
+		}
+		return !found, expr, nil
+	})
+	return found
+}
+
+// mergeSource returns the source of a MERGE statement along with the name
+// through which the actions refer to its columns. Sources without a name of
+// their own, such as subqueries without an alias and joins, are given one.
+func mergeSource(source tree.TableExpr) (tree.TableExpr, tree.Name) {
+	source = tree.StripTableParens(source)
+	if join, ok := source.(*tree.JoinTableExpr); ok {
+		return join, mergeSourceName
+	}
+	ate, ok := source.(*tree.AliasedTableExpr)
+	if !ok {
+		panic(errors.AssertionFailedf("unexpected MERGE source %T", source))
+	}
+	if ate.As.Alias != "" {
+		return ate, ate.As.Alias
+	}
+	switch t := ate.Expr.(type) {
+	case *tree.TableName:
+		return ate, t.ObjectName
+	case *tree.TableRef:
+		if t.As.Alias != "" {
+			return ate, t.As.Alias
+		}
+	}
+	aliased := *ate
+	aliased.As.Alias = mergeSourceName
+	return &aliased, mergeSourceName
+}
+
+// mergeSourceRel is a relation joined by the source of a MERGE statement.
+type mergeSourceRel struct {
+	// name is the name through which the columns of the relation are
+	// referenced.
+	name tree.Name
+	cols tree.NameList
+}
+
+// mergeJoinSourceRels returns the relations joined by a join source of a MERGE
+// statement. Only tables, views and CTEs can be joined, and they must be joined
+// with an ON condition, so that the columns of each relation are known by name.
+func (b *Builder) mergeJoinSourceRels(source tree.TableExpr, inScope *scope) []mergeSourceRel {
+	switch t := source.(type) {
+	case *tree.ParenTableExpr:
+		return b.mergeJoinSourceRels(t.Expr, inScope)
+
+	case *tree.JoinTableExpr:
+		switch t.Cond.(type) {
+		case *tree.UsingJoinCond, tree.NaturalJoinCond:
+			panic(unimplemented.New("merge join source using",
+				"MERGE with a USING or NATURAL join as its source is not supported; use ON instead"))
+		}
+		left := b.mergeJoinSourceRels(t.Left, inScope)
+		return append(left, b.mergeJoinSourceRels(t.Right, inScope)...)
+
+	case *tree.AliasedTableExpr:
+		tn, ok := t.Expr.(*tree.TableName)
+		if !ok || t.Lateral {
+			break
+		}
+		rel := mergeSourceRel{name: tn.ObjectName}
+		if cte := inScope.resolveCTE(tn); cte != nil {
+			for i := range cte.cols {
+				rel.cols = append(rel.cols, tree.Name(cte.cols[i].Alias))
+			}
+		} else {
+			ds, _, _ := b.resolveDataSource(tn, privilege.SELECT)
+			switch ds := ds.(type) {
+			case cat.Table:
+				for i, n := 0, ds.ColumnCount(); i < n; i++ {
+					if col := ds.Column(i); col.Visibility() == cat.Visible {
+						rel.cols = append(rel.cols, col.ColName())
+					}
+				}
+			case cat.View:
+				for i, n := 0, ds.ColumnNameCount(); i < n; i++ {
+					rel.cols = append(rel.cols, ds.ColumnName(i))
+				}
+			default:
+				panic(unimplemented.New("merge join source",
+					"MERGE with a join of sequences as its source is not supported"))
+			}
+		}
+		if t.As.Alias != "" {
+			rel.name = t.As.Alias
+			for i := range t.As.Cols {
+				if i < len(rel.cols) {
+					rel.cols[i] = t.As.Cols[i].Name
+				}
+			}
+		}
+		return []mergeSourceRel{rel}
+	}
+	panic(unimplemented.New("merge join source",
+		"MERGE with a join of subqueries or functions as its source is not supported; "+
+			"use a subquery for the whole source instead"))
+}
+
+// mergeBuilder constructs the statements that a MERGE statement is built
+// from. See buildMerge for details.
+type mergeBuilder struct {
+	merge *tree.Merge
+	tab   cat.Table
+
+	// targetName and sourceName are the names through which the columns of the
+	// target and source are referenced.
+	targetName tree.Name
+	sourceName tree.Name
+	source     tree.TableExpr
+
+	// sourceRels are the relations joined by a join source. It is empty for
+	// any other source.
+	sourceRels []mergeSourceRel
+
+	// keyCols are the primary key columns of the target table.
+	keyCols tree.NameList
+
+	// actions are the mutations built by actionsSelect.
+	actions []tree.Statement
+}
+
+// actionCodes returns the ordinals of the WHEN clauses that mutate the target
+// table and that apply to matched (or not matched) source rows.
+func (mb *mergeBuilder) actionCodes(matched bool) tree.Exprs {
+	var codes tree.Exprs
+	for i, when := range mb.merge.Whens {
+		if when.Matched == matched && when.Action != tree.MergeDoNothing {
+			codes = append(codes, mergeActionCode(i))
+		}
+	}
+	return codes
+}
+
+func mergeActionCode(i int) tree.Expr {
+	return tree.NewDInt(tree.DInt(i + 1))
+}
+
+func mergeKeyColName(i int) tree.Name {
+	return tree.Name(fmt.Sprintf("%s%d", mergeKeyColPrefix, i+1))
+}
+
+func mergeSrcColName(i int) tree.Name {
+	return tree.Name(fmt.Sprintf("%s%d", mergeSrcColPrefix, i+1))
+}
+
+// sourceSelect returns the left join of the source with the target, which
+// projects the source columns, the primary key of the matched target row and
+// the action for each source row.
+func (mb *mergeBuilder) sourceSelect() *tree.Select {
+	var exprs tree.SelectExprs
+	if len(mb.sourceRels) == 0 {
+		exprs = tree.SelectExprs{{
+			Expr: &tree.UnresolvedName{
+				Star: true, NumParts: 2, Parts: tree.NameParts{"", string(mb.sourceName)},
+			},
+		}}
+	}
+	for _, rel := range mb.sourceRels {
+		for _, col := range rel.cols {
+			exprs = append(exprs, tree.SelectExpr{
+				Expr: tree.NewUnresolvedName(string(rel.name), string(col)),
+				As:   tree.UnrestrictedName(mergeSrcColName(len(exprs))),
+			})
+		}
+	}
+	for i, col := range mb.keyCols {
+		exprs = append(exprs, tree.SelectExpr{
+			Expr: tree.NewUnresolvedName(string(mb.targetName), string(col)),
+			As:   tree.UnrestrictedName(mergeKeyColName(i)),
+		})
+	}
+
+	action := &tree.CaseExpr{}
+	matchedKey := tree.NewUnresolvedName(string(mb.targetName), string(mb.keyCols[0]))
+	for i, when := range mb.merge.Whens {
+		var cond tree.Expr
+		if when.Matched {
+			cond = &tree.IsNotNullExpr{Expr: matchedKey}
+		} else {
+			cond = &tree.IsNullExpr{Expr: matchedKey}
+		}
+		if when.Cond != nil {
+			cond = &tree.AndExpr{Left: cond, Right: &tree.ParenExpr{Expr: when.Cond}}
+		}
+		action.Whens = append(action.Whens, &tree.When{Cond: cond, Val: mergeActionCode(i)})
+	}
+	exprs = append(exprs, tree.SelectExpr{Expr: action, As: mergeActionColName})
+
+	return &tree.Select{Select: &tree.SelectClause{
+		Exprs: exprs,
+		From: tree.From{Tables: tree.TableExprs{&tree.JoinTableExpr{
+			JoinType: tree.AstLeft,
+			Left:     mb.source,
+			Right:    mb.merge.Table,
+			Cond:     &tree.OnJoinCond{Expr: mb.merge.On},
+		}}},
+	}}
+}
+
+// dupSelect returns a projection on top of sourceSelect that repeats the
+// primary key of the matched target row for source rows whose action is one of
+// the given codes, and is NULL otherwise.
+func (mb *mergeBuilder) dupSelect(codes tree.Exprs) *tree.Select {
+	exprs := tree.SelectExprs{tree.StarSelectExpr()}
+	for i := range mb.keyCols {
+		exprs = append(exprs, tree.SelectExpr{
+			Expr: &tree.CaseExpr{Whens: []*tree.When{{
+				Cond: &tree.ComparisonExpr{
+					Operator: treecmp.MakeComparisonOperator(treecmp.In),
+					Left:     tree.NewUnresolvedName(mergeActionColName),
+					Right:    &tree.Tuple{Exprs: codes},
+				},
+				Val: tree.NewUnresolvedName(string(mergeKeyColName(i))),
+			}}},
+			As: tree.UnrestrictedName(fmt.Sprintf("%s%d", mergeDupColPrefix, i+1)),
+		})
+	}
+	return &tree.Select{Select: &tree.SelectClause{
+		Exprs: exprs,
+		From: tree.From{Tables: tree.TableExprs{&tree.AliasedTableExpr{
+			Expr: &tree.Subquery{Select: &tree.ParenSelect{Select: mb.sourceSelect()}},
+			As:   tree.AliasClause{Alias: mb.sourceName},
+		}}},
+	}}
+}
+
+// actionsSelect returns the statement that runs the mutation of each WHEN
+// clause and returns either the RETURNING rows of all mutations or the total
+// number of affected rows.
+func (mb *mergeBuilder) actionsSelect() *tree.Select {
+	// Deletes run first and inserts last, which keeps the order of the
+	// mutations independent of the order of the WHEN clauses.
+	var with tree.With
+	for _, action := range []tree.MergeActionType{
+		tree.MergeDelete, tree.MergeUpdate, tree.MergeInsert,
+	} {
+		for i, when := range mb.merge.Whens {
+			if when.Action != action {
+				continue
+			}
+			stmt := mb.actionStmt(i, when)
+			mb.actions = append(mb.actions, stmt)
+			with.CTEList = append(with.CTEList, &tree.CTE{
+				Name: tree.AliasClause{Alias: tree.Name(fmt.Sprintf("crdb_internal_merge_%d", i+1))},
+				Stmt: stmt,
+			})
+		}
+	}
+
+	var sel tree.SelectStatement
+	for _, cte := range with.CTEList {
+		branch := &tree.Select{Select: &tree.SelectClause{
+			Exprs: tree.SelectExprs{tree.StarSelectExpr()},
+			From: tree.From{Tables: tree.TableExprs{&tree.AliasedTableExpr{
+				Expr: tree.NewUnqualifiedTableName(cte.Name.Alias),
+			}}},
+		}}
+		if sel == nil {
+			sel = branch.Select
+			continue
+		}
+		sel = &tree.UnionClause{
+			Type:  tree.UnionOp,
+			Left:  &tree.Select{Select: sel},
+			Right: branch,
+			All:   true,
+		}
+	}
+
+	returning := resultsNeeded(mb.merge.Returning)
+	if sel == nil {
+		// None of the WHEN clauses mutate the table.
+		if !returning {
+			return &tree.Select{Select: &tree.SelectClause{
+				Exprs: tree.SelectExprs{{Expr: tree.NewDInt(0)}},
+			}}
+		}
+		return &tree.Select{Select: &tree.SelectClause{
+			Exprs: mb.returningExprs(),
+			From:  tree.From{Tables: append(mb.sourceTables(), mb.merge.Table)},
+			Where: tree.NewWhere(tree.AstWhere, tree.DBoolFalse),
+		}}
+	}
+	if !returning {
+		sel = &tree.SelectClause{
+			Exprs: tree.SelectExprs{{Expr: &tree.FuncExpr{
+				Func:  tree.WrapFunction("count"),
+				Exprs: tree.Exprs{tree.StarExpr()},
+			}}},
+			From: tree.From{Tables: tree.TableExprs{&tree.AliasedTableExpr{
+				Expr: &tree.Subquery{Select: &tree.ParenSelect{Select: &tree.Select{Select: sel}}},
+				As:   tree.AliasClause{Alias: "crdb_internal_merge_rows"},
+			}}},
+		}
+	}
+	return &tree.Select{With: &with, Select: sel}
+}
+
+// actionStmt returns the mutation for the i-th WHEN clause.
+func (mb *mergeBuilder) actionStmt(i int, when *tree.MergeWhen) tree.Statement {
+	isAction := &tree.ComparisonExpr{
+		Operator: treecmp.MakeComparisonOperator(treecmp.EQ),
+		Left:     tree.NewUnresolvedName(string(mb.sourceName), mergeActionColName),
+		Right:    mergeActionCode(i),
+	}
+	if when.Action == tree.MergeInsert {
+		return mb.insertStmt(when, isAction)
+	}
+
+	// Matched rows are found through the primary key of the target row.
+	var where tree.Expr = isAction
+	for j, col := range mb.keyCols {
+		where = &tree.AndExpr{
+			Left: &tree.ComparisonExpr{
+				Operator: treecmp.MakeComparisonOperator(treecmp.EQ),
+				Left:     tree.NewUnresolvedName(string(mb.targetName), string(col)),
+				Right:    tree.NewUnresolvedName(string(mb.sourceName), string(mergeKeyColName(j))),
+			},
+			Right: where,
+		}
+	}
+	if when.Action == tree.MergeDelete {
+		return &tree.Delete{
+			Table:     mb.merge.Table,
+			Using:     mb.sourceTables(),
+			Where:     tree.NewWhere(tree.AstWhere, where),
+			Returning: mb.returning(),
+		}
+	}
+	return &tree.Update{
+		Table:     mb.merge.Table,
+		Exprs:     when.Exprs,
+		From:      mb.sourceTables(),
+		Where:     tree.NewWhere(tree.AstWhere, where),
+		Returning: mb.returning(),
+	}
+}
+
+// insertStmt returns the INSERT for a WHEN NOT MATCHED clause. The values are
+// computed by a SELECT over the source rows, which does not allow DEFAULT, so
+// any DEFAULT values are dropped together with their target columns.
+func (mb *mergeBuilder) insertStmt(when *tree.MergeWhen, isAction tree.Expr) tree.Statement {
+	cols := when.Columns
+	hasDefault := false
+	for _, val := range when.Values {
+		if _, ok := val.(tree.DefaultVal); ok {
+			hasDefault = true
+		}
+	}
+	if hasDefault && len(cols) == 0 {
+		// Target the columns that the values are implicitly assigned to.
+		for i, n := 0, mb.tab.ColumnCount(); i < n && len(cols) < len(when.Values); i++ {
+			col := mb.tab.Column(i)
+			if col.Kind() == cat.Ordinary && col.Visibility() == cat.Visible {
+				cols = append(cols, col.ColName())
+			}
+		}
+		if len(cols) < len(when.Values) {
+			panic(pgerror.Newf(pgcode.Syntax,
+				"INSERT has more expressions than target columns, %d expressions for %d targets",
+				len(when.Values), len(cols)))
+		}
+	}
+	var exprs tree.SelectExprs
+	var targets tree.NameList
+	for i, val := range when.Values {
+		if _, ok := val.(tree.DefaultVal); ok {
+			continue
+		}
+		exprs = append(exprs, tree.SelectExpr{Expr: val})
+		if i < len(cols) {
+			targets = append(targets, cols[i])
+		}
+	}
+	if !hasDefault {
+		targets = cols
+	}
+
+	// The target table cannot have index flags in an INSERT.
+	table := mb.merge.Table
+	if ate, ok := table.(*tree.AliasedTableExpr); ok {
+		table = &tree.AliasedTableExpr{Expr: ate.Expr, As: ate.As}
+	}
+	return &tree.Insert{
+		Table:   table,
+		Columns: targets,
+		Rows: &tree.Select{Select: &tree.SelectClause{
+			Exprs: exprs,
+			From:  tree.From{Tables: mb.sourceTables()},
+			Where: tree.NewWhere(tree.AstWhere, isAction),
+		}},
+		Returning: mb.returning(),
+	}
+}
+
+// sourceTables returns a reference to the source rows CTE under the name of
+// the source. For a join source, it also returns a LATERAL subquery for each
+// joined relation, which exposes the columns of the relation under their
+// original names:
+//
+//	crdb_internal_merge_source,
+//	LATERAL (
+//	  SELECT crdb_internal_merge_source.crdb_internal_merge_src_1 AS a, ...
+//	) AS s1,
+//	...
+func (mb *mergeBuilder) sourceTables() tree.TableExprs {
+	tables := tree.TableExprs{&tree.AliasedTableExpr{
+		Expr: tree.NewUnqualifiedTableName(mergeSourceName),
+		As:   tree.AliasClause{Alias: mb.sourceName},
+	}}
+	n := 0
+	for _, rel := range mb.sourceRels {
+		exprs := make(tree.SelectExprs, len(rel.cols))
+		for i, col := range rel.cols {
+			exprs[i] = tree.SelectExpr{
+				Expr: tree.NewUnresolvedName(string(mb.sourceName), string(mergeSrcColName(n))),
+				As:   tree.UnrestrictedName(col),
+			}
+			n++
+		}
+		tables = append(tables, &tree.AliasedTableExpr{
+			Lateral: true,
+			Expr: &tree.Subquery{Select: &tree.ParenSelect{Select: &tree.Select{
+				Select: &tree.SelectClause{Exprs: exprs},
+			}}},
+			As: tree.AliasClause{Alias: rel.name},
+		})
+	}
+	return tables
+}
+
+// returning returns the RETURNING clause of each mutation. Without a RETURNING
+// clause on the MERGE statement, each mutation returns a row per affected row
+// so that they can be counted.
+func (mb *mergeBuilder) returning() tree.ReturningClause {
+	if !resultsNeeded(mb.merge.Returning) {
+		return &tree.ReturningExprs{{Expr: tree.NewDInt(1)}}
+	}
+	exprs := tree.ReturningExprs(mb.returningExprs())
+	return &exprs
+}
+
+// returningExprs returns the expressions of the RETURNING clause, in which an
+// unqualified star refers to the target columns only, since the inserted rows
+// have no source columns to return.
+func (mb *mergeBuilder) returningExprs() tree.SelectExprs {
+	ret := *mb.merge.Returning.(*tree.ReturningExprs)
+	exprs := make(tree.SelectExprs, len(ret))
+	for i, expr := range ret {
+		exprs[i] = expr
+		switch t := expr.Expr.(type) {
+		case tree.UnqualifiedStar:
+		case *tree.UnresolvedName:
+			if !t.Star || t.NumParts != 1 {
+				continue
+			}
+		default:
+			continue
+		}
+		exprs[i].Expr = &tree.UnresolvedName{
+			Star: true, NumParts: 2, Parts: tree.NameParts{"", string(mb.targetName)},
+		}
+	}
+	return exprs
+}
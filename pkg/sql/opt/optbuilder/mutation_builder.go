@@ -76,6 +76,12 @@ type mutationBuilder struct {
 	// targetColSet contains the same column IDs as targetColList, but as a set.
 	targetColSet opt.ColSet
 
+	// triggerUpdateCols contains the table columns explicitly targeted by the
+	// SET clause of an UPDATE. Unlike targetColSet, it does not include columns
+	// with synthesized values. It is used to determine which UPDATE OF triggers
+	// are fired.
+	triggerUpdateCols opt.ColSet
+
 	// insertColIDs lists the input column IDs providing values to insert. Its
 	// length is always equal to the number of columns in the target table,
 	// including mutation columns. Table columns which will not have values
@@ -1413,8 +1419,10 @@ func (mb *mutationBuilder) addAssignmentCasts(srcCols opt.OptionalColList) {
 		targetType := mb.tab.Column(ord).DatumType()
 
 		// An assignment cast is not necessary if the source and target types
-		// are identical.
-		if srcType.Identical(targetType) {
+		// are identical. The constraints of a domain must still be checked,
+		// since values are often typed as the domain of the target column
+		// without being cast to it.
+		if srcType.Identical(targetType) && !targetType.IsDomain() {
 			continue
 		}
 
@@ -1425,8 +1433,11 @@ func (mb *mutationBuilder) addAssignmentCasts(srcCols opt.OptionalColList) {
 		}
 
 		// Create the cast expression.
-		variable := mb.b.factory.ConstructVariable(colID)
-		cast := mb.b.factory.ConstructAssignmentCast(variable, targetType)
+		var cast opt.ScalarExpr = mb.b.factory.ConstructVariable(colID)
+		if !srcType.Identical(targetType) {
+			cast = mb.b.factory.ConstructAssignmentCast(cast, targetType)
+		}
+		cast = mb.b.buildDomainChecks(cast, targetType)
 
 		// Lazily create the new scope.
 		if projectionScope == nil {
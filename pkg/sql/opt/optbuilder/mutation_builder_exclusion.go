@@ -0,0 +1,220 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// buildExclusionChecksForInsert builds exclusion check queries for an insert.
+// These check queries are used to enforce EXCLUDE constraints.
+func (mb *mutationBuilder) buildExclusionChecksForInsert() {
+	for i, n := 0, mb.tab.ExclusionConstraintCount(); i < n; i++ {
+		mb.buildExclusionCheck(i)
+	}
+}
+
+// buildExclusionChecksForUpdate builds exclusion check queries for an update.
+// These check queries are used to enforce EXCLUDE constraints.
+func (mb *mutationBuilder) buildExclusionChecksForUpdate() {
+	if mb.tab.ExclusionConstraintCount() == 0 {
+		return
+	}
+
+	mb.ensureWithID()
+	for i, n := 0, mb.tab.ExclusionConstraintCount(); i < n; i++ {
+		// If this constraint doesn't include the updated columns we don't need to
+		// plan a check.
+		if !mb.exclusionColsUpdated(i) {
+			continue
+		}
+		mb.buildExclusionCheck(i)
+	}
+}
+
+// buildExclusionChecksForUpsert builds exclusion check queries for an upsert.
+// These check queries are used to enforce EXCLUDE constraints.
+func (mb *mutationBuilder) buildExclusionChecksForUpsert() {
+	if mb.tab.ExclusionConstraintCount() == 0 {
+		return
+	}
+
+	mb.ensureWithID()
+	for i, n := 0, mb.tab.ExclusionConstraintCount(); i < n; i++ {
+		mb.buildExclusionCheck(i)
+	}
+}
+
+// exclusionColsUpdated returns true if any of the columns of an exclusion
+// constraint are being updated (according to updateColIDs). When the
+// constraint has a partial predicate, it also returns true if the predicate
+// references any of the columns being updated.
+func (mb *mutationBuilder) exclusionColsUpdated(exclusionOrdinal int) bool {
+	ec := mb.tab.ExclusionConstraint(exclusionOrdinal)
+
+	for i, n := 0, ec.ColumnCount(); i < n; i++ {
+		if ord := ec.ColumnOrdinal(mb.tab, i); mb.updateColIDs[ord] != 0 {
+			return true
+		}
+	}
+
+	if predStr, isPartial := ec.Predicate(); isPartial {
+		pred, err := parser.ParseExpr(predStr)
+		if err != nil {
+			panic(err)
+		}
+		typedPred := mb.fetchScope.resolveAndRequireType(pred, types.Bool)
+
+		var predCols opt.ColSet
+		mb.b.buildScalar(typedPred, mb.fetchScope, nil, nil, &predCols)
+		for colID, ok := predCols.Next(0); ok; colID, ok = predCols.Next(colID + 1) {
+			ord := mb.md.ColumnMeta(colID).Table.ColumnOrdinal(colID)
+			if mb.updateColIDs[ord] != 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// buildExclusionCheck builds a check query for the exclusion constraint with
+// the given ordinal, and adds it to the unique checks of the mutation. The
+// check is built like the check of a UNIQUE WITHOUT INDEX constraint: it is a
+// self semi-join of the new rows with the rows of the table, except that the
+// join compares the columns with the operators of the constraint instead of
+// with equality. For example, for the constraint:
+//
+//	EXCLUDE USING GIST (room WITH =, area WITH &&)
+//
+// the join filters are:
+//
+//	(new_room = existing_room) AND (new_area && existing_area) AND
+//	  ((new_pk1 != existing_pk1) OR (new_pk2 != existing_pk2) OR ...)
+//
+// Since the scanned table includes the new rows, conflicts between two of the
+// new rows are also detected.
+func (mb *mutationBuilder) buildExclusionCheck(exclusionOrdinal int) {
+	f := mb.b.factory
+	ec := mb.tab.ExclusionConstraint(exclusionOrdinal)
+
+	// If a column is getting a NULL value, like when this mutation is the result
+	// of a SET NULL cascade action, the new rows cannot conflict with any row,
+	// since the comparison of a NULL value is never true.
+	for i, n := 0, ec.ColumnCount(); i < n; i++ {
+		colID := mb.mapToReturnColID(ec.ColumnOrdinal(mb.tab, i))
+		if memo.OutputColumnIsAlwaysNull(mb.outScope.expr, colID) {
+			return
+		}
+	}
+
+	// The scan of the table is built in the same way as the scan of a unique
+	// check, so that it obtains predicate locks under weaker isolation levels.
+	h := uniqueCheckHelper{mb: mb}
+	scanScope, scanOrdinals := h.buildTableScan()
+	checkScope, _ := mb.buildCheckInputScan(
+		checkInputScanNewVals, scanOrdinals, false, /* isFK */
+	)
+
+	// Build the join filters. Add 3 to the capacity for the primary key filter
+	// and the partial predicate filters, if any.
+	semiJoinFilters := make(memo.FiltersExpr, 0, ec.ColumnCount()+3)
+	keyCols := make(opt.ColList, ec.ColumnCount())
+	for i, n := 0, ec.ColumnCount(); i < n; i++ {
+		ord := ec.ColumnOrdinal(mb.tab, i)
+		newCol, existingCol := &checkScope.cols[ord], &scanScope.cols[ord]
+		cmp := mb.b.constructComparison(
+			exclusionComparison(ec.Operator(i), newCol.typ),
+			f.ConstructVariable(newCol.id),
+			f.ConstructVariable(existingCol.id),
+		)
+		semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(cmp))
+		keyCols[i] = newCol.id
+	}
+
+	// If the constraint is partial, filter out both the new rows and the
+	// existing rows that do not satisfy its predicate.
+	if predStr, isPartial := ec.Predicate(); isPartial {
+		pred, err := parser.ParseExpr(predStr)
+		if err != nil {
+			panic(err)
+		}
+		typedPred := checkScope.resolveAndRequireType(pred, types.Bool)
+		checkPred := mb.b.buildScalar(typedPred, checkScope, nil, nil, nil)
+		semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(checkPred))
+
+		typedPred = scanScope.resolveAndRequireType(pred, types.Bool)
+		scanPred := mb.b.buildScalar(typedPred, scanScope, nil, nil, nil)
+		semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(scanPred))
+	}
+
+	// Prevent rows from matching themselves in the semi join. Unlike unique
+	// checks, this is needed even if the primary key columns are a subset of the
+	// constraint columns, since a row always overlaps itself.
+	var pkFilter opt.ScalarExpr
+	primaryOrds := getIndexLaxKeyOrdinals(mb.tab.Index(cat.PrimaryIndex))
+	for i, ok := primaryOrds.Next(0); ok; i, ok = primaryOrds.Next(i + 1) {
+		pkFilterLocal := f.ConstructNe(
+			f.ConstructVariable(checkScope.cols[i].id),
+			f.ConstructVariable(scanScope.cols[i].id),
+		)
+		if pkFilter == nil {
+			pkFilter = pkFilterLocal
+		} else {
+			pkFilter = f.ConstructOr(pkFilter, pkFilterLocal)
+		}
+	}
+	semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(pkFilter))
+
+	joinPrivate := memo.EmptyJoinPrivate
+	// If we're using a weaker isolation level, the semi-joined scan needs to
+	// obtain predicate locks, which requires a lookup semi-join.
+	if mb.b.evalCtx.TxnIsoLevel != isolation.Serializable {
+		joinPrivate = &memo.JoinPrivate{
+			Flags: memo.PreferLookupJoinIntoRight,
+		}
+	}
+
+	semiJoin := f.ConstructSemiJoin(checkScope.expr, scanScope.expr, semiJoinFilters, joinPrivate)
+
+	// Pass through only the key columns, which are shown in the error message.
+	project := f.ConstructProject(semiJoin, nil /* projections */, keyCols.ToSet())
+
+	mb.uniqueChecks = append(mb.uniqueChecks, f.ConstructUniqueChecksItem(
+		project, &memo.UniqueChecksItemPrivate{
+			Table:        mb.tabID,
+			CheckOrdinal: exclusionOrdinal,
+			KeyCols:      keyCols,
+			Exclusion:    true,
+		},
+	))
+}
+
+// exclusionComparison returns a comparison expression with the given operator
+// and its overload for two operands of the given type, which is passed to
+// constructComparison to build a comparison of two columns of that type.
+func exclusionComparison(op treecmp.ComparisonOperator, typ *types.T) *tree.ComparisonExpr {
+	cmp := &tree.ComparisonExpr{Operator: op}
+	// Some operators, like !=, are implemented with the overloads of another
+	// operator.
+	folded, _, _, _, _ := tree.FoldComparisonExpr(op, nil /* left */, nil /* right */)
+	if overloads, ok := tree.CmpOps[folded.Symbol]; ok {
+		cmp.Op, _ = overloads.LookupImpl(typ, typ)
+	}
+	return cmp
+}
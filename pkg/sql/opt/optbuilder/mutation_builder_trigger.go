@@ -0,0 +1,717 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// This file contains methods that build the invocations of triggers defined on
+// the target table of a mutation.
+//
+// Row-level BEFORE triggers are built inline, as part of the mutation input.
+// Each trigger function is invoked once for every row, and is passed the NEW
+// and OLD rows as tuples. The row returned by the function replaces NEW, and
+// the row is skipped entirely if the function returns NULL:
+//
+//	select
+//	 ├── barrier
+//	 │    └── project
+//	 │         ├── <mutation input>
+//	 │         └── projections
+//	 │              └── f(new, old, ...) [as=tr_result]
+//	 └── filters
+//	      └── tr_result IS DISTINCT FROM NULL
+//
+// Row-level and statement-level AFTER triggers are built lazily, after the
+// mutation has executed, using the same mechanism as foreign key cascades. See
+// afterTriggerBuilder.
+//
+// Statement-level BEFORE triggers are built as a With binding that wraps the
+// mutation, which ensures that they are executed before the mutation starts.
+
+// triggerRowType returns the composite type of the NEW and OLD rows that are
+// passed to a trigger function for the given table, along with the ordinals of
+// the table columns that make up the row. Only visible, ordinary columns are
+// included.
+func triggerRowType(tab cat.Table) (*types.T, []int) {
+	var ords []int
+	var contents []*types.T
+	var labels []string
+	for i, n := 0, tab.ColumnCount(); i < n; i++ {
+		col := tab.Column(i)
+		if col.Kind() != cat.Ordinary || col.Visibility() != cat.Visible {
+			continue
+		}
+		ords = append(ords, i)
+		contents = append(contents, col.DatumType())
+		labels = append(labels, string(col.ColName()))
+	}
+	return types.MakeLabeledTuple(contents, labels), ords
+}
+
+// findTriggers returns the enabled triggers on the given table that fire at
+// the given time for the given event. The triggers are returned in the order
+// in which they must be fired, which is alphabetical by name. Triggers that
+// are restricted to a list of columns (UPDATE OF ...) are only returned if one
+// of those columns is in updatedCols.
+func findTriggers(
+	tab cat.Table,
+	actionTime tree.TriggerActionTime,
+	eventType tree.TriggerEventType,
+	forEachRow bool,
+	updatedCols opt.ColSet,
+	tabID opt.TableID,
+) []cat.Trigger {
+	var triggers []cat.Trigger
+	for i := 0; i < tab.TriggerCount(); i++ {
+		trigger := tab.Trigger(i)
+		if !trigger.Enabled() || trigger.ActionTime() != actionTime ||
+			trigger.ForEachRow() != forEachRow {
+			continue
+		}
+		for j := 0; j < trigger.EventCount(); j++ {
+			event := trigger.Event(j)
+			if event.EventType != eventType {
+				continue
+			}
+			if len(event.Columns) > 0 {
+				var found bool
+				for _, name := range event.Columns {
+					for k := 0; k < tab.ColumnCount() && !found; k++ {
+						found = tab.Column(k).ColName() == name && updatedCols.Contains(tabID.ColumnID(k))
+					}
+				}
+				if !found {
+					continue
+				}
+			}
+			triggers = append(triggers, trigger)
+			break
+		}
+	}
+	return triggers
+}
+
+// hasUpsertTriggers returns true if the given table has any enabled triggers
+// for the events of an UPSERT or INSERT ... ON CONFLICT statement.
+func hasUpsertTriggers(tab cat.Table) bool {
+	for i := 0; i < tab.TriggerCount(); i++ {
+		trigger := tab.Trigger(i)
+		if !trigger.Enabled() {
+			continue
+		}
+		if cat.HasTriggerEvent(trigger, tree.TriggerEventInsert) ||
+			cat.HasTriggerEvent(trigger, tree.TriggerEventUpdate) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildTriggerRows projects the NEW and OLD rows for a trigger invocation as
+// tuple-typed columns named "new" and "old". newCols and oldCols map each field
+// of the row type to the input column that provides its value, or to 0 if the
+// field is NULL. If newCols (or oldCols) is nil, the entire NEW (or OLD) row is
+// NULL. The IDs of the projected columns are returned.
+func (b *Builder) buildTriggerRows(
+	s *scope, rowType *types.T, newCols, oldCols opt.OptionalColList,
+) (newColID, oldColID opt.ColumnID) {
+	f := b.factory
+	makeRow := func(cols opt.OptionalColList) opt.ScalarExpr {
+		if cols == nil {
+			return f.ConstructNull(rowType)
+		}
+		elems := make(memo.ScalarListExpr, len(cols))
+		for i, col := range cols {
+			if col == 0 {
+				elems[i] = f.ConstructNull(rowType.TupleContents()[i])
+			} else {
+				elems[i] = f.ConstructVariable(col)
+			}
+		}
+		return f.ConstructTuple(elems, rowType)
+	}
+	projectionsScope := s.replace()
+	projectionsScope.appendColumnsFromScope(s)
+	newColID = b.synthesizeColumn(
+		projectionsScope, scopeColName("").WithMetadataName("new"), rowType, nil /* expr */, makeRow(newCols),
+	).id
+	oldColID = b.synthesizeColumn(
+		projectionsScope, scopeColName("").WithMetadataName("old"), rowType, nil /* expr */, makeRow(oldCols),
+	).id
+	b.constructProjectForScope(s, projectionsScope)
+	*s = *projectionsScope
+	return newColID, oldColID
+}
+
+// triggerWhenScope returns a scope in which the WHEN condition of a trigger can
+// be resolved. It contains the given NEW and OLD row columns, if non-zero.
+func (b *Builder) triggerWhenScope(rowType *types.T, newColID, oldColID opt.ColumnID) *scope {
+	whenScope := b.allocScope()
+	whenScope.context = exprKindTriggerWhen
+	if newColID != 0 {
+		whenScope.cols = append(whenScope.cols, scopeColumn{
+			name: scopeColName("new"), typ: rowType, id: newColID,
+		})
+	}
+	if oldColID != 0 {
+		whenScope.cols = append(whenScope.cols, scopeColumn{
+			name: scopeColName("old"), typ: rowType, id: oldColID,
+		})
+	}
+	return whenScope
+}
+
+// resolveTriggerWhen type-checks the WHEN condition of a trigger in the given
+// scope. Subqueries, aggregates, window functions and generators are not
+// allowed.
+func (b *Builder) resolveTriggerWhen(when tree.Expr, whenScope *scope) tree.TypedExpr {
+	defer func(old bool) { b.insideTriggerWhen = old }(b.insideTriggerWhen)
+	b.insideTriggerWhen = true
+	defer b.semaCtx.Properties.Restore(b.semaCtx.Properties)
+	b.semaCtx.Properties.Require(exprKindTriggerWhen.String(), tree.RejectSpecial|tree.RejectSubqueries)
+	return whenScope.resolveAndRequireType(when, types.Bool)
+}
+
+// buildTriggerWhen builds the WHEN condition of the given trigger, if it has
+// one. It returns nil if the trigger has no WHEN condition.
+func (b *Builder) buildTriggerWhen(
+	trigger cat.Trigger, rowType *types.T, newColID, oldColID opt.ColumnID,
+) opt.ScalarExpr {
+	if trigger.WhenExpr() == "" {
+		return nil
+	}
+	when, err := parser.ParseExpr(trigger.WhenExpr())
+	if err != nil {
+		panic(err)
+	}
+	whenScope := b.triggerWhenScope(rowType, newColID, oldColID)
+	texpr := b.resolveTriggerWhen(when, whenScope)
+	return b.buildScalar(texpr, whenScope, nil /* outScope */, nil /* outCol */, nil /* colRefs */)
+}
+
+// triggerArgvName is the name of the parameter through which the arguments of
+// a trigger are passed to the trigger function.
+const triggerArgvName = tree.Name("tg_argv")
+
+// buildTriggerFunctionCall builds an invocation of the function of the given
+// trigger. The NEW and OLD rows are passed to the function along with the
+// other special TG_ variables, which the function body can reference as
+// ordinary parameters. As in Postgres, TG_ARGV is indexed starting from 0; see
+// Builder.insideTriggerFunction.
+func (b *Builder) buildTriggerFunctionCall(
+	tab cat.Table,
+	trigger cat.Trigger,
+	eventType tree.TriggerEventType,
+	rowType *types.T,
+	newColID, oldColID opt.ColumnID,
+	inScope *scope,
+) opt.ScalarExpr {
+	funcOID := catid.FuncIDToOID(catid.DescID(trigger.FuncID()))
+	fnName, o, err := b.semaCtx.FunctionResolver.ResolveFunctionByOID(b.ctx, funcOID)
+	if err != nil {
+		panic(err)
+	}
+	if err := b.catalog.CheckExecutionPrivilege(b.ctx, funcOID); err != nil {
+		panic(err)
+	}
+	if o.Language != tree.RoutineLangPLpgSQL {
+		panic(pgerror.Newf(pgcode.InvalidObjectDefinition,
+			"trigger function %s must be written in PL/pgSQL", fnName.Object(),
+		))
+	}
+	tn, err := b.catalog.FullyQualifiedName(b.ctx, tab)
+	if err != nil {
+		panic(err)
+	}
+
+	// Synthesize an overload for the trigger function that takes the trigger
+	// variables as parameters and returns the row type of the table.
+	params := tree.ParamTypes{
+		{Name: "new", Typ: rowType},
+		{Name: "old", Typ: rowType},
+		{Name: "tg_name", Typ: types.Name},
+		{Name: "tg_when", Typ: types.String},
+		{Name: "tg_level", Typ: types.String},
+		{Name: "tg_op", Typ: types.String},
+		{Name: "tg_relid", Typ: types.Oid},
+		{Name: "tg_relname", Typ: types.Name},
+		{Name: "tg_table_name", Typ: types.Name},
+		{Name: "tg_table_schema", Typ: types.Name},
+		{Name: "tg_nargs", Typ: types.Int},
+		{Name: string(triggerArgvName), Typ: types.StringArray},
+	}
+	overload := *o
+	overload.Types = params
+	overload.RoutineParams = make(tree.RoutineParams, len(params))
+	for i := range params {
+		overload.RoutineParams[i] = tree.RoutineParam{
+			Name:  tree.Name(params[i].Name),
+			Type:  params[i].Typ,
+			Class: tree.RoutineParamIn,
+		}
+	}
+	overload.ReturnType = tree.FixedReturnType(rowType)
+	overload.ReturnsRecordType = false
+	// The trigger function must be invoked even if NEW or OLD is NULL.
+	overload.CalledOnNullInput = true
+
+	level := "STATEMENT"
+	if trigger.ForEachRow() {
+		level = "ROW"
+	}
+	actionTime, op := trigger.ActionTime(), eventType
+	argv := tree.NewDArray(types.String)
+	for _, arg := range trigger.FuncArgs() {
+		if err := argv.Append(tree.NewDString(arg)); err != nil {
+			panic(err)
+		}
+	}
+	args := tree.TypedExprs{
+		inScope.getColumn(newColID),
+		inScope.getColumn(oldColID),
+		tree.NewDName(string(trigger.Name())),
+		tree.NewDString(tree.AsString(&actionTime)),
+		tree.NewDString(level),
+		tree.NewDString(tree.AsString(&op)),
+		tree.NewDOid(oid.Oid(tab.ID())),
+		tree.NewDName(string(tab.Name())),
+		tree.NewDName(string(tab.Name())),
+		tree.NewDName(tn.Schema()),
+		tree.NewDInt(tree.DInt(len(trigger.FuncArgs()))),
+		argv,
+	}
+	fn := tree.NewTypedFuncExpr(
+		tree.ResolvableFunctionReference{FunctionReference: &tree.FunctionOID{OID: funcOID}},
+		0, /* aggQualifier */
+		args,
+		nil, /* filter */
+		nil, /* windowDef */
+		rowType,
+		&overload.FunctionProperties,
+		&overload,
+	)
+	def := &tree.ResolvedFunctionDefinition{Name: fnName.Object()}
+	return b.buildRoutine(
+		fn, def, inScope, nil /* outScope */, nil /* colRefs */, true, /* isTrigger */
+	)
+}
+
+// triggerArgvIndex adjusts the given subscript of an array-typed expression if
+// the expression is a reference to TG_ARGV within a trigger function. TG_ARGV
+// is indexed starting from 0, unlike other arrays, so the subscript is shifted
+// by one. References are identified by column rather than by name, since a
+// table column named tg_argv can shadow the parameter.
+func (b *Builder) triggerArgvIndex(arr tree.Expr, index opt.ScalarExpr) opt.ScalarExpr {
+	if !b.insideTriggerFunction {
+		return index
+	}
+	if col, ok := arr.(*scopeColumn); !ok || !b.triggerArgvCols.Contains(col.id) {
+		return index
+	}
+	return b.factory.ConstructPlus(index, b.factory.ConstructConstVal(tree.NewDInt(1), types.Int))
+}
+
+// buildRowLevelBeforeTriggers builds the row-level BEFORE triggers for the
+// given event into the mutation input. For INSERT and UPDATE, the values of
+// the non-computed columns are replaced by those of the row returned by the
+// last trigger. For all events, rows for which a trigger returns NULL are
+// removed from the input, so that they are not mutated.
+//
+// For an UPSERT or INSERT ... ON CONFLICT DO UPDATE, the UPDATE triggers are
+// only fired for rows that conflict with an existing row, as determined by the
+// canary column.
+//
+// This must be called after the values of the non-computed columns have been
+// set, but before the computed columns are synthesized.
+func (mb *mutationBuilder) buildRowLevelBeforeTriggers(eventType tree.TriggerEventType) {
+	if eventType == tree.TriggerEventUpdate {
+		// Remember the columns in the SET clause (or the updated columns of an
+		// UPSERT) before any synthesized columns are added.
+		mb.triggerUpdateCols = opt.ColSet{}
+		for ord, colID := range mb.updateColIDs {
+			if colID != 0 && !mb.tab.Column(ord).IsComputed() {
+				mb.triggerUpdateCols.Add(mb.tabID.ColumnID(ord))
+			}
+		}
+	}
+	triggers := findTriggers(
+		mb.tab, tree.TriggerActionTimeBefore, eventType, true /* forEachRow */, mb.triggerUpdateCols, mb.tabID,
+	)
+	if len(triggers) == 0 {
+		return
+	}
+	rowType, ords := triggerRowType(mb.tab)
+
+	// Build the NEW and OLD rows. Computed columns have not been computed yet,
+	// so their values in NEW are NULL, as in Postgres.
+	var newCols, oldCols opt.OptionalColList
+	if eventType == tree.TriggerEventInsert || eventType == tree.TriggerEventUpdate {
+		newCols = make(opt.OptionalColList, len(ords))
+		for i, ord := range ords {
+			if mb.tab.Column(ord).IsComputed() {
+				continue
+			}
+			if eventType == tree.TriggerEventInsert {
+				newCols[i] = mb.insertColIDs[ord]
+			} else if mb.updateColIDs[ord] != 0 {
+				newCols[i] = mb.updateColIDs[ord]
+			} else {
+				newCols[i] = mb.fetchColIDs[ord]
+			}
+		}
+	}
+	if eventType == tree.TriggerEventUpdate || eventType == tree.TriggerEventDelete {
+		oldCols = make(opt.OptionalColList, len(ords))
+		for i, ord := range ords {
+			oldCols[i] = mb.fetchColIDs[ord]
+		}
+	}
+	newColID, oldColID := mb.b.buildTriggerRows(mb.outScope, rowType, newCols, oldCols)
+
+	f := mb.b.factory
+	for _, trigger := range triggers {
+		// The row returned by the trigger function. If the WHEN condition is
+		// false, the trigger is not fired and the row is passed through
+		// unchanged.
+		passthroughColID := newColID
+		if eventType == tree.TriggerEventDelete {
+			passthroughColID = oldColID
+		}
+		call := mb.b.buildTriggerFunctionCall(
+			mb.tab, trigger, eventType, rowType, newColID, oldColID, mb.outScope,
+		)
+		cond := mb.b.buildTriggerWhen(trigger, rowType, newColID, oldColID)
+		if eventType == tree.TriggerEventUpdate && mb.canaryColID != 0 {
+			// Rows that do not conflict with an existing row are inserted rather
+			// than updated, so the trigger is not fired for them.
+			conflict := f.ConstructIsNot(f.ConstructVariable(mb.canaryColID), memo.NullSingleton)
+			if cond == nil {
+				cond = conflict
+			} else {
+				cond = f.ConstructAnd(conflict, cond)
+			}
+		}
+		if cond != nil {
+			call = f.ConstructCase(
+				memo.TrueSingleton,
+				memo.ScalarListExpr{f.ConstructWhen(cond, call)},
+				f.ConstructVariable(passthroughColID),
+			)
+		}
+		projectionsScope := mb.outScope.replace()
+		projectionsScope.appendColumnsFromScope(mb.outScope)
+		resultColID := mb.b.synthesizeColumn(
+			projectionsScope,
+			scopeColName("").WithMetadataName(string(trigger.Name())),
+			rowType,
+			nil, /* expr */
+			call,
+		).id
+		mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+		mb.outScope = projectionsScope
+
+		// Prevent the trigger function calls from being reordered, duplicated, or
+		// eliminated, and filter out rows for which the trigger returned NULL.
+		mb.outScope.expr = f.ConstructSelect(
+			f.ConstructBarrier(mb.outScope.expr),
+			memo.FiltersExpr{f.ConstructFiltersItem(
+				f.ConstructIsNot(f.ConstructVariable(resultColID), f.ConstructNull(rowType)),
+			)},
+		)
+		if eventType != tree.TriggerEventDelete {
+			// Subsequent triggers see the row returned by this one.
+			newColID = resultColID
+		}
+	}
+	if eventType == tree.TriggerEventDelete {
+		return
+	}
+
+	// Project the fields of the final NEW row as the new column values.
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	for i, ord := range ords {
+		tabCol := mb.tab.Column(ord)
+		if tabCol.IsComputed() {
+			continue
+		}
+		colName := scopeColName(tabCol.ColName()).WithMetadataName(
+			string(tabCol.ColName()) + "_trigger",
+		)
+		col := mb.b.synthesizeColumn(
+			projectionsScope, colName, tabCol.DatumType(), nil, /* expr */
+			f.ConstructColumnAccess(f.ConstructVariable(newColID), memo.TupleOrdinal(i)),
+		)
+		if eventType == tree.TriggerEventInsert {
+			mb.insertColIDs[ord] = col.id
+		} else {
+			// The trigger may modify any column, so all columns are updated. Note
+			// that the target columns are not changed, since they determine which
+			// UPDATE OF triggers are fired.
+			mb.updateColIDs[ord] = col.id
+		}
+	}
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+}
+
+// buildAfterTriggers plans the row-level and statement-level AFTER triggers
+// for the given event. They are planned as post-queries that run after the
+// mutation, in the same way as foreign key cascades.
+func (mb *mutationBuilder) buildAfterTriggers(eventType tree.TriggerEventType) {
+	rowTriggers := findTriggers(
+		mb.tab, tree.TriggerActionTimeAfter, eventType, true /* forEachRow */, mb.triggerUpdateCols, mb.tabID,
+	)
+	if len(rowTriggers) > 0 {
+		_, ords := triggerRowType(mb.tab)
+		var oldValues, newValues opt.ColList
+		if eventType == tree.TriggerEventInsert || eventType == tree.TriggerEventUpdate {
+			newValues = make(opt.ColList, len(ords))
+			for i, ord := range ords {
+				switch {
+				case mb.upsertColIDs[ord] != 0:
+					newValues[i] = mb.upsertColIDs[ord]
+				case eventType == tree.TriggerEventInsert:
+					newValues[i] = mb.insertColIDs[ord]
+				case mb.updateColIDs[ord] != 0:
+					newValues[i] = mb.updateColIDs[ord]
+				default:
+					newValues[i] = mb.fetchColIDs[ord]
+				}
+			}
+		}
+		if eventType == tree.TriggerEventUpdate || eventType == tree.TriggerEventDelete {
+			oldValues = make(opt.ColList, len(ords), len(ords)+1)
+			for i, ord := range ords {
+				oldValues[i] = mb.fetchColIDs[ord]
+			}
+		}
+		if mb.canaryColID != 0 {
+			// For an UPSERT or INSERT ... ON CONFLICT DO UPDATE, the canary column
+			// is passed as the last old value, so that the trigger is only fired
+			// for the rows that were inserted or updated, respectively.
+			oldValues = append(oldValues, mb.canaryColID)
+		}
+		mb.ensureWithID()
+		for _, trigger := range rowTriggers {
+			mb.cascades = append(mb.cascades, memo.FKCascade{
+				Trigger: trigger,
+				Builder: &afterTriggerBuilder{
+					mutatedTable: mb.tab,
+					trigger:      trigger,
+					eventType:    eventType,
+					hasCanary:    mb.canaryColID != 0,
+				},
+				WithID:    mb.withID,
+				OldValues: oldValues,
+				NewValues: newValues,
+			})
+		}
+	}
+
+	stmtTriggers := findTriggers(
+		mb.tab, tree.TriggerActionTimeAfter, eventType, false /* forEachRow */, mb.triggerUpdateCols, mb.tabID,
+	)
+	for _, trigger := range stmtTriggers {
+		mb.cascades = append(mb.cascades, memo.FKCascade{
+			Trigger: trigger,
+			Builder: &afterTriggerBuilder{
+				mutatedTable: mb.tab,
+				trigger:      trigger,
+				eventType:    eventType,
+			},
+		})
+	}
+}
+
+// buildStatementLevelBeforeTriggers wraps the mutation expression in a With
+// binding that invokes the statement-level BEFORE triggers for the given
+// event. The binding is always materialized, so the triggers are executed
+// exactly once, before the mutation.
+func (mb *mutationBuilder) buildStatementLevelBeforeTriggers(eventType tree.TriggerEventType) {
+	triggers := findTriggers(
+		mb.tab, tree.TriggerActionTimeBefore, eventType, false /* forEachRow */, mb.triggerUpdateCols, mb.tabID,
+	)
+	if len(triggers) == 0 {
+		return
+	}
+	f := mb.b.factory
+	bindingScope := mb.b.allocScope()
+	bindingScope.expr = f.ConstructNoColsRow()
+	mb.b.buildTriggerCalls(mb.tab, triggers, eventType, bindingScope)
+
+	withID := f.Memo().NextWithID()
+	mb.md.AddWithBinding(withID, bindingScope.expr)
+	mb.outScope.expr = f.ConstructWith(bindingScope.expr, mb.outScope.expr, &memo.WithPrivate{
+		ID:   withID,
+		Name: "before-triggers",
+		Mtr:  tree.CTEMaterializeAlways,
+	})
+}
+
+// buildTriggerCalls projects an invocation of each of the given triggers on
+// top of the given scope, in order. The NEW and OLD rows are projected first
+// if they are not already present in the scope.
+func (b *Builder) buildTriggerCalls(
+	tab cat.Table, triggers []cat.Trigger, eventType tree.TriggerEventType, s *scope,
+) {
+	rowType, _ := triggerRowType(tab)
+	newColID, oldColID := b.buildTriggerRows(s, rowType, nil /* newCols */, nil /* oldCols */)
+	b.buildTriggerCallsWithRows(tab, triggers, eventType, rowType, newColID, oldColID, s)
+}
+
+// buildTriggerCallsWithRows is similar to buildTriggerCalls, but uses the
+// given NEW and OLD row columns, which must already be present in the scope.
+// Each invocation is separated by an optimization barrier, so that the trigger
+// functions are executed in order.
+func (b *Builder) buildTriggerCallsWithRows(
+	tab cat.Table,
+	triggers []cat.Trigger,
+	eventType tree.TriggerEventType,
+	rowType *types.T,
+	newColID, oldColID opt.ColumnID,
+	s *scope,
+) {
+	f := b.factory
+	for _, trigger := range triggers {
+		call := b.buildTriggerFunctionCall(tab, trigger, eventType, rowType, newColID, oldColID, s)
+		projectionsScope := s.replace()
+		projectionsScope.appendColumnsFromScope(s)
+		b.synthesizeColumn(
+			projectionsScope,
+			scopeColName("").WithMetadataName(string(trigger.Name())),
+			rowType,
+			nil, /* expr */
+			call,
+		)
+		b.constructProjectForScope(s, projectionsScope)
+		projectionsScope.expr = f.ConstructBarrier(projectionsScope.expr)
+		*s = *projectionsScope
+	}
+}
+
+// afterTriggerBuilder is a memo.CascadeBuilder implementation for AFTER
+// triggers. It builds a query that invokes the trigger function once for every
+// row modified by the mutation (for row-level triggers), or once in total (for
+// statement-level triggers). The query does not return any rows; it is only
+// executed for the side effects of the trigger function.
+//
+// The trigger is planned in the same way as a foreign key cascade, since it
+// must run after the mutation and observe its effects.
+type afterTriggerBuilder struct {
+	mutatedTable cat.Table
+	trigger      cat.Trigger
+	eventType    tree.TriggerEventType
+
+	// hasCanary is true if the trigger was planned for an UPSERT or INSERT ...
+	// ON CONFLICT DO UPDATE. In that case, the last old value is the canary
+	// column of the mutation, which is NULL for inserted rows and non-NULL for
+	// updated rows. INSERT triggers are only fired for the inserted rows, and
+	// UPDATE triggers only for the updated rows.
+	hasCanary bool
+}
+
+var _ memo.CascadeBuilder = &afterTriggerBuilder{}
+
+// Build is part of the memo.CascadeBuilder interface.
+func (tb *afterTriggerBuilder) Build(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	evalCtx *eval.Context,
+	catalog cat.Catalog,
+	factoryI interface{},
+	binding opt.WithID,
+	bindingProps *props.Relational,
+	oldValues, newValues opt.ColList,
+) (memo.RelExpr, error) {
+	return buildCascadeHelper(ctx, semaCtx, evalCtx, catalog, factoryI, func(b *Builder) memo.RelExpr {
+		f := b.factory
+		md := f.Metadata()
+		rowType, _ := triggerRowType(tb.mutatedTable)
+		s := b.allocScope()
+
+		var newCols, oldCols opt.OptionalColList
+		if binding == 0 {
+			if tb.trigger.ForEachRow() {
+				panic(errors.AssertionFailedf("expected input binding for row-level trigger"))
+			}
+			s.expr = f.ConstructNoColsRow()
+		} else {
+			md.AddWithBinding(binding, f.ConstructFakeRel(&memo.FakeRelPrivate{
+				Props: bindingProps,
+			}))
+			inCols := make(opt.ColList, 0, len(oldValues)+len(newValues))
+			inCols = append(inCols, oldValues...)
+			inCols = append(inCols, newValues...)
+			outCols := make(opt.ColList, len(inCols))
+			for i := range inCols {
+				colMeta := md.ColumnMeta(inCols[i])
+				outCols[i] = md.AddColumn(colMeta.Alias, colMeta.Type)
+				s.cols = append(s.cols, scopeColumn{
+					name: scopeColName(""),
+					id:   outCols[i],
+					typ:  colMeta.Type,
+				})
+			}
+			s.expr = f.ConstructWithScan(&memo.WithScanPrivate{
+				With:    binding,
+				InCols:  inCols,
+				OutCols: outCols,
+				ID:      md.NextUniqueID(),
+			})
+			numOldCols := len(oldValues)
+			if tb.hasCanary {
+				numOldCols--
+				canary := f.ConstructVariable(outCols[numOldCols])
+				var filter opt.ScalarExpr
+				if tb.eventType == tree.TriggerEventInsert {
+					filter = f.ConstructIs(canary, memo.NullSingleton)
+				} else {
+					filter = f.ConstructIsNot(canary, memo.NullSingleton)
+				}
+				s.expr = f.ConstructSelect(s.expr, memo.FiltersExpr{f.ConstructFiltersItem(filter)})
+			}
+			if numOldCols > 0 {
+				oldCols = opt.OptionalColList(outCols[:numOldCols])
+			}
+			if len(newValues) > 0 {
+				newCols = opt.OptionalColList(outCols[len(oldValues):])
+			}
+		}
+
+		newColID, oldColID := b.buildTriggerRows(s, rowType, newCols, oldCols)
+		if when := b.buildTriggerWhen(tb.trigger, rowType, newColID, oldColID); when != nil {
+			s.expr = f.ConstructSelect(s.expr, memo.FiltersExpr{f.ConstructFiltersItem(when)})
+		}
+		b.buildTriggerCallsWithRows(
+			tb.mutatedTable, []cat.Trigger{tb.trigger}, tb.eventType, rowType, newColID, oldColID, s,
+		)
+
+		// The return value of an AFTER trigger is ignored, so discard all rows.
+		return f.ConstructSelect(s.expr, memo.FiltersExpr{f.ConstructFiltersItem(memo.FalseSingleton)})
+	})
+}
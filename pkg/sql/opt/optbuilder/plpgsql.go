@@ -479,8 +479,14 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 
 		case *ast.Assignment:
 			// Assignment (:=) is handled by projecting a new column with the same
-			// name as the variable being assigned.
-			s = b.addPLpgSQLAssign(s, t.Var, t.Value)
+			// name as the variable being assigned. An assignment to a field of a
+			// composite-typed variable is handled by reconstructing the variable
+			// with the field replaced.
+			val := t.Value
+			if t.Indirection != "" {
+				val = b.makeFieldAssignExpr(t.Var, t.Indirection, t.Value)
+			}
+			s = b.addPLpgSQLAssign(s, t.Var, val)
 			if b.hasExceptionHandler() {
 				// If exception handling is required, we have to start a new
 				// continuation after each variable assignment. This ensures that in the
@@ -972,7 +978,9 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 			colName := scopeColName("").WithMetadataName(b.makeIdentifier("stmt_call"))
 			col := b.ob.synthesizeColumn(callScope, colName, procTyp, nil /* expr */, nil /* scalar */)
 			b.ob.withinNestedPLpgSQLCall(func() {
-				col.scalar = b.ob.buildRoutine(proc, def, callCon.s, callScope, b.colRefs)
+				col.scalar = b.ob.buildRoutine(
+					proc, def, callCon.s, callScope, b.colRefs, false, /* isTrigger */
+				)
 			})
 			b.ob.constructProjectForScope(callCon.s, callScope)
 
@@ -1122,12 +1130,47 @@ func (b *plpgsqlBuilder) addPLpgSQLAssign(inScope *scope, ident ast.Variable, va
 	colName := scopeColName(ident)
 	scalar := b.buildPLpgSQLExpr(val, typ, inScope)
 	b.addBarrierIfVolatile(inScope, scalar)
-	b.ob.synthesizeColumn(assignScope, colName, typ, nil, scalar)
+	col := b.ob.synthesizeColumn(assignScope, colName, typ, nil, scalar)
+	b.trackTriggerArgv(ident, col)
 	b.ob.constructProjectForScope(inScope, assignScope)
 	b.addBarrierIfVolatile(assignScope, scalar)
 	return assignScope
 }
 
+// makeFieldAssignExpr returns an expression that reconstructs the value of the
+// given composite-typed variable with the given field replaced by val. It is
+// used to model assignments of the form "var.field := val".
+func (b *plpgsqlBuilder) makeFieldAssignExpr(
+	ident ast.Variable, field tree.Name, val ast.Expr,
+) ast.Expr {
+	typ := b.resolveVariableForAssign(ident)
+	if typ.Family() != types.TupleFamily {
+		panic(pgerror.Newf(pgcode.Syntax, "\"%s.%s\" is not a known variable", ident, field))
+	}
+	labels := typ.TupleLabels()
+	contents := typ.TupleContents()
+	exprs := make(tree.Exprs, len(contents))
+	found := false
+	for i := range contents {
+		if i < len(labels) && labels[i] == string(field) {
+			exprs[i] = &tree.CastExpr{Expr: val, Type: contents[i], SyntaxMode: tree.CastShort}
+			found = true
+			continue
+		}
+		exprs[i] = &tree.ColumnAccessExpr{
+			Expr:     tree.NewUnresolvedName(string(ident)),
+			ByIndex:  true,
+			ColIndex: i,
+		}
+	}
+	if !found {
+		panic(pgerror.Newf(pgcode.UndefinedColumn,
+			"record \"%s\" has no field \"%s\"", ident, field,
+		))
+	}
+	return &tree.Tuple{Exprs: exprs, Labels: labels}
+}
+
 // buildInto handles the mapping from the columns of a SQL statement to the
 // variables in an INTO target.
 func (b *plpgsqlBuilder) buildInto(stmtScope *scope, target []ast.Variable) *scope {
@@ -1165,7 +1208,10 @@ func (b *plpgsqlBuilder) buildInto(stmtScope *scope, target []ast.Variable) *sco
 			scalar = b.ob.factory.ConstructConstVal(tree.DNull, typ)
 		}
 		scalar = b.coerceType(scalar, typ)
-		b.ob.synthesizeColumn(intoScope, colName, typ, nil /* expr */, scalar)
+		col := b.ob.synthesizeColumn(intoScope, colName, typ, nil /* expr */, scalar)
+		if targetNames != nil {
+			b.trackTriggerArgv(targetNames[j], col)
+		}
 	}
 	b.ob.constructProjectForScope(stmtScope, intoScope)
 	if b.targetIsRecordVar(target) {
@@ -1704,6 +1750,7 @@ func (b *plpgsqlBuilder) makeContinuation(conName string) continuation {
 		// continuation UDFs.
 		col.setParamOrd(len(params))
 		params = append(params, col.id)
+		b.trackTriggerArgv(name, col)
 	}
 	// Invariant: the variables of a child block always follow those of a parent
 	// block in a continuation's parameters. This ensures that a continuation
@@ -1948,7 +1995,8 @@ func (b *plpgsqlBuilder) projectTupleAsIntoTarget(inScope *scope, target []ast.V
 			memo.TupleOrdinal(i),
 		)
 		scalar = b.coerceType(scalar, typ)
-		b.ob.synthesizeColumn(intoScope, colName, typ, nil /* expr */, scalar)
+		col := b.ob.synthesizeColumn(intoScope, colName, typ, nil /* expr */, scalar)
+		b.trackTriggerArgv(target[i], col)
 	}
 	b.ob.constructProjectForScope(inScope, intoScope)
 	return intoScope
@@ -2317,3 +2365,12 @@ var (
 		"PL/pgSQL SET TRANSACTION statements must immediately follow COMMIT or ROLLBACK",
 	)
 )
+
+// trackTriggerArgv records the given column as holding the value of TG_ARGV
+// if the variable is TG_ARGV and we are building the body of a trigger
+// function. See Builder.triggerArgvIndex.
+func (b *plpgsqlBuilder) trackTriggerArgv(name ast.Variable, col *scopeColumn) {
+	if b.ob.insideTriggerFunction && name == triggerArgvName {
+		b.ob.triggerArgvCols.Add(col.id)
+	}
+}
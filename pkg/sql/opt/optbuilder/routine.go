@@ -25,7 +25,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/cast"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
-	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
 )
 
@@ -69,7 +68,7 @@ func (b *Builder) buildUDF(
 	}
 
 	// Build the routine.
-	routine := b.buildRoutine(f, def, inScope, outScope, colRefs)
+	routine := b.buildRoutine(f, def, inScope, outScope, colRefs, false /* isTrigger */)
 
 	// Synthesize an output columns if necessary.
 	if outCol == nil {
@@ -136,7 +135,7 @@ func (b *Builder) buildProcedure(c *tree.Call, inScope *scope) *scope {
 	}
 
 	// Build the routine.
-	routine := b.buildRoutine(proc, def, inScope, outScope, nil /* colRefs */)
+	routine := b.buildRoutine(proc, def, inScope, outScope, nil /* colRefs */, false /* isTrigger */)
 	routine = b.finishBuildScalar(nil /* texpr */, routine, inScope,
 		nil /* outScope */, nil /* outCol */)
 
@@ -204,11 +203,15 @@ func (b *Builder) resolveProcedureDefinition(
 // - outScope is only used for stored procedures, specifically when there is a
 // transaction control statement. This is necessary because transaction control
 // statements have to construct a new CALL statement to resume execution.
+//
+// - isTrigger is true if the routine is a trigger function that is invoked by
+// a mutation.
 func (b *Builder) buildRoutine(
 	f *tree.FuncExpr,
 	def *tree.ResolvedFunctionDefinition,
 	inScope, outScope *scope,
 	colRefs *opt.ColSet,
+	isTrigger bool,
 ) opt.ScalarExpr {
 	o := f.ResolvedOverload()
 	isProc := o.Type == tree.ProcedureRoutine
@@ -282,8 +285,7 @@ func (b *Builder) buildRoutine(
 		// Add all input parameters to the scope.
 		paramTypes, ok := o.Types.(tree.ParamTypes)
 		if !ok {
-			panic(unimplemented.NewWithIssue(88947,
-				"variadiac user-defined functions are not yet supported"))
+			panic(errors.AssertionFailedf("expected routine parameters to be ParamTypes"))
 		}
 		if len(paramTypes) != len(args) {
 			panic(errors.AssertionFailedf(
@@ -337,6 +339,10 @@ func (b *Builder) buildRoutine(
 			col := b.synthesizeColumn(bodyScope, argColName, desiredTyp, nil /* expr */, nil /* scalar */)
 			col.setParamOrd(i)
 			params[i] = col.id
+			if isTrigger && i == len(paramTypes)-1 {
+				// The last parameter of a trigger function is TG_ARGV.
+				b.triggerArgvCols.Add(col.id)
+			}
 		}
 	}
 
@@ -347,17 +353,24 @@ func (b *Builder) buildRoutine(
 	// for the schema changer we only need depth 1. Also keep track of when
 	// we have are executing inside a UDF, and whether the routine is used as a
 	// data source (this could be nested, so we need to track the previous state).
-	defer func(trackSchemaDeps, insideUDF, insideDataSource, insideSQLRoutine bool) {
+	defer func(
+		trackSchemaDeps, insideUDF, insideDataSource, insideSQLRoutine, insideTriggerFunction bool,
+	) {
 		b.trackSchemaDeps = trackSchemaDeps
 		b.insideUDF = insideUDF
 		b.insideDataSource = insideDataSource
 		b.insideSQLRoutine = insideSQLRoutine
-	}(b.trackSchemaDeps, b.insideUDF, b.insideDataSource, b.insideSQLRoutine)
+		b.insideTriggerFunction = insideTriggerFunction
+	}(
+		b.trackSchemaDeps, b.insideUDF, b.insideDataSource, b.insideSQLRoutine,
+		b.insideTriggerFunction,
+	)
 	oldInsideDataSource := b.insideDataSource
 	b.insideDataSource = false
 	b.trackSchemaDeps = false
 	b.insideUDF = true
 	b.insideSQLRoutine = o.Language == tree.RoutineLangSQL
+	b.insideTriggerFunction = isTrigger
 	isSetReturning := o.Class == tree.GeneratorClass
 
 	// Build an expression for each statement in the function body.
@@ -183,10 +183,8 @@ func (b *Builder) buildScalar(
 				panic(unimplementedWithIssueDetailf(32551, "", "array slicing is not supported"))
 			}
 
-			out = b.factory.ConstructIndirection(
-				out,
-				b.buildScalar(subscript.Begin.(tree.TypedExpr), inScope, nil, nil, colRefs),
-			)
+			index := b.buildScalar(subscript.Begin.(tree.TypedExpr), inScope, nil, nil, colRefs)
+			out = b.factory.ConstructIndirection(out, b.triggerArgvIndex(t.Expr, index))
 		}
 
 	case *tree.IfErrExpr:
@@ -281,6 +279,7 @@ func (b *Builder) buildScalar(
 		texpr := t.Expr.(tree.TypedExpr)
 		arg := b.buildScalar(texpr, inScope, nil, nil, colRefs)
 		out = b.factory.ConstructCast(arg, t.ResolvedType())
+		out = b.buildDomainChecks(out, t.ResolvedType())
 
 	case *tree.CoalesceExpr:
 		args := make(memo.ScalarListExpr, len(t.Exprs))
@@ -552,6 +551,11 @@ func (b *Builder) buildFunction(
 	}
 	b.factory.Metadata().AddBuiltin(f.Func.ReferenceByName)
 
+	if def.Name == "grouping" {
+		out = b.buildGroupingFunc(f, inScope)
+		return b.finishBuildScalar(f, out, inScope, outScope, outCol)
+	}
+
 	if overload.Class == tree.AggregateClass {
 		panic(errors.AssertionFailedf("aggregate function should have been replaced"))
 	}
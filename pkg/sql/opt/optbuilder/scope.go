@@ -125,6 +125,7 @@ const (
 	exprKindNone exprKind = iota
 	exprKindAlterTableSplitAt
 	exprKindDistinctOn
+	exprKindDomainCheck
 	exprKindFrom
 	exprKindGroupBy
 	exprKindHaving
@@ -138,6 +139,7 @@ const (
 	exprKindReturning
 	exprKindSelect
 	exprKindStoreID
+	exprKindTriggerWhen
 	exprKindValues
 	exprKindWhere
 	exprKindWindowFrameStart
@@ -148,6 +150,7 @@ var exprKindName = [...]string{
 	exprKindNone:              "",
 	exprKindAlterTableSplitAt: "ALTER TABLE SPLIT AT",
 	exprKindDistinctOn:        "DISTINCT ON",
+	exprKindDomainCheck:       "DOMAIN CHECK",
 	exprKindFrom:              "FROM",
 	exprKindGroupBy:           "GROUP BY",
 	exprKindHaving:            "HAVING",
@@ -161,6 +164,7 @@ var exprKindName = [...]string{
 	exprKindReturning:         "RETURNING",
 	exprKindSelect:            "SELECT",
 	exprKindStoreID:           "RELOCATE STORE ID",
+	exprKindTriggerWhen:       "WHEN",
 	exprKindValues:            "VALUES",
 	exprKindWhere:             "WHERE",
 	exprKindWindowFrameStart:  "WINDOW FRAME START",
@@ -999,6 +1003,30 @@ func (s *scope) Resolve(
 	return nil, colinfo.NewUndefinedColumnError(tree.ErrString(tree.NewColumnItem(prefix, colName)))
 }
 
+// maybeResolveVariableFieldAccess attempts to resolve a column item of the
+// form "var.field" as an access to a field of the composite-typed routine
+// variable (or parameter) "var". This is also used for references to the NEW
+// and OLD rows in a trigger WHEN condition. It returns nil if the column item
+// cannot be resolved in this way.
+func (s *scope) maybeResolveVariableFieldAccess(t *tree.ColumnItem) tree.Expr {
+	if !s.builder.insideUDF && !s.builder.insideTriggerWhen {
+		return nil
+	}
+	if t.TableName == nil || t.TableName.NumParts != 1 {
+		return nil
+	}
+	varName := &tree.ColumnItem{ColumnName: tree.Name(t.TableName.Parts[0])}
+	colI, err := colinfo.ResolveColumnItem(s.builder.ctx, s, varName)
+	if err != nil {
+		return nil
+	}
+	col := colI.(*scopeColumn)
+	if col.typ.Family() != types.TupleFamily {
+		return nil
+	}
+	return &tree.ColumnAccessExpr{Expr: col, ColName: t.ColumnName}
+}
+
 func makeUntypedTuple(labels []string, texprs []tree.TypedExpr) *tree.Tuple {
 	exprs := make(tree.Exprs, len(texprs))
 	for i, e := range texprs {
@@ -1042,6 +1070,11 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 	case *tree.ColumnItem:
 		colI, resolveErr := colinfo.ResolveColumnItem(s.builder.ctx, s, t)
 		if resolveErr != nil {
+			// Within a routine, a qualified name may refer to a field of a
+			// composite-typed variable, e.g. NEW.x in a trigger function.
+			if fieldAccess := s.maybeResolveVariableFieldAccess(t); fieldAccess != nil {
+				return false, fieldAccess
+			}
 			// It may be a reference to a table, e.g. SELECT tbl FROM tbl.
 			// Attempt to resolve as a TupleStar.
 			if sqlerrors.IsUndefinedColumnError(resolveErr) {
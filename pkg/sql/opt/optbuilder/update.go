@@ -92,7 +92,9 @@ func (b *Builder) buildUpdate(upd *tree.Update, inScope *scope) (outScope *scope
 	b.checkPrivilege(depName, tab, privilege.SELECT)
 
 	// Check if this table has already been mutated in another subquery.
-	b.checkMultipleMutations(tab, generalMutation)
+	if !b.isMergeAction(upd) {
+		b.checkMultipleMutations(tab, generalMutation)
+	}
 
 	var mb mutationBuilder
 	mb.init(b, "update", tab, alias)
@@ -119,6 +121,9 @@ func (b *Builder) buildUpdate(upd *tree.Update, inScope *scope) (outScope *scope
 		mb.buildUpdate(nil /* returning */)
 	}
 
+	// Statement-level BEFORE triggers must be executed before the update.
+	mb.buildStatementLevelBeforeTriggers(tree.TriggerEventUpdate)
+
 	return mb.outScope
 }
 
@@ -289,6 +294,10 @@ func (mb *mutationBuilder) addUpdateCols(exprs tree.UpdateExprs) {
 	// Add assignment casts for update columns.
 	mb.addAssignmentCasts(mb.updateColIDs)
 
+	// Invoke any row-level BEFORE triggers, which may modify the updated
+	// values before computed columns are synthesized.
+	mb.buildRowLevelBeforeTriggers(tree.TriggerEventUpdate)
+
 	// Add additional columns for computed expressions that may depend on the
 	// updated columns.
 	mb.addSynthesizedColsForUpdate()
@@ -350,8 +359,12 @@ func (mb *mutationBuilder) buildUpdate(returning *tree.ReturningExprs) {
 
 	mb.buildUniqueChecksForUpdate()
 
+	mb.buildExclusionChecksForUpdate()
+
 	mb.buildFKChecksForUpdate()
 
+	mb.buildAfterTriggers(tree.TriggerEventUpdate)
+
 	private := mb.makeMutationPrivate(returning != nil)
 	for _, col := range mb.extraAccessibleCols {
 		if col.id != 0 {
@@ -536,6 +536,17 @@ func (b *Builder) checkMultipleMutations(tab cat.Table, typ mutationType) {
 	}
 }
 
+// isMergeAction returns true if the given mutation implements an action of the
+// MERGE statement being built.
+func (b *Builder) isMergeAction(stmt tree.Statement) bool {
+	for _, action := range b.mergeActions {
+		if action == stmt {
+			return true
+		}
+	}
+	return false
+}
+
 func (b *Builder) checkMultipleMutationsCascade(
 	tab cat.Table, typ mutationType, visited intsets.Fast,
 ) {
@@ -91,7 +91,7 @@ func (b *Builder) buildWindow(outScope *scope, inScope *scope) {
 		def := w.WindowDef
 		defs[i] = def
 
-		argExprs := b.getTypedWindowArgs(w)
+		argExprs := userDefinedAggArgs(&w.def, b.getTypedWindowArgs(w))
 
 		// Build the appropriate arguments.
 		argLists[i] = b.buildWindowArgs(argExprs, i, w.def.Name, inScope, argScope)
@@ -143,7 +143,7 @@ func (b *Builder) buildWindow(outScope *scope, inScope *scope) {
 
 		frameIdx := b.findMatchingFrameIndex(&frames, partitions[i], orderings[i])
 
-		fn := b.constructWindowFn(w.def.Name, argLists[i])
+		fn := b.constructWindowFn(&w.def, w.FuncExpr, argLists[i])
 
 		if windowFrames[i].Bounds.StartBound.OffsetExpr != nil {
 			fn = b.factory.ConstructWindowFromOffset(
@@ -255,13 +255,19 @@ func (b *Builder) buildAggregationAsWindow(
 	filterCols := make([]opt.ColumnID, len(g.aggs))
 
 	// Construct the pre-projection, which renders the grouping columns and the
-	// aggregate arguments, as well as any additional order by columns.
+	// aggregate arguments, as well as any additional order by columns. With
+	// grouping sets, the pre-projection is also expanded into one row for each
+	// grouping set, which must be grouped by the grouping set index as well.
 	g.aggInScope.appendColumnsFromScope(fromScope)
-	b.constructProjectForScope(fromScope, g.aggInScope)
+	if g.groupingSets != nil {
+		groupingColSet = b.constructGroupingSetsInput(fromScope)
+	} else {
+		b.constructProjectForScope(fromScope, g.aggInScope)
+	}
 
 	// Build the arguments, partitions and orderings for each aggregate.
 	for i, agg := range g.aggs {
-		argExprs := getTypedExprs(agg.Exprs)
+		argExprs := userDefinedAggArgs(&agg.def, getTypedExprs(agg.Exprs))
 
 		// Build the appropriate arguments.
 		argLists[i] = b.buildWindowArgs(argExprs, i, agg.def.Name, fromScope, g.aggInScope)
@@ -288,7 +294,7 @@ func (b *Builder) buildAggregationAsWindow(
 	// so that we can group functions over the same partition and ordering.
 	frames := make([]memo.WindowExpr, 0, len(g.aggs))
 	for i, agg := range g.aggs {
-		fn := b.constructAggregate(agg.def.Name, argLists[i])
+		fn := b.constructAggregate(&agg.def, agg.FuncExpr, argLists[i])
 		if filterCols[i] != 0 {
 			fn = b.factory.ConstructAggFilter(
 				fn,
@@ -317,7 +323,12 @@ func (b *Builder) buildAggregationAsWindow(
 	// aggregations built as window functions emit an aggregated value for each row
 	// instead of each group. To rectify this, we must 'squash' the values down by
 	// wrapping it with a GroupBy or ScalarGroupBy.
-	g.aggOutScope.expr = b.constructWindowGroup(aggregateExpr, groupingColSet, g.aggs, g.aggOutScope)
+	g.aggOutScope.expr = b.constructWindowGroup(
+		aggregateExpr, groupingColSet, g.aggs, g.groupingSets, g.aggOutScope,
+	)
+	if g.groupingSets != nil {
+		g.aggOutScope.expr = b.constructEmptyGroupingSets(g, g.aggOutScope.expr)
+	}
 
 	// Wrap with having filter if it exists.
 	if having != nil {
@@ -546,9 +557,15 @@ func (b *Builder) findMatchingFrameIndex(
 // constructWindowGroup wraps the input window expression with an appropriate
 // grouping so the results of each window column are squashed down.
 // The expression may be wrapped with a projection so ensure the default NULL
-// values of the aggregates are respected when no rows are returned.
+// values of the aggregates are respected when no rows are returned. If the
+// GROUP BY clause has grouping sets, the window columns are produced into the
+// columns returned by aggOutCol of the groupingSetsInfo.
 func (b *Builder) constructWindowGroup(
-	input memo.RelExpr, groupingColSet opt.ColSet, aggInfos []aggregateInfo, outScope *scope,
+	input memo.RelExpr,
+	groupingColSet opt.ColSet,
+	aggInfos []aggregateInfo,
+	groupingSets *groupingSetsInfo,
+	outScope *scope,
 ) memo.RelExpr {
 	if groupingColSet.Empty() {
 		// Construct a scalar GroupBy wrapped around the appropriate projections.
@@ -563,7 +580,7 @@ func (b *Builder) constructWindowGroup(
 	for i := range aggInfos {
 		aggs = append(aggs, b.factory.ConstructAggregationsItem(
 			b.factory.ConstructConstAgg(b.factory.ConstructVariable(aggInfos[i].col.id)),
-			aggInfos[i].col.id,
+			groupingSets.aggOutCol(aggInfos[i].col.id),
 		))
 	}
 	return b.factory.ConstructGroupBy(input, aggs, &private)
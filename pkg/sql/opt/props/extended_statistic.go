@@ -0,0 +1,134 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package props
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// ExtendedStatistic captures the statistics of a multi-column statistic on a
+// table that describe the joint distribution of the values of its columns: a
+// histogram over tuples of the column values, the most common tuples, and the
+// functional dependencies between the columns. Unlike Histogram, it is not
+// filtered as constraints are applied to the columns; it is only used to
+// estimate the selectivity of a conjunction of equality predicates on its
+// columns, which cannot be derived from single-column statistics when the
+// columns are correlated.
+type ExtendedStatistic struct {
+	// Cols are the columns of the statistic, in the order of the values in the
+	// tuples of the histogram and of the most common values. The From and To
+	// fields of Dependencies are indexes into Cols.
+	Cols opt.ColList
+
+	// NonNullRowCount and NonNullDistinctCount are the number of rows and of
+	// distinct tuples in the table, excluding the rows in which all of the
+	// columns are NULL.
+	NonNullRowCount      float64
+	NonNullDistinctCount float64
+
+	// Histogram contains the buckets of the histogram, in ascending order of
+	// their upper bounds. It does not contain a bucket for rows in which all of
+	// the columns are NULL. It is nil if no histogram was collected.
+	Histogram []cat.HistogramBucket
+
+	// MostCommonValues contains the most common tuples, in ascending order.
+	MostCommonValues []cat.MostCommonValue
+
+	// Dependencies contains the functional dependencies between the columns.
+	Dependencies []cat.ColumnDependency
+}
+
+// EqualityRowCount returns the estimated number of rows for which the
+// columns of the statistic are equal to the given tuple of values. It returns
+// false if there is no histogram or most common values to estimate it from,
+// or if the tuple cannot be compared to their values.
+func (e *ExtendedStatistic) EqualityRowCount(
+	ctx context.Context, cmpCtx tree.CompareContext, tuple *tree.DTuple,
+) (rowCount float64, ok bool) {
+	var err error
+	mcvs := e.MostCommonValues
+	i := sort.Search(len(mcvs), func(i int) bool {
+		if err != nil {
+			return true
+		}
+		var cmp int
+		cmp, err = mcvs[i].Value.Compare(ctx, cmpCtx, tuple)
+		return cmp >= 0
+	})
+	if err != nil {
+		return 0, false
+	}
+	if i < len(mcvs) {
+		if cmp, err := mcvs[i].Value.Compare(ctx, cmpCtx, tuple); err != nil {
+			return 0, false
+		} else if cmp == 0 {
+			return mcvs[i].NumEq, true
+		}
+	}
+	if len(e.Histogram) > 0 {
+		return e.histogramEqualityRowCount(ctx, cmpCtx, tuple)
+	}
+	if len(mcvs) == 0 {
+		return 0, false
+	}
+	// Assume the rows that don't have one of the most common values are
+	// uniformly distributed among the other distinct values.
+	remainingRows := e.NonNullRowCount
+	for i := range mcvs {
+		remainingRows -= mcvs[i].NumEq
+	}
+	remainingDistinct := e.NonNullDistinctCount - float64(len(mcvs))
+	if remainingRows <= 0 || remainingDistinct < 1 {
+		return 0, true
+	}
+	return remainingRows / remainingDistinct, true
+}
+
+// histogramEqualityRowCount returns the estimated number of rows for which
+// the columns of the statistic are equal to the given tuple of values,
+// according to the histogram.
+func (e *ExtendedStatistic) histogramEqualityRowCount(
+	ctx context.Context, cmpCtx tree.CompareContext, tuple *tree.DTuple,
+) (rowCount float64, ok bool) {
+	var err error
+	i := sort.Search(len(e.Histogram), func(i int) bool {
+		if err != nil {
+			return true
+		}
+		var cmp int
+		cmp, err = e.Histogram[i].UpperBound.Compare(ctx, cmpCtx, tuple)
+		return cmp >= 0
+	})
+	if err != nil {
+		return 0, false
+	}
+	if i == len(e.Histogram) {
+		// The tuple is greater than all of the sampled values.
+		return 0, true
+	}
+	b := &e.Histogram[i]
+	if cmp, err := b.UpperBound.Compare(ctx, cmpCtx, tuple); err != nil {
+		return 0, false
+	} else if cmp == 0 {
+		return b.NumEq, true
+	}
+	if b.DistinctRange < 1 {
+		return 0, true
+	}
+	// Assume the rows in the bucket's range are uniformly distributed among its
+	// distinct values.
+	return b.NumRange / b.DistinctRange, true
+}
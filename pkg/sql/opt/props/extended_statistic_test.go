@@ -0,0 +1,123 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package props
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+func TestExtendedStatisticEqualityRowCount(t *testing.T) {
+	ctx := context.Background()
+	evalCtx := eval.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+	typ := types.MakeTuple([]*types.T{types.Int, types.String})
+	tuple := func(i int, s string) *tree.DTuple {
+		return tree.NewDTuple(typ, tree.NewDInt(tree.DInt(i)), tree.NewDString(s))
+	}
+
+	h := ExtendedStatistic{
+		Cols: opt.ColList{1, 2},
+		Histogram: []cat.HistogramBucket{
+			{NumEq: 5, NumRange: 0, DistinctRange: 0, UpperBound: tuple(1, "a")},
+			{NumEq: 10, NumRange: 20, DistinctRange: 4, UpperBound: tuple(1, "z")},
+			{NumEq: 1, NumRange: 0, DistinctRange: 0, UpperBound: tuple(2, "a")},
+		},
+	}
+
+	testData := []struct {
+		tuple    *tree.DTuple
+		rowCount float64
+	}{
+		// Values below the first bucket.
+		{tuple: tuple(0, "a"), rowCount: 0},
+		// Values equal to upper bounds.
+		{tuple: tuple(1, "a"), rowCount: 5},
+		{tuple: tuple(1, "z"), rowCount: 10},
+		{tuple: tuple(2, "a"), rowCount: 1},
+		// Values within a bucket's range.
+		{tuple: tuple(1, "m"), rowCount: 5},
+		// Values within a bucket without a range.
+		{tuple: tuple(1, "zz"), rowCount: 0},
+		// Values above the last bucket.
+		{tuple: tuple(3, "a"), rowCount: 0},
+	}
+
+	for _, tc := range testData {
+		rowCount, ok := h.EqualityRowCount(ctx, &evalCtx, tc.tuple)
+		if !ok {
+			t.Fatalf("%s: expected the tuple to be comparable", tc.tuple)
+		}
+		if rowCount != tc.rowCount {
+			t.Errorf("%s: expected row count %v, found %v", tc.tuple, tc.rowCount, rowCount)
+		}
+	}
+
+	// The most common values take precedence over the histogram.
+	h.MostCommonValues = []cat.MostCommonValue{
+		{NumEq: 7, Value: tuple(1, "m")},
+		{NumEq: 9, Value: tuple(3, "a")},
+	}
+	testData = []struct {
+		tuple    *tree.DTuple
+		rowCount float64
+	}{
+		{tuple: tuple(1, "m"), rowCount: 7},
+		{tuple: tuple(3, "a"), rowCount: 9},
+		{tuple: tuple(1, "a"), rowCount: 5},
+		{tuple: tuple(1, "n"), rowCount: 5},
+	}
+	for _, tc := range testData {
+		rowCount, ok := h.EqualityRowCount(ctx, &evalCtx, tc.tuple)
+		if !ok {
+			t.Fatalf("%s: expected the tuple to be comparable", tc.tuple)
+		}
+		if rowCount != tc.rowCount {
+			t.Errorf("%s: expected row count %v, found %v", tc.tuple, tc.rowCount, rowCount)
+		}
+	}
+
+	// Without a histogram, the rows without one of the most common values are
+	// distributed uniformly among the other distinct values.
+	h.Histogram = nil
+	h.NonNullRowCount = 100
+	h.NonNullDistinctCount = 12
+	testData = []struct {
+		tuple    *tree.DTuple
+		rowCount float64
+	}{
+		{tuple: tuple(1, "m"), rowCount: 7},
+		{tuple: tuple(3, "a"), rowCount: 9},
+		{tuple: tuple(1, "a"), rowCount: 8.4},
+		{tuple: tuple(4, "z"), rowCount: 8.4},
+	}
+	for _, tc := range testData {
+		rowCount, ok := h.EqualityRowCount(ctx, &evalCtx, tc.tuple)
+		if !ok {
+			t.Fatalf("%s: expected the tuple to be comparable", tc.tuple)
+		}
+		if rowCount != tc.rowCount {
+			t.Errorf("%s: expected row count %v, found %v", tc.tuple, tc.rowCount, rowCount)
+		}
+	}
+
+	// Without a histogram or most common values, there is no estimate.
+	h.MostCommonValues = nil
+	if _, ok := h.EqualityRowCount(ctx, &evalCtx, tuple(1, "a")); ok {
+		t.Errorf("expected no estimate without a histogram or most common values")
+	}
+}
@@ -89,6 +89,11 @@ type Statistics struct {
 	// size of the column with ordinal i in its table. AvgSize is only non-nil
 	// when the statistics are built from a table.
 	AvgColSizes []uint64
+
+	// ExtendedStats contains the histograms, most common values and functional
+	// dependencies of multi-column statistics on a table. Like AvgColSizes,
+	// ExtendedStats is only non-nil when the statistics are built from a table.
+	ExtendedStats []ExtendedStatistic
 }
 
 // Init initializes the data members of Statistics.
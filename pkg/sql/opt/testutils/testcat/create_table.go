@@ -33,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/cockroach/pkg/util/intsets"
 )
 
@@ -366,6 +367,9 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 		case *tree.IndexTableDef:
 			tab.addIndex(def, nonUniqueIndex)
 
+		case *tree.ExclusionConstraintTableDef:
+			tab.addExclusionConstraint(def)
+
 		case *tree.FamilyTableDef:
 			tab.addFamily(def)
 
@@ -678,6 +682,7 @@ func (tc *Catalog) resolveFK(tab *Table, d *tree.ForeignKeyConstraintTableDef) {
 		matchMethod:              d.Match,
 		deleteAction:             d.Actions.Delete,
 		updateAction:             d.Actions.Update,
+		deferrability:            d.Deferrable,
 	}
 	tab.outboundFKs = append(tab.outboundFKs, fk)
 	targetTable.inboundFKs = append(targetTable.inboundFKs, fk)
@@ -752,6 +757,37 @@ func (tt *Table) addUniqueConstraint(
 	tt.uniqueConstraints = append(tt.uniqueConstraints, u)
 }
 
+func (tt *Table) addExclusionConstraint(def *tree.ExclusionConstraintTableDef) {
+	// Add the backing index, named like Postgres names it if the constraint is
+	// unnamed.
+	indexDef := def.IndexTableDef
+	if indexDef.Name == "" {
+		var sb strings.Builder
+		sb.WriteString(tt.TabName.Table())
+		for _, col := range def.Columns {
+			sb.WriteRune('_')
+			sb.WriteString(col.Column.String())
+		}
+		sb.WriteString("_excl")
+		indexDef.Name = tree.Name(sb.String())
+	}
+	idx := tt.addIndex(&indexDef, nonUniqueIndex)
+
+	e := ExclusionConstraint{
+		name:           idx.IdxName,
+		tabID:          tt.TabID,
+		columnOrdinals: make([]int, len(def.Columns)),
+		operators:      def.Operators,
+	}
+	for i, c := range def.Columns {
+		e.columnOrdinals[i] = tt.FindOrdinal(string(c.Column))
+	}
+	if def.Predicate != nil {
+		e.predicate = tree.Serialize(def.Predicate)
+	}
+	tt.exclusionConstraints = append(tt.exclusionConstraints, e)
+}
+
 func (tt *Table) addColumn(def *tree.ColumnTableDef) {
 	ordinal := len(tt.Columns)
 	nullable := !def.PrimaryKey.IsPrimaryKey && def.Nullable.Nullability != tree.NotNull
@@ -950,6 +986,21 @@ func (tt *Table) addIndexWithVersion(
 						MaxCells: 3,
 					}},
 				}
+
+			case types.PGVectorFamily:
+				if !def.Vector {
+					break
+				}
+				// Use a fixed seed so that plans are deterministic.
+				metric := catpb.VectorDistanceMetric_L2
+				switch colDef.OpClass {
+				case "vector_cosine_ops":
+					metric = catpb.VectorDistanceMetric_COSINE
+				case "vector_ip_ops":
+					metric = catpb.VectorDistanceMetric_INNER_PRODUCT
+				}
+				typ := tt.Columns[col.InvertedSourceColumnOrdinal()].DatumType()
+				idx.vecConfig = vecindex.MakeConfig(typ.Width(), vecindex.DefaultLists, metric, 1 /* seed */)
 			}
 		}
 	}
@@ -11,6 +11,10 @@
 package xform
 
 import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
@@ -18,6 +22,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
 	"github.com/cockroachdb/errors"
 )
 
@@ -346,6 +353,129 @@ func (c *CustomFuncs) GenerateLimitedTopKScans(
 	})
 }
 
+// GenerateVectorSearchScans generates a TopK over an IndexJoin and a scan of a
+// vector index for each vector index that can serve the TopK's ordering. The
+// TopK's ordering must start with the distance between an indexed vector
+// column and a constant query vector, computed with the distance metric of the
+// index. The scan reads the rows with NULL vectors along with the rows in the
+// vector_search_probes partitions closest to the query vector.
+func (c *CustomFuncs) GenerateVectorSearchScans(
+	grp memo.RelExpr,
+	required *physical.Required,
+	sp *memo.ScanPrivate,
+	projections memo.ProjectionsExpr,
+	passthrough opt.ColSet,
+	tp *memo.TopKPrivate,
+) {
+	if sp.Flags.NoIndexJoin {
+		return
+	}
+	requiredOrdering := tp.Ordering
+	if len(requiredOrdering.Columns) == 0 || requiredOrdering.Columns[0].Descending {
+		return
+	}
+
+	// Find the distance computation that the ordering starts with.
+	var distance opt.ScalarExpr
+	for i := range projections {
+		if requiredOrdering.Columns[0].Group.Contains(projections[i].Col) {
+			distance = projections[i].Element
+			break
+		}
+	}
+	if distance == nil {
+		return
+	}
+	metric, col, query, ok := c.matchVectorDistance(distance)
+	if !ok {
+		return
+	}
+
+	probes := int(c.e.evalCtx.SessionData().VectorSearchProbes)
+	if probes <= 0 {
+		probes = vecindex.DefaultSearchProbes
+	}
+
+	var pkCols opt.ColSet
+	var iter scanIndexIter
+	var sb indexScanBuilder
+	sb.Init(c, sp.Table)
+	iter.Init(c.e.evalCtx, c.e, c.e.mem, &c.im, sp, nil /* filters */, rejectNonInvertedIndexes)
+	iter.ForEach(func(index cat.Index, _ memo.FiltersExpr, _ opt.ColSet, _ bool, _ memo.ProjectionsExpr) {
+		cfg := index.VecConfig()
+		if cfg.IsEmpty() || cfg.Metric != metric || index.NonInvertedPrefixColumnCount() > 0 {
+			return
+		}
+		if sp.Table.ColumnID(index.InvertedColumn().InvertedSourceColumnOrdinal()) != col {
+			return
+		}
+		partitions, err := vecindex.SearchPartitions(cfg, query, probes)
+		if err != nil {
+			// The query vector has the wrong number of dimensions. Leave it to
+			// the distance function to report the error.
+			return
+		}
+		sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+
+		// NULL vectors sort before all others, so they are always scanned. The
+		// NULL key sorts before all partition keys.
+		spans := make(inverted.Spans, 0, len(partitions)+1)
+		spans = append(spans, inverted.MakeSingleValSpan(encoding.EncodeNullAscending(nil)))
+		for _, p := range partitions {
+			spans = append(spans, inverted.MakeSingleValSpan(vecindex.EncodePartitionKey(nil, p)))
+		}
+
+		// Calculate the PK columns once.
+		if pkCols.Empty() {
+			pkCols = c.PrimaryKeyCols(sp.Table)
+		}
+
+		// Each row has exactly one entry in a vector index, so there is no need
+		// to deduplicate the primary keys produced by the scan.
+		newScanPrivate := *sp
+		newScanPrivate.Distribution.Regions = nil
+		newScanPrivate.Index = index.Ordinal()
+		newScanPrivate.InvertedConstraint = spans
+		newScanPrivate.Cols = pkCols.Copy()
+		sb.SetScan(&newScanPrivate)
+		sb.AddIndexJoin(sp.Cols)
+		input := c.e.f.ConstructProject(sb.BuildNewExpr(), projections, passthrough)
+		grp.Memo().AddTopKToGroup(&memo.TopKExpr{Input: input, TopKPrivate: *tp}, grp)
+	})
+}
+
+// matchVectorDistance checks whether the given expression computes the
+// distance between a vector column and a constant vector. If so, it returns the
+// distance metric, the column, and the constant vector.
+func (c *CustomFuncs) matchVectorDistance(
+	e opt.ScalarExpr,
+) (metric catpb.VectorDistanceMetric, col opt.ColumnID, query vector.T, ok bool) {
+	switch e.Op() {
+	case opt.VectorDistanceOp:
+		metric = catpb.VectorDistanceMetric_L2
+	case opt.VectorCosDistanceOp:
+		metric = catpb.VectorDistanceMetric_COSINE
+	case opt.VectorNegInnerProductOp:
+		metric = catpb.VectorDistanceMetric_INNER_PRODUCT
+	default:
+		return 0, 0, nil, false
+	}
+	// All of the distance operators are commutative.
+	left, right := e.Child(0).(opt.ScalarExpr), e.Child(1).(opt.ScalarExpr)
+	if left.Op() != opt.VariableOp {
+		left, right = right, left
+	}
+	v, ok := left.(*memo.VariableExpr)
+	if !ok || right.Op() != opt.ConstOp {
+		return 0, 0, nil, false
+	}
+	d, ok := tree.UnwrapDOidWrapper(right.(*memo.ConstExpr).Value).(*tree.DPGVector)
+	if !ok {
+		return 0, 0, nil, false
+	}
+	return metric, v.Col, d.T, true
+}
+
 // getPrefixFromOrdering returns an OrderingChoice that holds the prefix
 // of Ordering o that satisfies part of the required OrderingChoice intraOrd,
 // a bool indicating whether the entire Ordering o was satisfied, and a bool
@@ -13,6 +13,8 @@ package sql
 import (
 	"context"
 	"math"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/config"
@@ -760,6 +762,8 @@ type optTable struct {
 
 	uniqueConstraints []optUniqueConstraint
 
+	exclusionConstraints []optExclusionConstraint
+
 	outboundFKs []optForeignKeyConstraint
 	inboundFKs  []optForeignKeyConstraint
 
@@ -768,6 +772,9 @@ type optTable struct {
 	// constraints for user defined types.
 	checkConstraints []optCheckConstraint
 
+	// triggers is the set of triggers for this table, sorted by name.
+	triggers []optTrigger
+
 	// colMap is a mapping from unique ColumnID to column ordinal within the
 	// table. This is a common lookup that needs to be fast.
 	colMap catalog.TableColMap
@@ -835,7 +842,7 @@ func newOptTable(
 				col.GetType(),
 				col.IsNullable(),
 				visibility,
-				cd.DefaultExpr,
+				columnDefaultExpr(col),
 				cd.ComputeExpr,
 				cd.OnUpdateExpr,
 				mapGeneratedAsIdentityType(col.GetGeneratedAsIdentityType()),
@@ -902,12 +909,13 @@ func newOptTable(
 	ot.uniqueConstraints = make([]optUniqueConstraint, len(ot.desc.EnforcedUniqueConstraintsWithoutIndex()))
 	for i, u := range ot.desc.EnforcedUniqueConstraintsWithoutIndex() {
 		ot.uniqueConstraints[i] = optUniqueConstraint{
-			name:         u.GetName(),
-			table:        ot.ID(),
-			columns:      u.CollectKeyColumnIDs().Ordered(),
-			predicate:    u.GetPredicate(),
-			withoutIndex: true,
-			validity:     u.GetConstraintValidity(),
+			name:          u.GetName(),
+			table:         ot.ID(),
+			columns:       u.CollectKeyColumnIDs().Ordered(),
+			predicate:     u.GetPredicate(),
+			withoutIndex:  true,
+			validity:      u.GetConstraintValidity(),
+			deferrability: tree.ConstraintDeferrability(u.UniqueWithoutIndexDesc().Deferrability),
 		}
 	}
 
@@ -1012,6 +1020,7 @@ func newOptTable(
 			match:             tree.CompositeKeyMatchMethodType[fk.Match()],
 			deleteAction:      tree.ForeignKeyReferenceActionType[fk.OnDelete()],
 			updateAction:      tree.ForeignKeyReferenceActionType[fk.OnUpdate()],
+			deferrability:     tree.ConstraintDeferrability(fk.ForeignKeyDesc().Deferrability),
 		})
 	}
 	for _, fk := range ot.desc.InboundForeignKeys() {
@@ -1025,9 +1034,37 @@ func newOptTable(
 			match:             tree.CompositeKeyMatchMethodType[fk.Match()],
 			deleteAction:      tree.ForeignKeyReferenceActionType[fk.OnDelete()],
 			updateAction:      tree.ForeignKeyReferenceActionType[fk.OnUpdate()],
+			deferrability:     tree.ConstraintDeferrability(fk.ForeignKeyDesc().Deferrability),
 		})
 	}
 
+	// Add exclusion constraints, which take their name and predicate from their
+	// backing index.
+	for _, ec := range ot.desc.GetExclusionConstraints() {
+		idx, err := catalog.MustFindIndexByID(desc, ec.IndexID)
+		if err != nil {
+			return nil, err
+		}
+		oc := optExclusionConstraint{
+			name:      idx.GetName(),
+			table:     ot.ID(),
+			columns:   make([]descpb.ColumnID, len(ec.Elements)),
+			operators: make([]treecmp.ComparisonOperator, len(ec.Elements)),
+			predicate: idx.GetPredicate(),
+		}
+		for i := range ec.Elements {
+			op, ok := treecmp.ComparisonOpByName(ec.Elements[i].Operator)
+			if !ok {
+				return nil, errors.AssertionFailedf(
+					"unknown operator %q in exclusion constraint %q", ec.Elements[i].Operator, oc.name,
+				)
+			}
+			oc.columns[i] = ec.Elements[i].ColumnID
+			oc.operators[i] = op
+		}
+		ot.exclusionConstraints = append(ot.exclusionConstraints, oc)
+	}
+
 	ot.primaryFamily.init(ot, &desc.GetFamilies()[0])
 	ot.families = make([]optFamily, len(desc.GetFamilies())-1)
 	for i := range ot.families {
@@ -1079,6 +1116,17 @@ func newOptTable(
 	}
 	ot.checkConstraints = append(ot.checkConstraints, synthesizedChecks...)
 
+	// Add triggers in the order in which they fire.
+	if descTriggers := desc.GetTriggers(); len(descTriggers) > 0 {
+		ot.triggers = make([]optTrigger, len(descTriggers))
+		for i := range descTriggers {
+			ot.triggers[i].init(&descTriggers[i])
+		}
+		sort.Slice(ot.triggers, func(i, j int) bool {
+			return ot.triggers[i].name < ot.triggers[j].name
+		})
+	}
+
 	// Add stats last, now that other metadata is initialized.
 	if stats != nil {
 		ot.stats = make([]optTableStat, len(stats))
@@ -1304,6 +1352,16 @@ func (ot *optTable) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	return &ot.uniqueConstraints[i]
 }
 
+// ExclusionConstraintCount is part of the cat.Table interface.
+func (ot *optTable) ExclusionConstraintCount() int {
+	return len(ot.exclusionConstraints)
+}
+
+// ExclusionConstraint is part of the cat.Table interface.
+func (ot *optTable) ExclusionConstraint(i int) cat.ExclusionConstraint {
+	return &ot.exclusionConstraints[i]
+}
+
 // Zone is part of the cat.Table interface.
 func (ot *optTable) Zone() cat.Zone {
 	return ot.zone
@@ -1389,6 +1447,16 @@ func (ot *optTable) IsHypothetical() bool {
 	return false
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (ot *optTable) TriggerCount() int {
+	return len(ot.triggers)
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optTable) Trigger(i int) cat.Trigger {
+	return &ot.triggers[i]
+}
+
 // lookupColumnOrdinal returns the ordinal of the column with the given ID. A
 // cache makes the lookup O(1).
 func (ot *optTable) lookupColumnOrdinal(colID descpb.ColumnID) (int, error) {
@@ -1700,6 +1768,11 @@ func (oi *optIndex) GeoConfig() geopb.Config {
 	return oi.idx.IndexDesc().GeoConfig
 }
 
+// VecConfig is part of the cat.Index interface.
+func (oi *optIndex) VecConfig() catpb.VectorIndexConfig {
+	return oi.idx.IndexDesc().VecConfig
+}
+
 // Version is part of the cat.Index interface.
 func (oi *optIndex) Version() descpb.IndexDescriptorVersion {
 	return oi.idx.GetVersion()
@@ -1778,6 +1851,83 @@ func (oc *optCheckConstraint) ColumnOrdinal(i int) int {
 	return ord
 }
 
+// optTrigger is a wrapper around descpb.TriggerDescriptor that implements the
+// cat.Trigger interface.
+type optTrigger struct {
+	name       tree.Name
+	actionTime tree.TriggerActionTime
+	events     []tree.TriggerEvent
+	forEachRow bool
+	whenExpr   string
+	funcID     cat.StableID
+	funcArgs   []string
+	enabled    bool
+}
+
+var _ cat.Trigger = &optTrigger{}
+
+func (ot *optTrigger) init(desc *descpb.TriggerDescriptor) {
+	ot.name = tree.Name(desc.Name)
+	ot.actionTime = tree.TriggerActionTimeFromProto[desc.ActionTime]
+	ot.events = make([]tree.TriggerEvent, len(desc.Events))
+	for i, event := range desc.Events {
+		ot.events[i].EventType = tree.TriggerEventTypeFromProto[event.Type]
+		for _, colName := range event.ColumnNames {
+			ot.events[i].Columns = append(ot.events[i].Columns, tree.Name(colName))
+		}
+	}
+	ot.forEachRow = desc.ForEachRow
+	ot.whenExpr = desc.WhenExpr
+	ot.funcID = cat.StableID(desc.FuncID)
+	ot.funcArgs = desc.FuncArgs
+	ot.enabled = desc.Enabled
+}
+
+// Name is part of the cat.Trigger interface.
+func (ot *optTrigger) Name() tree.Name {
+	return ot.name
+}
+
+// ActionTime is part of the cat.Trigger interface.
+func (ot *optTrigger) ActionTime() tree.TriggerActionTime {
+	return ot.actionTime
+}
+
+// EventCount is part of the cat.Trigger interface.
+func (ot *optTrigger) EventCount() int {
+	return len(ot.events)
+}
+
+// Event is part of the cat.Trigger interface.
+func (ot *optTrigger) Event(i int) tree.TriggerEvent {
+	return ot.events[i]
+}
+
+// ForEachRow is part of the cat.Trigger interface.
+func (ot *optTrigger) ForEachRow() bool {
+	return ot.forEachRow
+}
+
+// WhenExpr is part of the cat.Trigger interface.
+func (ot *optTrigger) WhenExpr() string {
+	return ot.whenExpr
+}
+
+// FuncID is part of the cat.Trigger interface.
+func (ot *optTrigger) FuncID() cat.StableID {
+	return ot.funcID
+}
+
+// FuncArgs is part of the cat.Trigger interface.
+func (ot *optTrigger) FuncArgs() []string {
+	return ot.funcArgs
+}
+
+// Enabled is part of the cat.Trigger interface.
+func (ot *optTrigger) Enabled() bool {
+	return ot.enabled
+}
+
 type optTableStat struct {
 	stat           *stats.TableStatistic
 	columnOrdinals []int
@@ -1800,13 +1950,23 @@ func (os *optTableStat) init(
 		}
 	}
 
-	// Verify that histogram column type matches table column type.
-	// TODO(49698): When we support multi-column histograms this check will need
-	// adjustment.
-	if len(os.columnOrdinals) == 1 {
+	// Verify that histogram column type matches table column type. The
+	// histogram of a multi-column statistic is built over tuples of the column
+	// values.
+	if len(os.columnOrdinals) > 0 {
 		col := tab.getCol(os.columnOrdinals[0])
+		colType, colName := col.GetType(), col.GetName()
+		if len(os.columnOrdinals) > 1 {
+			colTypes := make([]*types.T, len(os.columnOrdinals))
+			colNames := make([]string, len(os.columnOrdinals))
+			for i, ord := range os.columnOrdinals {
+				col := tab.getCol(ord)
+				colTypes[i], colNames[i] = col.GetType(), col.GetName()
+			}
+			colType, colName = types.MakeTuple(colTypes), strings.Join(colNames, ",")
+		}
 		if err := stat.HistogramData.TypeCheck(
-			col.GetType(), string(tab.Name()), col.GetName(), stats.TSFromTime(stat.CreatedAt),
+			colType, string(tab.Name()), colName, stats.TSFromTime(stat.CreatedAt),
 		); err != nil {
 			// Column type in the histogram differs from column type in the
 			// table. This is only possible if we somehow re-used the same column ID
@@ -1904,6 +2064,23 @@ func (os *optTableStat) IsAuto() bool {
 	return os.stat.IsAuto()
 }
 
+// MostCommonValues is part of the cat.TableStatistic interface.
+func (os *optTableStat) MostCommonValues() []cat.MostCommonValue {
+	return os.stat.MostCommonValues
+}
+
+// Dependencies is part of the cat.TableStatistic interface.
+func (os *optTableStat) Dependencies() []cat.ColumnDependency {
+	if os.stat.HistogramData == nil || len(os.stat.HistogramData.Dependencies) == 0 {
+		return nil
+	}
+	deps := make([]cat.ColumnDependency, len(os.stat.HistogramData.Dependencies))
+	for i, dep := range os.stat.HistogramData.Dependencies {
+		deps[i] = cat.ColumnDependency{From: int(dep.From), To: int(dep.To), Degree: dep.Degree}
+	}
+	return deps
+}
+
 // optFamily is a wrapper around descpb.ColumnFamilyDescriptor that keeps a
 // reference to the table wrapper.
 type optFamily struct {
@@ -1955,8 +2132,9 @@ type optUniqueConstraint struct {
 	columns   []descpb.ColumnID
 	predicate string
 
-	withoutIndex bool
-	validity     descpb.ConstraintValidity
+	withoutIndex  bool
+	validity      descpb.ConstraintValidity
+	deferrability tree.ConstraintDeferrability
 
 	uniquenessGuaranteedByAnotherIndex bool
 }
@@ -2003,7 +2181,9 @@ func (u *optUniqueConstraint) WithoutIndex() bool {
 
 // Validated is part of the cat.UniqueConstraint interface.
 func (u *optUniqueConstraint) Validated() bool {
-	return u.validity == descpb.ConstraintValidity_Validated
+	// Duplicates are allowed to exist mid-transaction if the constraint is
+	// deferrable, so it cannot be relied upon.
+	return u.validity == descpb.ConstraintValidity_Validated && !u.deferrability.IsDeferrable()
 }
 
 // UniquenessGuaranteedByAnotherIndex is part of the cat.UniqueConstraint
@@ -2014,6 +2194,57 @@ func (u *optUniqueConstraint) UniquenessGuaranteedByAnotherIndex() bool {
 	return u.uniquenessGuaranteedByAnotherIndex
 }
 
+// Deferrability is part of the cat.UniqueConstraint interface.
+func (u *optUniqueConstraint) Deferrability() tree.ConstraintDeferrability {
+	return u.deferrability
+}
+
+// optExclusionConstraint implements cat.ExclusionConstraint and represents an
+// EXCLUDE constraint.
+type optExclusionConstraint struct {
+	name string
+
+	table     cat.StableID
+	columns   []descpb.ColumnID
+	operators []treecmp.ComparisonOperator
+	predicate string
+}
+
+var _ cat.ExclusionConstraint = &optExclusionConstraint{}
+
+// Name is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Name() string {
+	return e.name
+}
+
+// ColumnCount is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) ColumnCount() int {
+	return len(e.columns)
+}
+
+// ColumnOrdinal is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) ColumnOrdinal(tab cat.Table, i int) int {
+	if tab.ID() != e.table {
+		panic(errors.AssertionFailedf(
+			"invalid table %d passed to ColumnOrdinal (expected %d)",
+			tab.ID(), e.table,
+		))
+	}
+	optTab := convertTableToOptTable(tab)
+	ord, _ := optTab.lookupColumnOrdinal(e.columns[i])
+	return ord
+}
+
+// Operator is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Operator(i int) treecmp.ComparisonOperator {
+	return e.operators[i]
+}
+
+// Predicate is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Predicate() (string, bool) {
+	return e.predicate, e.predicate != ""
+}
+
 // optForeignKeyConstraint implements cat.ForeignKeyConstraint and represents a
 // foreign key relationship. Both the origin and the referenced table store the
 // same optForeignKeyConstraint (as an outbound and inbound reference,
@@ -2027,10 +2258,11 @@ type optForeignKeyConstraint struct {
 	referencedTable   cat.StableID
 	referencedColumns []descpb.ColumnID
 
-	validity     descpb.ConstraintValidity
-	match        tree.CompositeKeyMatchMethod
-	deleteAction tree.ReferenceAction
-	updateAction tree.ReferenceAction
+	validity      descpb.ConstraintValidity
+	match         tree.CompositeKeyMatchMethod
+	deleteAction  tree.ReferenceAction
+	updateAction  tree.ReferenceAction
+	deferrability tree.ConstraintDeferrability
 }
 
 var _ cat.ForeignKeyConstraint = &optForeignKeyConstraint{}
@@ -2084,7 +2316,9 @@ func (fk *optForeignKeyConstraint) ReferencedColumnOrdinal(referencedTable cat.T
 
 // Validated is part of the cat.ForeignKeyConstraint interface.
 func (fk *optForeignKeyConstraint) Validated() bool {
-	return fk.validity == descpb.ConstraintValidity_Validated
+	// Orphaned rows are allowed to exist mid-transaction if the constraint is
+	// deferrable, so it cannot be relied upon.
+	return fk.validity == descpb.ConstraintValidity_Validated && !fk.deferrability.IsDeferrable()
 }
 
 // MatchMethod is part of the cat.ForeignKeyConstraint interface.
@@ -2102,6 +2336,11 @@ func (fk *optForeignKeyConstraint) UpdateReferenceAction() tree.ReferenceAction
 	return fk.updateAction
 }
 
+// Deferrability is part of the cat.ForeignKeyConstraint interface.
+func (fk *optForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	return fk.deferrability
+}
+
 // optVirtualTable is similar to optTable but is used with virtual tables.
 type optVirtualTable struct {
 	desc catalog.TableDescriptor
@@ -2404,6 +2643,16 @@ func (ot *optVirtualTable) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	panic(errors.AssertionFailedf("no unique constraints"))
 }
 
+// ExclusionConstraintCount is part of the cat.Table interface.
+func (ot *optVirtualTable) ExclusionConstraintCount() int {
+	return 0
+}
+
+// ExclusionConstraint is part of the cat.Table interface.
+func (ot *optVirtualTable) ExclusionConstraint(i int) cat.ExclusionConstraint {
+	panic(errors.AssertionFailedf("no exclusion constraints"))
+}
+
 // Zone is part of the cat.Table interface.
 func (ot *optVirtualTable) Zone() cat.Zone {
 	panic(errors.AssertionFailedf("no zone"))
@@ -2449,6 +2698,16 @@ func (ot *optVirtualTable) IsHypothetical() bool {
 	return false
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (ot *optVirtualTable) TriggerCount() int {
+	return 0
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optVirtualTable) Trigger(i int) cat.Trigger {
+	panic(errors.AssertionFailedf("no triggers"))
+}
+
 // CollectTypes is part of the cat.DataSource interface.
 func (ot *optVirtualTable) CollectTypes(ord int) (descpb.IDs, error) {
 	col := ot.desc.AllColumns()[ord]
@@ -2628,6 +2887,11 @@ func (oi *optVirtualIndex) GeoConfig() geopb.Config {
 	return geopb.Config{}
 }
 
+// VecConfig is part of the cat.Index interface.
+func (oi *optVirtualIndex) VecConfig() catpb.VectorIndexConfig {
+	return catpb.VectorIndexConfig{}
+}
+
 // Version is part of the cat.Index interface.
 func (oi *optVirtualIndex) Version() descpb.IndexDescriptorVersion {
 	return 0
@@ -2728,6 +2992,21 @@ func collectTypes(col catalog.Column) (descpb.IDs, error) {
 	return ids, nil
 }
 
+// columnDefaultExpr returns the default expression of the given column. A
+// column of a domain type without a default expression of its own defaults to
+// the default expression of the domain.
+func columnDefaultExpr(col catalog.Column) *string {
+	cd := col.ColumnDesc()
+	if cd.DefaultExpr != nil {
+		return cd.DefaultExpr
+	}
+	if typ := col.GetType(); typ.IsDomain() && typ.TypeMeta.DomainData != nil &&
+		typ.TypeMeta.DomainData.DefaultExpr != "" {
+		return &typ.TypeMeta.DomainData.DefaultExpr
+	}
+	return nil
+}
+
 // mapGeneratedAsIdentityType maps a descpb.GeneratedAsIdentityType into corresponding
 // cat.GeneratedAsIdentityType. This is a helper function for the read access to
 // the GeneratedAsIdentityType attribute for descpb.ColumnDescriptor.
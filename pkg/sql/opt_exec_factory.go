@@ -543,6 +543,7 @@ func (ef *execFactory) addAggregations(n *groupNode, aggregations []exec.AggInfo
 			agg.DistsqlBlocklist,
 		)
 		f.filterRenderIdx = int(agg.Filter)
+		f.userDefined = agg.UserDefined
 
 		n.funcs = append(n.funcs, f)
 	}
@@ -1145,6 +1146,7 @@ func (ef *execFactory) ConstructWindow(root exec.Node, wi exec.WindowInfo) (exec
 			partitionIdxs:  partitionIdxs,
 			columnOrdering: wi.Ordering,
 			frame:          wi.Exprs[i].WindowDef.Frame,
+			userDefined:    wi.UserDefinedAggs[i],
 		}
 		if len(wi.Ordering) == 0 {
 			frame := p.funcs[i].frame
@@ -1893,6 +1895,34 @@ func (ef *execFactory) ConstructCreateFunction(
 	}, nil
 }
 
+// ConstructCreateTrigger is part of the exec.Factory interface.
+func (ef *execFactory) ConstructCreateTrigger(
+	ct *tree.CreateTrigger,
+	deps opt.SchemaDeps,
+	typeDeps opt.SchemaTypeDeps,
+	functionDeps opt.SchemaFunctionDeps,
+) (exec.Node, error) {
+	if err := checkSchemaChangeEnabled(
+		ef.ctx,
+		ef.planner.ExecCfg(),
+		"CREATE TRIGGER",
+	); err != nil {
+		return nil, err
+	}
+
+	planDeps, typeDepSet, funcDepList, err := toPlanDependencies(deps, typeDeps, functionDeps)
+	if err != nil {
+		return nil, err
+	}
+
+	return &createTriggerNode{
+		ct:           ct,
+		planDeps:     planDeps,
+		typeDeps:     typeDepSet,
+		functionDeps: funcDepList,
+	}, nil
+}
+
 func toPlanDependencies(
 	deps opt.SchemaDeps, typeDeps opt.SchemaTypeDeps, funcDeps opt.SchemaFunctionDeps,
 ) (planDependencies, typeDependencies, functionDependencies, error) {
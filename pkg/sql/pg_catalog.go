@@ -966,6 +966,7 @@ func populateTableConstraints(
 		consrc := tree.DNull
 		conbin := tree.DNull
 		condef := tree.DNull
+		deferrability := tree.ConstraintNotDeferrable
 
 		// Determine constraint kind-specific fields.
 		var err error
@@ -1014,6 +1015,7 @@ func populateTableConstraints(
 		} else if fk := c.AsForeignKey(); fk != nil {
 			conoid = h.ForeignKeyConstraintOid(db.GetID(), sc.GetID(), table.GetID(), fk)
 			contype = conTypeFK
+			deferrability = tree.ConstraintDeferrability(fk.ForeignKeyDesc().Deferrability)
 			// Foreign keys don't have a single linked index. Pick the first one
 			// that matches on the referenced table.
 			referencedTable, err := tableLookup.getTableByID(fk.GetReferencedTableID())
@@ -1059,6 +1061,7 @@ func populateTableConstraints(
 			conoid = h.UniqueWithoutIndexConstraintOid(
 				db.GetID(), sc.GetID(), table.GetID(), uwoi,
 			)
+			deferrability = tree.ConstraintDeferrability(uwoi.UniqueWithoutIndexDesc().Deferrability)
 			f.WriteString("UNIQUE WITHOUT INDEX (")
 			colNames, err := catalog.ColumnNamesForIDs(table, uwoi.UniqueWithoutIndexDesc().ColumnIDs)
 			if err != nil {
@@ -1066,6 +1069,7 @@ func populateTableConstraints(
 			}
 			f.WriteString(strings.Join(colNames, ", "))
 			f.WriteByte(')')
+			f.FormatNode(&deferrability)
 			if !uwoi.IsConstraintValidated() {
 				f.WriteString(" NOT VALID")
 			}
@@ -1096,13 +1100,15 @@ func populateTableConstraints(
 			condef = tree.NewDString(fmt.Sprintf("CHECK ((%s))%s", displayExpr, validity))
 		}
 
+		condeferrable := tree.MakeDBool(tree.DBool(deferrability.IsDeferrable()))
+		condeferred := tree.MakeDBool(tree.DBool(deferrability == tree.DeferrableInitiallyDeferred))
 		if err := addRow(
 			conoid,                   // oid
 			dNameOrNull(c.GetName()), // conname
 			namespaceOid,             // connamespace
 			contype,                  // contype
-			tree.DBoolFalse,          // condeferrable
-			tree.DBoolFalse,          // condeferred
+			condeferrable,            // condeferrable
+			condeferred,              // condeferred
 			tree.MakeDBool(tree.DBool(!c.IsConstraintUnvalidated())), // convalidated
 			tblOid,         // conrelid
 			oidZero,        // contypid
@@ -2644,6 +2650,14 @@ func addPgProcUDFRow(
 	} else if fnDesc.GetLanguage() == catpb.Function_SQL {
 		lang = languageSqlOid
 	}
+	prosrc := tree.NewDString(fnDesc.GetFunctionBody())
+	if fnDesc.IsAggregate() {
+		// Like in Postgres, aggregates are described by pg_aggregate rather than
+		// by a function body.
+		kind = proKindAggregate
+		lang = languageInternalOid
+		prosrc = tree.NewDString("aggregate_dummy")
+	}
 
 	argTypes, allArgTypesArray := tree.NewDArray(types.Oid), tree.NewDArray(types.Oid)
 	argModesArray, argNamesArray := tree.NewDArray(types.String), tree.NewDArray(types.String)
@@ -2651,6 +2665,7 @@ func addPgProcUDFRow(
 	var foundAnyArgNames bool
 	var nArgs, nArgDefaults int
 	var argDefaultsBuilder strings.Builder
+	variadicType := oidZero
 	for _, param := range fnDesc.GetParams() {
 		class := funcdesc.ToTreeRoutineParamClass(param.Class)
 		if tree.IsInParamClass(class) {
@@ -2689,6 +2704,7 @@ func addPgProcUDFRow(
 			argMode = proArgModeInOut
 		case tree.RoutineParamVariadic:
 			argMode = proArgModeVariadic
+			variadicType = tree.NewDOid(param.Type.ArrayContents().Oid())
 		default:
 			return errors.AssertionFailedf("unknown parameter class %d", class)
 		}
@@ -2724,7 +2740,7 @@ func addPgProcUDFRow(
 		lang,            // prolang
 		tree.DNull,      // procost
 		tree.DNull,      // prorows
-		oidZero,         // provariadic // TODO(88947): this might need an adjustment.
+		variadicType,    // provariadic
 		tree.DNull,      // prosupport
 		kind,            // prokind
 		tree.DBoolFalse, // prosecdef
@@ -2742,7 +2758,7 @@ func addPgProcUDFRow(
 		argNames,                                        // proargnames
 		argDefaults,                                     // proargdefaults
 		tree.DNull,                                      // protrftypes
-		tree.NewDString(fnDesc.GetFunctionBody()),       // prosrc
+		prosrc,                                          // prosrc
 		tree.DNull,                                      // probin
 		tree.DNull,                                      // prosqlbody
 		tree.DNull,                                      // proconfig
@@ -3255,17 +3271,16 @@ https://www.postgresql.org/docs/9.5/catalog-pg-trigger.html`,
 }
 
 var (
-	typTypeBase      = tree.NewDString("b")
-	typTypeComposite = tree.NewDString("c")
-	typTypeDomain    = tree.NewDString("d")
-	typTypeEnum      = tree.NewDString("e")
-	typTypePseudo    = tree.NewDString("p")
-	typTypeRange     = tree.NewDString("r")
+	typTypeBase       = tree.NewDString("b")
+	typTypeComposite  = tree.NewDString("c")
+	typTypeDomain     = tree.NewDString("d")
+	typTypeEnum       = tree.NewDString("e")
+	typTypeMultirange = tree.NewDString("m")
+	typTypePseudo     = tree.NewDString("p")
+	typTypeRange      = tree.NewDString("r")
 
 	// Avoid unused warning for constants.
-	_ = typTypeDomain
 	_ = typTypePseudo
-	_ = typTypeRange
 
 	// See https://www.postgresql.org/docs/9.6/static/catalog-pg-type.html#CATALOG-TYPCATEGORY-TABLE.
 	typCategoryArray       = tree.NewDString("A")
@@ -3393,6 +3408,12 @@ func addPGTypeRow(
 		if isUDT {
 			typrelid = tree.NewDOid(typ.Oid())
 		}
+	case types.RangeFamily:
+		typType = typTypeRange
+		typArray = tree.NewDOid(types.CalcArrayOid(typ))
+	case types.MultirangeFamily:
+		typType = typTypeMultirange
+		typArray = tree.NewDOid(types.CalcArrayOid(typ))
 	case types.VoidFamily:
 		// void does not have an array type.
 	case types.TriggerFamily:
@@ -3403,6 +3424,19 @@ func addPGTypeRow(
 	if cat == typCategoryPseudo {
 		typType = typTypePseudo
 	}
+	typNotNull := tree.DBoolFalse
+	typBaseType := oidZero
+	typDefault := tree.DNull
+	if typ.IsDomain() {
+		typType = typTypeDomain
+		typBaseType = tree.NewDOid(typ.DomainBaseType().Oid())
+		if domain := typ.TypeMeta.DomainData; domain != nil {
+			typNotNull = tree.MakeDBool(tree.DBool(domain.NotNull))
+			if domain.DefaultExpr != "" {
+				typDefault = tree.NewDString(domain.DefaultExpr)
+			}
+		}
+	}
 	typname := typ.PGName()
 	typDelim := tree.NewDString(typ.Delimiter())
 	return addRow(
@@ -3432,13 +3466,13 @@ func addPGTypeRow(
 
 		tree.DNull,      // typalign
 		tree.DNull,      // typstorage
-		tree.DBoolFalse, // typnotnull
-		oidZero,         // typbasetype
+		typNotNull,      // typnotnull
+		typBaseType,     // typbasetype
 		negOneVal,       // typtypmod
 		zeroVal,         // typndims
 		typColl(typ, h), // typcollation
 		tree.DNull,      // typdefaultbin
-		tree.DNull,      // typdefault
+		typDefault,      // typdefault
 		tree.DNull,      // typacl
 	)
 }
@@ -4781,6 +4815,8 @@ var datumToTypeCategory = map[types.Family]*tree.DString{
 	types.OidFamily:         typCategoryNumeric,
 	types.PGLSNFamily:       typCategoryUserDefined,
 	types.PGVectorFamily:    typCategoryUserDefined,
+	types.RangeFamily:       typCategoryRange,
+	types.MultirangeFamily:  typCategoryRange,
 	types.RefCursorFamily:   typCategoryUserDefined,
 	types.UuidFamily:        typCategoryUserDefined,
 	types.INetFamily:        typCategoryNetworkAddr,
@@ -4907,11 +4943,84 @@ https://www.postgresql.org/docs/9.6/catalog-pg-aggregate.html`,
 						}
 					}
 				}
-				return nil
+				return forEachSchema(ctx, p, db, true /* requiresPrivileges */, func(ctx context.Context, scDesc catalog.SchemaDescriptor) error {
+					return scDesc.ForEachFunctionSignature(func(sig descpb.SchemaDescriptor_FunctionSignature) error {
+						if !sig.IsAggregate {
+							return nil
+						}
+						fnDesc, err := p.Descriptors().ByIDWithoutLeased(p.Txn()).WithoutNonPublic().Get().Function(ctx, sig.ID)
+						if err != nil {
+							return err
+						}
+						return addPgAggregateUDFRow(ctx, p, fnDesc, addRow)
+					})
+				})
 			})
 	},
 }
 
+// addPgAggregateUDFRow adds the pg_aggregate row of a user-defined aggregate.
+func addPgAggregateUDFRow(
+	ctx context.Context,
+	p *planner,
+	fnDesc catalog.FunctionDescriptor,
+	addRow func(...tree.Datum) error,
+) error {
+	agg := fnDesc.FuncDesc().Aggregate
+	regProc := func(fnOID oid.Oid) (tree.Datum, error) {
+		if fnOID == 0 {
+			return tree.NewDOidWithTypeAndName(0, types.RegProc, "-"), nil
+		}
+		name, _, err := p.ResolveFunctionByOID(ctx, fnOID)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDOid(fnOID).AsRegProc(name.Object()), nil
+	}
+	transFn, err := regProc(agg.StateTransitionFunc)
+	if err != nil {
+		return err
+	}
+	finalFn, err := regProc(agg.FinalFunc)
+	if err != nil {
+		return err
+	}
+	combineFn, err := regProc(agg.CombineFunc)
+	if err != nil {
+		return err
+	}
+	noFn, _ := regProc(0)
+	initVal := tree.DNull
+	if agg.InitialCondition != nil {
+		initVal = tree.NewDString(*agg.InitialCondition)
+	}
+	fnOID := catid.FuncIDToOID(fnDesc.GetID())
+	return addRow(
+		tree.NewDOid(fnOID).AsRegProc(fnDesc.GetName()), // aggfnoid
+		tree.NewDString("n"),                            // aggkind
+		zeroVal,                                         // aggnumdirectargs
+		transFn,                                         // aggtransfn
+		finalFn,                                         // aggfinalfn
+		combineFn,                                       // aggcombinefn
+		noFn,                                            // aggserialfn
+		noFn,                                            // aggdeserialfn
+		noFn,                                            // aggmtransfn
+		noFn,                                            // aggminvtransfn
+		noFn,                                            // aggmfinalfn
+		tree.DBoolFalse,                                 // aggfinalextra
+		tree.DBoolFalse,                                 // aggmfinalextra
+		oidZero,                                         // aggsortop
+		tree.NewDOid(agg.StateType.Oid()),               // aggtranstype
+		tree.DNull,                                      // aggtransspace
+		tree.DNull,                                      // aggmtranstype
+		tree.DNull,                                      // aggmtransspace
+		initVal,                                         // agginitval
+		tree.DNull,                                      // aggminitval
+		tree.DNull,                                      // aggfinalmodify
+		tree.DNull,                                      // aggmfinalmodify
+	)
+}
+
 // oidHasher provides a consistent hashing mechanism for object identifiers in
 // pg_catalog tables, allowing for reliable joins across tables.
 //
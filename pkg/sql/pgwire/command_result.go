@@ -59,6 +59,7 @@ type commandResult struct {
 	// buffer contains items that are sent before the connection is closed.
 	buffer struct {
 		notices            []pgnotice.Notice
+		notifications      []sql.Notification
 		paramStatusUpdates []paramStatusUpdate
 	}
 
@@ -147,6 +148,11 @@ func (r *commandResult) Close(ctx context.Context, t sql.TransactionStatusIndica
 			panic(errors.NewAssertionErrorWithWrappedErrf(err, "unexpected err when sending notice"))
 		}
 	}
+	for _, notification := range r.buffer.notifications {
+		if err := r.conn.bufferNotification(notification); err != nil {
+			panic(errors.NewAssertionErrorWithWrappedErrf(err, "unexpected err when sending notification"))
+		}
+	}
 
 	// Send a completion message, specific to the type of result.
 	switch r.typ {
@@ -332,6 +338,11 @@ func (r *commandResult) BufferNotice(notice pgnotice.Notice) {
 	r.buffer.notices = append(r.buffer.notices, notice)
 }
 
+// BufferNotification is part of the sql.NotificationResult interface.
+func (r *commandResult) BufferNotification(notification sql.Notification) {
+	r.buffer.notifications = append(r.buffer.notifications, notification)
+}
+
 // SendNotice is part of the sql.RestrictedCommandResult interface.
 func (r *commandResult) SendNotice(ctx context.Context, notice pgnotice.Notice) error {
 	if err := r.conn.bufferNotice(ctx, notice); err != nil {
@@ -642,6 +653,10 @@ func (r *limitedCommandResult) moreResultsNeeded(ctx context.Context) error {
 			if err := r.conn.Flush(r.pos); err != nil {
 				return err
 			}
+		case sql.SendNotifications:
+			// The portal is open inside a transaction, so the notifications stay
+			// queued until the session becomes idle.
+			r.conn.stmtBuf.AdvanceOne()
 		default:
 			// If the portal is immediately followed by a COMMIT, we can proceed and
 			// let the portal be destroyed at the end of the transaction.
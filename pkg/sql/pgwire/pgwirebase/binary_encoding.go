@@ -0,0 +1,566 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwirebase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/apd/v3"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// This file contains the encoders of the binary format of datums. They are
+// used both for the values of DataRow messages and for COPY TO in the binary
+// format. Every value is written with its int32 length prefix.
+
+func putInt16(b *bytes.Buffer, v int16) {
+	var buf [2]byte
+	binary.BigEndian.PutUint16(buf[:], uint16(v))
+	b.Write(buf[:])
+}
+
+func putInt32(b *bytes.Buffer, v int32) {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(v))
+	b.Write(buf[:])
+}
+
+func putInt64(b *bytes.Buffer, v int64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(v))
+	b.Write(buf[:])
+}
+
+// reserveLength writes a placeholder for the length prefix of a value whose
+// length isn't known upfront, and returns the index of the placeholder.
+func reserveLength(b *bytes.Buffer) int {
+	idx := b.Len()
+	putInt32(b, 0)
+	return idx
+}
+
+// finishLength fills the length prefix reserved at idx with the number of
+// bytes written after it.
+func finishLength(b *bytes.Buffer, idx int) {
+	binary.BigEndian.PutUint32(b.Bytes()[idx:idx+4], uint32(b.Len()-(idx+4)))
+}
+
+// WriteLengthPrefixedString writes a length-prefixed string. The length is
+// encoded as an int32.
+func WriteLengthPrefixedString(b *bytes.Buffer, s string) {
+	putInt32(b, int32(len(s)))
+	b.WriteString(s)
+}
+
+// WriteBinaryBool writes the binary encoding of a BOOL.
+func WriteBinaryBool(b *bytes.Buffer, v bool) {
+	putInt32(b, 1)
+	if v {
+		b.WriteByte(1)
+	} else {
+		b.WriteByte(0)
+	}
+}
+
+// WriteBinaryInt writes the binary encoding of an integer of type t.
+func WriteBinaryInt(b *bytes.Buffer, v int64, t *types.T) error {
+	switch t.Oid() {
+	case oid.T_int2:
+		putInt32(b, 2)
+		putInt16(b, int16(v))
+	case oid.T_int4:
+		putInt32(b, 4)
+		putInt32(b, int32(v))
+	case oid.T_int8:
+		putInt32(b, 8)
+		putInt64(b, v)
+	default:
+		return errors.Errorf("unsupported int oid: %v", t.Oid())
+	}
+	return nil
+}
+
+// WriteBinaryFloat writes the binary encoding of a float of type t.
+func WriteBinaryFloat(b *bytes.Buffer, v float64, t *types.T) error {
+	switch t.Oid() {
+	case oid.T_float4:
+		putInt32(b, 4)
+		putInt32(b, int32(math.Float32bits(float32(v))))
+	case oid.T_float8:
+		putInt32(b, 8)
+		putInt64(b, int64(math.Float64bits(v)))
+	default:
+		return errors.Errorf("unsupported float oid: %v", t.Oid())
+	}
+	return nil
+}
+
+// WriteBinaryDecimal writes the binary encoding of a DECIMAL.
+func WriteBinaryDecimal(b *bytes.Buffer, v *apd.Decimal) {
+	if v.Form != apd.Finite {
+		putInt32(b, 8)
+		// 0 digits.
+		putInt32(b, 0)
+		if v.Form == apd.Infinite {
+			// The 0x20 in the DScale byte does not actually seem to be part of the
+			// spec, but that's what PostgreSQL outputs.
+			if v.Negative {
+				// https://github.com/postgres/postgres/blob/a57d312a7706321d850faa048a562a0c0c01b835/src/backend/utils/adt/numeric.c#L200
+				b.Write([]byte{0xf0, 0, 0, 0x20})
+			} else {
+				// https://github.com/postgres/postgres/blob/a57d312a7706321d850faa048a562a0c0c01b835/src/backend/utils/adt/numeric.c#L201
+				b.Write([]byte{0xd0, 0, 0, 0x20})
+			}
+			// Official Infinity support for DECIMAL was added in CockroachDB 22.1,
+			// so let's keep tracking usage.
+			telemetry.Inc(sqltelemetry.BinaryDecimalInfinityCounter)
+		} else {
+			// https://github.com/postgres/postgres/blob/ffa4cbd623dd69f9fa99e5e92426928a5782cf1a/src/backend/utils/adt/numeric.c#L169
+			b.Write([]byte{0xc0, 0, 0, 0})
+		}
+		return
+	}
+
+	alloc := struct {
+		pgNum PGNumeric
+
+		bigI apd.BigInt
+	}{
+		pgNum: PGNumeric{
+			// Since we use 2000 as the exponent limits in tree.DecimalCtx, this
+			// conversion should not overflow.
+			Dscale: int16(-v.Exponent),
+		},
+	}
+
+	if v.Sign() >= 0 {
+		alloc.pgNum.Sign = PGNumericPos
+	} else {
+		alloc.pgNum.Sign = PGNumericNeg
+	}
+
+	isZero := func(r rune) bool {
+		return r == '0'
+	}
+
+	// Mostly cribbed from libpqtypes' str2num.
+	digits := strings.TrimLeftFunc(alloc.bigI.Abs(&v.Coeff).String(), isZero)
+	dweight := len(digits) - int(alloc.pgNum.Dscale) - 1
+	digits = strings.TrimRightFunc(digits, isZero)
+
+	if dweight >= 0 {
+		alloc.pgNum.Weight = int16((dweight+1+PGDecDigits-1)/PGDecDigits - 1)
+	} else {
+		alloc.pgNum.Weight = int16(-((-dweight-1)/PGDecDigits + 1))
+	}
+	offset := (int(alloc.pgNum.Weight)+1)*PGDecDigits - (dweight + 1)
+	alloc.pgNum.Ndigits = int16((len(digits) + offset + PGDecDigits - 1) / PGDecDigits)
+
+	if len(digits) == 0 {
+		offset = 0
+		alloc.pgNum.Ndigits = 0
+		alloc.pgNum.Weight = 0
+	}
+
+	digitIdx := -offset
+
+	nextDigit := func() int16 {
+		var ndigit int16
+		for nextDigitIdx := digitIdx + PGDecDigits; digitIdx < nextDigitIdx; digitIdx++ {
+			ndigit *= 10
+			if digitIdx >= 0 && digitIdx < len(digits) {
+				ndigit += int16(digits[digitIdx] - '0')
+			}
+		}
+		return ndigit
+	}
+
+	// The dscale is defined as number of digits (in base 10) visible
+	// after the decimal separator, so it can't be negative.
+	if alloc.pgNum.Dscale < 0 {
+		alloc.pgNum.Dscale = 0
+	}
+
+	putInt32(b, int32(2*(4+alloc.pgNum.Ndigits)))
+	putInt16(b, alloc.pgNum.Ndigits)
+	putInt16(b, alloc.pgNum.Weight)
+	putInt16(b, int16(alloc.pgNum.Sign))
+	putInt16(b, alloc.pgNum.Dscale)
+
+	for digitIdx < len(digits) {
+		putInt16(b, nextDigit())
+	}
+}
+
+// WriteBinaryBytes writes the binary encoding of a BYTES or UUID.
+func WriteBinaryBytes(b *bytes.Buffer, v []byte) {
+	putInt32(b, int32(len(v)))
+	b.Write(v)
+}
+
+// WriteBinaryString writes the binary encoding of a string of type t.
+func WriteBinaryString(b *bytes.Buffer, v string, t *types.T) {
+	s := tree.ResolveBlankPaddedChar(v, t)
+	if t.Oid() == oid.T_char && s == "" {
+		// Match Postgres and always explicitly include a null byte if we have
+		// an empty string for the "char" type in the binary format.
+		s = string([]byte{0})
+	}
+	WriteLengthPrefixedString(b, s)
+}
+
+// WriteBinaryTimestamp writes the binary encoding of a TIMESTAMP.
+func WriteBinaryTimestamp(b *bytes.Buffer, v time.Time) {
+	putInt32(b, 8)
+	putInt64(b, TimeToPgBinary(v, nil))
+}
+
+// WriteBinaryTimestampTZ writes the binary encoding of a TIMESTAMPTZ.
+func WriteBinaryTimestampTZ(b *bytes.Buffer, v time.Time, sessionLoc *time.Location) {
+	putInt32(b, 8)
+	putInt64(b, TimeToPgBinary(v, sessionLoc))
+}
+
+// WriteBinaryDate writes the binary encoding of a DATE.
+func WriteBinaryDate(b *bytes.Buffer, v pgdate.Date) {
+	putInt32(b, 4)
+	putInt32(b, v.PGEpochDays())
+}
+
+// WriteBinaryInterval writes the binary encoding of an INTERVAL.
+func WriteBinaryInterval(b *bytes.Buffer, v duration.Duration) {
+	putInt32(b, 16)
+	putInt64(b, v.Nanos()/int64(time.Microsecond/time.Nanosecond))
+	putInt32(b, int32(v.Days))
+	putInt32(b, int32(v.Months))
+}
+
+// WriteBinaryJSON writes the binary encoding of a JSON or JSONB of type t.
+func WriteBinaryJSON(b *bytes.Buffer, v json.JSON, t *types.T) {
+	s := v.String()
+	if t.Oid() == oid.T_jsonb {
+		putInt32(b, int32(len(s)+1))
+		// Postgres version number, as of writing, `1` is the only valid value.
+		b.WriteByte(1)
+	} else {
+		putInt32(b, int32(len(s)))
+	}
+	b.WriteString(s)
+}
+
+// writeBinaryRange writes the length-prefixed binary format of a range, which
+// is a flags byte followed by the binary encodings of its finite bounds.
+func writeBinaryRange(b *bytes.Buffer, v *tree.DRange, sessionLoc *time.Location) error {
+	lengthIdx := reserveLength(b)
+	if v.Empty {
+		b.WriteByte(RangeFlagEmpty)
+	} else {
+		var flags byte
+		if v.Lower.IsInfinite() {
+			flags |= RangeFlagLowerInfinite
+		} else if v.Lower.Inclusive {
+			flags |= RangeFlagLowerInc
+		}
+		if v.Upper.IsInfinite() {
+			flags |= RangeFlagUpperInfinite
+		} else if v.Upper.Inclusive {
+			flags |= RangeFlagUpperInc
+		}
+		b.WriteByte(flags)
+		elemTyp := v.ResolvedType().RangeContents()
+		for _, bound := range [...]tree.RangeBound{v.Lower, v.Upper} {
+			if !bound.IsInfinite() {
+				if err := WriteBinaryDatum(b, bound.Val, sessionLoc, elemTyp); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	finishLength(b, lengthIdx)
+	return nil
+}
+
+// WriteBinaryDatum writes d to the buffer in the binary format. NULL is
+// encoded as a length of -1. Type t must be specified for types that have
+// various width encodings (floats, ints, chars). It is ignored (and can be
+// nil) for types with a 1:1 datum:type mapping.
+func WriteBinaryDatum(b *bytes.Buffer, d tree.Datum, sessionLoc *time.Location, t *types.T) error {
+	if d == tree.DNull {
+		// NULL is encoded as -1; all other values have a length prefix.
+		putInt32(b, -1)
+		return nil
+	}
+	return WriteBinaryDatumNotNull(b, d, sessionLoc, t)
+}
+
+// WriteBinaryDatumNotNull is like WriteBinaryDatum, but d must not be NULL.
+func WriteBinaryDatumNotNull(
+	b *bytes.Buffer, d tree.Datum, sessionLoc *time.Location, t *types.T,
+) error {
+	switch v := tree.UnwrapDOidWrapper(d).(type) {
+	case *tree.DBitArray:
+		words, lastBitsUsed := v.EncodingParts()
+		if len(words) == 0 {
+			putInt32(b, 4)
+		} else {
+			// Encode the length of the output bytes. It is computed here so we don't
+			// have to keep a buffer.
+			// 4: the int32 of the bitLen.
+			// 8*(len(words)-1): number of 8-byte words except the last one since it's
+			//   partial.
+			// (lastBitsUsed+7)/8: number of bytes that will be written in the last
+			//   partial word. The /8 rounds down, such that the +7 will cause 1-or-more
+			//   bits to use a byte, but 0 will not.
+			putInt32(b, 4+int32(8*(len(words)-1))+int32((lastBitsUsed+7)/8))
+		}
+		bitLen := v.BitLen()
+		putInt32(b, int32(bitLen))
+		var byteBuf [8]byte
+		for i := 0; i < len(words)-1; i++ {
+			w := words[i]
+			binary.BigEndian.PutUint64(byteBuf[:], w)
+			b.Write(byteBuf[:])
+		}
+		if len(words) > 0 {
+			w := words[len(words)-1]
+			for i := uint(0); i < uint(lastBitsUsed); i += 8 {
+				c := byte(w >> (56 - i))
+				b.WriteByte(c)
+			}
+		}
+
+	case *tree.DBool:
+		WriteBinaryBool(b, bool(*v))
+
+	case *tree.DInt:
+		return WriteBinaryInt(b, int64(*v), t)
+
+	case *tree.DFloat:
+		return WriteBinaryFloat(b, float64(*v), t)
+
+	case *tree.DDecimal:
+		WriteBinaryDecimal(b, &v.Decimal)
+
+	case *tree.DBytes:
+		WriteBinaryBytes(b, []byte(*v))
+
+	case *tree.DUuid:
+		WriteBinaryBytes(b, v.GetBytes())
+
+	case *tree.DIPAddr:
+		// We calculate the Postgres binary format for an IPAddr. For the spec see,
+		// https://github.com/postgres/postgres/blob/81c5e46c490e2426db243eada186995da5bb0ba7/src/backend/utils/adt/network.c#L144
+		// The pgBinary encoding is as follows:
+		//  The int32 length of the following bytes.
+		//  The family byte.
+		//  The mask size byte.
+		//  A 0 byte for is_cidr. It's ignored on the postgres frontend.
+		//  The length of our IP bytes.
+		//  The IP bytes.
+		const pgIPAddrBinaryHeaderSize = 4
+		if v.Family == ipaddr.IPv4family {
+			putInt32(b, net.IPv4len+pgIPAddrBinaryHeaderSize)
+			b.WriteByte(PGBinaryIPv4family)
+			b.WriteByte(v.Mask)
+			b.WriteByte(0)
+			b.WriteByte(byte(net.IPv4len))
+			return v.Addr.WriteIPv4Bytes(b)
+		} else if v.Family == ipaddr.IPv6family {
+			putInt32(b, net.IPv6len+pgIPAddrBinaryHeaderSize)
+			b.WriteByte(PGBinaryIPv6family)
+			b.WriteByte(v.Mask)
+			b.WriteByte(0)
+			b.WriteByte(byte(net.IPv6len))
+			return v.Addr.WriteIPv6Bytes(b)
+		}
+		return errors.Errorf("error encoding inet to pgBinary: %v", v.IPAddr)
+
+	case *tree.DEnum:
+		WriteLengthPrefixedString(b, v.LogicalRep)
+
+	case *tree.DString:
+		WriteBinaryString(b, string(*v), t)
+
+	case *tree.DCollatedString:
+		WriteLengthPrefixedString(b, tree.ResolveBlankPaddedChar(v.Contents, t))
+
+	case *tree.DTimestamp:
+		WriteBinaryTimestamp(b, v.Time)
+
+	case *tree.DTimestampTZ:
+		WriteBinaryTimestampTZ(b, v.Time, sessionLoc)
+
+	case *tree.DDate:
+		WriteBinaryDate(b, v.Date)
+
+	case *tree.DTime:
+		putInt32(b, 8)
+		putInt64(b, int64(*v))
+
+	case *tree.DTimeTZ:
+		putInt32(b, 12)
+		putInt64(b, int64(v.TimeOfDay))
+		putInt32(b, v.OffsetSecs)
+
+	case *tree.DInterval:
+		WriteBinaryInterval(b, v.Duration)
+
+	case *tree.DTuple:
+		lengthIdx := reserveLength(b)
+
+		// Put the number of datums.
+		putInt32(b, int32(len(v.D)))
+		tupleTypes := t.TupleContents()
+		for i, elem := range v.D {
+			// Untyped tuples don't know the types of the tuple contents, so fallback
+			// to using the datum's type.
+			elemTyp := elem.ResolvedType()
+			if i < len(tupleTypes) && tupleTypes[i].Family() != types.AnyFamily {
+				elemTyp = tupleTypes[i]
+			}
+			putInt32(b, int32(elemTyp.Oid()))
+			if err := WriteBinaryDatum(b, elem, sessionLoc, elemTyp); err != nil {
+				return err
+			}
+		}
+
+		finishLength(b, lengthIdx)
+
+	case *tree.DVoid:
+		putInt32(b, 0)
+
+	case *tree.DPGLSN:
+		putInt32(b, 8)
+		putInt64(b, int64(v.LSN))
+
+	case *tree.DBox2D:
+		putInt32(b, 32)
+		putInt64(b, int64(math.Float64bits(v.LoX)))
+		putInt64(b, int64(math.Float64bits(v.HiX)))
+		putInt64(b, int64(math.Float64bits(v.LoY)))
+		putInt64(b, int64(math.Float64bits(v.HiY)))
+
+	case *tree.DGeography:
+		putInt32(b, int32(len(v.EWKB())))
+		b.Write(v.EWKB())
+
+	case *tree.DGeometry:
+		putInt32(b, int32(len(v.EWKB())))
+		b.Write(v.EWKB())
+
+	case *tree.DTSQuery:
+		lengthIdx := reserveLength(b)
+		b.Write(tsearch.EncodeTSQueryPGBinary(nil, v.TSQuery))
+		finishLength(b, lengthIdx)
+
+	case *tree.DTSVector:
+		ret, err := tsearch.EncodeTSVectorPGBinary(nil, v.TSVector)
+		if err != nil {
+			return err
+		}
+		WriteBinaryBytes(b, ret)
+
+	case *tree.DPGVector:
+		// 2 bytes for dimensions, 2 bytes for unused, and 4 bytes for each
+		// float4.
+		putInt32(b, int32(4+4*len(v.T)))
+		putInt16(b, int16(len(v.T)))
+		putInt16(b, int16(0)) // vec->unused - "reserved for future use, always zero"
+		for _, f := range v.T {
+			putInt32(b, int32(math.Float32bits(f)))
+		}
+
+	case *tree.DRange:
+		return writeBinaryRange(b, v, sessionLoc)
+
+	case *tree.DMultirange:
+		lengthIdx := reserveLength(b)
+		putInt32(b, int32(len(v.Ranges)))
+		for _, r := range v.Ranges {
+			if err := writeBinaryRange(b, r, sessionLoc); err != nil {
+				return err
+			}
+		}
+		finishLength(b, lengthIdx)
+
+	case *tree.DArray:
+		if v.ParamTyp.Family() == types.ArrayFamily {
+			return unimplemented.NewWithIssueDetail(32552,
+				"binenc", "unsupported binary serialization of multidimensional arrays")
+		}
+
+		lengthIdx := reserveLength(b)
+
+		// Put the number of dimensions. We currently support 1d arrays only.
+		var ndims int32 = 1
+		if v.Len() == 0 {
+			ndims = 0
+		}
+		putInt32(b, ndims)
+		hasNulls := 0
+		if v.HasNulls {
+			hasNulls = 1
+		}
+		putInt32(b, int32(hasNulls))
+		putInt32(b, int32(v.ParamTyp.Oid()))
+		if v.Len() > 0 {
+			putInt32(b, int32(v.Len()))
+			// Lower bound, we only support a lower bound of 1.
+			putInt32(b, 1)
+			for _, elem := range v.Array {
+				if err := WriteBinaryDatum(b, elem, sessionLoc, v.ParamTyp); err != nil {
+					return err
+				}
+			}
+		}
+
+		finishLength(b, lengthIdx)
+
+	case *tree.DJSON:
+		WriteBinaryJSON(b, v.JSON, t)
+
+	case *tree.DOid:
+		putInt32(b, 4)
+		putInt32(b, int32(v.Oid))
+
+	default:
+		return errors.AssertionFailedf("unsupported type %T", d)
+	}
+	return nil
+}
+
+// TimeToPgBinary calculates the Postgres binary format for a timestamp. The
+// timestamp is represented as the number of microseconds between the given
+// time and Jan 1, 2000 (dubbed the PGEpochJDate), stored within an int64.
+func TimeToPgBinary(t time.Time, offset *time.Location) int64 {
+	if offset != nil {
+		t = t.In(offset)
+	} else {
+		t = t.UTC()
+	}
+	return duration.DiffMicros(t, PGEpochJDate)
+}
@@ -493,6 +493,24 @@ func DecodeDatum(
 				return nil, err
 			}
 			return da.NewDString(tree.DString(bs)), nil
+		case types.RangeFamily:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			d, _, err := tree.ParseDRangeFromString(evalCtx, bs, typ)
+			if err != nil {
+				return nil, err
+			}
+			return d, nil
+		case types.MultirangeFamily:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			d, _, err := tree.ParseDMultirangeFromString(evalCtx, bs, typ)
+			if err != nil {
+				return nil, err
+			}
+			return d, nil
 		}
 	case FormatBinary:
 		switch id {
@@ -816,6 +834,16 @@ func DecodeDatum(
 			if typ.Family() == types.TupleFamily {
 				return decodeBinaryTuple(ctx, evalCtx, b, da)
 			}
+			if typ.Family() == types.RangeFamily {
+				d, err := decodeBinaryRange(ctx, evalCtx, typ, b, da)
+				if err != nil {
+					return nil, err
+				}
+				return d, nil
+			}
+			if typ.Family() == types.MultirangeFamily {
+				return decodeBinaryMultirange(ctx, evalCtx, typ, b, da)
+			}
 			if typ.Family() == types.OidFamily {
 				if len(b) < 4 {
 					return nil, pgerror.Newf(pgcode.ProtocolViolation, "oid requires 4 bytes for binary format")
@@ -1033,6 +1061,87 @@ func decodeBinaryArray(
 	return arr, nil
 }
 
+// The flags of the binary format of a range, which is a flags byte followed
+// by the length-prefixed binary encodings of the finite bounds.
+const (
+	RangeFlagEmpty         byte = 0x01
+	RangeFlagLowerInc      byte = 0x02
+	RangeFlagUpperInc      byte = 0x04
+	RangeFlagLowerInfinite byte = 0x08
+	RangeFlagUpperInfinite byte = 0x10
+)
+
+func decodeBinaryRange(
+	ctx context.Context, evalCtx *eval.Context, t *types.T, b []byte, da *tree.DatumAlloc,
+) (*tree.DRange, error) {
+	r := bytes.NewBuffer(b)
+	flags, err := r.ReadByte()
+	if err != nil {
+		return nil, pgerror.New(pgcode.Syntax, "range requires a flags byte for binary format")
+	}
+	if flags&RangeFlagEmpty != 0 {
+		return tree.NewDEmptyRange(t), nil
+	}
+	lower := tree.RangeBound{Inclusive: flags&RangeFlagLowerInc != 0}
+	upper := tree.RangeBound{Inclusive: flags&RangeFlagUpperInc != 0}
+	for _, bound := range []struct {
+		b        *tree.RangeBound
+		infinite bool
+	}{
+		{b: &lower, infinite: flags&RangeFlagLowerInfinite != 0},
+		{b: &upper, infinite: flags&RangeFlagUpperInfinite != 0},
+	} {
+		if bound.infinite {
+			continue
+		}
+		var vlen int32
+		if err := binary.Read(r, binary.BigEndian, &vlen); err != nil {
+			return nil, err
+		}
+		if vlen < 0 || int(vlen) > r.Len() {
+			return nil, pgerror.Newf(pgcode.Syntax, "invalid range bound length %d", vlen)
+		}
+		if bound.b.Val, err = DecodeDatum(
+			ctx, evalCtx, t.RangeContents(), FormatBinary, r.Next(int(vlen)), da,
+		); err != nil {
+			return nil, err
+		}
+	}
+	if r.Len() > 0 {
+		return nil, pgerror.New(pgcode.Syntax, "unexpected bytes after range for binary format")
+	}
+	return tree.NewDRange(t, lower, upper)
+}
+
+func decodeBinaryMultirange(
+	ctx context.Context, evalCtx *eval.Context, t *types.T, b []byte, da *tree.DatumAlloc,
+) (tree.Datum, error) {
+	r := bytes.NewBuffer(b)
+	var n int32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, pgerror.Newf(pgcode.Syntax, "invalid multirange length %d", n)
+	}
+	ranges := make([]*tree.DRange, 0, n)
+	for i := int32(0); i < n; i++ {
+		var vlen int32
+		if err := binary.Read(r, binary.BigEndian, &vlen); err != nil {
+			return nil, err
+		}
+		if vlen < 0 || int(vlen) > r.Len() {
+			return nil, pgerror.Newf(pgcode.Syntax, "invalid range length %d", vlen)
+		}
+		rng, err := decodeBinaryRange(ctx, evalCtx, t.RangeContents(), r.Next(int(vlen)), da)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, rng)
+	}
+	return tree.NewDMultirange(t, ranges), nil
+}
+
 const tupleHeaderSize, oidSize, elementSize = 4, 4, 4
 
 func decodeBinaryTuple(
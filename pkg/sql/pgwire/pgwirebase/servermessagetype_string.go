@@ -22,6 +22,7 @@ func _() {
 	_ = x[ServerMsgErrorResponse-69]
 	_ = x[ServerMsgNoticeResponse-78]
 	_ = x[ServerMsgNoData-110]
+	_ = x[ServerMsgNotificationResponse-65]
 	_ = x[ServerMsgParameterDescription-116]
 	_ = x[ServerMsgParameterStatus-83]
 	_ = x[ServerMsgParseComplete-49]
@@ -60,6 +61,8 @@ func (i ServerMessageType) String() string {
 		return "ServerMsgNoticeResponse"
 	case ServerMsgNoData:
 		return "ServerMsgNoData"
+	case ServerMsgNotificationResponse:
+		return "ServerMsgNotificationResponse"
 	case ServerMsgParameterDescription:
 		return "ServerMsgParameterDescription"
 	case ServerMsgParameterStatus:
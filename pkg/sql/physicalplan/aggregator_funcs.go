@@ -469,3 +469,31 @@ var DistAggregationTable = map[execinfrapb.AggregatorSpec_Func]DistAggregationIn
 		},
 	},
 }
+
+// userDefinedDistAggregationInfo describes a user-defined aggregate with a
+// combine function: the local stage computes partial states, which the final
+// stage combines before applying the final function.
+var userDefinedDistAggregationInfo = DistAggregationInfo{
+	LocalStage: []execinfrapb.AggregatorSpec_Func{execinfrapb.UserDefined},
+	FinalStage: []FinalStageInfo{
+		{
+			Fn:        execinfrapb.UserDefined,
+			LocalIdxs: passThroughLocalIdxs,
+		},
+	},
+}
+
+// GetDistAggregationInfo returns the DistAggregationInfo of the given
+// aggregation, or false if it is not optimized with a local stage.
+func GetDistAggregationInfo(
+	agg *execinfrapb.AggregatorSpec_Aggregation,
+) (DistAggregationInfo, bool) {
+	if agg.Func == execinfrapb.UserDefined {
+		if agg.UserDefined == nil || agg.UserDefined.Combine.Empty() {
+			return DistAggregationInfo{}, false
+		}
+		return userDefinedDistAggregationInfo, true
+	}
+	info, ok := DistAggregationTable[agg.Func]
+	return info, ok
+}
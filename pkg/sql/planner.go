@@ -114,6 +114,15 @@ type extendedEvalContext struct {
 
 	// validateDbZoneConfig should the DB zone config on commit.
 	validateDbZoneConfig *bool
+
+	// deferredConstraints refers to the deferred constraint state in
+	// extraTxnState. It is nil for internal executors that run under an outer
+	// transaction.
+	deferredConstraints *deferredConstraintState
+
+	// notifications refers to the LISTEN and NOTIFY state in extraTxnState.
+	// It is nil for internal executors that run under an outer transaction.
+	notifications *txnNotificationState
 }
 
 // copyFromExecCfg copies relevant fields from an ExecutorConfig.
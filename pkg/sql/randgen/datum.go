@@ -331,11 +331,43 @@ func RandDatumWithNullChance(
 		return tree.NewDTSQuery(tsearch.RandomTSQuery(rng))
 	case types.PGVectorFamily:
 		return tree.NewDPGVector(vector.Random(rng))
+	case types.RangeFamily:
+		return randRange(rng, typ)
+	case types.MultirangeFamily:
+		ranges := make([]*tree.DRange, rng.Intn(4))
+		for i := range ranges {
+			ranges[i] = randRange(rng, typ.RangeContents())
+		}
+		return tree.NewDMultirange(typ, ranges)
 	default:
 		panic(errors.AssertionFailedf("invalid type %v", typ.DebugString()))
 	}
 }
 
+// randRange generates a random DRange of the given range type. Each bound has
+// a 1 in 10 chance of being infinite.
+func randRange(rng *rand.Rand, typ *types.T) *tree.DRange {
+	if rng.Intn(10) == 0 {
+		return tree.NewDEmptyRange(typ)
+	}
+	var bounds [2]tree.RangeBound
+	for i := range bounds {
+		bounds[i].Inclusive = rng.Intn(2) == 0
+		if rng.Intn(10) != 0 {
+			bounds[i].Val = RandDatum(rng, typ.RangeContents(), false /* nullOk */)
+		}
+	}
+	r, err := tree.NewDRange(typ, bounds[0], bounds[1])
+	if err != nil {
+		// The lower bound was greater than the upper bound.
+		r, err = tree.NewDRange(typ, bounds[1], bounds[0])
+		if err != nil {
+			return tree.NewDEmptyRange(typ)
+		}
+	}
+	return r
+}
+
 // RandArray generates a random DArray where the contents have nullChance
 // of being null.
 func RandArray(rng *rand.Rand, typ *types.T, nullChance int) tree.Datum {
@@ -615,7 +615,10 @@ func randIndexTableDefFromCols(
 
 		// The non-terminal index columns must be indexable.
 		forwardIndexable := colinfo.ColumnTypeIsIndexable(semType)
-		invertedIndexable := colinfo.ColumnTypeIsInvertedIndexable(semType)
+		// Vector indexes are not generated, since they require all the vectors
+		// in a column to have the same number of dimensions.
+		invertedIndexable := colinfo.ColumnTypeIsInvertedIndexable(semType) &&
+			semType.Family() != types.PGVectorFamily
 		if !isLastCol && !forwardIndexable {
 			return tree.IndexTableDef{}, false
 		}
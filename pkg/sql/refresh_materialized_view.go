@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/errors"
 )
 
 type refreshMaterializedViewNode struct {
@@ -60,6 +61,21 @@ func (p *planner) RefreshMaterializedView(
 		)
 	}
 
+	if n.Incremental {
+		if desc.IsRefreshViewRequired() || desc.GetMaterializedViewRefreshTime().IsEmpty() {
+			return nil, errors.WithHint(
+				pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+					"materialized view %q has no data to refresh incrementally", desc.Name),
+				"Use REFRESH MATERIALIZED VIEW without the incremental option to populate the view.",
+			)
+		}
+		if _, err := makeIncrementalViewRefresh(
+			ctx, p.Descriptors().ByNameWithLeased(p.Txn()).Get(), desc,
+		); err != nil {
+			return nil, err
+		}
+	}
+
 	return &refreshMaterializedViewNode{n: n, desc: desc}, nil
 }
 
@@ -76,6 +92,9 @@ func (n *refreshMaterializedViewNode) startExec(params runParams) error {
 	}
 
 	telemetry.Inc(sqltelemetry.SchemaRefreshMaterializedView)
+	if n.n.Incremental {
+		telemetry.Inc(sqltelemetry.SchemaRefreshMaterializedViewIncremental)
+	}
 
 	// Inform the user that CONCURRENTLY is not needed.
 	if n.n.Concurrently {
@@ -92,15 +111,18 @@ func (n *refreshMaterializedViewNode) startExec(params runParams) error {
 		newIndexes[i] = idx.IndexDescDeepCopy()
 	}
 
-	// Reset and allocate new IDs for the new indexes.
-	getID := func() descpb.IndexID {
-		res := n.desc.NextIndexID
-		n.desc.NextIndexID++
-		return res
-	}
-	newPrimaryIndex.ID = getID()
-	for i := range newIndexes {
-		newIndexes[i].ID = getID()
+	// Reset and allocate new IDs for the new indexes. An incremental refresh
+	// writes to the existing indexes, so it keeps their IDs.
+	if !n.n.Incremental {
+		getID := func() descpb.IndexID {
+			res := n.desc.NextIndexID
+			n.desc.NextIndexID++
+			return res
+		}
+		newPrimaryIndex.ID = getID()
+		for i := range newIndexes {
+			newIndexes[i].ID = getID()
+		}
 	}
 
 	// Set RefreshViewRequired to false. This will allow SELECT operations on the materialized
@@ -112,6 +134,7 @@ func (n *refreshMaterializedViewNode) startExec(params runParams) error {
 		NewIndexes:      newIndexes,
 		AsOf:            params.p.Txn().ReadTimestamp(),
 		ShouldBackfill:  n.n.RefreshDataOption != tree.RefreshDataClear,
+		Incremental:     n.n.Incremental,
 	})
 
 	return params.p.writeSchemaChange(
@@ -0,0 +1,1107 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobsprotectedts"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catenumpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/keyside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+// incrementalViewRefresh is the analyzed query of a materialized view that
+// can be refreshed incrementally. The supported queries are a single SELECT
+// clause over base tables combined with inner joins, with arbitrary filters
+// and projections, optionally grouped with SUM and COUNT aggregates.
+//
+// An incremental refresh from time t0 to t1 first scans the MVCC revisions
+// of the base table rows in (t0, t1], and divides the primary keys of the
+// changed rows into batches of consecutive keys. Each batch is represented by
+// the span of the primary index between its first and last key. Only the rows
+// of the view query that are derived from at least one base row in those spans
+// (the "touched" rows) can differ between t0 and t1, and the batches are
+// applied to the view one at a time, restricting the query to the rows touched
+// by the batch that were not touched by a previous batch:
+//
+//   - Without aggregation, the view is a multiset of rows, and the refresh
+//     deletes the touched rows of the query as of t0 from the view and inserts
+//     the touched rows as of t1. Rows that are present at both times cancel
+//     out and are not rewritten.
+//   - With aggregation, the refresh determines the groups of the touched rows
+//     as of t0 and t1, deletes the rows of those groups from the view, and
+//     inserts the rows of the query as of t1 restricted to those groups.
+//
+// Rows derived from unchanged base rows in the spans are present at both times
+// and cancel out or are rewritten with the same values. All of the changes are
+// written in a single transaction, which also advances the
+// MaterializedViewRefreshTime of the view to t1. The rows of the view query
+// that are buffered for a batch are accounted for in a memory monitor.
+type incrementalViewRefresh struct {
+	view catalog.TableDescriptor
+	// sel is the SELECT clause of the view query.
+	sel *tree.SelectClause
+	// tables are the references to base tables in the FROM clause of sel.
+	tables []incrementalViewTable
+	// aggregate is set if the view query aggregates rows. groupCols are then
+	// the ordinals of the visible view columns that hold the grouping
+	// expressions of sel, in the same order as sel.GroupBy.
+	aggregate bool
+	groupCols []int
+}
+
+// incrementalViewTable is a reference to a base table in the query of an
+// incrementally refreshed view.
+type incrementalViewTable struct {
+	desc catalog.TableDescriptor
+	// pkCols are the primary key columns of the table, qualified the same way
+	// as the table reference in the view query.
+	pkCols tree.Exprs
+}
+
+// incrementalRefreshAggregates are the aggregate functions that can be used in
+// an incrementally refreshed view.
+var incrementalRefreshAggregates = map[string]struct{}{
+	"count":      {},
+	"count_rows": {},
+	"sum":        {},
+	"sum_int":    {},
+}
+
+// errIncrementalRefreshUnsupported returns the error for a materialized view
+// whose query cannot be refreshed incrementally.
+func errIncrementalRefreshUnsupported(
+	view catalog.TableDescriptor, format string, args ...interface{},
+) error {
+	return errors.WithHint(
+		pgerror.Newf(pgcode.FeatureNotSupported,
+			"materialized view %q cannot be refreshed incrementally: %s",
+			view.GetName(), fmt.Sprintf(format, args...)),
+		"Incremental refreshes support filters, projections, inner joins of tables, "+
+			"and grouping with SUM and COUNT aggregates.",
+	)
+}
+
+// makeIncrementalViewRefresh analyzes the query of the given materialized
+// view, returning an error if it cannot be refreshed incrementally. The base
+// tables of the view are resolved with g.
+func makeIncrementalViewRefresh(
+	ctx context.Context, g descs.ByNameGetter, view catalog.TableDescriptor,
+) (*incrementalViewRefresh, error) {
+	stmt, err := parser.ParseOne(view.GetViewQuery())
+	if err != nil {
+		return nil, err
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		return nil, errIncrementalRefreshUnsupported(view, "the query is not a SELECT statement")
+	}
+	for {
+		if sel.With != nil || sel.OrderBy != nil || sel.Limit != nil || len(sel.Locking) > 0 {
+			return nil, errIncrementalRefreshUnsupported(view,
+				"WITH, ORDER BY, LIMIT and locking clauses are not supported")
+		}
+		paren, ok := sel.Select.(*tree.ParenSelect)
+		if !ok {
+			break
+		}
+		sel = paren.Select
+	}
+	clause, ok := sel.Select.(*tree.SelectClause)
+	if !ok {
+		return nil, errIncrementalRefreshUnsupported(view, "set operations and VALUES are not supported")
+	}
+	switch {
+	case clause.Distinct || clause.DistinctOn != nil:
+		return nil, errIncrementalRefreshUnsupported(view, "DISTINCT is not supported")
+	case clause.Having != nil:
+		return nil, errIncrementalRefreshUnsupported(view, "HAVING is not supported")
+	case len(clause.Window) > 0:
+		return nil, errIncrementalRefreshUnsupported(view, "window functions are not supported")
+	case clause.From.AsOf.Expr != nil:
+		return nil, errIncrementalRefreshUnsupported(view, "AS OF SYSTEM TIME is not supported")
+	}
+
+	r := &incrementalViewRefresh{view: view, sel: clause}
+	for _, t := range clause.From.Tables {
+		if err := r.addTables(ctx, g, t); err != nil {
+			return nil, err
+		}
+	}
+
+	checker := incrementalViewExprChecker{ctx: ctx}
+	for i := range clause.Exprs {
+		tree.WalkExprConst(&checker, clause.Exprs[i].Expr)
+	}
+	if clause.Where != nil {
+		checker.inWhere = true
+		tree.WalkExprConst(&checker, clause.Where.Expr)
+	}
+	if checker.reason != "" {
+		return nil, errIncrementalRefreshUnsupported(view, "%s", checker.reason)
+	}
+	r.aggregate = checker.aggregate || len(clause.GroupBy) > 0
+	for _, expr := range clause.GroupBy {
+		ord, ok := findGroupingColumn(clause.Exprs, expr)
+		if !ok {
+			return nil, errIncrementalRefreshUnsupported(view,
+				"the grouping expression %s is not a column of the view", tree.AsString(expr))
+		}
+		r.groupCols = append(r.groupCols, ord)
+	}
+	return r, nil
+}
+
+// addTables adds the base tables referenced by the given table expression of
+// the FROM clause.
+func (r *incrementalViewRefresh) addTables(
+	ctx context.Context, g descs.ByNameGetter, expr tree.TableExpr,
+) error {
+	switch t := expr.(type) {
+	case *tree.AliasedTableExpr:
+		tn, ok := t.Expr.(*tree.TableName)
+		if !ok || t.Lateral || t.Ordinality {
+			return errIncrementalRefreshUnsupported(r.view,
+				"subqueries, table functions and WITH ORDINALITY are not supported")
+		}
+		if len(t.As.Cols) > 0 {
+			return errIncrementalRefreshUnsupported(r.view, "column aliases are not supported")
+		}
+		_, desc, err := descs.PrefixAndTable(ctx, g, tn)
+		if err != nil {
+			return err
+		}
+		if !desc.IsTable() || desc.IsVirtualTable() {
+			return errIncrementalRefreshUnsupported(r.view, "%q is not a table", desc.GetName())
+		}
+		// Qualify the primary key columns the same way that the rest of the query
+		// refers to the table.
+		var qualifier []string
+		if t.As.Alias != "" {
+			qualifier = []string{string(t.As.Alias)}
+		} else {
+			if tn.ExplicitCatalog {
+				qualifier = append(qualifier, tn.Catalog())
+			}
+			if tn.ExplicitSchema {
+				qualifier = append(qualifier, tn.Schema())
+			}
+			qualifier = append(qualifier, tn.Object())
+		}
+		pk := desc.GetPrimaryIndex()
+		pkCols := make(tree.Exprs, pk.NumKeyColumns())
+		for i := range pkCols {
+			parts := append(append([]string(nil), qualifier...), pk.GetKeyColumnName(i))
+			pkCols[i] = tree.NewUnresolvedName(parts...)
+		}
+		r.tables = append(r.tables, incrementalViewTable{desc: desc, pkCols: pkCols})
+		return nil
+
+	case *tree.JoinTableExpr:
+		if t.JoinType != "" && t.JoinType != tree.AstInner && t.JoinType != tree.AstCross {
+			return errIncrementalRefreshUnsupported(r.view, "%s joins are not supported", t.JoinType)
+		}
+		if err := r.addTables(ctx, g, t.Left); err != nil {
+			return err
+		}
+		return r.addTables(ctx, g, t.Right)
+
+	case *tree.ParenTableExpr:
+		return r.addTables(ctx, g, t.Expr)
+
+	default:
+		return errIncrementalRefreshUnsupported(r.view,
+			"the FROM clause expression %s is not supported", tree.AsString(expr))
+	}
+}
+
+// findGroupingColumn returns the ordinal of the SELECT expression that is the
+// given GROUP BY expression.
+func findGroupingColumn(exprs tree.SelectExprs, g tree.Expr) (int, bool) {
+	if n, ok := g.(*tree.NumVal); ok {
+		// GROUP BY refers to a column by its position.
+		if ord, err := n.AsInt64(); err == nil && ord >= 1 && int(ord) <= len(exprs) {
+			return int(ord) - 1, true
+		}
+		return 0, false
+	}
+	str := tree.AsString(g)
+	for i := range exprs {
+		if tree.AsString(exprs[i].Expr) == str {
+			return i, true
+		}
+	}
+	// GROUP BY may also refer to an output column by its alias.
+	if n, ok := g.(*tree.UnresolvedName); ok && n.NumParts == 1 {
+		for i := range exprs {
+			if exprs[i].As == tree.Name(n.Parts[0]) {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// incrementalViewExprChecker is a tree.Visitor that finds the expressions in a
+// view query that prevent an incremental refresh.
+type incrementalViewExprChecker struct {
+	ctx     context.Context
+	inWhere bool
+	// reason is set to the reason why the query cannot be refreshed
+	// incrementally once such an expression is found.
+	reason string
+	// aggregate is set if an aggregate function is found.
+	aggregate bool
+}
+
+var _ tree.Visitor = &incrementalViewExprChecker{}
+
+// VisitPre is part of the tree.Visitor interface.
+func (v *incrementalViewExprChecker) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if v.reason != "" {
+		return false, expr
+	}
+	switch t := expr.(type) {
+	case *tree.Subquery:
+		v.reason = "subqueries are not supported"
+		return false, expr
+
+	case *tree.FuncExpr:
+		if t.WindowDef != nil {
+			v.reason = "window functions are not supported"
+			return false, expr
+		}
+		// Resolve a copy of the reference, since the visitor must not modify
+		// the expression.
+		ref := t.Func
+		fd, err := ref.Resolve(v.ctx, tree.EmptySearchPath, nil /* resolver */)
+		if err != nil {
+			v.reason = fmt.Sprintf("function %s is not supported", tree.AsString(&t.Func))
+			return false, expr
+		}
+		for i := range fd.Overloads {
+			if fd.Overloads[i].Volatility == volatility.Volatile {
+				v.reason = fmt.Sprintf("volatile function %s is not supported", fd.Name)
+				return false, expr
+			}
+		}
+		class, err := fd.GetClass()
+		if err != nil {
+			v.reason = err.Error()
+			return false, expr
+		}
+		switch class {
+		case tree.AggregateClass:
+			if _, ok := incrementalRefreshAggregates[fd.Name]; !ok || v.inWhere {
+				v.reason = fmt.Sprintf("aggregate function %s is not supported", fd.Name)
+				return false, expr
+			}
+			v.aggregate = true
+		case tree.WindowClass:
+			v.reason = "window functions are not supported"
+		case tree.GeneratorClass:
+			v.reason = "set-returning functions are not supported"
+		}
+	}
+	return true, expr
+}
+
+// VisitPost is part of the tree.Visitor interface.
+func (v *incrementalViewExprChecker) VisitPost(expr tree.Expr) tree.Expr { return expr }
+
+// refreshMaterializedViewIncrementally performs an incremental refresh of a
+// materialized view. See incrementalViewRefresh for the algorithm.
+func (sc *SchemaChanger) refreshMaterializedViewIncrementally(
+	ctx context.Context, table catalog.TableDescriptor, refresh catalog.MaterializedViewRefresh,
+) (err error) {
+	from, to := table.GetMaterializedViewRefreshTime(), refresh.AsOf()
+	if from == to {
+		// The refresh was already applied before the job was interrupted.
+		return nil
+	}
+	if from.IsEmpty() || to.LessEq(from) {
+		return errors.AssertionFailedf(
+			"cannot refresh materialized view %q incrementally from %s to %s", table.GetName(), from, to)
+	}
+	log.Infof(ctx, "starting incremental refresh of materialized view %q from %s to %s",
+		table.GetName(), from, to)
+
+	var r *incrementalViewRefresh
+	if err := sc.txn(ctx, func(ctx context.Context, txn descs.Txn) (err error) {
+		r, err = makeIncrementalViewRefresh(ctx, txn.Descriptors().ByNameWithLeased(txn.KV()).Get(), table)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	// The base tables are read as of the previous refresh, so protect their
+	// history from garbage collection for the duration of the refresh.
+	var ptsCleaners []jobsprotectedts.Cleaner
+	defer func() {
+		for _, cleaner := range ptsCleaners {
+			err = errors.CombineErrors(err, cleaner(ctx))
+		}
+	}()
+	for _, t := range r.tables {
+		ptsCleaners = append(ptsCleaners,
+			sc.execCfg.ProtectedTimestampManager.TryToProtectBeforeGC(ctx, sc.job, t.desc, from))
+	}
+
+	monitor := mon.NewMonitorInheritWithLimit(
+		"refresh-materialized-view-incremental", 0 /* limit */, sc.execCfg.RootMemoryMonitor,
+		false, /* longLiving */
+	)
+	monitor.StartNoReserved(ctx, sc.execCfg.RootMemoryMonitor)
+	defer monitor.Stop(ctx)
+	spansAcc, rowsAcc := monitor.MakeBoundAccount(), monitor.MakeBoundAccount()
+	defer spansAcc.Close(ctx)
+	defer rowsAcc.Close(ctx)
+
+	spans, err := r.changedSpans(ctx, sc.execCfg, &spansAcc, from, to)
+	if err != nil {
+		return withIncrementalRefreshGCHint(err)
+	}
+
+	return sc.txn(ctx, func(ctx context.Context, txn descs.Txn) error {
+		view, err := txn.Descriptors().ByIDWithoutLeased(txn.KV()).Get().Table(ctx, table.GetID())
+		if err != nil {
+			return err
+		}
+		if view.GetMaterializedViewRefreshTime() == to {
+			return nil
+		}
+		if view.GetMaterializedViewRefreshTime() != from {
+			return errors.AssertionFailedf(
+				"materialized view %q was refreshed concurrently", view.GetName())
+		}
+		w, err := makeIncrementalViewWriter(ctx, sc.execCfg, txn, view)
+		if err != nil {
+			return err
+		}
+		if err := r.applyChanges(ctx, sc.execCfg, &rowsAcc, w, spans, from, to); err != nil {
+			return withIncrementalRefreshGCHint(err)
+		}
+		mut, err := txn.Descriptors().MutableByID(txn.KV()).Table(ctx, view.GetID())
+		if err != nil {
+			return err
+		}
+		mut.MaterializedViewRefreshTime = to
+		return txn.Descriptors().WriteDesc(ctx, true /* kvTrace */, mut, txn.KV())
+	})
+}
+
+// withIncrementalRefreshGCHint adds a hint to the error returned when the
+// history of the base tables since the last refresh is no longer available.
+func withIncrementalRefreshGCHint(err error) error {
+	if errors.HasType(err, (*kvpb.BatchTimestampBeforeGCError)(nil)) {
+		err = errors.WithHint(err, "The history of the base tables since the last refresh "+
+			"has been garbage collected. Use REFRESH MATERIALIZED VIEW without the "+
+			"incremental option to refresh the view.")
+	}
+	return err
+}
+
+// incrementalRefreshBatchSize is the maximum number of changed rows of a base
+// table in a batch of an incremental refresh.
+const incrementalRefreshBatchSize = 1000
+
+// changedKeySpan is the span of the primary index of a base table between the
+// first and the last primary key of a batch of changed rows, in index order.
+type changedKeySpan struct {
+	first, last tree.Datums
+}
+
+// changedSpans returns, for each base table of the view, the spans of the
+// primary keys of the rows with MVCC revisions in (from, to], each with up to
+// incrementalRefreshBatchSize changed rows. The spans are accounted for in
+// acc.
+func (r *incrementalViewRefresh) changedSpans(
+	ctx context.Context, execCfg *ExecutorConfig, acc *mon.BoundAccount, from, to hlc.Timestamp,
+) ([][]changedKeySpan, error) {
+	spans := make([][]changedKeySpan, len(r.tables))
+	for i, t := range r.tables {
+		// The revisions of all stored columns are decoded so that the revisions
+		// of every column family produce a row.
+		var cols []catalog.Column
+		for _, col := range t.desc.PublicColumns() {
+			if !col.IsVirtual() {
+				cols = append(cols, col)
+			}
+		}
+		// The primary key values are collected in the order of the key columns,
+		// which is also the order of t.pkCols.
+		pk := t.desc.GetPrimaryIndex()
+		pkOrds := make([]int, pk.NumKeyColumns())
+		for j := range pkOrds {
+			for k, col := range cols {
+				if col.GetID() == pk.GetKeyColumnID(j) {
+					pkOrds[j] = k
+				}
+			}
+		}
+		it, err := newTableRevisionsIterator(
+			ctx, execCfg.DB, execCfg.Codec, t.desc, cols, from.Next(), to,
+		)
+		if err != nil {
+			return nil, err
+		}
+		err = func() error {
+			defer func() { _ = it.Close() }()
+			var span changedKeySpan
+			var prevKey string
+			var n int
+			addSpan := func() error {
+				if err := acc.Grow(ctx, datumsSize(span.first)+datumsSize(span.last)); err != nil {
+					return err
+				}
+				spans[i] = append(spans[i], span)
+				span, n = changedKeySpan{}, 0
+				return nil
+			}
+			for {
+				ok, err := it.nextRevision(ctx)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					break
+				}
+				vals := make(tree.Datums, len(pkOrds))
+				for j, ord := range pkOrds {
+					vals[j] = it.datums[ord]
+				}
+				// The revisions are produced in the order of the primary index, so
+				// the revisions of a row are adjacent.
+				key := rowKey(vals)
+				if key == prevKey {
+					continue
+				}
+				prevKey = key
+				if n == 0 {
+					span.first = vals
+				}
+				span.last = vals
+				if n++; n == incrementalRefreshBatchSize {
+					if err := addSpan(); err != nil {
+						return err
+					}
+				}
+			}
+			if n > 0 {
+				return addSpan()
+			}
+			return nil
+		}()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return spans, nil
+}
+
+// applyChanges applies the changes of the base tables in the given spans to
+// the view with w. The rows of the view query are read as of the given
+// timestamps one batch at a time, and accounted for in acc.
+func (r *incrementalViewRefresh) applyChanges(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	acc *mon.BoundAccount,
+	w *incrementalViewWriter,
+	spans [][]changedKeySpan,
+	from, to hlc.Timestamp,
+) error {
+	if r.aggregate && len(r.groupCols) == 0 {
+		// The single row of the view is replaced if any of the base tables
+		// changed.
+		for i := range spans {
+			if len(spans[i]) == 0 {
+				continue
+			}
+			newRows, err := r.query(ctx, execCfg, acc, to, r.viewQuery(nil /* filter */))
+			if err != nil {
+				return err
+			}
+			return w.replaceGroups(ctx, r.groupCols, nil /* groups */, newRows, true /* all */)
+		}
+		return nil
+	}
+
+	// Each batch is restricted to the rows that are not derived from the base
+	// rows in the spans of the previous batches, so that no row of the view
+	// is removed or added twice.
+	var prev tree.Expr
+	for i := range r.tables {
+		for _, span := range spans[i] {
+			touched := r.tables[i].spanFilter(span)
+			filter := touched
+			if prev == nil {
+				prev = touched
+			} else {
+				filter = &tree.AndExpr{
+					Left:  &tree.ParenExpr{Expr: touched},
+					Right: &tree.NotExpr{Expr: &tree.ParenExpr{Expr: prev}},
+				}
+				prev = &tree.OrExpr{Left: prev, Right: &tree.ParenExpr{Expr: touched}}
+			}
+			if err := r.applyBatch(ctx, execCfg, acc, w, filter, from, to); err != nil {
+				return err
+			}
+			acc.Clear(ctx)
+		}
+	}
+	return w.flush(ctx)
+}
+
+// applyBatch applies the changes to the rows of the view query that satisfy
+// the given filter to the view with w.
+func (r *incrementalViewRefresh) applyBatch(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	acc *mon.BoundAccount,
+	w *incrementalViewWriter,
+	touched tree.Expr,
+	from, to hlc.Timestamp,
+) error {
+	if !r.aggregate {
+		oldRows, err := r.query(ctx, execCfg, acc, from, r.viewQuery(touched))
+		if err != nil {
+			return err
+		}
+		newRows, err := r.query(ctx, execCfg, acc, to, r.viewQuery(touched))
+		if err != nil {
+			return err
+		}
+		return w.applyDelta(ctx, oldRows, newRows)
+	}
+	// Find the groups that the changed rows belonged to before or after the
+	// changes, and replace the rows of those groups.
+	var groups []tree.Datums
+	for _, ts := range []hlc.Timestamp{from, to} {
+		rows, err := r.query(ctx, execCfg, acc, ts, r.groupsQuery(touched))
+		if err != nil {
+			return err
+		}
+		groups = append(groups, rows...)
+	}
+	groups = distinctRows(groups)
+	if len(groups) == 0 {
+		return nil
+	}
+	groupFilter := makeTupleFilter(r.groupExprs(), groups)
+	newRows, err := r.query(ctx, execCfg, acc, to, r.viewQuery(groupFilter))
+	if err != nil {
+		return err
+	}
+	return w.replaceGroups(ctx, r.groupCols, groups, newRows, false /* all */)
+}
+
+// spanFilter returns a filter that is true if the primary key of the table
+// is within the given span, in the order of the primary index.
+func (t *incrementalViewTable) spanFilter(span changedKeySpan) tree.Expr {
+	return &tree.AndExpr{
+		Left:  &tree.ParenExpr{Expr: t.keyBoundFilter(span.first, true /* lower */)},
+		Right: &tree.ParenExpr{Expr: t.keyBoundFilter(span.last, false /* lower */)},
+	}
+}
+
+// keyBoundFilter returns a filter that is true if the primary key of the
+// table is greater than or equal to the given key if lower is set, or less
+// than or equal to it otherwise, in the order of the primary index.
+func (t *incrementalViewTable) keyBoundFilter(key tree.Datums, lower bool) tree.Expr {
+	pk := t.desc.GetPrimaryIndex()
+	allAscending := true
+	for i := range t.pkCols {
+		allAscending = allAscending && pk.GetKeyColumnDirection(i) == catenumpb.IndexColumn_ASC
+	}
+	bound := treecmp.LE
+	if lower {
+		bound = treecmp.GE
+	}
+	if allAscending {
+		// The index order is the order of tuples of the key columns.
+		tuple := &tree.Tuple{Exprs: make(tree.Exprs, len(key))}
+		for i, d := range key {
+			tuple.Exprs[i] = d
+		}
+		return &tree.ComparisonExpr{
+			Operator: treecmp.MakeComparisonOperator(bound),
+			Left:     &tree.Tuple{Exprs: t.pkCols},
+			Right:    tuple,
+		}
+	}
+	// Otherwise, compare the columns one at a time, from the last one:
+	//
+	//   (c1, c2) >= (k1, k2)  <=>  c1 > k1 OR (c1 = k1 AND c2 >= k2)
+	//
+	// with the comparisons of descending columns reversed.
+	var filter tree.Expr
+	for i := len(t.pkCols) - 1; i >= 0; i-- {
+		greater := lower == (pk.GetKeyColumnDirection(i) == catenumpb.IndexColumn_ASC)
+		op := treecmp.LT
+		switch {
+		case filter == nil && greater:
+			op = treecmp.GE
+		case filter == nil:
+			op = treecmp.LE
+		case greater:
+			op = treecmp.GT
+		}
+		cmp := &tree.ComparisonExpr{
+			Operator: treecmp.MakeComparisonOperator(op),
+			Left:     t.pkCols[i],
+			Right:    key[i],
+		}
+		if filter == nil {
+			filter = cmp
+			continue
+		}
+		filter = &tree.OrExpr{
+			Left: cmp,
+			Right: &tree.AndExpr{
+				Left: &tree.ComparisonExpr{
+					Operator: treecmp.MakeComparisonOperator(treecmp.EQ),
+					Left:     t.pkCols[i],
+					Right:    key[i],
+				},
+				Right: &tree.ParenExpr{Expr: filter},
+			},
+		}
+	}
+	return filter
+}
+
+// viewQuery returns the view query with the given filter added to its WHERE
+// clause.
+func (r *incrementalViewRefresh) viewQuery(filter tree.Expr) string {
+	sel := *r.sel
+	sel.Where = r.where(filter)
+	return tree.AsStringWithFlags(&sel, tree.FmtParsable)
+}
+
+// groupsQuery returns a query for the distinct values of the grouping
+// expressions of the view query with the given filter added to its WHERE
+// clause.
+func (r *incrementalViewRefresh) groupsQuery(filter tree.Expr) string {
+	sel := *r.sel
+	sel.Where = r.where(filter)
+	sel.Distinct = true
+	sel.Exprs = make(tree.SelectExprs, len(r.groupCols))
+	for i, ord := range r.groupCols {
+		sel.Exprs[i] = r.sel.Exprs[ord]
+	}
+	sel.GroupBy = nil
+	return tree.AsStringWithFlags(&sel, tree.FmtParsable)
+}
+
+// groupExprs returns the grouping expressions of the view query.
+func (r *incrementalViewRefresh) groupExprs() tree.Exprs {
+	exprs := make(tree.Exprs, len(r.groupCols))
+	for i, ord := range r.groupCols {
+		exprs[i] = r.sel.Exprs[ord].Expr
+	}
+	return exprs
+}
+
+// where returns the WHERE clause of the view query with the given filter
+// added to it.
+func (r *incrementalViewRefresh) where(filter tree.Expr) *tree.Where {
+	switch {
+	case filter == nil:
+		return r.sel.Where
+	case r.sel.Where == nil:
+		return tree.NewWhere(tree.AstWhere, filter)
+	}
+	return tree.NewWhere(tree.AstWhere, &tree.AndExpr{
+		Left:  &tree.ParenExpr{Expr: r.sel.Where.Expr},
+		Right: &tree.ParenExpr{Expr: filter},
+	})
+}
+
+// query runs the given query as of the given timestamp, accounting for the
+// returned rows in acc.
+func (r *incrementalViewRefresh) query(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	acc *mon.BoundAccount,
+	ts hlc.Timestamp,
+	query string,
+) (rows []tree.Datums, err error) {
+	var accounted int64
+	err = execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) (err error) {
+		// Release the rows of a previous attempt of the transaction.
+		acc.Shrink(ctx, accounted)
+		rows, accounted = nil, 0
+		if err := txn.KV().SetFixedTimestamp(ctx, ts); err != nil {
+			return err
+		}
+		it, err := txn.QueryIteratorEx(
+			ctx, "refresh-materialized-view-incremental", txn.KV(),
+			sessiondata.NodeUserSessionDataOverride, query,
+		)
+		if err != nil {
+			return err
+		}
+		defer func() { err = errors.CombineErrors(err, it.Close()) }()
+		for {
+			ok, err := it.Next(ctx)
+			if err != nil || !ok {
+				return err
+			}
+			row := it.Cur()
+			size := datumsSize(row)
+			if err := acc.Grow(ctx, size); err != nil {
+				return err
+			}
+			accounted += size
+			rows = append(rows, row)
+		}
+	})
+	return rows, err
+}
+
+// datumsSize returns the estimated size of the given datums in memory.
+func datumsSize(datums tree.Datums) int64 {
+	size := int64(len(datums)) * int64(unsafe.Sizeof(tree.Datum(nil)))
+	for _, d := range datums {
+		size += int64(d.Size())
+	}
+	return size
+}
+
+// incrementalViewWriter writes the changes of an incremental refresh to the
+// indexes of a materialized view.
+type incrementalViewWriter struct {
+	execCfg *ExecutorConfig
+	txn     descs.Txn
+	view    catalog.TableDescriptor
+	// cols are the public columns of the view, including the hidden rowid
+	// column, and visible are the ordinals of the visible columns in cols.
+	cols    []catalog.Column
+	visible []int
+	ri      row.Inserter
+	rd      row.Deleter
+	b       *kv.Batch
+	n       int
+}
+
+// incrementalViewWriterBatchSize is the number of rows written to a view in a
+// single batch.
+const incrementalViewWriterBatchSize = 1000
+
+func makeIncrementalViewWriter(
+	ctx context.Context, execCfg *ExecutorConfig, txn descs.Txn, view catalog.TableDescriptor,
+) (*incrementalViewWriter, error) {
+	w := &incrementalViewWriter{
+		execCfg: execCfg,
+		txn:     txn,
+		view:    view,
+		cols:    view.PublicColumns(),
+		b:       txn.KV().NewBatch(),
+	}
+	for i, col := range w.cols {
+		if !col.IsHidden() {
+			w.visible = append(w.visible, i)
+		}
+	}
+	const internal = true
+	var err error
+	w.ri, err = row.MakeInserter(
+		ctx, txn.KV(), execCfg.Codec, view, w.cols, &tree.DatumAlloc{},
+		&execCfg.Settings.SV, internal, execCfg.GetRowMetrics(internal),
+	)
+	if err != nil {
+		return nil, err
+	}
+	w.rd = row.MakeDeleter(
+		execCfg.Codec, view, w.cols, &execCfg.Settings.SV, internal, execCfg.GetRowMetrics(internal),
+	)
+	return w, nil
+}
+
+// applyDelta removes the old rows from the view and adds the new rows. The
+// rows contain the values of the visible columns of the view.
+func (w *incrementalViewWriter) applyDelta(ctx context.Context, oldRows, newRows []tree.Datums) error {
+	// Rows that are present both before and after the changes are left alone.
+	counts := make(map[string]int)
+	var deleted []tree.Datums
+	for _, row := range oldRows {
+		key := rowKey(row)
+		if counts[key] == 0 {
+			deleted = append(deleted, row)
+		}
+		counts[key]++
+	}
+	var inserted []tree.Datums
+	for _, row := range newRows {
+		key := rowKey(row)
+		if counts[key] > 0 {
+			counts[key]--
+		} else {
+			inserted = append(inserted, row)
+		}
+	}
+
+	var toDelete []tree.Datums
+	for _, row := range deleted {
+		key := rowKey(row)
+		if counts[key] > 0 {
+			toDelete = append(toDelete, row)
+		}
+	}
+	if len(toDelete) > 0 {
+		visibleCols := make(tree.Exprs, len(w.visible))
+		for i, ord := range w.visible {
+			visibleCols[i] = tree.NewUnresolvedName(w.cols[ord].GetName())
+		}
+		rows, err := w.scan(ctx, makeTupleFilter(visibleCols, toDelete))
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			key := rowKey(w.visibleValues(row))
+			if counts[key] == 0 {
+				continue
+			}
+			counts[key]--
+			if err := w.deleteRow(ctx, row); err != nil {
+				return err
+			}
+		}
+		for _, n := range counts {
+			if n > 0 {
+				return errors.WithHint(
+					pgerror.Newf(pgcode.DataCorrupted,
+						"materialized view %q does not contain the rows to be removed by the refresh",
+						w.view.GetName()),
+					"Use REFRESH MATERIALIZED VIEW without the incremental option to refresh the view.",
+				)
+			}
+		}
+	}
+	for _, row := range inserted {
+		if err := w.insertRow(ctx, row); err != nil {
+			return err
+		}
+	}
+	return w.flush(ctx)
+}
+
+// replaceGroups replaces the rows of the given groups in the view with the
+// given rows. If all is set, all rows of the view are replaced instead.
+func (w *incrementalViewWriter) replaceGroups(
+	ctx context.Context, groupCols []int, groups []tree.Datums, newRows []tree.Datums, all bool,
+) error {
+	var filter tree.Expr
+	if !all {
+		if len(groups) == 0 {
+			return nil
+		}
+		exprs := make(tree.Exprs, len(groupCols))
+		for i, ord := range groupCols {
+			exprs[i] = tree.NewUnresolvedName(w.cols[w.visible[ord]].GetName())
+		}
+		filter = makeTupleFilter(exprs, groups)
+	}
+	rows, err := w.scan(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := w.deleteRow(ctx, row); err != nil {
+			return err
+		}
+	}
+	for _, row := range newRows {
+		if err := w.insertRow(ctx, row); err != nil {
+			return err
+		}
+	}
+	return w.flush(ctx)
+}
+
+// scan returns the rows of the view that satisfy the given filter, with the
+// values of all public columns of the view.
+func (w *incrementalViewWriter) scan(ctx context.Context, filter tree.Expr) ([]tree.Datums, error) {
+	var buf strings.Builder
+	buf.WriteString("SELECT ")
+	for i, col := range w.cols {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(tree.NameString(col.GetName()))
+	}
+	fmt.Fprintf(&buf, " FROM [%d AS v]", w.view.GetID())
+	if filter != nil {
+		fmt.Fprintf(&buf, " WHERE %s", tree.AsStringWithFlags(filter, tree.FmtParsable))
+	}
+	return w.txn.QueryBufferedEx(
+		ctx, "refresh-materialized-view-incremental-scan", w.txn.KV(),
+		sessiondata.NodeUserSessionDataOverride, buf.String(),
+	)
+}
+
+func (w *incrementalViewWriter) visibleValues(row tree.Datums) tree.Datums {
+	values := make(tree.Datums, len(w.visible))
+	for i, ord := range w.visible {
+		values[i] = row[ord]
+	}
+	return values
+}
+
+func (w *incrementalViewWriter) deleteRow(ctx context.Context, values tree.Datums) error {
+	var pm row.PartialIndexUpdateHelper
+	if err := w.rd.DeleteRow(ctx, w.b, values, pm, false /* traceKV */); err != nil {
+		return err
+	}
+	return w.maybeFlush(ctx)
+}
+
+// insertRow inserts a row with the given values of the visible columns of the
+// view, generating a new value for the hidden rowid column.
+func (w *incrementalViewWriter) insertRow(ctx context.Context, visibleValues tree.Datums) error {
+	if len(visibleValues) != len(w.visible) {
+		return errors.AssertionFailedf(
+			"expected %d values for materialized view %q, found %d",
+			len(w.visible), w.view.GetName(), len(visibleValues))
+	}
+	values := make(tree.Datums, len(w.cols))
+	for i, ord := range w.visible {
+		values[ord] = visibleValues[i]
+	}
+	for i := range values {
+		if values[i] == nil {
+			// The hidden rowid column.
+			values[i] = tree.NewDInt(builtins.GenerateUniqueInt(
+				builtins.ProcessUniqueID(w.execCfg.NodeInfo.NodeID.SQLInstanceID()),
+			))
+		}
+	}
+	var pm row.PartialIndexUpdateHelper
+	if err := w.ri.InsertRow(
+		ctx, &row.KVBatchAdapter{Batch: w.b}, values, pm, false /* overwrite */, false, /* traceKV */
+	); err != nil {
+		return err
+	}
+	return w.maybeFlush(ctx)
+}
+
+func (w *incrementalViewWriter) maybeFlush(ctx context.Context) error {
+	w.n++
+	if w.n < incrementalViewWriterBatchSize {
+		return nil
+	}
+	return w.flush(ctx)
+}
+
+func (w *incrementalViewWriter) flush(ctx context.Context) error {
+	if w.n == 0 {
+		return nil
+	}
+	if err := w.txn.KV().Run(ctx, w.b); err != nil {
+		return err
+	}
+	w.b = w.txn.KV().NewBatch()
+	w.n = 0
+	return nil
+}
+
+// makeTupleFilter returns a filter that is true if the tuple of the given
+// expressions is not distinct from one of the given tuples of values.
+func makeTupleFilter(exprs tree.Exprs, values []tree.Datums) tree.Expr {
+	lhs := &tree.Tuple{Exprs: exprs}
+	var filter tree.Expr
+	or := func(e tree.Expr) {
+		if filter == nil {
+			filter = e
+		} else {
+			filter = &tree.OrExpr{Left: filter, Right: e}
+		}
+	}
+	// Tuples without NULLs are matched with a single IN expression, which can
+	// be used to constrain an index scan.
+	var in tree.Exprs
+	for _, vals := range values {
+		tuple := &tree.Tuple{Exprs: make(tree.Exprs, len(vals))}
+		hasNull := false
+		for i, d := range vals {
+			tuple.Exprs[i] = d
+			hasNull = hasNull || d == tree.DNull
+		}
+		if !hasNull {
+			in = append(in, tuple)
+			continue
+		}
+		or(&tree.ComparisonExpr{
+			Operator: treecmp.MakeComparisonOperator(treecmp.IsNotDistinctFrom),
+			Left:     lhs,
+			Right:    tuple,
+		})
+	}
+	if len(in) > 0 {
+		or(&tree.ComparisonExpr{
+			Operator: treecmp.MakeComparisonOperator(treecmp.In),
+			Left:     lhs,
+			Right:    &tree.Tuple{Exprs: in},
+		})
+	}
+	return filter
+}
+
+// rowKey returns a string that is equal for rows with equal values.
+func rowKey(row tree.Datums) string {
+	var key []byte
+	for _, d := range row {
+		if encoded, err := keyside.Encode(key, d, encoding.Ascending); err == nil {
+			key = encoded
+		} else {
+			// Fall back to the textual representation for types that cannot be
+			// key-encoded.
+			key = encoding.EncodeStringAscending(key, tree.AsStringWithFlags(d, tree.FmtParsable))
+		}
+	}
+	return string(key)
+}
+
+// distinctRows returns the distinct rows in the given rows.
+func distinctRows(rows []tree.Datums) []tree.Datums {
+	seen := make(map[string]struct{}, len(rows))
+	var res []tree.Datums
+	for _, row := range rows {
+		key := rowKey(row)
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			res = append(res, row)
+		}
+	}
+	return res
+}
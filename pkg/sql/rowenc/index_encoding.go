@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catenumpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
@@ -34,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/intsets"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
@@ -628,6 +630,9 @@ func EncodeInvertedIndexKeys(
 	if !indexGeoConfig.IsEmpty() {
 		return EncodeGeoInvertedIndexTableKeys(ctx, val, keyPrefix, indexGeoConfig)
 	}
+	if indexVecConfig := index.GetVecConfig(); !indexVecConfig.IsEmpty() {
+		return EncodeVectorInvertedIndexTableKeys(val, keyPrefix, indexVecConfig)
+	}
 	return EncodeInvertedIndexTableKeys(val, keyPrefix, index.GetVersion())
 }
 
@@ -698,6 +703,8 @@ func EncodeInvertedIndexTableKeys(
 		return encodeTrigramInvertedIndexTableKeys(string(*datum.(*tree.DString)), inKey, version, true /* pad */)
 	case types.TSVectorFamily:
 		return tsearch.EncodeInvertedIndexKeys(inKey, val.(*tree.DTSVector).TSVector)
+	case types.RangeFamily:
+		return encodeRangeInvertedIndexTableKeys(datum.(*tree.DRange), inKey)
 	}
 	return nil, errors.AssertionFailedf("trying to apply inverted index to unsupported type %s", datum.ResolvedType().SQLStringForError())
 }
@@ -820,15 +827,15 @@ func EncodeExistsInvertedIndexSpans(
 
 // EncodeOverlapsInvertedIndexSpans returns the spans that must be scanned in
 // the inverted index to evaluate an overlaps (&&) predicate with the given
-// datum, which should be an Array. These spans should be used to find the
-// objects in the index that could overlap with the given array. In other
-// words, if we have a predicate x && y, this function should use the value of
-// y to find the spans to scan in an inverted index on x.
+// datum, which should be an Array or a range. These spans should be used to
+// find the objects in the index that could overlap with the given array or
+// range. In other words, if we have a predicate x && y, this function should
+// use the value of y to find the spans to scan in an inverted index on x.
 //
 // The spans are returned in an inverted.SpanExpression, which represents the
 // set operations that must be applied on the spans read during execution. The
-// span expression returned will be tight. See comments in the
-// SpanExpression definition for details.
+// span expression returned will be tight for arrays, but not for ranges. See
+// comments in the SpanExpression definition for details.
 func EncodeOverlapsInvertedIndexSpans(
 	ctx context.Context, evalCtx *eval.Context, val tree.Datum,
 ) (invertedExpr inverted.Expression, err error) {
@@ -839,6 +846,8 @@ func EncodeOverlapsInvertedIndexSpans(
 	switch val.ResolvedType().Family() {
 	case types.ArrayFamily:
 		return encodeOverlapsArrayInvertedIndexSpans(val.(*tree.DArray), nil /* inKey */)
+	case types.RangeFamily:
+		return encodeOverlapsRangeInvertedIndexSpans(datum.(*tree.DRange))
 	default:
 		return nil, errors.AssertionFailedf(
 			"trying to apply inverted index to unsupported type %s", datum.ResolvedType().SQLStringForError(),
@@ -1087,6 +1096,28 @@ func EncodeGeoInvertedIndexTableKeys(
 	}
 }
 
+// EncodeVectorInvertedIndexTableKeys produces the key for the vector index
+// entry of the given vector. Unlike other inverted indexes, vector indexes
+// contain exactly one entry per row: the key of the partition containing the
+// vector, or a NULL key if the vector is NULL. Indexing NULLs allows nearest
+// neighbor searches to return them, since they sort before all distances.
+func EncodeVectorInvertedIndexTableKeys(
+	val tree.Datum, inKey []byte, indexVecConfig catpb.VectorIndexConfig,
+) (key [][]byte, err error) {
+	if val == tree.DNull {
+		return [][]byte{encoding.EncodeNullAscending(inKey)}, nil
+	}
+	v, ok := tree.UnwrapDOidWrapper(val).(*tree.DPGVector)
+	if !ok {
+		return nil, errors.AssertionFailedf("trying to apply vector index to unsupported type %s", val.ResolvedType().SQLStringForError())
+	}
+	enc, err := vecindex.EncodeVectorKey(inKey, indexVecConfig, v.T)
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{enc}, nil
+}
+
 func encodeGeoKeys(
 	inKey []byte, geoKeys []geoindex.Key, bbox geopb.BoundingBox,
 ) (keys [][]byte, err error) {
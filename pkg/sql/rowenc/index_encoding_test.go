@@ -982,6 +982,77 @@ func TestEncodeOverlapsArrayInvertedIndexSpans(t *testing.T) {
 	}
 }
 
+func TestEncodeOverlapsRangeInvertedIndexSpans(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		indexedValue string
+		value        string
+		ok           bool
+		expected     bool
+	}{
+		// The spans are not tight, so only ranges that are far apart are
+		// guaranteed to be excluded.
+		{`[1,5)`, `[4,10)`, true, true},
+		{`[1,5)`, `[1,5)`, true, true},
+		{`[1,5)`, `(,2)`, true, true},
+		{`(,)`, `[1000,1001)`, true, true},
+		{`[1,1000)`, `[500,501)`, true, true},
+		{`[1,5)`, `[100,200)`, true, false},
+		{`[100,200)`, `[1,5)`, true, false},
+		{`empty`, `[1,5)`, true, false},
+		{`[1,5)`, `empty`, false, false},
+	}
+
+	evalCtx := eval.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+	runTest := func(indexedValue, value tree.Datum, expected, ok bool) {
+		keys, err := EncodeInvertedIndexTableKeys(indexedValue, nil, descpb.LatestIndexDescriptorVersion)
+		require.NoError(t, err)
+
+		invertedExpr, err := EncodeOverlapsInvertedIndexSpans(context.Background(), &evalCtx, value)
+		require.NoError(t, err)
+
+		spanExpr, conversionOk := invertedExpr.(*inverted.SpanExpression)
+		if ok != conversionOk {
+			t.Fatalf("For (%s, %s), expected ok=%t, but got %t", indexedValue, value, ok, conversionOk)
+		} else if !ok {
+			return
+		}
+		if spanExpr.Tight {
+			t.Errorf("For (%s, %s), expected tight=false, but got true", indexedValue, value)
+		}
+
+		overlaps, err := spanExpr.ContainsKeys(keys)
+		require.NoError(t, err)
+		if expected && !overlaps {
+			t.Errorf("Expected spans of %s to overlap with %s but they did not", value, indexedValue)
+		} else if !expected && overlaps {
+			t.Errorf("Expected spans of %s to not overlap with %s but they did", value, indexedValue)
+		}
+	}
+
+	for _, c := range testCases {
+		indexedValue, _, err := tree.ParseDRangeFromString(&evalCtx, c.indexedValue, types.Int8Range)
+		require.NoError(t, err)
+		value, _, err := tree.ParseDRangeFromString(&evalCtx, c.value, types.Int8Range)
+		require.NoError(t, err)
+		runTest(indexedValue, value, c.expected, c.ok)
+	}
+
+	// Check that the spans of a random range include all the ranges that it
+	// overlaps.
+	rng, _ := randutil.NewTestRand()
+	for _, typ := range []*types.T{types.Int4Range, types.Int8Range, types.DateRange, types.TSTZRange} {
+		for i := 0; i < 100; i++ {
+			left := randgen.RandDatum(rng, typ, false /* nullOk */).(*tree.DRange)
+			right := randgen.RandDatum(rng, typ, false /* nullOk */).(*tree.DRange)
+			if left.Overlaps(right) {
+				runTest(left, right, true /* expected */, true /* ok */)
+			}
+		}
+	}
+}
+
 // Determines if the input array contains only one or more entries of the
 // same non-null element. NULL entries are not considered.
 func containsNonNullUniqueElement(
@@ -0,0 +1,131 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package keyside
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// encodeRangeKey generates an ordered key encoding of a range. The encoding
+// format for a range [a, b) is as follows:
+// [rangeMarker, lowerHeader, enc(a), inclusive, upperHeader, enc(b), exclusive].
+// Infinite bounds consist of only their header, and the empty range of a single
+// byte after the marker. See encoding.EncodeRangeKeyMarker for details.
+func encodeRangeKey(b []byte, r *tree.DRange, dir encoding.Direction) ([]byte, error) {
+	b = encoding.EncodeRangeKeyMarker(b, dir)
+	return encodeRangeKeyBounds(b, r, dir)
+}
+
+// encodeMultirangeKey generates an ordered key encoding of a multirange, which
+// consists of the bounds of each of its ranges, followed by a terminator.
+func encodeMultirangeKey(
+	b []byte, m *tree.DMultirange, dir encoding.Direction,
+) ([]byte, error) {
+	var err error
+	b = encoding.EncodeMultirangeKeyMarker(b, dir)
+	for _, r := range m.Ranges {
+		if b, err = encodeRangeKeyBounds(b, r, dir); err != nil {
+			return nil, err
+		}
+	}
+	return encoding.EncodeMultirangeKeyTerminator(b, dir), nil
+}
+
+func encodeRangeKeyBounds(b []byte, r *tree.DRange, dir encoding.Direction) ([]byte, error) {
+	if r.Empty {
+		return encoding.EncodeEmptyRangeKey(b, dir), nil
+	}
+	var err error
+	for _, upper := range []bool{false, true} {
+		bound := r.Lower
+		if upper {
+			bound = r.Upper
+		}
+		b = encoding.EncodeRangeKeyBoundHeader(b, upper, bound.IsInfinite(), dir)
+		if bound.IsInfinite() {
+			continue
+		}
+		if b, err = Encode(b, bound.Val, dir); err != nil {
+			return nil, err
+		}
+		b = encoding.EncodeRangeKeyBoundInclusive(b, upper, bound.Inclusive, dir)
+	}
+	return b, nil
+}
+
+// decodeRangeKey decodes a range key generated by encodeRangeKey.
+func decodeRangeKey(
+	a *tree.DatumAlloc, t *types.T, buf []byte, dir encoding.Direction,
+) (tree.Datum, []byte, error) {
+	buf, err := encoding.ValidateAndConsumeRangeKeyMarker(buf, dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	return decodeRangeKeyBounds(a, t, buf, dir)
+}
+
+// decodeMultirangeKey decodes a multirange key generated by
+// encodeMultirangeKey.
+func decodeMultirangeKey(
+	a *tree.DatumAlloc, t *types.T, buf []byte, dir encoding.Direction,
+) (tree.Datum, []byte, error) {
+	buf, err := encoding.ValidateAndConsumeMultirangeKeyMarker(buf, dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	var ranges []*tree.DRange
+	for {
+		if len(buf) == 0 {
+			return nil, nil, errors.AssertionFailedf("invalid multirange encoding (unterminated)")
+		}
+		if encoding.IsMultirangeKeyDone(buf, dir) {
+			buf = buf[1:]
+			break
+		}
+		var r *tree.DRange
+		if r, buf, err = decodeRangeKeyBounds(a, t.RangeContents(), buf, dir); err != nil {
+			return nil, nil, err
+		}
+		ranges = append(ranges, r)
+	}
+	return tree.NewDMultirange(t, ranges), buf, nil
+}
+
+func decodeRangeKeyBounds(
+	a *tree.DatumAlloc, t *types.T, buf []byte, dir encoding.Direction,
+) (*tree.DRange, []byte, error) {
+	var bounds [2]tree.RangeBound
+	for i, upper := range []bool{false, true} {
+		var empty, infinite bool
+		var err error
+		buf, empty, infinite, err = encoding.DecodeRangeKeyBoundHeader(buf, upper, dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		if empty {
+			return tree.NewDEmptyRange(t), buf, nil
+		}
+		if infinite {
+			continue
+		}
+		if bounds[i].Val, buf, err = Decode(a, t.RangeContents(), buf, dir); err != nil {
+			return nil, nil, err
+		}
+		if buf, bounds[i].Inclusive, err = encoding.DecodeRangeKeyBoundInclusive(buf, upper, dir); err != nil {
+			return nil, nil, err
+		}
+	}
+	r, err := tree.NewDRange(t, bounds[0], bounds[1])
+	return r, buf, err
+}
@@ -0,0 +1,168 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rowenc
+
+import (
+	"math"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// Inverted indexes on ranges are used to accelerate overlap (&&) queries. They
+// are built like the inverted indexes on geospatial types, except that the
+// space being indexed is one-dimensional.
+//
+// Every value of the element type of a range is mapped, in order, to a
+// position in [0, 2^63). The positions are partitioned into a hierarchy of
+// cells: the single cell at level 0 contains all positions, and each cell at
+// level L is split into two cells at level L+1, down to the cells at level 63,
+// which contain a single position. Like S2 cell IDs, the ID of a cell is the
+// prefix of the positions it contains, followed by a 1 bit and padded with
+// zeros to 64 bits. Therefore, the IDs of the descendants of a cell are the
+// contiguous interval around its own ID.
+//
+// A range is indexed under the cells of the deepest level at which it is
+// covered by at most rangeIndexMaxCells cells. Two overlapping ranges contain
+// a common position, and the cells of their coverings that contain it are
+// either equal or one is an ancestor of the other. So, the rows that may
+// overlap a range are found by scanning the ancestors and the descendants of
+// each cell of its covering.
+
+// rangeIndexMaxCells is the maximum number of cells a range is indexed under.
+const rangeIndexMaxCells = 4
+
+// rangeIndexMaxLevel is the level of the smallest cells.
+const rangeIndexMaxLevel = 63
+
+// rangeIndexPosition maps the given range element to its position.
+func rangeIndexPosition(d tree.Datum) (uint64, error) {
+	var v int64
+	switch t := tree.UnwrapDOidWrapper(d).(type) {
+	case *tree.DInt:
+		v = int64(*t)
+	case *tree.DDate:
+		v = t.UnixEpochDaysWithOrig()
+	case *tree.DTimestamp:
+		v = t.UnixMicro()
+	case *tree.DTimestampTZ:
+		v = t.UnixMicro()
+	default:
+		return 0, errors.AssertionFailedf(
+			"trying to apply inverted index to unsupported type %s", d.ResolvedType().SQLStringForError(),
+		)
+	}
+	// Flip the sign bit so that the order of the values is preserved, and drop
+	// the lowest bit to fit the position in 63 bits.
+	return (uint64(v) ^ (1 << 63)) >> 1, nil
+}
+
+// rangeIndexCovering returns the IDs of the cells that cover the given range,
+// in increasing order. No cells are returned for an empty range.
+func rangeIndexCovering(r *tree.DRange) ([]uint64, error) {
+	if r.Empty {
+		return nil, nil
+	}
+	lo, hi := uint64(0), uint64(math.MaxInt64)
+	var err error
+	if !r.Lower.IsInfinite() {
+		if lo, err = rangeIndexPosition(r.Lower.Val); err != nil {
+			return nil, err
+		}
+	}
+	if !r.Upper.IsInfinite() {
+		if hi, err = rangeIndexPosition(r.Upper.Val); err != nil {
+			return nil, err
+		}
+	}
+	for level := rangeIndexMaxLevel; ; level-- {
+		shift := uint(rangeIndexMaxLevel - level)
+		first, last := lo>>shift, hi>>shift
+		if last-first < rangeIndexMaxCells || level == 0 {
+			cells := make([]uint64, 0, last-first+1)
+			for p := first; p <= last; p++ {
+				cells = append(cells, p<<(shift+1)|1<<shift)
+			}
+			return cells, nil
+		}
+	}
+}
+
+// encodeRangeInvertedIndexTableKeys returns the inverted index keys for the
+// given range, one per cell of its covering. The input inKey is prefixed to
+// all returned keys.
+func encodeRangeInvertedIndexTableKeys(val *tree.DRange, inKey []byte) ([][]byte, error) {
+	cells, err := rangeIndexCovering(val)
+	if err != nil {
+		return nil, err
+	}
+	outKeys := make([][]byte, len(cells))
+	for i, cell := range cells {
+		// Make sure to copy inKey into a new byte slice to avoid aliasing.
+		outKey := make([]byte, len(inKey), len(inKey)+encoding.MaxVarintLen)
+		copy(outKey, inKey)
+		outKeys[i] = encoding.EncodeUvarintAscending(outKey, cell)
+	}
+	return outKeys, nil
+}
+
+// encodeOverlapsRangeInvertedIndexSpans returns the spans that must be scanned
+// in an inverted index on ranges to find the ranges that could overlap the
+// given range. The returned expression is not tight.
+func encodeOverlapsRangeInvertedIndexSpans(val *tree.DRange) (inverted.Expression, error) {
+	cells, err := rangeIndexCovering(val)
+	if err != nil {
+		return nil, err
+	}
+	if len(cells) == 0 {
+		return inverted.NonInvertedColExpression{}, nil
+	}
+	// Collect the inclusive intervals of cell IDs to scan: each cell of the
+	// covering along with its descendants, and each of their ancestors. Since
+	// all the cells of the covering are at the same level, these intervals
+	// only overlap when two cells share an ancestor.
+	type cellSpan struct{ start, end uint64 }
+	cellSpans := make([]cellSpan, 0, len(cells)*2)
+	for _, cell := range cells {
+		// The lowest set bit of a cell ID determines its level.
+		lsb := cell & -cell
+		cellSpans = append(cellSpans, cellSpan{start: cell - (lsb - 1), end: cell + (lsb - 1)})
+		for lsb != 1<<rangeIndexMaxLevel {
+			lsb <<= 1
+			parent := cell&-(lsb<<1) | lsb
+			cellSpans = append(cellSpans, cellSpan{start: parent, end: parent})
+		}
+	}
+	sort.Slice(cellSpans, func(i, j int) bool { return cellSpans[i].start < cellSpans[j].start })
+
+	spans := make([]inverted.Span, 0, len(cellSpans))
+	for i, s := range cellSpans {
+		if i > 0 && s.start == cellSpans[i-1].start {
+			continue
+		}
+		start := encoding.EncodeUvarintAscending(nil, s.start)
+		var end []byte
+		if s.end < math.MaxUint64 {
+			end = encoding.EncodeUvarintAscending(nil, s.end+1)
+		} else {
+			end = roachpb.Key(encoding.EncodeUvarintAscending(nil, s.end)).PrefixEnd()
+		}
+		spans = append(spans, inverted.Span{Start: start, End: end})
+	}
+	return &inverted.SpanExpression{
+		SpansToRead:        spans,
+		FactoredUnionSpans: spans,
+	}, nil
+}
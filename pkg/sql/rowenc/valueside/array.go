@@ -239,6 +239,10 @@ func DatumTypeToArrayElementEncodingType(t *types.T) (encoding.Type, error) {
 		return encoding.JSON, nil
 	case types.TupleFamily:
 		return encoding.Tuple, nil
+	case types.RangeFamily:
+		return encoding.Range, nil
+	case types.MultirangeFamily:
+		return encoding.Multirange, nil
 	case types.ArrayFamily:
 		return 0, unimplemented.NewWithIssueDetail(32552, "", "nested arrays are not fully supported")
 	default:
@@ -321,6 +325,18 @@ func encodeArrayElement(b []byte, d tree.Datum) ([]byte, error) {
 		return encoding.EncodeUntaggedBytesValue(b, encoded), nil
 	case *tree.DTuple:
 		return encodeUntaggedTuple(t, b, encoding.NoColumnID, nil)
+	case *tree.DRange:
+		encoded, err := encodeRange(t, nil)
+		if err != nil {
+			return nil, err
+		}
+		return encoding.EncodeUntaggedBytesValue(b, encoded), nil
+	case *tree.DMultirange:
+		encoded, err := encodeMultirange(t, nil)
+		if err != nil {
+			return nil, err
+		}
+		return encoding.EncodeUntaggedBytesValue(b, encoded), nil
 	case *tree.DTSQuery:
 		encoded := tsearch.EncodeTSQueryPGBinary(nil, t.TSQuery)
 		return encoding.EncodeUntaggedBytesValue(b, encoded), nil
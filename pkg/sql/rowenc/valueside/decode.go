@@ -249,6 +249,20 @@ func DecodeUntaggedDatum(
 			return nil, nil, err
 		}
 		return decodeArray(a, t, b)
+	case types.RangeFamily:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		r, _, err := decodeRange(a, t, data)
+		return r, b, err
+	case types.MultirangeFamily:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		m, _, err := decodeMultirange(a, t, data)
+		return m, b, err
 	case types.TupleFamily:
 		return decodeTuple(a, t, buf)
 	case types.EnumFamily:
@@ -109,6 +109,18 @@ func Encode(appendTo []byte, colID ColumnIDDelta, val tree.Datum, scratch []byte
 			return nil, err
 		}
 		return encoding.EncodeArrayValue(appendTo, uint32(colID), a), nil
+	case *tree.DRange:
+		r, err := encodeRange(t, scratch)
+		if err != nil {
+			return nil, err
+		}
+		return encoding.EncodeRangeValue(appendTo, uint32(colID), r), nil
+	case *tree.DMultirange:
+		m, err := encodeMultirange(t, scratch)
+		if err != nil {
+			return nil, err
+		}
+		return encoding.EncodeMultirangeValue(appendTo, uint32(colID), m), nil
 	case *tree.DTuple:
 		return encodeTuple(t, appendTo, uint32(colID), scratch)
 	case *tree.DCollatedString:
@@ -0,0 +1,123 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package valueside
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// The value encoding of a range starts with a flags byte, followed by the
+// untagged value encodings of its finite bounds, lower bound first.
+const (
+	rangeFlagEmpty byte = 1 << iota
+	rangeFlagLowerInfinite
+	rangeFlagUpperInfinite
+	rangeFlagLowerInclusive
+	rangeFlagUpperInclusive
+)
+
+// encodeRange produces the value encoding for a range.
+func encodeRange(d *tree.DRange, scratch []byte) ([]byte, error) {
+	scratch = scratch[0:0]
+	var flags byte
+	switch {
+	case d.Empty:
+		return append(scratch, rangeFlagEmpty), nil
+	case d.Lower.IsInfinite():
+		flags |= rangeFlagLowerInfinite
+	case d.Lower.Inclusive:
+		flags |= rangeFlagLowerInclusive
+	}
+	switch {
+	case d.Upper.IsInfinite():
+		flags |= rangeFlagUpperInfinite
+	case d.Upper.Inclusive:
+		flags |= rangeFlagUpperInclusive
+	}
+	scratch = append(scratch, flags)
+	var err error
+	for _, b := range [...]tree.RangeBound{d.Lower, d.Upper} {
+		if b.IsInfinite() {
+			continue
+		}
+		if scratch, err = encodeArrayElement(scratch, b.Val); err != nil {
+			return nil, err
+		}
+	}
+	return scratch, nil
+}
+
+// decodeRange decodes the value encoding for a range. It returns the
+// remaining bytes after the encoded range.
+func decodeRange(a *tree.DatumAlloc, rangeType *types.T, b []byte) (*tree.DRange, []byte, error) {
+	if len(b) == 0 {
+		return nil, nil, errors.AssertionFailedf("invalid range encoding (empty)")
+	}
+	flags := b[0]
+	b = b[1:]
+	if flags&rangeFlagEmpty != 0 {
+		return tree.NewDEmptyRange(rangeType), b, nil
+	}
+	lower := tree.RangeBound{Inclusive: flags&rangeFlagLowerInclusive != 0}
+	upper := tree.RangeBound{Inclusive: flags&rangeFlagUpperInclusive != 0}
+	var err error
+	if flags&rangeFlagLowerInfinite == 0 {
+		if lower.Val, b, err = DecodeUntaggedDatum(a, rangeType.RangeContents(), b); err != nil {
+			return nil, b, err
+		}
+	}
+	if flags&rangeFlagUpperInfinite == 0 {
+		if upper.Val, b, err = DecodeUntaggedDatum(a, rangeType.RangeContents(), b); err != nil {
+			return nil, b, err
+		}
+	}
+	r, err := tree.NewDRange(rangeType, lower, upper)
+	return r, b, err
+}
+
+// encodeMultirange produces the value encoding for a multirange, which is the
+// number of its ranges followed by their encodings.
+func encodeMultirange(d *tree.DMultirange, scratch []byte) ([]byte, error) {
+	scratch = encoding.EncodeNonsortingUvarint(scratch[0:0], uint64(len(d.Ranges)))
+	var rangeScratch []byte
+	var err error
+	for _, r := range d.Ranges {
+		if rangeScratch, err = encodeRange(r, rangeScratch); err != nil {
+			return nil, err
+		}
+		scratch = encoding.EncodeUntaggedBytesValue(scratch, rangeScratch)
+	}
+	return scratch, nil
+}
+
+// decodeMultirange decodes the value encoding for a multirange.
+func decodeMultirange(
+	a *tree.DatumAlloc, multirangeType *types.T, b []byte,
+) (tree.Datum, []byte, error) {
+	b, _, n, err := encoding.DecodeNonsortingUvarint(b)
+	if err != nil {
+		return nil, b, err
+	}
+	ranges := make([]*tree.DRange, n)
+	for i := range ranges {
+		var data []byte
+		if b, data, err = encoding.DecodeUntaggedBytesValue(b); err != nil {
+			return nil, b, err
+		}
+		if ranges[i], _, err = decodeRange(a, multirangeType.RangeContents(), data); err != nil {
+			return nil, b, err
+		}
+	}
+	return tree.NewDMultirange(multirangeType, ranges), b, nil
+}
@@ -102,12 +102,9 @@ func newSampleAggregator(
 		if _, ok := supportedSketchTypes[s.SketchType]; !ok {
 			return nil, errors.Errorf("unsupported sketch type %s", s.SketchType)
 		}
-		if s.GenerateHistogram && s.HistogramMaxBuckets == 0 {
+		if (s.GenerateHistogram || s.GenerateMostCommonValues) && s.HistogramMaxBuckets == 0 {
 			return nil, errors.Errorf("histogram max buckets not specified")
 		}
-		if s.GenerateHistogram && len(s.Columns) != 1 {
-			return nil, errors.Errorf("histograms require one column")
-		}
 	}
 
 	// Limit the memory use by creating a child monitor with a hard limit.
@@ -153,8 +150,10 @@ func newSampleAggregator(
 			numNulls: 0,
 			numRows:  0,
 		}
-		if spec.Sketches[i].GenerateHistogram {
-			sampleCols.Add(int(spec.Sketches[i].Columns[0]))
+		if sk := &spec.Sketches[i]; sk.GenerateHistogram || sk.GenerateDependencies || sk.GenerateMostCommonValues {
+			for _, col := range spec.Sketches[i].Columns {
+				sampleCols.Add(int(col))
+			}
 		}
 	}
 
@@ -463,8 +462,20 @@ func (s *sampleAggregator) writeResults(ctx context.Context) error {
 		for _, si := range s.sketches {
 			var histogram *stats.HistogramData
 			if si.spec.GenerateHistogram {
-				colIdx := int(si.spec.Columns[0])
-				typ := s.inTypes[colIdx]
+				colIdxs := make([]int, len(si.spec.Columns))
+				for i, c := range si.spec.Columns {
+					colIdxs[i] = int(c)
+				}
+				typ := s.inTypes[colIdxs[0]]
+				if len(colIdxs) > 1 {
+					// Multi-column histograms are built over tuples of the
+					// column values.
+					colTypes := make([]*types.T, len(colIdxs))
+					for i, colIdx := range colIdxs {
+						colTypes[i] = s.inTypes[colIdx]
+					}
+					typ = types.MakeTuple(colTypes)
+				}
 
 				var lowerBound tree.Datum
 				if si.spec.PrevLowerBound != "" {
@@ -488,7 +499,7 @@ func (s *sampleAggregator) writeResults(ctx context.Context) error {
 					ctx,
 					s.FlowCtx.EvalCtx,
 					&s.sr,
-					colIdx,
+					colIdxs,
 					typ,
 					si.numRows-si.numNulls,
 					s.getDistinctCount(&si, false /* includeNulls */),
@@ -511,7 +522,7 @@ func (s *sampleAggregator) writeResults(ctx context.Context) error {
 				// the column.
 
 				invDistinctCount := s.getDistinctCount(invSketch, false /* includeNulls */)
-				// Use 0 for the colIdx here because it refers
+				// Use 0 for the column index here because it refers
 				// to the column index of the samples, which
 				// only has a single bytes column with the
 				// inverted keys.
@@ -519,7 +530,7 @@ func (s *sampleAggregator) writeResults(ctx context.Context) error {
 					ctx,
 					s.FlowCtx.EvalCtx,
 					invSr,
-					0, /* colIdx */
+					[]int{0}, /* colIdxs */
 					types.Bytes,
 					invSketch.numRows-invSketch.numNulls,
 					invDistinctCount,
@@ -531,6 +542,12 @@ func (s *sampleAggregator) writeResults(ctx context.Context) error {
 				}
 				histogram = &h
 			}
+			if len(si.spec.Columns) > 1 && (si.spec.GenerateDependencies || si.spec.GenerateMostCommonValues) {
+				var err error
+				if histogram, err = s.generateExtendedStatistics(ctx, &si, histogram); err != nil {
+					return err
+				}
+			}
 
 			columnIDs := make([]descpb.ColumnID, len(si.spec.Columns))
 			for i, c := range si.spec.Columns {
@@ -640,15 +657,16 @@ func (s *sampleAggregator) getDistinctCount(si *sketchInfo, includeNulls bool) i
 	return distinctCount
 }
 
-// generateHistogram returns a histogram (on a given column) from a set of
-// samples.
+// generateHistogram returns a histogram (on the given columns) from a set of
+// samples. If there are multiple columns, the histogram is built over tuples
+// of the column values and colType must be the tuple type.
 // numRows is the total number of rows from which values were sampled
-// (excluding rows that have NULL values on the histogram column).
+// (excluding rows that have NULL values on all of the histogram columns).
 func (s *sampleAggregator) generateHistogram(
 	ctx context.Context,
 	evalCtx *eval.Context,
 	sr *stats.SampleReservoir,
-	colIdx int,
+	colIdxs []int,
 	colType *types.T,
 	numRows int64,
 	distinctCount int64,
@@ -656,7 +674,13 @@ func (s *sampleAggregator) generateHistogram(
 	lowerBound tree.Datum,
 ) (stats.HistogramData, error) {
 	prevCapacity := sr.Cap()
-	values, err := sr.GetNonNullDatums(ctx, &s.tempMemAcc, colIdx)
+	var values tree.Datums
+	var err error
+	if len(colIdxs) == 1 {
+		values, err = sr.GetNonNullDatums(ctx, &s.tempMemAcc, colIdxs[0])
+	} else {
+		values, err = sr.GetNonNullTuples(ctx, &s.tempMemAcc, colIdxs, colType)
+	}
 	if err != nil {
 		return stats.HistogramData{}, err
 	}
@@ -680,6 +704,38 @@ func (s *sampleAggregator) generateHistogram(
 	return h, err
 }
 
+// generateExtendedStatistics adds the most common values and the functional
+// dependencies requested by the given multi-column sketch to the histogram,
+// which is created if nil.
+func (s *sampleAggregator) generateExtendedStatistics(
+	ctx context.Context, si *sketchInfo, histogram *stats.HistogramData,
+) (*stats.HistogramData, error) {
+	colIdxs := make([]int, len(si.spec.Columns))
+	colTypes := make([]*types.T, len(si.spec.Columns))
+	for i, c := range si.spec.Columns {
+		colIdxs[i] = int(c)
+		colTypes[i] = s.inTypes[c]
+	}
+	typ := types.MakeTuple(colTypes)
+	values, err := s.sr.GetNonNullTuples(ctx, &s.tempMemAcc, colIdxs, typ)
+	if err != nil {
+		return nil, err
+	}
+	return stats.BuildExtendedStatistics(
+		ctx,
+		s.FlowCtx.EvalCtx,
+		histogram,
+		typ,
+		values,
+		si.numRows-si.numNulls,
+		s.getDistinctCount(si, false /* includeNulls */),
+		int(si.spec.HistogramMaxBuckets),
+		si.spec.GenerateMostCommonValues,
+		si.spec.GenerateDependencies,
+		s.FlowCtx.Cfg.Settings,
+	)
+}
+
 var _ execinfra.DoesNotUseTxn = &sampleAggregator{}
 
 // DoesNotUseTxn implements the DoesNotUseTxn interface.
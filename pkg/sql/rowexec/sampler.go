@@ -143,8 +143,10 @@ func newSamplerProcessor(
 			numNulls: 0,
 			numRows:  0,
 		}
-		if spec.Sketches[i].GenerateHistogram {
-			sampleCols.Add(int(spec.Sketches[i].Columns[0]))
+		if sk := &spec.Sketches[i]; sk.GenerateHistogram || sk.GenerateDependencies || sk.GenerateMostCommonValues {
+			for _, col := range spec.Sketches[i].Columns {
+				sampleCols.Add(int(col))
+			}
 		}
 	}
 	for i := range spec.InvertedSketches {
@@ -343,6 +345,8 @@ func (s *samplerProcessor) mainLoop(
 			switch s.outTypes[col].Family() {
 			case types.GeographyFamily, types.GeometryFamily:
 				invKeys, err = rowenc.EncodeGeoInvertedIndexTableKeys(ctx, row[col].Datum, nil /* inKey */, index.GeoConfig)
+			case types.PGVectorFamily:
+				invKeys, err = rowenc.EncodeVectorInvertedIndexTableKeys(row[col].Datum, nil /* inKey */, index.VecConfig)
 			default:
 				invKeys, err = rowenc.EncodeInvertedIndexTableKeys(row[col].Datum, nil /* inKey */, index.Version)
 			}
@@ -0,0 +1,288 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package scheduledsql implements CREATE SCHEDULE FOR SQL, which runs
+// user-provided SQL statements periodically through the job scheduler.
+package scheduledsql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs/schedulebase"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	pbtypes "github.com/gogo/protobuf/types"
+)
+
+const opName = "CREATE SCHEDULE FOR SQL"
+
+const (
+	optFirstRun          = "first_run"
+	optOnExecFailure     = "on_execution_failure"
+	optOnPreviousRunning = "on_previous_running"
+)
+
+var expectValues = map[string]exprutil.KVStringOptValidate{
+	optFirstRun:          exprutil.KVStringOptRequireValue,
+	optOnExecFailure:     exprutil.KVStringOptRequireValue,
+	optOnPreviousRunning: exprutil.KVStringOptRequireValue,
+}
+
+var headerCols = colinfo.ResultColumns{
+	{Name: "schedule_id", Typ: types.Int},
+	{Name: "label", Typ: types.String},
+	{Name: "status", Typ: types.String},
+	{Name: "first_run", Typ: types.TimestampTZ},
+	{Name: "schedule", Typ: types.String},
+	{Name: "statement", Typ: types.String},
+}
+
+// scheduledSQLSpec holds the evaluated components of a CREATE SCHEDULE FOR
+// SQL statement.
+type scheduledSQLSpec struct {
+	*tree.ScheduledSQL
+
+	scheduleLabel *string
+	statement     string
+	recurrence    *string
+	scheduleOpts  map[string]string
+}
+
+func makeScheduledSQLSpec(
+	ctx context.Context, p sql.PlanHookState, schedule *tree.ScheduledSQL,
+) (*scheduledSQLSpec, error) {
+	exprEval := p.ExprEvaluator(opName)
+	spec := &scheduledSQLSpec{ScheduledSQL: schedule}
+
+	if schedule.ScheduleLabelSpec.Label != nil {
+		label, err := exprEval.String(ctx, schedule.ScheduleLabelSpec.Label)
+		if err != nil {
+			return nil, err
+		}
+		spec.scheduleLabel = &label
+	}
+
+	stmt, err := exprEval.String(ctx, schedule.Statement)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateStatements(stmt); err != nil {
+		return nil, err
+	}
+	spec.statement = stmt
+
+	rec, err := exprEval.String(ctx, schedule.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+	spec.recurrence = &rec
+
+	spec.scheduleOpts, err = exprEval.KVOptions(ctx, schedule.ScheduleOptions, expectValues)
+	if err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// validateStatements checks that stmt can be executed by a scheduled SQL
+// job: it must parse, and it must not contain transaction control
+// statements since each run executes in a transaction of its own.
+func validateStatements(stmt string) error {
+	stmts, err := parser.Parse(stmt)
+	if err != nil {
+		return pgerror.Wrap(err, pgcode.Syntax, "invalid scheduled statement")
+	}
+	if len(stmts) == 0 {
+		return pgerror.New(pgcode.InvalidParameterValue, "no statements to schedule")
+	}
+	for _, s := range stmts {
+		if s.AST.StatementType() == tree.TypeTCL {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"%s is not supported in scheduled SQL", s.AST.StatementTag())
+		}
+	}
+	return nil
+}
+
+func makeScheduleDetails(
+	opts map[string]string, clusterID uuid.UUID, version clusterversion.ClusterVersion,
+) (jobspb.ScheduleDetails, error) {
+	var details jobspb.ScheduleDetails
+	if v, ok := opts[optOnExecFailure]; ok {
+		if err := schedulebase.ParseOnError(v, &details); err != nil {
+			return details, err
+		}
+	}
+	if v, ok := opts[optOnPreviousRunning]; ok {
+		if err := schedulebase.ParseWaitBehavior(v, &details); err != nil {
+			return details, err
+		}
+	}
+	details.ClusterID = clusterID
+	details.CreationClusterVersion = version
+	return details, nil
+}
+
+// doCreateScheduledSQL creates the schedule described by spec. The schedule
+// is owned by, and its statements run as, the user creating it.
+func doCreateScheduledSQL(
+	ctx context.Context, p sql.PlanHookState, spec *scheduledSQLSpec, resultsCh chan<- tree.Datums,
+) error {
+	env := sql.JobSchedulerEnv(p.ExecCfg().JobsKnobs())
+
+	recurrence, err := schedulebase.ComputeScheduleRecurrence(env.Now(), spec.recurrence)
+	if err != nil {
+		return err
+	}
+
+	var scheduleLabel string
+	if spec.scheduleLabel != nil {
+		if spec.ScheduleLabelSpec.IfNotExists {
+			exists, err := schedulebase.CheckScheduleAlreadyExists(ctx, p, *spec.scheduleLabel)
+			if err != nil {
+				return err
+			}
+			if exists {
+				p.BufferClientNotice(ctx,
+					pgnotice.Newf("schedule %q already exists, skipping", *spec.scheduleLabel),
+				)
+				return nil
+			}
+		}
+		scheduleLabel = *spec.scheduleLabel
+	} else {
+		scheduleLabel = fmt.Sprintf("SQL %d", env.Now().Unix())
+	}
+
+	evalCtx := &p.ExtendedEvalContext().Context
+	var firstRun *time.Time
+	if v, ok := spec.scheduleOpts[optFirstRun]; ok {
+		ts, _, err := tree.ParseDTimestampTZ(evalCtx, v, time.Microsecond)
+		if err != nil {
+			return err
+		}
+		firstRun = &ts.Time
+	}
+
+	details, err := makeScheduleDetails(
+		spec.scheduleOpts, evalCtx.ClusterID, p.ExecCfg().Settings.Version.ActiveVersion(ctx),
+	)
+	if err != nil {
+		return err
+	}
+
+	sj := jobs.NewScheduledJob(env)
+	sj.SetScheduleLabel(scheduleLabel)
+	sj.SetOwner(p.User())
+	if err := sj.SetSchedule(recurrence.Cron); err != nil {
+		return err
+	}
+	sj.SetScheduleDetails(details)
+	any, err := pbtypes.MarshalAny(&jobspb.ScheduledSQLExecutionArgs{
+		Statement: spec.statement,
+		Database:  p.SessionData().Database,
+	})
+	if err != nil {
+		return err
+	}
+	sj.SetExecutionDetails(
+		tree.ScheduledSQLExecutor.InternalName(), jobspb.ExecutionArguments{Args: any},
+	)
+	if firstRun != nil {
+		sj.SetNextRun(*firstRun)
+	}
+
+	if err := jobs.ScheduledJobTxn(p.InternalSQLTxn()).Create(ctx, sj); err != nil {
+		return err
+	}
+
+	nextRun, err := tree.MakeDTimestampTZ(sj.NextRun(), time.Microsecond)
+	if err != nil {
+		return err
+	}
+	resultsCh <- tree.Datums{
+		tree.NewDInt(tree.DInt(sj.ScheduleID())),
+		tree.NewDString(sj.ScheduleLabel()),
+		tree.NewDString("ACTIVE"),
+		nextRun,
+		tree.NewDString(sj.ScheduleExpr()),
+		tree.NewDString(spec.statement),
+	}
+	telemetry.Count("scheduled-sql.create.success")
+	return nil
+}
+
+func createScheduledSQLHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	schedule, ok := stmt.(*tree.ScheduledSQL)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_3_ScheduledSQL) {
+		return nil, nil, nil, false, pgerror.New(pgcode.FeatureNotSupported,
+			"CREATE SCHEDULE FOR SQL not supported until upgrade to version 24.3 is finalized")
+	}
+
+	spec, err := makeScheduledSQLSpec(ctx, p, schedule)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		if err := doCreateScheduledSQL(ctx, p, spec, resultsCh); err != nil {
+			telemetry.Count("scheduled-sql.create.failed")
+			return err
+		}
+		return nil
+	}
+	return fn, headerCols, nil, false, nil
+}
+
+func createScheduledSQLTypeCheck(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (matched bool, header colinfo.ResultColumns, _ error) {
+	schedule, ok := stmt.(*tree.ScheduledSQL)
+	if !ok {
+		return false, nil, nil
+	}
+	if err := exprutil.TypeCheck(ctx, opName, p.SemaCtx(),
+		exprutil.Strings{
+			schedule.Statement,
+			schedule.Recurrence,
+			schedule.ScheduleLabelSpec.Label,
+		},
+		&exprutil.KVOptions{
+			KVOptions:  schedule.ScheduleOptions,
+			Validation: expectValues,
+		},
+	); err != nil {
+		return false, nil, err
+	}
+	return true, headerCols, nil
+}
+
+func init() {
+	sql.AddPlanHook("schedule sql", createScheduledSQLHook, createScheduledSQLTypeCheck)
+}
@@ -0,0 +1,215 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scheduledsql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs/schedulebase"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+)
+
+// maxRunHistory is the number of runs kept in the execution history of a
+// schedule.
+const maxRunHistory = 10
+
+// scheduledSQLExecutor starts a scheduled SQL job every time its schedule
+// fires, and records the outcome of each job in the schedule.
+type scheduledSQLExecutor struct {
+	metrics *jobs.ExecutorMetrics
+}
+
+var _ jobs.ScheduledJobExecutor = (*scheduledSQLExecutor)(nil)
+
+// ExecuteJob implements the jobs.ScheduledJobExecutor interface.
+func (e *scheduledSQLExecutor) ExecuteJob(
+	ctx context.Context,
+	txn isql.Txn,
+	cfg *scheduledjobs.JobExecutionConfig,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+) error {
+	if err := e.createJob(ctx, txn, cfg, sj); err != nil {
+		e.metrics.NumFailed.Inc(1)
+		return err
+	}
+	e.metrics.NumStarted.Inc(1)
+	return nil
+}
+
+func (e *scheduledSQLExecutor) createJob(
+	ctx context.Context, txn isql.Txn, cfg *scheduledjobs.JobExecutionConfig, sj *jobs.ScheduledJob,
+) error {
+	args, err := unmarshalArgs(sj)
+	if err != nil {
+		return err
+	}
+
+	p, cleanup := cfg.PlanHookMaker(ctx, "invoke-scheduled-sql", txn.KV(), sj.Owner())
+	defer cleanup()
+	registry := p.(sql.PlanHookState).ExecCfg().JobRegistry
+
+	record := jobs.Record{
+		Description: args.Statement,
+		Statements:  []string{args.Statement},
+		Username:    sj.Owner(),
+		Details: jobspb.ScheduledSQLDetails{
+			Statement: args.Statement,
+			Database:  args.Database,
+		},
+		Progress: jobspb.ScheduledSQLProgress{},
+		CreatedBy: &jobs.CreatedByInfo{
+			ID:   int64(sj.ScheduleID()),
+			Name: jobs.CreatedByScheduledJobs,
+		},
+	}
+	_, err = registry.CreateAdoptableJobWithTxn(ctx, record, registry.MakeJobID(), txn)
+	return err
+}
+
+// NotifyJobTermination implements the jobs.ScheduledJobExecutor interface.
+func (e *scheduledSQLExecutor) NotifyJobTermination(
+	ctx context.Context,
+	txn isql.Txn,
+	jobID jobspb.JobID,
+	jobStatus jobs.Status,
+	details jobspb.Details,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+) error {
+	run := jobspb.ScheduledSQLExecutionArgs_Run{
+		JobID:    jobID,
+		Status:   string(jobStatus),
+		Finished: env.Now(),
+	}
+	progress, err := loadProgress(ctx, txn, jobID)
+	if err != nil {
+		return err
+	}
+	run.RowsAffected = progress.RowsAffected
+	run.Error = progress.Error
+	if err := recordRun(sj, run); err != nil {
+		return err
+	}
+
+	if jobStatus == jobs.StatusSucceeded {
+		e.metrics.NumSucceeded.Inc(1)
+		sj.SetScheduleStatus(string(jobStatus))
+		return nil
+	}
+
+	e.metrics.NumFailed.Inc(1)
+	log.Warningf(ctx, "SQL job %d scheduled by %d finished with status %s: %s",
+		jobID, sj.ScheduleID(), jobStatus, run.Error)
+	jobs.DefaultHandleFailedRun(sj, "SQL job %d %s: %s", jobID, jobStatus, run.Error)
+	return nil
+}
+
+// loadProgress reads the progress of the specified scheduled SQL job, which
+// holds the outcome of its statements.
+func loadProgress(
+	ctx context.Context, txn isql.Txn, jobID jobspb.JobID,
+) (jobspb.ScheduledSQLProgress, error) {
+	progressBytes, exists, err := jobs.InfoStorageForJob(txn, jobID).GetLegacyProgress(ctx)
+	if err != nil || !exists {
+		return jobspb.ScheduledSQLProgress{}, err
+	}
+	var progress jobspb.Progress
+	if err := protoutil.Unmarshal(progressBytes, &progress); err != nil {
+		return jobspb.ScheduledSQLProgress{}, err
+	}
+	if p, ok := progress.UnwrapDetails().(jobspb.ScheduledSQLProgress); ok {
+		return p, nil
+	}
+	return jobspb.ScheduledSQLProgress{}, nil
+}
+
+// recordRun appends run to the execution history of the schedule, dropping
+// the oldest runs beyond maxRunHistory.
+func recordRun(sj *jobs.ScheduledJob, run jobspb.ScheduledSQLExecutionArgs_Run) error {
+	args, err := unmarshalArgs(sj)
+	if err != nil {
+		return err
+	}
+	args.History = append(args.History, run)
+	if n := len(args.History); n > maxRunHistory {
+		args.History = append(args.History[:0], args.History[n-maxRunHistory:]...)
+	}
+	any, err := pbtypes.MarshalAny(args)
+	if err != nil {
+		return err
+	}
+	sj.SetExecutionDetails(sj.ExecutorType(), jobspb.ExecutionArguments{Args: any})
+	return nil
+}
+
+func unmarshalArgs(sj *jobs.ScheduledJob) (*jobspb.ScheduledSQLExecutionArgs, error) {
+	args := &jobspb.ScheduledSQLExecutionArgs{}
+	if err := pbtypes.UnmarshalAny(sj.ExecutionArgs().Args, args); err != nil {
+		return nil, errors.Wrap(err, "un-marshaling args")
+	}
+	return args, nil
+}
+
+// Metrics implements the jobs.ScheduledJobExecutor interface.
+func (e *scheduledSQLExecutor) Metrics() metric.Struct {
+	return e.metrics
+}
+
+// GetCreateScheduleStatement implements the jobs.ScheduledJobExecutor
+// interface.
+func (e *scheduledSQLExecutor) GetCreateScheduleStatement(
+	ctx context.Context, txn isql.Txn, env scheduledjobs.JobSchedulerEnv, sj *jobs.ScheduledJob,
+) (string, error) {
+	args, err := unmarshalArgs(sj)
+	if err != nil {
+		return "", err
+	}
+	wait, err := schedulebase.ParseOnPreviousRunningOption(sj.ScheduleDetails().Wait)
+	if err != nil {
+		return "", err
+	}
+	onError, err := schedulebase.ParseOnErrorOption(sj.ScheduleDetails().OnError)
+	if err != nil {
+		return "", err
+	}
+
+	node := &tree.ScheduledSQL{
+		ScheduleLabelSpec: tree.LabelSpec{Label: tree.NewStrVal(sj.ScheduleLabel())},
+		Statement:         tree.NewStrVal(args.Statement),
+		Recurrence:        tree.NewStrVal(sj.ScheduleExpr()),
+		ScheduleOptions: tree.KVOptions{
+			{Key: optOnExecFailure, Value: tree.NewDString(onError)},
+			{Key: optOnPreviousRunning, Value: tree.NewDString(wait)},
+		},
+	}
+	return tree.AsString(node), nil
+}
+
+func init() {
+	jobs.RegisterScheduledJobExecutorFactory(
+		tree.ScheduledSQLExecutor.InternalName(),
+		func() (jobs.ScheduledJobExecutor, error) {
+			m := jobs.MakeExecutorMetrics(tree.ScheduledSQLExecutor.UserName())
+			return &scheduledSQLExecutor{metrics: &m}, nil
+		},
+	)
+}
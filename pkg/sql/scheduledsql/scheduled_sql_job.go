@@ -0,0 +1,121 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scheduledsql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+type scheduledSQLResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = (*scheduledSQLResumer)(nil)
+
+// Resume implements the jobs.Resumer interface.
+func (r *scheduledSQLResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	execCfg := execCtx.(sql.JobExecContext).ExecCfg()
+	details := r.job.Details().(jobspb.ScheduledSQLDetails)
+
+	stmts, err := parser.Parse(details.Statement)
+	if err != nil {
+		return jobs.MarkAsPermanentJobError(err)
+	}
+	// The statements run as the owner of the schedule, so that they are
+	// subject to the owner's privileges.
+	override := sessiondata.InternalExecutorOverride{
+		User:     r.job.Payload().UsernameProto.Decode(),
+		Database: details.Database,
+	}
+
+	var rowsAffected int64
+	if err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		rowsAffected = 0
+		for _, stmt := range stmts {
+			n, err := txn.ExecParsed(ctx, "scheduled-sql", txn.KV(), override, stmt)
+			if err != nil {
+				return err
+			}
+			rowsAffected += int64(n)
+		}
+		return nil
+	}); err != nil {
+		return jobs.MarkAsPermanentJobError(err)
+	}
+	log.Infof(ctx, "scheduled SQL job %d affected %d rows", r.job.ID(), rowsAffected)
+
+	return r.notifyJobTermination(ctx, execCfg, jobs.StatusSucceeded,
+		jobspb.ScheduledSQLProgress{RowsAffected: rowsAffected})
+}
+
+// OnFailOrCancel implements the jobs.Resumer interface.
+func (r *scheduledSQLResumer) OnFailOrCancel(
+	ctx context.Context, execCtx interface{}, jobErr error,
+) error {
+	execCfg := execCtx.(sql.JobExecContext).ExecCfg()
+	status := jobs.StatusFailed
+	if jobs.HasErrJobCanceled(jobErr) {
+		status = jobs.StatusCanceled
+	}
+	var progress jobspb.ScheduledSQLProgress
+	if jobErr != nil {
+		progress.Error = jobErr.Error()
+	}
+	return r.notifyJobTermination(ctx, execCfg, status, progress)
+}
+
+// CollectProfile implements the jobs.Resumer interface.
+func (r *scheduledSQLResumer) CollectProfile(_ context.Context, _ interface{}) error {
+	return nil
+}
+
+// notifyJobTermination records the outcome of the job in its progress and
+// notifies the schedule which created the job, if any, so that the run is
+// added to the execution history of the schedule.
+func (r *scheduledSQLResumer) notifyJobTermination(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	status jobs.Status,
+	progress jobspb.ScheduledSQLProgress,
+) error {
+	return execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		if err := r.job.WithTxn(txn).SetProgress(ctx, progress); err != nil {
+			return err
+		}
+		createdBy := r.job.CreatedBy()
+		if createdBy == nil || createdBy.ScheduleID() == jobspb.InvalidScheduleID {
+			return nil
+		}
+		return jobs.NotifyJobTermination(
+			ctx, txn, sql.JobSchedulerEnv(execCfg.JobsKnobs()),
+			r.job.ID(), status, r.job.Details(), createdBy.ScheduleID(),
+		)
+	})
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeScheduledSQL,
+		func(job *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+			return &scheduledSQLResumer{job: job}
+		},
+		jobs.UsesTenantCostControl,
+	)
+}
@@ -0,0 +1,157 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scheduledsql_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobstest"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type testHelper struct {
+	server           serverutils.TestServerInterface
+	sqlDB            *sqlutils.SQLRunner
+	env              *jobstest.JobSchedulerTestEnv
+	executeSchedules func() error
+}
+
+func newTestHelper(t *testing.T) (*testHelper, func()) {
+	h := &testHelper{
+		env: jobstest.NewJobSchedulerTestEnv(
+			jobstest.UseSystemTables, timeutil.Now(), tree.ScheduledSQLExecutor),
+	}
+	knobs := jobs.NewTestingKnobsWithShortIntervals()
+	knobs.JobSchedulerEnv = h.env
+	knobs.TakeOverJobsScheduling = func(fn func(ctx context.Context, maxSchedules int64) error) {
+		h.executeSchedules = func() error {
+			defer h.server.JobRegistry().(*jobs.Registry).TestingNudgeAdoptionQueue()
+			return fn(context.Background(), 0 /* maxSchedules */)
+		}
+	}
+
+	var params base.TestServerArgs
+	params.Knobs.JobsTestingKnobs = knobs
+	srv, db, _ := serverutils.StartServer(t, params)
+	h.server = srv
+	h.sqlDB = sqlutils.MakeSQLRunner(db)
+	return h, func() { srv.Stopper().Stop(context.Background()) }
+}
+
+func (h *testHelper) loadSchedule(t *testing.T, id jobspb.ScheduleID) *jobs.ScheduledJob {
+	schedules := jobs.ScheduledJobDB(h.server.InternalDB().(isql.DB))
+	sj, err := schedules.Load(context.Background(), h.env, id)
+	require.NoError(t, err)
+	return sj
+}
+
+// runSchedule forces the schedule to run, and waits for the job it starts to
+// reach a terminal state.
+func (h *testHelper) runSchedule(t *testing.T, id jobspb.ScheduleID, expected jobs.Status) {
+	h.env.SetTime(h.loadSchedule(t, id).NextRun().Add(time.Second))
+	require.NoError(t, h.executeSchedules())
+	testutils.SucceedsSoon(t, func() error {
+		h.server.JobRegistry().(*jobs.Registry).TestingNudgeAdoptionQueue()
+		var status string
+		h.sqlDB.QueryRow(t, `
+SELECT coalesce(max(status), '') FROM system.jobs
+WHERE created_by_type = $1 AND created_by_id = $2`,
+			jobs.CreatedByScheduledJobs, id).Scan(&status)
+		if status != string(expected) {
+			return errors.Newf("job status %q, expected %q", status, expected)
+		}
+		return nil
+	})
+}
+
+func TestScheduledSQL(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newTestHelper(t)
+	defer cleanup()
+
+	h.sqlDB.Exec(t, `CREATE TABLE events (k INT PRIMARY KEY)`)
+	h.sqlDB.Exec(t, `CREATE TABLE rollup (n INT)`)
+	h.sqlDB.Exec(t, `INSERT INTO events SELECT generate_series(1, 10)`)
+
+	var id jobspb.ScheduleID
+	var stmt string
+	var unused interface{}
+	h.sqlDB.QueryRow(t, `
+CREATE SCHEDULE 'prune' FOR SQL $$DELETE FROM events WHERE k <= 4; INSERT INTO rollup SELECT count(*) FROM events$$
+RECURRING '@hourly' WITH SCHEDULE OPTIONS on_previous_running = 'skip'`,
+	).Scan(&id, &unused, &unused, &unused, &unused, &stmt)
+	require.Equal(t, `DELETE FROM events WHERE k <= 4; INSERT INTO rollup SELECT count(*) FROM events`, stmt)
+
+	sj := h.loadSchedule(t, id)
+	require.Equal(t, jobspb.ScheduleDetails_SKIP, sj.ScheduleDetails().Wait)
+
+	h.runSchedule(t, id, jobs.StatusSucceeded)
+	h.sqlDB.CheckQueryResults(t, `SELECT n FROM rollup`, [][]string{{"6"}})
+	h.sqlDB.CheckQueryResults(t, fmt.Sprintf(`
+SELECT command, jsonb_array_length(history), history->0->>'status', history->0->>'rowsAffected'
+FROM [SHOW SCHEDULES FOR SQL] WHERE id = %d`, id),
+		[][]string{{stmt, "1", "succeeded", "5"}},
+	)
+	require.Equal(t, string(jobs.StatusSucceeded), h.loadSchedule(t, id).ScheduleStatus())
+
+	t.Run("runs as owner", func(t *testing.T) {
+		h.sqlDB.Exec(t, `CREATE USER testuser`)
+		userDB := sqlutils.MakeSQLRunner(
+			h.server.ApplicationLayer().SQLConn(t, serverutils.User("testuser")))
+
+		var userID jobspb.ScheduleID
+		userDB.QueryRow(t,
+			`CREATE SCHEDULE FOR SQL 'DELETE FROM events' RECURRING '@hourly'`,
+		).Scan(&userID, &unused, &unused, &unused, &unused, &unused)
+		h.runSchedule(t, userID, jobs.StatusFailed)
+
+		h.sqlDB.CheckQueryResults(t, `SELECT count(*) FROM events`, [][]string{{"6"}})
+		var runErr string
+		h.sqlDB.QueryRow(t, fmt.Sprintf(
+			`SELECT history->0->>'error' FROM [SHOW SCHEDULES FOR SQL] WHERE id = %d`, userID,
+		)).Scan(&runErr)
+		require.Contains(t, runErr, "user testuser does not have DELETE privilege")
+	})
+
+	t.Run("invalid statements", func(t *testing.T) {
+		h.sqlDB.ExpectErr(t, `invalid scheduled statement`,
+			`CREATE SCHEDULE FOR SQL 'DELETE FROM' RECURRING '@hourly'`)
+		h.sqlDB.ExpectErr(t, `no statements to schedule`,
+			`CREATE SCHEDULE FOR SQL '' RECURRING '@hourly'`)
+		h.sqlDB.ExpectErr(t, `COMMIT is not supported in scheduled SQL`,
+			`CREATE SCHEDULE FOR SQL 'DELETE FROM events; COMMIT' RECURRING '@hourly'`)
+	})
+
+	t.Run("show create", func(t *testing.T) {
+		h.sqlDB.CheckQueryResults(t, fmt.Sprintf(`SELECT create_statement FROM [SHOW CREATE SCHEDULE %d]`, id),
+			[][]string{{fmt.Sprintf(
+				`CREATE SCHEDULE 'prune' FOR SQL '%s' RECURRING '@hourly' WITH SCHEDULE OPTIONS on_execution_failure = 'RESCHEDULE', on_previous_running = 'SKIP'`,
+				stmt)}},
+		)
+	})
+}
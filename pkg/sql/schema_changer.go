@@ -291,6 +291,9 @@ func (sc *SchemaChanger) refreshMaterializedView(
 	if !refresh.ShouldBackfill() {
 		return nil
 	}
+	if refresh.Incremental() {
+		return sc.refreshMaterializedViewIncrementally(ctx, table, refresh)
+	}
 	// The data for the materialized view is stored under the current set of
 	// indexes in table. We want to keep all of that data untouched, and write
 	// out all the data into the new set of indexes denoted by refresh. So, just
@@ -1602,16 +1605,20 @@ func (sc *SchemaChanger) done(ctx context.Context) error {
 						return err
 					}
 				}
-				// If we are mutation is in the ADD state, then start GC jobs for the
-				// existing indexes on the table.
-				if m.Adding() {
+				switch {
+				case refresh.Incremental():
+					// An incremental refresh writes to the existing indexes of the view,
+					// which must not be garbage collected in either direction.
+				case m.Adding():
+					// If we are mutation is in the ADD state, then start GC jobs for the
+					// existing indexes on the table.
 					desc := fmt.Sprintf("REFRESH MATERIALIZED VIEW %q cleanup", scTable.Name)
 					for _, idx := range scTable.ActiveIndexes() {
 						if err := sc.createIndexGCJob(ctx, idx.GetID(), txn, desc); err != nil {
 							return err
 						}
 					}
-				} else if m.Dropped() {
+				case m.Dropped():
 					// Otherwise, the refresh job ran into an error and is being rolled
 					// back. So, we need to GC all of the indexes that were going to be
 					// created, in case any data was written to them.
@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
@@ -30,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
@@ -40,6 +42,23 @@ import (
 func alterTableAddConstraint(
 	b BuildCtx, tn *tree.TableName, tbl *scpb.Table, t *tree.AlterTableAddConstraint,
 ) {
+	// The declarative schema changer elements don't record deferrability, so
+	// DEFERRABLE constraints are added by the legacy schema changer.
+	switch d := t.ConstraintDef.(type) {
+	case *tree.UniqueConstraintTableDef:
+		if d.Deferrable.IsDeferrable() {
+			panic(scerrors.NotImplementedErrorf(t, "DEFERRABLE unique constraint"))
+		}
+	case *tree.ForeignKeyConstraintTableDef:
+		if d.Deferrable.IsDeferrable() {
+			panic(scerrors.NotImplementedErrorf(t, "DEFERRABLE foreign key constraint"))
+		}
+	case *tree.ExclusionConstraintTableDef:
+		if !b.EvalCtx().Settings.Version.IsActive(b, clusterversion.V24_3) {
+			panic(scerrors.NotImplementedErrorf(t, "EXCLUDE constraint"))
+		}
+	}
+
 	switch d := t.ConstraintDef.(type) {
 	case *tree.UniqueConstraintTableDef:
 		if d.PrimaryKey {
@@ -63,6 +82,8 @@ func alterTableAddConstraint(
 		alterTableAddCheck(b, tn, tbl, t)
 	case *tree.ForeignKeyConstraintTableDef:
 		alterTableAddForeignKey(b, tn, tbl, t)
+	case *tree.ExclusionConstraintTableDef:
+		alterTableAddExclusion(b, tn, tbl, t)
 	}
 }
 
@@ -537,6 +558,87 @@ func alterTableAddUniqueWithoutIndex(
 	})
 }
 
+// alterTableAddExclusion contains logic for building
+// `ALTER TABLE ... ADD CONSTRAINT ... EXCLUDE`.
+// The constraint is backed by a secondary index which shares its name. The
+// existing rows are validated against each other before the constraint
+// becomes public.
+func alterTableAddExclusion(
+	b BuildCtx, tn *tree.TableName, tbl *scpb.Table, t *tree.AlterTableAddConstraint,
+) {
+	d := t.ConstraintDef.(*tree.ExclusionConstraintTableDef)
+	if t.ValidationBehavior == tree.ValidationSkip {
+		panic(sqlerrors.NewUnsupportedUnvalidatedConstraintError(catconstants.ConstraintTypeExclusion))
+	}
+
+	// 1. Resolve the columns and check that their operators can be used.
+	elements := make([]scpb.ExclusionConstraint_Element, len(d.Columns))
+	colNames := make([]string, len(d.Columns))
+	for i, col := range d.Columns {
+		colID := getColumnIDFromColumnName(b, tbl.TableID, col.Column, true /* required */)
+		colType := mustRetrieveColumnTypeElem(b, tbl.TableID, colID).Type
+		if err := schemaexpr.ValidateExclusionConstraintOperator(d.Operators[i], colType); err != nil {
+			panic(err)
+		}
+		elements[i] = scpb.ExclusionConstraint_Element{
+			ColumnID: colID,
+			Operator: treecmp.ComparisonOpName(d.Operators[i].Symbol),
+		}
+		colNames[i] = string(col.Column)
+	}
+
+	// 2. If a name is provided, check that this name is not used; Otherwise,
+	// generate a unique name for it.
+	nameInUse := func(name string) bool {
+		return constraintNameInUse(b, tbl.TableID, name) ||
+			getIndexIDFromIndexName(b, tbl.TableID, name) != 0
+	}
+	if d.Name == "" {
+		d.Name = tree.Name(tabledesc.GenerateUniqueName(
+			fmt.Sprintf("%s_%s_excl", tn.Object(), strings.Join(colNames, "_")),
+			nameInUse,
+		))
+	} else if nameInUse(string(d.Name)) {
+		if d.IfNotExists {
+			return
+		}
+		panic(pgerror.Newf(pgcode.DuplicateObject,
+			"duplicate constraint name: %q", d.Name))
+	}
+
+	// 3. Create the backing index, and the constraint on top of it.
+	CreateIndex(b, &tree.CreateIndex{
+		Name:             d.Name,
+		Table:            *tn,
+		Inverted:         d.Inverted,
+		Columns:          d.Columns,
+		Sharded:          d.Sharded,
+		Storing:          d.Storing,
+		PartitionByIndex: d.PartitionByIndex,
+		StorageParams:    d.StorageParams,
+		Predicate:        d.Predicate,
+		Invisibility:     d.Invisibility,
+	})
+	b.Add(&scpb.ExclusionConstraint{
+		TableID:  tbl.TableID,
+		IndexID:  getIndexIDFromIndexName(b, tbl.TableID, string(d.Name)),
+		Elements: elements,
+	})
+}
+
+// getIndexIDFromIndexName returns the ID of the non-dropping index named
+// `name`, or 0 if there is no such index.
+func getIndexIDFromIndexName(b BuildCtx, tableID catid.DescID, name string) (ret catid.IndexID) {
+	scpb.ForEachIndexName(b.QueryByID(tableID).Filter(publicTargetFilter), func(
+		_ scpb.Status, _ scpb.TargetStatus, e *scpb.IndexName,
+	) {
+		if e.Name == name {
+			ret = e.IndexID
+		}
+	})
+	return ret
+}
+
 // getFullyResolvedColNames returns fully resolved column names for `colNames`.
 // For each column name in `colNames`, its fully resolved name will be "db.sc.tbl.col".
 // The order of column names in the return is in syc with that in the input `colNames`.
@@ -70,6 +70,7 @@ func alterPrimaryKey(b BuildCtx, tn *tree.TableName, tbl *scpb.Table, t alterPri
 	// be removed to fully support `ALTER PRIMARY KEY`.
 	fallBackIfShardedIndexExists(b, t, tbl.TableID)
 	fallBackIfPartitionedIndexExists(b, t, tbl.TableID)
+	fallBackIfExclusionConstraintExists(b, t, tbl.TableID)
 	fallBackIfRegionalByRowTable(b, t.n, tbl.TableID)
 	fallBackIfSubZoneConfigExists(b, t.n, tbl.TableID)
 
@@ -407,6 +408,17 @@ func fallBackIfPartitionedIndexExists(b BuildCtx, t alterPrimaryKeySpec, tableID
 	})
 }
 
+// fallBackIfExclusionConstraintExists panics with an unimplemented
+// error if there exists exclusion constraints on the table, since they
+// would have to be moved to the recreated secondary indexes.
+func fallBackIfExclusionConstraintExists(b BuildCtx, t alterPrimaryKeySpec, tableID catid.DescID) {
+	tableElts := b.QueryByID(tableID).Filter(notFilter(absentTargetFilter))
+	scpb.ForEachExclusionConstraint(tableElts, func(_ scpb.Status, _ scpb.TargetStatus, _ *scpb.ExclusionConstraint) {
+		panic(scerrors.NotImplementedErrorf(t.n,
+			"ALTER PRIMARY KEY on a table with exclusion constraints is not yet supported"))
+	})
+}
+
 // fallBackIfShardedIndexExists panics with an unimplemented
 // error if there exists sharded indexes on the table.
 func fallBackIfShardedIndexExists(b BuildCtx, t alterPrimaryKeySpec, tableID catid.DescID) {
@@ -25,6 +25,16 @@ import (
 func alterTableDropConstraint(
 	b BuildCtx, tn *tree.TableName, tbl *scpb.Table, t *tree.AlterTableDropConstraint,
 ) {
+	// An exclusion constraint is dropped along with its backing index.
+	if sie := retrieveExclusionConstraintIndex(b, tbl.TableID, string(t.Constraint)); sie != nil {
+		dropSecondaryIndex(b, &tree.TableIndexName{
+			Table: *tn,
+			Index: tree.UnrestrictedName(t.Constraint),
+		}, t.DropBehavior, sie)
+		b.LogEventForExistingTarget(sie)
+		return
+	}
+
 	constraintElems := b.ResolveConstraint(tbl.TableID, t.Constraint, ResolveParams{
 		IsExistenceOptional: t.IfExists,
 		RequiredPrivilege:   privilege.CREATE,
@@ -75,6 +85,23 @@ func maybeDropAdditionallyForUniqueWithoutIndexConstraint(
 		})
 }
 
+// retrieveExclusionConstraintIndex returns the secondary index backing the
+// exclusion constraint named `name`, or nil if there is no such constraint.
+func retrieveExclusionConstraintIndex(
+	b BuildCtx, tableID catid.DescID, name string,
+) (ret *scpb.SecondaryIndex) {
+	indexID := getIndexIDFromIndexName(b, tableID, name)
+	if indexID == 0 {
+		return nil
+	}
+	indexElts := b.QueryByID(tableID).Filter(hasIndexIDAttrFilter(indexID)).Filter(publicTargetFilter)
+	if _, _, ec := scpb.FindExclusionConstraint(indexElts); ec == nil {
+		return nil
+	}
+	_, _, ret = scpb.FindSecondaryIndex(indexElts)
+	return ret
+}
+
 func fallBackIfDroppingPrimaryKey(
 	constraintElems ElementResultSet, t *tree.AlterTableDropConstraint,
 ) {
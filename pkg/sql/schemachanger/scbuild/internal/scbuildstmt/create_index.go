@@ -46,6 +46,9 @@ import (
 
 // CreateIndex implements CREATE INDEX.
 func CreateIndex(b BuildCtx, n *tree.CreateIndex) {
+	if n.Vector {
+		panic(scerrors.NotImplementedErrorf(n, "vector indexes are not supported"))
+	}
 	b.IncrementSchemaChangeCreateCounter("index")
 	// Resolve the table name and start building the new index element.
 	relationElements := b.ResolveRelation(n.Table.ToUnresolvedObjectName(), ResolveParams{
@@ -367,7 +370,17 @@ func processColNodeType(
 			}
 			invertedKind = catpb.InvertedIndexColumnKind_TRIGRAM
 			b.IncrementSchemaChangeIndexCounter("trigram_inverted")
-
+		case types.RangeFamily:
+			switch columnNode.OpClass {
+			case "range_ops", "":
+			default:
+				panic(newUndefinedOpclassError(columnNode.OpClass))
+			}
+			b.IncrementSchemaChangeIndexCounter("range_inverted")
+		case types.PGVectorFamily:
+			// Vectors can only be indexed by vector indexes, which the legacy
+			// schema changer creates.
+			panic(scerrors.NotImplementedErrorf(n, "inverted index on a vector column"))
 		}
 		relationElts := b.QueryByID(indexSpec.secondary.TableID)
 		scpb.ForEachIndexColumn(relationElts, func(current scpb.Status, target scpb.TargetStatus, e *scpb.IndexColumn) {
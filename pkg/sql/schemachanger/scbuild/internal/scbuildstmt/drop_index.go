@@ -109,6 +109,17 @@ func maybeDropIndex(
 			"use CASCADE if you really want to drop it.",
 		))
 	}
+	// Likewise if an exclusion constraint is backed by it.
+	if dropBehavior != tree.DropCascade {
+		indexElts := b.QueryByID(sie.TableID).Filter(hasIndexIDAttrFilter(sie.IndexID)).Filter(publicTargetFilter)
+		if _, _, ec := scpb.FindExclusionConstraint(indexElts); ec != nil {
+			panic(errors.WithHint(
+				pgerror.Newf(pgcode.DependentObjectsStillExist,
+					"index %q is in use as exclusion constraint", indexName.Index.String()),
+				"use CASCADE if you really want to drop it.",
+			))
+		}
+	}
 	panicIfSchemaIsLocked(b.QueryByID(sie.TableID))
 	dropSecondaryIndex(b, indexName, dropBehavior, sie)
 	return sie
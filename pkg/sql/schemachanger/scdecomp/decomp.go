@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
@@ -219,6 +220,11 @@ func (w *walkCtx) walkType(typ catalog.TypeDescriptor) {
 				Name:            comp.GetElementLabel(i),
 			})
 		}
+	} else if typ.AsDomainTypeDescriptor() != nil {
+		// Domains are not yet modeled as elements, so schema changes involving
+		// them are handled by the legacy schema changer.
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"type %q is a domain", typ.GetName()))
 	} else {
 		panic(errors.AssertionFailedf("unsupported type kind %q", typ.GetKind()))
 	}
@@ -287,6 +293,18 @@ func GetSequenceOptions(
 }
 
 func (w *walkCtx) walkRelation(tbl catalog.TableDescriptor) {
+	// Triggers are not yet modeled as elements, so schema changes on tables
+	// with triggers are handled by the legacy schema changer.
+	if len(tbl.GetTriggers()) > 0 {
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"table %q has triggers", tbl.GetName()))
+	}
+	// EXCLUDE constraints are only modeled as elements once the cluster has
+	// been upgraded.
+	if len(tbl.GetExclusionConstraints()) > 0 && !w.clusterVersion.IsActive(clusterversion.V24_3) {
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"table %q has exclusion constraints", tbl.GetName()))
+	}
 	switch {
 	case tbl.IsSequence():
 		w.ev(descriptorStatus(tbl), &scpb.Sequence{
@@ -390,6 +408,9 @@ func (w *walkCtx) walkRelation(tbl catalog.TableDescriptor) {
 		for _, idx := range tbl.AllIndexes() {
 			w.walkIndex(tbl, idx)
 		}
+		for _, ec := range tbl.GetExclusionConstraints() {
+			w.walkExclusionConstraint(tbl, ec)
+		}
 		if ttl := tbl.GetRowLevelTTL(); ttl != nil {
 			// We pull out the TTL expression so that we can build proper column
 			// dependencies with whatever column is used.
@@ -619,6 +640,9 @@ func (w *walkCtx) walkIndex(tbl catalog.TableDescriptor, idx catalog.Index) {
 		if geoConfig := idx.GetGeoConfig(); !geoConfig.IsEmpty() {
 			index.GeoConfig = protoutil.Clone(&geoConfig).(*geopb.Config)
 		}
+		if vecConfig := idx.GetVecConfig(); !vecConfig.IsEmpty() {
+			index.VecConfig = protoutil.Clone(&vecConfig).(*catpb.VectorIndexConfig)
+		}
 		for i, c := range cpy.KeyColumnIDs {
 			invertedKind := catpb.InvertedIndexColumnKind_DEFAULT
 			if index.IsInverted && c == idx.InvertedColumnID() {
@@ -709,6 +733,30 @@ func (w *walkCtx) walkIndex(tbl catalog.TableDescriptor, idx catalog.Index) {
 	})
 }
 
+func (w *walkCtx) walkExclusionConstraint(
+	tbl catalog.TableDescriptor, ec descpb.ExclusionConstraint,
+) {
+	elements := make([]scpb.ExclusionConstraint_Element, len(ec.Elements))
+	for i := range ec.Elements {
+		elements[i] = scpb.ExclusionConstraint_Element{
+			ColumnID: ec.Elements[i].ColumnID,
+			Operator: ec.Elements[i].Operator,
+		}
+	}
+	status := scpb.Status_PUBLIC
+	switch ec.Validity {
+	case descpb.ConstraintValidity_Validating:
+		status = scpb.Status_WRITE_ONLY
+	case descpb.ConstraintValidity_Dropping:
+		status = scpb.Status_VALIDATED
+	}
+	w.ev(status, &scpb.ExclusionConstraint{
+		TableID:  tbl.GetID(),
+		IndexID:  ec.IndexID,
+		Elements: elements,
+	})
+}
+
 func (w *walkCtx) walkUniqueWithoutIndexConstraint(
 	tbl catalog.TableDescriptor, c catalog.UniqueWithoutIndexConstraint,
 ) {
@@ -829,6 +877,12 @@ func (w *walkCtx) walkForeignKeyConstraint(
 }
 
 func (w *walkCtx) walkFunction(fnDesc catalog.FunctionDescriptor) {
+	if fnDesc.IsAggregate() {
+		// Aggregates are not yet modeled as elements, so schema changes involving
+		// them are handled by the legacy schema changer.
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"function %q is an aggregate", fnDesc.GetName()))
+	}
 	typeT := newTypeT(fnDesc.GetReturnType().Type)
 	fn := &scpb.Function{
 		FunctionID: fnDesc.GetID(),
@@ -1174,6 +1174,18 @@ func (s *TestState) ValidateConstraint(
 	return nil
 }
 
+// ValidateExclusionConstraint implements the validator interface.
+func (s *TestState) ValidateExclusionConstraint(
+	ctx context.Context,
+	tbl catalog.TableDescriptor,
+	indexID descpb.IndexID,
+	override sessiondata.InternalExecutorOverride,
+) error {
+	s.LogSideEffectf("validate exclusion constraint on index %d in table #%d",
+		indexID, tbl.GetID())
+	return nil
+}
+
 func (s *TestState) ValidateForeignKeyConstraint(
 	ctx context.Context,
 	out catalog.TableDescriptor,
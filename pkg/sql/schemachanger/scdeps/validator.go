@@ -64,20 +64,32 @@ type ValidateConstraintFn func(
 	execOverride sessiondata.InternalExecutorOverride,
 ) error
 
+// ValidateExclusionConstraintFn callback function for validating exclusion
+// constraints.
+type ValidateExclusionConstraintFn func(
+	ctx context.Context,
+	tbl catalog.TableDescriptor,
+	indexID descpb.IndexID,
+	sessionData *sessiondata.SessionData,
+	runHistoricalTxn descs.HistoricalInternalExecTxnRunner,
+	execOverride sessiondata.InternalExecutorOverride,
+) error
+
 // NewFakeSessionDataFn callback function used to create session data
 // for the internal executor.
 type NewFakeSessionDataFn func(ctx context.Context, settings *cluster.Settings, opName string) *sessiondata.SessionData
 
 type validator struct {
-	db                         *kv.DB
-	codec                      keys.SQLCodec
-	settings                   *cluster.Settings
-	ieFactory                  isql.DB
-	validateForwardIndexes     ValidateForwardIndexesFn
-	validateInvertedIndexes    ValidateInvertedIndexesFn
-	validateConstraint         ValidateConstraintFn
-	newFakeSessionData         NewFakeSessionDataFn
-	protectedTimestampProvider scexec.ProtectedTimestampManager
+	db                          *kv.DB
+	codec                       keys.SQLCodec
+	settings                    *cluster.Settings
+	ieFactory                   isql.DB
+	validateForwardIndexes      ValidateForwardIndexesFn
+	validateInvertedIndexes     ValidateInvertedIndexesFn
+	validateConstraint          ValidateConstraintFn
+	validateExclusionConstraint ValidateExclusionConstraintFn
+	newFakeSessionData          NewFakeSessionDataFn
+	protectedTimestampProvider  scexec.ProtectedTimestampManager
 }
 
 // ValidateForwardIndexes checks that the indexes have entries for all the rows.
@@ -124,6 +136,19 @@ func (vd validator) ValidateConstraint(
 		vd.makeHistoricalInternalExecTxnRunner(), override)
 }
 
+// ValidateExclusionConstraint checks that no two rows of the table conflict
+// with each other according to the exclusion constraint backed by the index.
+func (vd validator) ValidateExclusionConstraint(
+	ctx context.Context,
+	tbl catalog.TableDescriptor,
+	indexID descpb.IndexID,
+	override sessiondata.InternalExecutorOverride,
+) error {
+	return vd.validateExclusionConstraint(ctx, tbl, indexID,
+		vd.newFakeSessionData(ctx, vd.settings, "validate-exclusion-constraint"),
+		vd.makeHistoricalInternalExecTxnRunner(), override)
+}
+
 // makeHistoricalInternalExecTxnRunner creates a new transaction runner which
 // always runs at the same time and that time is the current time as of when
 // this constructor was called.
@@ -152,17 +177,19 @@ func NewValidator(
 	validateForwardIndexes ValidateForwardIndexesFn,
 	validateInvertedIndexes ValidateInvertedIndexesFn,
 	validateCheckConstraint ValidateConstraintFn,
+	validateExclusionConstraint ValidateExclusionConstraintFn,
 	newFakeSessionData NewFakeSessionDataFn,
 ) scexec.Validator {
 	return validator{
-		db:                         db,
-		codec:                      codec,
-		settings:                   settings,
-		ieFactory:                  ieFactory,
-		validateForwardIndexes:     validateForwardIndexes,
-		validateInvertedIndexes:    validateInvertedIndexes,
-		validateConstraint:         validateCheckConstraint,
-		newFakeSessionData:         newFakeSessionData,
-		protectedTimestampProvider: protectedTimestampProvider,
+		db:                          db,
+		codec:                       codec,
+		settings:                    settings,
+		ieFactory:                   ieFactory,
+		validateForwardIndexes:      validateForwardIndexes,
+		validateInvertedIndexes:     validateInvertedIndexes,
+		validateConstraint:          validateCheckConstraint,
+		validateExclusionConstraint: validateExclusionConstraint,
+		newFakeSessionData:          newFakeSessionData,
+		protectedTimestampProvider:  protectedTimestampProvider,
 	}
 }
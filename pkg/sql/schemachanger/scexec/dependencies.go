@@ -218,6 +218,13 @@ type Validator interface {
 		indexIDForValidation descpb.IndexID,
 		override sessiondata.InternalExecutorOverride,
 	) error
+
+	ValidateExclusionConstraint(
+		ctx context.Context,
+		tbl catalog.TableDescriptor,
+		indexID descpb.IndexID,
+		override sessiondata.InternalExecutorOverride,
+	) error
 }
 
 // IndexSpanSplitter can try to split an index span in the current transaction
@@ -105,6 +105,28 @@ func executeValidateColumnNotNull(
 	return nil
 }
 
+func executeValidateExclusionConstraint(
+	ctx context.Context, deps Dependencies, op *scop.ValidateExclusionConstraint,
+) error {
+	descs, err := deps.Catalog().MustReadImmutableDescriptors(ctx, op.TableID)
+	if err != nil {
+		return err
+	}
+	desc := descs[0]
+	table, err := catalog.AsTableDescriptor(desc)
+	if err != nil {
+		return err
+	}
+
+	// Execute the validation operation as a node user.
+	execOverride := sessiondata.NodeUserSessionDataOverride
+	err = deps.Validator().ValidateExclusionConstraint(ctx, table, op.IndexID, execOverride)
+	if err != nil {
+		return scerrors.SchemaChangerUserError(err)
+	}
+	return nil
+}
+
 func executeValidationOps(ctx context.Context, deps Dependencies, ops []scop.Op) (err error) {
 	for _, op := range ops {
 		if err = executeValidationOp(ctx, deps, op); err != nil {
@@ -137,6 +159,13 @@ func executeValidationOp(ctx context.Context, deps Dependencies, op scop.Op) (er
 			}
 			return err
 		}
+	case *scop.ValidateExclusionConstraint:
+		if err = executeValidateExclusionConstraint(ctx, deps, op); err != nil {
+			if !scerrors.HasSchemaChangerUserError(err) {
+				return errors.Wrapf(err, "%T: %v", op, op)
+			}
+			return err
+		}
 
 	default:
 		panic("unimplemented")
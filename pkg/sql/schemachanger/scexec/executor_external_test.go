@@ -505,6 +505,15 @@ func (noopValidator) ValidateConstraint(
 	return nil
 }
 
+func (noopValidator) ValidateExclusionConstraint(
+	ctx context.Context,
+	tbl catalog.TableDescriptor,
+	indexID descpb.IndexID,
+	override sessiondata.InternalExecutorOverride,
+) error {
+	return nil
+}
+
 type noopStatsReferesher struct{}
 
 var _ scexec.StatsRefresher = noopStatsReferesher{}
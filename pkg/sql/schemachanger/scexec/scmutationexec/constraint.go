@@ -601,3 +601,73 @@ func (i *immediateVisitor) MakePublicUniqueWithoutIndexConstraintValidated(
 
 	return errors.AssertionFailedf("failed to find unique_without_index constraint %d in descriptor %v", op.ConstraintID, tbl)
 }
+
+func (i *immediateVisitor) AddExclusionConstraint(
+	ctx context.Context, op scop.AddExclusionConstraint,
+) error {
+	tbl, err := i.checkOutTable(ctx, op.TableID)
+	if err != nil || tbl.Dropped() {
+		return err
+	}
+	// Exclusion constraints are not tracked as mutations: writes are checked
+	// against the constraint as soon as it is added, and its validity records
+	// whether the existing rows have been validated.
+	tbl.ExclusionConstraints = append(tbl.ExclusionConstraints, descpb.ExclusionConstraint{
+		IndexID:  op.IndexID,
+		Elements: op.Elements,
+		Validity: op.Validity,
+	})
+	return nil
+}
+
+func (i *immediateVisitor) MakeValidatedExclusionConstraintPublic(
+	ctx context.Context, op scop.MakeValidatedExclusionConstraintPublic,
+) error {
+	return i.setExclusionConstraintValidity(ctx, op.TableID, op.IndexID, descpb.ConstraintValidity_Validated)
+}
+
+func (i *immediateVisitor) MakePublicExclusionConstraintValidated(
+	ctx context.Context, op scop.MakePublicExclusionConstraintValidated,
+) error {
+	return i.setExclusionConstraintValidity(ctx, op.TableID, op.IndexID, descpb.ConstraintValidity_Dropping)
+}
+
+func (i *immediateVisitor) setExclusionConstraintValidity(
+	ctx context.Context,
+	tableID descpb.ID,
+	indexID descpb.IndexID,
+	validity descpb.ConstraintValidity,
+) error {
+	tbl, err := i.checkOutTable(ctx, tableID)
+	if err != nil || tbl.Dropped() {
+		return err
+	}
+	for i := range tbl.ExclusionConstraints {
+		if tbl.ExclusionConstraints[i].IndexID == indexID {
+			tbl.ExclusionConstraints[i].Validity = validity
+			return nil
+		}
+	}
+	return errors.AssertionFailedf("failed to find exclusion constraint on index %d in table %q (%d)",
+		indexID, tbl.GetName(), tbl.GetID())
+}
+
+func (i *immediateVisitor) RemoveExclusionConstraint(
+	ctx context.Context, op scop.RemoveExclusionConstraint,
+) error {
+	tbl, err := i.checkOutTable(ctx, op.TableID)
+	if err != nil || tbl.Dropped() {
+		return err
+	}
+	for i, ec := range tbl.ExclusionConstraints {
+		if ec.IndexID == op.IndexID {
+			tbl.ExclusionConstraints = append(tbl.ExclusionConstraints[:i], tbl.ExclusionConstraints[i+1:]...)
+			if len(tbl.ExclusionConstraints) == 0 {
+				tbl.ExclusionConstraints = nil
+			}
+			return nil
+		}
+	}
+	return errors.AssertionFailedf("failed to find exclusion constraint on index %d in table %q (%d)",
+		op.IndexID, tbl.GetName(), tbl.GetID())
+}
@@ -637,6 +637,9 @@ func (i *immediateVisitor) SetObjectParentID(ctx context.Context, op scop.SetObj
 			if tree.IsInParamClass(class) {
 				ol.ArgTypes = append(ol.ArgTypes, p.Type)
 			}
+			if class == tree.RoutineParamVariadic {
+				ol.IsVariadic = true
+			}
 			if class == tree.RoutineParamOut {
 				ol.OutParamOrdinals = append(ol.OutParamOrdinals, int32(pIdx))
 				ol.OutParamTypes = append(ol.OutParamTypes, p.Type)
@@ -466,6 +466,39 @@ type RemoveUniqueWithoutIndexConstraint struct {
 	ConstraintID descpb.ConstraintID
 }
 
+// AddExclusionConstraint adds a non-existent exclusion constraint, backed by
+// an existing index, to the table.
+type AddExclusionConstraint struct {
+	immediateMutationOp
+	TableID  descpb.ID
+	IndexID  descpb.IndexID
+	Elements []descpb.ExclusionConstraint_Element
+	Validity descpb.ConstraintValidity
+}
+
+// MakeValidatedExclusionConstraintPublic marks a new, validated exclusion
+// constraint as public.
+type MakeValidatedExclusionConstraintPublic struct {
+	immediateMutationOp
+	TableID descpb.ID
+	IndexID descpb.IndexID
+}
+
+// MakePublicExclusionConstraintValidated moves a public exclusion constraint
+// to VALIDATED.
+type MakePublicExclusionConstraintValidated struct {
+	immediateMutationOp
+	TableID descpb.ID
+	IndexID descpb.IndexID
+}
+
+// RemoveExclusionConstraint removes an exclusion constraint from the table.
+type RemoveExclusionConstraint struct {
+	immediateMutationOp
+	TableID descpb.ID
+	IndexID descpb.IndexID
+}
+
 // RemoveSchemaParent removes the schema - parent database relationship.
 type RemoveSchemaParent struct {
 	immediateMutationOp
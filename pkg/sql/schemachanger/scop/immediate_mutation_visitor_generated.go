@@ -77,6 +77,10 @@ type ImmediateMutationVisitor interface {
 	MakeValidatedUniqueWithoutIndexConstraintPublic(context.Context, MakeValidatedUniqueWithoutIndexConstraintPublic) error
 	MakePublicUniqueWithoutIndexConstraintValidated(context.Context, MakePublicUniqueWithoutIndexConstraintValidated) error
 	RemoveUniqueWithoutIndexConstraint(context.Context, RemoveUniqueWithoutIndexConstraint) error
+	AddExclusionConstraint(context.Context, AddExclusionConstraint) error
+	MakeValidatedExclusionConstraintPublic(context.Context, MakeValidatedExclusionConstraintPublic) error
+	MakePublicExclusionConstraintValidated(context.Context, MakePublicExclusionConstraintValidated) error
+	RemoveExclusionConstraint(context.Context, RemoveExclusionConstraint) error
 	RemoveSchemaParent(context.Context, RemoveSchemaParent) error
 	AddSchemaParent(context.Context, AddSchemaParent) error
 	AddIndexPartitionInfo(context.Context, AddIndexPartitionInfo) error
@@ -415,6 +419,26 @@ func (op RemoveUniqueWithoutIndexConstraint) Visit(ctx context.Context, v Immedi
 	return v.RemoveUniqueWithoutIndexConstraint(ctx, op)
 }
 
+// Visit is part of the ImmediateMutationOp interface.
+func (op AddExclusionConstraint) Visit(ctx context.Context, v ImmediateMutationVisitor) error {
+	return v.AddExclusionConstraint(ctx, op)
+}
+
+// Visit is part of the ImmediateMutationOp interface.
+func (op MakeValidatedExclusionConstraintPublic) Visit(ctx context.Context, v ImmediateMutationVisitor) error {
+	return v.MakeValidatedExclusionConstraintPublic(ctx, op)
+}
+
+// Visit is part of the ImmediateMutationOp interface.
+func (op MakePublicExclusionConstraintValidated) Visit(ctx context.Context, v ImmediateMutationVisitor) error {
+	return v.MakePublicExclusionConstraintValidated(ctx, op)
+}
+
+// Visit is part of the ImmediateMutationOp interface.
+func (op RemoveExclusionConstraint) Visit(ctx context.Context, v ImmediateMutationVisitor) error {
+	return v.RemoveExclusionConstraint(ctx, op)
+}
+
 // Visit is part of the ImmediateMutationOp interface.
 func (op RemoveSchemaParent) Visit(ctx context.Context, v ImmediateMutationVisitor) error {
 	return v.RemoveSchemaParent(ctx, op)
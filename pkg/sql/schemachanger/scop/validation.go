@@ -44,5 +44,13 @@ type ValidateColumnNotNull struct {
 	IndexIDForValidation descpb.IndexID
 }
 
+// ValidateExclusionConstraint validates an exclusion constraint on a table by
+// checking that no two rows of the table conflict with each other.
+type ValidateExclusionConstraint struct {
+	validationOp
+	TableID descpb.ID
+	IndexID descpb.IndexID
+}
+
 // Make sure baseOp is used for linter.
 var _ = validationOp{baseOp: baseOp{}}
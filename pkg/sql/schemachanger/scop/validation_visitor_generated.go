@@ -25,6 +25,7 @@ type ValidationVisitor interface {
 	ValidateIndex(context.Context, ValidateIndex) error
 	ValidateConstraint(context.Context, ValidateConstraint) error
 	ValidateColumnNotNull(context.Context, ValidateColumnNotNull) error
+	ValidateExclusionConstraint(context.Context, ValidateExclusionConstraint) error
 }
 
 // Visit is part of the ValidationOp interface.
@@ -41,3 +42,8 @@ func (op ValidateConstraint) Visit(ctx context.Context, v ValidationVisitor) err
 func (op ValidateColumnNotNull) Visit(ctx context.Context, v ValidationVisitor) error {
 	return v.ValidateColumnNotNull(ctx, op)
 }
+
+// Visit is part of the ValidationOp interface.
+func (op ValidateExclusionConstraint) Visit(ctx context.Context, v ValidationVisitor) error {
+	return v.ValidateExclusionConstraint(ctx, op)
+}
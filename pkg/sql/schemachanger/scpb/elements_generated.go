@@ -940,6 +940,43 @@ func (c *ElementCollection[E]) FilterEnumTypeValue() *ElementCollection[*EnumTyp
 	return (*ElementCollection[*EnumTypeValue])(ret)
 }
 
+func (e ExclusionConstraint) element() {}
+
+// Element implements ElementGetter.
+func (e * ElementProto_ExclusionConstraint) Element() Element {
+	return e.ExclusionConstraint
+}
+
+// ForEachExclusionConstraint iterates over elements of type ExclusionConstraint.
+// Deprecated
+func ForEachExclusionConstraint(
+	c *ElementCollection[Element], fn func(current Status, target TargetStatus, e *ExclusionConstraint),
+) {
+  c.FilterExclusionConstraint().ForEach(fn)
+}
+
+// FindExclusionConstraint finds the first element of type ExclusionConstraint.
+// Deprecated
+func FindExclusionConstraint(
+	c *ElementCollection[Element],
+) (current Status, target TargetStatus, element *ExclusionConstraint) {
+	if tc := c.FilterExclusionConstraint(); !tc.IsEmpty() {
+		var e Element
+		current, target, e = tc.Get(0)
+		element = e.(*ExclusionConstraint)
+	}
+	return current, target, element
+}
+
+// ExclusionConstraintElements filters elements of type ExclusionConstraint.
+func (c *ElementCollection[E]) FilterExclusionConstraint() *ElementCollection[*ExclusionConstraint] {
+	ret := c.genericFilter(func(_ Status, _ TargetStatus, e Element) bool {
+		_, ok := e.(*ExclusionConstraint)
+		return ok
+	})
+	return (*ElementCollection[*ExclusionConstraint])(ret)
+}
+
 func (e ForeignKeyConstraint) element() {}
 
 // Element implements ElementGetter.
@@ -2586,6 +2623,8 @@ func (e* ElementProto) SetElement(element Element) {
 			e.ElementOneOf = &ElementProto_EnumType{ EnumType: t}
 		case *EnumTypeValue:
 			e.ElementOneOf = &ElementProto_EnumTypeValue{ EnumTypeValue: t}
+		case *ExclusionConstraint:
+			e.ElementOneOf = &ElementProto_ExclusionConstraint{ ExclusionConstraint: t}
 		case *ForeignKeyConstraint:
 			e.ElementOneOf = &ElementProto_ForeignKeyConstraint{ ForeignKeyConstraint: t}
 		case *ForeignKeyConstraintUnvalidated:
@@ -2703,6 +2742,7 @@ func GetElementOneOfProtos() []interface{} {
 	((*ElementProto_DatabaseZoneConfig)(nil)),
 	((*ElementProto_EnumType)(nil)),
 	((*ElementProto_EnumTypeValue)(nil)),
+	((*ElementProto_ExclusionConstraint)(nil)),
 	((*ElementProto_ForeignKeyConstraint)(nil)),
 	((*ElementProto_ForeignKeyConstraintUnvalidated)(nil)),
 	((*ElementProto_Function)(nil)),
@@ -2778,6 +2818,7 @@ func GetElementTypes() []interface{} {
 	((*DatabaseZoneConfig)(nil)),
 	((*EnumType)(nil)),
 	((*EnumTypeValue)(nil)),
+	((*ExclusionConstraint)(nil)),
 	((*ForeignKeyConstraint)(nil)),
 	((*ForeignKeyConstraintUnvalidated)(nil)),
 	((*Function)(nil)),
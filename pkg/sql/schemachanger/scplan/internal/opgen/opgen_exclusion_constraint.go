@@ -0,0 +1,81 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package opgen
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+)
+
+func init() {
+	opRegistry.register((*scpb.ExclusionConstraint)(nil),
+		toPublic(
+			scpb.Status_ABSENT,
+			to(scpb.Status_WRITE_ONLY,
+				emit(func(this *scpb.ExclusionConstraint) *scop.AddExclusionConstraint {
+					elements := make([]descpb.ExclusionConstraint_Element, len(this.Elements))
+					for i := range this.Elements {
+						elements[i] = descpb.ExclusionConstraint_Element{
+							ColumnID: this.Elements[i].ColumnID,
+							Operator: this.Elements[i].Operator,
+						}
+					}
+					return &scop.AddExclusionConstraint{
+						TableID:  this.TableID,
+						IndexID:  this.IndexID,
+						Elements: elements,
+						Validity: descpb.ConstraintValidity_Validating,
+					}
+				}),
+			),
+			to(scpb.Status_VALIDATED,
+				emit(func(this *scpb.ExclusionConstraint, md *opGenContext) *scop.ValidateExclusionConstraint {
+					if checkIfDescriptorIsWithoutData(this.TableID, md) {
+						return nil
+					}
+					return &scop.ValidateExclusionConstraint{
+						TableID: this.TableID,
+						IndexID: this.IndexID,
+					}
+				}),
+			),
+			to(scpb.Status_PUBLIC,
+				emit(func(this *scpb.ExclusionConstraint) *scop.MakeValidatedExclusionConstraintPublic {
+					return &scop.MakeValidatedExclusionConstraintPublic{
+						TableID: this.TableID,
+						IndexID: this.IndexID,
+					}
+				}),
+			),
+		),
+		toAbsent(
+			scpb.Status_PUBLIC,
+			to(scpb.Status_VALIDATED,
+				emit(func(this *scpb.ExclusionConstraint) *scop.MakePublicExclusionConstraintValidated {
+					return &scop.MakePublicExclusionConstraintValidated{
+						TableID: this.TableID,
+						IndexID: this.IndexID,
+					}
+				}),
+			),
+			equiv(scpb.Status_WRITE_ONLY),
+			to(scpb.Status_ABSENT,
+				emit(func(this *scpb.ExclusionConstraint) *scop.RemoveExclusionConstraint {
+					return &scop.RemoveExclusionConstraint{
+						TableID: this.TableID,
+						IndexID: this.IndexID,
+					}
+				}),
+			),
+		),
+	)
+}
@@ -103,6 +103,23 @@ func init() {
 		},
 	)
 
+	// Exclusion constraints are checked on writes as soon as they are added,
+	// using the partial predicate of their backing index, so the index must
+	// exist by then.
+	registerDepRule(
+		"secondary index exists before its exclusion constraint",
+		scgraph.Precedence,
+		"index", "exclusion-constraint",
+		func(from, to NodeVars) rel.Clauses {
+			return rel.Clauses{
+				from.Type((*scpb.SecondaryIndex)(nil)),
+				to.Type((*scpb.ExclusionConstraint)(nil)),
+				JoinOnIndexID(from, to, "table-id", "index-id"),
+				StatusesToPublicOrTransient(from, scpb.Status_BACKFILL_ONLY, to, scpb.Status_WRITE_ONLY),
+			}
+		},
+	)
+
 	registerDepRule(
 		"secondary index named before validation (without index swap)",
 		scgraph.Precedence,
@@ -94,7 +94,7 @@ func isSubjectTo2VersionInvariant(e scpb.Element) bool {
 	}
 	switch e.(type) {
 	case *scpb.CheckConstraint, *scpb.UniqueWithoutIndexConstraint, *scpb.ForeignKeyConstraint,
-		*scpb.ColumnNotNull:
+		*scpb.ColumnNotNull, *scpb.ExclusionConstraint:
 		return true
 	}
 	return false
@@ -236,7 +236,7 @@ func isIndexDependent(e scpb.Element) bool {
 	case *scpb.IndexName, *scpb.IndexComment, *scpb.IndexColumn,
 		*scpb.IndexZoneConfig:
 		return true
-	case *scpb.IndexPartitioning, *scpb.SecondaryIndexPartial:
+	case *scpb.IndexPartitioning, *scpb.SecondaryIndexPartial, *scpb.ExclusionConstraint:
 		return true
 	}
 	return false
@@ -82,6 +82,10 @@ func checkSimpleDependentsReferenceDescID(e scpb.Element) error {
 //     subject to the two-version invariant;
 //   - go direct to ABSENT in all other cases.
 func checkToAbsentCategories(e scpb.Element) error {
+	// Skip if this version doesn't support the element.
+	if !screl.VersionSupportsElementUse(e, clusterversion.ClusterVersion{Version: clusterversion.V24_1.Version()}) {
+		return nil
+	}
 	s0 := opgen.InitialStatus(e, scpb.Status_ABSENT)
 	s1 := opgen.NextStatus(e, scpb.Status_ABSENT, s0)
 	switch s1 {
@@ -162,6 +166,10 @@ func checkIsIndexDependent(e scpb.Element) error {
 	if isIndex(e) || isData(e) || isNonIndexBackedConstraint(e) {
 		return nil
 	}
+	// Skip if this version doesn't support the element.
+	if !screl.VersionSupportsElementUse(e, clusterversion.ClusterVersion{Version: clusterversion.V24_1.Version()}) {
+		return nil
+	}
 	// An index dependent should have an IndexID attribute.
 	_, err := screl.Schema.GetAttribute(screl.IndexID, e)
 	if isIndexDependent(e) {
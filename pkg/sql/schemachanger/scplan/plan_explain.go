@@ -538,6 +538,12 @@ func (p Plan) explainValidations(root treeprinter.Node, ops []scop.Op) error {
 				p.IndexName(op.TableID, op.IndexIDForValidation),
 				p.Name(op.TableID),
 			)))
+		case *scop.ValidateExclusionConstraint:
+			root.Child(accountFor(fmt.Sprintf(
+				"validate EXCLUDE constraint backed by index %s in relation %s",
+				p.IndexName(op.TableID, op.IndexID),
+				p.Name(op.TableID),
+			)))
 		}
 	}
 	return p.Params.MemAcc.Grow(p.Params.Ctx, int64(estimatedMemAlloc))
@@ -314,6 +314,10 @@ var elementSchemaOptions = []rel.SchemaOption{
 		rel.EntityAttr(DescID, "TableID"),
 		rel.EntityAttr(IndexID, "IndexID"),
 	),
+	rel.EntityMapping(t((*scpb.ExclusionConstraint)(nil)),
+		rel.EntityAttr(DescID, "TableID"),
+		rel.EntityAttr(IndexID, "IndexID"),
+	),
 	// Constraint elements.
 	rel.EntityMapping(t((*scpb.ConstraintWithoutIndexName)(nil)),
 		rel.EntityAttr(DescID, "TableID"),
@@ -127,7 +127,7 @@ func VersionSupportsElementUse(el scpb.Element, version clusterversion.ClusterVe
 		return true
 	case *scpb.TypeComment, *scpb.DatabaseZoneConfig:
 		return version.IsActive(clusterversion.V24_2)
-	case *scpb.ColumnComputeExpression:
+	case *scpb.ColumnComputeExpression, *scpb.ExclusionConstraint:
 		return version.IsActive(clusterversion.V24_3)
 	default:
 		panic(errors.AssertionFailedf("unknown element %T", el))
@@ -594,6 +594,9 @@ var aggregates = map[string]builtinDefinition{
 	"st_collect":    makeSTCollectBuiltin(),
 	"st_memcollect": makeSTCollectBuiltin(),
 
+	"range_agg":           makeRangeAggBuiltin(),
+	"range_intersect_agg": makeRangeIntersectAggBuiltin(),
+
 	AnyNotNull: makePrivate(makeBuiltin(tree.FunctionProperties{},
 		makeImmutableAggOverloadWithReturnType(
 			[]*types.T{types.Any},
@@ -1266,6 +1269,153 @@ func (agg *stExtentAgg) Size() int64 {
 	return sizeOfSTExtentAggregate
 }
 
+func makeRangeAggBuiltin() builtinDefinition {
+	overloads := make([]tree.Overload, 0, 2*len(types.RangeTypes))
+	for i, typ := range types.RangeTypes {
+		multirangeTyp := types.MultirangeTypes[i]
+		overloads = append(overloads,
+			makeImmutableAggOverload([]*types.T{typ}, multirangeTyp, newRangeAggregate,
+				"Computes the union of the selected ranges."),
+			makeImmutableAggOverload([]*types.T{multirangeTyp}, multirangeTyp, newRangeAggregate,
+				"Computes the union of the selected multiranges."),
+		)
+	}
+	return makeBuiltin(tree.FunctionProperties{}, overloads...)
+}
+
+func makeRangeIntersectAggBuiltin() builtinDefinition {
+	overloads := make([]tree.Overload, 0, 2*len(types.RangeTypes))
+	for i, typ := range types.RangeTypes {
+		multirangeTyp := types.MultirangeTypes[i]
+		overloads = append(overloads,
+			makeImmutableAggOverload([]*types.T{typ}, typ, newRangeIntersectAggregate,
+				"Computes the intersection of the selected ranges."),
+			makeImmutableAggOverload([]*types.T{multirangeTyp}, multirangeTyp, newRangeIntersectAggregate,
+				"Computes the intersection of the selected multiranges."),
+		)
+	}
+	return makeBuiltin(tree.FunctionProperties{}, overloads...)
+}
+
+// rangeAggregate computes the union of ranges or multiranges as a multirange.
+// The ranges are only merged when the result is computed.
+type rangeAggregate struct {
+	typ    *types.T
+	ranges []*tree.DRange
+	// sawNonNull is true if any range was added, since the union of empty
+	// ranges is an empty multirange rather than NULL.
+	sawNonNull bool
+	acc        mon.BoundAccount
+}
+
+func newRangeAggregate(params []*types.T, evalCtx *eval.Context, _ tree.Datums) eval.AggregateFunc {
+	typ := params[0]
+	if typ.Family() == types.RangeFamily {
+		typ = types.MakeMultirange(typ)
+	}
+	return &rangeAggregate{
+		typ: typ,
+		acc: evalCtx.Planner.Mon().MakeBoundAccount(),
+	}
+}
+
+// Add implements the AggregateFunc interface.
+func (a *rangeAggregate) Add(ctx context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	if err := a.acc.Grow(ctx, int64(datum.Size())); err != nil {
+		return err
+	}
+	a.sawNonNull = true
+	if r, ok := tree.AsDRange(datum); ok {
+		a.ranges = append(a.ranges, r)
+	} else {
+		a.ranges = append(a.ranges, tree.MustBeDMultirange(datum).Ranges...)
+	}
+	return nil
+}
+
+// Result implements the AggregateFunc interface.
+func (a *rangeAggregate) Result() (tree.Datum, error) {
+	if !a.sawNonNull {
+		return tree.DNull, nil
+	}
+	return tree.NewDMultirange(a.typ, a.ranges), nil
+}
+
+// Reset implements the AggregateFunc interface.
+func (a *rangeAggregate) Reset(ctx context.Context) {
+	a.ranges = nil
+	a.sawNonNull = false
+	a.acc.Empty(ctx)
+}
+
+// Close implements the AggregateFunc interface.
+func (a *rangeAggregate) Close(ctx context.Context) {
+	a.acc.Close(ctx)
+}
+
+// Size implements the AggregateFunc interface.
+func (a *rangeAggregate) Size() int64 {
+	return sizeOfRangeAggregate
+}
+
+// rangeIntersectAggregate computes the intersection of ranges or
+// multiranges.
+type rangeIntersectAggregate struct {
+	singleDatumAggregateBase
+
+	result tree.Datum
+}
+
+func newRangeIntersectAggregate(
+	_ []*types.T, evalCtx *eval.Context, _ tree.Datums,
+) eval.AggregateFunc {
+	return &rangeIntersectAggregate{
+		singleDatumAggregateBase: makeSingleDatumAggregateBase(evalCtx),
+	}
+}
+
+// Add implements the AggregateFunc interface.
+func (a *rangeIntersectAggregate) Add(ctx context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	if a.result == nil {
+		a.result = datum
+	} else if r, ok := tree.AsDRange(a.result); ok {
+		a.result = r.Intersect(tree.MustBeDRange(datum))
+	} else {
+		a.result = tree.MustBeDMultirange(a.result).Intersect(tree.MustBeDMultirange(datum))
+	}
+	return a.updateMemoryUsage(ctx, int64(a.result.Size()))
+}
+
+// Result implements the AggregateFunc interface.
+func (a *rangeIntersectAggregate) Result() (tree.Datum, error) {
+	if a.result == nil {
+		return tree.DNull, nil
+	}
+	return a.result, nil
+}
+
+// Reset implements the AggregateFunc interface.
+func (a *rangeIntersectAggregate) Reset(ctx context.Context) {
+	a.result = nil
+	a.reset(ctx)
+}
+
+// Close implements the AggregateFunc interface.
+func (a *rangeIntersectAggregate) Close(ctx context.Context) {
+	a.close(ctx)
+}
+
+// Size implements the AggregateFunc interface.
+func (a *rangeIntersectAggregate) Size() int64 {
+	return sizeOfRangeIntersectAggregate
+}
+
 func makeVarianceBuiltin() builtinDefinition {
 	return makeBuiltin(tree.FunctionProperties{},
 		makeImmutableAggOverload([]*types.T{types.Int}, types.Decimal, newIntVarianceAggregate,
@@ -1343,6 +1493,8 @@ var _ eval.AggregateFunc = &regressionSYYAggregate{}
 var _ eval.AggregateFunc = &regressionCountAggregate{}
 var _ eval.AggregateFunc = &regressionAvgXAggregate{}
 var _ eval.AggregateFunc = &regressionAvgYAggregate{}
+var _ eval.AggregateFunc = &rangeAggregate{}
+var _ eval.AggregateFunc = &rangeIntersectAggregate{}
 
 const sizeOfArrayAggregate = int64(unsafe.Sizeof(arrayAggregate{}))
 const sizeOfArrayCatAggregate = int64(unsafe.Sizeof(arrayCatAggregate{}))
@@ -1388,6 +1540,8 @@ const sizeOfSTMakeLineAggregate = int64(unsafe.Sizeof(stMakeLineAgg{}))
 const sizeOfSTUnionAggregate = int64(unsafe.Sizeof(stUnionAgg{}))
 const sizeOfSTCollectAggregate = int64(unsafe.Sizeof(stCollectAgg{}))
 const sizeOfSTExtentAggregate = int64(unsafe.Sizeof(stExtentAgg{}))
+const sizeOfRangeAggregate = int64(unsafe.Sizeof(rangeAggregate{}))
+const sizeOfRangeIntersectAggregate = int64(unsafe.Sizeof(rangeIntersectAggregate{}))
 const sizeOfStatementStatistics = int64(unsafe.Sizeof(aggStatementStatistics{}))
 const sizeOfAggStatementMetadata = int64(unsafe.Sizeof(aggStatementMetadata{}))
 const sizeOfTransactionStatistics = int64(unsafe.Sizeof(aggTransactionStatistics{}))
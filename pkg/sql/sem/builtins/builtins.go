@@ -299,14 +299,18 @@ var regularBuiltins = map[string]builtinDefinition{
 	// TODO(pmattis): What string functions should also support types.Bytes?
 
 	"lower": makeBuiltin(tree.FunctionProperties{Category: builtinconstants.CategoryString},
-		stringOverload1(
-			func(_ context.Context, _ *eval.Context, s string) (tree.Datum, error) {
-				return tree.NewDString(strings.ToLower(s)), nil
+		append([]tree.Overload{{
+			Types:      tree.ParamTypes{{Name: "val", Typ: types.String}},
+			ReturnType: tree.FixedReturnType(types.String),
+			// The string overload is preferred over the range overloads, so that
+			// the type of a placeholder argument can be inferred.
+			PreferredOverload: true,
+			Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDString(strings.ToLower(string(tree.MustBeDString(args[0])))), nil
 			},
-			types.String,
-			"Converts all characters in `val` to their lower-case equivalents.",
-			volatility.Immutable,
-		),
+			Info:       "Converts all characters in `val` to their lower-case equivalents.",
+			Volatility: volatility.Immutable,
+		}}, makeRangeBoundOverloads(false /* upper */)...)...,
 	),
 
 	"unaccent": makeBuiltin(tree.FunctionProperties{Category: builtinconstants.CategoryString},
@@ -330,14 +334,17 @@ var regularBuiltins = map[string]builtinDefinition{
 	),
 
 	"upper": makeBuiltin(tree.FunctionProperties{Category: builtinconstants.CategoryString},
-		stringOverload1(
-			func(_ context.Context, _ *eval.Context, s string) (tree.Datum, error) {
-				return tree.NewDString(strings.ToUpper(s)), nil
+		append([]tree.Overload{{
+			Types:      tree.ParamTypes{{Name: "val", Typ: types.String}},
+			ReturnType: tree.FixedReturnType(types.String),
+			// See the comment on lower.
+			PreferredOverload: true,
+			Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDString(strings.ToUpper(string(tree.MustBeDString(args[0])))), nil
 			},
-			types.String,
-			"Converts all characters in `val` to their to their upper-case equivalents.",
-			volatility.Immutable,
-		),
+			Info:       "Converts all characters in `val` to their to their upper-case equivalents.",
+			Volatility: volatility.Immutable,
+		}}, makeRangeBoundOverloads(true /* upper */)...)...,
 	),
 
 	"prettify_statement": makeBuiltin(tree.FunctionProperties{Category: builtinconstants.CategoryString},
@@ -6506,6 +6513,43 @@ SELECT
 		},
 	),
 
+	"crdb_internal.check_domain": makeBuiltin(
+		tree.FunctionProperties{
+			Category:     builtinconstants.CategorySystemInfo,
+			Undocumented: true,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "val", Typ: types.Any},
+				{Name: "passed", Typ: types.Bool},
+				{Name: "domain", Typ: types.String},
+				{Name: "constraint", Typ: types.String},
+			},
+			ReturnType: tree.IdentityReturnType(0),
+			Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				// Like CHECK constraints on tables, a constraint of a domain is
+				// satisfied if it evaluates to NULL.
+				if args[1] == tree.DNull || tree.MustBeDBool(args[1]) {
+					return args[0], nil
+				}
+				domain := string(tree.MustBeDString(args[2]))
+				constraint := string(tree.MustBeDString(args[3]))
+				if constraint == "" {
+					return nil, pgerror.Newf(pgcode.NotNullViolation,
+						"domain %s does not allow null values", domain)
+				}
+				return nil, pgerror.Newf(pgcode.CheckViolation,
+					"value for domain %s violates check constraint %q", domain, constraint)
+			},
+			Info: "This function is used internally to check the constraints of a " +
+				"domain. It returns val if passed is true or NULL, and raises an error " +
+				"for the given constraint otherwise. An empty constraint refers to " +
+				"the NOT NULL constraint of the domain.",
+			Volatility:        volatility.Immutable,
+			CalledOnNullInput: true,
+		},
+	),
+
 	"crdb_internal.round_decimal_values": makeBuiltin(
 		tree.FunctionProperties{
 			Category: builtinconstants.CategorySystemInfo,
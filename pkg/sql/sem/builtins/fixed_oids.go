@@ -2607,6 +2607,263 @@ var builtinOidsArray = []string{
 	2639: `crdb_internal.start_replication_stream_for_tables(req: bytes) -> bytes`,
 	2640: `crdb_internal.clear_query_plan_cache() -> void`,
 	2641: `crdb_internal.clear_table_stats_cache() -> void`,
+	2642: `grouping(anyelement...) -> int`,
+	2643: `pg_notify(channel: string, payload: string) -> void`,
+	2644: `int4rangesend(int4range: int4range) -> bytes`,
+	2645: `int4rangeout(int4range: int4range) -> bytes`,
+	2646: `int4rangerecv(input: anyelement) -> int4range`,
+	2647: `int4rangein(input: anyelement) -> int4range`,
+	2648: `int8rangesend(int8range: int8range) -> bytes`,
+	2649: `int8rangeout(int8range: int8range) -> bytes`,
+	2650: `int8rangerecv(input: anyelement) -> int8range`,
+	2651: `int8rangein(input: anyelement) -> int8range`,
+	2652: `numrangesend(numrange: numrange) -> bytes`,
+	2653: `numrangeout(numrange: numrange) -> bytes`,
+	2654: `numrangerecv(input: anyelement) -> numrange`,
+	2655: `numrangein(input: anyelement) -> numrange`,
+	2656: `tsrangesend(tsrange: tsrange) -> bytes`,
+	2657: `tsrangeout(tsrange: tsrange) -> bytes`,
+	2658: `tsrangerecv(input: anyelement) -> tsrange`,
+	2659: `tsrangein(input: anyelement) -> tsrange`,
+	2660: `tstzrangesend(tstzrange: tstzrange) -> bytes`,
+	2661: `tstzrangeout(tstzrange: tstzrange) -> bytes`,
+	2662: `tstzrangerecv(input: anyelement) -> tstzrange`,
+	2663: `tstzrangein(input: anyelement) -> tstzrange`,
+	2664: `daterangesend(daterange: daterange) -> bytes`,
+	2665: `daterangeout(daterange: daterange) -> bytes`,
+	2666: `daterangerecv(input: anyelement) -> daterange`,
+	2667: `daterangein(input: anyelement) -> daterange`,
+	2668: `int4multirangesend(int4multirange: int4multirange) -> bytes`,
+	2669: `int4multirangeout(int4multirange: int4multirange) -> bytes`,
+	2670: `int4multirangerecv(input: anyelement) -> int4multirange`,
+	2671: `int4multirangein(input: anyelement) -> int4multirange`,
+	2672: `int8multirangesend(int8multirange: int8multirange) -> bytes`,
+	2673: `int8multirangeout(int8multirange: int8multirange) -> bytes`,
+	2674: `int8multirangerecv(input: anyelement) -> int8multirange`,
+	2675: `int8multirangein(input: anyelement) -> int8multirange`,
+	2676: `nummultirangesend(nummultirange: nummultirange) -> bytes`,
+	2677: `nummultirangeout(nummultirange: nummultirange) -> bytes`,
+	2678: `nummultirangerecv(input: anyelement) -> nummultirange`,
+	2679: `nummultirangein(input: anyelement) -> nummultirange`,
+	2680: `tsmultirangesend(tsmultirange: tsmultirange) -> bytes`,
+	2681: `tsmultirangeout(tsmultirange: tsmultirange) -> bytes`,
+	2682: `tsmultirangerecv(input: anyelement) -> tsmultirange`,
+	2683: `tsmultirangein(input: anyelement) -> tsmultirange`,
+	2684: `tstzmultirangesend(tstzmultirange: tstzmultirange) -> bytes`,
+	2685: `tstzmultirangeout(tstzmultirange: tstzmultirange) -> bytes`,
+	2686: `tstzmultirangerecv(input: anyelement) -> tstzmultirange`,
+	2687: `tstzmultirangein(input: anyelement) -> tstzmultirange`,
+	2688: `datemultirangesend(datemultirange: datemultirange) -> bytes`,
+	2689: `datemultirangeout(datemultirange: datemultirange) -> bytes`,
+	2690: `datemultirangerecv(input: anyelement) -> datemultirange`,
+	2691: `datemultirangein(input: anyelement) -> datemultirange`,
+	2692: `char(int4range: int4range) -> "char"`,
+	2693: `name(int4range: int4range) -> name`,
+	2694: `text(int4range: int4range) -> string`,
+	2695: `varchar(int4range: int4range) -> varchar`,
+	2696: `bpchar(int4range: int4range) -> char`,
+	2697: `char(int8range: int8range) -> "char"`,
+	2698: `name(int8range: int8range) -> name`,
+	2699: `text(int8range: int8range) -> string`,
+	2700: `varchar(int8range: int8range) -> varchar`,
+	2701: `bpchar(int8range: int8range) -> char`,
+	2702: `char(numrange: numrange) -> "char"`,
+	2703: `name(numrange: numrange) -> name`,
+	2704: `text(numrange: numrange) -> string`,
+	2705: `varchar(numrange: numrange) -> varchar`,
+	2706: `bpchar(numrange: numrange) -> char`,
+	2707: `char(tsrange: tsrange) -> "char"`,
+	2708: `name(tsrange: tsrange) -> name`,
+	2709: `text(tsrange: tsrange) -> string`,
+	2710: `varchar(tsrange: tsrange) -> varchar`,
+	2711: `bpchar(tsrange: tsrange) -> char`,
+	2712: `char(tstzrange: tstzrange) -> "char"`,
+	2713: `name(tstzrange: tstzrange) -> name`,
+	2714: `text(tstzrange: tstzrange) -> string`,
+	2715: `varchar(tstzrange: tstzrange) -> varchar`,
+	2716: `bpchar(tstzrange: tstzrange) -> char`,
+	2717: `char(daterange: daterange) -> "char"`,
+	2718: `name(daterange: daterange) -> name`,
+	2719: `text(daterange: daterange) -> string`,
+	2720: `varchar(daterange: daterange) -> varchar`,
+	2721: `bpchar(daterange: daterange) -> char`,
+	2722: `char(int4multirange: int4multirange) -> "char"`,
+	2723: `name(int4multirange: int4multirange) -> name`,
+	2724: `text(int4multirange: int4multirange) -> string`,
+	2725: `varchar(int4multirange: int4multirange) -> varchar`,
+	2726: `bpchar(int4multirange: int4multirange) -> char`,
+	2727: `char(int8multirange: int8multirange) -> "char"`,
+	2728: `name(int8multirange: int8multirange) -> name`,
+	2729: `text(int8multirange: int8multirange) -> string`,
+	2730: `varchar(int8multirange: int8multirange) -> varchar`,
+	2731: `bpchar(int8multirange: int8multirange) -> char`,
+	2732: `char(nummultirange: nummultirange) -> "char"`,
+	2733: `name(nummultirange: nummultirange) -> name`,
+	2734: `text(nummultirange: nummultirange) -> string`,
+	2735: `varchar(nummultirange: nummultirange) -> varchar`,
+	2736: `bpchar(nummultirange: nummultirange) -> char`,
+	2737: `char(tsmultirange: tsmultirange) -> "char"`,
+	2738: `name(tsmultirange: tsmultirange) -> name`,
+	2739: `text(tsmultirange: tsmultirange) -> string`,
+	2740: `varchar(tsmultirange: tsmultirange) -> varchar`,
+	2741: `bpchar(tsmultirange: tsmultirange) -> char`,
+	2742: `char(tstzmultirange: tstzmultirange) -> "char"`,
+	2743: `name(tstzmultirange: tstzmultirange) -> name`,
+	2744: `text(tstzmultirange: tstzmultirange) -> string`,
+	2745: `varchar(tstzmultirange: tstzmultirange) -> varchar`,
+	2746: `bpchar(tstzmultirange: tstzmultirange) -> char`,
+	2747: `char(datemultirange: datemultirange) -> "char"`,
+	2748: `name(datemultirange: datemultirange) -> name`,
+	2749: `text(datemultirange: datemultirange) -> string`,
+	2750: `varchar(datemultirange: datemultirange) -> varchar`,
+	2751: `bpchar(datemultirange: datemultirange) -> char`,
+	2752: `int4range(lower: int4, upper: int4) -> int4range`,
+	2753: `int4range(lower: int4, upper: int4, bounds: string) -> int4range`,
+	2754: `int4multirange(int4range...) -> int4multirange`,
+	2755: `multirange(range: int4range) -> int4multirange`,
+	2756: `int8range(lower: int, upper: int) -> int8range`,
+	2757: `int8range(lower: int, upper: int, bounds: string) -> int8range`,
+	2758: `int8multirange(int8range...) -> int8multirange`,
+	2759: `multirange(range: int8range) -> int8multirange`,
+	2760: `numrange(lower: decimal, upper: decimal) -> numrange`,
+	2761: `numrange(lower: decimal, upper: decimal, bounds: string) -> numrange`,
+	2762: `nummultirange(numrange...) -> nummultirange`,
+	2763: `multirange(range: numrange) -> nummultirange`,
+	2764: `tsrange(lower: timestamp, upper: timestamp) -> tsrange`,
+	2765: `tsrange(lower: timestamp, upper: timestamp, bounds: string) -> tsrange`,
+	2766: `tsmultirange(tsrange...) -> tsmultirange`,
+	2767: `multirange(range: tsrange) -> tsmultirange`,
+	2768: `tstzrange(lower: timestamptz, upper: timestamptz) -> tstzrange`,
+	2769: `tstzrange(lower: timestamptz, upper: timestamptz, bounds: string) -> tstzrange`,
+	2770: `tstzmultirange(tstzrange...) -> tstzmultirange`,
+	2771: `multirange(range: tstzrange) -> tstzmultirange`,
+	2772: `daterange(lower: date, upper: date) -> daterange`,
+	2773: `daterange(lower: date, upper: date, bounds: string) -> daterange`,
+	2774: `datemultirange(daterange...) -> datemultirange`,
+	2775: `multirange(range: daterange) -> datemultirange`,
+	2776: `lower(range: int4range) -> int4`,
+	2777: `lower(range: int8range) -> int`,
+	2778: `lower(range: numrange) -> decimal`,
+	2779: `lower(range: tsrange) -> timestamp`,
+	2780: `lower(range: tstzrange) -> timestamptz`,
+	2781: `lower(range: daterange) -> date`,
+	2782: `lower(multirange: int4multirange) -> int4`,
+	2783: `lower(multirange: int8multirange) -> int`,
+	2784: `lower(multirange: nummultirange) -> decimal`,
+	2785: `lower(multirange: tsmultirange) -> timestamp`,
+	2786: `lower(multirange: tstzmultirange) -> timestamptz`,
+	2787: `lower(multirange: datemultirange) -> date`,
+	2788: `upper(range: int4range) -> int4`,
+	2789: `upper(range: int8range) -> int`,
+	2790: `upper(range: numrange) -> decimal`,
+	2791: `upper(range: tsrange) -> timestamp`,
+	2792: `upper(range: tstzrange) -> timestamptz`,
+	2793: `upper(range: daterange) -> date`,
+	2794: `upper(multirange: int4multirange) -> int4`,
+	2795: `upper(multirange: int8multirange) -> int`,
+	2796: `upper(multirange: nummultirange) -> decimal`,
+	2797: `upper(multirange: tsmultirange) -> timestamp`,
+	2798: `upper(multirange: tstzmultirange) -> timestamptz`,
+	2799: `upper(multirange: datemultirange) -> date`,
+	2800: `isempty(range: int4range) -> bool`,
+	2801: `isempty(range: int8range) -> bool`,
+	2802: `isempty(range: numrange) -> bool`,
+	2803: `isempty(range: tsrange) -> bool`,
+	2804: `isempty(range: tstzrange) -> bool`,
+	2805: `isempty(range: daterange) -> bool`,
+	2806: `isempty(multirange: int4multirange) -> bool`,
+	2807: `isempty(multirange: int8multirange) -> bool`,
+	2808: `isempty(multirange: nummultirange) -> bool`,
+	2809: `isempty(multirange: tsmultirange) -> bool`,
+	2810: `isempty(multirange: tstzmultirange) -> bool`,
+	2811: `isempty(multirange: datemultirange) -> bool`,
+	2812: `lower_inc(range: int4range) -> bool`,
+	2813: `lower_inc(range: int8range) -> bool`,
+	2814: `lower_inc(range: numrange) -> bool`,
+	2815: `lower_inc(range: tsrange) -> bool`,
+	2816: `lower_inc(range: tstzrange) -> bool`,
+	2817: `lower_inc(range: daterange) -> bool`,
+	2818: `lower_inc(multirange: int4multirange) -> bool`,
+	2819: `lower_inc(multirange: int8multirange) -> bool`,
+	2820: `lower_inc(multirange: nummultirange) -> bool`,
+	2821: `lower_inc(multirange: tsmultirange) -> bool`,
+	2822: `lower_inc(multirange: tstzmultirange) -> bool`,
+	2823: `lower_inc(multirange: datemultirange) -> bool`,
+	2824: `upper_inc(range: int4range) -> bool`,
+	2825: `upper_inc(range: int8range) -> bool`,
+	2826: `upper_inc(range: numrange) -> bool`,
+	2827: `upper_inc(range: tsrange) -> bool`,
+	2828: `upper_inc(range: tstzrange) -> bool`,
+	2829: `upper_inc(range: daterange) -> bool`,
+	2830: `upper_inc(multirange: int4multirange) -> bool`,
+	2831: `upper_inc(multirange: int8multirange) -> bool`,
+	2832: `upper_inc(multirange: nummultirange) -> bool`,
+	2833: `upper_inc(multirange: tsmultirange) -> bool`,
+	2834: `upper_inc(multirange: tstzmultirange) -> bool`,
+	2835: `upper_inc(multirange: datemultirange) -> bool`,
+	2836: `lower_inf(range: int4range) -> bool`,
+	2837: `lower_inf(range: int8range) -> bool`,
+	2838: `lower_inf(range: numrange) -> bool`,
+	2839: `lower_inf(range: tsrange) -> bool`,
+	2840: `lower_inf(range: tstzrange) -> bool`,
+	2841: `lower_inf(range: daterange) -> bool`,
+	2842: `lower_inf(multirange: int4multirange) -> bool`,
+	2843: `lower_inf(multirange: int8multirange) -> bool`,
+	2844: `lower_inf(multirange: nummultirange) -> bool`,
+	2845: `lower_inf(multirange: tsmultirange) -> bool`,
+	2846: `lower_inf(multirange: tstzmultirange) -> bool`,
+	2847: `lower_inf(multirange: datemultirange) -> bool`,
+	2848: `upper_inf(range: int4range) -> bool`,
+	2849: `upper_inf(range: int8range) -> bool`,
+	2850: `upper_inf(range: numrange) -> bool`,
+	2851: `upper_inf(range: tsrange) -> bool`,
+	2852: `upper_inf(range: tstzrange) -> bool`,
+	2853: `upper_inf(range: daterange) -> bool`,
+	2854: `upper_inf(multirange: int4multirange) -> bool`,
+	2855: `upper_inf(multirange: int8multirange) -> bool`,
+	2856: `upper_inf(multirange: nummultirange) -> bool`,
+	2857: `upper_inf(multirange: tsmultirange) -> bool`,
+	2858: `upper_inf(multirange: tstzmultirange) -> bool`,
+	2859: `upper_inf(multirange: datemultirange) -> bool`,
+	2860: `range_merge(range1: int4range, range2: int4range) -> int4range`,
+	2861: `range_merge(range1: int8range, range2: int8range) -> int8range`,
+	2862: `range_merge(range1: numrange, range2: numrange) -> numrange`,
+	2863: `range_merge(range1: tsrange, range2: tsrange) -> tsrange`,
+	2864: `range_merge(range1: tstzrange, range2: tstzrange) -> tstzrange`,
+	2865: `range_merge(range1: daterange, range2: daterange) -> daterange`,
+	2866: `range_merge(multirange: int4multirange) -> int4range`,
+	2867: `range_merge(multirange: int8multirange) -> int8range`,
+	2868: `range_merge(multirange: nummultirange) -> numrange`,
+	2869: `range_merge(multirange: tsmultirange) -> tsrange`,
+	2870: `range_merge(multirange: tstzmultirange) -> tstzrange`,
+	2871: `range_merge(multirange: datemultirange) -> daterange`,
+	2872: `range_agg(arg1: int4range) -> int4multirange`,
+	2873: `range_agg(arg1: int4multirange) -> int4multirange`,
+	2874: `range_agg(arg1: int8range) -> int8multirange`,
+	2875: `range_agg(arg1: int8multirange) -> int8multirange`,
+	2876: `range_agg(arg1: numrange) -> nummultirange`,
+	2877: `range_agg(arg1: nummultirange) -> nummultirange`,
+	2878: `range_agg(arg1: tsrange) -> tsmultirange`,
+	2879: `range_agg(arg1: tsmultirange) -> tsmultirange`,
+	2880: `range_agg(arg1: tstzrange) -> tstzmultirange`,
+	2881: `range_agg(arg1: tstzmultirange) -> tstzmultirange`,
+	2882: `range_agg(arg1: daterange) -> datemultirange`,
+	2883: `range_agg(arg1: datemultirange) -> datemultirange`,
+	2884: `range_intersect_agg(arg1: int4range) -> int4range`,
+	2885: `range_intersect_agg(arg1: int4multirange) -> int4multirange`,
+	2886: `range_intersect_agg(arg1: int8range) -> int8range`,
+	2887: `range_intersect_agg(arg1: int8multirange) -> int8multirange`,
+	2888: `range_intersect_agg(arg1: numrange) -> numrange`,
+	2889: `range_intersect_agg(arg1: nummultirange) -> nummultirange`,
+	2890: `range_intersect_agg(arg1: tsrange) -> tsrange`,
+	2891: `range_intersect_agg(arg1: tsmultirange) -> tsmultirange`,
+	2892: `range_intersect_agg(arg1: tstzrange) -> tstzrange`,
+	2893: `range_intersect_agg(arg1: tstzmultirange) -> tstzmultirange`,
+	2894: `range_intersect_agg(arg1: daterange) -> daterange`,
+	2895: `range_intersect_agg(arg1: datemultirange) -> datemultirange`,
+	2896: `crdb_internal.check_domain(val: anyelement, passed: bool, domain: string, constraint: string) -> anyelement`,
+	2897: `crdb_internal.scan_table_revisions(table: regclass, start_time: timestamptz, end_time: timestamptz) -> tuple{decimal AS crdb_internal_mvcc_timestamp, bool AS deleted, jsonb AS data}`,
+	2898: `crdb_internal.scan_table_revisions(table: regclass, start_time: decimal, end_time: decimal) -> tuple{decimal AS crdb_internal_mvcc_timestamp, bool AS deleted, jsonb AS data}`,
 }
 
 var builtinOidsBySignature map[string]oid.Oid
@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/workloadindexrec"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
@@ -45,6 +46,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/arith"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/randident"
@@ -192,6 +194,36 @@ var generators = map[string]builtinDefinition{
 			volatility.Stable,
 		),
 	),
+	"crdb_internal.scan_table_revisions": makeBuiltin(genProps(),
+		makeGeneratorOverload(
+			tree.ParamTypes{
+				{Name: "table", Typ: types.RegClass},
+				{Name: "start_time", Typ: types.TimestampTZ},
+				{Name: "end_time", Typ: types.TimestampTZ},
+			},
+			tableRevisionsGeneratorType,
+			makeTableRevisionsGenerator,
+			"Returns every revision of the rows of the table written between "+
+				"start_time and end_time (inclusive), including deletions. The "+
+				"interval must not extend beyond the table's GC TTL unless it is "+
+				"covered by a protected timestamp.",
+			volatility.Volatile,
+		),
+		makeGeneratorOverload(
+			tree.ParamTypes{
+				{Name: "table", Typ: types.RegClass},
+				{Name: "start_time", Typ: types.Decimal},
+				{Name: "end_time", Typ: types.Decimal},
+			},
+			tableRevisionsGeneratorType,
+			makeTableRevisionsGenerator,
+			"Returns every revision of the rows of the table written between the "+
+				"MVCC timestamps start_time and end_time (inclusive), including "+
+				"deletions. The interval must not extend beyond the table's GC TTL "+
+				"unless it is covered by a protected timestamp.",
+			volatility.Volatile,
+		),
+	),
 	"generate_series": makeBuiltin(genProps(),
 		// See https://www.postgresql.org/docs/current/static/functions-srf.html#FUNCTIONS-SRF-SERIES
 		makeGeneratorOverload(
@@ -730,6 +762,78 @@ The last argument is a JSONB object containing the following optional fields:
 	),
 }
 
+var tableRevisionsGeneratorType = types.MakeLabeledTuple(
+	[]*types.T{types.Decimal, types.Bool, types.Jsonb},
+	[]string{"crdb_internal_mvcc_timestamp", "deleted", "data"},
+)
+
+// tableRevisionsGenerator is an eval.ValueGenerator that produces every MVCC
+// revision of the rows of a table in a time interval.
+type tableRevisionsGenerator struct {
+	tableID            descpb.ID
+	startTime, endTime hlc.Timestamp
+	p                  eval.Planner
+	it                 eval.InternalRows
+}
+
+var _ eval.ValueGenerator = &tableRevisionsGenerator{}
+
+func makeTableRevisionsGenerator(
+	ctx context.Context, evalCtx *eval.Context, args tree.Datums,
+) (eval.ValueGenerator, error) {
+	startTime, err := revisionTimestamp(args[1])
+	if err != nil {
+		return nil, err
+	}
+	endTime, err := revisionTimestamp(args[2])
+	if err != nil {
+		return nil, err
+	}
+	return &tableRevisionsGenerator{
+		tableID:   descpb.ID(tree.MustBeDOid(args[0]).Oid),
+		startTime: startTime,
+		endTime:   endTime,
+		p:         evalCtx.Planner,
+	}, nil
+}
+
+// revisionTimestamp converts a TIMESTAMPTZ or an MVCC timestamp in its DECIMAL
+// form to an hlc.Timestamp.
+func revisionTimestamp(d tree.Datum) (hlc.Timestamp, error) {
+	if dec, ok := tree.AsDDecimal(d); ok {
+		return hlc.DecimalToHLC(&dec.Decimal)
+	}
+	return hlc.Timestamp{WallTime: tree.MustBeDTimestampTZ(d).Time.UnixNano()}, nil
+}
+
+// ResolvedType implements the eval.ValueGenerator interface.
+func (g *tableRevisionsGenerator) ResolvedType() *types.T {
+	return tableRevisionsGeneratorType
+}
+
+// Start implements the eval.ValueGenerator interface.
+func (g *tableRevisionsGenerator) Start(ctx context.Context, _ *kv.Txn) (err error) {
+	g.it, err = g.p.ScanTableRevisions(ctx, g.tableID, g.startTime, g.endTime)
+	return err
+}
+
+// Next implements the eval.ValueGenerator interface.
+func (g *tableRevisionsGenerator) Next(ctx context.Context) (bool, error) {
+	return g.it.Next(ctx)
+}
+
+// Values implements the eval.ValueGenerator interface.
+func (g *tableRevisionsGenerator) Values() (tree.Datums, error) {
+	return g.it.Cur(), nil
+}
+
+// Close implements the eval.ValueGenerator interface.
+func (g *tableRevisionsGenerator) Close(context.Context) {
+	if g.it != nil {
+		_ = g.it.Close()
+	}
+}
+
 var decodePlanGistGeneratorType = types.String
 
 type gistPlanGenerator struct {
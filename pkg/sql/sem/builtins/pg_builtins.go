@@ -154,6 +154,11 @@ func init() {
 		if !ok {
 			return
 		}
+		if f := toType.Family(); f == types.RangeFamily || f == types.MultirangeFamily {
+			// The range constructors, defined in range_builtins.go, have the same
+			// names as the range types.
+			return
+		}
 		distSQLBlockList := toType.Family() == types.OidFamily
 		if _, ok := castBuiltins[toOID]; !ok {
 			castBuiltins[toOID] = &builtinDefinition{
@@ -656,6 +661,30 @@ var pgBuiltins = map[string]builtinDefinition{
 		},
 	),
 
+	// grouping is only valid in the SELECT list, HAVING and ORDER BY clauses of a
+	// query with a GROUP BY clause, where the optimizer replaces it with an
+	// expression over the grouping set that produced each row. The overload below
+	// exists so that the function can be resolved and type checked.
+	"grouping": makeBuiltin(defProps(),
+		tree.Overload{
+			Types: tree.VariadicType{
+				VarType: types.Any,
+			},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return nil, pgerror.New(pgcode.Grouping,
+					"arguments to GROUPING must be grouping expressions of the associated query level",
+				)
+			},
+			Info: "Returns a bit mask indicating which of the arguments are not included in the " +
+				"grouping set of the current row. Bits are assigned with the rightmost argument " +
+				"being the least-significant bit; each bit is 0 if the corresponding expression " +
+				"is included in the grouping set, and 1 if it is not.",
+			Volatility:        volatility.Immutable,
+			CalledOnNullInput: true,
+		},
+	),
+
 	// Postgres defines pg_get_expr as a function that "decompiles the internal form
 	// of an expression", which is provided in the pg_node_tree type. In Cockroach's
 	// pg_catalog implementation, we populate all pg_node_tree columns with the
@@ -1358,6 +1387,33 @@ var pgBuiltins = map[string]builtinDefinition{
 		},
 	),
 
+	// See https://www.postgresql.org/docs/current/functions-info.html#FUNCTIONS-INFO-SESSION.
+	"pg_notify": makeBuiltin(
+		tree.FunctionProperties{DistsqlBlocklist: true},
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "channel", Typ: types.String}, {Name: "payload", Typ: types.String}},
+			ReturnType: tree.FixedReturnType(types.Void),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				if args[0] == tree.DNull {
+					return nil, pgerror.New(pgcode.InvalidParameterValue, "channel name cannot be empty")
+				}
+				var payload string
+				if args[1] != tree.DNull {
+					payload = string(tree.MustBeDString(args[1]))
+				}
+				if err := evalCtx.Planner.Notify(ctx, string(tree.MustBeDString(args[0])), payload); err != nil {
+					return nil, err
+				}
+				return tree.DVoidDatum, nil
+			},
+			Info: "Sends a notification with the given payload to the sessions listening " +
+				"on the given channel. The notification is sent when the current " +
+				"transaction commits.",
+			Volatility:        volatility.Volatile,
+			CalledOnNullInput: true,
+		},
+	),
+
 	"pg_sleep": makeBuiltin(
 		tree.FunctionProperties{},
 		tree.Overload{
@@ -0,0 +1,264 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+func init() {
+	for k, v := range rangeBuiltins {
+		v.props.Category = builtinconstants.CategoryRange
+		v.props.AvailableOnPublicSchema = true
+		const enforceClass = true
+		registerBuiltin(k, v, tree.NormalClass, enforceClass)
+	}
+}
+
+// rangeBuiltins contains the range accessors and the constructors of the
+// range and multirange types. The lower and upper builtins, which also have
+// string overloads, are defined in builtins.go.
+var rangeBuiltins = makeRangeBuiltins()
+
+func makeRangeBuiltins() map[string]builtinDefinition {
+	boolTyp := func(*types.T) *types.T { return types.Bool }
+	b := map[string]builtinDefinition{
+		"isempty": makeBuiltin(defProps(), makeRangeOverloads(
+			boolTyp,
+			func(r *tree.DRange) tree.Datum { return tree.MakeDBool(tree.DBool(r.Empty)) },
+			"Returns true if the range or multirange is empty.",
+		)...),
+		"lower_inc": makeBuiltin(defProps(), makeRangeOverloads(
+			boolTyp,
+			func(r *tree.DRange) tree.Datum { return tree.MakeDBool(tree.DBool(r.Lower.Inclusive)) },
+			"Returns true if the lower bound of the range or multirange is inclusive.",
+		)...),
+		"upper_inc": makeBuiltin(defProps(), makeRangeOverloads(
+			boolTyp,
+			func(r *tree.DRange) tree.Datum { return tree.MakeDBool(tree.DBool(r.Upper.Inclusive)) },
+			"Returns true if the upper bound of the range or multirange is inclusive.",
+		)...),
+		"lower_inf": makeBuiltin(defProps(), makeRangeOverloads(
+			boolTyp,
+			func(r *tree.DRange) tree.Datum {
+				return tree.MakeDBool(tree.DBool(!r.Empty && r.Lower.IsInfinite()))
+			},
+			"Returns true if the range or multirange has no lower bound.",
+		)...),
+		"upper_inf": makeBuiltin(defProps(), makeRangeOverloads(
+			boolTyp,
+			func(r *tree.DRange) tree.Datum {
+				return tree.MakeDBool(tree.DBool(!r.Empty && r.Upper.IsInfinite()))
+			},
+			"Returns true if the range or multirange has no upper bound.",
+		)...),
+		"range_merge": makeBuiltin(defProps(), makeRangeMergeOverloads()...),
+		"multirange":  makeBuiltin(defProps(), makeMultirangeOverloads()...),
+	}
+	for i, typ := range types.RangeTypes {
+		b[typ.Name()] = makeBuiltin(defProps(), makeRangeConstructorOverloads(typ)...)
+		multirangeTyp := types.MultirangeTypes[i]
+		b[multirangeTyp.Name()] = makeBuiltin(defProps(), makeMultirangeConstructorOverload(multirangeTyp))
+	}
+	return b
+}
+
+// makeRangeOverloads returns an overload of a function of a single range for
+// each range type, and one for each multirange type that applies the function
+// to the smallest range that includes all the ranges of the multirange. The
+// return type of the overloads is computed from the range type.
+func makeRangeOverloads(
+	retType func(rangeTyp *types.T) *types.T, fn func(*tree.DRange) tree.Datum, info string,
+) []tree.Overload {
+	overloads := make([]tree.Overload, 0, len(types.RangeTypes)+len(types.MultirangeTypes))
+	for _, typ := range types.RangeTypes {
+		overloads = append(overloads, tree.Overload{
+			Types:      tree.ParamTypes{{Name: "range", Typ: typ}},
+			ReturnType: tree.FixedReturnType(retType(typ)),
+			Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return fn(tree.MustBeDRange(args[0])), nil
+			},
+			Info:       info,
+			Volatility: volatility.Immutable,
+		})
+	}
+	for _, typ := range types.MultirangeTypes {
+		overloads = append(overloads, tree.Overload{
+			Types:      tree.ParamTypes{{Name: "multirange", Typ: typ}},
+			ReturnType: tree.FixedReturnType(retType(typ.RangeContents())),
+			Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return fn(tree.MustBeDMultirange(args[0]).Span()), nil
+			},
+			Info:       info,
+			Volatility: volatility.Immutable,
+		})
+	}
+	return overloads
+}
+
+// makeRangeBoundOverloads returns the range and multirange overloads of the
+// lower and upper builtins.
+func makeRangeBoundOverloads(upper bool) []tree.Overload {
+	info := "Returns the lower bound of the range or multirange, or NULL if it is " +
+		"empty or has no lower bound."
+	if upper {
+		info = "Returns the upper bound of the range or multirange, or NULL if it is " +
+			"empty or has no upper bound."
+	}
+	return makeRangeOverloads(
+		func(typ *types.T) *types.T { return typ.RangeContents() },
+		func(r *tree.DRange) tree.Datum {
+			b := r.Lower
+			if upper {
+				b = r.Upper
+			}
+			if r.Empty || b.IsInfinite() {
+				return tree.DNull
+			}
+			return b.Val
+		},
+		info,
+	)
+}
+
+func makeRangeMergeOverloads() []tree.Overload {
+	overloads := make([]tree.Overload, 0, len(types.RangeTypes)+len(types.MultirangeTypes))
+	for _, typ := range types.RangeTypes {
+		overloads = append(overloads, tree.Overload{
+			Types:      tree.ParamTypes{{Name: "range1", Typ: typ}, {Name: "range2", Typ: typ}},
+			ReturnType: tree.FixedReturnType(typ),
+			Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return tree.MustBeDRange(args[0]).Merge(tree.MustBeDRange(args[1])), nil
+			},
+			Info:       "Returns the smallest range that includes both ranges.",
+			Volatility: volatility.Immutable,
+		})
+	}
+	for _, typ := range types.MultirangeTypes {
+		overloads = append(overloads, tree.Overload{
+			Types:      tree.ParamTypes{{Name: "multirange", Typ: typ}},
+			ReturnType: tree.FixedReturnType(typ.RangeContents()),
+			Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return tree.MustBeDMultirange(args[0]).Span(), nil
+			},
+			Info:       "Returns the smallest range that includes all the ranges of the multirange.",
+			Volatility: volatility.Immutable,
+		})
+	}
+	return overloads
+}
+
+func makeMultirangeOverloads() []tree.Overload {
+	overloads := make([]tree.Overload, 0, len(types.RangeTypes))
+	for i, typ := range types.RangeTypes {
+		multirangeTyp := types.MultirangeTypes[i]
+		overloads = append(overloads, tree.Overload{
+			Types:      tree.ParamTypes{{Name: "range", Typ: typ}},
+			ReturnType: tree.FixedReturnType(multirangeTyp),
+			Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDMultirange(multirangeTyp, []*tree.DRange{tree.MustBeDRange(args[0])}), nil
+			},
+			Info:       "Returns a multirange containing only the given range.",
+			Volatility: volatility.Immutable,
+		})
+	}
+	return overloads
+}
+
+// makeRangeConstructorOverloads returns the overloads of the constructor of
+// the given range type, which takes the lower and upper bounds of the range,
+// and optionally a string like "[)" which specifies which bounds are
+// inclusive. A NULL bound is infinite.
+func makeRangeConstructorOverloads(typ *types.T) []tree.Overload {
+	elemTyp := typ.RangeContents()
+	fn := func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+		lower := tree.RangeBound{Val: args[0], Inclusive: true}
+		upper := tree.RangeBound{Val: args[1]}
+		if len(args) > 2 {
+			if args[2] == tree.DNull {
+				return nil, pgerror.New(pgcode.DataException,
+					"range constructor flags argument must not be null")
+			}
+			var err error
+			lower.Inclusive, upper.Inclusive, err = parseRangeBoundFlags(string(tree.MustBeDString(args[2])))
+			if err != nil {
+				return nil, err
+			}
+		}
+		if lower.Val == tree.DNull {
+			lower.Val = nil
+		}
+		if upper.Val == tree.DNull {
+			upper.Val = nil
+		}
+		return tree.NewDRange(typ, lower, upper)
+	}
+	return []tree.Overload{
+		{
+			Types:             tree.ParamTypes{{Name: "lower", Typ: elemTyp}, {Name: "upper", Typ: elemTyp}},
+			ReturnType:        tree.FixedReturnType(typ),
+			Fn:                fn,
+			CalledOnNullInput: true,
+			Info: "Returns the range with the given bounds, which includes the lower bound " +
+				"and excludes the upper bound. A NULL bound is infinite.",
+			Volatility: volatility.Immutable,
+		},
+		{
+			Types: tree.ParamTypes{
+				{Name: "lower", Typ: elemTyp}, {Name: "upper", Typ: elemTyp}, {Name: "bounds", Typ: types.String},
+			},
+			ReturnType:        tree.FixedReturnType(typ),
+			Fn:                fn,
+			CalledOnNullInput: true,
+			Info: "Returns the range with the given bounds. `bounds` specifies whether each " +
+				"bound is inclusive, and is one of `[]`, `[)`, `(]` or `()`. A NULL bound is infinite.",
+			Volatility: volatility.Immutable,
+		},
+	}
+}
+
+// makeMultirangeConstructorOverload returns the overload of the constructor
+// of the given multirange type, which takes any number of ranges.
+func makeMultirangeConstructorOverload(typ *types.T) tree.Overload {
+	return tree.Overload{
+		Types:      tree.VariadicType{VarType: typ.RangeContents()},
+		ReturnType: tree.FixedReturnType(typ),
+		Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+			ranges := make([]*tree.DRange, len(args))
+			for i := range args {
+				ranges[i] = tree.MustBeDRange(args[i])
+			}
+			return tree.NewDMultirange(typ, ranges), nil
+		},
+		Info:       "Returns the multirange with the values of the given ranges.",
+		Volatility: volatility.Immutable,
+	}
+}
+
+// parseRangeBoundFlags parses the bounds argument of a range constructor.
+func parseRangeBoundFlags(flags string) (lowerInc, upperInc bool, _ error) {
+	if len(flags) != 2 || (flags[0] != '[' && flags[0] != '(') || (flags[1] != ']' && flags[1] != ')') {
+		return false, false, errors.WithHint(
+			pgerror.New(pgcode.Syntax, "invalid range bound flags"),
+			`Valid values are "[]", "[)", "(]", and "()".`,
+		)
+	}
+	return flags[0] == '[', flags[1] == ']', nil
+}
@@ -213,6 +213,16 @@ func LookupCast(src, tgt *types.T) (Cast, bool) {
 		}, true
 	}
 
+	// Domains have dynamic OIDs as well, and are cast like their base types.
+	// The constraints of a target domain are checked separately by the
+	// optimizer.
+	if src.IsDomain() {
+		return LookupCast(src.DomainBaseType(), tgt)
+	}
+	if tgt.IsDomain() {
+		return LookupCast(src, tgt.DomainBaseType())
+	}
+
 	// Enums have dynamic OIDs, so they can't be populated in castMap. Instead,
 	// we dynamically create cast structs for valid enum casts.
 	if srcFamily == types.EnumFamily && tgtFamily == types.StringFamily {
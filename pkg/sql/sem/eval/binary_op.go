@@ -143,6 +143,12 @@ func (e *evaluator) EvalCompareBox2DOp(
 	return tree.MakeDBool(tree.DBool(op.Op(left, right))), nil
 }
 
+func (e *evaluator) EvalCompareRangeOp(
+	ctx context.Context, op *tree.CompareRangeOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	return tree.MakeDBool(tree.DBool(op.Op(left, right))), nil
+}
+
 func (e *evaluator) EvalCompareScalarOp(
 	ctx context.Context, op *tree.CompareScalarOp, left, right tree.Datum,
 ) (tree.Datum, error) {
@@ -1712,3 +1718,39 @@ func (e *evaluator) EvalMultPGVectorOp(
 	}
 	return tree.NewDPGVector(ret), nil
 }
+
+func (e *evaluator) EvalPlusRangeOp(
+	ctx context.Context, _ *tree.PlusRangeOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	return tree.MustBeDRange(left).Union(tree.MustBeDRange(right))
+}
+
+func (e *evaluator) EvalMinusRangeOp(
+	ctx context.Context, _ *tree.MinusRangeOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	return tree.MustBeDRange(left).Difference(tree.MustBeDRange(right))
+}
+
+func (e *evaluator) EvalMultRangeOp(
+	ctx context.Context, _ *tree.MultRangeOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	return tree.MustBeDRange(left).Intersect(tree.MustBeDRange(right)), nil
+}
+
+func (e *evaluator) EvalPlusMultirangeOp(
+	ctx context.Context, _ *tree.PlusMultirangeOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	return tree.MustBeDMultirange(left).Union(tree.MustBeDMultirange(right)), nil
+}
+
+func (e *evaluator) EvalMinusMultirangeOp(
+	ctx context.Context, _ *tree.MinusMultirangeOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	return tree.MustBeDMultirange(left).Difference(tree.MustBeDMultirange(right))
+}
+
+func (e *evaluator) EvalMultMultirangeOp(
+	ctx context.Context, _ *tree.MultMultirangeOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	return tree.MustBeDMultirange(left).Intersect(tree.MustBeDMultirange(right)), nil
+}
@@ -461,7 +461,7 @@ func performCastWithoutPrecisionTruncation(
 				tree.FmtDataConversionConfig(evalCtx.SessionData().DataConversionConfig),
 				tree.FmtLocation(evalCtx.GetLocation()),
 			)
-		case *tree.DArray:
+		case *tree.DArray, *tree.DRange, *tree.DMultirange:
 			s = tree.AsStringWithFlags(
 				d,
 				tree.FmtPgwireText,
@@ -974,6 +974,31 @@ func performCastWithoutPrecisionTruncation(
 			}
 			return dcast, nil
 		}
+	case types.RangeFamily:
+		switch v := d.(type) {
+		case *tree.DString:
+			res, _, err := tree.ParseDRangeFromString(evalCtx, string(*v), t)
+			return res, err
+		case *tree.DRange:
+			if v.ResolvedType().Equivalent(t) {
+				return v, nil
+			}
+		}
+	case types.MultirangeFamily:
+		switch v := d.(type) {
+		case *tree.DString:
+			res, _, err := tree.ParseDMultirangeFromString(evalCtx, string(*v), t)
+			return res, err
+		case *tree.DMultirange:
+			if v.ResolvedType().Equivalent(t) {
+				return v, nil
+			}
+		case *tree.DRange:
+			// Like in Postgres, a range can be cast to the multirange of its type.
+			if types.MakeMultirange(v.ResolvedType()).Equivalent(t) {
+				return tree.NewDMultirange(t, []*tree.DRange{v}), nil
+			}
+		}
 	case types.OidFamily:
 		switch v := d.(type) {
 		case *tree.DOid:
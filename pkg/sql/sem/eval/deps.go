@@ -362,6 +362,12 @@ type Planner interface {
 	// the transaction.
 	FingerprintSpan(ctx context.Context, span roachpb.Span, startTime hlc.Timestamp, allRevisions bool, stripped bool) (uint64, error)
 
+	// ScanTableRevisions returns an iterator over every MVCC revision of the
+	// rows of the given table that was written in the interval
+	// [startTime, endTime]. Each row contains the revision's MVCC timestamp,
+	// whether it is a deletion tombstone, and its column values as JSON.
+	ScanTableRevisions(ctx context.Context, tableID descpb.ID, startTime, endTime hlc.Timestamp) (InternalRows, error)
+
 	// QueryRowEx executes the supplied SQL statement and returns a single row, or
 	// nil if no row is found, or an error if more that one row is returned.
 	//
@@ -457,6 +463,15 @@ type Planner interface {
 
 	// ClearTableStatsCache removes all entries from the node's table stats cache.
 	ClearTableStatsCache()
+
+	// Notify queues a notification with the given payload on the given
+	// channel. The notification is sent to listening sessions when the current
+	// transaction commits. It is used to implement pg_notify.
+	Notify(ctx context.Context, channel, payload string) error
+
+	// HasPendingNotifications returns whether the current transaction has
+	// notifications to send when it commits.
+	HasPendingNotifications() bool
 }
 
 // InternalRows is an iterator interface that's exposed by the internal
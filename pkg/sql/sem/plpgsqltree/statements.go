@@ -225,6 +225,10 @@ type Assignment struct {
 	StatementImpl
 	Var   Variable
 	Value Expr
+
+	// Indirection, if set, is the name of the field of the composite-typed
+	// variable Var that is being assigned, as in "var.field := value".
+	Indirection tree.Name
 }
 
 func (s *Assignment) CopyNode() *Assignment {
@@ -238,6 +242,10 @@ func (s *Assignment) PlpgSQLStatementTag() string {
 
 func (s *Assignment) Format(ctx *tree.FmtCtx) {
 	ctx.FormatNode(&s.Var)
+	if s.Indirection != "" {
+		ctx.WriteString(".")
+		ctx.FormatNode(&s.Indirection)
+	}
 	ctx.WriteString(" := ")
 	ctx.FormatNode(s.Value)
 	ctx.WriteString(";\n")
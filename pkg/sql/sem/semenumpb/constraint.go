@@ -16,3 +16,13 @@ var _ redact.SafeValue = ForeignKeyAction(0)
 
 // SafeValue implements redact.SafeValue.
 func (x ForeignKeyAction) SafeValue() {}
+
+var _ redact.SafeValue = TriggerActionTime(0)
+
+// SafeValue implements redact.SafeValue.
+func (x TriggerActionTime) SafeValue() {}
+
+var _ redact.SafeValue = TriggerEventType(0)
+
+// SafeValue implements redact.SafeValue.
+func (x TriggerEventType) SafeValue() {}
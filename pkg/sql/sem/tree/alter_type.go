@@ -10,15 +10,21 @@
 
 package tree
 
-// AlterType represents an ALTER TYPE statement.
+// AlterType represents an ALTER TYPE or ALTER DOMAIN statement.
 type AlterType struct {
 	Type *UnresolvedObjectName
 	Cmd  AlterTypeCmd
+	// IsDomain is true if this represents an ALTER DOMAIN statement.
+	IsDomain bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *AlterType) Format(ctx *FmtCtx) {
-	ctx.WriteString("ALTER TYPE ")
+	if node.IsDomain {
+		ctx.WriteString("ALTER DOMAIN ")
+	} else {
+		ctx.WriteString("ALTER TYPE ")
+	}
 	ctx.FormatNode(node.Type)
 	ctx.FormatNode(node.Cmd)
 }
@@ -38,12 +44,23 @@ func (*AlterTypeSetSchema) alterTypeCmd()   {}
 func (*AlterTypeOwner) alterTypeCmd()       {}
 func (*AlterTypeDropValue) alterTypeCmd()   {}
 
+func (*AlterDomainSetDefault) alterTypeCmd()         {}
+func (*AlterDomainSetNotNull) alterTypeCmd()         {}
+func (*AlterDomainAddConstraint) alterTypeCmd()      {}
+func (*AlterDomainDropConstraint) alterTypeCmd()     {}
+func (*AlterDomainValidateConstraint) alterTypeCmd() {}
+
 var _ AlterTypeCmd = &AlterTypeAddValue{}
 var _ AlterTypeCmd = &AlterTypeRenameValue{}
 var _ AlterTypeCmd = &AlterTypeRename{}
 var _ AlterTypeCmd = &AlterTypeSetSchema{}
 var _ AlterTypeCmd = &AlterTypeOwner{}
 var _ AlterTypeCmd = &AlterTypeDropValue{}
+var _ AlterTypeCmd = &AlterDomainSetDefault{}
+var _ AlterTypeCmd = &AlterDomainSetNotNull{}
+var _ AlterTypeCmd = &AlterDomainAddConstraint{}
+var _ AlterTypeCmd = &AlterDomainDropConstraint{}
+var _ AlterTypeCmd = &AlterDomainValidateConstraint{}
 
 // AlterTypeAddValue represents an ALTER TYPE ADD VALUE command.
 type AlterTypeAddValue struct {
@@ -163,3 +180,113 @@ func (node *AlterTypeOwner) Format(ctx *FmtCtx) {
 func (node *AlterTypeOwner) TelemetryName() string {
 	return "owner"
 }
+
+// AlterDomainSetDefault represents an ALTER DOMAIN SET DEFAULT or DROP DEFAULT
+// command.
+type AlterDomainSetDefault struct {
+	// Default is nil for DROP DEFAULT.
+	Default Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterDomainSetDefault) Format(ctx *FmtCtx) {
+	if node.Default == nil {
+		ctx.WriteString(" DROP DEFAULT")
+		return
+	}
+	ctx.WriteString(" SET DEFAULT ")
+	ctx.FormatNode(node.Default)
+}
+
+// TelemetryName implements the AlterTypeCmd interface.
+func (node *AlterDomainSetDefault) TelemetryName() string {
+	if node.Default == nil {
+		return "drop_default"
+	}
+	return "set_default"
+}
+
+// AlterDomainSetNotNull represents an ALTER DOMAIN SET NOT NULL or DROP NOT
+// NULL command.
+type AlterDomainSetNotNull struct {
+	NotNull bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterDomainSetNotNull) Format(ctx *FmtCtx) {
+	if node.NotNull {
+		ctx.WriteString(" SET NOT NULL")
+	} else {
+		ctx.WriteString(" DROP NOT NULL")
+	}
+}
+
+// TelemetryName implements the AlterTypeCmd interface.
+func (node *AlterDomainSetNotNull) TelemetryName() string {
+	if node.NotNull {
+		return "set_not_null"
+	}
+	return "drop_not_null"
+}
+
+// AlterDomainAddConstraint represents an ALTER DOMAIN ADD command.
+type AlterDomainAddConstraint struct {
+	Constraint DomainConstraint
+	NotValid   bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterDomainAddConstraint) Format(ctx *FmtCtx) {
+	ctx.WriteString(" ADD ")
+	ctx.FormatNode(&node.Constraint)
+	if node.NotValid {
+		ctx.WriteString(" NOT VALID")
+	}
+}
+
+// TelemetryName implements the AlterTypeCmd interface.
+func (node *AlterDomainAddConstraint) TelemetryName() string {
+	return "add_constraint"
+}
+
+// AlterDomainDropConstraint represents an ALTER DOMAIN DROP CONSTRAINT command.
+type AlterDomainDropConstraint struct {
+	Constraint   Name
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterDomainDropConstraint) Format(ctx *FmtCtx) {
+	ctx.WriteString(" DROP CONSTRAINT ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Constraint)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
+
+// TelemetryName implements the AlterTypeCmd interface.
+func (node *AlterDomainDropConstraint) TelemetryName() string {
+	return "drop_constraint"
+}
+
+// AlterDomainValidateConstraint represents an ALTER DOMAIN VALIDATE CONSTRAINT
+// command.
+type AlterDomainValidateConstraint struct {
+	Constraint Name
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterDomainValidateConstraint) Format(ctx *FmtCtx) {
+	ctx.WriteString(" VALIDATE CONSTRAINT ")
+	ctx.FormatNode(&node.Constraint)
+}
+
+// TelemetryName implements the AlterTypeCmd interface.
+func (node *AlterDomainValidateConstraint) TelemetryName() string {
+	return "validate_constraint"
+}
@@ -143,3 +143,43 @@ func (x CompositeKeyMatchMethod) String() string {
 		return strconv.Itoa(int(x))
 	}
 }
+
+// ConstraintDeferrability specifies whether the checking of a constraint can
+// be deferred until the end of the transaction, and whether it is deferred by
+// default. See https://www.postgresql.org/docs/current/sql-set-constraints.html.
+type ConstraintDeferrability semenumpb.ConstraintDeferrability
+
+// The values for ConstraintDeferrability. It has a one-to-one mapping to
+// semenumpb.ConstraintDeferrability.
+const (
+	ConstraintNotDeferrable ConstraintDeferrability = iota
+	DeferrableInitiallyImmediate
+	DeferrableInitiallyDeferred
+)
+
+// IsDeferrable returns true if the checking of the constraint can be deferred.
+func (x ConstraintDeferrability) IsDeferrable() bool {
+	return x != ConstraintNotDeferrable
+}
+
+// Format implements the NodeFormatter interface.
+func (x *ConstraintDeferrability) Format(ctx *FmtCtx) {
+	if *x != ConstraintNotDeferrable {
+		ctx.WriteByte(' ')
+		ctx.WriteString(x.String())
+	}
+}
+
+// String implements the fmt.Stringer interface.
+func (x ConstraintDeferrability) String() string {
+	switch x {
+	case ConstraintNotDeferrable:
+		return "NOT DEFERRABLE"
+	case DeferrableInitiallyImmediate:
+		return "DEFERRABLE"
+	case DeferrableInitiallyDeferred:
+		return "DEFERRABLE INITIALLY DEFERRED"
+	default:
+		return strconv.Itoa(int(x))
+	}
+}
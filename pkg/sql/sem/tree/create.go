@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/collatedstring"
 	"github.com/cockroachdb/cockroach/pkg/util/pretty"
@@ -233,12 +234,29 @@ type IndexInvisibility struct {
 	FloatProvided bool
 }
 
+// IndexAccessMethod is the index access method named in the USING clause of a
+// CREATE INDEX statement.
+type IndexAccessMethod int8
+
+const (
+	// IndexAccessMethodBTree is the access method of forward indexes. It's the
+	// default.
+	IndexAccessMethodBTree IndexAccessMethod = iota
+	// IndexAccessMethodInverted is the access method of inverted indexes,
+	// named by USING GIN or USING GIST.
+	IndexAccessMethodInverted
+	// IndexAccessMethodVector is the access method of vector indexes, named by
+	// USING IVFFLAT.
+	IndexAccessMethodVector
+)
+
 // CreateIndex represents a CREATE INDEX statement.
 type CreateIndex struct {
 	Name        Name
 	Table       TableName
 	Unique      bool
 	Inverted    bool
+	Vector      bool // Vector indexes are inverted indexes; Inverted is also set.
 	IfNotExists bool
 	Columns     IndexElemList
 	Sharded     *ShardedIndexDef
@@ -261,7 +279,9 @@ func (node *CreateIndex) Format(ctx *FmtCtx) {
 	if node.Unique {
 		ctx.WriteString("UNIQUE ")
 	}
-	if node.Inverted {
+	if node.Vector {
+		ctx.WriteString("VECTOR ")
+	} else if node.Inverted {
 		ctx.WriteString("INVERTED ")
 	}
 	ctx.WriteString("INDEX ")
@@ -374,6 +394,11 @@ type CreateType struct {
 	// CompositeTypeList is set when this repesnets a CREATE TYPE ... AS ( )
 	// statement.
 	CompositeTypeList []CompositeTypeElem
+	// DomainType, DomainDefault and DomainConstraints are set when this
+	// represents a CREATE DOMAIN statement.
+	DomainType        ResolvableTypeReference
+	DomainDefault     Expr
+	DomainConstraints []DomainConstraint
 	// IfNotExists is true if IF NOT EXISTS was requested.
 	IfNotExists bool
 }
@@ -382,6 +407,10 @@ var _ Statement = &CreateType{}
 
 // Format implements the NodeFormatter interface.
 func (node *CreateType) Format(ctx *FmtCtx) {
+	if node.Variety == Domain {
+		node.formatDomain(ctx)
+		return
+	}
 	ctx.WriteString("CREATE TYPE ")
 	if node.IfNotExists {
 		ctx.WriteString("IF NOT EXISTS ")
@@ -408,10 +437,56 @@ func (node *CreateType) Format(ctx *FmtCtx) {
 	}
 }
 
+func (node *CreateType) formatDomain(ctx *FmtCtx) {
+	ctx.WriteString("CREATE DOMAIN ")
+	ctx.FormatNode(node.TypeName)
+	ctx.WriteString(" AS ")
+	ctx.FormatTypeReference(node.DomainType)
+	if node.DomainDefault != nil {
+		ctx.WriteString(" DEFAULT ")
+		ctx.FormatNode(node.DomainDefault)
+	}
+	for i := range node.DomainConstraints {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(&node.DomainConstraints[i])
+	}
+}
+
 func (node *CreateType) String() string {
 	return AsString(node)
 }
 
+// DomainConstraint represents a constraint of a domain, as specified in a
+// CREATE DOMAIN or ALTER DOMAIN ... ADD statement. It is either NOT NULL,
+// NULL, or a CHECK constraint.
+type DomainConstraint struct {
+	Name    Name
+	NotNull bool
+	Null    bool
+	// Check is the expression of a CHECK constraint, in which the value being
+	// checked is referred to as VALUE.
+	Check Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DomainConstraint) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.WriteString("CONSTRAINT ")
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	switch {
+	case node.NotNull:
+		ctx.WriteString("NOT NULL")
+	case node.Null:
+		ctx.WriteString("NULL")
+	default:
+		ctx.WriteString("CHECK (")
+		ctx.FormatNode(node.Check)
+		ctx.WriteByte(')')
+	}
+}
+
 // TableDef represents a column, index or constraint definition within a CREATE
 // TABLE statement.
 type TableDef interface {
@@ -518,6 +593,7 @@ type ColumnTableDef struct {
 		ConstraintName Name
 		Actions        ReferenceActions
 		Match          CompositeKeyMatchMethod
+		Deferrable     ConstraintDeferrability
 	}
 	Computed struct {
 		Computed bool
@@ -701,6 +777,7 @@ func NewColumnTableDef(
 			d.References.ConstraintName = c.Name
 			d.References.Actions = t.Actions
 			d.References.Match = t.Match
+			d.References.Deferrable = t.Deferrable
 		case *ColumnComputedDef:
 			if d.GeneratedIdentity.IsGeneratedAsIdentity {
 				return nil, pgerror.Newf(pgcode.Syntax,
@@ -871,6 +948,7 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 			ctx.WriteString(node.References.Match.String())
 		}
 		ctx.FormatNode(&node.References.Actions)
+		ctx.FormatNode(&node.References.Deferrable)
 	}
 	if node.IsComputed() {
 		ctx.WriteString(" AS (")
@@ -1009,10 +1087,11 @@ type ColumnCheckConstraint struct {
 
 // ColumnFKConstraint represents a FK-constaint on a column.
 type ColumnFKConstraint struct {
-	Table   TableName
-	Col     Name // empty-string means use PK
-	Actions ReferenceActions
-	Match   CompositeKeyMatchMethod
+	Table      TableName
+	Col        Name // empty-string means use PK
+	Actions    ReferenceActions
+	Match      CompositeKeyMatchMethod
+	Deferrable ConstraintDeferrability
 }
 
 // ColumnComputedDef represents the description of a computed column.
@@ -1036,6 +1115,7 @@ type IndexTableDef struct {
 	Sharded          *ShardedIndexDef
 	Storing          NameList
 	Inverted         bool
+	Vector           bool // Vector indexes are inverted indexes; Inverted is also set.
 	PartitionByIndex *PartitionByIndex
 	StorageParams    StorageParams
 	Predicate        Expr
@@ -1044,7 +1124,9 @@ type IndexTableDef struct {
 
 // Format implements the NodeFormatter interface.
 func (node *IndexTableDef) Format(ctx *FmtCtx) {
-	if node.Inverted {
+	if node.Vector {
+		ctx.WriteString("VECTOR ")
+	} else if node.Inverted {
 		ctx.WriteString("INVERTED ")
 	}
 	ctx.WriteString("INDEX ")
@@ -1102,6 +1184,7 @@ type ConstraintTableDef interface {
 func (*UniqueConstraintTableDef) constraintTableDef()     {}
 func (*ForeignKeyConstraintTableDef) constraintTableDef() {}
 func (*CheckConstraintTableDef) constraintTableDef()      {}
+func (*ExclusionConstraintTableDef) constraintTableDef()  {}
 
 // UniqueConstraintTableDef represents a unique constraint within a CREATE
 // TABLE statement.
@@ -1109,6 +1192,7 @@ type UniqueConstraintTableDef struct {
 	IndexTableDef
 	PrimaryKey   bool
 	WithoutIndex bool
+	Deferrable   ConstraintDeferrability
 	IfNotExists  bool
 }
 
@@ -1154,6 +1238,7 @@ func (node *UniqueConstraintTableDef) Format(ctx *FmtCtx) {
 	if node.PartitionByIndex != nil {
 		ctx.FormatNode(node.PartitionByIndex)
 	}
+	ctx.FormatNode(&node.Deferrable)
 	if node.Predicate != nil {
 		ctx.WriteString(" WHERE ")
 		ctx.FormatNode(node.Predicate)
@@ -1179,6 +1264,7 @@ type ForeignKeyConstraintTableDef struct {
 	ToCols      NameList
 	Actions     ReferenceActions
 	Match       CompositeKeyMatchMethod
+	Deferrable  ConstraintDeferrability
 	IfNotExists bool
 }
 
@@ -1210,6 +1296,7 @@ func (node *ForeignKeyConstraintTableDef) Format(ctx *FmtCtx) {
 	}
 
 	ctx.FormatNode(&node.Actions)
+	ctx.FormatNode(&node.Deferrable)
 }
 
 // SetName implements the ConstraintTableDef interface.
@@ -1256,6 +1343,56 @@ func (node *CheckConstraintTableDef) Format(ctx *FmtCtx) {
 	ctx.WriteByte(')')
 }
 
+// ExclusionConstraintTableDef represents an EXCLUDE constraint within a CREATE
+// TABLE statement. The constraint is backed by the index described by the
+// embedded IndexTableDef; each of its columns is paired with the operator at
+// the same position in Operators.
+type ExclusionConstraintTableDef struct {
+	IndexTableDef
+	Operators   []treecmp.ComparisonOperator
+	IfNotExists bool
+}
+
+// SetName implements the ConstraintTableDef interface.
+func (node *ExclusionConstraintTableDef) SetName(name Name) {
+	node.Name = name
+}
+
+// SetIfNotExists implements the ConstraintTableDef interface.
+func (node *ExclusionConstraintTableDef) SetIfNotExists() {
+	node.IfNotExists = true
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ExclusionConstraintTableDef) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.WriteString("CONSTRAINT ")
+		if node.IfNotExists {
+			ctx.WriteString("IF NOT EXISTS ")
+		}
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	ctx.WriteString("EXCLUDE ")
+	if node.Inverted {
+		ctx.WriteString("USING GIST ")
+	}
+	ctx.WriteByte('(')
+	for i := range node.Columns {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&node.Columns[i])
+		ctx.WriteString(" WITH ")
+		ctx.WriteString(node.Operators[i].String())
+	}
+	ctx.WriteByte(')')
+	if node.Predicate != nil {
+		ctx.WriteString(" WHERE ")
+		ctx.FormatNode(node.Predicate)
+	}
+}
+
 // FamilyTableDef represents a family definition within a CREATE TABLE
 // statement.
 type FamilyTableDef struct {
@@ -1620,12 +1757,13 @@ func (node *CreateTable) HoistConstraints() {
 					targetCol = append(targetCol, col.References.Col)
 				}
 				node.Defs = append(node.Defs, &ForeignKeyConstraintTableDef{
-					Table:    *col.References.Table,
-					FromCols: NameList{col.Name},
-					ToCols:   targetCol,
-					Name:     col.References.ConstraintName,
-					Actions:  col.References.Actions,
-					Match:    col.References.Match,
+					Table:      *col.References.Table,
+					FromCols:   NameList{col.Name},
+					ToCols:     targetCol,
+					Name:       col.References.ConstraintName,
+					Actions:    col.References.Actions,
+					Match:      col.References.Match,
+					Deferrable: col.References.Deferrable,
 				})
 				col.References.Table = nil
 			}
@@ -1996,6 +2134,10 @@ type RefreshMaterializedView struct {
 	Name              *UnresolvedObjectName
 	Concurrently      bool
 	RefreshDataOption RefreshDataOption
+	// Incremental is set if the view should be refreshed by applying the
+	// changes to its base tables since the last refresh, rather than by
+	// recomputing the view query.
+	Incremental bool
 }
 
 // RefreshDataOption corresponds to arguments for the REFRESH MATERIALIZED VIEW
@@ -2027,6 +2169,9 @@ func (node *RefreshMaterializedView) Format(ctx *FmtCtx) {
 	case RefreshDataClear:
 		ctx.WriteString(" WITH NO DATA")
 	}
+	if node.Incremental {
+		ctx.WriteString(" WITH (incremental)")
+	}
 }
 
 // CreateStats represents a CREATE STATISTICS statement.
@@ -2051,11 +2196,39 @@ func (node *CreateStats) Format(ctx *FmtCtx) {
 	ctx.FormatNode(node.Table)
 
 	if !node.Options.Empty() {
-		ctx.WriteString(" WITH OPTIONS")
+		// The kinds of statistics are formatted first, and the options can follow
+		// them without WITH OPTIONS.
+		if len(node.Options.Kinds) == 0 {
+			ctx.WriteString(" WITH OPTIONS")
+		}
 		ctx.FormatNode(&node.Options)
 	}
 }
 
+// Kinds of statistics that can be requested with
+// CREATE STATISTICS ... WITH (<kind>, ...).
+const (
+	// CreateStatsKindHistogram requests a histogram over the values of the
+	// columns.
+	CreateStatsKindHistogram = "histogram"
+	// CreateStatsKindDependencies requests the functional dependencies between
+	// pairs of the columns.
+	CreateStatsKindDependencies = "dependencies"
+	// CreateStatsKindMostCommonValues requests the most common values of the
+	// columns.
+	CreateStatsKindMostCommonValues = "mcv"
+)
+
+// IsCreateStatsKind returns true if the given name is a kind of statistics
+// that can be requested with CREATE STATISTICS.
+func IsCreateStatsKind(kind Name) bool {
+	switch kind {
+	case CreateStatsKindHistogram, CreateStatsKindDependencies, CreateStatsKindMostCommonValues:
+		return true
+	}
+	return false
+}
+
 // CreateStatsOptions contains options for CREATE STATISTICS.
 type CreateStatsOptions struct {
 	// Throttling enables throttling and indicates the fraction of time we are
@@ -2074,15 +2247,40 @@ type CreateStatsOptions struct {
 	// Where will specify statistics collection in a set of rows of the table
 	// or index specified.
 	Where *Where
+
+	// Kinds are the kinds of statistics to collect on the columns (see
+	// CreateStatsKindHistogram, etc.). If empty, the default statistics are
+	// collected.
+	Kinds NameList
 }
 
 // Empty returns true if no options were provided.
 func (o *CreateStatsOptions) Empty() bool {
-	return o.Throttling == 0 && o.AsOf.Expr == nil && o.Where == nil && !o.UsingExtremes
+	return o.Throttling == 0 && o.AsOf.Expr == nil && o.Where == nil && !o.UsingExtremes &&
+		len(o.Kinds) == 0
+}
+
+// HasKind returns true if the given kind of statistics was requested.
+func (o *CreateStatsOptions) HasKind(kind Name) bool {
+	for _, k := range o.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
 }
 
 // Format implements the NodeFormatter interface.
 func (o *CreateStatsOptions) Format(ctx *FmtCtx) {
+	if len(o.Kinds) > 0 {
+		ctx.WriteString(" WITH (")
+		// NB: we do not anonymize the kinds of statistics, which are keywords
+		// rather than identifiers.
+		ctx.WithFlags(ctx.flags&^FmtAnonymize, func() {
+			ctx.FormatNode(&o.Kinds)
+		})
+		ctx.WriteByte(')')
+	}
 	if o.UsingExtremes {
 		ctx.WriteString(" USING EXTREMES")
 	}
@@ -2136,6 +2334,12 @@ func (o *CreateStatsOptions) CombineWith(other *CreateStatsOptions) error {
 		}
 		o.Where = other.Where
 	}
+	if len(other.Kinds) > 0 {
+		if len(o.Kinds) > 0 {
+			return errors.New("statistics kinds specified multiple times")
+		}
+		o.Kinds = other.Kinds
+	}
 	if other.Where != nil && o.UsingExtremes || o.Where != nil && other.UsingExtremes {
 		return errors.New("USING EXTREMES and WHERE may not be specified together")
 	}
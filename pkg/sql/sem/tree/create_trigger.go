@@ -10,6 +10,8 @@
 
 package tree
 
+import "github.com/cockroachdb/cockroach/pkg/sql/sem/semenumpb"
+
 type CreateTrigger struct {
 	Replace     bool
 	Name        Name
@@ -117,6 +119,42 @@ func (node *TriggerEventType) Format(ctx *FmtCtx) {
 	}
 }
 
+// TriggerActionTimeFromProto allows the conversion from a
+// semenumpb.TriggerActionTime to a tree.TriggerActionTime.
+var TriggerActionTimeFromProto = map[semenumpb.TriggerActionTime]TriggerActionTime{
+	semenumpb.TriggerActionTime_BEFORE:     TriggerActionTimeBefore,
+	semenumpb.TriggerActionTime_AFTER:      TriggerActionTimeAfter,
+	semenumpb.TriggerActionTime_INSTEAD_OF: TriggerActionTimeInsteadOf,
+}
+
+// TriggerActionTimeToProto allows the conversion from a tree.TriggerActionTime
+// to a semenumpb.TriggerActionTime.
+var TriggerActionTimeToProto = map[TriggerActionTime]semenumpb.TriggerActionTime{
+	TriggerActionTimeBefore:    semenumpb.TriggerActionTime_BEFORE,
+	TriggerActionTimeAfter:     semenumpb.TriggerActionTime_AFTER,
+	TriggerActionTimeInsteadOf: semenumpb.TriggerActionTime_INSTEAD_OF,
+}
+
+// TriggerEventTypeFromProto allows the conversion from a
+// semenumpb.TriggerEventType to a tree.TriggerEventType.
+var TriggerEventTypeFromProto = map[semenumpb.TriggerEventType]TriggerEventType{
+	semenumpb.TriggerEventType_INSERT:   TriggerEventInsert,
+	semenumpb.TriggerEventType_UPDATE:   TriggerEventUpdate,
+	semenumpb.TriggerEventType_DELETE:   TriggerEventDelete,
+	semenumpb.TriggerEventType_TRUNCATE: TriggerEventTruncate,
+	semenumpb.TriggerEventType_UPSERT:   TriggerEventUpsert,
+}
+
+// TriggerEventTypeToProto allows the conversion from a tree.TriggerEventType to
+// a semenumpb.TriggerEventType.
+var TriggerEventTypeToProto = map[TriggerEventType]semenumpb.TriggerEventType{
+	TriggerEventInsert:   semenumpb.TriggerEventType_INSERT,
+	TriggerEventUpdate:   semenumpb.TriggerEventType_UPDATE,
+	TriggerEventDelete:   semenumpb.TriggerEventType_DELETE,
+	TriggerEventTruncate: semenumpb.TriggerEventType_TRUNCATE,
+	TriggerEventUpsert:   semenumpb.TriggerEventType_UPSERT,
+}
+
 type TriggerEvent struct {
 	EventType TriggerEventType
 	Columns   NameList
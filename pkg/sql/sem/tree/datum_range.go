@@ -0,0 +1,934 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// RangeBound is one of the two bounds of a DRange.
+type RangeBound struct {
+	// Val is the value of the bound, or nil if the bound is infinite.
+	Val Datum
+	// Inclusive is true if Val is itself part of the range. It is always false
+	// for infinite bounds.
+	Inclusive bool
+}
+
+// IsInfinite returns true if the bound is unbounded.
+func (b RangeBound) IsInfinite() bool {
+	return b.Val == nil
+}
+
+// DRange is the Datum for the range types, like INT4RANGE and TSTZRANGE. A
+// range is either empty, or the set of values of its element type between its
+// lower and upper bounds. Ranges are always kept in canonical form: ranges of
+// the discrete types (INT4RANGE, INT8RANGE and DATERANGE) use an inclusive
+// lower bound and an exclusive upper bound, so that two equal ranges always
+// have the same representation.
+type DRange struct {
+	typ   *types.T
+	Lower RangeBound
+	Upper RangeBound
+	Empty bool
+}
+
+var malformedRangeError = pgerror.New(pgcode.InvalidTextRepresentation, "malformed range literal")
+var malformedMultirangeError = pgerror.New(pgcode.InvalidTextRepresentation, "malformed multirange literal")
+
+// rangeEmptyLiteral is the text representation of an empty range.
+const rangeEmptyLiteral = "empty"
+
+// NewDRange returns a new range of the given type with the given bounds, in
+// canonical form. An error is returned if the lower bound is greater than
+// the upper bound.
+func NewDRange(typ *types.T, lower, upper RangeBound) (*DRange, error) {
+	if lower.IsInfinite() {
+		lower.Inclusive = false
+	}
+	if upper.IsInfinite() {
+		upper.Inclusive = false
+	}
+	if !lower.IsInfinite() && !upper.IsInfinite() {
+		if c := compareRangeValues(lower.Val, upper.Val); c > 0 {
+			return nil, pgerror.New(pgcode.DataException,
+				"range lower bound must be less than or equal to range upper bound")
+		} else if c == 0 && !(lower.Inclusive && upper.Inclusive) {
+			return NewDEmptyRange(typ), nil
+		}
+	}
+	// Discrete ranges are canonicalized to the [) form.
+	var err error
+	if !lower.IsInfinite() && !lower.Inclusive && isCanonicalizableBound(typ, lower.Val) {
+		if lower.Val, err = nextDiscreteRangeValue(typ, lower.Val); err != nil {
+			return nil, err
+		}
+		lower.Inclusive = true
+	}
+	if !upper.IsInfinite() && upper.Inclusive && isCanonicalizableBound(typ, upper.Val) {
+		if upper.Val, err = nextDiscreteRangeValue(typ, upper.Val); err != nil {
+			return nil, err
+		}
+		upper.Inclusive = false
+	}
+	if !lower.IsInfinite() && !upper.IsInfinite() {
+		// Canonicalization can make the range empty, like (1,2).
+		if c := compareRangeValues(lower.Val, upper.Val); c == 0 && !(lower.Inclusive && upper.Inclusive) {
+			return NewDEmptyRange(typ), nil
+		}
+	}
+	return &DRange{typ: typ, Lower: lower, Upper: upper}, nil
+}
+
+// isCanonicalizableBound returns true if the given bound value of a range of
+// type typ is adjusted to the [) form. Like in Postgres, the infinite dates
+// are left as they are.
+func isCanonicalizableBound(typ *types.T, v Datum) bool {
+	switch typ.Oid() {
+	case oid.T_int4range, oid.T_int8range:
+		return true
+	case oid.T_daterange:
+		return v.(*DDate).IsFinite()
+	}
+	return false
+}
+
+// nextDiscreteRangeValue returns the value following v, which is a bound of
+// a range of a discrete type.
+func nextDiscreteRangeValue(typ *types.T, v Datum) (Datum, error) {
+	switch typ.Oid() {
+	case oid.T_int4range:
+		i := MustBeDInt(v)
+		if i >= math.MaxInt32 {
+			return nil, ErrInt4OutOfRange
+		}
+		return NewDInt(i + 1), nil
+	case oid.T_int8range:
+		i := MustBeDInt(v)
+		if i == math.MaxInt64 {
+			return nil, ErrIntOutOfRange
+		}
+		return NewDInt(i + 1), nil
+	case oid.T_daterange:
+		next, err := v.(*DDate).AddDays(1)
+		if err != nil {
+			return nil, err
+		}
+		return NewDDate(next), nil
+	}
+	return nil, errors.AssertionFailedf("unexpected discrete range type %s", typ.SQLStringForError())
+}
+
+// NewDEmptyRange returns a new empty range of the given type.
+func NewDEmptyRange(typ *types.T) *DRange {
+	return &DRange{typ: typ, Empty: true}
+}
+
+// AsDRange attempts to retrieve a *DRange from an Expr, returning a *DRange
+// and a flag signifying whether the assertion was successful. The function
+// should be used instead of direct type assertions wherever a *DRange
+// wrapped by a *DOidWrapper is possible.
+func AsDRange(e Expr) (*DRange, bool) {
+	switch t := e.(type) {
+	case *DRange:
+		return t, true
+	case *DOidWrapper:
+		return AsDRange(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDRange attempts to retrieve a *DRange from an Expr, panicking if the
+// assertion fails.
+func MustBeDRange(e Expr) *DRange {
+	r, ok := AsDRange(e)
+	if !ok {
+		panic(errors.AssertionFailedf("expected *DRange, found %T", e))
+	}
+	return r
+}
+
+// ParseDRangeFromString parses the string-form of a range, like '[1,10)', as
+// a range of type t.
+//
+// The dependsOnContext return value indicates if we had to consult the
+// ParseContext (either for the time or the local timezone).
+func ParseDRangeFromString(
+	ctx ParseContext, s string, t *types.T,
+) (_ *DRange, dependsOnContext bool, _ error) {
+	r, rest, dependsOnContext, err := parseRangePrefix(ctx, s, t)
+	if err == nil && strings.TrimSpace(rest) != "" {
+		err = errors.WithDetail(malformedRangeError, "Junk after right parenthesis or bracket.")
+	}
+	if err != nil {
+		return nil, false, MakeParseError(s, t, err)
+	}
+	return r, dependsOnContext, nil
+}
+
+// parseRangePrefix parses the range at the start of s, and returns the rest
+// of the string.
+func parseRangePrefix(
+	ctx ParseContext, s string, t *types.T,
+) (_ *DRange, rest string, dependsOnContext bool, _ error) {
+	s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	if len(s) >= len(rangeEmptyLiteral) && strings.EqualFold(s[:len(rangeEmptyLiteral)], rangeEmptyLiteral) {
+		return NewDEmptyRange(t), s[len(rangeEmptyLiteral):], false, nil
+	}
+	if s == "" || (s[0] != '[' && s[0] != '(') {
+		return nil, "", false, errors.WithDetail(malformedRangeError, "Missing left parenthesis or bracket.")
+	}
+	lowerInc := s[0] == '['
+	lowerStr, lowerInf, s, err := parseRangeBound(s[1:])
+	if err != nil {
+		return nil, "", false, err
+	}
+	if s == "" || s[0] != ',' {
+		return nil, "", false, errors.WithDetail(malformedRangeError, "Missing comma after lower bound.")
+	}
+	upperStr, upperInf, s, err := parseRangeBound(s[1:])
+	if err != nil {
+		return nil, "", false, err
+	}
+	if s == "" || s[0] == ',' {
+		return nil, "", false, errors.WithDetail(malformedRangeError, "Too many commas.")
+	}
+	upperInc := s[0] == ']'
+
+	elemTyp := t.RangeContents()
+	var lower, upper RangeBound
+	if !lowerInf {
+		v, dep, err := ParseAndRequireString(elemTyp, lowerStr, ctx)
+		if err != nil {
+			return nil, "", false, err
+		}
+		dependsOnContext = dependsOnContext || dep
+		lower = RangeBound{Val: v, Inclusive: lowerInc}
+	}
+	if !upperInf {
+		v, dep, err := ParseAndRequireString(elemTyp, upperStr, ctx)
+		if err != nil {
+			return nil, "", false, err
+		}
+		dependsOnContext = dependsOnContext || dep
+		upper = RangeBound{Val: v, Inclusive: upperInc}
+	}
+	r, err := NewDRange(t, lower, upper)
+	if err != nil {
+		return nil, "", false, err
+	}
+	return r, s[1:], dependsOnContext, nil
+}
+
+// parseRangeBound parses a bound of a range literal, up to the next
+// unquoted comma, parenthesis or bracket. Like in Postgres, characters can
+// be quoted with double quotes or escaped with a backslash, and a bound that
+// is empty and unquoted is infinite.
+func parseRangeBound(s string) (val string, infinite bool, rest string, _ error) {
+	var b strings.Builder
+	inQuote, hadQuote := false, false
+	i := 0
+	for ; i < len(s); i++ {
+		ch := s[i]
+		if !inQuote && (ch == ',' || ch == ')' || ch == ']') {
+			break
+		}
+		switch ch {
+		case '\\':
+			i++
+			if i == len(s) {
+				return "", false, "", errors.WithDetail(malformedRangeError, "Unexpected end of input.")
+			}
+			b.WriteByte(s[i])
+		case '"':
+			if inQuote && i+1 < len(s) && s[i+1] == '"' {
+				// Inside quotes, a doubled double quote is a literal double quote.
+				b.WriteByte('"')
+				i++
+			} else {
+				inQuote = !inQuote
+				hadQuote = true
+			}
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	if inQuote || i == len(s) {
+		return "", false, "", errors.WithDetail(malformedRangeError, "Unexpected end of input.")
+	}
+	return b.String(), b.Len() == 0 && !hadQuote, s[i:], nil
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (d *DRange) ResolvedType() *types.T {
+	return d.typ
+}
+
+// Compare implements the Datum interface. Like in Postgres, empty ranges sort
+// before all other ranges, and other ranges are ordered by their lower bound
+// and then by their upper bound.
+func (d *DRange) Compare(ctx context.Context, cmpCtx CompareContext, other Datum) (int, error) {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1, nil
+	}
+	v, ok := cmpCtx.UnwrapDatum(ctx, other).(*DRange)
+	if !ok || !d.typ.Equivalent(v.typ) {
+		return 0, makeUnsupportedComparisonMessage(d, other)
+	}
+	return d.compare(v), nil
+}
+
+func (d *DRange) compare(v *DRange) int {
+	switch {
+	case d.Empty && v.Empty:
+		return 0
+	case d.Empty:
+		return -1
+	case v.Empty:
+		return 1
+	}
+	if c := compareRangeBounds(d.Lower, true /* aLower */, v.Lower, true /* bLower */); c != 0 {
+		return c
+	}
+	return compareRangeBounds(d.Upper, false /* aLower */, v.Upper, false /* bLower */)
+}
+
+// Prev implements the Datum interface.
+func (d *DRange) Prev(ctx context.Context, cmpCtx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DRange) Next(ctx context.Context, cmpCtx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DRange) IsMax(ctx context.Context, cmpCtx CompareContext) bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DRange) IsMin(ctx context.Context, cmpCtx CompareContext) bool {
+	return d.Empty
+}
+
+// Max implements the Datum interface.
+func (d *DRange) Max(ctx context.Context, cmpCtx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DRange) Min(ctx context.Context, cmpCtx CompareContext) (Datum, bool) {
+	return NewDEmptyRange(d.typ), true
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DRange) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DRange) Format(ctx *FmtCtx) {
+	bareStrings := ctx.HasFlags(FmtFlags(lexbase.EncBareStrings))
+	if !bareStrings {
+		ctx.WriteByte('\'')
+	}
+	d.formatBare(ctx)
+	if !bareStrings {
+		ctx.WriteByte('\'')
+	}
+}
+
+// formatBare writes the text representation of the range, like [1,10), to
+// ctx without any surrounding quotes.
+func (d *DRange) formatBare(ctx *FmtCtx) {
+	if d.Empty {
+		ctx.WriteString(rangeEmptyLiteral)
+		return
+	}
+	if d.Lower.Inclusive {
+		ctx.WriteByte('[')
+	} else {
+		ctx.WriteByte('(')
+	}
+	// The bounds are printed like the elements of a tuple: in their bare text
+	// form, and quoted if needed.
+	flags := FmtBareStrings | (ctx.flags & fmtPgwireFormat)
+	if !d.Lower.IsInfinite() {
+		s := AsStringWithFlags(d.Lower.Val, flags, FmtDataConversionConfig(ctx.dataConversionConfig), FmtLocation(ctx.location))
+		pgwireFormatStringInRange(&ctx.Buffer, s)
+	}
+	ctx.WriteByte(',')
+	if !d.Upper.IsInfinite() {
+		s := AsStringWithFlags(d.Upper.Val, flags, FmtDataConversionConfig(ctx.dataConversionConfig), FmtLocation(ctx.location))
+		pgwireFormatStringInRange(&ctx.Buffer, s)
+	}
+	if d.Upper.Inclusive {
+		ctx.WriteByte(']')
+	} else {
+		ctx.WriteByte(')')
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DRange) Size() uintptr {
+	sz := unsafe.Sizeof(*d)
+	if !d.Lower.IsInfinite() {
+		sz += d.Lower.Val.Size()
+	}
+	if !d.Upper.IsInfinite() {
+		sz += d.Upper.Val.Size()
+	}
+	return sz
+}
+
+// IsComposite implements the CompositeDatum interface.
+func (d *DRange) IsComposite() bool {
+	for _, b := range [...]RangeBound{d.Lower, d.Upper} {
+		if cdatum, ok := b.Val.(CompositeDatum); ok && cdatum.IsComposite() {
+			return true
+		}
+	}
+	return false
+}
+
+// compareRangeValues compares two values of the element type of a range.
+// Unlike Datum.Compare, it doesn't need a context, since the element types
+// of ranges don't depend on the session for comparison.
+func compareRangeValues(a, b Datum) int {
+	switch t := a.(type) {
+	case *DInt:
+		u := MustBeDInt(b)
+		switch {
+		case *t < u:
+			return -1
+		case *t > u:
+			return 1
+		}
+		return 0
+	case *DDecimal:
+		return CompareDecimals(&t.Decimal, &b.(*DDecimal).Decimal)
+	case *DDate:
+		return t.Date.Compare(b.(*DDate).Date)
+	case *DTimestamp:
+		return t.Time.Compare(b.(*DTimestamp).Time)
+	case *DTimestampTZ:
+		return t.Time.Compare(b.(*DTimestampTZ).Time)
+	}
+	panic(errors.AssertionFailedf("unsupported range element %T", a))
+}
+
+// compareRangeBounds compares two range bounds, which are lower bounds if
+// aLower or bLower are true, and upper bounds otherwise. It follows
+// range_cmp_bounds in Postgres: an infinite lower bound is less than
+// everything else, an infinite upper bound is greater than everything else,
+// and for equal values an exclusive lower bound sorts after an inclusive one
+// while an exclusive upper bound sorts before an inclusive one.
+func compareRangeBounds(a RangeBound, aLower bool, b RangeBound, bLower bool) int {
+	switch {
+	case a.IsInfinite() && b.IsInfinite():
+		if aLower == bLower {
+			return 0
+		}
+		if aLower {
+			return -1
+		}
+		return 1
+	case a.IsInfinite():
+		if aLower {
+			return -1
+		}
+		return 1
+	case b.IsInfinite():
+		if bLower {
+			return 1
+		}
+		return -1
+	}
+	c := compareRangeValues(a.Val, b.Val)
+	if c != 0 {
+		return c
+	}
+	switch {
+	case !a.Inclusive && !b.Inclusive:
+		if aLower == bLower {
+			return 0
+		}
+		if aLower {
+			return 1
+		}
+		return -1
+	case !a.Inclusive:
+		if aLower {
+			return 1
+		}
+		return -1
+	case !b.Inclusive:
+		if bLower {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// rangeBoundsAdjacent returns true if the given upper bound and lower bound
+// touch without overlapping, like the bounds of [1,3) and [3,5).
+func rangeBoundsAdjacent(upper, lower RangeBound) bool {
+	if upper.IsInfinite() || lower.IsInfinite() {
+		return false
+	}
+	return upper.Inclusive != lower.Inclusive && compareRangeValues(upper.Val, lower.Val) == 0
+}
+
+// ContainsValue returns true if the given value of the element type of the
+// range is part of the range.
+func (d *DRange) ContainsValue(v Datum) bool {
+	if d.Empty {
+		return false
+	}
+	if !d.Lower.IsInfinite() {
+		c := compareRangeValues(d.Lower.Val, v)
+		if c > 0 || (c == 0 && !d.Lower.Inclusive) {
+			return false
+		}
+	}
+	if !d.Upper.IsInfinite() {
+		c := compareRangeValues(d.Upper.Val, v)
+		if c < 0 || (c == 0 && !d.Upper.Inclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsRange returns true if every value of the other range is part of
+// the range. Every range contains the empty range.
+func (d *DRange) ContainsRange(o *DRange) bool {
+	if o.Empty {
+		return true
+	}
+	if d.Empty {
+		return false
+	}
+	return compareRangeBounds(d.Lower, true, o.Lower, true) <= 0 &&
+		compareRangeBounds(d.Upper, false, o.Upper, false) >= 0
+}
+
+// Overlaps returns true if the two ranges have values in common.
+func (d *DRange) Overlaps(o *DRange) bool {
+	if d.Empty || o.Empty {
+		return false
+	}
+	if compareRangeBounds(d.Lower, true, o.Lower, true) >= 0 &&
+		compareRangeBounds(d.Lower, true, o.Upper, false) <= 0 {
+		return true
+	}
+	return compareRangeBounds(o.Lower, true, d.Lower, true) >= 0 &&
+		compareRangeBounds(o.Lower, true, d.Upper, false) <= 0
+}
+
+// Adjacent returns true if the two ranges touch without overlapping.
+func (d *DRange) Adjacent(o *DRange) bool {
+	if d.Empty || o.Empty {
+		return false
+	}
+	return rangeBoundsAdjacent(d.Upper, o.Lower) || rangeBoundsAdjacent(o.Upper, d.Lower)
+}
+
+// Merge returns the smallest range that includes both ranges, like the
+// range_merge builtin.
+func (d *DRange) Merge(o *DRange) *DRange {
+	if d.Empty {
+		return o
+	}
+	if o.Empty {
+		return d
+	}
+	r := &DRange{typ: d.typ, Lower: d.Lower, Upper: d.Upper}
+	if compareRangeBounds(o.Lower, true, r.Lower, true) < 0 {
+		r.Lower = o.Lower
+	}
+	if compareRangeBounds(o.Upper, false, r.Upper, false) > 0 {
+		r.Upper = o.Upper
+	}
+	return r
+}
+
+// Union returns the union of the two ranges, which is an error if the
+// ranges neither overlap nor are adjacent.
+func (d *DRange) Union(o *DRange) (*DRange, error) {
+	if !d.Empty && !o.Empty && !d.Overlaps(o) && !d.Adjacent(o) {
+		return nil, pgerror.New(pgcode.DataException, "result of range union would not be contiguous")
+	}
+	return d.Merge(o), nil
+}
+
+// Intersect returns the intersection of the two ranges.
+func (d *DRange) Intersect(o *DRange) *DRange {
+	if !d.Overlaps(o) {
+		return NewDEmptyRange(d.typ)
+	}
+	r := &DRange{typ: d.typ, Lower: d.Lower, Upper: d.Upper}
+	if compareRangeBounds(o.Lower, true, r.Lower, true) > 0 {
+		r.Lower = o.Lower
+	}
+	if compareRangeBounds(o.Upper, false, r.Upper, false) < 0 {
+		r.Upper = o.Upper
+	}
+	return r
+}
+
+// Difference returns the values of the range that are not in the other
+// range, which is an error if the result would consist of two ranges.
+func (d *DRange) Difference(o *DRange) (*DRange, error) {
+	res, err := d.subtract(o)
+	if err != nil {
+		return nil, err
+	}
+	switch len(res) {
+	case 0:
+		return NewDEmptyRange(d.typ), nil
+	case 1:
+		return res[0], nil
+	}
+	return nil, pgerror.New(pgcode.DataException, "result of range difference would not be contiguous")
+}
+
+// subtract returns the non-empty ranges that make up the values of the range
+// that are not in the other range. There are at most two of them, in order.
+func (d *DRange) subtract(o *DRange) ([]*DRange, error) {
+	if d.Empty {
+		return nil, nil
+	}
+	if !d.Overlaps(o) {
+		return []*DRange{d}, nil
+	}
+	var res []*DRange
+	if compareRangeBounds(d.Lower, true, o.Lower, true) < 0 {
+		r, err := NewDRange(d.typ, d.Lower, RangeBound{Val: o.Lower.Val, Inclusive: !o.Lower.Inclusive})
+		if err != nil {
+			return nil, err
+		}
+		if !r.Empty {
+			res = append(res, r)
+		}
+	}
+	if compareRangeBounds(d.Upper, false, o.Upper, false) > 0 {
+		r, err := NewDRange(d.typ, RangeBound{Val: o.Upper.Val, Inclusive: !o.Upper.Inclusive}, d.Upper)
+		if err != nil {
+			return nil, err
+		}
+		if !r.Empty {
+			res = append(res, r)
+		}
+	}
+	return res, nil
+}
+
+// DMultirange is the Datum for the multirange types, like INT4MULTIRANGE. A
+// multirange is a set of non-empty ranges that neither overlap nor are
+// adjacent, ordered by their bounds.
+type DMultirange struct {
+	typ    *types.T
+	Ranges []*DRange
+}
+
+// NewDMultirange returns a new multirange of the given type with the values
+// of the given ranges. The ranges may be empty and overlap each other; they
+// are normalized by the constructor.
+func NewDMultirange(typ *types.T, ranges []*DRange) *DMultirange {
+	sorted := make([]*DRange, 0, len(ranges))
+	for _, r := range ranges {
+		if !r.Empty {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].compare(sorted[j]) < 0
+	})
+	res := sorted[:0]
+	for _, r := range sorted {
+		if n := len(res); n > 0 && (res[n-1].Overlaps(r) || res[n-1].Adjacent(r)) {
+			res[n-1] = res[n-1].Merge(r)
+			continue
+		}
+		res = append(res, r)
+	}
+	return &DMultirange{typ: typ, Ranges: res}
+}
+
+// AsDMultirange attempts to retrieve a *DMultirange from an Expr, returning
+// a *DMultirange and a flag signifying whether the assertion was successful.
+// The function should be used instead of direct type assertions wherever a
+// *DMultirange wrapped by a *DOidWrapper is possible.
+func AsDMultirange(e Expr) (*DMultirange, bool) {
+	switch t := e.(type) {
+	case *DMultirange:
+		return t, true
+	case *DOidWrapper:
+		return AsDMultirange(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDMultirange attempts to retrieve a *DMultirange from an Expr,
+// panicking if the assertion fails.
+func MustBeDMultirange(e Expr) *DMultirange {
+	m, ok := AsDMultirange(e)
+	if !ok {
+		panic(errors.AssertionFailedf("expected *DMultirange, found %T", e))
+	}
+	return m
+}
+
+// ParseDMultirangeFromString parses the string-form of a multirange, like
+// '{[1,3),[5,10)}', as a multirange of type t.
+//
+// The dependsOnContext return value indicates if we had to consult the
+// ParseContext (either for the time or the local timezone).
+func ParseDMultirangeFromString(
+	ctx ParseContext, s string, t *types.T,
+) (_ *DMultirange, dependsOnContext bool, _ error) {
+	m, dependsOnContext, err := doParseDMultirangeFromString(ctx, s, t)
+	if err != nil {
+		return nil, false, MakeParseError(s, t, err)
+	}
+	return m, dependsOnContext, nil
+}
+
+func doParseDMultirangeFromString(
+	ctx ParseContext, s string, t *types.T,
+) (_ *DMultirange, dependsOnContext bool, _ error) {
+	rest := strings.TrimSpace(s)
+	if rest == "" || rest[0] != '{' {
+		return nil, false, errors.WithDetail(malformedMultirangeError, "Missing left brace.")
+	}
+	rest = strings.TrimLeftFunc(rest[1:], unicode.IsSpace)
+	var ranges []*DRange
+	if rest != "" && rest[0] == '}' {
+		rest = rest[1:]
+	} else {
+		for {
+			r, remaining, dep, err := parseRangePrefix(ctx, rest, t.RangeContents())
+			if err != nil {
+				return nil, false, err
+			}
+			dependsOnContext = dependsOnContext || dep
+			ranges = append(ranges, r)
+			rest = strings.TrimLeftFunc(remaining, unicode.IsSpace)
+			if rest == "" {
+				return nil, false, errors.WithDetail(malformedMultirangeError, "Unexpected end of input.")
+			}
+			if rest[0] == '}' {
+				rest = rest[1:]
+				break
+			}
+			if rest[0] != ',' {
+				return nil, false, errors.WithDetail(malformedMultirangeError, "Expected comma or end of multirange.")
+			}
+			rest = rest[1:]
+		}
+	}
+	if strings.TrimSpace(rest) != "" {
+		return nil, false, errors.WithDetail(malformedMultirangeError, "Junk after closing right brace.")
+	}
+	return NewDMultirange(t, ranges), dependsOnContext, nil
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (d *DMultirange) ResolvedType() *types.T {
+	return d.typ
+}
+
+// Compare implements the Datum interface. Multiranges are compared range by
+// range, and a multirange that is a prefix of another sorts first.
+func (d *DMultirange) Compare(
+	ctx context.Context, cmpCtx CompareContext, other Datum,
+) (int, error) {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1, nil
+	}
+	v, ok := cmpCtx.UnwrapDatum(ctx, other).(*DMultirange)
+	if !ok || !d.typ.Equivalent(v.typ) {
+		return 0, makeUnsupportedComparisonMessage(d, other)
+	}
+	for i := 0; i < len(d.Ranges) && i < len(v.Ranges); i++ {
+		if c := d.Ranges[i].compare(v.Ranges[i]); c != 0 {
+			return c, nil
+		}
+	}
+	switch {
+	case len(d.Ranges) < len(v.Ranges):
+		return -1, nil
+	case len(d.Ranges) > len(v.Ranges):
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Prev implements the Datum interface.
+func (d *DMultirange) Prev(ctx context.Context, cmpCtx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DMultirange) Next(ctx context.Context, cmpCtx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DMultirange) IsMax(ctx context.Context, cmpCtx CompareContext) bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DMultirange) IsMin(ctx context.Context, cmpCtx CompareContext) bool {
+	return len(d.Ranges) == 0
+}
+
+// Max implements the Datum interface.
+func (d *DMultirange) Max(ctx context.Context, cmpCtx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DMultirange) Min(ctx context.Context, cmpCtx CompareContext) (Datum, bool) {
+	return &DMultirange{typ: d.typ}, true
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DMultirange) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DMultirange) Format(ctx *FmtCtx) {
+	bareStrings := ctx.HasFlags(FmtFlags(lexbase.EncBareStrings))
+	if !bareStrings {
+		ctx.WriteByte('\'')
+	}
+	ctx.WriteByte('{')
+	for i, r := range d.Ranges {
+		if i > 0 {
+			ctx.WriteByte(',')
+		}
+		r.formatBare(ctx)
+	}
+	ctx.WriteByte('}')
+	if !bareStrings {
+		ctx.WriteByte('\'')
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DMultirange) Size() uintptr {
+	sz := unsafe.Sizeof(*d)
+	for _, r := range d.Ranges {
+		sz += r.Size()
+	}
+	return sz
+}
+
+// IsComposite implements the CompositeDatum interface.
+func (d *DMultirange) IsComposite() bool {
+	for _, r := range d.Ranges {
+		if r.IsComposite() {
+			return true
+		}
+	}
+	return false
+}
+
+// RangeType returns the type of the ranges of the multirange.
+func (d *DMultirange) RangeType() *types.T {
+	return d.typ.RangeContents()
+}
+
+// Span returns the smallest range that includes all the ranges of the
+// multirange, which is empty if the multirange is empty.
+func (d *DMultirange) Span() *DRange {
+	if len(d.Ranges) == 0 {
+		return NewDEmptyRange(d.RangeType())
+	}
+	return d.Ranges[0].Merge(d.Ranges[len(d.Ranges)-1])
+}
+
+// Union returns the multirange with the values of both multiranges.
+func (d *DMultirange) Union(o *DMultirange) *DMultirange {
+	ranges := make([]*DRange, 0, len(d.Ranges)+len(o.Ranges))
+	ranges = append(ranges, d.Ranges...)
+	ranges = append(ranges, o.Ranges...)
+	return NewDMultirange(d.typ, ranges)
+}
+
+// Intersect returns the multirange with the values that are in both
+// multiranges.
+func (d *DMultirange) Intersect(o *DMultirange) *DMultirange {
+	var ranges []*DRange
+	for _, r := range d.Ranges {
+		for _, s := range o.Ranges {
+			if i := r.Intersect(s); !i.Empty {
+				ranges = append(ranges, i)
+			}
+		}
+	}
+	return NewDMultirange(d.typ, ranges)
+}
+
+// Difference returns the multirange with the values of the multirange that
+// are not in the other multirange.
+func (d *DMultirange) Difference(o *DMultirange) (*DMultirange, error) {
+	ranges := d.Ranges
+	for _, s := range o.Ranges {
+		var next []*DRange
+		for _, r := range ranges {
+			res, err := r.subtract(s)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, res...)
+		}
+		ranges = next
+	}
+	return NewDMultirange(d.typ, ranges), nil
+}
+
+func pgwireFormatStringInRange(buf *bytes.Buffer, in string) {
+	quote := in == "" || rangeQuoteSet.in(in)
+	if quote {
+		buf.WriteByte('"')
+	}
+	for _, r := range in {
+		if r == '"' || r == '\\' {
+			// Like in tuples, bounds of ranges double " and \.
+			buf.WriteByte(byte(r))
+			buf.WriteByte(byte(r))
+		} else {
+			buf.WriteRune(r)
+		}
+	}
+	if quote {
+		buf.WriteByte('"')
+	}
+}
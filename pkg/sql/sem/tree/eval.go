@@ -618,7 +618,7 @@ var BinOps = map[treebin.BinaryOperatorSymbol]*BinOpOverloads{
 		},
 	}},
 
-	treebin.Plus: {overloads: []*BinOp{
+	treebin.Plus: {overloads: append([]*BinOp{
 		{
 			LeftType:   types.Int,
 			RightType:  types.Int,
@@ -808,9 +808,9 @@ var BinOps = map[treebin.BinaryOperatorSymbol]*BinOpOverloads{
 			EvalOp:     &PlusPGVectorOp{},
 			Volatility: volatility.Immutable,
 		},
-	}},
+	}, makeRangeBinOps(&PlusRangeOp{}, &PlusMultirangeOp{})...)},
 
-	treebin.Minus: {overloads: []*BinOp{
+	treebin.Minus: {overloads: append([]*BinOp{
 		{
 			LeftType:   types.Int,
 			RightType:  types.Int,
@@ -1001,9 +1001,9 @@ var BinOps = map[treebin.BinaryOperatorSymbol]*BinOpOverloads{
 			EvalOp:     &MinusPGVectorOp{},
 			Volatility: volatility.Immutable,
 		},
-	}},
+	}, makeRangeBinOps(&MinusRangeOp{}, &MinusMultirangeOp{})...)},
 
-	treebin.Mult: {overloads: []*BinOp{
+	treebin.Mult: {overloads: append([]*BinOp{
 		{
 			LeftType:   types.Int,
 			RightType:  types.Int,
@@ -1091,7 +1091,7 @@ var BinOps = map[treebin.BinaryOperatorSymbol]*BinOpOverloads{
 			EvalOp:     &MultPGVectorOp{},
 			Volatility: volatility.Immutable,
 		},
-	}},
+	}, makeRangeBinOps(&MultRangeOp{}, &MultMultirangeOp{})...)},
 
 	treebin.Div: {overloads: []*BinOp{
 		{
@@ -1602,7 +1602,7 @@ func makeIsFn(a, b *types.T, v volatility.V) *CmpOp {
 
 // CmpOps contains the comparison operations indexed by operation type.
 var CmpOps = cmpOpFixups(map[treecmp.ComparisonOperatorSymbol]*CmpOpOverloads{
-	treecmp.EQ: {overloads: []*CmpOp{
+	treecmp.EQ: {overloads: append([]*CmpOp{
 		// Single-type comparisons.
 		makeEqFn(types.AnyEnum, types.AnyEnum, volatility.Immutable),
 		makeEqFn(types.Bool, types.Bool, volatility.Leakproof),
@@ -1662,9 +1662,9 @@ var CmpOps = cmpOpFixups(map[treecmp.ComparisonOperatorSymbol]*CmpOpOverloads{
 			},
 			Volatility: volatility.Immutable,
 		},
-	}},
+	}, makeRangeCmpOps(makeEqFn)...)},
 
-	treecmp.LT: {overloads: []*CmpOp{
+	treecmp.LT: {overloads: append([]*CmpOp{
 		// Single-type comparisons.
 		makeLtFn(types.AnyEnum, types.AnyEnum, volatility.Immutable),
 		makeLtFn(types.Bool, types.Bool, volatility.Leakproof),
@@ -1722,9 +1722,9 @@ var CmpOps = cmpOpFixups(map[treecmp.ComparisonOperatorSymbol]*CmpOpOverloads{
 			},
 			Volatility: volatility.Immutable,
 		},
-	}},
+	}, makeRangeCmpOps(makeLtFn)...)},
 
-	treecmp.LE: {overloads: []*CmpOp{
+	treecmp.LE: {overloads: append([]*CmpOp{
 		// Single-type comparisons.
 		makeLeFn(types.AnyEnum, types.AnyEnum, volatility.Immutable),
 		makeLeFn(types.Bool, types.Bool, volatility.Leakproof),
@@ -1782,9 +1782,9 @@ var CmpOps = cmpOpFixups(map[treecmp.ComparisonOperatorSymbol]*CmpOpOverloads{
 			},
 			Volatility: volatility.Immutable,
 		},
-	}},
+	}, makeRangeCmpOps(makeLeFn)...)},
 
-	treecmp.IsNotDistinctFrom: {overloads: []*CmpOp{
+	treecmp.IsNotDistinctFrom: {overloads: append([]*CmpOp{
 		{
 			LeftType:  types.Unknown,
 			RightType: types.Unknown,
@@ -1874,9 +1874,9 @@ var CmpOps = cmpOpFixups(map[treecmp.ComparisonOperatorSymbol]*CmpOpOverloads{
 			},
 			Volatility: volatility.Immutable,
 		},
-	}},
+	}, makeRangeCmpOps(makeIsFn)...)},
 
-	treecmp.In: {overloads: []*CmpOp{
+	treecmp.In: {overloads: append([]*CmpOp{
 		makeEvalTupleIn(types.AnyEnum, volatility.Leakproof),
 		makeEvalTupleIn(types.Bool, volatility.Leakproof),
 		makeEvalTupleIn(types.Bytes, volatility.Leakproof),
@@ -1903,7 +1903,9 @@ var CmpOps = cmpOpFixups(map[treecmp.ComparisonOperatorSymbol]*CmpOpOverloads{
 		makeEvalTupleIn(types.TimestampTZ, volatility.Leakproof),
 		makeEvalTupleIn(types.Uuid, volatility.Leakproof),
 		makeEvalTupleIn(types.VarBit, volatility.Leakproof),
-	}},
+	}, makeRangeCmpOps(func(a, _ *types.T, v volatility.V) *CmpOp {
+		return makeEvalTupleIn(a, v)
+	})...)},
 
 	treecmp.Like: {overloads: []*CmpOp{
 		{
@@ -1983,7 +1985,7 @@ var CmpOps = cmpOpFixups(map[treecmp.ComparisonOperatorSymbol]*CmpOpOverloads{
 		},
 	}},
 
-	treecmp.Contains: {overloads: []*CmpOp{
+	treecmp.Contains: {overloads: append([]*CmpOp{
 		{
 			LeftType:   types.AnyArray,
 			RightType:  types.AnyArray,
@@ -1996,9 +1998,9 @@ var CmpOps = cmpOpFixups(map[treecmp.ComparisonOperatorSymbol]*CmpOpOverloads{
 			EvalOp:     &ContainsJsonbOp{},
 			Volatility: volatility.Immutable,
 		},
-	}},
+	}, makeRangeComparisonOperators(rangeContains, rangeElemRight)...)},
 
-	treecmp.ContainedBy: {overloads: []*CmpOp{
+	treecmp.ContainedBy: {overloads: append([]*CmpOp{
 		{
 			LeftType:   types.AnyArray,
 			RightType:  types.AnyArray,
@@ -2011,7 +2013,7 @@ var CmpOps = cmpOpFixups(map[treecmp.ComparisonOperatorSymbol]*CmpOpOverloads{
 			EvalOp:     &ContainedByJsonbOp{},
 			Volatility: volatility.Immutable,
 		},
-	}},
+	}, makeRangeComparisonOperators(rangeContainedBy, rangeElemLeft)...)},
 	treecmp.Overlaps: {overloads: append([]*CmpOp{
 		{
 			LeftType:   types.AnyArray,
@@ -2025,11 +2027,11 @@ var CmpOps = cmpOpFixups(map[treecmp.ComparisonOperatorSymbol]*CmpOpOverloads{
 			EvalOp:     &OverlapsINetOp{},
 			Volatility: volatility.Immutable,
 		},
-	}, makeBox2DComparisonOperators(
+	}, append(makeBox2DComparisonOperators(
 		func(lhs, rhs *geo.CartesianBoundingBox) bool {
 			return lhs.Intersects(rhs)
 		},
-	)...),
+	), makeRangeComparisonOperators(rangeOverlaps, rangeElemNone)...)...),
 	},
 	treecmp.TSMatches: {overloads: []*CmpOp{
 		{
@@ -2096,6 +2098,145 @@ func makeBox2DComparisonOperators(op func(lhs, rhs *geo.CartesianBoundingBox) bo
 	}
 }
 
+// makeRangeBinOps returns the overloads of a binary operator between two
+// ranges and between two multiranges, for each of the range types.
+func makeRangeBinOps(rangeOp, multirangeOp BinaryEvalOp) []*BinOp {
+	ops := make([]*BinOp, 0, 2*len(types.RangeTypes))
+	for i, rangeTyp := range types.RangeTypes {
+		multirangeTyp := types.MultirangeTypes[i]
+		ops = append(ops,
+			&BinOp{
+				LeftType:   rangeTyp,
+				RightType:  rangeTyp,
+				ReturnType: rangeTyp,
+				EvalOp:     rangeOp,
+				Volatility: volatility.Immutable,
+			},
+			&BinOp{
+				LeftType:   multirangeTyp,
+				RightType:  multirangeTyp,
+				ReturnType: multirangeTyp,
+				EvalOp:     multirangeOp,
+				Volatility: volatility.Immutable,
+			},
+		)
+	}
+	return ops
+}
+
+// makeRangeCmpOps returns the overloads built by makeFn of a scalar
+// comparison between two values of each of the range and multirange types.
+func makeRangeCmpOps(makeFn func(a, b *types.T, v volatility.V) *CmpOp) []*CmpOp {
+	ops := make([]*CmpOp, 0, len(types.RangeTypes)+len(types.MultirangeTypes))
+	for _, typ := range types.RangeTypes {
+		ops = append(ops, makeFn(typ, typ, volatility.Immutable))
+	}
+	for _, typ := range types.MultirangeTypes {
+		ops = append(ops, makeFn(typ, typ, volatility.Immutable))
+	}
+	return ops
+}
+
+// rangeElemSide indicates on which side of a range comparison operator a
+// value of the element type of the range is allowed.
+type rangeElemSide int
+
+const (
+	rangeElemNone rangeElemSide = iota
+	rangeElemLeft
+	rangeElemRight
+)
+
+// makeRangeComparisonOperators returns the overloads of a range comparison
+// operator, like && or @>, between any combination of ranges and multiranges
+// of the same range type, and between those and values of the element type
+// of the range on the given side.
+func makeRangeComparisonOperators(op func(left, right Datum) bool, elemSide rangeElemSide) []*CmpOp {
+	var ops []*CmpOp
+	add := func(left, right *types.T) {
+		ops = append(ops, &CmpOp{
+			LeftType:   left,
+			RightType:  right,
+			EvalOp:     &CompareRangeOp{Op: op},
+			Volatility: volatility.Immutable,
+		})
+	}
+	for i, rangeTyp := range types.RangeTypes {
+		operands := []*types.T{rangeTyp, types.MultirangeTypes[i]}
+		for _, left := range operands {
+			for _, right := range operands {
+				add(left, right)
+			}
+		}
+		for _, typ := range operands {
+			switch elemSide {
+			case rangeElemLeft:
+				add(rangeTyp.RangeContents(), typ)
+			case rangeElemRight:
+				add(typ, rangeTyp.RangeContents())
+			}
+		}
+	}
+	return ops
+}
+
+// rangesOf returns the ranges of a range or multirange datum.
+func rangesOf(d Datum) []*DRange {
+	if r, ok := AsDRange(d); ok {
+		return []*DRange{r}
+	}
+	return MustBeDMultirange(d).Ranges
+}
+
+// rangeOverlaps implements the && operator for ranges and multiranges.
+func rangeOverlaps(left, right Datum) bool {
+	for _, l := range rangesOf(left) {
+		for _, r := range rangesOf(right) {
+			if l.Overlaps(r) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// rangeContains implements the @> operator for ranges and multiranges. The
+// right side can be a range, a multirange, or a value of the element type.
+func rangeContains(left, right Datum) bool {
+	ls := rangesOf(left)
+	right = UnwrapDOidWrapper(right)
+	switch right.(type) {
+	case *DRange, *DMultirange:
+		// Since the ranges of a multirange are never adjacent, each range on the
+		// right must be contained in a single range on the left.
+		containedInLeft := func(r *DRange) bool {
+			for _, l := range ls {
+				if l.ContainsRange(r) {
+					return true
+				}
+			}
+			return false
+		}
+		for _, r := range rangesOf(right) {
+			if !r.Empty && !containedInLeft(r) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, l := range ls {
+		if l.ContainsValue(right) {
+			return true
+		}
+	}
+	return false
+}
+
+// rangeContainedBy implements the <@ operator for ranges and multiranges.
+func rangeContainedBy(left, right Datum) bool {
+	return rangeContains(right, left)
+}
+
 // This map contains the inverses for operators in the CmpOps map that have
 // inverses.
 var cmpOpsInverse map[treecmp.ComparisonOperatorSymbol]treecmp.ComparisonOperatorSymbol
@@ -32,6 +32,11 @@ type CompareBox2DOp struct {
 	Op func(left, right Datum) bool
 }
 
+// CompareRangeOp is a BinaryEvalOp.
+type CompareRangeOp struct {
+	Op func(left, right Datum) bool
+}
+
 // InTupleOp is a BinaryEvalOp.
 type InTupleOp struct{}
 
@@ -191,6 +196,10 @@ type (
 	PlusPGLSNDecimalOp struct{}
 	// PlusPGVectorOp is a BinaryEvalOp.
 	PlusPGVectorOp struct{}
+	// PlusRangeOp is a BinaryEvalOp.
+	PlusRangeOp struct{}
+	// PlusMultirangeOp is a BinaryEvalOp.
+	PlusMultirangeOp struct{}
 )
 
 type (
@@ -248,6 +257,10 @@ type (
 	MinusPGLSNOp struct{}
 	// MinusPGVectorOp is a BinaryEvalOp.
 	MinusPGVectorOp struct{}
+	// MinusRangeOp is a BinaryEvalOp.
+	MinusRangeOp struct{}
+	// MinusMultirangeOp is a BinaryEvalOp.
+	MinusMultirangeOp struct{}
 )
 type (
 	// MultDecimalIntOp is a BinaryEvalOp.
@@ -274,6 +287,10 @@ type (
 	MultIntervalIntOp struct{}
 	// MultPGVectorOp is a BinaryEvalOp.
 	MultPGVectorOp struct{}
+	// MultRangeOp is a BinaryEvalOp.
+	MultRangeOp struct{}
+	// MultMultirangeOp is a BinaryEvalOp.
+	MultMultirangeOp struct{}
 )
 
 type (
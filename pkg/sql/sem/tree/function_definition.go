@@ -319,8 +319,9 @@ func (fd *ResolvedFunctionDefinition) MatchOverload(
 		// Special handling of routines.
 		//
 		// First, apply regular postgres resolution approach of using only
-		// the input types.
-		if ol.params().MatchIdentical(paramTypes) {
+		// the input types. The types of a routine are used directly, since a
+		// VARIADIC parameter is identified by its array type.
+		if ol.Types.MatchIdentical(paramTypes) {
 			return true
 		}
 		if tryDefaultExprs && len(ol.defaultExprs()) > 0 {
@@ -295,10 +295,96 @@ type Overload struct {
 	// UDFContainsOnlySignature is false, then DEFAULT expressions are included
 	// into RoutineParams.
 	DefaultExprs Exprs
+	// Variadic is true if the last input parameter of the routine is VARIADIC.
+	// The type of the parameter in Types is then an array type, and the routine
+	// can be called with any number of arguments of its element type in place
+	// of the array.
+	Variadic bool
+	// UDFAggregate is set if the overload is an aggregate created with CREATE
+	// AGGREGATE, in which case Class is AggregateClass and the overload has no
+	// body.
+	UDFAggregate *UDFAggregate
+}
+
+// UDFAggregate describes how the value of an aggregate created with CREATE
+// AGGREGATE is computed by its support functions, which are referenced by OID.
+type UDFAggregate struct {
+	// StateTransitionFunc is called with the current state and the arguments
+	// of each aggregated row, and returns the next state.
+	StateTransitionFunc oid.Oid
+	// StateType is the type of the state.
+	StateType *types.T
+	// InitialCondition is the string representation of the initial state, or
+	// nil if the initial state is NULL.
+	InitialCondition *string
+	// FinalFunc computes the result of the aggregate from the final state. If
+	// zero, the final state is the result.
+	FinalFunc oid.Oid
+	// CombineFunc combines two partial states. If zero, the aggregate cannot
+	// be computed in multiple stages.
+	CombineFunc oid.Oid
 }
 
 // params implements the overloadImpl interface.
-func (b Overload) params() TypeList { return b.Types }
+func (b Overload) params() TypeList {
+	if b.Variadic {
+		return b.variadicParams()
+	}
+	return b.Types
+}
+
+// variadicParams returns the parameter types of a routine with a VARIADIC
+// parameter which is called with the values of that parameter spread as
+// individual arguments.
+func (b Overload) variadicParams() TypeList {
+	paramTypes := b.Types.Types()
+	n := len(paramTypes)
+	return variadicRoutineType{VariadicType{
+		FixedTypes: paramTypes[:n-1],
+		VarType:    paramTypes[n-1].ArrayContents(),
+	}}
+}
+
+// variadicRoutineType is the TypeList of a routine with a VARIADIC parameter.
+// Unlike builtins, such a routine must be called with at least one value for
+// the VARIADIC parameter.
+type variadicRoutineType struct {
+	VariadicType
+}
+
+// MatchLen is part of the TypeList interface.
+func (v variadicRoutineType) MatchLen(l int) bool {
+	return l > len(v.FixedTypes)
+}
+
+// variadicArrayOverload is the overloadImpl of a routine in a call that marks
+// its last argument with VARIADIC, which passes the values of the variadic
+// parameter as an array.
+type variadicArrayOverload struct {
+	*Overload
+}
+
+// params implements the overloadImpl interface.
+func (o variadicArrayOverload) params() TypeList {
+	if !o.Variadic {
+		// Only routines with a VARIADIC parameter can be called this way, and
+		// the empty list of parameters matches no call with a VARIADIC argument.
+		return ParamTypes{}
+	}
+	return o.Types
+}
+
+// variadicArrayOverloads is the overloadSet of a call that marks its last
+// argument with VARIADIC.
+type variadicArrayOverloads []QualifiedOverload
+
+func (vo variadicArrayOverloads) len() int {
+	return len(vo)
+}
+
+func (vo variadicArrayOverloads) get(i int) overloadImpl {
+	return variadicArrayOverload{vo[i].Overload}
+}
 
 // returnType implements the overloadImpl interface.
 func (b Overload) returnType() ReturnTyper { return b.ReturnType }
@@ -1051,8 +1137,9 @@ func (s *overloadTypeChecker) typeCheckOverloadedExprs(
 	// argument types are invalid.
 	s.overloadIdxs = filterOverloads(s.overloadIdxs, s.overloads, func(o overloadImpl) bool {
 		ol, ok := o.(*Overload)
-		if !ok || ol.Type == BuiltinRoutine {
-			// Don't filter builtin routines.
+		if !ok || ol.Type == BuiltinRoutine || ol.Variadic {
+			// Don't filter builtin routines. Routines with a VARIADIC parameter
+			// cannot have polymorphic parameters.
 			return true
 		}
 		params := ol.Types.(ParamTypes)
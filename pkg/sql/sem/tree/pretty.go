@@ -1647,7 +1647,9 @@ func (node *CreateIndex) doc(p *PrettyCfg) pretty.Doc {
 	if node.Unique {
 		title = append(title, pretty.Keyword("UNIQUE"))
 	}
-	if node.Inverted {
+	if node.Vector {
+		title = append(title, pretty.Keyword("VECTOR"))
+	} else if node.Inverted {
 		title = append(title, pretty.Keyword("INVERTED"))
 	}
 	title = append(title, pretty.Keyword("INDEX"))
@@ -1740,7 +1742,9 @@ func (node *IndexTableDef) doc(p *PrettyCfg) pretty.Doc {
 	if node.Name != "" {
 		title = pretty.ConcatSpace(title, p.Doc(&node.Name))
 	}
-	if node.Inverted {
+	if node.Vector {
+		title = pretty.ConcatSpace(pretty.Keyword("VECTOR"), title)
+	} else if node.Inverted {
 		title = pretty.ConcatSpace(pretty.Keyword("INVERTED"), title)
 	}
 	title = pretty.ConcatSpace(title, p.bracket("(", p.Doc(&node.Columns), ")"))
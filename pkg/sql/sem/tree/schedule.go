@@ -148,3 +148,30 @@ func (node *ScheduledChangefeed) Format(ctx *FmtCtx) {
 		ctx.FormatNode(&node.ScheduleOptions)
 	}
 }
+
+// ScheduledSQL represents a schedule which periodically executes SQL
+// statements.
+type ScheduledSQL struct {
+	ScheduleLabelSpec LabelSpec
+	Statement         Expr
+	Recurrence        Expr
+	ScheduleOptions   KVOptions
+}
+
+var _ Statement = &ScheduledSQL{}
+
+// Format implements the NodeFormatter interface.
+func (node *ScheduledSQL) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE SCHEDULE")
+	ctx.FormatNode(&node.ScheduleLabelSpec)
+	ctx.WriteString(" FOR SQL ")
+	ctx.FormatNode(node.Statement)
+
+	ctx.WriteString(" RECURRING ")
+	ctx.FormatNode(node.Recurrence)
+
+	if node.ScheduleOptions != nil {
+		ctx.WriteString(" WITH SCHEDULE OPTIONS ")
+		ctx.FormatNode(&node.ScheduleOptions)
+	}
+}
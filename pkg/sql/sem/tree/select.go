@@ -780,6 +780,50 @@ func (node *GroupBy) Format(ctx *FmtCtx) {
 	}
 }
 
+// RollupExpr represents a ROLLUP(...) element of a GROUP BY clause. It is
+// shorthand for the grouping sets formed by each prefix of Exprs, including the
+// empty prefix. A parenthesized list of expressions in Exprs is treated as a
+// single unit.
+type RollupExpr struct {
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *RollupExpr) Format(ctx *FmtCtx) {
+	ctx.WriteString("ROLLUP (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
+// CubeExpr represents a CUBE(...) element of a GROUP BY clause. It is shorthand
+// for the grouping sets formed by every subset of Exprs. A parenthesized list
+// of expressions in Exprs is treated as a single unit.
+type CubeExpr struct {
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CubeExpr) Format(ctx *FmtCtx) {
+	ctx.WriteString("CUBE (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
+// GroupingSetsExpr represents a GROUPING SETS (...) element of a GROUP BY
+// clause. Each of the Sets is either a single expression, a parenthesized list
+// of expressions, an empty grouping set (represented by an empty Tuple), or a
+// nested ROLLUP, CUBE or GROUPING SETS element.
+type GroupingSetsExpr struct {
+	Sets Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingSetsExpr) Format(ctx *FmtCtx) {
+	ctx.WriteString("GROUPING SETS (")
+	ctx.FormatNode(&node.Sets)
+	ctx.WriteByte(')')
+}
+
 // DistinctOn represents a DISTINCT ON clause.
 type DistinctOn []Expr
 
@@ -153,3 +153,15 @@ func ComparisonOpName(op ComparisonOperatorSymbol) string {
 	}
 	return comparisonOpName[op]
 }
+
+// ComparisonOpByName returns the comparison operator whose symbol has the
+// given name, as returned by ComparisonOpName. The second return value is
+// false if there is no such operator.
+func ComparisonOpByName(name string) (ComparisonOperator, bool) {
+	for i := range comparisonOpName {
+		if comparisonOpName[i] == name {
+			return MakeComparisonOperator(ComparisonOperatorSymbol(i)), true
+		}
+	}
+	return ComparisonOperator{}, false
+}
@@ -632,6 +632,8 @@ func (expr *CastExpr) TypeCheck(
 	// the cast may be needed to infer the placeholder type.
 	_, isPlaceholder := expr.Expr.(*Placeholder)
 	canElideCast := !isPlaceholder
+	// Nor for domains, since the cast checks the constraints of the domain.
+	canElideCast = canElideCast && !exprType.IsDomain()
 
 	switch {
 	case isConstant(expr.Expr):
@@ -1003,11 +1005,12 @@ func (expr *ComparisonExpr) TypeCheck(
 }
 
 var (
-	errStarNotAllowed      = pgerror.New(pgcode.Syntax, "cannot use \"*\" in this context")
-	errInvalidDefaultUsage = pgerror.New(pgcode.Syntax, "DEFAULT can only appear in a VALUES list within INSERT or on the right side of a SET")
-	errInvalidMaxUsage     = pgerror.New(pgcode.Syntax, "MAXVALUE can only appear within a range partition expression")
-	errInvalidMinUsage     = pgerror.New(pgcode.Syntax, "MINVALUE can only appear within a range partition expression")
-	errPrivateFunction     = pgerror.New(pgcode.ReservedName, "function reserved for internal use")
+	errStarNotAllowed          = pgerror.New(pgcode.Syntax, "cannot use \"*\" in this context")
+	errInvalidDefaultUsage     = pgerror.New(pgcode.Syntax, "DEFAULT can only appear in a VALUES list within INSERT or on the right side of a SET")
+	errInvalidMaxUsage         = pgerror.New(pgcode.Syntax, "MAXVALUE can only appear within a range partition expression")
+	errInvalidMinUsage         = pgerror.New(pgcode.Syntax, "MINVALUE can only appear within a range partition expression")
+	errInvalidGroupingSetUsage = pgerror.New(pgcode.Syntax, "ROLLUP, CUBE and GROUPING SETS can only appear within a GROUP BY clause")
+	errPrivateFunction         = pgerror.New(pgcode.ReservedName, "function reserved for internal use")
 )
 
 // NewAggInAggError creates an error for the case when an aggregate function is
@@ -1214,9 +1217,11 @@ func (expr *FuncExpr) TypeCheck(
 		return sb.String()
 	}
 
-	s := getOverloadTypeChecker(
-		(*qualifiedOverloads)(&def.Overloads), expr.Exprs...,
-	)
+	var overloads overloadSet = (*qualifiedOverloads)(&def.Overloads)
+	if expr.Variadic {
+		overloads = (*variadicArrayOverloads)(&def.Overloads)
+	}
+	s := getOverloadTypeChecker(overloads, expr.Exprs...)
 	defer s.release()
 
 	if err = expr.typeCheckWithFuncAncestor(semaCtx, func() error {
@@ -1457,14 +1462,23 @@ func (expr *FuncExpr) TypeCheck(
 		}
 	}
 
-	for i, subExpr := range s.typedExprs {
+	typedExprs := s.typedExprs
+	if overloadImpl.Variadic && !expr.Variadic {
+		// Collect the arguments for the VARIADIC parameter into an array, and
+		// mark the call accordingly so that it resolves to the same overload
+		// when it is formatted and type-checked again.
+		typedExprs = packVariadicArgs(overloadImpl, typedExprs)
+		expr.Exprs = make(Exprs, len(typedExprs))
+		expr.Variadic = true
+	}
+	for i, subExpr := range typedExprs {
 		expr.Exprs[i] = subExpr
 	}
 
 	expr.Func.FunctionReference = def
 	expr.fn = overloadImpl
 	expr.fnProps = &overloadImpl.FunctionProperties
-	expr.typ = overloadImpl.returnType()(s.typedExprs)
+	expr.typ = overloadImpl.returnType()(typedExprs)
 	if expr.typ == UnknownReturnType {
 		typeNames := make([]string, 0, len(expr.Exprs))
 		for _, expr := range s.typedExprs {
@@ -1486,6 +1500,26 @@ func (expr *FuncExpr) TypeCheck(
 	return expr, nil
 }
 
+// packVariadicArgs returns the arguments of a call to a routine with a
+// VARIADIC parameter, with the arguments for that parameter replaced by an
+// array that holds them.
+func packVariadicArgs(ol *Overload, typedExprs []TypedExpr) []TypedExpr {
+	numFixed := ol.Types.Length() - 1
+	arrType := ol.Types.GetAt(numFixed)
+	elemType := arrType.ArrayContents()
+	elems := make(TypedExprs, 0, len(typedExprs)-numFixed)
+	for _, e := range typedExprs[numFixed:] {
+		if typ := e.ResolvedType(); typ.Family() != types.UnknownFamily && !typ.Identical(elemType) {
+			e = NewTypedCastExpr(e, elemType)
+		}
+		elems = append(elems, e)
+	}
+	res := make([]TypedExpr, numFixed+1)
+	copy(res, typedExprs[:numFixed])
+	res[numFixed] = NewTypedArray(elems, arrType)
+	return res
+}
+
 // TypeCheck implements the Expr interface.
 func (expr *IfErrExpr) TypeCheck(
 	ctx context.Context, semaCtx *SemaContext, desired *types.T,
@@ -1826,6 +1860,27 @@ func (expr DefaultVal) TypeCheck(
 	return nil, errInvalidDefaultUsage
 }
 
+// TypeCheck implements the Expr interface.
+func (expr *RollupExpr) TypeCheck(
+	_ context.Context, _ *SemaContext, desired *types.T,
+) (TypedExpr, error) {
+	return nil, errInvalidGroupingSetUsage
+}
+
+// TypeCheck implements the Expr interface.
+func (expr *CubeExpr) TypeCheck(
+	_ context.Context, _ *SemaContext, desired *types.T,
+) (TypedExpr, error) {
+	return nil, errInvalidGroupingSetUsage
+}
+
+// TypeCheck implements the Expr interface.
+func (expr *GroupingSetsExpr) TypeCheck(
+	_ context.Context, _ *SemaContext, desired *types.T,
+) (TypedExpr, error) {
+	return nil, errInvalidGroupingSetUsage
+}
+
 // TypeCheck implements the Expr interface.
 func (expr PartitionMinVal) TypeCheck(
 	_ context.Context, _ *SemaContext, desired *types.T,
@@ -2134,6 +2189,20 @@ func (d *DPGVector) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (Ty
 	return d, nil
 }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DRange) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
+	return d, nil
+}
+
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DMultirange) TypeCheck(
+	_ context.Context, _ *SemaContext, _ *types.T,
+) (TypedExpr, error) {
+	return d, nil
+}
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DGeography) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
@@ -0,0 +1,151 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/semenumpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// SetConstraints implements the SET CONSTRAINTS statement.
+// See https://www.postgresql.org/docs/current/sql-set-constraints.html for
+// details.
+func (p *planner) SetConstraints(ctx context.Context, n *tree.SetConstraints) (planNode, error) {
+	return &setConstraintsNode{n: n}, nil
+}
+
+type setConstraintsNode struct {
+	n *tree.SetConstraints
+}
+
+func (n *setConstraintsNode) Next(_ runParams) (bool, error) { return false, nil }
+func (n *setConstraintsNode) Values() tree.Datums            { return nil }
+func (n *setConstraintsNode) Close(_ context.Context)        {}
+func (n *setConstraintsNode) startExec(params runParams) error {
+	p := params.p
+	s := p.extendedEvalCtx.deferredConstraints
+	if p.extendedEvalCtx.TxnImplicit || s == nil {
+		// Like Postgres, only warn when the statement would have no effect.
+		p.BufferClientNotice(params.ctx, pgnotice.NewWithSeverityf(
+			"WARNING", "SET CONSTRAINTS can only be used in transaction blocks",
+		))
+		return nil
+	}
+	var constraints []deferredConstraintKey
+	if len(n.n.Names) > 0 {
+		var err error
+		constraints, err = p.resolveDeferrableConstraints(params.ctx, n.n.Names)
+		if err != nil {
+			return err
+		}
+	}
+	s.setMode(constraints, n.n.Deferred)
+	if n.n.Deferred {
+		return nil
+	}
+	// Constraints that become IMMEDIATE are checked right away, as if they had
+	// been violated by the SET CONSTRAINTS statement itself.
+	return validateDeferredConstraints(
+		params.ctx, p.InternalSQLTxn(), p.User(), s, true, /* onlyImmediate */
+	)
+}
+
+// resolveDeferrableConstraints resolves the constraint names used in SET
+// CONSTRAINTS. Like in Postgres, a name that is not qualified with a schema
+// refers to the constraints with that name in the first schema of the search
+// path that has any; all these constraints must be DEFERRABLE.
+func (p *planner) resolveDeferrableConstraints(
+	ctx context.Context, names tree.TableNames,
+) ([]deferredConstraintKey, error) {
+	var ret []deferredConstraintKey
+	// tables caches the tables of the schemas that were looked up, by schema
+	// ID.
+	tables := make(map[descpb.ID][]catalog.TableDescriptor)
+	for i := range names {
+		name := &names[i]
+		dbName := p.CurrentDatabase()
+		if name.ExplicitCatalog {
+			dbName = name.Catalog()
+		}
+		db, err := p.Descriptors().ByNameWithLeased(p.Txn()).Get().Database(ctx, dbName)
+		if err != nil {
+			return nil, err
+		}
+		var schemaNames []string
+		if name.ExplicitSchema {
+			schemaNames = []string{name.Schema()}
+		} else {
+			iter := p.CurrentSearchPath().Iter()
+			for scName, ok := iter.Next(); ok; scName, ok = iter.Next() {
+				schemaNames = append(schemaNames, scName)
+			}
+		}
+		var found []deferredConstraintKey
+		deferrable := true
+		for _, scName := range schemaNames {
+			sc, err := p.Descriptors().ByNameWithLeased(p.Txn()).MaybeGet().Schema(ctx, db, scName)
+			if err != nil {
+				return nil, err
+			}
+			if sc == nil || sc.SchemaKind() == catalog.SchemaVirtual {
+				continue
+			}
+			scTables, ok := tables[sc.GetID()]
+			if !ok {
+				objs, err := p.Descriptors().GetAllObjectsInSchema(ctx, p.Txn(), db, sc)
+				if err != nil {
+					return nil, err
+				}
+				if err := objs.ForEachDescriptor(func(desc catalog.Descriptor) error {
+					if tableDesc, ok := desc.(catalog.TableDescriptor); ok {
+						scTables = append(scTables, tableDesc)
+					}
+					return nil
+				}); err != nil {
+					return nil, err
+				}
+				tables[sc.GetID()] = scTables
+			}
+			for _, tableDesc := range scTables {
+				c := catalog.FindConstraintByName(tableDesc, name.Object())
+				if c == nil {
+					continue
+				}
+				var d semenumpb.ConstraintDeferrability
+				if fk := c.AsForeignKey(); fk != nil {
+					d = fk.ForeignKeyDesc().Deferrability
+				} else if uwi := c.AsUniqueWithoutIndex(); uwi != nil {
+					d = uwi.UniqueWithoutIndexDesc().Deferrability
+				}
+				deferrable = deferrable && tree.ConstraintDeferrability(d).IsDeferrable()
+				found = append(found, deferredConstraintKey{tableID: tableDesc.GetID(), name: name.Object()})
+			}
+			if len(found) > 0 {
+				break
+			}
+		}
+		if len(found) == 0 {
+			return nil, pgerror.Newf(pgcode.UndefinedObject, "constraint %q does not exist", name.Object())
+		}
+		if !deferrable {
+			return nil, pgerror.Newf(pgcode.WrongObjectType, "constraint %q is not deferrable", name.Object())
+		}
+		ret = append(ret, found...)
+	}
+	return ret, nil
+}
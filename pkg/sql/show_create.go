@@ -147,6 +147,11 @@ func ShowCreateTable(
 	for _, idx := range desc.PublicNonPrimaryIndexes() {
 		// Showing the primary index is handled above.
 
+		// Indexes backing exclusion constraints are shown with the constraints.
+		if findExclusionConstraintIndex(desc, idx.GetName()) != nil {
+			continue
+		}
+
 		// Build the PARTITION BY clause.
 		var partitionBuf bytes.Buffer
 		if err := ShowCreatePartitioning(
@@ -593,6 +593,10 @@ func showForeignKeyConstraint(
 		buf.WriteString(" ON UPDATE ")
 		buf.WriteString(tree.ForeignKeyReferenceActionType[fk.OnUpdate].String())
 	}
+	if d := tree.ConstraintDeferrability(fk.Deferrability); d.IsDeferrable() {
+		buf.WriteByte(' ')
+		buf.WriteString(d.String())
+	}
 	if fk.Validity != descpb.ConstraintValidity_Validated {
 		buf.WriteString(" NOT VALID")
 	}
@@ -862,6 +866,10 @@ func showConstraintClause(
 		}
 		f.WriteString(strings.Join(colNames, ", "))
 		f.WriteString(")")
+		if d := tree.ConstraintDeferrability(c.UniqueWithoutIndexDesc().Deferrability); d.IsDeferrable() {
+			f.WriteString(" ")
+			f.WriteString(d.String())
+		}
 		if c.IsPartial() {
 			f.WriteString(" WHERE ")
 			pred, err := schemaexpr.FormatExprForDisplay(
@@ -876,6 +884,42 @@ func showConstraintClause(
 			f.WriteString(" NOT VALID")
 		}
 	}
+	for _, ec := range desc.GetExclusionConstraints() {
+		idx, err := catalog.MustFindIndexByID(desc, ec.IndexID)
+		if err != nil {
+			return err
+		}
+		f.WriteString(",\n\tCONSTRAINT ")
+		formatQuoteNames(&f.Buffer, idx.GetName())
+		f.WriteString(" EXCLUDE ")
+		if idx.GetType() == descpb.IndexDescriptor_INVERTED {
+			f.WriteString("USING GIST ")
+		}
+		f.WriteString("(")
+		for i, elem := range ec.Elements {
+			if i > 0 {
+				f.WriteString(", ")
+			}
+			col, err := catalog.MustFindColumnByID(desc, elem.ColumnID)
+			if err != nil {
+				return err
+			}
+			formatQuoteNames(&f.Buffer, col.GetName())
+			f.WriteString(" WITH ")
+			f.WriteString(elem.Operator)
+		}
+		f.WriteString(")")
+		if idx.IsPartial() {
+			f.WriteString(" WHERE ")
+			pred, err := schemaexpr.FormatExprForDisplay(
+				ctx, desc, idx.GetPredicate(), evalCtx, semaCtx, sessionData, exprFmtFlags,
+			)
+			if err != nil {
+				return err
+			}
+			f.WriteString(pred)
+		}
+	}
 	f.WriteString("\n)")
 	return nil
 }
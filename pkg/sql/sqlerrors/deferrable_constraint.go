@@ -0,0 +1,136 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sqlerrors
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/errors/errorspb"
+	"github.com/gogo/protobuf/proto"
+)
+
+// DeferrableConstraintViolation decorates the error returned by a FK or
+// uniqueness check for a DEFERRABLE constraint. It identifies the violated
+// constraint so that the executor can decide whether to report the violation
+// immediately or to recheck the constraint when the transaction commits.
+type DeferrableConstraintViolation struct {
+	cause error
+	// TableID is the ID of the table on which the constraint is defined (the
+	// origin table for foreign keys).
+	TableID descpb.ID
+	// ConstraintName is the name of the violated constraint.
+	ConstraintName string
+	// Deferrability is the deferrability of the constraint, as recorded in the
+	// table descriptor.
+	Deferrability tree.ConstraintDeferrability
+	// Keys contains the values of the constraint columns of the violating
+	// rows, in the order of the constraint columns. The keys are not preserved
+	// when the error is encoded; Keys is nil if the violating keys are unknown,
+	// in which case the whole constraint must be revalidated.
+	Keys []tree.Datums
+}
+
+// WithDeferrableConstraintViolation decorates a constraint violation error
+// with information about the deferrable constraint that was violated. keyVals
+// are the values of the constraint columns of the violating row.
+func WithDeferrableConstraintViolation(
+	err error,
+	tableID descpb.ID,
+	constraintName string,
+	deferrability tree.ConstraintDeferrability,
+	keyVals tree.Datums,
+) error {
+	if err == nil {
+		return nil
+	}
+	return &DeferrableConstraintViolation{
+		cause:          err,
+		TableID:        tableID,
+		ConstraintName: constraintName,
+		Deferrability:  deferrability,
+		Keys:           []tree.Datums{keyVals},
+	}
+}
+
+// GetDeferrableConstraintViolation returns the DeferrableConstraintViolation
+// in the given error's causal chain, or nil if there is none.
+func GetDeferrableConstraintViolation(err error) *DeferrableConstraintViolation {
+	if v := (*DeferrableConstraintViolation)(nil); errors.As(err, &v) {
+		return v
+	}
+	return nil
+}
+
+var _ error = (*DeferrableConstraintViolation)(nil)
+var _ fmt.Formatter = (*DeferrableConstraintViolation)(nil)
+var _ errors.SafeFormatter = (*DeferrableConstraintViolation)(nil)
+
+func (w *DeferrableConstraintViolation) Error() string { return w.cause.Error() }
+func (w *DeferrableConstraintViolation) Cause() error  { return w.cause }
+func (w *DeferrableConstraintViolation) Unwrap() error { return w.cause }
+
+// Format implements the fmt.Formatter interface.
+func (w *DeferrableConstraintViolation) Format(s fmt.State, verb rune) {
+	errors.FormatError(w, s, verb)
+}
+
+// SafeFormatError implements the errors.SafeFormatter interface.
+func (w *DeferrableConstraintViolation) SafeFormatError(p errors.Printer) (next error) {
+	if p.Detail() {
+		p.Printf("deferrable constraint on table %d", w.TableID)
+	}
+	return w.cause
+}
+
+func encodeDeferrableConstraintViolation(
+	_ context.Context, err error,
+) (string, []string, proto.Message) {
+	w := err.(*DeferrableConstraintViolation)
+	return "", nil, &errorspb.StringsPayload{Details: []string{
+		strconv.FormatUint(uint64(w.TableID), 10),
+		w.ConstraintName,
+		strconv.Itoa(int(w.Deferrability)),
+	}}
+}
+
+func decodeDeferrableConstraintViolation(
+	_ context.Context, cause error, _ string, _ []string, payload proto.Message,
+) error {
+	m, ok := payload.(*errorspb.StringsPayload)
+	if !ok || len(m.Details) != 3 {
+		return nil
+	}
+	tableID, err := strconv.ParseUint(m.Details[0], 10, 32)
+	if err != nil {
+		return nil
+	}
+	deferrability, err := strconv.Atoi(m.Details[2])
+	if err != nil {
+		return nil
+	}
+	return &DeferrableConstraintViolation{
+		cause:          cause,
+		TableID:        descpb.ID(tableID),
+		ConstraintName: m.Details[1],
+		Deferrability:  tree.ConstraintDeferrability(deferrability),
+	}
+}
+
+func init() {
+	key := errors.GetTypeKey((*DeferrableConstraintViolation)(nil))
+	errors.RegisterWrapperEncoder(key, encodeDeferrableConstraintViolation)
+	errors.RegisterWrapperDecoder(key, decodeDeferrableConstraintViolation)
+}
@@ -221,6 +221,16 @@ func NewUnsupportedUnvalidatedConstraintError(constraintType catconstants.Constr
 		"%v constraints cannot be marked NOT VALID", constraintType)
 }
 
+// NewDeferrableUniqueIndexNotSupportedError creates an error for a DEFERRABLE
+// unique constraint that would be enforced by a unique index.
+func NewDeferrableUniqueIndexNotSupportedError() error {
+	return errors.WithHint(
+		unimplemented.NewWithIssuef(31632,
+			"DEFERRABLE unique constraints backed by an index are not supported"),
+		"use UNIQUE WITHOUT INDEX to create a deferrable unique constraint",
+	)
+}
+
 // NewInvalidActionOnComputedFKColumnError creates an error when there is an
 // attempt to have an unsupported action on a FK over a computed column.
 func NewInvalidActionOnComputedFKColumnError(onUpdateAction bool) error {
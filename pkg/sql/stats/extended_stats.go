@@ -0,0 +1,220 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package stats
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// mostCommonValueMinRatio is the minimum ratio between the number of samples
+// of a value and the average number of samples per distinct value for the
+// value to be considered one of the most common values. This is the same
+// ratio used by Postgres.
+const mostCommonValueMinRatio = 1.25
+
+// BuildExtendedStatistics adds the most common values of the columns of a
+// multi-column statistic and the functional dependencies between them, as
+// requested, to the given histogram, which is created if nil. The samples are
+// tuples of the values of the columns, of type colType, omitting the rows in
+// which all of the columns are NULL. numRows is the total number of rows from
+// which the values were sampled, excluding those rows, and distinctCount is
+// the number of distinct tuples among them. At most maxValues most common
+// values are kept.
+//
+// If there are no samples, the histogram is returned unchanged.
+func BuildExtendedStatistics(
+	ctx context.Context,
+	compareCtx tree.CompareContext,
+	h *HistogramData,
+	colType *types.T,
+	samples tree.Datums,
+	numRows, distinctCount int64,
+	maxValues int,
+	mostCommonValues, dependencies bool,
+	st *cluster.Settings,
+) (*HistogramData, error) {
+	if len(samples) == 0 || (!mostCommonValues && !dependencies) {
+		return h, nil
+	}
+	if colType.Family() != types.TupleFamily {
+		return nil, errors.AssertionFailedf("extended statistics require a tuple type, found %s", colType)
+	}
+	if numRows < int64(len(samples)) {
+		return nil, errors.Errorf("more samples than rows")
+	}
+	if h == nil {
+		hd, err := histogram{buckets: make([]cat.HistogramBucket, 0)}.toHistogramData(ctx, colType, st)
+		if err != nil {
+			return nil, err
+		}
+		h = &hd
+	}
+	if mostCommonValues {
+		mcvs, err := buildMostCommonValues(ctx, compareCtx, h.Version, samples, numRows, distinctCount, maxValues)
+		if err != nil {
+			return nil, err
+		}
+		h.MostCommonValues = mcvs
+	}
+	if dependencies {
+		h.Dependencies = buildDependencies(ctx, compareCtx, samples, len(colType.TupleContents()))
+	}
+	return h, nil
+}
+
+// buildMostCommonValues returns the encoded most common values of the given
+// samples, in ascending order. Like Postgres, a value is common if it appears
+// in the samples significantly more often than the average value, unless all
+// of the distinct values appear in the samples, in which case all of them are
+// kept if there are no more than maxValues.
+func buildMostCommonValues(
+	ctx context.Context,
+	compareCtx tree.CompareContext,
+	version HistogramVersion,
+	samples tree.Datums,
+	numRows, distinctCount int64,
+	maxValues int,
+) ([]HistogramData_MostCommonValue, error) {
+	sort.Slice(samples, func(i, j int) bool {
+		cmp, err := samples[i].Compare(ctx, compareCtx, samples[j])
+		if err != nil {
+			panic(err)
+		}
+		return cmp < 0
+	})
+
+	// Count the runs of equal values.
+	type run struct {
+		value tree.Datum
+		count int
+	}
+	var runs []run
+	for i, d := range samples {
+		if i > 0 {
+			cmp, err := d.Compare(ctx, compareCtx, runs[len(runs)-1].value)
+			if err != nil {
+				return nil, err
+			}
+			if cmp == 0 {
+				runs[len(runs)-1].count++
+				continue
+			}
+		}
+		runs = append(runs, run{value: d, count: 1})
+	}
+
+	candidates := make([]int, 0, len(runs))
+	if distinctCount <= int64(len(runs)) && len(runs) <= maxValues {
+		for i := range runs {
+			candidates = append(candidates, i)
+		}
+	} else {
+		minCount := mostCommonValueMinRatio * float64(len(samples)) / float64(len(runs))
+		for i := range runs {
+			if runs[i].count >= 2 && float64(runs[i].count) > minCount {
+				candidates = append(candidates, i)
+			}
+		}
+		if len(candidates) > maxValues {
+			sort.SliceStable(candidates, func(i, j int) bool {
+				return runs[candidates[i]].count > runs[candidates[j]].count
+			})
+			candidates = candidates[:maxValues]
+			sort.Ints(candidates)
+		}
+	}
+
+	mcvs := make([]HistogramData_MostCommonValue, len(candidates))
+	rowsPerSample := float64(numRows) / float64(len(samples))
+	for i, idx := range candidates {
+		encoded, err := EncodeUpperBound(version, runs[idx].value)
+		if err != nil {
+			return nil, err
+		}
+		mcvs[i] = HistogramData_MostCommonValue{
+			NumEq: int64(math.Round(float64(runs[idx].count) * rowsPerSample)),
+			Value: encoded,
+		}
+	}
+	return mcvs, nil
+}
+
+// buildDependencies returns the degrees of the functional dependencies
+// between each ordered pair of the columns of the given tuples, omitting
+// those that hold for none of the rows. Like Postgres, the degree of the
+// dependency from column a to column b is the fraction of the samples in
+// groups with the same value of a that all have the same value of b.
+func buildDependencies(
+	ctx context.Context, compareCtx tree.CompareContext, samples tree.Datums, numCols int,
+) []HistogramData_Dependency {
+	compare := func(a, b tree.Datum) int {
+		cmp, err := a.Compare(ctx, compareCtx, b)
+		if err != nil {
+			panic(err)
+		}
+		return cmp
+	}
+	tuples := make([]*tree.DTuple, len(samples))
+	for i := range samples {
+		tuples[i] = tree.MustBeDTuple(samples[i])
+	}
+
+	var deps []HistogramData_Dependency
+	for from := 0; from < numCols; from++ {
+		for to := 0; to < numCols; to++ {
+			if from == to {
+				continue
+			}
+			sort.Slice(tuples, func(i, j int) bool {
+				if cmp := compare(tuples[i].D[from], tuples[j].D[from]); cmp != 0 {
+					return cmp < 0
+				}
+				return compare(tuples[i].D[to], tuples[j].D[to]) < 0
+			})
+			var supporting int
+			for start := 0; start < len(tuples); {
+				end := start + 1
+				for end < len(tuples) && compare(tuples[start].D[from], tuples[end].D[from]) == 0 {
+					end++
+				}
+				// The values of the dependent column are sorted within the group,
+				// so they are all equal if the first and last are.
+				if compare(tuples[start].D[to], tuples[end-1].D[to]) == 0 {
+					supporting += end - start
+				}
+				start = end
+			}
+			if supporting > 0 {
+				deps = append(deps, HistogramData_Dependency{
+					From:   uint32(from),
+					To:     uint32(to),
+					Degree: float64(supporting) / float64(len(tuples)),
+				})
+			}
+		}
+	}
+	return deps
+}
+
+// HasExtendedStats returns true if the histogram holds the most common values
+// or the functional dependencies of a multi-column statistic.
+func (histogramData *HistogramData) HasExtendedStats() bool {
+	return histogramData != nil &&
+		(len(histogramData.MostCommonValues) > 0 || len(histogramData.Dependencies) > 0)
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package stats
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildExtendedStatistics(t *testing.T) {
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := eval.NewTestingEvalContext(st)
+	typ := types.MakeTuple([]*types.T{types.Int, types.String})
+
+	// The samples are (1, 'x') four times, (2, 'y') three times, and (3, 'y'),
+	// (4, 'z') and (5, 'z') once. Column 0 determines column 1 in all of the
+	// samples, and column 1 determines column 0 only in the 'x' group.
+	makeSamples := func() tree.Datums {
+		var samples tree.Datums
+		add := func(a int, b string, n int) {
+			for i := 0; i < n; i++ {
+				samples = append(samples, tree.NewDTuple(
+					typ, tree.NewDInt(tree.DInt(a)), tree.NewDString(b),
+				))
+			}
+		}
+		add(5, "z", 1)
+		add(1, "x", 4)
+		add(3, "y", 1)
+		add(2, "y", 3)
+		add(4, "z", 1)
+		return samples
+	}
+
+	testCases := []struct {
+		distinctCount int64
+		maxValues     int
+		// expMCVs are the expected most common values, formatted as tuples,
+		// followed by their counts.
+		expMCVs []string
+	}{
+		{
+			// Only values sampled significantly more often than the average are
+			// common.
+			distinctCount: 50,
+			maxValues:     10,
+			expMCVs:       []string{"(1, 'x') 40", "(2, 'y') 30"},
+		},
+		{
+			// The most common of them are kept.
+			distinctCount: 50,
+			maxValues:     1,
+			expMCVs:       []string{"(1, 'x') 40"},
+		},
+		{
+			// All of the distinct values were sampled.
+			distinctCount: 5,
+			maxValues:     10,
+			expMCVs: []string{
+				"(1, 'x') 40", "(2, 'y') 30", "(3, 'y') 10", "(4, 'z') 10", "(5, 'z') 10",
+			},
+		},
+		{
+			// Too many distinct values to keep all of them.
+			distinctCount: 5,
+			maxValues:     4,
+			expMCVs:       []string{"(1, 'x') 40", "(2, 'y') 30"},
+		},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			h, err := BuildExtendedStatistics(
+				ctx, evalCtx, nil /* h */, typ, makeSamples(), 100 /* numRows */, tc.distinctCount,
+				tc.maxValues, true /* mostCommonValues */, true /* dependencies */, st,
+			)
+			require.NoError(t, err)
+			require.True(t, h.HasExtendedStats())
+			require.Empty(t, h.Buckets)
+			require.Equal(t, typ, h.ColumnType)
+
+			var a tree.DatumAlloc
+			mcvs := make([]string, len(h.MostCommonValues))
+			for j, mcv := range h.MostCommonValues {
+				d, err := DecodeUpperBound(h.Version, h.ColumnType, &a, mcv.Value)
+				require.NoError(t, err)
+				mcvs[j] = fmt.Sprintf("%s %d", d, mcv.NumEq)
+			}
+			require.Equal(t, tc.expMCVs, mcvs)
+
+			require.Equal(t, []HistogramData_Dependency{
+				{From: 0, To: 1, Degree: 1},
+				{From: 1, To: 0, Degree: 0.4},
+			}, h.Dependencies)
+		})
+	}
+
+	t.Run("no samples", func(t *testing.T) {
+		h, err := BuildExtendedStatistics(
+			ctx, evalCtx, nil /* h */, typ, nil /* samples */, 0 /* numRows */, 0, /* distinctCount */
+			10 /* maxValues */, true /* mostCommonValues */, true /* dependencies */, st,
+		)
+		require.NoError(t, err)
+		require.Nil(t, h)
+	})
+}
@@ -128,12 +128,21 @@ func ForecastTableStatistics(
 		colKey := MakeSortedColStatKey(stat.ColumnIDs)
 		obs, ok := observedByCols[colKey]
 		if !ok {
+			// The most common values and functional dependencies of multi-column
+			// statistics cannot be forecast, and a forecast without them would
+			// replace the latest statistics, so skip these columns.
+			if stat.HistogramData.HasExtendedStats() {
+				observedByCols[colKey] = nil
+				continue
+			}
 			forecastCols = append(forecastCols, colKey)
+		} else if obs == nil {
+			continue
 		}
 		observedByCols[colKey] = append(obs, stat)
 	}
 
-	if len(observedByCols) == 0 {
+	if len(forecastCols) == 0 {
 		// No suitable stats.
 		return nil
 	}
@@ -50,6 +50,17 @@ var HistogramClusterMode = settings.RegisterBoolSetting(
 	true,
 	settings.WithPublic)
 
+// MultiColumnHistogramClusterMode controls whether histograms are collected
+// for statistics explicitly requested on multiple columns. The histogram of a
+// multi-column statistic is built over tuples of the column values, which lets
+// the optimizer estimate the selectivity of equality predicates on correlated
+// columns.
+var MultiColumnHistogramClusterMode = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"sql.stats.multi_column_histograms.enabled",
+	"set to true to collect histograms for statistics created on multiple columns",
+	false)
+
 // HistogramVersion identifies histogram versions.
 type HistogramVersion uint32
 
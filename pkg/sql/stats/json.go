@@ -138,6 +138,11 @@ func (js *JSONStatistic) GetHistogram(
 	if js.HistogramColumnType == "" {
 		return nil, nil
 	}
+	// Multi-column histograms are built over tuples, whose upper bounds cannot
+	// be parsed without the types of the tuple's columns, so they are dropped.
+	if js.HistogramColumnType == types.AnyTuple.SQLString() {
+		return nil, nil
+	}
 	h := &HistogramData{}
 	colTypeRef, err := parser.GetTypeFromValidSQLSyntax(js.HistogramColumnType)
 	if err != nil {
@@ -240,6 +240,45 @@ func (sr *SampleReservoir) GetNonNullDatums(
 	return
 }
 
+// GetNonNullTuples returns the values of the specified columns as tuples of
+// the given tuple type, omitting the rows in which all of the columns are
+// NULL. Like GetNonNullDatums, the capacity of the reservoir may shrink if we
+// hit a memory limit while building the return slice.
+func (sr *SampleReservoir) GetNonNullTuples(
+	ctx context.Context, memAcc *mon.BoundAccount, colIdxs []int, typ *types.T,
+) (values tree.Datums, err error) {
+	err = sr.retryMaybeResize(ctx, func() error {
+		// Account for the memory we'll use copying the samples into values. The
+		// tuples only reference the sampled datums, so only the overhead of the
+		// tuples themselves is accounted for.
+		if memAcc != nil {
+			perTuple := memsize.DatumOverhead * int64(len(colIdxs)+1)
+			if err := memAcc.Grow(ctx, perTuple*int64(len(sr.samples))); err != nil {
+				return err
+			}
+		}
+		values = make(tree.Datums, 0, len(sr.samples))
+		for _, sample := range sr.samples {
+			tuple := tree.NewDTupleWithLen(typ, len(colIdxs))
+			allNull := true
+			for i, colIdx := range colIdxs {
+				d := sample.Row[colIdx].Datum
+				if d == nil {
+					values = nil
+					return errors.AssertionFailedf("value in column %d not decoded", colIdx)
+				}
+				tuple.D[i] = d
+				allNull = allNull && d == tree.DNull
+			}
+			if !allNull {
+				values = append(values, tuple)
+			}
+		}
+		return nil
+	})
+	return
+}
+
 func (sr *SampleReservoir) copyRow(
 	ctx context.Context, evalCtx *eval.Context, dst, src rowenc.EncDatumRow,
 ) error {
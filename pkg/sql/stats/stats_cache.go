@@ -49,6 +49,10 @@ type TableStatistic struct {
 
 	// Histogram is the decoded histogram data.
 	Histogram []cat.HistogramBucket
+
+	// MostCommonValues are the decoded most common values of a multi-column
+	// statistic.
+	MostCommonValues []cat.MostCommonValue
 }
 
 // A TableStatisticsCache contains two underlying LRU caches:
@@ -684,6 +688,11 @@ func (sc *TableStatisticsCache) parseStats(
 			return nil, nil, err
 		}
 	}
+	if res.HistogramData != nil && len(res.HistogramData.MostCommonValues) > 0 {
+		if err := DecodeMostCommonValues(res); err != nil {
+			return nil, nil, err
+		}
+	}
 	return res, udt, nil
 }
 
@@ -730,6 +739,25 @@ func DecodeHistogramBuckets(tabStat *TableStatistic) error {
 	return nil
 }
 
+// DecodeMostCommonValues decodes the encoded most common values in the
+// HistogramData of tabStat and writes them into tabStat.MostCommonValues.
+func DecodeMostCommonValues(tabStat *TableStatistic) error {
+	h := tabStat.HistogramData
+	tabStat.MostCommonValues = make([]cat.MostCommonValue, len(h.MostCommonValues))
+	var a tree.DatumAlloc
+	for i := range h.MostCommonValues {
+		datum, err := DecodeUpperBound(h.Version, h.ColumnType, &a, h.MostCommonValues[i].Value)
+		if err != nil {
+			return err
+		}
+		tabStat.MostCommonValues[i] = cat.MostCommonValue{
+			NumEq: float64(h.MostCommonValues[i].NumEq),
+			Value: datum,
+		}
+	}
+	return nil
+}
+
 // setHistogramBuckets shallow-copies the passed histogram into the
 // TableStatistic, and prepends a bucket for NULL rows using the
 // TableStatistic's null count. The resulting TableStatistic looks the same as
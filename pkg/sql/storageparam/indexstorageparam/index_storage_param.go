@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/storageparam"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
 )
@@ -107,6 +108,32 @@ func (po *Setter) applyGeometryIndexSetting(
 	return nil
 }
 
+func (po *Setter) applyVectorIndexSetting(
+	ctx context.Context, evalCtx *eval.Context, key string, expr tree.Datum,
+) error {
+	if po.IndexDesc.VecConfig.IsEmpty() {
+		return pgerror.Newf(pgcode.InvalidParameterValue, "%q can only be applied to vector indexes", key)
+	}
+	// The partitioning of a vector index is fixed when its entries are
+	// encoded, so it cannot be changed without rebuilding the index. Indexes
+	// that are being created do not have an ID yet.
+	if po.IndexDesc.ID != 0 {
+		return pgerror.Newf(
+			pgcode.FeatureNotSupported, "%q can only be set when the vector index is created", key,
+		)
+	}
+	val, err := paramparse.DatumAsInt(ctx, evalCtx, key, expr)
+	if err != nil {
+		return errors.Wrapf(err, "error decoding %q", key)
+	}
+	// The bounds match the ones of pgvector's ivfflat indexes.
+	if val < 1 || val > 32768 {
+		return pgerror.Newf(pgcode.InvalidParameterValue, "%q value must be between 1 and 32768 inclusive", key)
+	}
+	po.IndexDesc.VecConfig.PartitionBits = vecindex.PartitionBitsForLists(val)
+	return nil
+}
+
 // Set implements the Setter interface.
 func (po *Setter) Set(
 	ctx context.Context,
@@ -126,6 +153,8 @@ func (po *Setter) Set(
 		return po.applyS2ConfigSetting(ctx, evalCtx, key, expr, 1, 32)
 	case `geometry_min_x`, `geometry_max_x`, `geometry_min_y`, `geometry_max_y`:
 		return po.applyGeometryIndexSetting(ctx, evalCtx, key, expr)
+	case `lists`:
+		return po.applyVectorIndexSetting(ctx, evalCtx, key, expr)
 	// `bucket_count` is handled in schema changer when creating hash sharded
 	// indexes.
 	case `bucket_count`:
@@ -0,0 +1,51 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package indexstorageparam
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVectorIndexListsOnlySetOnCreate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	evalCtx := eval.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+	defer evalCtx.Stop(ctx)
+	semaCtx := tree.MakeSemaContext(nil /* resolver */)
+
+	// An index that is being created has no ID yet.
+	idx := descpb.IndexDescriptor{VecConfig: catpb.VectorIndexConfig{Dims: 2}}
+	setter := &Setter{IndexDesc: &idx}
+	require.NoError(t, setter.Set(ctx, &semaCtx, &evalCtx, "lists", tree.NewDInt(4)))
+	require.Equal(t, int32(2), idx.VecConfig.PartitionBits)
+
+	// The number of lists of an existing index cannot be changed.
+	idx.ID = 2
+	err := setter.Set(ctx, &semaCtx, &evalCtx, "lists", tree.NewDInt(8))
+	require.Error(t, err)
+	require.Equal(t, pgcode.FeatureNotSupported, pgerror.GetPGCode(err))
+	require.Contains(t, err.Error(), `"lists" can only be set when the vector index is created`)
+	require.Equal(t, int32(2), idx.VecConfig.PartitionBits)
+}
@@ -0,0 +1,323 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/errors"
+)
+
+// ScanTableRevisions is part of the eval.Planner interface.
+//
+// The revisions are read with ExportRequests using MVCCFilter_All, which
+// evaluate on top of an MVCCIncrementalIterator. Since the ExportRequest reads
+// at startTime, the scan fails if startTime is below the GC threshold of any
+// range in the table, so the history that can be scanned is bounded by the
+// table's GC TTL and any protected timestamps covering the table.
+func (p *planner) ScanTableRevisions(
+	ctx context.Context, tableID descpb.ID, startTime, endTime hlc.Timestamp,
+) (eval.InternalRows, error) {
+	desc, err := p.Descriptors().ByIDWithLeased(p.Txn()).WithoutNonPublic().Get().Table(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	if !desc.IsTable() || desc.IsVirtualTable() {
+		return nil, pgerror.Newf(pgcode.WrongObjectType, "%q is not a table", desc.GetName())
+	}
+	if err := p.CheckPrivilege(ctx, desc, privilege.SELECT); err != nil {
+		return nil, err
+	}
+	// Each column family is stored in its own KV, so a single revision of a
+	// row in a table with multiple families would only contain the columns
+	// of the family that was written.
+	if len(desc.GetFamilies()) > 1 {
+		return nil, unimplemented.Newf("table revisions with column families",
+			"scanning the revisions of a table with multiple column families is not supported")
+	}
+	if endTime.Less(startTime) {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"end time %s is before start time %s", endTime, startTime)
+	}
+	if readTS := p.Txn().ReadTimestamp(); readTS.Less(endTime) {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"end time %s is after the transaction read timestamp %s", endTime, readTS)
+	}
+
+	var cols []catalog.Column
+	for _, col := range desc.PublicColumns() {
+		if col.IsVirtual() {
+			continue
+		}
+		cols = append(cols, col)
+	}
+	it, err := newTableRevisionsIterator(
+		ctx, p.ExecCfg().DB, p.ExecCfg().Codec, desc, cols, startTime, endTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	it.evalCtx = p.EvalContext()
+	return it, nil
+}
+
+// newTableRevisionsIterator returns a tableRevisionsIterator over the MVCC
+// revisions of the rows in the primary index of the given table with
+// timestamps in [startTime, endTime], decoding the given columns.
+func newTableRevisionsIterator(
+	ctx context.Context,
+	db *kv.DB,
+	codec keys.SQLCodec,
+	desc catalog.TableDescriptor,
+	cols []catalog.Column,
+	startTime, endTime hlc.Timestamp,
+) (*tableRevisionsIterator, error) {
+	colIDs := make([]descpb.ColumnID, len(cols))
+	for i, col := range cols {
+		colIDs[i] = col.GetID()
+	}
+	var spec fetchpb.IndexFetchSpec
+	if err := rowenc.InitIndexFetchSpec(
+		&spec, codec, desc, desc.GetPrimaryIndex(), colIDs,
+	); err != nil {
+		return nil, err
+	}
+
+	it := &tableRevisionsIterator{
+		db:        db,
+		span:      desc.PrimaryIndexSpan(codec),
+		startTime: startTime,
+		endTime:   endTime,
+		cols:      cols,
+	}
+	if err := it.fetcher.Init(ctx, row.FetcherInitArgs{
+		WillUseKVProvider: true,
+		Alloc:             &it.alloc,
+		Spec:              &spec,
+	}); err != nil {
+		return nil, err
+	}
+	return it, nil
+}
+
+// tableRevisionsIterator iterates over the MVCC revisions of the rows in a
+// table's primary index. As an eval.InternalRows, it produces one row per
+// revision containing the MVCC timestamp of the revision, whether the revision
+// is a deletion tombstone, and the revision's column values as a JSON object.
+type tableRevisionsIterator struct {
+	db *kv.DB
+	// span is the remaining part of the primary index that has not been
+	// exported yet. It is empty once all ExportRequests have been sent.
+	span               roachpb.Span
+	startTime, endTime hlc.Timestamp
+	cols               []catalog.Column
+	// evalCtx is only used to produce JSON rows, and is nil if the iterator is
+	// only accessed through nextRevision.
+	evalCtx *eval.Context
+
+	fetcher    row.Fetcher
+	kvProvider row.KVProvider
+	alloc      tree.DatumAlloc
+
+	// kvs are the revisions returned by the last ExportRequest that have not
+	// been decoded yet.
+	kvs  []roachpb.KeyValue
+	done bool
+
+	// The current revision. datums contains the decoded values of cols.
+	datums  tree.Datums
+	deleted bool
+	ts      hlc.Timestamp
+	cur     tree.Datums
+}
+
+var _ eval.InternalRows = &tableRevisionsIterator{}
+
+// Next is part of the eval.InternalRows interface.
+func (it *tableRevisionsIterator) Next(ctx context.Context) (bool, error) {
+	ok, err := it.nextRevision(ctx)
+	if !ok || err != nil {
+		return false, err
+	}
+	dcc := it.evalCtx.SessionData().DataConversionConfig
+	loc := it.evalCtx.GetLocation()
+	builder := json.NewObjectBuilder(len(it.cols))
+	for i, col := range it.cols {
+		j, err := tree.AsJSON(it.datums[i], dcc, loc)
+		if err != nil {
+			return false, err
+		}
+		builder.Add(col.GetName(), j)
+	}
+	it.cur = tree.Datums{
+		eval.TimestampToDecimalDatum(it.ts),
+		tree.MakeDBool(tree.DBool(it.deleted)),
+		tree.NewDJSON(builder.Build()),
+	}
+	return true, nil
+}
+
+// nextRevision advances the iterator to the next revision, returning false
+// once all revisions have been produced.
+func (it *tableRevisionsIterator) nextRevision(ctx context.Context) (bool, error) {
+	for !it.done {
+		if len(it.kvs) == 0 {
+			if len(it.span.Key) == 0 {
+				it.done = true
+				break
+			}
+			if err := it.export(ctx); err != nil {
+				it.done = true
+				return false, err
+			}
+			continue
+		}
+		kv := it.kvs[0]
+		it.kvs = it.kvs[1:]
+		ok, err := it.decode(ctx, kv)
+		if err != nil {
+			it.done = true
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// export sends an ExportRequest for the remaining span and buffers the
+// returned revisions.
+func (it *tableRevisionsIterator) export(ctx context.Context) error {
+	header := kvpb.Header{
+		Timestamp:                   it.endTime,
+		ReturnElasticCPUResumeSpans: true,
+	}
+	req := &kvpb.ExportRequest{
+		RequestHeader: kvpb.RequestHeader{Key: it.span.Key, EndKey: it.span.EndKey},
+		MVCCFilter:    kvpb.MVCCFilter_All,
+	}
+	// The start time of an ExportRequest is exclusive.
+	if !it.startTime.IsEmpty() {
+		req.StartTime = it.startTime.Prev()
+	}
+	rawResp, pErr := kv.SendWrappedWith(ctx, it.db.NonTransactionalSender(), header, req)
+	if pErr != nil {
+		err := pErr.GoError()
+		if errors.HasType(err, (*kvpb.BatchTimestampBeforeGCError)(nil)) {
+			err = errors.WithHint(err, "revisions older than the GC TTL of the table "+
+				"are only retained if they are covered by a protected timestamp")
+		}
+		return err
+	}
+	resp := rawResp.(*kvpb.ExportResponse)
+	for _, file := range resp.Files {
+		if err := it.bufferSST(file); err != nil {
+			return err
+		}
+	}
+	it.span = roachpb.Span{}
+	if resp.ResumeSpan != nil {
+		it.span = *resp.ResumeSpan
+	}
+	return nil
+}
+
+// bufferSST appends the point keys of an exported SST to the buffered
+// revisions. MVCC range tombstones are only written when an entire table or
+// index is cleared, so they are not reported as row deletions.
+func (it *tableRevisionsIterator) bufferSST(file kvpb.ExportResponse_File) error {
+	iter, err := storage.NewMemSSTIterator(file.SST, false /* verify */, storage.IterOptions{
+		KeyTypes:   storage.IterKeyTypePointsOnly,
+		LowerBound: keys.MinKey,
+		UpperBound: keys.MaxKey,
+	})
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+	for iter.SeekGE(storage.NilKey); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+		key := iter.UnsafeKey().Clone()
+		v, err := iter.UnsafeValue()
+		if err != nil {
+			return err
+		}
+		mvccValue, err := storage.DecodeMVCCValue(v)
+		if err != nil {
+			return err
+		}
+		value := mvccValue.Value
+		value.RawBytes = append([]byte(nil), value.RawBytes...)
+		value.Timestamp = key.Timestamp
+		it.kvs = append(it.kvs, roachpb.KeyValue{Key: key.Key, Value: value})
+	}
+}
+
+// decode decodes a single revision into the current revision. It returns
+// false if the KV did not produce a row.
+func (it *tableRevisionsIterator) decode(ctx context.Context, kv roachpb.KeyValue) (bool, error) {
+	// The revisions are fed to the fetcher one at a time so that consecutive
+	// revisions of the same row are not merged.
+	it.kvProvider.KVs = append(it.kvProvider.KVs[:0], kv)
+	if err := it.fetcher.ConsumeKVProvider(ctx, &it.kvProvider); err != nil {
+		return false, err
+	}
+	encRow, _, err := it.fetcher.NextRow(ctx)
+	if err != nil || encRow == nil {
+		return false, err
+	}
+	it.datums = make(tree.Datums, len(it.cols))
+	for i, col := range it.cols {
+		if err := encRow[i].EnsureDecoded(col.GetType(), &it.alloc); err != nil {
+			return false, err
+		}
+		it.datums[i] = encRow[i].Datum
+	}
+	it.deleted = it.fetcher.RowIsDeleted()
+	it.ts = kv.Value.Timestamp
+	return true, nil
+}
+
+// Cur is part of the eval.InternalRows interface.
+func (it *tableRevisionsIterator) Cur() tree.Datums {
+	return it.cur
+}
+
+// Close is part of the eval.InternalRows interface.
+func (it *tableRevisionsIterator) Close() error {
+	it.done = true
+	it.kvs = nil
+	it.fetcher.Close(context.TODO())
+	return nil
+}
@@ -153,6 +153,9 @@ type tableWriterBase struct {
 	// originID is an identifier for the cluster that originally wrote the data
 	// being written by the table writer during Logical Data Replication.
 	originID uint32
+	// planner is used to check for pending notifications before auto
+	// committing, if set.
+	planner eval.Planner
 }
 
 var maxBatchBytes = settings.RegisterByteSizeSetting(
@@ -173,7 +176,9 @@ func (tb *tableWriterBase) init(
 	tb.lockTimeout = 0
 	tb.deadlockTimeout = 0
 	tb.originID = 0
+	tb.planner = nil
 	if evalCtx != nil {
+		tb.planner = evalCtx.Planner
 		tb.lockTimeout = evalCtx.SessionData().LockTimeout
 		tb.deadlockTimeout = evalCtx.SessionData().DeadlockTimeout
 		tb.originID = evalCtx.SessionData().OriginIDForLogicalDataReplication
@@ -231,7 +236,10 @@ func (tb *tableWriterBase) finalize(ctx context.Context) (err error) {
 		// Also, we don't want to try to commit here if the deadline is expired.
 		// If we bubble back up to SQL then maybe we can get a fresh deadline
 		// before committing.
-		!tb.txn.DeadlineLikelySufficient() {
+		!tb.txn.DeadlineLikelySufficient() &&
+		// Notifications are written by the transaction when it commits, so
+		// the batch cannot commit it if there are any.
+		(tb.planner == nil || !tb.planner.HasPendingNotifications()) {
 		log.Event(ctx, "autocommit enabled")
 		log.VEventf(ctx, 2, "writing batch with %d requests and committing", len(tb.b.Requests()))
 		// An auto-txn can commit the transaction with the batch. This is an
@@ -39,6 +39,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/regions"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/plpgsqltree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/plpgsqltree/utils"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -508,6 +509,15 @@ func (t *typeSchemaChanger) exec(ctx context.Context) error {
 		}
 	}
 
+	// Validate the existing values of a domain against any constraints added
+	// to it. The leases were refreshed above, so all new values are already
+	// checked against these constraints.
+	if typeDesc.AsDomainTypeDescriptor() != nil && !typeDesc.Dropped() {
+		if err := t.validateDomainConstraints(ctx); err != nil {
+			return err
+		}
+	}
+
 	// If the type is being dropped, remove the descriptor here only
 	// if the declarative schema changer is not in use.
 	if typeDesc.Dropped() && typeDesc.GetDeclarativeSchemaChangerState() == nil {
@@ -524,6 +534,174 @@ func (t *typeSchemaChanger) exec(ctx context.Context) error {
 	return nil
 }
 
+// validateDomainConstraints checks that the values of the domain stored in the
+// tables which reference it satisfy the constraints of the domain which are
+// being validated, and marks those constraints as validated.
+func (t *typeSchemaChanger) validateDomainConstraints(ctx context.Context) error {
+	var validateNotNull bool
+	var checks []descpb.TypeDescriptor_Domain_DomainConstraint
+	validate := func(ctx context.Context, txn descs.Txn) error {
+		typeDesc, err := txn.Descriptors().MutableByID(txn.KV()).Type(ctx, t.typeID)
+		if err != nil {
+			return err
+		}
+		validateNotNull = typeDesc.Domain.NotNull &&
+			typeDesc.Domain.NotNullValidity == descpb.ConstraintValidity_Validating
+		checks = checks[:0]
+		for _, c := range typeDesc.Domain.Constraints {
+			if c.Validity == descpb.ConstraintValidity_Validating {
+				checks = append(checks, c)
+			}
+		}
+		return validateDomainValues(ctx, txn, typeDesc, validateNotNull, checks)
+	}
+	if err := t.execCfg.InternalDB.DescsTxn(ctx, validate); err != nil {
+		return err
+	}
+	if !validateNotNull && len(checks) == 0 {
+		return nil
+	}
+
+	// Now that the values are known to satisfy the constraints, mark them as
+	// validated. Constraints which were dropped in the meantime are ignored.
+	return t.execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+		typeDesc, err := txn.Descriptors().MutableByID(txn.KV()).Type(ctx, t.typeID)
+		if err != nil {
+			return err
+		}
+		if validateNotNull && typeDesc.Domain.NotNull {
+			typeDesc.Domain.NotNullValidity = descpb.ConstraintValidity_Validated
+		}
+		for i := range typeDesc.Domain.Constraints {
+			c := &typeDesc.Domain.Constraints[i]
+			for j := range checks {
+				if c.Name == checks[j].Name && c.Validity == descpb.ConstraintValidity_Validating {
+					c.Validity = descpb.ConstraintValidity_Validated
+				}
+			}
+		}
+		return txn.Descriptors().WriteDesc(ctx, true /* kvTrace */, typeDesc, txn.KV())
+	})
+}
+
+// validateDomainValues returns an error if the tables which reference the given
+// domain contain a value of the domain that violates one of the given
+// constraints.
+func validateDomainValues(
+	ctx context.Context,
+	txn descs.Txn,
+	typeDesc *typedesc.Mutable,
+	validateNotNull bool,
+	checks []descpb.TypeDescriptor_Domain_DomainConstraint,
+) error {
+	if !validateNotNull && len(checks) == 0 {
+		return nil
+	}
+	descGetter := txn.Descriptors().ByIDWithoutLeased(txn.KV()).WithoutNonPublic().Get()
+	dbDesc, err := descGetter.Database(ctx, typeDesc.ParentID)
+	if err != nil {
+		return err
+	}
+	override := sessiondata.InternalExecutorOverride{
+		User:     username.NodeUserName(),
+		Database: dbDesc.GetName(),
+	}
+	for _, id := range typeDesc.ReferencingDescriptorIDs {
+		desc, err := descGetter.Desc(ctx, id)
+		if err != nil {
+			return err
+		}
+		tableDesc, ok := desc.(catalog.TableDescriptor)
+		if !ok || tableDesc.IsView() {
+			continue
+		}
+		if err := validateDomainValuesInTable(
+			ctx, txn, override, typeDesc, tableDesc, validateNotNull, checks,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateDomainValuesInTable returns an error if a column of the given table
+// which is of the domain type contains a value that violates one of the given
+// constraints.
+func validateDomainValuesInTable(
+	ctx context.Context,
+	txn isql.Txn,
+	override sessiondata.InternalExecutorOverride,
+	typeDesc *typedesc.Mutable,
+	desc catalog.TableDescriptor,
+	validateNotNull bool,
+	checks []descpb.TypeDescriptor_Domain_DomainConstraint,
+) error {
+	domainOID := catid.TypeIDToOID(typeDesc.ID)
+	for _, col := range desc.PublicColumns() {
+		if col.GetType().Oid() != domainOID {
+			continue
+		}
+		colName := col.ColName()
+		if validateNotNull {
+			query := fmt.Sprintf(
+				"SELECT 1 FROM [%d AS t] WHERE t.%s IS NULL LIMIT 1", desc.GetID(), colName.String(),
+			)
+			row, err := txn.QueryRowEx(ctx, "validate-domain-not-null", txn.KV(), override, query)
+			if err != nil {
+				return err
+			}
+			if row != nil {
+				return pgerror.Newf(pgcode.NotNullViolation,
+					"column %q of table %q contains null values", col.GetName(), desc.GetName())
+			}
+		}
+		for i := range checks {
+			// The check expression refers to the value being checked as VALUE, so
+			// the column is projected under that name.
+			query := fmt.Sprintf(
+				"SELECT 1 FROM (SELECT t.%s::%s AS value FROM [%d AS t]) WHERE NOT (%s) LIMIT 1",
+				colName.String(), typeDesc.Domain.BaseType.SQLString(), desc.GetID(), checks[i].Expr,
+			)
+			row, err := txn.QueryRowEx(ctx, "validate-domain-check", txn.KV(), override, query)
+			if err != nil {
+				return err
+			}
+			if row != nil {
+				return pgerror.Newf(pgcode.CheckViolation,
+					"column %q of table %q contains values that violate the new constraint",
+					col.GetName(), desc.GetName())
+			}
+		}
+	}
+	return nil
+}
+
+// cleanupDomainConstraints removes the constraints of a domain which were
+// being validated when the type schema change failed.
+func (t *typeSchemaChanger) cleanupDomainConstraints(ctx context.Context) error {
+	return t.execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+		typeDesc, err := txn.Descriptors().MutableByID(txn.KV()).Type(ctx, t.typeID)
+		if err != nil {
+			return err
+		}
+		if typeDesc.Kind != descpb.TypeDescriptor_DOMAIN || !typeDesc.HasPendingSchemaChanges() {
+			return nil
+		}
+		if typeDesc.Domain.NotNullValidity == descpb.ConstraintValidity_Validating {
+			typeDesc.Domain.NotNull = false
+			typeDesc.Domain.NotNullValidity = descpb.ConstraintValidity_Validated
+		}
+		constraints := typeDesc.Domain.Constraints[:0]
+		for _, c := range typeDesc.Domain.Constraints {
+			if c.Validity != descpb.ConstraintValidity_Validating {
+				constraints = append(constraints, c)
+			}
+		}
+		typeDesc.Domain.Constraints = constraints
+		return txn.Descriptors().WriteDesc(ctx, true /* kvTrace */, typeDesc, txn.KV())
+	})
+}
+
 // isTransitioningInCurrentJob returns true if the given member is either being
 // added or removed in the current job.
 func (t *typeSchemaChanger) isTransitioningInCurrentJob(
@@ -1419,6 +1597,9 @@ func (t *typeChangeResumer) OnFailOrCancel(
 		if err := tc.cleanupEnumValues(ctx); err != nil {
 			return err
 		}
+		if err := tc.cleanupDomainConstraints(ctx); err != nil {
+			return err
+		}
 
 		if fn := tc.execCfg.TypeSchemaChangerTestingKnobs.RunAfterOnFailOrCancel; fn != nil {
 			return fn()
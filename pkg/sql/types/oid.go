@@ -110,6 +110,19 @@ var OidToType = map[oid.Oid]*T{
 	oidext.T_geography: Geography,
 	oidext.T_box2d:     Box2D,
 	oidext.T_pgvector:  PGVector,
+
+	oid.T_int4range:         Int4Range,
+	oid.T_int8range:         Int8Range,
+	oid.T_numrange:          NumRange,
+	oid.T_tsrange:           TSRange,
+	oid.T_tstzrange:         TSTZRange,
+	oid.T_daterange:         DateRange,
+	oidext.T_int4multirange: Int4Multirange,
+	oidext.T_int8multirange: Int8Multirange,
+	oidext.T_nummultirange:  NumMultirange,
+	oidext.T_tsmultirange:   TSMultirange,
+	oidext.T_tstzmultirange: TSTZMultirange,
+	oidext.T_datemultirange: DateMultirange,
 }
 
 // oidToArrayOid maps scalar type Oids to their corresponding array type Oid.
@@ -158,6 +171,19 @@ var oidToArrayOid = map[oid.Oid]oid.Oid{
 	oidext.T_geography: oidext.T__geography,
 	oidext.T_box2d:     oidext.T__box2d,
 	oidext.T_pgvector:  oidext.T__pgvector,
+
+	oid.T_int4range:         oid.T__int4range,
+	oid.T_int8range:         oid.T__int8range,
+	oid.T_numrange:          oid.T__numrange,
+	oid.T_tsrange:           oid.T__tsrange,
+	oid.T_tstzrange:         oid.T__tstzrange,
+	oid.T_daterange:         oid.T__daterange,
+	oidext.T_int4multirange: oidext.T__int4multirange,
+	oidext.T_int8multirange: oidext.T__int8multirange,
+	oidext.T_nummultirange:  oidext.T__nummultirange,
+	oidext.T_tsmultirange:   oidext.T__tsmultirange,
+	oidext.T_tstzmultirange: oidext.T__tstzmultirange,
+	oidext.T_datemultirange: oidext.T__datemultirange,
 }
 
 // familyToOid maps each type family to a default OID value that is used when
@@ -190,6 +216,8 @@ var familyToOid = map[Family]oid.Oid{
 	TupleFamily:          oid.T_record,
 	BitFamily:            oid.T_bit,
 	AnyFamily:            oid.T_anyelement,
+	RangeFamily:          oid.T_anyrange,
+	MultirangeFamily:     oidext.T_anymultirange,
 
 	GeometryFamily:  oidext.T_geometry,
 	GeographyFamily: oidext.T_geography,
@@ -211,6 +239,11 @@ func init() {
 // type.
 func CalcArrayOid(elemTyp *T) oid.Oid {
 	o := elemTyp.Oid()
+	if elemTyp.IsDomain() {
+		// Domains are of the family of their base type, but have array types of
+		// their own.
+		return elemTyp.UserDefinedArrayOID()
+	}
 	switch elemTyp.Family() {
 	case ArrayFamily:
 		// Postgres nested arrays return the OID of the nested array (i.e. the
@@ -215,6 +215,9 @@ type UserDefinedTypeMetadata struct {
 	// EnumData is non-nil iff the metadata is for an ENUM type.
 	EnumData *EnumMetadata
 
+	// DomainData is non-nil iff the metadata is for a domain.
+	DomainData *DomainMetadata
+
 	// Version is the descriptor version of the descriptor used to construct
 	// this version of the type metadata.
 	Version uint32
@@ -241,6 +244,26 @@ type EnumMetadata struct {
 	//  should occur, if at all.
 }
 
+// DomainMetadata is metadata about a domain needed for evaluation.
+type DomainMetadata struct {
+	// NotNull is true if the domain does not allow NULL values.
+	NotNull bool
+	// DefaultExpr is the serialized default expression of the domain, or empty
+	// if it has none.
+	DefaultExpr string
+	// Checks are the CHECK constraints of the domain that are enforced on new
+	// values.
+	Checks []DomainCheck
+}
+
+// DomainCheck is a CHECK constraint of a domain.
+type DomainCheck struct {
+	Name string
+	// Expr is the serialized check expression, in which the value being checked
+	// is referred to as VALUE.
+	Expr string
+}
+
 func (e *EnumMetadata) debugString() string {
 	return fmt.Sprintf(
 		"PhysicalReps: %v; LogicalReps: %s",
@@ -546,6 +569,74 @@ var (
 		},
 	}
 
+	// Int4Range is the type of a range of INT4 values. For example:
+	//
+	//   [1,10)
+	//
+	Int4Range = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_int4range, Locale: &emptyLocale}}
+
+	// Int8Range is the type of a range of INT8 values.
+	Int8Range = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_int8range, Locale: &emptyLocale}}
+
+	// NumRange is the type of a range of DECIMAL values.
+	NumRange = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_numrange, Locale: &emptyLocale}}
+
+	// TSRange is the type of a range of TIMESTAMP values.
+	TSRange = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_tsrange, Locale: &emptyLocale}}
+
+	// TSTZRange is the type of a range of TIMESTAMPTZ values. For example:
+	//
+	//   ["2024-01-01 10:00:00+00","2024-01-01 11:00:00+00")
+	//
+	TSTZRange = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_tstzrange, Locale: &emptyLocale}}
+
+	// DateRange is the type of a range of DATE values.
+	DateRange = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_daterange, Locale: &emptyLocale}}
+
+	// Int4Multirange is the type of a set of non-overlapping INT4RANGE values.
+	// For example:
+	//
+	//   {[1,3), [5,10)}
+	//
+	Int4Multirange = &T{InternalType: InternalType{
+		Family: MultirangeFamily, Oid: oidext.T_int4multirange, Locale: &emptyLocale}}
+
+	// Int8Multirange is the type of a set of non-overlapping INT8RANGE values.
+	Int8Multirange = &T{InternalType: InternalType{
+		Family: MultirangeFamily, Oid: oidext.T_int8multirange, Locale: &emptyLocale}}
+
+	// NumMultirange is the type of a set of non-overlapping NUMRANGE values.
+	NumMultirange = &T{InternalType: InternalType{
+		Family: MultirangeFamily, Oid: oidext.T_nummultirange, Locale: &emptyLocale}}
+
+	// TSMultirange is the type of a set of non-overlapping TSRANGE values.
+	TSMultirange = &T{InternalType: InternalType{
+		Family: MultirangeFamily, Oid: oidext.T_tsmultirange, Locale: &emptyLocale}}
+
+	// TSTZMultirange is the type of a set of non-overlapping TSTZRANGE values.
+	TSTZMultirange = &T{InternalType: InternalType{
+		Family: MultirangeFamily, Oid: oidext.T_tstzmultirange, Locale: &emptyLocale}}
+
+	// DateMultirange is the type of a set of non-overlapping DATERANGE values.
+	DateMultirange = &T{InternalType: InternalType{
+		Family: MultirangeFamily, Oid: oidext.T_datemultirange, Locale: &emptyLocale}}
+
+	// RangeTypes contains all the range types, in the same order as the
+	// multirange types in MultirangeTypes.
+	RangeTypes = []*T{Int4Range, Int8Range, NumRange, TSRange, TSTZRange, DateRange}
+
+	// MultirangeTypes contains all the multirange types, in the same order as
+	// the range types in RangeTypes.
+	MultirangeTypes = []*T{
+		Int4Multirange, Int8Multirange, NumMultirange, TSMultirange, TSTZMultirange, DateMultirange,
+	}
+
 	// Scalar contains all types that meet this criteria:
 	//
 	//   1. Scalar type (no ArrayFamily or TupleFamily types).
@@ -1192,6 +1283,20 @@ func MakeEnum(typeOID, arrayTypeOID oid.Oid) *T {
 	}}
 }
 
+// MakeDomain constructs a new instance of a domain over the given base type,
+// with the given stable type ID. The domain has the same family and physical
+// representation as its base type. Note that it does not hydrate cached
+// fields on the type.
+func MakeDomain(typeOID, arrayTypeOID oid.Oid, base *T) *T {
+	internalType := base.InternalType
+	internalType.Oid = typeOID
+	internalType.UDTMetadata = &PersistentUserDefinedTypeMetadata{
+		ArrayTypeOID:      arrayTypeOID,
+		DomainBaseTypeOID: base.Oid(),
+	}
+	return &T{InternalType: internalType}
+}
+
 // MakeArray constructs a new instance of an ArrayFamily type with the given
 // element type (which may itself be an ArrayFamily type).
 func MakeArray(typ *T) *T {
@@ -1492,6 +1597,23 @@ func (t *T) UserDefinedArrayOID() oid.Oid {
 	return t.InternalType.UDTMetadata.ArrayTypeOID
 }
 
+// IsDomain returns whether or not t is a domain.
+func (t *T) IsDomain() bool {
+	return t.InternalType.UDTMetadata != nil && t.InternalType.UDTMetadata.DomainBaseTypeOID != 0
+}
+
+// DomainBaseType returns the base type of a domain. It must only be called on
+// domains.
+func (t *T) DomainBaseType() *T {
+	if !t.IsDomain() {
+		panic(errors.AssertionFailedf("%s is not a domain", t.SQLStringForError()))
+	}
+	internalType := t.InternalType
+	internalType.Oid = t.InternalType.UDTMetadata.DomainBaseTypeOID
+	internalType.UDTMetadata = nil
+	return &T{InternalType: internalType}
+}
+
 // RemapUserDefinedTypeOIDs is used to remap OIDs stored within a types.T
 // that is a user defined type. The newArrayOID argument is ignored if the
 // input type is an Array type. It mutates the input types.T and should only
@@ -1536,8 +1658,10 @@ var familyNames = map[Family]redact.SafeString{
 	IntervalFamily:       "interval",
 	JsonFamily:           "jsonb",
 	OidFamily:            "oid",
+	MultirangeFamily:     "multirange",
 	PGLSNFamily:          "pg_lsn",
 	PGVectorFamily:       "vector",
+	RangeFamily:          "range",
 	RefCursorFamily:      "refcursor",
 	StringFamily:         "string",
 	TimeFamily:           "time",
@@ -1573,6 +1697,9 @@ func (f Family) Name() redact.SafeString {
 //
 // TODO(andyk): Should these be changed to be the same as SQLStandardName?
 func (t *T) Name() string {
+	if t.IsDomain() && t.TypeMeta.Name != nil {
+		return t.TypeMeta.Name.Basename()
+	}
 	switch fam := t.Family(); fam {
 	case AnyFamily:
 		return "anyelement"
@@ -1638,6 +1765,9 @@ func (t *T) Name() string {
 	case TupleFamily:
 		return t.SQLStandardName()
 
+	case RangeFamily, MultirangeFamily:
+		return t.PGName()
+
 	case EnumFamily:
 		if t.Oid() == oid.T_anyenum {
 			return "anyenum"
@@ -1712,6 +1842,9 @@ func (t *T) TelemetryName() string {
 // This function is full of special cases. See backend/utils/adt/format_type.c
 // in Postgres.
 func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
+	if t.IsDomain() && t.TypeMeta.Name != nil {
+		return t.TypeMeta.Name.Basename()
+	}
 	var buf strings.Builder
 	switch t.Family() {
 	case AnyFamily:
@@ -1822,6 +1955,8 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 		return "pg_lsn"
 	case PGVectorFamily:
 		return "vector"
+	case RangeFamily, MultirangeFamily:
+		return t.Name()
 	case RefCursorFamily:
 		return "refcursor"
 	case StringFamily, CollatedStringFamily:
@@ -1916,6 +2051,10 @@ func (t *T) InformationSchemaName() string {
 	if t.Family() == ArrayFamily {
 		return "ARRAY"
 	}
+	// Columns of a domain report the name of its base type, as in Postgres.
+	if t.IsDomain() {
+		return t.DomainBaseType().SQLStandardName()
+	}
 	// TypeMeta attributes are populated only when it is user defined type.
 	if t.TypeMeta.Name != nil {
 		return "USER-DEFINED"
@@ -1927,6 +2066,10 @@ func (t *T) InformationSchemaName() string {
 // reproduce the type via parsing the string as a type. It is used in error
 // messages and also to produce the output of SHOW CREATE.
 func (t *T) SQLString() string {
+	if t.IsDomain() && t.TypeMeta.Name != nil {
+		// As for other user-defined types, do not include the catalog name.
+		return t.TypeMeta.Name.FQName(false /* explicitCatalog */)
+	}
 	switch t.Family() {
 	case BitFamily:
 		o := t.Oid()
@@ -2056,7 +2199,7 @@ func (t *T) SQLString() string {
 // type name to be a fully-qualified 3-part name.
 func (t *T) SQLStringFullyQualified() string {
 	if t.TypeMeta.Name != nil &&
-		(t.Family() == EnumFamily || (t.Family() == TupleFamily && t.UserDefined())) {
+		(t.Family() == EnumFamily || (t.Family() == TupleFamily && t.UserDefined()) || t.IsDomain()) {
 		// Include the catalog in the type name. This is necessary to properly
 		// resolve the type, as some code paths require the database name to
 		// correctly distinguish cross-database references.
@@ -2083,6 +2226,9 @@ func (t *T) SQLStringForError() redact.RedactableString {
 		case ArrayFamily:
 			prefix = "ARRAY"
 		}
+		if t.IsDomain() {
+			prefix = "DOMAIN"
+		}
 		return redact.Sprintf("USER DEFINED %s: %s", redact.Safe(prefix), t.SQLString())
 	}
 	switch t.Family() {
@@ -2095,7 +2241,8 @@ func (t *T) SQLStringForError() redact.RedactableString {
 		IntervalFamily, StringFamily, BytesFamily, TimestampTZFamily, CollatedStringFamily, OidFamily,
 		UnknownFamily, UuidFamily, INetFamily, TimeFamily, JsonFamily, TimeTZFamily, BitFamily,
 		GeometryFamily, GeographyFamily, Box2DFamily, VoidFamily, EncodedKeyFamily, TSQueryFamily,
-		TSVectorFamily, AnyFamily, PGLSNFamily, PGVectorFamily, RefCursorFamily:
+		TSVectorFamily, AnyFamily, PGLSNFamily, PGVectorFamily, RefCursorFamily, RangeFamily,
+		MultirangeFamily:
 		// These types do not contain other types, and do not require redaction.
 		return redact.Sprint(redact.SafeString(t.SQLString()))
 	}
@@ -2161,6 +2308,12 @@ func (t *T) Equivalent(other *T) bool {
 			return false
 		}
 
+	case RangeFamily, MultirangeFamily:
+		// Range types with different element types are not compatible.
+		if t.Oid() != other.Oid() {
+			return false
+		}
+
 	case EnumFamily:
 		// If one of the types is anyenum, then allow the comparison to
 		// go through -- anyenum is used when matching overloads.
@@ -2788,6 +2941,43 @@ func (t *T) IsAmbiguous() bool {
 	return false
 }
 
+// RangeContents returns the type of the bounds of a range type, or the range
+// type of the ranges in a multirange type. It is nil for all other types.
+func (t *T) RangeContents() *T {
+	switch t.Family() {
+	case RangeFamily, MultirangeFamily:
+		return rangeContents[t.Oid()]
+	}
+	return nil
+}
+
+// rangeContents maps the Oid of each range type to the type of its bounds,
+// and the Oid of each multirange type to its range type.
+var rangeContents = map[oid.Oid]*T{
+	oid.T_int4range:         Int4,
+	oid.T_int8range:         Int,
+	oid.T_numrange:          Decimal,
+	oid.T_tsrange:           Timestamp,
+	oid.T_tstzrange:         TimestampTZ,
+	oid.T_daterange:         Date,
+	oidext.T_int4multirange: Int4Range,
+	oidext.T_int8multirange: Int8Range,
+	oidext.T_nummultirange:  NumRange,
+	oidext.T_tsmultirange:   TSRange,
+	oidext.T_tstzmultirange: TSTZRange,
+	oidext.T_datemultirange: DateRange,
+}
+
+// MakeMultirange returns the multirange type of the given range type.
+func MakeMultirange(rangeTyp *T) *T {
+	for i := range RangeTypes {
+		if RangeTypes[i].Oid() == rangeTyp.Oid() {
+			return MultirangeTypes[i]
+		}
+	}
+	panic(errors.AssertionFailedf("%s is not a range type", rangeTyp.SQLStringForError()))
+}
+
 // IsNumeric returns true iff this type is an integer, float, or decimal.
 func (t *T) IsNumeric() bool {
 	switch t.Family() {
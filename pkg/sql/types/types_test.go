@@ -1187,6 +1187,28 @@ func TestEnumWithoutTypeMetaNameDoesNotPanicInSQLString(t *testing.T) {
 	require.Equal(t, "@100100[]", arrayType.SQLString())
 }
 
+func TestDomain(t *testing.T) {
+	const domainOID = oidext.CockroachPredefinedOIDMax + 600
+	base := MakeDecimal(10, 2)
+	typ := MakeDomain(domainOID, domainOID+1, base)
+	require.True(t, typ.IsDomain())
+	require.False(t, base.IsDomain())
+	require.Equal(t, DecimalFamily, typ.Family())
+	require.Equal(t, oid.Oid(domainOID), typ.Oid())
+	require.True(t, typ.Equivalent(base))
+	require.False(t, typ.Identical(base))
+	require.True(t, typ.DomainBaseType().Identical(base))
+
+	// Without a hydrated name, the domain is formatted like its base type.
+	require.Equal(t, "DECIMAL(10,2)", typ.SQLString())
+	typ.TypeMeta = UserDefinedTypeMetadata{
+		Name: &UserDefinedTypeName{Catalog: "db", Schema: "public", Name: "positive_money"},
+	}
+	require.Equal(t, "positive_money", typ.Name())
+	require.Equal(t, "positive_money", typ.SQLStandardName())
+	require.Equal(t, "numeric", typ.InformationSchemaName())
+}
+
 func TestSQLStringForError(t *testing.T) {
 	const userDefinedOID = oidext.CockroachPredefinedOIDMax + 500
 	userDefinedEnum := MakeEnum(userDefinedOID, userDefinedOID+3)
@@ -13,17 +13,19 @@ package sql
 import (
 	"context"
 
-	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
-	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 )
 
+// Unlisten implements the UNLISTEN statement. Like LISTEN, it takes effect
+// when the transaction commits.
 func (p *planner) Unlisten(ctx context.Context, n *tree.Unlisten) (planNode, error) {
-	p.BufferClientNotice(ctx,
-		pgerror.WithSeverity(
-			unimplemented.NewWithIssuef(41522, "CRDB does not support LISTEN, making UNLISTEN a no-op"),
-			"NOTICE",
-		),
-	)
+	// Sessions that can't listen have nothing to unlisten from.
+	if s := p.extendedEvalCtx.notifications; s != nil {
+		op := listenOp{}
+		if !n.Star {
+			op.channel = n.ChannelName.Object()
+		}
+		s.listenOps = append(s.listenOps, op)
+	}
 	return newZeroNode(nil /* columns */), nil
 }
@@ -45,6 +45,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -3324,6 +3325,30 @@ var varGen = map[string]sessionVar{
 		GlobalDefault: globalTrue,
 	},
 
+	// CockroachDB extension.
+	`vector_search_probes`: {
+		GetStringVal: makeIntGetStringValFn(`vector_search_probes`),
+		Set: func(_ context.Context, m sessionDataMutator, s string) error {
+			i, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return wrapSetVarError(err, "vector_search_probes", s)
+			}
+			if i < 1 || i > 1<<vecindex.MaxPartitionBits {
+				return pgerror.Newf(pgcode.InvalidParameterValue,
+					`%d is outside the valid range for parameter "vector_search_probes" (1 .. %d)`,
+					i, 1<<vecindex.MaxPartitionBits)
+			}
+			m.SetVectorSearchProbes(i)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext, _ *kv.Txn) (string, error) {
+			return strconv.FormatInt(evalCtx.SessionData().VectorSearchProbes, 10), nil
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return strconv.Itoa(vecindex.DefaultSearchProbes)
+		},
+	},
+
 	// CockroachDB extension.
 	`optimizer_use_trigram_similarity_optimization`: {
 		GetStringVal: makePostgresBoolGetStringValFn(`optimizer_use_trigram_similarity_optimization`),
@@ -0,0 +1,264 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package vecindex implements the partitioning scheme used by vector indexes.
+//
+// A vector index is an inverted index on a VECTOR column. The vector space is
+// divided into partitions using random-hyperplane locality sensitive hashing:
+// the index has a fixed set of hyperplanes through the origin, and bit i of a
+// vector's partition is set iff the vector lies on the positive side of
+// hyperplane i. Each row is stored in the index under the single key
+// identifying its partition, so vectors that are close to each other (in
+// angle) tend to be stored next to each other.
+//
+// An approximate nearest neighbor search scans the partition containing the
+// query vector along with the "probes-1" neighboring partitions that are most
+// likely to contain close vectors, and then ranks the candidate rows by their
+// exact distance to the query vector. Scanning more partitions improves recall
+// at the expense of latency.
+package vecindex
+
+import (
+	"container/heap"
+	"math"
+	"math/bits"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
+)
+
+const (
+	// DefaultLists is the default number of lists (partitions) requested for a
+	// vector index. It matches the default of pgvector's ivfflat indexes.
+	DefaultLists = 100
+	// MaxPartitionBits is the maximum number of hyperplanes used to partition
+	// the vector space of an index.
+	MaxPartitionBits = 16
+	// DefaultSearchProbes is the default number of partitions scanned by a
+	// nearest neighbor search.
+	DefaultSearchProbes = 8
+)
+
+// PartitionBitsForLists returns the number of hyperplanes needed to divide
+// the vector space into at least the given number of partitions, capped at
+// MaxPartitionBits.
+func PartitionBitsForLists(lists int64) int32 {
+	if lists <= 2 {
+		return 1
+	}
+	n := int32(bits.Len64(uint64(lists - 1)))
+	if n > MaxPartitionBits {
+		n = MaxPartitionBits
+	}
+	return n
+}
+
+// MakeConfig returns the configuration of a new vector index over vectors
+// with the given number of dimensions.
+func MakeConfig(
+	dims int32, lists int64, metric catpb.VectorDistanceMetric, seed int64,
+) catpb.VectorIndexConfig {
+	return catpb.VectorIndexConfig{
+		Dims:          dims,
+		PartitionBits: PartitionBitsForLists(lists),
+		Seed:          seed,
+		Metric:        metric,
+	}
+}
+
+// EncodePartitionKey appends the inverted index key of the given partition to
+// b.
+func EncodePartitionKey(b []byte, partition uint64) []byte {
+	return encoding.EncodeUvarintAscending(b, partition)
+}
+
+// EncodeVectorKey appends the inverted index key of the partition containing
+// v to b.
+func EncodeVectorKey(b []byte, cfg catpb.VectorIndexConfig, v vector.T) ([]byte, error) {
+	partition, err := Partition(cfg, v)
+	if err != nil {
+		return nil, err
+	}
+	return EncodePartitionKey(b, partition), nil
+}
+
+// Partition returns the partition containing v.
+func Partition(cfg catpb.VectorIndexConfig, v vector.T) (uint64, error) {
+	margins, err := getHyperplanes(cfg).margins(v)
+	if err != nil {
+		return 0, err
+	}
+	var partition uint64
+	for i, m := range margins {
+		if m >= 0 {
+			partition |= 1 << uint(i)
+		}
+	}
+	return partition, nil
+}
+
+// SearchPartitions returns the partitions that should be scanned in order to
+// find the nearest neighbors of q, in order of decreasing likelihood of
+// containing close vectors. At most probes partitions are returned, and the
+// first one is always the partition containing q.
+//
+// Partitions are ranked using the multi-probe heuristic: the partitions that
+// differ from q's partition in a set of bits S are ranked by the sum of the
+// distances from q to the hyperplanes in S, since q needs to move at least
+// that far to cross into them.
+func SearchPartitions(cfg catpb.VectorIndexConfig, q vector.T, probes int) ([]uint64, error) {
+	margins, err := getHyperplanes(cfg).margins(q)
+	if err != nil {
+		return nil, err
+	}
+	var home uint64
+	for i, m := range margins {
+		if m >= 0 {
+			home |= 1 << uint(i)
+		}
+		margins[i] = math.Abs(m)
+	}
+	if probes < 1 {
+		probes = 1
+	}
+	if total := uint64(1) << uint(len(margins)); uint64(probes) > total {
+		probes = int(total)
+	}
+
+	// Sort the bits by increasing distance to their hyperplane; perturbation
+	// sets are represented as increasing sequences of positions in this order.
+	order := make([]int, len(margins))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return margins[order[i]] < margins[order[j]] })
+
+	res := make([]uint64, 1, probes)
+	res[0] = home
+	if probes == 1 {
+		return res, nil
+	}
+	// Enumerate the perturbation sets in order of increasing score, using the
+	// "shift" and "expand" operations to generate each set exactly once.
+	h := perturbationHeap{{positions: []int{0}, score: margins[order[0]]}}
+	for len(res) < probes && len(h) > 0 {
+		s := heap.Pop(&h).(perturbation)
+		partition := home
+		for _, p := range s.positions {
+			partition ^= 1 << uint(order[p])
+		}
+		res = append(res, partition)
+
+		last := s.positions[len(s.positions)-1]
+		if last+1 >= len(order) {
+			continue
+		}
+		// Shift: replace the last position with the next one.
+		shifted := append([]int(nil), s.positions...)
+		shifted[len(shifted)-1] = last + 1
+		heap.Push(&h, perturbation{
+			positions: shifted,
+			score:     s.score - margins[order[last]] + margins[order[last+1]],
+		})
+		// Expand: add the next position.
+		expanded := append(append([]int(nil), s.positions...), last+1)
+		heap.Push(&h, perturbation{
+			positions: expanded,
+			score:     s.score + margins[order[last+1]],
+		})
+	}
+	return res, nil
+}
+
+// perturbation is a set of bits to flip in the partition of a query vector.
+type perturbation struct {
+	positions []int
+	score     float64
+}
+
+type perturbationHeap []perturbation
+
+var _ heap.Interface = (*perturbationHeap)(nil)
+
+func (h perturbationHeap) Len() int           { return len(h) }
+func (h perturbationHeap) Less(i, j int) bool { return h[i].score < h[j].score }
+func (h perturbationHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *perturbationHeap) Push(x interface{}) { *h = append(*h, x.(perturbation)) }
+
+func (h *perturbationHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// hyperplanes are the normal vectors of the hyperplanes of a vector index,
+// stored contiguously.
+type hyperplanes struct {
+	dims    int
+	normals []float64
+}
+
+// hyperplanesKey identifies the hyperplanes of a vector index. The metric is
+// deliberately not part of the key, since it does not affect them.
+type hyperplanesKey struct {
+	dims, partitionBits int32
+	seed                int64
+}
+
+// hyperplanesCache caches the hyperplanes of each index config, since
+// generating them requires partitionBits*dims random numbers.
+var hyperplanesCache sync.Map // map[hyperplanesKey]*hyperplanes
+
+func getHyperplanes(cfg catpb.VectorIndexConfig) *hyperplanes {
+	key := hyperplanesKey{dims: cfg.Dims, partitionBits: cfg.PartitionBits, seed: cfg.Seed}
+	if h, ok := hyperplanesCache.Load(key); ok {
+		return h.(*hyperplanes)
+	}
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	h := &hyperplanes{
+		dims:    int(cfg.Dims),
+		normals: make([]float64, int(cfg.Dims)*int(cfg.PartitionBits)),
+	}
+	// Normally distributed components yield normals that are uniformly
+	// distributed in direction.
+	for i := range h.normals {
+		h.normals[i] = rng.NormFloat64()
+	}
+	actual, _ := hyperplanesCache.LoadOrStore(key, h)
+	return actual.(*hyperplanes)
+}
+
+// margins returns the signed (unnormalized) distance from v to each
+// hyperplane.
+func (h *hyperplanes) margins(v vector.T) ([]float64, error) {
+	if len(v) != h.dims || h.dims == 0 {
+		return nil, pgerror.Newf(pgcode.DataException,
+			"expected %d dimensions, not %d", h.dims, len(v))
+	}
+	res := make([]float64, len(h.normals)/h.dims)
+	for i := range res {
+		normal := h.normals[i*h.dims : (i+1)*h.dims]
+		var dot float64
+		for j, x := range v {
+			dot += float64(x) * normal[j]
+		}
+		res[i] = dot
+	}
+	return res, nil
+}
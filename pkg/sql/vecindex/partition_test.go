@@ -0,0 +1,139 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package vecindex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionBitsForLists(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		lists    int64
+		expected int32
+	}{
+		{lists: -1, expected: 1},
+		{lists: 1, expected: 1},
+		{lists: 2, expected: 1},
+		{lists: 3, expected: 2},
+		{lists: 4, expected: 2},
+		{lists: 5, expected: 3},
+		{lists: DefaultLists, expected: 7},
+		{lists: 1 << 16, expected: 16},
+		{lists: 1<<16 + 1, expected: MaxPartitionBits},
+		{lists: math.MaxInt64, expected: MaxPartitionBits},
+	} {
+		require.Equal(t, tc.expected, PartitionBitsForLists(tc.lists), "lists=%d", tc.lists)
+	}
+}
+
+func randomVector(rng *rand.Rand, dims int) vector.T {
+	v := make(vector.T, dims)
+	for i := range v {
+		v[i] = rng.Float32()*2 - 1
+	}
+	return v
+}
+
+func TestPartition(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	rng, _ := randutil.NewTestRand()
+	cfg := MakeConfig(8, 64, catpb.VectorDistanceMetric_L2, rng.Int63())
+	for i := 0; i < 100; i++ {
+		v := randomVector(rng, int(cfg.Dims))
+		p, err := Partition(cfg, v)
+		require.NoError(t, err)
+		require.Less(t, p, uint64(1)<<uint(cfg.PartitionBits))
+
+		// Partitions only depend on the direction of the vector.
+		scaled := make(vector.T, len(v))
+		for j := range v {
+			scaled[j] = v[j] * 3
+		}
+		p2, err := Partition(cfg, scaled)
+		require.NoError(t, err)
+		require.Equal(t, p, p2)
+
+		// The partition of a vector never changes, even when the hyperplanes
+		// are regenerated.
+		hyperplanesCache.Delete(hyperplanesKey{
+			dims: cfg.Dims, partitionBits: cfg.PartitionBits, seed: cfg.Seed,
+		})
+		p3, err := Partition(cfg, v)
+		require.NoError(t, err)
+		require.Equal(t, p, p3)
+	}
+
+	_, err := Partition(cfg, vector.T{1, 2, 3})
+	require.EqualError(t, err, "expected 8 dimensions, not 3")
+}
+
+func TestSearchPartitions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	rng, _ := randutil.NewTestRand()
+	cfg := MakeConfig(16, 32, catpb.VectorDistanceMetric_COSINE, rng.Int63())
+	numPartitions := 1 << uint(cfg.PartitionBits)
+	for i := 0; i < 100; i++ {
+		q := randomVector(rng, int(cfg.Dims))
+		home, err := Partition(cfg, q)
+		require.NoError(t, err)
+		margins, err := getHyperplanes(cfg).margins(q)
+		require.NoError(t, err)
+		score := func(p uint64) float64 {
+			var s float64
+			for b, m := range margins {
+				if (p^home)&(1<<uint(b)) != 0 {
+					s += math.Abs(m)
+				}
+			}
+			return s
+		}
+
+		for _, probes := range []int{0, 1, 2, 7, numPartitions, numPartitions + 10} {
+			res, err := SearchPartitions(cfg, q, probes)
+			require.NoError(t, err)
+
+			expectedLen := probes
+			if expectedLen < 1 {
+				expectedLen = 1
+			} else if expectedLen > numPartitions {
+				expectedLen = numPartitions
+			}
+			require.Len(t, res, expectedLen)
+			require.Equal(t, home, res[0])
+
+			// The partitions are distinct and ordered by increasing score.
+			seen := make(map[uint64]bool, len(res))
+			for j, p := range res {
+				require.False(t, seen[p], "partition %d returned twice", p)
+				seen[p] = true
+				if j > 0 {
+					require.LessOrEqual(t, score(res[j-1]), score(p)+1e-9)
+				}
+			}
+		}
+	}
+}
@@ -144,6 +144,31 @@ const (
 	jsonKeyTerminator           byte = 0x00
 	jsonKeyDescendingTerminator byte = 0xFF
 
+	// Markers for the key encodings of ranges and multiranges. A range is
+	// encoded as its marker followed by its lower and upper bounds, and a
+	// multirange as its marker followed by the bounds of each of its ranges
+	// and a terminator. Each bound begins with a header byte, and a bound
+	// which is not infinite continues with the key encoding of its value and
+	// a byte that tells whether it is inclusive. The bytes are chosen so that
+	// the encoded ranges sort like the ranges themselves: the empty range
+	// first, then by lower bound, with the infinite bound first and inclusive
+	// bounds before exclusive ones, and then by upper bound, with exclusive
+	// bounds before inclusive ones and the infinite bound last. The
+	// multirange terminator is smaller than the header of any lower bound, so
+	// a multirange sorts before the multiranges it is a prefix of. All the
+	// bytes are inverted in the descending encodings.
+	rangeKeyMarker                = jsonEmptyArrayKeyDescendingMarker + 1
+	rangeKeyDescendingMarker      = rangeKeyMarker + 1
+	multirangeKeyMarker           = rangeKeyDescendingMarker + 1
+	multirangeKeyDescendingMarker = multirangeKeyMarker + 1
+
+	rangeKeyEmpty           byte = 0x00
+	rangeKeyLowerInfinite   byte = 0x01
+	rangeKeyLowerBounded    byte = 0x02
+	rangeKeyUpperBounded    byte = 0x01
+	rangeKeyUpperInfinite   byte = 0x02
+	multirangeKeyTerminator byte = 0x00
+
 	// IntMin is chosen such that the range of int tags does not overlap the
 	// ascii character set that is frequently used in testing.
 	IntMin      = 0x80 // 128
@@ -1781,6 +1806,12 @@ const (
 	JsonEmptyArray     Type = 42
 	JsonEmptyArrayDesc Type = 43
 	PGVector           Type = 44
+	RangeKeyAsc        Type = 45 // Range key encoding
+	RangeKeyDesc       Type = 46 // Range key encoded descendingly
+	MultirangeKeyAsc   Type = 47 // Multirange key encoding
+	MultirangeKeyDesc  Type = 48 // Multirange key encoded descendingly
+	Range              Type = 49
+	Multirange         Type = 50
 )
 
 // typMap maps an encoded type byte to a decoded Type. It's got 256 slots, one
@@ -1883,6 +1914,14 @@ func slowPeekType(b []byte) Type {
 			return Decimal
 		case m == voidMarker:
 			return Void
+		case m == rangeKeyMarker:
+			return RangeKeyAsc
+		case m == rangeKeyDescendingMarker:
+			return RangeKeyDesc
+		case m == multirangeKeyMarker:
+			return MultirangeKeyAsc
+		case m == multirangeKeyDescendingMarker:
+			return MultirangeKeyDesc
 		}
 	}
 	return Unknown
@@ -2032,6 +2071,20 @@ func PeekLength(b []byte) (int, error) {
 		}
 		length, err := getArrayOrJSONLength(b[1:], dir, IsArrayKeyDone)
 		return 1 + length, err
+	case rangeKeyMarker, rangeKeyDescendingMarker:
+		dir := Ascending
+		if m == rangeKeyDescendingMarker {
+			dir = Descending
+		}
+		length, err := getRangeKeyLength(b[1:], dir)
+		return 1 + length, err
+	case multirangeKeyMarker, multirangeKeyDescendingMarker:
+		dir := Ascending
+		if m == multirangeKeyDescendingMarker {
+			dir = Descending
+		}
+		length, err := getMultirangeKeyLength(b[1:], dir)
+		return 1 + length, err
 	case bytesMarker:
 		return getBytesLength(b, ascendingBytesEscapes)
 	case box2DMarker:
@@ -2215,6 +2268,50 @@ func prettyPrintValueImpl(
 	return allDecoded
 }
 
+// prettyPrintRangeKeyBounds writes the string representation of the bounds of
+// a key encoded range, like [1,10), to build, and returns the remaining bytes.
+func prettyPrintRangeKeyBounds(
+	build *strings.Builder, dir, encDir Direction, buf []byte,
+) ([]byte, error) {
+	var bounds [2]string
+	var inclusive [2]bool
+	for i, upper := range []bool{false, true} {
+		var empty, infinite bool
+		var err error
+		buf, empty, infinite, err = DecodeRangeKeyBoundHeader(buf, upper, encDir)
+		if err != nil {
+			return nil, err
+		}
+		if empty {
+			build.WriteString("empty")
+			return buf, nil
+		}
+		if infinite {
+			continue
+		}
+		if buf, bounds[i], err = prettyPrintFirstValue(dir, buf); err != nil {
+			return nil, err
+		}
+		if buf, inclusive[i], err = DecodeRangeKeyBoundInclusive(buf, upper, encDir); err != nil {
+			return nil, err
+		}
+	}
+	if inclusive[0] {
+		build.WriteString("[")
+	} else {
+		build.WriteString("(")
+	}
+	build.WriteString(bounds[0])
+	build.WriteString(",")
+	build.WriteString(bounds[1])
+	if inclusive[1] {
+		build.WriteString("]")
+	} else {
+		build.WriteString(")")
+	}
+	return buf, nil
+}
+
 // prettyPrintFirstValue returns a string representation of the first decodable
 // value in the provided byte slice, along with the remaining byte slice
 // after decoding.
@@ -2273,6 +2370,50 @@ func prettyPrintFirstValue(dir Direction, b []byte) ([]byte, string, error) {
 		}
 		build.WriteString("]")
 		return buf, build.String(), nil
+	case RangeKeyAsc, RangeKeyDesc:
+		encDir := Ascending
+		if typ == RangeKeyDesc {
+			encDir = Descending
+		}
+		buf, err := ValidateAndConsumeRangeKeyMarker(b, encDir)
+		if err != nil {
+			return nil, "", err
+		}
+		var build strings.Builder
+		buf, err = prettyPrintRangeKeyBounds(&build, dir, encDir, buf)
+		if err != nil {
+			return nil, "", err
+		}
+		return buf, build.String(), nil
+	case MultirangeKeyAsc, MultirangeKeyDesc:
+		encDir := Ascending
+		if typ == MultirangeKeyDesc {
+			encDir = Descending
+		}
+		buf, err := ValidateAndConsumeMultirangeKeyMarker(b, encDir)
+		if err != nil {
+			return nil, "", err
+		}
+		var build strings.Builder
+		build.WriteString("{")
+		for first := true; ; first = false {
+			if len(buf) == 0 {
+				return nil, "", errors.AssertionFailedf("invalid multirange (unterminated)")
+			}
+			if IsMultirangeKeyDone(buf, encDir) {
+				buf = buf[1:]
+				break
+			}
+			if !first {
+				build.WriteString(",")
+			}
+			buf, err = prettyPrintRangeKeyBounds(&build, dir, encDir, buf)
+			if err != nil {
+				return nil, "", err
+			}
+		}
+		build.WriteString("}")
+		return buf, build.String(), nil
 	case NotNull:
 		b, _ = DecodeIfNotNull(b)
 		return b, "!NULL", nil
@@ -2827,6 +2968,22 @@ func EncodeTSVectorValue(appendTo []byte, colID uint32, data []byte) []byte {
 	return EncodeUntaggedBytesValue(appendTo, data)
 }
 
+// EncodeRangeValue encodes an already-byte-encoded range value with no value
+// tag but with a length prefix, appends it to the supplied buffer, and returns
+// the final buffer.
+func EncodeRangeValue(appendTo []byte, colID uint32, data []byte) []byte {
+	appendTo = EncodeValueTag(appendTo, colID, Range)
+	return EncodeUntaggedBytesValue(appendTo, data)
+}
+
+// EncodeMultirangeValue encodes an already-byte-encoded multirange value with
+// no value tag but with a length prefix, appends it to the supplied buffer, and
+// returns the final buffer.
+func EncodeMultirangeValue(appendTo []byte, colID uint32, data []byte) []byte {
+	appendTo = EncodeValueTag(appendTo, colID, Multirange)
+	return EncodeUntaggedBytesValue(appendTo, data)
+}
+
 // EncodePGVectorValue encodes an already-byte-encoded PGVector value with no
 // value tag but with a length prefix, appends it to the supplied buffer, and
 // returns the final buffer.
@@ -3233,7 +3390,7 @@ func PeekValueLengthWithOffsetsAndType(b []byte, dataOffset int, typ Type) (leng
 		return dataOffset + n, err
 	case Float:
 		return dataOffset + floatValueEncodedLength, nil
-	case Bytes, Array, JSON, Geo, TSVector, TSQuery, PGVector:
+	case Bytes, Array, JSON, Geo, TSVector, TSQuery, PGVector, Range, Multirange:
 		_, n, i, err := DecodeNonsortingUvarint(b)
 		return dataOffset + n + int(i), err
 	case Box2D:
@@ -3692,6 +3849,204 @@ func IsArrayKeyDone(buf []byte, dir Direction) bool {
 	return buf[0] == expected
 }
 
+// EncodeRangeKeyMarker adds the range key encoding marker to buf and returns
+// the new buffer. It is followed by the bounds of the range, which are encoded
+// by EncodeEmptyRangeKey for an empty range.
+func EncodeRangeKeyMarker(buf []byte, dir Direction) []byte {
+	switch dir {
+	case Ascending:
+		return append(buf, rangeKeyMarker)
+	case Descending:
+		return append(buf, rangeKeyDescendingMarker)
+	default:
+		panic("invalid direction")
+	}
+}
+
+// EncodeMultirangeKeyMarker adds the multirange key encoding marker to buf and
+// returns the new buffer. It is followed by the bounds of each of the ranges of
+// the multirange, and by the terminator added by EncodeMultirangeKeyTerminator.
+func EncodeMultirangeKeyMarker(buf []byte, dir Direction) []byte {
+	switch dir {
+	case Ascending:
+		return append(buf, multirangeKeyMarker)
+	case Descending:
+		return append(buf, multirangeKeyDescendingMarker)
+	default:
+		panic("invalid direction")
+	}
+}
+
+// EncodeMultirangeKeyTerminator adds the multirange key terminator to buf and
+// returns the new buffer.
+func EncodeMultirangeKeyTerminator(buf []byte, dir Direction) []byte {
+	return append(buf, rangeKeyByte(multirangeKeyTerminator, dir))
+}
+
+// EncodeEmptyRangeKey adds the bounds of an empty range to buf and returns the
+// new buffer.
+func EncodeEmptyRangeKey(buf []byte, dir Direction) []byte {
+	return append(buf, rangeKeyByte(rangeKeyEmpty, dir))
+}
+
+// EncodeRangeKeyBoundHeader adds the header of the lower or upper bound of a
+// key encoded range to buf and returns the new buffer. Unless the bound is
+// infinite, the header must be followed by the key encoding of the value of
+// the bound, in the same direction, and by EncodeRangeKeyBoundInclusive.
+func EncodeRangeKeyBoundHeader(buf []byte, upper, infinite bool, dir Direction) []byte {
+	var b byte
+	switch {
+	case !upper && infinite:
+		b = rangeKeyLowerInfinite
+	case !upper:
+		b = rangeKeyLowerBounded
+	case infinite:
+		b = rangeKeyUpperInfinite
+	default:
+		b = rangeKeyUpperBounded
+	}
+	return append(buf, rangeKeyByte(b, dir))
+}
+
+// EncodeRangeKeyBoundInclusive adds whether the lower or upper bound of a key
+// encoded range is inclusive to buf and returns the new buffer.
+func EncodeRangeKeyBoundInclusive(buf []byte, upper, inclusive bool, dir Direction) []byte {
+	var b byte
+	// Inclusive lower bounds sort before exclusive ones, and inclusive upper
+	// bounds sort after exclusive ones.
+	if inclusive == upper {
+		b = 1
+	}
+	return append(buf, rangeKeyByte(b, dir))
+}
+
+// ValidateAndConsumeRangeKeyMarker checks that the marker at the front of buf
+// is the range key encoding marker for the given direction, and returns the
+// remaining bytes.
+func ValidateAndConsumeRangeKeyMarker(buf []byte, dir Direction) ([]byte, error) {
+	typ := PeekType(buf)
+	expected := RangeKeyAsc
+	if dir == Descending {
+		expected = RangeKeyDesc
+	}
+	if typ != expected {
+		return nil, errors.Newf("invalid type found %s", typ)
+	}
+	return buf[1:], nil
+}
+
+// ValidateAndConsumeMultirangeKeyMarker checks that the marker at the front of
+// buf is the multirange key encoding marker for the given direction, and
+// returns the remaining bytes.
+func ValidateAndConsumeMultirangeKeyMarker(buf []byte, dir Direction) ([]byte, error) {
+	typ := PeekType(buf)
+	expected := MultirangeKeyAsc
+	if dir == Descending {
+		expected = MultirangeKeyDesc
+	}
+	if typ != expected {
+		return nil, errors.Newf("invalid type found %s", typ)
+	}
+	return buf[1:], nil
+}
+
+// IsMultirangeKeyDone returns if the first byte in the input is the multirange
+// terminator for the input direction.
+func IsMultirangeKeyDone(buf []byte, dir Direction) bool {
+	return buf[0] == rangeKeyByte(multirangeKeyTerminator, dir)
+}
+
+// DecodeRangeKeyBoundHeader decodes the header of the lower or upper bound of
+// a key encoded range, and returns the remaining bytes. The empty return value
+// can only be true for lower bounds, in which case the range has no bounds.
+func DecodeRangeKeyBoundHeader(
+	buf []byte, upper bool, dir Direction,
+) (_ []byte, empty, infinite bool, _ error) {
+	if len(buf) == 0 {
+		return nil, false, false, errors.AssertionFailedf("invalid range encoding (too short)")
+	}
+	switch b := rangeKeyByte(buf[0], dir); {
+	case !upper && b == rangeKeyEmpty:
+		empty = true
+	case !upper && b == rangeKeyLowerInfinite, upper && b == rangeKeyUpperInfinite:
+		infinite = true
+	case !upper && b == rangeKeyLowerBounded, upper && b == rangeKeyUpperBounded:
+	default:
+		return nil, false, false, errors.AssertionFailedf("invalid range bound header %d", buf[0])
+	}
+	return buf[1:], empty, infinite, nil
+}
+
+// DecodeRangeKeyBoundInclusive decodes whether the lower or upper bound of a
+// key encoded range is inclusive, and returns the remaining bytes.
+func DecodeRangeKeyBoundInclusive(
+	buf []byte, upper bool, dir Direction,
+) (_ []byte, inclusive bool, _ error) {
+	if len(buf) == 0 {
+		return nil, false, errors.AssertionFailedf("invalid range encoding (too short)")
+	}
+	return buf[1:], (rangeKeyByte(buf[0], dir) == 1) == upper, nil
+}
+
+// rangeKeyByte returns the given byte of the range key encoding in the given
+// direction.
+func rangeKeyByte(b byte, dir Direction) byte {
+	if dir == Descending {
+		return ^b
+	}
+	return b
+}
+
+// getRangeKeyLength returns the length of the bounds of a key encoded range.
+func getRangeKeyLength(buf []byte, dir Direction) (int, error) {
+	length := 0
+	for _, upper := range []bool{false, true} {
+		rest, empty, infinite, err := DecodeRangeKeyBoundHeader(buf, upper, dir)
+		if err != nil {
+			return 0, err
+		}
+		length++
+		if empty {
+			return length, nil
+		}
+		buf = rest
+		if infinite {
+			continue
+		}
+		n, err := PeekLength(buf)
+		if err != nil {
+			return 0, err
+		}
+		// The value of the bound is followed by whether it is inclusive.
+		if len(buf) < n+1 {
+			return 0, errors.AssertionFailedf("invalid range encoding (too short)")
+		}
+		length += n + 1
+		buf = buf[n+1:]
+	}
+	return length, nil
+}
+
+// getMultirangeKeyLength returns the length of the ranges and the terminator
+// of a key encoded multirange.
+func getMultirangeKeyLength(buf []byte, dir Direction) (int, error) {
+	length := 0
+	for {
+		if len(buf) == 0 {
+			return 0, errors.AssertionFailedf("invalid multirange encoding (unterminated)")
+		}
+		if IsMultirangeKeyDone(buf, dir) {
+			return length + 1, nil
+		}
+		n, err := getRangeKeyLength(buf, dir)
+		if err != nil {
+			return 0, err
+		}
+		buf = buf[n:]
+		length += n
+	}
+}
+
 // isJSONKeyDone returns if the first byte in the input is the JSON
 // terminator for the input direction.
 func IsJSONKeyDone(buf []byte, dir Direction) bool {
@@ -1530,6 +1530,279 @@ func testCustomEncodeDuration(
 	}
 }
 
+// testRangeBound and testRange describe an int range for the tests of the
+// range and multirange key encodings.
+type testRangeBound struct {
+	infinite  bool
+	val       int64
+	inclusive bool
+}
+
+type testRange struct {
+	empty        bool
+	lower, upper testRangeBound
+}
+
+func (r testRange) String() string {
+	if r.empty {
+		return "empty"
+	}
+	var buf bytes.Buffer
+	if r.lower.inclusive {
+		buf.WriteString("[")
+	} else {
+		buf.WriteString("(")
+	}
+	if !r.lower.infinite {
+		fmt.Fprintf(&buf, "%d", r.lower.val)
+	}
+	buf.WriteString(",")
+	if !r.upper.infinite {
+		fmt.Fprintf(&buf, "%d", r.upper.val)
+	}
+	if r.upper.inclusive {
+		buf.WriteString("]")
+	} else {
+		buf.WriteString(")")
+	}
+	return buf.String()
+}
+
+func encodeTestRangeBounds(buf []byte, r testRange, dir Direction) []byte {
+	if r.empty {
+		return EncodeEmptyRangeKey(buf, dir)
+	}
+	for _, upper := range []bool{false, true} {
+		bound := r.lower
+		if upper {
+			bound = r.upper
+		}
+		buf = EncodeRangeKeyBoundHeader(buf, upper, bound.infinite, dir)
+		if bound.infinite {
+			continue
+		}
+		if dir == Ascending {
+			buf = EncodeVarintAscending(buf, bound.val)
+		} else {
+			buf = EncodeVarintDescending(buf, bound.val)
+		}
+		buf = EncodeRangeKeyBoundInclusive(buf, upper, bound.inclusive, dir)
+	}
+	return buf
+}
+
+func decodeTestRangeBounds(buf []byte, dir Direction) ([]byte, testRange, error) {
+	var r testRange
+	for _, upper := range []bool{false, true} {
+		var bound testRangeBound
+		var empty bool
+		var err error
+		buf, empty, bound.infinite, err = DecodeRangeKeyBoundHeader(buf, upper, dir)
+		if err != nil {
+			return nil, testRange{}, err
+		}
+		if empty {
+			r.empty = true
+			return buf, r, nil
+		}
+		if !bound.infinite {
+			if dir == Ascending {
+				buf, bound.val, err = DecodeVarintAscending(buf)
+			} else {
+				buf, bound.val, err = DecodeVarintDescending(buf)
+			}
+			if err != nil {
+				return nil, testRange{}, err
+			}
+			if buf, bound.inclusive, err = DecodeRangeKeyBoundInclusive(buf, upper, dir); err != nil {
+				return nil, testRange{}, err
+			}
+		}
+		if upper {
+			r.upper = bound
+		} else {
+			r.lower = bound
+		}
+	}
+	return buf, r, nil
+}
+
+func encodeTestMultirange(buf []byte, ranges []testRange, dir Direction) []byte {
+	buf = EncodeMultirangeKeyMarker(buf, dir)
+	for _, r := range ranges {
+		buf = encodeTestRangeBounds(buf, r, dir)
+	}
+	return EncodeMultirangeKeyTerminator(buf, dir)
+}
+
+var (
+	testRangeNegInf = testRangeBound{infinite: true}
+	testRangePosInf = testRangeBound{infinite: true}
+)
+
+func testRangeIncl(v int64) testRangeBound { return testRangeBound{val: v, inclusive: true} }
+func testRangeExcl(v int64) testRangeBound { return testRangeBound{val: v} }
+
+func TestEncodeDecodeRangeKey(t *testing.T) {
+	// The ranges are listed in the order of their ascending encodings.
+	ranges := []testRange{
+		{empty: true},
+		{lower: testRangeNegInf, upper: testRangeExcl(-5)},
+		{lower: testRangeNegInf, upper: testRangeExcl(1)},
+		{lower: testRangeNegInf, upper: testRangeIncl(1)},
+		{lower: testRangeNegInf, upper: testRangePosInf},
+		{lower: testRangeIncl(1), upper: testRangeExcl(2)},
+		{lower: testRangeIncl(1), upper: testRangeIncl(2)},
+		{lower: testRangeIncl(1), upper: testRangeExcl(10)},
+		{lower: testRangeIncl(1), upper: testRangePosInf},
+		{lower: testRangeExcl(1), upper: testRangeExcl(2)},
+		{lower: testRangeExcl(1), upper: testRangePosInf},
+		{lower: testRangeIncl(2), upper: testRangeExcl(3)},
+		{lower: testRangeIncl(1000), upper: testRangeIncl(1000)},
+	}
+	for _, dir := range []Direction{Ascending, Descending} {
+		dirStr, otherDir, expectedType := "Asc", Descending, RangeKeyAsc
+		if dir == Descending {
+			dirStr, otherDir, expectedType = "Desc", Ascending, RangeKeyDesc
+		}
+		t.Run(dirStr, func(t *testing.T) {
+			var last []byte
+			for i, r := range ranges {
+				enc := EncodeRangeKeyMarker(nil, dir)
+				enc = encodeTestRangeBounds(enc, r, dir)
+
+				// The encodings sort like the ranges.
+				if i > 0 {
+					c := bytes.Compare(last, enc)
+					if (dir == Ascending && c >= 0) || (dir == Descending && c <= 0) {
+						t.Errorf("expected %s to sort after %s: [% x] vs [% x]",
+							r, ranges[i-1], enc, last)
+					}
+				}
+				last = enc
+
+				require.Equal(t, expectedType, PeekType(enc), r.String())
+				testPeekLength(t, enc)
+
+				rest, err := ValidateAndConsumeRangeKeyMarker(enc, dir)
+				require.NoError(t, err)
+				rest, decoded, err := decodeTestRangeBounds(rest, dir)
+				require.NoError(t, err)
+				require.Empty(t, rest, r.String())
+				require.Equal(t, r, decoded)
+
+				// The encoding in the other direction is rejected.
+				_, err = ValidateAndConsumeRangeKeyMarker(enc, otherDir)
+				require.Error(t, err)
+
+				// Truncated encodings are rejected.
+				for n := 1; n < len(enc); n++ {
+					if _, err := PeekLength(enc[:n]); err == nil {
+						t.Errorf("expected error for truncated encoding [% x] of %s", enc[:n], r)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeDecodeMultirangeKey(t *testing.T) {
+	// The multiranges are listed in the order of their ascending encodings.
+	multiranges := [][]testRange{
+		{},
+		{{lower: testRangeNegInf, upper: testRangeExcl(1)}},
+		{{lower: testRangeNegInf, upper: testRangeExcl(1)}, {lower: testRangeIncl(5), upper: testRangePosInf}},
+		{{lower: testRangeIncl(1), upper: testRangeExcl(2)}},
+		{{lower: testRangeIncl(1), upper: testRangeExcl(2)}, {lower: testRangeIncl(3), upper: testRangeExcl(4)}},
+		{{lower: testRangeIncl(1), upper: testRangeExcl(2)}, {lower: testRangeIncl(5), upper: testRangePosInf}},
+		{{lower: testRangeIncl(1), upper: testRangeExcl(3)}},
+		{{lower: testRangeIncl(2), upper: testRangeExcl(3)}},
+	}
+	for _, dir := range []Direction{Ascending, Descending} {
+		dirStr, expectedType := "Asc", MultirangeKeyAsc
+		if dir == Descending {
+			dirStr, expectedType = "Desc", MultirangeKeyDesc
+		}
+		t.Run(dirStr, func(t *testing.T) {
+			var last []byte
+			for i, mr := range multiranges {
+				enc := encodeTestMultirange(nil, mr, dir)
+
+				// The encodings sort like the multiranges.
+				if i > 0 {
+					c := bytes.Compare(last, enc)
+					if (dir == Ascending && c >= 0) || (dir == Descending && c <= 0) {
+						t.Errorf("expected %v to sort after %v: [% x] vs [% x]",
+							mr, multiranges[i-1], enc, last)
+					}
+				}
+				last = enc
+
+				require.Equal(t, expectedType, PeekType(enc))
+				testPeekLength(t, enc)
+
+				rest, err := ValidateAndConsumeMultirangeKeyMarker(enc, dir)
+				require.NoError(t, err)
+				var decoded []testRange
+				for !IsMultirangeKeyDone(rest, dir) {
+					var r testRange
+					rest, r, err = decodeTestRangeBounds(rest, dir)
+					require.NoError(t, err)
+					decoded = append(decoded, r)
+				}
+				require.Equal(t, []byte{rest[0]}, rest)
+				require.Equal(t, len(mr), len(decoded))
+				for j := range mr {
+					require.Equal(t, mr[j], decoded[j])
+				}
+
+				// Truncated encodings are rejected.
+				for n := 1; n < len(enc); n++ {
+					if _, err := PeekLength(enc[:n]); err == nil {
+						t.Errorf("expected error for truncated encoding [% x] of %v", enc[:n], mr)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPrettyPrintRangeKey(t *testing.T) {
+	testData := []struct {
+		key []byte
+		exp string
+	}{
+		{
+			key: EncodeEmptyRangeKey(EncodeRangeKeyMarker(nil, Ascending), Ascending),
+			exp: "/empty",
+		},
+		{
+			key: encodeTestRangeBounds(EncodeRangeKeyMarker(nil, Ascending),
+				testRange{lower: testRangeIncl(1), upper: testRangeExcl(10)}, Ascending),
+			exp: "/[1,10)",
+		},
+		{
+			key: encodeTestRangeBounds(EncodeRangeKeyMarker(nil, Ascending),
+				testRange{lower: testRangeNegInf, upper: testRangeIncl(3)}, Ascending),
+			exp: "/(,3]",
+		},
+		{
+			key: encodeTestMultirange(nil, []testRange{
+				{lower: testRangeIncl(1), upper: testRangeExcl(2)},
+				{lower: testRangeExcl(5), upper: testRangePosInf},
+			}, Ascending),
+			exp: "/{[1,2),(5,)}",
+		},
+	}
+	for _, test := range testData {
+		t.Run(test.exp[1:], func(t *testing.T) {
+			var buf redact.StringBuilder
+			PrettyPrintValue(&buf, []Direction{Ascending}, test.key, "/")
+			require.Equal(t, test.exp, buf.String())
+		})
+	}
+}
+
 func TestEncodeDecodeDuration(t *testing.T) {
 	testCases := []testCaseDuration{
 		{duration.DecodeDuration(0, 0, 0), []byte{0x16, 0x88, 0x88, 0x88}},
@@ -1591,6 +1864,10 @@ func TestPeekType(t *testing.T) {
 		{encodedDurationDescending, Duration},
 		{EncodeBitArrayAscending(nil, bitarray.BitArray{}), BitArray},
 		{EncodeBitArrayDescending(nil, bitarray.BitArray{}), BitArrayDesc},
+		{EncodeEmptyRangeKey(EncodeRangeKeyMarker(nil, Ascending), Ascending), RangeKeyAsc},
+		{EncodeEmptyRangeKey(EncodeRangeKeyMarker(nil, Descending), Descending), RangeKeyDesc},
+		{EncodeMultirangeKeyTerminator(EncodeMultirangeKeyMarker(nil, Ascending), Ascending), MultirangeKeyAsc},
+		{EncodeMultirangeKeyTerminator(EncodeMultirangeKeyMarker(nil, Descending), Descending), MultirangeKeyDesc},
 	}
 	for i, c := range testCases {
 		typ := PeekType(c.enc)
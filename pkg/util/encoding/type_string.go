@@ -54,6 +54,12 @@ func _() {
 	_ = x[JsonEmptyArray-42]
 	_ = x[JsonEmptyArrayDesc-43]
 	_ = x[PGVector-44]
+	_ = x[RangeKeyAsc-45]
+	_ = x[RangeKeyDesc-46]
+	_ = x[MultirangeKeyAsc-47]
+	_ = x[MultirangeKeyDesc-48]
+	_ = x[Range-49]
+	_ = x[Multirange-50]
 }
 
 func (i Type) String() string {
@@ -148,6 +154,18 @@ func (i Type) String() string {
 		return "JsonEmptyArrayDesc"
 	case PGVector:
 		return "PGVector"
+	case RangeKeyAsc:
+		return "RangeKeyAsc"
+	case RangeKeyDesc:
+		return "RangeKeyDesc"
+	case MultirangeKeyAsc:
+		return "MultirangeKeyAsc"
+	case MultirangeKeyDesc:
+		return "MultirangeKeyDesc"
+	case Range:
+		return "Range"
+	case Multirange:
+		return "Multirange"
 	default:
 		return "Type(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
@@ -370,6 +370,32 @@ func ApplyConfig(
 		attachSinkInfo(httpSinkInfo, &fc.Channels)
 	}
 
+	// Create the syslog sinks.
+	for _, fc := range config.Sinks.SyslogServers {
+		if fc.Filter == severity.NONE {
+			continue
+		}
+		syslogSinkInfo, err := newSyslogSinkInfo(*fc)
+		if err != nil {
+			return nil, err
+		}
+		attachBufferWrapper(syslogSinkInfo, fc.CommonSinkConfig.Buffering, closer)
+		attachSinkInfo(syslogSinkInfo, &fc.Channels)
+	}
+
+	// Create the OTLP sinks.
+	for _, fc := range config.Sinks.OTLPServers {
+		if fc.Filter == severity.NONE {
+			continue
+		}
+		otlpSinkInfo, err := newOTLPSinkInfo(*fc)
+		if err != nil {
+			return nil, err
+		}
+		attachBufferWrapper(otlpSinkInfo, fc.CommonSinkConfig.Buffering, closer)
+		attachSinkInfo(otlpSinkInfo, &fc.Channels)
+	}
+
 	// Prepend the interceptor sink to all channels.
 	// We prepend it because we want the interceptors
 	// to see every event before they make their way to disk/network.
@@ -437,6 +463,46 @@ func newHTTPSinkInfo(c logconfig.HTTPSinkConfig) (*sinkInfo, error) {
 	return info, nil
 }
 
+// newSyslogSinkInfo creates a new syslogSink and its accompanying sinkInfo
+// from the provided configuration.
+func newSyslogSinkInfo(c logconfig.SyslogSinkConfig) (*sinkInfo, error) {
+	info := &sinkInfo{}
+	if err := info.applyConfig(c.CommonSinkConfig); err != nil {
+		return nil, err
+	}
+	info.applyFilters(c.Channels)
+	// The syslog header is added around the entries formatted
+	// using the configured format.
+	info.formatter = newSyslogFormatter(info.formatter, c)
+
+	syslogSink, err := newSyslogSink(c)
+	if err != nil {
+		return nil, err
+	}
+	info.sink = syslogSink
+	return info, nil
+}
+
+// newOTLPSinkInfo creates a new otlpSink and its accompanying sinkInfo
+// from the provided configuration.
+func newOTLPSinkInfo(c logconfig.OTLPSinkConfig) (*sinkInfo, error) {
+	info := &sinkInfo{}
+	if err := info.applyConfig(c.CommonSinkConfig); err != nil {
+		return nil, err
+	}
+	info.applyFilters(c.Channels)
+	// Each entry formatted using the configured format becomes the body
+	// of an OTLP log record.
+	info.formatter = otlpFormatter{logFormatter: info.formatter}
+
+	otlpSink, err := newOTLPSink(c)
+	if err != nil {
+		return nil, err
+	}
+	info.sink = otlpSink
+	return info, nil
+}
+
 // applyFilters applies the channel filters to a sinkInfo.
 func (l *sinkInfo) applyFilters(chs logconfig.ChannelFilters) {
 	for ch, threshold := range chs.ChannelFilters {
@@ -666,6 +732,61 @@ func DescribeAppliedConfig() string {
 		return nil
 	})
 
+	// Describe the syslog sinks.
+	config.Sinks.SyslogServers = make(map[string]*logconfig.SyslogSinkConfig)
+	sIdx = 1
+	_ = logging.allSinkInfos.iter(func(l *sinkInfo) error {
+		slSink, ok := l.sink.(*syslogSink)
+		if !ok {
+			// Check to see if it's a syslogSink wrapped in a bufferedSink.
+			bufferedSink, ok := l.sink.(*bufferedSink)
+			if !ok {
+				return nil
+			}
+			slSink, ok = bufferedSink.child.(*syslogSink)
+			if !ok {
+				return nil
+			}
+		}
+
+		sc := &logconfig.SyslogSinkConfig{}
+		sc.SyslogDefaults = slSink.config.SyslogDefaults
+		sc.CommonSinkConfig = l.describeAppliedConfig()
+		sc.Net = slSink.network
+		sc.Address = slSink.addr
+
+		// Describe the connections to this syslog sink.
+		for ch, logger := range chans {
+			describeConnections(logger, ch, l, &sc.Channels)
+		}
+		skey := fmt.Sprintf("s%d", sIdx)
+		sIdx++
+		config.Sinks.SyslogServers[skey] = sc
+		return nil
+	})
+
+	// Describe the OTLP sinks.
+	config.Sinks.OTLPServers = make(map[string]*logconfig.OTLPSinkConfig)
+	sIdx = 1
+	_ = logging.allSinkInfos.iter(func(l *sinkInfo) error {
+		netSink, ok := l.sink.(*otlpSink)
+		if !ok {
+			// Check to see if it's an otlpSink wrapped in a bufferedSink.
+			bufferedSink, ok := l.sink.(*bufferedSink)
+			if !ok {
+				return nil
+			}
+			netSink, ok = bufferedSink.child.(*otlpSink)
+			if !ok {
+				return nil
+			}
+		}
+		skey := fmt.Sprintf("s%d", sIdx)
+		sIdx++
+		config.Sinks.OTLPServers[skey] = netSink.config
+		return nil
+	})
+
 	// Note: we cannot return 'config' directly, because this captures
 	// certain variables from the loggers by reference and thus could be
 	// invalidated by concurrent uses of ApplyConfig().
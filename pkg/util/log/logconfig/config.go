@@ -43,6 +43,14 @@ const DefaultFluentFormat = `json-fluent-compact`
 // when not specified in a configuration.
 const DefaultHTTPFormat = `json-compact`
 
+// DefaultSyslogFormat is the entry format for syslog sinks
+// when not specified in a configuration.
+const DefaultSyslogFormat = `crdb-v2`
+
+// DefaultOTLPFormat is the entry format for OTLP sinks
+// when not specified in a configuration.
+const DefaultOTLPFormat = `json-compact`
+
 // DefaultFilePerms is the default permissions used in file-defaults. It
 // is applied literally via os.Chmod, without considering the umask.
 const DefaultFilePerms = FilePermissions(0o640)
@@ -80,6 +88,25 @@ http-defaults:
       max-staleness: 5s	
       flush-trigger-size: 1mib
       max-buffer-size: 50mib
+syslog-defaults:
+    filter: INFO
+    format: ` + DefaultSyslogFormat + `
+    redactable: true
+    exit-on-error: false
+    buffering:
+      max-staleness: 5s
+      flush-trigger-size: 1mib
+      max-buffer-size: 50mib
+otlp-defaults:
+    filter: INFO
+    format: ` + DefaultOTLPFormat + `
+    redactable: true
+    exit-on-error: false
+    timeout: 2s
+    buffering:
+      max-staleness: 5s
+      flush-trigger-size: 1mib
+      max-buffer-size: 50mib
 sinks:
   stderr:
     filter: NONE
@@ -133,6 +160,16 @@ type Config struct {
 	// configuration value.
 	HTTPDefaults HTTPDefaults `yaml:"http-defaults,omitempty"`
 
+	// SyslogDefaults represents the default configuration for syslog sinks,
+	// inherited when a specific syslog sink config does not provide a
+	// configuration value.
+	SyslogDefaults SyslogDefaults `yaml:"syslog-defaults,omitempty"`
+
+	// OTLPDefaults represents the default configuration for OTLP sinks,
+	// inherited when a specific OTLP sink config does not provide a
+	// configuration value.
+	OTLPDefaults OTLPDefaults `yaml:"otlp-defaults,omitempty"`
+
 	// Sinks represents the sink configurations.
 	Sinks SinkConfig `yaml:",omitempty"`
 
@@ -257,6 +294,10 @@ type SinkConfig struct {
 	FluentServers map[string]*FluentSinkConfig `yaml:"fluent-servers,omitempty"`
 	// HTTPServers represents the list of configured http sinks.
 	HTTPServers map[string]*HTTPSinkConfig `yaml:"http-servers,omitempty"`
+	// SyslogServers represents the list of configured syslog sinks.
+	SyslogServers map[string]*SyslogSinkConfig `yaml:"syslog-servers,omitempty"`
+	// OTLPServers represents the list of configured OTLP sinks.
+	OTLPServers map[string]*OTLPSinkConfig `yaml:"otlp-servers,omitempty"`
 	// Stderr represents the configuration for the stderr sink.
 	Stderr StderrSinkConfig `yaml:",omitempty"`
 }
@@ -577,6 +618,196 @@ type HTTPSinkConfig struct {
 	sinkName string
 }
 
+// SyslogDefaults represents the configuration defaults for syslog sinks.
+type SyslogDefaults struct {
+	// Facility is the syslog facility used in the priority of the
+	// messages, for example "user", "daemon" or "local0" to "local7".
+	// Defaults to "user".
+	Facility *SyslogFacility `yaml:",omitempty"`
+
+	// AppName is the APP-NAME field of the syslog messages.
+	// Defaults to "cockroach".
+	AppName *string `yaml:"app-name,omitempty"`
+
+	// UnsafeTLS enables certificate authentication to be bypassed
+	// for the "tls" protocol. Defaults to false.
+	UnsafeTLS *bool `yaml:"unsafe-tls,omitempty"`
+
+	// CAFile is the path to a file containing the PEM-encoded CA
+	// certificates used to verify the certificate of the syslog
+	// server for the "tls" protocol. If unset, the system's trusted
+	// CA certificates are used.
+	CAFile *string `yaml:"ca-file,omitempty"`
+
+	// CommonSinkConfig is the configuration common to all sinks. Note
+	// that although the idiom in Go is to place embedded fields at the
+	// beginning of a struct, we purposefully deviate from the idiom
+	// here to ensure that "general" options appear after the
+	// sink-specific options in YAML config dumps.
+	CommonSinkConfig `yaml:",inline"`
+}
+
+// SyslogSinkConfig represents the configuration for one syslog sink.
+//
+// User-facing documentation follows.
+// TITLE: Output to syslog servers
+//
+// This sink type causes logging data to be sent over the network to
+// a syslog server, as messages in the
+// [RFC 5424](https://www.rfc-editor.org/rfc/rfc5424) format.
+//
+// The messages can be sent over TCP, over TLS as per
+// [RFC 5425](https://www.rfc-editor.org/rfc/rfc5425), or over UDP.
+// Over TCP and TLS, messages are framed using octet counting.
+// Over UDP, each message is sent in a separate datagram.
+//
+// The priority of each message is derived from the configured
+// facility and the severity of the logging event, and the MSGID field
+// is set to the name of the logging channel. The message itself
+// contains the logging event in the configured format.
+//
+// The configuration key under the `sinks` key in the YAML
+// configuration is `syslog-servers`. Example configuration:
+//
+//	sinks:
+//	   syslog-servers:
+//	      health:
+//	         channels: HEALTH
+//	         net: tls
+//	         address: syslog.example.com:6514
+//	         facility: local0
+//
+// Every new server sink configured automatically inherits the configuration set in the `syslog-defaults` section.
+//
+// For example:
+//
+//	syslog-defaults:
+//	    redactable: false # default: disable redaction markers
+//	sinks:
+//	  syslog-servers:
+//	    health:
+//	       channels: HEALTH
+//	       # This sink has redactable set to false,
+//	       # as the setting is inherited from syslog-defaults
+//	       # unless overridden here.
+//
+// The default output format for syslog sinks is
+// `crdb-v2`. [Other supported formats.](log-formats.html)
+//
+// The buffering format of syslog sinks is always `none`, since the
+// messages are framed by the sink itself.
+//
+// {{site.data.alerts.callout_info}}
+// Run `cockroach debug check-log-config` to verify the effect of defaults inheritance.
+// {{site.data.alerts.end}}
+type SyslogSinkConfig struct {
+	// Channels is the list of logging channels that use this sink.
+	Channels ChannelFilters `yaml:",omitempty,flow"`
+
+	// Net is the protocol for the syslog server. Can be "tcp", "tls",
+	// "udp", "tcp4", "udp6", etc. Defaults to "tcp".
+	Net string `yaml:",omitempty"`
+
+	// Address is the network address of the syslog server. The
+	// host/address and port parts are separated with a colon. IPv6
+	// numeric addresses should be included within square brackets,
+	// e.g.: [::1]:1234.
+	Address string `yaml:""`
+
+	// SyslogDefaults contains the defaultable fields of the config.
+	SyslogDefaults `yaml:",inline"`
+
+	// sinkName is populated during validation.
+	sinkName string
+}
+
+// OTLPDefaults represents the configuration defaults for OTLP sinks.
+type OTLPDefaults struct {
+	// Address is the URL of the OTLP/HTTP logs endpoint of the log
+	// collector, for example http://127.0.0.1:4318. If the URL has no
+	// path, the standard /v1/logs path is used.
+	Address *string `yaml:",omitempty"`
+
+	// UnsafeTLS enables certificate authentication to be bypassed.
+	// Defaults to false.
+	UnsafeTLS *bool `yaml:"unsafe-tls,omitempty"`
+
+	// Timeout is the timeout of the requests to the collector.
+	// Defaults to 2s.
+	Timeout *time.Duration `yaml:",omitempty"`
+
+	// Headers is a list of headers to attach to each request.
+	Headers map[string]string `yaml:",omitempty,flow"`
+
+	// Compression can be "none" or "gzip" to enable gzip compression.
+	// Set to "gzip" by default.
+	Compression *string `yaml:",omitempty"`
+
+	// ResourceAttributes is a list of attributes to attach to the
+	// resource of the exported log records, in addition to the
+	// service.name attribute.
+	ResourceAttributes map[string]string `yaml:"resource-attributes,omitempty,flow"`
+
+	CommonSinkConfig `yaml:",inline"`
+}
+
+// OTLPSinkConfig represents the configuration for one OTLP sink.
+//
+// User-facing documentation follows.
+// TITLE: Output to OpenTelemetry log collectors
+//
+// This sink type causes logging data to be sent over the network to
+// a log collector that implements the
+// [OpenTelemetry protocol](https://opentelemetry.io/docs/specs/otlp/)
+// (OTLP) over HTTP, using the JSON encoding.
+//
+// Each logging event is exported as an OTLP log record. The severity
+// and timestamp of the record are those of the event, the name of the
+// logging channel is reported in the `channel` attribute, and the body
+// of the record contains the logging event in the configured format.
+//
+// The configuration key under the `sinks` key in the YAML
+// configuration is `otlp-servers`. Example configuration:
+//
+//	sinks:
+//	   otlp-servers:
+//	      health:
+//	         channels: HEALTH
+//	         address: http://127.0.0.1:4318
+//
+// Every new server sink configured automatically inherits the configuration set in the `otlp-defaults` section.
+//
+// For example:
+//
+//	otlp-defaults:
+//	    redactable: false # default: disable redaction markers
+//	sinks:
+//	  otlp-servers:
+//	    health:
+//	       channels: HEALTH
+//	       # This sink has redactable set to false,
+//	       # as the setting is inherited from otlp-defaults
+//	       # unless overridden here.
+//
+// The default output format for OTLP sinks is
+// `json-compact`. [Other supported formats.](log-formats.html)
+//
+// The buffering format of OTLP sinks is always `json-array`, since
+// buffered log records are exported in a single request.
+//
+// {{site.data.alerts.callout_info}}
+// Run `cockroach debug check-log-config` to verify the effect of defaults inheritance.
+// {{site.data.alerts.end}}
+type OTLPSinkConfig struct {
+	// Channels is the list of logging channels that use this sink.
+	Channels ChannelFilters `yaml:",omitempty,flow"`
+
+	OTLPDefaults `yaml:",inline"`
+
+	// sinkName is populated during validation.
+	sinkName string
+}
+
 // IterateDirectories calls the provided fn on every directory linked to
 // by the configuration.
 func (c *Config) IterateDirectories(fn func(d string) error) error {
@@ -1165,6 +1396,74 @@ func (hsm *HTTPSinkMethod) UnmarshalYAML(fn func(interface{}) error) error {
 	return unmarshalYAMLConstrainedString(hsm, fn)
 }
 
+// SyslogFacility is a string restricted to the names of the syslog
+// facilities.
+type SyslogFacility string
+
+// syslogFacilities maps the names of the syslog facilities to their
+// numerical codes, as defined in RFC 5424.
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+var _ constrainedString = (*SyslogFacility)(nil)
+
+// Code returns the numerical code of the facility.
+func (f SyslogFacility) Code() int {
+	return syslogFacilities[string(f)]
+}
+
+// Accept implements the constrainedString interface.
+func (f *SyslogFacility) Accept(s string) {
+	*f = SyslogFacility(s)
+}
+
+// Canonicalize implements the constrainedString interface.
+func (SyslogFacility) Canonicalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// AllowedSet implements the constrainedString interface.
+func (SyslogFacility) AllowedSet() []string {
+	names := make([]string, 0, len(syslogFacilities))
+	for name := range syslogFacilities {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return syslogFacilities[names[i]] < syslogFacilities[names[j]]
+	})
+	return names
+}
+
+// MarshalYAML implements yaml.Marshaler interface.
+func (f SyslogFacility) MarshalYAML() (interface{}, error) {
+	return string(f), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (f *SyslogFacility) UnmarshalYAML(fn func(interface{}) error) error {
+	return unmarshalYAMLConstrainedString(f, fn)
+}
+
 // constrainedString is an interface to make it easy to unmarshal
 // a string constrained to a small set of accepted values.
 type constrainedString interface {
@@ -0,0 +1,263 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/errors"
+)
+
+// otlpSink represents an OpenTelemetry log collector reachable over
+// OTLP/HTTP. Requests are encoded using the JSON encoding of the OTLP
+// protocol.
+type otlpSink struct {
+	client  http.Client
+	address string
+	config  *logconfig.OTLPSinkConfig
+	// prefix and suffix surround the array of log records in the body of
+	// each request. They encode the resource and scope of the records,
+	// which do not change over the lifetime of the sink.
+	prefix, suffix []byte
+}
+
+// otlpLogsPath is the default URL path of the OTLP/HTTP logs endpoint.
+const otlpLogsPath = "/v1/logs"
+
+// otlpServiceName is the service name reported to the collector, unless
+// overridden by the resource attributes in the sink configuration.
+const otlpServiceName = "cockroachdb"
+
+func newOTLPSink(c logconfig.OTLPSinkConfig) (*otlpSink, error) {
+	u, err := url.Parse(*c.Address)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing OTLP collector address")
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = otlpLogsPath
+	}
+
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, errors.AssertionFailedf("http.DefaultTransport is not a http.Transport: %T", http.DefaultTransport)
+	}
+	transport = transport.Clone()
+	if *c.UnsafeTLS {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	resourceAttrs := map[string]string{"service.name": otlpServiceName}
+	for k, v := range c.ResourceAttributes {
+		resourceAttrs[k] = v
+	}
+	resource, err := json.Marshal(otlpAttributes(resourceAttrs))
+	if err != nil {
+		return nil, err
+	}
+	prefix := []byte(`{"resourceLogs":[{"resource":{"attributes":`)
+	prefix = append(prefix, resource...)
+	prefix = append(prefix, `},"scopeLogs":[{"scope":{"name":"`+otlpServiceName+`"},"logRecords":`...)
+
+	return &otlpSink{
+		client: http.Client{
+			Transport: transport,
+			Timeout:   *c.Timeout,
+		},
+		address: u.String(),
+		config:  &c,
+		prefix:  prefix,
+		suffix:  []byte(`}]}]}`),
+	}, nil
+}
+
+// active implements the logSink interface.
+func (*otlpSink) active() bool {
+	return true
+}
+
+// attachHints implements the logSink interface.
+func (*otlpSink) attachHints(stacks []byte) []byte {
+	return stacks
+}
+
+// exitCode implements the logSink interface.
+func (*otlpSink) exitCode() exit.Code {
+	return exit.LoggingNetCollectorUnavailable()
+}
+
+// output implements the logSink interface.
+//
+// b is either a single log record, or a JSON array of log records when
+// the sink is buffered.
+func (s *otlpSink) output(b []byte, opts sinkOutputOptions) error {
+	var body bytes.Buffer
+	var w io.Writer = &body
+	var g *gzip.Writer
+	if *s.config.Compression == logconfig.GzipCompression {
+		g = gzip.NewWriter(&body)
+		w = g
+	}
+	_, _ = w.Write(s.prefix)
+	if len(b) > 0 && b[0] == '[' {
+		_, _ = w.Write(b)
+	} else {
+		_, _ = w.Write([]byte{'['})
+		_, _ = w.Write(b)
+		_, _ = w.Write([]byte{']'})
+	}
+	if _, err := w.Write(s.suffix); err != nil {
+		return err
+	}
+	if g != nil {
+		if err := g.Close(); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.address, &body)
+	if err != nil {
+		return err
+	}
+	for k, v := range s.config.Headers {
+		req.Header.Add(k, v)
+	}
+	if g != nil {
+		req.Header.Add(httputil.ContentEncodingHeader, httputil.GzipEncoding)
+	}
+	req.Header.Set(httputil.ContentTypeHeader, httputil.JSONContentType)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close() // don't care about content
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return HTTPLogError{
+			StatusCode: resp.StatusCode,
+			Address:    s.address,
+		}
+	}
+	return nil
+}
+
+// otlpAnyValue is the JSON encoding of an OTLP AnyValue. Only the value
+// types used by the sink are represented. As per the OTLP JSON encoding,
+// 64-bit integers are encoded as strings.
+type otlpAnyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+// otlpKeyValue is the JSON encoding of an OTLP KeyValue.
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+// otlpLogRecord is the JSON encoding of an OTLP LogRecord.
+type otlpLogRecord struct {
+	TimeUnixNano   string         `json:"timeUnixNano"`
+	SeverityNumber int            `json:"severityNumber,omitempty"`
+	SeverityText   string         `json:"severityText,omitempty"`
+	Body           otlpAnyValue   `json:"body"`
+	Attributes     []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+func otlpString(s string) otlpAnyValue {
+	return otlpAnyValue{StringValue: &s}
+}
+
+func otlpInt(i int64) otlpAnyValue {
+	s := strconv.FormatInt(i, 10)
+	return otlpAnyValue{IntValue: &s}
+}
+
+// otlpAttributes returns the given attributes as OTLP key-values, sorted
+// by key.
+func otlpAttributes(attrs map[string]string) []otlpKeyValue {
+	kvs := make([]otlpKeyValue, 0, len(attrs))
+	for k, v := range attrs {
+		kvs = append(kvs, otlpKeyValue{Key: k, Value: otlpString(v)})
+	}
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+	return kvs
+}
+
+// otlpSeverityNumber returns the OpenTelemetry severity number for the
+// given severity.
+func otlpSeverityNumber(sev Severity) int {
+	switch sev {
+	case severity.INFO:
+		return 9
+	case severity.WARNING:
+		return 13
+	case severity.ERROR:
+		return 17
+	case severity.FATAL:
+		return 21
+	default:
+		// SEVERITY_NUMBER_UNSPECIFIED.
+		return 0
+	}
+}
+
+// otlpFormatter wraps the formatter configured for an OTLP sink,
+// turning each entry into an OTLP log record whose body is the entry
+// formatted by the wrapped formatter.
+type otlpFormatter struct {
+	logFormatter
+}
+
+// formatEntry implements the logFormatter interface.
+func (f otlpFormatter) formatEntry(entry logEntry) *buffer {
+	inner := f.logFormatter.formatEntry(entry)
+	defer putBuffer(inner)
+
+	rec := otlpLogRecord{
+		TimeUnixNano: strconv.FormatInt(entry.ts, 10),
+		Body:         otlpString(string(bytes.TrimSuffix(inner.Bytes(), []byte{'\n'}))),
+	}
+	if !entry.header {
+		rec.SeverityNumber = otlpSeverityNumber(entry.sev)
+		rec.SeverityText = entry.sev.String()
+		rec.Attributes = append(rec.Attributes, otlpKeyValue{Key: "channel", Value: otlpString(entry.ch.String())})
+	}
+	if entry.file != "" {
+		rec.Attributes = append(rec.Attributes,
+			otlpKeyValue{Key: "code.filepath", Value: otlpString(entry.file)},
+			otlpKeyValue{Key: "code.lineno", Value: otlpInt(int64(entry.line))})
+	}
+
+	buf := getBuffer()
+	if err := json.NewEncoder(buf).Encode(rec); err != nil {
+		// The record only contains strings and integers, so this cannot
+		// happen.
+		panic(errors.NewAssertionErrorWithWrappedErrf(err, "encoding OTLP log record"))
+	}
+	// Strip the newline added by the encoder; records are delimited by the
+	// sink or by the buffering.
+	buf.Truncate(buf.Len() - 1)
+	return buf
+}
+
+// contentType implements the logFormatter interface.
+func (otlpFormatter) contentType() string { return httputil.JSONContentType }
@@ -0,0 +1,172 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/stretchr/testify/require"
+)
+
+// otlpRequest is the subset of an OTLP/HTTP logs export request checked
+// by the tests.
+type otlpRequest struct {
+	ResourceLogs []struct {
+		Resource struct {
+			Attributes []otlpKeyValue `json:"attributes"`
+		} `json:"resource"`
+		ScopeLogs []struct {
+			LogRecords []otlpLogRecord `json:"logRecords"`
+		} `json:"scopeLogs"`
+	} `json:"resourceLogs"`
+}
+
+func TestOTLPSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, compression := range []string{logconfig.GzipCompression, logconfig.NoneCompression} {
+		t.Run(compression, func(t *testing.T) {
+			sc := ScopeWithoutShowLogs(t)
+			defer sc.Close(t)
+
+			requests := make(chan otlpRequest, 10)
+			handler := func(rw http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/logs" {
+					t.Errorf("unexpected path: %s", r.URL.Path)
+				}
+				if ct := r.Header.Get(httputil.ContentTypeHeader); ct != httputil.JSONContentType {
+					t.Errorf("unexpected content type: %s", ct)
+				}
+				if v := r.Header.Get("X-Test"); v != "yes" {
+					t.Errorf("missing custom header")
+				}
+				var body io.Reader = r.Body
+				if r.Header.Get(httputil.ContentEncodingHeader) == httputil.GzipEncoding {
+					g, err := gzip.NewReader(r.Body)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					body = g
+				} else if compression == logconfig.GzipCompression {
+					t.Errorf("request is not compressed")
+				}
+				var req otlpRequest
+				if err := json.NewDecoder(body).Decode(&req); err != nil {
+					t.Errorf("unable to decode request: %v", err)
+					return
+				}
+				select {
+				case requests <- req:
+				default:
+				}
+			}
+			s := httptest.NewServer(http.HandlerFunc(handler))
+			defer s.Close()
+
+			// Set up a logging configuration with the server we've just set
+			// up as target for the OPS channel.
+			cfg := logconfig.DefaultConfig()
+			comp := compression
+			cfg.Sinks.OTLPServers = map[string]*logconfig.OTLPSinkConfig{
+				"ops": {
+					Channels: logconfig.SelectChannels(channel.OPS),
+					OTLPDefaults: logconfig.OTLPDefaults{
+						Address:            &s.URL,
+						Compression:        &comp,
+						Headers:            map[string]string{"X-Test": "yes"},
+						ResourceAttributes: map[string]string{"deployment.environment": "test"},
+						CommonSinkConfig: logconfig.CommonSinkConfig{
+							Buffering: disabledBufferingCfg,
+						},
+					},
+				},
+			}
+			// Derive a full config using the same directory as the
+			// TestLogScope.
+			require.NoError(t, cfg.Validate(&sc.logDir))
+
+			// Apply the configuration.
+			TestingResetActive()
+			cleanup, err := ApplyConfig(cfg, nil /* fileSinkMetricsForDir */, nil /* fatalOnLogStall */)
+			require.NoError(t, err)
+			defer cleanup()
+
+			// Send a log event on the OPS channel.
+			Ops.Errorf(context.Background(), "hello world")
+
+			// Check that the event was indeed sent via the OTLP sink.
+			var rec *otlpLogRecord
+			for rec == nil {
+				var req otlpRequest
+				select {
+				case <-time.After(5 * time.Second):
+					t.Fatal("timeout")
+				case req = <-requests:
+				}
+				require.Len(t, req.ResourceLogs, 1)
+				require.Equal(t, otlpAttributes(map[string]string{
+					"deployment.environment": "test",
+					"service.name":           "cockroachdb",
+				}), req.ResourceLogs[0].Resource.Attributes)
+				require.Len(t, req.ResourceLogs[0].ScopeLogs, 1)
+				for _, r := range req.ResourceLogs[0].ScopeLogs[0].LogRecords {
+					if r.Body.StringValue != nil && strings.Contains(*r.Body.StringValue, "hello world") {
+						r := r
+						rec = &r
+					}
+				}
+			}
+
+			require.NotEmpty(t, rec.TimeUnixNano)
+			require.Equal(t, 17, rec.SeverityNumber)
+			require.Equal(t, "ERROR", rec.SeverityText)
+			require.Contains(t, rec.Attributes, otlpKeyValue{Key: "channel", Value: otlpString("OPS")})
+		})
+	}
+}
+
+func TestOTLPSinkError(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	sc := ScopeWithoutShowLogs(t)
+	defer sc.Close(t)
+
+	s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusBadRequest)
+	}))
+	defer s.Close()
+
+	cfg := logconfig.DefaultConfig()
+	cfg.Sinks.OTLPServers = map[string]*logconfig.OTLPSinkConfig{
+		"ops": {
+			Channels:     logconfig.SelectChannels(channel.OPS),
+			OTLPDefaults: logconfig.OTLPDefaults{Address: &s.URL},
+		},
+	}
+	require.NoError(t, cfg.Validate(&sc.logDir))
+
+	sink, err := newOTLPSink(*cfg.Sinks.OTLPServers["ops"])
+	require.NoError(t, err)
+	err = sink.output([]byte(`{"timeUnixNano":"1"}`), sinkOutputOptions{})
+	require.Equal(t, HTTPLogError{StatusCode: http.StatusBadRequest, Address: s.URL + "/v1/logs"}, err)
+}
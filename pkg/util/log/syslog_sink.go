@@ -0,0 +1,285 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// syslogSink represents a syslog collector reachable over the network
+// (RFC 5424). Messages are framed using octet counting (RFC 6587) on
+// stream transports, and sent as one datagram per message over UDP
+// (RFC 5426).
+type syslogSink struct {
+	// The network address of the syslog collector. network is "tls" for
+	// TLS over TCP.
+	network string
+	addr    string
+	// tlsConfig is set when network is "tls".
+	tlsConfig *tls.Config
+
+	config *logconfig.SyslogSinkConfig
+
+	mu struct {
+		syncutil.Mutex
+		// good indicates that the connection can be used.
+		good bool
+		conn net.Conn
+	}
+}
+
+const syslogDialTimeout = 5 * time.Second
+const syslogWriteTimeout = time.Second
+
+func newSyslogSink(c logconfig.SyslogSinkConfig) (*syslogSink, error) {
+	s := &syslogSink{
+		network: c.Net,
+		addr:    c.Address,
+		config:  &c,
+	}
+	if c.Net == "tls" {
+		s.tlsConfig = &tls.Config{InsecureSkipVerify: *c.UnsafeTLS}
+		if c.CAFile != nil && *c.CAFile != "" {
+			pem, err := os.ReadFile(*c.CAFile)
+			if err != nil {
+				return nil, errors.Wrap(err, "reading syslog CA certificate")
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, errors.Newf("no certificate found in %q", *c.CAFile)
+			}
+			s.tlsConfig.RootCAs = pool
+		}
+	}
+	return s, nil
+}
+
+func (l *syslogSink) String() string {
+	return fmt.Sprintf("syslog:%s://%s", l.network, l.addr)
+}
+
+// active implements the logSink interface.
+func (l *syslogSink) active() bool { return true }
+
+// attachHints implements the logSink interface.
+func (l *syslogSink) attachHints(stacks []byte) []byte {
+	return stacks
+}
+
+// exitCode implements the logSink interface.
+func (l *syslogSink) exitCode() exit.Code {
+	return exit.LoggingNetCollectorUnavailable()
+}
+
+// output implements the logSink interface.
+func (l *syslogSink) output(b []byte, opts sinkOutputOptions) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Try to write and reconnect immediately if the first write fails.
+	_ = l.tryWriteLocked(b)
+	if l.mu.good {
+		return nil
+	}
+	if err := l.ensureConnLocked(b); err != nil {
+		return err
+	}
+	return l.tryWriteLocked(b)
+}
+
+func (l *syslogSink) isDatagram() bool {
+	return strings.HasPrefix(l.network, "udp")
+}
+
+func (l *syslogSink) closeLocked() {
+	l.mu.good = false
+	if l.mu.conn != nil {
+		if err := l.mu.conn.Close(); err != nil {
+			fmt.Fprintf(OrigStderr, "error closing syslog logger: %v\n", err)
+		}
+		l.mu.conn = nil
+	}
+}
+
+func (l *syslogSink) ensureConnLocked(b []byte) error {
+	if l.mu.good {
+		return nil
+	}
+	l.closeLocked()
+	var err error
+	if l.tlsConfig != nil {
+		l.mu.conn, err = tls.DialWithDialer(
+			&net.Dialer{Timeout: syslogDialTimeout}, "tcp", l.addr, l.tlsConfig)
+	} else {
+		l.mu.conn, err = net.DialTimeout(l.network, l.addr, syslogDialTimeout)
+	}
+	if err != nil {
+		fmt.Fprintf(OrigStderr, "%s: error dialing syslog logger: %v\n%s", l, err, b)
+		return err
+	}
+	fmt.Fprintf(OrigStderr, "%s: connection to syslog logger resumed\n", l)
+	l.mu.good = true
+	return nil
+}
+
+func (l *syslogSink) tryWriteLocked(b []byte) error {
+	if !l.mu.good {
+		return errNoConn
+	}
+	if err := l.mu.conn.SetWriteDeadline(timeutil.Now().Add(syslogWriteTimeout)); err != nil {
+		// An error here is suggestive of a bug in the Go runtime.
+		fmt.Fprintf(OrigStderr, "%s: set write deadline error: %v\n%s", l, err, b)
+		l.mu.good = false
+		return err
+	}
+	if !l.isDatagram() {
+		return l.writeLocked(b)
+	}
+	// The octet-counting framing is not used over UDP; each message is sent
+	// in a datagram of its own.
+	for len(b) > 0 {
+		msg, rest, err := splitSyslogFrame(b)
+		if err != nil {
+			fmt.Fprintf(OrigStderr, "%s: %v\n%s", l, err, b)
+			return err
+		}
+		if err := l.writeLocked(msg); err != nil {
+			return err
+		}
+		b = rest
+	}
+	return nil
+}
+
+func (l *syslogSink) writeLocked(b []byte) error {
+	n, err := l.mu.conn.Write(b)
+	if err == nil && n < len(b) {
+		err = errors.Newf("short write (%d/%d)", n, len(b))
+	}
+	if err != nil {
+		fmt.Fprintf(OrigStderr, "%s: logging error: %v\n%s", l, err, b)
+		l.mu.good = false
+	}
+	return err
+}
+
+// splitSyslogFrame returns the first message of a sequence of
+// octet-counted syslog frames, and the remaining frames.
+func splitSyslogFrame(b []byte) (msg, rest []byte, _ error) {
+	sp := bytes.IndexByte(b, ' ')
+	if sp < 0 {
+		return nil, nil, errors.New("malformed syslog frame: missing message length")
+	}
+	n, err := strconv.Atoi(string(b[:sp]))
+	if err != nil || n < 0 || sp+1+n > len(b) {
+		return nil, nil, errors.Newf("malformed syslog frame: invalid message length %q", b[:sp])
+	}
+	b = b[sp+1:]
+	return b[:n], b[n:], nil
+}
+
+// syslogFormatter wraps the formatter configured for a syslog sink,
+// prepending the RFC 5424 header to each entry and framing the result
+// with its length.
+type syslogFormatter struct {
+	logFormatter
+
+	// facility is the facility code used to compute the message priority.
+	facility int
+	hostname string
+	appName  string
+	procID   string
+}
+
+// syslogTimeFormat is the RFC 3339 time format with microsecond
+// precision, as recommended by RFC 5424.
+const syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+func newSyslogFormatter(f logFormatter, c logconfig.SyslogSinkConfig) *syslogFormatter {
+	return &syslogFormatter{
+		logFormatter: f,
+		facility:     c.Facility.Code(),
+		hostname:     syslogHeaderField(getHostname()),
+		appName:      syslogHeaderField(*c.AppName),
+		procID:       strconv.Itoa(os.Getpid()),
+	}
+}
+
+func getHostname() string {
+	h, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	return h
+}
+
+// syslogHeaderField returns s in a form suitable for a field of the
+// syslog header, which cannot be empty nor contain spaces.
+func syslogHeaderField(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return strings.ReplaceAll(s, " ", "_")
+}
+
+// syslogSeverity returns the RFC 5424 severity for the given
+// severity.
+func syslogSeverity(sev Severity) int {
+	switch sev {
+	case severity.FATAL:
+		return 2 // critical
+	case severity.ERROR:
+		return 3 // error
+	case severity.WARNING:
+		return 4 // warning
+	default:
+		return 6 // informational
+	}
+}
+
+// formatEntry implements the logFormatter interface.
+func (f *syslogFormatter) formatEntry(entry logEntry) *buffer {
+	inner := f.logFormatter.formatEntry(entry)
+	defer putBuffer(inner)
+
+	msg := getBuffer()
+	defer putBuffer(msg)
+	msgID := "-"
+	if !entry.header {
+		msgID = entry.ch.String()
+	}
+	fmt.Fprintf(msg, "<%d>1 %s %s %s %s %s - ",
+		f.facility*8+syslogSeverity(entry.sev),
+		timeutil.Unix(0, entry.ts).UTC().Format(syslogTimeFormat),
+		f.hostname, f.appName, f.procID, msgID)
+	msg.Write(bytes.TrimSuffix(inner.Bytes(), []byte{'\n'}))
+
+	buf := getBuffer()
+	buf.WriteString(strconv.Itoa(msg.Len()))
+	buf.WriteByte(' ')
+	buf.Write(msg.Bytes())
+	return buf
+}
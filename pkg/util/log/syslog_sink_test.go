@@ -0,0 +1,225 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyslogSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, network := range []string{"tcp", "udp"} {
+		t.Run(network, func(t *testing.T) {
+			sc := ScopeWithoutShowLogs(t)
+			defer sc.Close(t)
+
+			var serverAddr string
+			var syslogData chan string
+			if network == "tcp" {
+				var cleanup func()
+				serverAddr, cleanup, syslogData = servePseudoSyslogTCP(t)
+				defer cleanup()
+			} else {
+				var cleanup func()
+				serverAddr, cleanup, syslogData = servePseudoSyslogUDP(t)
+				defer cleanup()
+			}
+
+			// Set up a logging configuration with the server we've just set
+			// up as target for the OPS channel.
+			cfg := logconfig.DefaultConfig()
+			facility := logconfig.SyslogFacility("local0")
+			cfg.Sinks.SyslogServers = map[string]*logconfig.SyslogSinkConfig{
+				"ops": {
+					Net:      network,
+					Address:  serverAddr,
+					Channels: logconfig.SelectChannels(channel.OPS),
+					SyslogDefaults: logconfig.SyslogDefaults{
+						Facility: &facility,
+						CommonSinkConfig: logconfig.CommonSinkConfig{
+							Buffering: disabledBufferingCfg,
+						},
+					},
+				},
+			}
+			// Derive a full config using the same directory as the
+			// TestLogScope.
+			require.NoError(t, cfg.Validate(&sc.logDir))
+
+			// Apply the configuration.
+			TestingResetActive()
+			cleanup, err := ApplyConfig(cfg, nil /* fileSinkMetricsForDir */, nil /* fatalOnLogStall */)
+			require.NoError(t, err)
+			defer cleanup()
+
+			// Send a log event on the OPS channel.
+			Ops.Warningf(context.Background(), "hello world")
+
+			// Check that the event was indeed sent via the syslog sink.
+			var msg string
+			for !strings.Contains(msg, "hello world") {
+				select {
+				case <-time.After(5 * time.Second):
+					t.Fatal("timeout")
+				case msg = <-syslogData:
+				}
+			}
+
+			// local0 is facility 16, and warnings have severity 4.
+			require.True(t, strings.HasPrefix(msg, "<132>1 "), "unexpected message: %q", msg)
+			fields := strings.SplitN(msg, " ", 8)
+			require.Len(t, fields, 8)
+			_, err = time.Parse(time.RFC3339Nano, fields[1])
+			require.NoError(t, err)
+			require.Equal(t, "cockroach", fields[3])
+			require.Equal(t, "OPS", fields[5])
+			require.Equal(t, "-", fields[6])
+			require.Contains(t, fields[7], "hello world")
+			require.False(t, strings.HasSuffix(msg, "\n"))
+		})
+	}
+}
+
+func TestSplitSyslogFrame(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	msg, rest, err := splitSyslogFrame([]byte("3 abc5 hello"))
+	require.NoError(t, err)
+	require.Equal(t, "abc", string(msg))
+	msg, rest, err = splitSyslogFrame(rest)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(msg))
+	require.Empty(t, rest)
+
+	for _, b := range []string{"abc", "x abc", "10 abc"} {
+		_, _, err := splitSyslogFrame([]byte(b))
+		require.ErrorContains(t, err, "malformed syslog frame")
+	}
+}
+
+// servePseudoSyslogTCP creates an in-memory TCP listener which accepts
+// octet-counted syslog messages and reports them over the returned
+// channel.
+func servePseudoSyslogTCP(
+	t *testing.T,
+) (serverAddr string, cleanup func(), syslogData chan string) {
+	l, err := net.ListenTCP("tcp", nil)
+	require.NoError(t, err)
+
+	syslogData = make(chan string, 10)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var conns []net.Conn
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				t.Logf("accept error: %v", err)
+				return
+			}
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				buf := bufio.NewReader(conn)
+				for {
+					length, err := buf.ReadString(' ')
+					if err != nil {
+						t.Logf("read error: %v", err)
+						return
+					}
+					n, err := strconv.Atoi(strings.TrimSuffix(length, " "))
+					if err != nil {
+						t.Errorf("invalid message length: %q", length)
+						return
+					}
+					msg := make([]byte, n)
+					if _, err := io.ReadFull(buf, msg); err != nil {
+						t.Logf("read error: %v", err)
+						return
+					}
+					select {
+					case syslogData <- string(msg):
+					default:
+						t.Logf("dropped message: %q", msg)
+					}
+				}
+			}()
+		}
+	}()
+	cleanup = func() {
+		// Close the listen socket and the client connections. This breaks
+		// any currently running call to Accept() or Read().
+		require.NoError(t, l.Close())
+		mu.Lock()
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+		mu.Unlock()
+		wg.Wait()
+	}
+	return l.Addr().String(), cleanup, syslogData
+}
+
+// servePseudoSyslogUDP creates an in-memory UDP listener which reports
+// the datagrams it receives over the returned channel.
+func servePseudoSyslogUDP(
+	t *testing.T,
+) (serverAddr string, cleanup func(), syslogData chan string) {
+	l, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	syslogData = make(chan string, 10)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		buf := make([]byte, 65536)
+		for {
+			n, _, err := l.ReadFrom(buf)
+			if err != nil {
+				t.Logf("read error: %v", err)
+				return
+			}
+			select {
+			case syslogData <- string(buf[:n]):
+			default:
+				t.Logf("dropped message: %q", buf[:n])
+			}
+		}
+	}()
+	cleanup = func() {
+		require.NoError(t, l.Close())
+		wg.Wait()
+	}
+	return l.Addr().String(), cleanup, syslogData
+}
@@ -0,0 +1,462 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package metric
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	otel_metrics_collector_pb "github.com/cockroachdb/cockroach/pkg/obsservice/obspb/opentelemetry-proto/collector/metrics/v1"
+	otel_pb "github.com/cockroachdb/cockroach/pkg/obsservice/obspb/opentelemetry-proto/common/v1"
+	otel_metrics_pb "github.com/cockroachdb/cockroach/pkg/obsservice/obspb/opentelemetry-proto/metrics/v1"
+	otel_res_pb "github.com/cockroachdb/cockroach/pkg/obsservice/obspb/opentelemetry-proto/resource/v1"
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	prometheusgo "github.com/prometheus/client_model/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// OTLPProtocol is the transport used by an OTLPExporter to push metrics to
+// an OpenTelemetry collector.
+type OTLPProtocol int64
+
+const (
+	// OTLPProtocolGRPC pushes metrics using OTLP/gRPC.
+	OTLPProtocolGRPC OTLPProtocol = iota
+	// OTLPProtocolHTTP pushes metrics using OTLP/HTTP, with the binary
+	// protobuf encoding.
+	OTLPProtocolHTTP
+)
+
+var errNoOTLPEndpoint = errors.New("external.otlp.metrics.endpoint is not set")
+
+// otlpMetricsPath is the default URL path of the OTLP/HTTP metrics endpoint.
+const otlpMetricsPath = "/v1/metrics"
+
+// otlpScopeName is the name of the instrumentation scope of the exported
+// metrics.
+const otlpScopeName = "cockroachdb"
+
+// otlpRetryOptions are the options used to retry failed pushes until the
+// context passed to Push is canceled.
+var otlpRetryOptions = retry.Options{
+	InitialBackoff:      100 * time.Millisecond,
+	MaxBackoff:          5 * time.Second,
+	Multiplier:          2,
+	RandomizationFactor: 0.15,
+}
+
+// OTLPExporter scrapes PrometheusExporter for metrics and pushes them to an
+// OpenTelemetry collector over OTLP/gRPC or OTLP/HTTP.
+//
+// The metrics are exported under their Prometheus names, so that they can
+// be correlated with the metrics scraped from the _status/vars endpoint.
+// Their labels, including the labels of the children of aggregate metrics
+// when these are scraped, are exported as attributes of the data points.
+// Counters and histograms are exported as cumulative sums and histograms
+// with explicit buckets respectively, with the creation time of the
+// exporter as their start time.
+//
+// Unless the endpoint has the http:// scheme, the collector is connected to
+// over TLS, verifying its certificate against the system's root CAs and the
+// custom root CA passed to Push, if any.
+type OTLPExporter struct {
+	pm        *PrometheusExporter
+	resource  otel_res_pb.Resource
+	startTime time.Time
+
+	mu struct {
+		syncutil.Mutex
+		// target is the endpoint to which conn is connected, if any, and
+		// grpcCustomCA is the custom root CA with which it was connected.
+		target       string
+		grpcCustomCA string
+		conn         *grpc.ClientConn
+		client       otel_metrics_collector_pb.MetricsServiceClient
+		// httpClient is the client used to push metrics over OTLP/HTTP, if
+		// any, and httpCustomCA is the custom root CA with which it was made.
+		httpClient   *http.Client
+		httpCustomCA string
+	}
+}
+
+// NewOTLPExporter returns an initialized OTLP exporter. The given attributes
+// describe the resource that produces the metrics, for example the node.
+func NewOTLPExporter(pm *PrometheusExporter, resourceAttrs map[string]string) *OTLPExporter {
+	keys := make([]string, 0, len(resourceAttrs))
+	for k := range resourceAttrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	oe := &OTLPExporter{
+		pm:        pm,
+		startTime: timeutil.Now(),
+	}
+	for _, k := range keys {
+		oe.resource.Attributes = append(oe.resource.Attributes, otlpStringAttribute(k, resourceAttrs[k]))
+	}
+	return oe
+}
+
+// Collect converts the metrics scraped into the PrometheusExporter into an
+// OTLP export request. Only the latest values of the metrics are exported:
+// the scraped metrics are cleared, whether the request is pushed
+// successfully or not.
+func (oe *OTLPExporter) Collect() *otel_metrics_collector_pb.ExportMetricsServiceRequest {
+	defer oe.pm.clearMetrics()
+	families, _ := oe.pm.Gather()
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].GetName() < families[j].GetName()
+	})
+
+	start := uint64(oe.startTime.UnixNano())
+	now := uint64(timeutil.Now().UnixNano())
+	metrics := make([]*otel_metrics_pb.Metric, 0, len(families))
+	for _, family := range families {
+		if m := otlpMetric(family, start, now); m != nil {
+			metrics = append(metrics, m)
+		}
+	}
+	return &otel_metrics_collector_pb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*otel_metrics_pb.ResourceMetrics{{
+			Resource: &oe.resource,
+			ScopeMetrics: []*otel_metrics_pb.ScopeMetrics{{
+				Scope:   &otel_pb.InstrumentationScope{Name: otlpScopeName},
+				Metrics: metrics,
+			}},
+		}},
+	}
+}
+
+// Push sends the request to the OpenTelemetry collector at the given
+// endpoint. If customCA is nonempty, it is a PEM-encoded root CA which is
+// trusted, in addition to the system's root CAs, to verify the certificate
+// of the collector. Failed attempts are retried with backoff until the
+// request succeeds or the context is canceled, so callers should bound the
+// context, for example to the export interval.
+func (oe *OTLPExporter) Push(
+	ctx context.Context,
+	protocol OTLPProtocol,
+	endpoint string,
+	customCA string,
+	req *otel_metrics_collector_pb.ExportMetricsServiceRequest,
+) error {
+	if endpoint == "" {
+		return errNoOTLPEndpoint
+	}
+	var err error
+	for r := retry.StartWithCtx(ctx, otlpRetryOptions); r.Next(); {
+		switch protocol {
+		case OTLPProtocolGRPC:
+			err = oe.pushGRPC(ctx, endpoint, customCA, req)
+		case OTLPProtocolHTTP:
+			err = oe.pushHTTP(ctx, endpoint, customCA, req)
+		default:
+			return errors.AssertionFailedf("unknown OTLP protocol %d", protocol)
+		}
+		if err == nil {
+			return nil
+		}
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	return errors.Wrapf(err, "pushing metrics to %s", endpoint)
+}
+
+// Close releases the connections to the collector, if any.
+func (oe *OTLPExporter) Close() {
+	oe.mu.Lock()
+	defer oe.mu.Unlock()
+	oe.closeLocked()
+	if oe.mu.httpClient != nil {
+		oe.mu.httpClient.CloseIdleConnections()
+		oe.mu.httpClient, oe.mu.httpCustomCA = nil, ""
+	}
+}
+
+func (oe *OTLPExporter) closeLocked() {
+	if oe.mu.conn != nil {
+		_ = oe.mu.conn.Close() // nolint:grpcconnclose
+		oe.mu.conn, oe.mu.client, oe.mu.target, oe.mu.grpcCustomCA = nil, nil, "", ""
+	}
+}
+
+// otlpTLSConfig returns the TLS configuration used to connect to a
+// collector, which trusts the given PEM-encoded root CA, if any, in addition
+// to the system's root CAs.
+func otlpTLSConfig(customCA string) (*tls.Config, error) {
+	if customCA == "" {
+		return &tls.Config{}, nil
+	}
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load system root CA pool")
+	}
+	if !roots.AppendCertsFromPEM([]byte(customCA)) {
+		return nil, errors.Errorf("failed to parse root CA certificate from %q", customCA)
+	}
+	return &tls.Config{RootCAs: roots}, nil
+}
+
+func (oe *OTLPExporter) pushGRPC(
+	ctx context.Context,
+	endpoint string,
+	customCA string,
+	req *otel_metrics_collector_pb.ExportMetricsServiceRequest,
+) error {
+	client, err := func() (otel_metrics_collector_pb.MetricsServiceClient, error) {
+		oe.mu.Lock()
+		defer oe.mu.Unlock()
+		if oe.mu.conn != nil && oe.mu.target == endpoint && oe.mu.grpcCustomCA == customCA {
+			return oe.mu.client, nil
+		}
+		oe.closeLocked()
+		// The endpoint is host:port, optionally prefixed by a scheme which
+		// determines whether TLS is used.
+		var target string
+		var creds credentials.TransportCredentials
+		if t, ok := strings.CutPrefix(endpoint, "http://"); ok {
+			target = t
+			creds = insecure.NewCredentials()
+		} else {
+			target = strings.TrimPrefix(endpoint, "https://")
+			tlsConf, err := otlpTLSConfig(customCA)
+			if err != nil {
+				return nil, err
+			}
+			creds = credentials.NewTLS(tlsConf)
+		}
+		// Note that Dial is non-blocking.
+		conn, err := grpc.Dial(target, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return nil, err
+		}
+		oe.mu.target = endpoint
+		oe.mu.grpcCustomCA = customCA
+		oe.mu.conn = conn
+		oe.mu.client = otel_metrics_collector_pb.NewMetricsServiceClient(conn)
+		return oe.mu.client, nil
+	}()
+	if err != nil {
+		return err
+	}
+	_, err = client.Export(ctx, req)
+	return err
+}
+
+// httpClientForCA returns the client used to push metrics over OTLP/HTTP
+// with the given custom root CA.
+func (oe *OTLPExporter) httpClientForCA(customCA string) (*http.Client, error) {
+	oe.mu.Lock()
+	defer oe.mu.Unlock()
+	if oe.mu.httpClient != nil && oe.mu.httpCustomCA == customCA {
+		return oe.mu.httpClient, nil
+	}
+	tlsConf, err := otlpTLSConfig(customCA)
+	if err != nil {
+		return nil, err
+	}
+	if oe.mu.httpClient != nil {
+		oe.mu.httpClient.CloseIdleConnections()
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = tlsConf
+	oe.mu.httpClient = &http.Client{Transport: t}
+	oe.mu.httpCustomCA = customCA
+	return oe.mu.httpClient, nil
+}
+
+func (oe *OTLPExporter) pushHTTP(
+	ctx context.Context,
+	endpoint string,
+	customCA string,
+	req *otel_metrics_collector_pb.ExportMetricsServiceRequest,
+) error {
+	// Endpoints without a scheme use TLS.
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = otlpMetricsPath
+	}
+	body, err := req.Marshal()
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set(httputil.ContentTypeHeader, httputil.ProtoContentType)
+	client, err := oe.httpClientForCA(customCA)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close() // don't care about content
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Newf("received %s response from %s", resp.Status, u)
+	}
+	return nil
+}
+
+// otlpMetric converts a Prometheus metric family into an OTLP metric, or
+// returns nil if the type of the family is not supported.
+func otlpMetric(
+	family *prometheusgo.MetricFamily, start, now uint64,
+) *otel_metrics_pb.Metric {
+	m := &otel_metrics_pb.Metric{
+		Name:        family.GetName(),
+		Description: family.GetHelp(),
+	}
+	switch family.GetType() {
+	case prometheusgo.MetricType_COUNTER:
+		sum := &otel_metrics_pb.Sum{
+			AggregationTemporality: otel_metrics_pb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+			IsMonotonic:            true,
+		}
+		for _, pm := range family.Metric {
+			sum.DataPoints = append(sum.DataPoints,
+				otlpNumberDataPoint(pm, start, now, pm.GetCounter().GetValue()))
+		}
+		m.Data = &otel_metrics_pb.Metric_Sum{Sum: sum}
+	case prometheusgo.MetricType_GAUGE, prometheusgo.MetricType_UNTYPED:
+		gauge := &otel_metrics_pb.Gauge{}
+		for _, pm := range family.Metric {
+			v := pm.GetGauge().GetValue()
+			if pm.Untyped != nil {
+				v = pm.GetUntyped().GetValue()
+			}
+			// The start time of gauges is ignored.
+			gauge.DataPoints = append(gauge.DataPoints, otlpNumberDataPoint(pm, 0, now, v))
+		}
+		m.Data = &otel_metrics_pb.Metric_Gauge{Gauge: gauge}
+	case prometheusgo.MetricType_HISTOGRAM:
+		hist := &otel_metrics_pb.Histogram{
+			AggregationTemporality: otel_metrics_pb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+		}
+		for _, pm := range family.Metric {
+			hist.DataPoints = append(hist.DataPoints, otlpHistogramDataPoint(pm, start, now))
+		}
+		m.Data = &otel_metrics_pb.Metric_Histogram{Histogram: hist}
+	case prometheusgo.MetricType_SUMMARY:
+		summary := &otel_metrics_pb.Summary{}
+		for _, pm := range family.Metric {
+			dp := &otel_metrics_pb.SummaryDataPoint{
+				Attributes:        otlpAttributes(pm.Label),
+				StartTimeUnixNano: start,
+				TimeUnixNano:      now,
+				Count:             pm.GetSummary().GetSampleCount(),
+				Sum:               pm.GetSummary().GetSampleSum(),
+			}
+			for _, q := range pm.GetSummary().GetQuantile() {
+				dp.QuantileValues = append(dp.QuantileValues, &otel_metrics_pb.SummaryDataPoint_ValueAtQuantile{
+					Quantile: q.GetQuantile(),
+					Value:    q.GetValue(),
+				})
+			}
+			summary.DataPoints = append(summary.DataPoints, dp)
+		}
+		m.Data = &otel_metrics_pb.Metric_Summary{Summary: summary}
+	default:
+		return nil
+	}
+	return m
+}
+
+func otlpNumberDataPoint(
+	pm *prometheusgo.Metric, start, now uint64, v float64,
+) *otel_metrics_pb.NumberDataPoint {
+	return &otel_metrics_pb.NumberDataPoint{
+		Attributes:        otlpAttributes(pm.Label),
+		StartTimeUnixNano: start,
+		TimeUnixNano:      now,
+		Value:             &otel_metrics_pb.NumberDataPoint_AsDouble{AsDouble: v},
+	}
+}
+
+// otlpHistogramDataPoint converts a Prometheus histogram into an OTLP
+// histogram data point. Prometheus buckets hold cumulative counts, while
+// OTLP buckets hold the count of their own interval, with an additional
+// bucket for the values above the largest bound.
+func otlpHistogramDataPoint(
+	pm *prometheusgo.Metric, start, now uint64,
+) *otel_metrics_pb.HistogramDataPoint {
+	h := pm.GetHistogram()
+	dp := &otel_metrics_pb.HistogramDataPoint{
+		Attributes:        otlpAttributes(pm.Label),
+		StartTimeUnixNano: start,
+		TimeUnixNano:      now,
+		Count:             h.GetSampleCount(),
+		Sum:               h.GetSampleSum(),
+	}
+	var prev uint64
+	for _, b := range h.GetBucket() {
+		if math.IsInf(b.GetUpperBound(), +1) {
+			// The +Inf bucket is implied by the overflow bucket below.
+			break
+		}
+		cum := b.GetCumulativeCount()
+		if cum < prev {
+			cum = prev
+		}
+		dp.ExplicitBounds = append(dp.ExplicitBounds, b.GetUpperBound())
+		dp.BucketCounts = append(dp.BucketCounts, cum-prev)
+		prev = cum
+	}
+	// The sample count includes the values above the largest bound, for which
+	// there is no bucket in the Prometheus histogram.
+	var overflow uint64
+	if dp.Count > prev {
+		overflow = dp.Count - prev
+	}
+	dp.BucketCounts = append(dp.BucketCounts, overflow)
+	return dp
+}
+
+func otlpAttributes(labels []*prometheusgo.LabelPair) []*otel_pb.KeyValue {
+	if len(labels) == 0 {
+		return nil
+	}
+	attrs := make([]*otel_pb.KeyValue, len(labels))
+	for i, l := range labels {
+		attrs[i] = otlpStringAttribute(l.GetName(), l.GetValue())
+	}
+	return attrs
+}
+
+func otlpStringAttribute(k, v string) *otel_pb.KeyValue {
+	return &otel_pb.KeyValue{
+		Key:   k,
+		Value: &otel_pb.AnyValue{Value: &otel_pb.AnyValue_StringValue{StringValue: v}},
+	}
+}
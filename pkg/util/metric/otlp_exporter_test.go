@@ -0,0 +1,225 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package metric
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	otel_metrics_collector_pb "github.com/cockroachdb/cockroach/pkg/obsservice/obspb/opentelemetry-proto/collector/metrics/v1"
+	otel_metrics_pb "github.com/cockroachdb/cockroach/pkg/obsservice/obspb/opentelemetry-proto/metrics/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func makeOTLPTestExporter() *OTLPExporter {
+	r := NewRegistry()
+	r.AddLabel("node_id", "1")
+
+	cMeta := Metadata{Name: "test.counter", Help: "a counter"}
+	cMeta.AddLabel("kind", "one")
+	c := NewCounter(cMeta)
+	c.Inc(3)
+	r.AddMetric(c)
+
+	g := NewGauge(Metadata{Name: "test.gauge"})
+	g.Update(5)
+	r.AddMetric(g)
+
+	h := NewHistogram(HistogramOptions{
+		Mode:     HistogramModePrometheus,
+		Metadata: Metadata{Name: "test.histogram"},
+		Duration: time.Hour,
+		Buckets:  []float64{1, 5, 10},
+	})
+	for _, v := range []int64{1, 3, 7, 20} {
+		h.RecordValue(v)
+	}
+	r.AddMetric(h)
+
+	pm := MakePrometheusExporter()
+	pm.ScrapeRegistry(r, true /* includeChildMetrics */)
+	return NewOTLPExporter(&pm, map[string]string{"service.name": "cockroachdb"})
+}
+
+func TestOTLPExporterCollect(t *testing.T) {
+	oe := makeOTLPTestExporter()
+	req := oe.Collect()
+
+	require.Len(t, req.ResourceMetrics, 1)
+	rm := req.ResourceMetrics[0]
+	require.Equal(t, "service.name", rm.Resource.Attributes[0].Key)
+	require.Len(t, rm.ScopeMetrics, 1)
+	metrics := rm.ScopeMetrics[0].Metrics
+	require.Len(t, metrics, 3)
+
+	// Metrics are sorted by name.
+	counter := metrics[0]
+	require.Equal(t, "test_counter", counter.Name)
+	require.Equal(t, "a counter", counter.Description)
+	sum := counter.GetSum()
+	require.NotNil(t, sum)
+	require.True(t, sum.IsMonotonic)
+	require.Equal(t, otel_metrics_pb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, sum.AggregationTemporality)
+	require.Len(t, sum.DataPoints, 1)
+	require.Equal(t, 3.0, sum.DataPoints[0].GetAsDouble())
+	var attrs []string
+	for _, kv := range sum.DataPoints[0].Attributes {
+		attrs = append(attrs, kv.Key+"="+kv.Value.GetStringValue())
+	}
+	require.Equal(t, []string{"node_id=1", "kind=one"}, attrs)
+	require.NotZero(t, sum.DataPoints[0].StartTimeUnixNano)
+	require.GreaterOrEqual(t, sum.DataPoints[0].TimeUnixNano, sum.DataPoints[0].StartTimeUnixNano)
+
+	gauge := metrics[1]
+	require.Equal(t, "test_gauge", gauge.Name)
+	require.NotNil(t, gauge.GetGauge())
+	require.Equal(t, 5.0, gauge.GetGauge().DataPoints[0].GetAsDouble())
+
+	hist := metrics[2]
+	require.Equal(t, "test_histogram", hist.Name)
+	require.NotNil(t, hist.GetHistogram())
+	dp := hist.GetHistogram().DataPoints[0]
+	require.Equal(t, uint64(4), dp.Count)
+	require.Equal(t, 31.0, dp.Sum)
+	require.Equal(t, []float64{1, 5, 10}, dp.ExplicitBounds)
+	// The last bucket holds the values above the largest bound.
+	require.Equal(t, []uint64{1, 1, 1, 1}, dp.BucketCounts)
+
+	// The scraped metrics are cleared by Collect.
+	require.Empty(t, oe.Collect().ResourceMetrics[0].ScopeMetrics[0].Metrics)
+}
+
+// testOTLPReceiver is an in-process OTLP/gRPC metrics receiver.
+type testOTLPReceiver struct {
+	requests chan *otel_metrics_collector_pb.ExportMetricsServiceRequest
+}
+
+var _ otel_metrics_collector_pb.MetricsServiceServer = (*testOTLPReceiver)(nil)
+
+// Export implements the MetricsServiceServer interface.
+func (r *testOTLPReceiver) Export(
+	_ context.Context, req *otel_metrics_collector_pb.ExportMetricsServiceRequest,
+) (*otel_metrics_collector_pb.ExportMetricsServiceResponse, error) {
+	r.requests <- req
+	return &otel_metrics_collector_pb.ExportMetricsServiceResponse{}, nil
+}
+
+func TestOTLPExporterPush(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("grpc", func(t *testing.T) {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		receiver := &testOTLPReceiver{
+			requests: make(chan *otel_metrics_collector_pb.ExportMetricsServiceRequest, 1),
+		}
+		s := grpc.NewServer()
+		otel_metrics_collector_pb.RegisterMetricsServiceServer(s, receiver)
+		go func() { _ = s.Serve(lis) }()
+		defer s.Stop()
+
+		oe := makeOTLPTestExporter()
+		defer oe.Close()
+		req := oe.Collect()
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		require.NoError(t, oe.Push(ctx, OTLPProtocolGRPC, "http://"+lis.Addr().String(), "" /* customCA */, req))
+		received := <-receiver.requests
+		require.Len(t, received.ResourceMetrics[0].ScopeMetrics[0].Metrics, 3)
+	})
+
+	t.Run("http", func(t *testing.T) {
+		// The receiver fails the first request, which is retried.
+		var attempts atomic.Int32
+		requests := make(chan *otel_metrics_collector_pb.ExportMetricsServiceRequest, 1)
+		s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) == 1 {
+				rw.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			if r.URL.Path != "/v1/metrics" {
+				t.Errorf("unexpected path: %s", r.URL.Path)
+			}
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			var req otel_metrics_collector_pb.ExportMetricsServiceRequest
+			if err := req.Unmarshal(body); err != nil {
+				t.Error(err)
+				return
+			}
+			requests <- &req
+		}))
+		defer s.Close()
+
+		oe := makeOTLPTestExporter()
+		req := oe.Collect()
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		require.NoError(t, oe.Push(ctx, OTLPProtocolHTTP, s.URL, "" /* customCA */, req))
+		require.Equal(t, int32(2), attempts.Load())
+		received := <-requests
+		require.Len(t, received.ResourceMetrics[0].ScopeMetrics[0].Metrics, 3)
+	})
+
+	t.Run("https", func(t *testing.T) {
+		requests := make(chan struct{}, 1)
+		s := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			requests <- struct{}{}
+		}))
+		defer s.Close()
+		customCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw}))
+
+		oe := makeOTLPTestExporter()
+		defer oe.Close()
+		// The certificate of the collector is not trusted without the custom CA.
+		shortCtx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+		defer cancel()
+		err := oe.Push(shortCtx, OTLPProtocolHTTP, s.URL, "" /* customCA */, oe.Collect())
+		require.ErrorContains(t, err, "certificate")
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		require.NoError(t, oe.Push(ctx, OTLPProtocolHTTP, s.URL, customCA, oe.Collect()))
+		<-requests
+
+		err = oe.Push(ctx, OTLPProtocolHTTP, s.URL, "not a certificate", oe.Collect())
+		require.ErrorContains(t, err, "failed to parse root CA certificate")
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+		s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer s.Close()
+
+		oe := makeOTLPTestExporter()
+		ctx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+		defer cancel()
+		err := oe.Push(ctx, OTLPProtocolHTTP, s.URL, "" /* customCA */, oe.Collect())
+		require.ErrorContains(t, err, "503")
+	})
+
+	t.Run("no endpoint", func(t *testing.T) {
+		oe := makeOTLPTestExporter()
+		require.Equal(t, errNoOTLPEndpoint, oe.Push(ctx, OTLPProtocolHTTP, "", "" /* customCA */, oe.Collect()))
+	})
+}